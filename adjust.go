@@ -13,7 +13,6 @@ package excelize_ch
 
 import (
 	"bytes"
-	"encoding/xml"
 	"io"
 	"strconv"
 	"strings"
@@ -76,6 +75,13 @@ func (f *File) adjustHelper(sheet string, dir adjustDirection, num, offset int)
 		return err
 	}
 	sheetID := f.getSheetID(sheet)
+	// Mark the whole worksheet as changed for the partial recalculation
+	f.changedCells.Store(cellRef{Sheet: sheet}, struct{}{})
+	if dir == rows {
+		f.addWatchEvents(sheet, []int{MinColumns, num, MaxColumns, TotalRows})
+	} else {
+		f.addWatchEvents(sheet, []int{num, 1, MaxColumns, TotalRows})
+	}
 	if dir == rows {
 		err = f.adjustRowDimensions(sheet, ws, num, offset)
 	} else {
@@ -86,7 +92,7 @@ func (f *File) adjustHelper(sheet string, dir adjustDirection, num, offset int)
 	}
 	f.adjustHyperlinks(ws, sheet, dir, num, offset)
 	ws.checkSheet()
-	_ = ws.checkRow()
+	_, _ = ws.checkRow()
 	for _, fn := range adjustHelperFunc {
 		if err := fn(f, ws, sheet, dir, num, offset, sheetID); err != nil {
 			return err
@@ -236,8 +242,59 @@ func (f *File) adjustSingleRowFormulas(sheet, sheetN string, r *xlsxRow, num, of
 	return nil
 }
 
-// adjustCellRef provides a function to adjust cell reference.
-func (f *File) adjustCellRef(ref string, dir adjustDirection, num, offset int) (string, bool, error) {
+// adjustNum provides a function to adjust the row or column number by given
+// number, the base row or column number and offset. Deleting multiple rows or
+// columns is equivalent to deleting a single row or column at the base
+// number repeatedly, so the numbers in the deleted block will be adjusted to
+// the previous row or column number of the block.
+func adjustNum(n, num, offset int) int {
+	if n < num {
+		return n
+	}
+	if n += offset; n < num {
+		return num - 1
+	}
+	return n
+}
+
+// isDeletedNum provides a function to check if the row or column number is
+// in the deleted block by given number, the base row or column number and
+// offset.
+func isDeletedNum(n, num, offset int) bool {
+	return offset < 0 && n >= num && n < num-offset
+}
+
+// adjustRefNum provides a function to adjust the row or column number of the
+// reference by given number, the base row or column number and offset. The
+// number will be moved by the offset without deleting the rows or columns if
+// move is true, which is used for duplicating rows.
+func adjustRefNum(n, num, offset int, move bool) int {
+	if move && n >= num {
+		return n + offset
+	}
+	return adjustNum(n, num, offset)
+}
+
+// adjustAnchorNum provides a function to adjust the zero-based row or column
+// number of the drawing object anchor by given number, the base row or column
+// number and offset.
+func adjustAnchorNum(n, num, offset int) int {
+	if offset > 0 {
+		return n + offset
+	}
+	if n += offset; n < num-2 {
+		n = num - 2
+	}
+	if n < 0 {
+		return 0
+	}
+	return n
+}
+
+// adjustCellRef provides a function to adjust cell reference. The reference
+// will be moved by the offset without deleting the rows or columns if move is
+// true.
+func (f *File) adjustCellRef(ref string, move bool, dir adjustDirection, num, offset int) (string, bool, error) {
 	if !strings.Contains(ref, ":") {
 		ref += ":" + ref
 	}
@@ -250,22 +307,12 @@ func (f *File) adjustCellRef(ref string, dir adjustDirection, num, offset int) (
 		if offset < 0 && coordinates[0] == coordinates[2] {
 			delete = true
 		}
-		if coordinates[0] >= num {
-			coordinates[0] += offset
-		}
-		if coordinates[2] >= num {
-			coordinates[2] += offset
-		}
+		coordinates[0], coordinates[2] = adjustRefNum(coordinates[0], num, offset, move), adjustRefNum(coordinates[2], num, offset, move)
 	} else {
 		if offset < 0 && coordinates[1] == coordinates[3] {
 			delete = true
 		}
-		if coordinates[1] >= num {
-			coordinates[1] += offset
-		}
-		if coordinates[3] >= num {
-			coordinates[3] += offset
-		}
+		coordinates[1], coordinates[3] = adjustRefNum(coordinates[1], num, offset, move), adjustRefNum(coordinates[3], num, offset, move)
 	}
 	ref, err = f.coordinatesToRangeRef(coordinates)
 	return ref, delete, err
@@ -279,7 +326,7 @@ func (f *File) adjustFormula(sheet, sheetN string, formula *xlsxF, dir adjustDir
 	}
 	var err error
 	if formula.Ref != "" && sheet == sheetN {
-		if formula.Ref, _, err = f.adjustCellRef(formula.Ref, dir, num, offset); err != nil {
+		if formula.Ref, _, err = f.adjustCellRef(formula.Ref, si, dir, num, offset); err != nil {
 			return err
 		}
 		if si && formula.Si != nil {
@@ -287,7 +334,7 @@ func (f *File) adjustFormula(sheet, sheetN string, formula *xlsxF, dir adjustDir
 		}
 	}
 	if formula.Content != "" {
-		if formula.Content, err = f.adjustFormulaRef(sheet, sheetN, formula.Content, false, dir, num, offset); err != nil {
+		if formula.Content, err = f.adjustFormulaRef(sheet, sheetN, formula.Content, false, si, dir, num, offset); err != nil {
 			return err
 		}
 	}
@@ -316,7 +363,7 @@ func escapeSheetName(name string) string {
 }
 
 // adjustFormulaColumnName adjust column name in the formula reference.
-func adjustFormulaColumnName(name, operand string, abs, keepRelative bool, dir adjustDirection, num, offset int) (string, string, bool, error) {
+func adjustFormulaColumnName(name, operand string, abs, keepRelative, move bool, dir adjustDirection, num, offset int) (string, string, bool, error) {
 	if name == "" || (!abs && keepRelative) {
 		return "", operand + name, abs, nil
 	}
@@ -325,7 +372,7 @@ func adjustFormulaColumnName(name, operand string, abs, keepRelative bool, dir a
 		return "", operand, false, err
 	}
 	if dir == columns && col >= num {
-		col += offset
+		col = adjustRefNum(col, num, offset, move)
 		colName, err := ColumnNumberToName(col)
 		return "", operand + colName, false, err
 	}
@@ -333,14 +380,13 @@ func adjustFormulaColumnName(name, operand string, abs, keepRelative bool, dir a
 }
 
 // adjustFormulaRowNumber adjust row number in the formula reference.
-func adjustFormulaRowNumber(name, operand string, abs, keepRelative bool, dir adjustDirection, num, offset int) (string, string, bool, error) {
+func adjustFormulaRowNumber(name, operand string, abs, keepRelative, move bool, dir adjustDirection, num, offset int) (string, string, bool, error) {
 	if name == "" || (!abs && keepRelative) {
 		return "", operand + name, abs, nil
 	}
 	row, _ := strconv.Atoi(name)
 	if dir == rows && row >= num {
-		row += offset
-		if row <= 0 || row > TotalRows {
+		if row = adjustRefNum(row, num, offset, move); row <= 0 || row > TotalRows {
 			return "", operand + name, false, ErrMaxRows
 		}
 		return "", operand + strconv.Itoa(row), false, nil
@@ -349,18 +395,18 @@ func adjustFormulaRowNumber(name, operand string, abs, keepRelative bool, dir ad
 }
 
 // adjustFormulaOperandRef adjust cell reference in the operand tokens for the formula.
-func adjustFormulaOperandRef(row, col, operand string, abs, keepRelative bool, dir adjustDirection, num int, offset int) (string, string, string, bool, error) {
+func adjustFormulaOperandRef(row, col, operand string, abs, keepRelative, move bool, dir adjustDirection, num int, offset int) (string, string, string, bool, error) {
 	var err error
-	col, operand, abs, err = adjustFormulaColumnName(col, operand, abs, keepRelative, dir, num, offset)
+	col, operand, abs, err = adjustFormulaColumnName(col, operand, abs, keepRelative, move, dir, num, offset)
 	if err != nil {
 		return row, col, operand, abs, err
 	}
-	row, operand, abs, err = adjustFormulaRowNumber(row, operand, abs, keepRelative, dir, num, offset)
+	row, operand, abs, err = adjustFormulaRowNumber(row, operand, abs, keepRelative, move, dir, num, offset)
 	return row, col, operand, abs, err
 }
 
 // adjustFormulaOperand adjust range operand tokens for the formula.
-func (f *File) adjustFormulaOperand(sheet, sheetN string, keepRelative bool, token efp.Token, dir adjustDirection, num int, offset int) (string, error) {
+func (f *File) adjustFormulaOperand(sheet, sheetN string, keepRelative, move bool, token efp.Token, dir adjustDirection, num int, offset int) (string, error) {
 	var (
 		err                          error
 		abs                          bool
@@ -380,7 +426,7 @@ func (f *File) adjustFormulaOperand(sheet, sheetN string, keepRelative bool, tok
 	}
 	for _, r := range cell {
 		if r == '$' {
-			if col, operand, _, err = adjustFormulaColumnName(col, operand, abs, keepRelative, dir, num, offset); err != nil {
+			if col, operand, _, err = adjustFormulaColumnName(col, operand, abs, keepRelative, move, dir, num, offset); err != nil {
 				return operand, err
 			}
 			abs = true
@@ -393,24 +439,25 @@ func (f *File) adjustFormulaOperand(sheet, sheetN string, keepRelative bool, tok
 		}
 		if '0' <= r && r <= '9' {
 			row += string(r)
-			col, operand, abs, err = adjustFormulaColumnName(col, operand, abs, keepRelative, dir, num, offset)
+			col, operand, abs, err = adjustFormulaColumnName(col, operand, abs, keepRelative, move, dir, num, offset)
 			if err != nil {
 				return operand, err
 			}
 			continue
 		}
-		if row, col, operand, abs, err = adjustFormulaOperandRef(row, col, operand, abs, keepRelative, dir, num, offset); err != nil {
+		if row, col, operand, abs, err = adjustFormulaOperandRef(row, col, operand, abs, keepRelative, move, dir, num, offset); err != nil {
 			return operand, err
 		}
 		operand += string(r)
 	}
-	_, _, operand, _, err = adjustFormulaOperandRef(row, col, operand, abs, keepRelative, dir, num, offset)
+	_, _, operand, _, err = adjustFormulaOperandRef(row, col, operand, abs, keepRelative, move, dir, num, offset)
 	return operand, err
 }
 
 // adjustFormulaRef returns adjusted formula by giving adjusting direction and
-// the base number of column or row, and offset.
-func (f *File) adjustFormulaRef(sheet, sheetN, formula string, keepRelative bool, dir adjustDirection, num, offset int) (string, error) {
+// the base number of column or row, and offset. The references will be moved
+// by the offset without deleting the rows or columns if move is true.
+func (f *File) adjustFormulaRef(sheet, sheetN, formula string, keepRelative, move bool, dir adjustDirection, num, offset int) (string, error) {
 	var (
 		val          string
 		definedNames []string
@@ -435,7 +482,7 @@ func (f *File) adjustFormulaRef(sheet, sheetN, formula string, keepRelative bool
 				val += token.TValue
 				continue
 			}
-			operand, err := f.adjustFormulaOperand(sheet, sheetN, keepRelative, token, dir, num, offset)
+			operand, err := f.adjustFormulaOperand(sheet, sheetN, keepRelative, move, token, dir, num, offset)
 			if err != nil {
 				return val, err
 			}
@@ -459,6 +506,150 @@ func (f *File) adjustFormulaRef(sheet, sheetN, formula string, keepRelative bool
 	return val, nil
 }
 
+// adjustFormulaSheetRefs provides a function to update the sheet names in the
+// references of the formula by given function, which returns the new sheet
+// name, or returns false to mark the reference to the sheet as removed, and
+// the reference will be replaced with the #REF! error. The formula will be
+// returned as-is if it can't be parsed. Returns the adjusted formula and
+// whether any reference was removed.
+func adjustFormulaSheetRefs(formula string, fn func(sheet string) (string, bool)) (string, bool) {
+	var (
+		val     string
+		removed bool
+		ps      = efp.ExcelParser()
+	)
+	for _, token := range ps.Parse(formula) {
+		if token.TType == efp.TokenTypeUnknown {
+			return formula, false
+		}
+		if token.TType == efp.TokenTypeOperand && token.TSubType == efp.TokenSubTypeRange {
+			idx := strings.LastIndex(token.TValue, "!")
+			if idx == -1 || strings.ContainsAny(token.TValue, "[]") {
+				val += token.TValue
+				continue
+			}
+			var sheets []string
+			for _, sheet := range strings.Split(token.TValue[:idx], ":") {
+				name, ok := fn(sheet)
+				if !ok {
+					sheets = nil
+					break
+				}
+				sheets = append(sheets, name)
+			}
+			if sheets == nil {
+				val, removed = val+formulaErrorREF, true
+				continue
+			}
+			ref := strings.Join(sheets, ":")
+			for _, name := range sheets {
+				if escapeSheetName(name) != name {
+					ref = "'" + strings.ReplaceAll(ref, "'", "''") + "'"
+					break
+				}
+			}
+			val += ref + token.TValue[idx:]
+			continue
+		}
+		if isFunctionStart(token) {
+			val += token.TValue + string(efp.ParenOpen)
+			continue
+		}
+		if isFunctionStop(token) {
+			val += token.TValue + string(efp.ParenClose)
+			continue
+		}
+		if token.TType == efp.TokenTypeOperand && token.TSubType == efp.TokenSubTypeText {
+			val += string(efp.QuoteDouble) + strings.ReplaceAll(token.TValue, "\"", "\"\"") + string(efp.QuoteDouble)
+			continue
+		}
+		val += token.TValue
+	}
+	return val, removed
+}
+
+// moveFormulaRows provides a function to update the row numbers of the
+// references to the given worksheet in the formula by given function, which
+// returns the new row number by the original row number. The sheetN
+// specifies the worksheet name of the formula located, and it can be empty
+// for the formulas which not located in a worksheet, such as defined names.
+// The formula will be returned as-is if it can't be parsed or doesn't refer
+// to the worksheet. Returns the adjusted formula and whether the formula
+// refers to the worksheet.
+func moveFormulaRows(sheet, sheetN, formula string, fn func(row int) int) (string, bool) {
+	var (
+		val   string
+		found bool
+		ps    = efp.ExcelParser()
+	)
+	for _, token := range ps.Parse(formula) {
+		if token.TType == efp.TokenTypeUnknown {
+			return formula, false
+		}
+		if token.TType == efp.TokenTypeOperand && token.TSubType == efp.TokenSubTypeRange {
+			operand, ok := moveOperandRows(sheet, sheetN, token.TValue, fn)
+			val, found = val+operand, found || ok
+			continue
+		}
+		if isFunctionStart(token) {
+			val += token.TValue + string(efp.ParenOpen)
+			continue
+		}
+		if isFunctionStop(token) {
+			val += token.TValue + string(efp.ParenClose)
+			continue
+		}
+		if token.TType == efp.TokenTypeOperand && token.TSubType == efp.TokenSubTypeText {
+			val += string(efp.QuoteDouble) + strings.ReplaceAll(token.TValue, "\"", "\"\"") + string(efp.QuoteDouble)
+			continue
+		}
+		val += token.TValue
+	}
+	if !found {
+		return formula, found
+	}
+	return val, found
+}
+
+// moveOperandRows provides a function to update the row numbers of the range
+// operand which refers to the given worksheet by given function. The range
+// will be kept in order if the rows of the boundaries be reversed.
+func moveOperandRows(sheet, sheetN, operand string, fn func(row int) int) (string, bool) {
+	if strings.ContainsAny(operand, "[]") {
+		return operand, false
+	}
+	var prefix string
+	sheetName, ref := sheetN, operand
+	if idx := strings.LastIndex(operand, "!"); idx != -1 {
+		sheetName, ref = operand[:idx], operand[idx+1:]
+		prefix = escapeSheetName(sheetName) + "!"
+	}
+	if sheetName != sheet {
+		return prefix + ref, false
+	}
+	parts, nums := strings.Split(ref, ":"), []int{}
+	for _, part := range parts {
+		col := strings.TrimRight(part, "0123456789")
+		row, err := strconv.Atoi(part[len(col):])
+		if err != nil {
+			return operand, false
+		}
+		if name := strings.Trim(col, "$"); name != "" {
+			if _, err = ColumnNameToNumber(name); err != nil {
+				return operand, false
+			}
+		}
+		nums = append(nums, fn(row))
+	}
+	if len(nums) == 2 && nums[0] > nums[1] {
+		nums[0], nums[1] = nums[1], nums[0]
+	}
+	for i, part := range parts {
+		parts[i] = strings.TrimRight(part, "0123456789") + strconv.Itoa(nums[i])
+	}
+	return prefix + strings.Join(parts, ":"), true
+}
+
 // adjustHyperlinks provides a function to update hyperlinks when inserting or
 // deleting rows or columns.
 func (f *File) adjustHyperlinks(ws *xlsxWorksheet, sheet string, dir adjustDirection, num, offset int) {
@@ -473,7 +664,7 @@ func (f *File) adjustHyperlinks(ws *xlsxWorksheet, sheet string, dir adjustDirec
 			linkData := ws.Hyperlinks.Hyperlink[i]
 			colNum, rowNum, _ := CellNameToCoordinates(linkData.Ref)
 
-			if (dir == rows && num == rowNum) || (dir == columns && num == colNum) {
+			if (dir == rows && isDeletedNum(rowNum, num, offset)) || (dir == columns && isDeletedNum(colNum, num, offset)) {
 				f.deleteSheetRelationships(sheet, linkData.RID)
 				if len(ws.Hyperlinks.Hyperlink) > 1 {
 					ws.Hyperlinks.Hyperlink = append(ws.Hyperlinks.Hyperlink[:i],
@@ -489,7 +680,7 @@ func (f *File) adjustHyperlinks(ws *xlsxWorksheet, sheet string, dir adjustDirec
 	}
 	for i := range ws.Hyperlinks.Hyperlink {
 		link := &ws.Hyperlinks.Hyperlink[i] // get reference
-		link.Ref, _ = f.adjustFormulaRef(sheet, sheet, link.Ref, false, dir, num, offset)
+		link.Ref, _ = f.adjustFormulaRef(sheet, sheet, link.Ref, false, false, dir, num, offset)
 	}
 }
 
@@ -517,7 +708,7 @@ func (f *File) adjustTable(ws *xlsxWorksheet, sheet string, dir adjustDirection,
 			return err
 		}
 		// Remove the table when deleting the header row of the table
-		if dir == rows && num == coordinates[0] && offset == -1 {
+		if dir == rows && num == coordinates[0] && offset < 0 {
 			ws.TableParts.TableParts = append(ws.TableParts.TableParts[:idx], ws.TableParts.TableParts[idx+1:]...)
 			ws.TableParts.Count = len(ws.TableParts.TableParts)
 			idx--
@@ -538,7 +729,7 @@ func (f *File) adjustTable(ws *xlsxWorksheet, sheet string, dir adjustDirection,
 		_ = f.setTableColumns(sheet, true, x1, y1, x2, &t)
 		// Currently doesn't support query table
 		t.TableType, t.TotalsRowCount, t.ConnectionID = "", 0, 0
-		table, _ := xml.Marshal(t)
+		table, _ := marshalTable(&t)
 		f.saveFileList(tableXML, table)
 	}
 	return nil
@@ -557,7 +748,7 @@ func (f *File) adjustAutoFilter(ws *xlsxWorksheet, sheet string, dir adjustDirec
 	}
 	x1, y1, x2, y2 := coordinates[0], coordinates[1], coordinates[2], coordinates[3]
 
-	if (dir == rows && y1 == num && offset < 0) || (dir == columns && x1 == num && x2 == num) {
+	if (dir == rows && isDeletedNum(y1, num, offset)) || (dir == columns && x1 == num && x2 == num) {
 		ws.AutoFilter = nil
 		for rowIdx := range ws.SheetData.Row {
 			rowData := &ws.SheetData.Row[rowIdx]
@@ -580,20 +771,10 @@ func (f *File) adjustAutoFilter(ws *xlsxWorksheet, sheet string, dir adjustDirec
 // operation reference and offset.
 func (f *File) adjustAutoFilterHelper(dir adjustDirection, coordinates []int, num, offset int) []int {
 	if dir == rows {
-		if coordinates[1] >= num {
-			coordinates[1] += offset
-		}
-		if coordinates[3] >= num {
-			coordinates[3] += offset
-		}
+		coordinates[1], coordinates[3] = adjustNum(coordinates[1], num, offset), adjustNum(coordinates[3], num, offset)
 		return coordinates
 	}
-	if coordinates[0] >= num {
-		coordinates[0] += offset
-	}
-	if coordinates[2] >= num {
-		coordinates[2] += offset
-	}
+	coordinates[0], coordinates[2] = adjustNum(coordinates[0], num, offset), adjustNum(coordinates[2], num, offset)
 	return coordinates
 }
 
@@ -616,15 +797,17 @@ func (f *File) adjustMergeCells(ws *xlsxWorksheet, sheet string, dir adjustDirec
 		}
 		x1, y1, x2, y2 := coordinates[0], coordinates[1], coordinates[2], coordinates[3]
 		if dir == rows {
-			if y1 == num && y2 == num && offset < 0 {
+			if isDeletedNum(y1, num, offset) && isDeletedNum(y2, num, offset) {
 				f.deleteMergeCell(ws, i)
+				i--
 				continue
 			}
 
 			y1, y2 = f.adjustMergeCellsHelper(y1, y2, num, offset)
 		} else {
-			if x1 == num && x2 == num && offset < 0 {
+			if isDeletedNum(x1, num, offset) && isDeletedNum(x2, num, offset) {
 				f.deleteMergeCell(ws, i)
+				i--
 				continue
 			}
 
@@ -660,13 +843,12 @@ func (f *File) adjustMergeCellsHelper(p1, p2, num, offset int) (int, int) {
 		}
 		return p1, p2
 	}
-	if num < p1 || (num == p1 && num == p2) {
-		p1 += offset
-		p2 += offset
-	} else if num <= p2 {
-		p2 += offset
+	if num < p1 {
+		if p1 += offset; p1 < num {
+			p1 = num
+		}
 	}
-	return p1, p2
+	return p1, adjustNum(p2, num, offset)
 }
 
 // deleteMergeCell provides a function to delete merged cell by given index.
@@ -714,7 +896,7 @@ func (f *File) adjustCalcChain(ws *xlsxWorksheet, sheet string, dir adjustDirect
 			return err
 		}
 		if dir == rows && num <= rowNum {
-			if num == rowNum && offset == -1 {
+			if isDeletedNum(rowNum, num, offset) {
 				_ = f.deleteCalcChain(c.I, c.R)
 				i--
 				continue
@@ -722,7 +904,7 @@ func (f *File) adjustCalcChain(ws *xlsxWorksheet, sheet string, dir adjustDirect
 			f.CalcChain.C[i].R, _ = adjustCellName(c.R, dir, colNum, rowNum, offset)
 		}
 		if dir == columns && num <= colNum {
-			if num == colNum && offset == -1 {
+			if isDeletedNum(colNum, num, offset) {
 				_ = f.deleteCalcChain(c.I, c.R)
 				i--
 				continue
@@ -742,7 +924,7 @@ func (vt *xlsxVolTypes) adjustVolatileDepsTopic(cell string, dir adjustDirection
 		return i4, err
 	}
 	if dir == rows && num <= rowNum {
-		if num == rowNum && offset == -1 {
+		if isDeletedNum(rowNum, num, offset) {
 			vt.deleteVolTopicRef(i1, i2, i3, i4)
 			i4--
 			return i4, err
@@ -750,7 +932,7 @@ func (vt *xlsxVolTypes) adjustVolatileDepsTopic(cell string, dir adjustDirection
 		vt.VolType[i1].Main[i2].Tp[i3].Tr[i4].R, _ = adjustCellName(cell, dir, colNum, rowNum, offset)
 	}
 	if dir == columns && num <= colNum {
-		if num == colNum && offset == -1 {
+		if isDeletedNum(colNum, num, offset) {
 			vt.deleteVolTopicRef(i1, i2, i3, i4)
 			i4--
 			return i4, err
@@ -795,7 +977,7 @@ func (f *File) adjustConditionalFormats(ws *xlsxWorksheet, sheet string, dir adj
 		if cf == nil {
 			continue
 		}
-		ref, del, err := f.adjustCellRef(cf.SQRef, dir, num, offset)
+		ref, del, err := f.adjustCellRef(cf.SQRef, false, dir, num, offset)
 		if err != nil {
 			return err
 		}
@@ -821,8 +1003,11 @@ func (f *File) adjustDataValidations(ws *xlsxWorksheet, sheet string, dir adjust
 			}
 			return err
 		}
+		if err = f.adjustX14DataValidations(worksheet, sheet, sheetN, dir, num, offset); err != nil {
+			return err
+		}
 		if worksheet.DataValidations == nil {
-			return nil
+			continue
 		}
 		for i := 0; i < len(worksheet.DataValidations.DataValidation); i++ {
 			dv := worksheet.DataValidations.DataValidation[i]
@@ -830,11 +1015,12 @@ func (f *File) adjustDataValidations(ws *xlsxWorksheet, sheet string, dir adjust
 				continue
 			}
 			if sheet == sheetN {
-				ref, del, err := f.adjustCellRef(dv.Sqref, dir, num, offset)
+				ref, del, err := f.adjustCellRef(dv.Sqref, false, dir, num, offset)
 				if err != nil {
 					return err
 				}
 				if del {
+					worksheet.deleteDataValidationOrder(false, i)
 					worksheet.DataValidations.DataValidation = append(worksheet.DataValidations.DataValidation[:i],
 						worksheet.DataValidations.DataValidation[i+1:]...)
 					i--
@@ -844,14 +1030,14 @@ func (f *File) adjustDataValidations(ws *xlsxWorksheet, sheet string, dir adjust
 			}
 			if worksheet.DataValidations.DataValidation[i].Formula1 != nil {
 				formula := unescapeDataValidationFormula(worksheet.DataValidations.DataValidation[i].Formula1.Content)
-				if formula, err = f.adjustFormulaRef(sheet, sheetN, formula, false, dir, num, offset); err != nil {
+				if formula, err = f.adjustFormulaRef(sheet, sheetN, formula, false, false, dir, num, offset); err != nil {
 					return err
 				}
 				worksheet.DataValidations.DataValidation[i].Formula1 = &xlsxInnerXML{Content: formulaEscaper.Replace(formula)}
 			}
 			if worksheet.DataValidations.DataValidation[i].Formula2 != nil {
 				formula := unescapeDataValidationFormula(worksheet.DataValidations.DataValidation[i].Formula2.Content)
-				if formula, err = f.adjustFormulaRef(sheet, sheetN, formula, false, dir, num, offset); err != nil {
+				if formula, err = f.adjustFormulaRef(sheet, sheetN, formula, false, false, dir, num, offset); err != nil {
 					return err
 				}
 				worksheet.DataValidations.DataValidation[i].Formula2 = &xlsxInnerXML{Content: formulaEscaper.Replace(formula)}
@@ -864,22 +1050,56 @@ func (f *File) adjustDataValidations(ws *xlsxWorksheet, sheet string, dir adjust
 	return nil
 }
 
+// adjustX14DataValidations updates the range and formulas of the data
+// validations in the worksheet extension list when inserting or deleting rows
+// or columns.
+func (f *File) adjustX14DataValidations(worksheet *xlsxWorksheet, sheet, sheetN string, dir adjustDirection, num, offset int) error {
+	dvs, err := f.getX14DataValidations(worksheet)
+	if err != nil || dvs == nil {
+		return err
+	}
+	for i := 0; i < len(dvs); i++ {
+		if sheet == sheetN {
+			ref, del, err := f.adjustCellRef(dvs[i].Sqref, false, dir, num, offset)
+			if err != nil {
+				return err
+			}
+			if del {
+				worksheet.deleteDataValidationOrder(true, i)
+				dvs = append(dvs[:i], dvs[i+1:]...)
+				i--
+				continue
+			}
+			dvs[i].Sqref = ref
+		}
+		for _, formula := range []*string{&dvs[i].Formula1, &dvs[i].Formula2} {
+			if *formula == "" {
+				continue
+			}
+			if *formula, err = f.adjustFormulaRef(sheet, sheetN, *formula, false, false, dir, num, offset); err != nil {
+				return err
+			}
+		}
+	}
+	return f.setX14DataValidations(worksheet, dvs)
+}
+
 // adjustDrawings updates the starting anchor of the two cell anchor pictures
 // and charts object when inserting or deleting rows or columns.
 func (from *xlsxFrom) adjustDrawings(dir adjustDirection, num, offset int, editAs string) (bool, error) {
 	var ok bool
-	if dir == columns && from.Col+1 >= num && from.Col+offset >= 0 {
-		if from.Col+offset >= MaxColumns {
+	if col := adjustAnchorNum(from.Col, num, offset); dir == columns && from.Col+1 >= num && col != from.Col {
+		if col >= MaxColumns {
 			return false, ErrColumnNumber
 		}
-		from.Col += offset
+		from.Col = col
 		ok = editAs == "oneCell"
 	}
-	if dir == rows && from.Row+1 >= num && from.Row+offset >= 0 {
-		if from.Row+offset >= TotalRows {
+	if row := adjustAnchorNum(from.Row, num, offset); dir == rows && from.Row+1 >= num && row != from.Row {
+		if row >= TotalRows {
 			return false, ErrMaxRows
 		}
-		from.Row += offset
+		from.Row = row
 		ok = editAs == "oneCell"
 	}
 	return ok, nil
@@ -888,17 +1108,17 @@ func (from *xlsxFrom) adjustDrawings(dir adjustDirection, num, offset int, editA
 // adjustDrawings updates the ending anchor of the two cell anchor pictures
 // and charts object when inserting or deleting rows or columns.
 func (to *xlsxTo) adjustDrawings(dir adjustDirection, num, offset int, editAs string, ok bool) error {
-	if dir == columns && to.Col+1 >= num && to.Col+offset >= 0 && ok {
-		if to.Col+offset >= MaxColumns {
+	if col := adjustAnchorNum(to.Col, num, offset); dir == columns && to.Col+1 >= num && col != to.Col && ok {
+		if col >= MaxColumns {
 			return ErrColumnNumber
 		}
-		to.Col += offset
+		to.Col = col
 	}
-	if dir == rows && to.Row+1 >= num && to.Row+offset >= 0 && ok {
-		if to.Row+offset >= TotalRows {
+	if row := adjustAnchorNum(to.Row, num, offset); dir == rows && to.Row+1 >= num && row != to.Row && ok {
+		if row >= TotalRows {
 			return ErrMaxRows
 		}
-		to.Row += offset
+		to.Row = row
 	}
 	return nil
 }
@@ -949,31 +1169,11 @@ func (f *File) adjustDrawings(ws *xlsxWorksheet, sheet string, dir adjustDirecti
 		if a.GraphicFrame == "" {
 			return a.adjustDrawings(dir, num, offset)
 		}
-		deCellAnchor := decodeCellAnchor{}
-		deCellAnchorPos := decodeCellAnchorPos{}
-		_ = f.xmlNewDecoder(strings.NewReader("<decodeCellAnchor>" + a.GraphicFrame + "</decodeCellAnchor>")).Decode(&deCellAnchor)
-		_ = f.xmlNewDecoder(strings.NewReader("<decodeCellAnchorPos>" + a.GraphicFrame + "</decodeCellAnchorPos>")).Decode(&deCellAnchorPos)
-		xlsxCellAnchorPos := xlsxCellAnchorPos(deCellAnchorPos)
-		for i := 0; i < len(xlsxCellAnchorPos.AlternateContent); i++ {
-			xlsxCellAnchorPos.AlternateContent[i].XMLNSMC = SourceRelationshipCompatibility.Value
-		}
-		if deCellAnchor.From != nil {
-			xlsxCellAnchorPos.From = &xlsxFrom{
-				Col: deCellAnchor.From.Col, ColOff: deCellAnchor.From.ColOff,
-				Row: deCellAnchor.From.Row, RowOff: deCellAnchor.From.RowOff,
-			}
-		}
-		if deCellAnchor.To != nil {
-			xlsxCellAnchorPos.To = &xlsxTo{
-				Col: deCellAnchor.To.Col, ColOff: deCellAnchor.To.ColOff,
-				Row: deCellAnchor.To.Row, RowOff: deCellAnchor.To.RowOff,
-			}
-		}
+		xlsxCellAnchorPos := f.getCellAnchorPos(a)
 		if err = xlsxCellAnchorPos.adjustDrawings(dir, num, offset, a.EditAs); err != nil {
 			return err
 		}
-		cellAnchor, _ := xml.Marshal(xlsxCellAnchorPos)
-		a.GraphicFrame = strings.TrimSuffix(strings.TrimPrefix(string(cellAnchor), "<xlsxCellAnchorPos>"), "</xlsxCellAnchorPos>")
+		a.setCellAnchorPos(xlsxCellAnchorPos)
 		return err
 	}
 	for _, anchor := range wsDr.TwoCellAnchor {
@@ -994,7 +1194,7 @@ func (f *File) adjustDefinedNames(ws *xlsxWorksheet, sheet string, dir adjustDir
 	if wb.DefinedNames != nil {
 		for i := 0; i < len(wb.DefinedNames.DefinedName); i++ {
 			data := wb.DefinedNames.DefinedName[i].Data
-			if data, err = f.adjustFormulaRef(sheet, "", data, true, dir, num, offset); err == nil {
+			if data, err = f.adjustFormulaRef(sheet, "", data, true, false, dir, num, offset); err == nil {
 				wb.DefinedNames.DefinedName[i].Data = data
 			}
 		}
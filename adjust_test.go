@@ -561,9 +561,9 @@ func TestAdjustFormula(t *testing.T) {
 	assert.Equal(t, newCellNameToCoordinatesError("-", newInvalidCellNameError("-")), f.adjustFormula("Sheet1", "Sheet1", &xlsxF{Ref: "-"}, rows, 0, 0, false))
 	assert.Equal(t, ErrColumnNumber, f.adjustFormula("Sheet1", "Sheet1", &xlsxF{Ref: "XFD1:XFD1"}, columns, 0, 1, false))
 
-	_, err := f.adjustFormulaRef("Sheet1", "Sheet1", "XFE1", false, columns, 0, 1)
+	_, err := f.adjustFormulaRef("Sheet1", "Sheet1", "XFE1", false, false, columns, 0, 1)
 	assert.Equal(t, ErrColumnNumber, err)
-	_, err = f.adjustFormulaRef("Sheet1", "Sheet1", "XFD1", false, columns, 0, 1)
+	_, err = f.adjustFormulaRef("Sheet1", "Sheet1", "XFD1", false, false, columns, 0, 1)
 	assert.Equal(t, ErrColumnNumber, err)
 
 	f = NewFile()
@@ -1204,3 +1204,61 @@ func TestAdjustDefinedNames(t *testing.T) {
 	f.Pkg.Store(defaultXMLPathWorkbook, MacintoshCyrillicCharset)
 	assert.EqualError(t, f.adjustDefinedNames(nil, "Sheet1", columns, 0, 0, 1), "XML syntax error on line 1: invalid UTF-8")
 }
+
+func TestAdjustFormulaSheetRefs(t *testing.T) {
+	rename := func(sheet string) (string, bool) {
+		switch sheet {
+		case "Sheet1":
+			return "Sheet 1", true
+		case "Sheet2":
+			return "Sheet2", false
+		}
+		return sheet, true
+	}
+	for _, c := range []struct {
+		formula, expected string
+		removed           bool
+	}{
+		{formula: "SUM(Sheet1!A1:B2,'a''b'!A1)", expected: "SUM('Sheet 1'!A1:B2,'a''b'!A1)"},
+		{formula: "Sheet3:Sheet1!A1&\"Sheet1!A1\"", expected: "'Sheet3:Sheet 1'!A1&\"Sheet1!A1\""},
+		{formula: "Sheet3:Sheet4!A1+Name1+[1]Sheet1!A1", expected: "Sheet3:Sheet4!A1+Name1+[1]Sheet1!A1"},
+		{formula: "Sheet1!A1+Sheet2!A1", expected: "'Sheet 1'!A1+#REF!", removed: true},
+		{formula: "Sheet3:Sheet2!A1", expected: "#REF!", removed: true},
+	} {
+		formula, removed := adjustFormulaSheetRefs(c.formula, rename)
+		assert.Equal(t, c.expected, formula, c.formula)
+		assert.Equal(t, c.removed, removed, c.formula)
+	}
+}
+
+func TestMoveFormulaRows(t *testing.T) {
+	moveRow := func(row int) int {
+		if row >= 2 && row <= 4 {
+			return row + 6
+		}
+		if row > 4 && row < 11 {
+			return row - 3
+		}
+		return row
+	}
+	for _, c := range []struct {
+		sheetN, formula, expected string
+		found                     bool
+	}{
+		{"Sheet1", "SUM(A2:A4)+$B$6", "SUM(A8:A10)+$B$3", true},
+		{"Sheet1", "SUM(A3:B6)", "SUM(A3:B9)", true},
+		{"Sheet1", "SUM(A:A)+SUM(2:4)+Block", "SUM(A:A)+SUM(8:10)+Block", true},
+		{"Sheet2", "A2+'Sheet 1'!A2+Sheet1!A2&\"A2\"", "A2+'Sheet 1'!A2+Sheet1!A8&\"A2\"", true},
+		{"Sheet2", "A2+[1]Sheet1!A2", "A2+[1]Sheet1!A2", false},
+		{"", "Sheet1!$A$2:$A$4", "Sheet1!$A$8:$A$10", true},
+		{"", "Sheet2!A2", "Sheet2!A2", false},
+	} {
+		formula, found := moveFormulaRows("Sheet1", c.sheetN, c.formula, moveRow)
+		assert.Equal(t, c.expected, formula, c.formula)
+		assert.Equal(t, c.found, found, c.formula)
+	}
+	// Test move formula rows with the formula which doesn't refer to the worksheet
+	formula, found := moveFormulaRows("Sheet1", "Sheet2", "SUM({1,2})+A2", moveRow)
+	assert.Equal(t, "SUM({1,2})+A2", formula)
+	assert.False(t, found)
+}
@@ -0,0 +1,248 @@
+// Copyright 2016 - 2023 The excelize Authors. All rights reserved. Use of
+// this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+//
+// package excelize_ch providing a set of functions that allow you to write to and
+// read from XLAM / XLSM / XLSX / XLTM / XLTX files. Supports reading and
+// writing spreadsheet documents generated by Microsoft Excel™ 2007 and later.
+// Supports complex components by high compatibility, and provided streaming
+// API for generating or reading data from a worksheet with huge amounts of
+// data. This library needs Go version 1.16 or later.
+
+package excelize_ch
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// AggregateCoercion is the type of the rules for coercing the cell values to
+// numbers in the column aggregation.
+type AggregateCoercion byte
+
+// This section defines the currently supported rules for coercing the cell
+// values to numbers in the column aggregation.
+const (
+	AggregateCoercionNone AggregateCoercion = iota
+	AggregateCoercionLenient
+	AggregateCoercionStrict
+)
+
+// AggregateSpec directly maps the settings of aggregating a column.
+type AggregateSpec struct {
+	Column     string
+	HeaderRows int
+	Coercion   AggregateCoercion
+	Distinct   bool
+}
+
+// ColumnAggregate directly maps the statistics of a column.
+type ColumnAggregate struct {
+	Column   string
+	Count    int
+	Numbers  int
+	Sum      float64
+	Min      float64
+	Max      float64
+	Mean     float64
+	Distinct int
+}
+
+// columnAggregator accumulates the statistics of a column in the column
+// aggregation.
+type columnAggregator struct {
+	spec     AggregateSpec
+	col      int
+	result   ColumnAggregate
+	distinct map[string]struct{}
+}
+
+// AggregateColumns provides a function to compute the statistics of the
+// columns by given worksheet name and aggregation settings in a single
+// streaming pass over the worksheet, the statistics will be returned in the
+// order of the settings. The statistics of a column are:
+//
+// Count: The number of the non-empty cells.
+//
+// Numbers: The number of the cells which values are numbers, or could be
+// coerced to numbers.
+//
+// Sum, Min, Max, Mean: The sum, minimum, maximum and arithmetic mean of the
+// numbers, which will be 0 if there are no numbers in the column.
+//
+// Distinct: The number of the distinct raw values of the non-empty cells,
+// which only be computed if the 'Distinct' setting is true.
+//
+// The aggregation settings that can be set are:
+//
+// Column: Specifies the column name, such as "A".
+//
+// HeaderRows: Specifies the number of rows at the beginning of the worksheet
+// which will be excluded from the statistics. The default value is 0.
+//
+// Coercion: Specifies the rule for coercing the cell values to numbers. The
+// date and time values will be treated as the serial numbers with all rules.
+// The available rules are:
+//
+//	 Rule                     | Description
+//	--------------------------+------------------------------------------------
+//	 AggregateCoercionNone    | Only the numeric cells will be treated as numbers
+//	 AggregateCoercionLenient | The text in numeric format will be treated as
+//	                          | numbers, and the boolean values will be treated
+//	                          | as 1 or 0
+//	 AggregateCoercionStrict  | Same as lenient, and returns an error if a
+//	                          | non-empty cell could not be coerced to number
+//
+// Distinct: Specifies if count the distinct values of the column, the raw
+// values will be kept in memory for counting. The default value is false.
+//
+// For example, compute the statistics of the column B and C with a header row
+// on a worksheet named 'Sheet1':
+//
+//	stats, err := f.AggregateColumns("Sheet1", []excelize.AggregateSpec{
+//	    {Column: "B", HeaderRows: 1},
+//	    {Column: "C", HeaderRows: 1, Coercion: excelize.AggregateCoercionStrict, Distinct: true},
+//	})
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	for _, stat := range stats {
+//	    fmt.Println(stat.Column, stat.Count, stat.Sum, stat.Mean, stat.Distinct)
+//	}
+func (f *File) AggregateColumns(sheet string, specs []AggregateSpec) ([]ColumnAggregate, error) {
+	if _, err := newColumnAggregators(specs); err != nil {
+		return nil, err
+	}
+	rows, err := f.Rows(sheet)
+	if err != nil {
+		return nil, err
+	}
+	return rows.aggregateColumns(specs)
+}
+
+// newColumnAggregators provides a function to create the column aggregators
+// by given aggregation specifications.
+func newColumnAggregators(specs []AggregateSpec) ([]*columnAggregator, error) {
+	aggregators := make([]*columnAggregator, len(specs))
+	for idx, spec := range specs {
+		col, err := ColumnNameToNumber(spec.Column)
+		if err != nil {
+			return nil, err
+		}
+		if spec.HeaderRows < 0 || spec.Coercion > AggregateCoercionStrict {
+			return nil, ErrParameterInvalid
+		}
+		aggregators[idx] = &columnAggregator{spec: spec, col: col, result: ColumnAggregate{Column: spec.Column}}
+		if spec.Distinct {
+			aggregators[idx].distinct = map[string]struct{}{}
+		}
+	}
+	return aggregators, nil
+}
+
+// aggregateColumns provides a function to compute the statistics of the
+// columns by given rows iterator and aggregation specifications, the rows will
+// be read from an in-memory sorted copy of the worksheet if the worksheet has
+// out of order rows and the strict sheet data mode is disabled.
+func (rows *Rows) aggregateColumns(specs []AggregateSpec) ([]ColumnAggregate, error) {
+	aggregators, err := newColumnAggregators(specs)
+	if err != nil {
+		_ = rows.Close()
+		return nil, err
+	}
+	for rows.Next() {
+		cells, err := rows.Cells()
+		if err != nil {
+			_ = rows.Close()
+			return nil, err
+		}
+		for _, aggregator := range aggregators {
+			if rows.seekRow <= aggregator.spec.HeaderRows || aggregator.col > len(cells) {
+				continue
+			}
+			if err = aggregator.add(cells[aggregator.col-1], rows.seekRow, rows.date1904); err != nil {
+				_ = rows.Close()
+				return nil, err
+			}
+		}
+	}
+	if rows.repaired {
+		if rows, err = rows.repairedRows(); err != nil {
+			return nil, err
+		}
+		return rows.aggregateColumns(specs)
+	}
+	if err = rows.Error(); err != nil {
+		_ = rows.Close()
+		return nil, err
+	}
+	results := make([]ColumnAggregate, len(aggregators))
+	for idx, aggregator := range aggregators {
+		if results[idx] = aggregator.result; results[idx].Numbers > 0 {
+			results[idx].Mean = results[idx].Sum / float64(results[idx].Numbers)
+		}
+		results[idx].Distinct = len(aggregator.distinct)
+	}
+	return results, rows.Close()
+}
+
+// add provides a function to accumulate the statistics of the column by
+// given typed cell, row number and if the workbook uses the 1904 date system.
+func (a *columnAggregator) add(cell RowCell, row int, date1904 bool) error {
+	if cell.RawValue == "" {
+		return nil
+	}
+	a.result.Count++
+	if a.distinct != nil {
+		a.distinct[cell.RawValue] = struct{}{}
+	}
+	number, ok := a.coerce(cell, date1904)
+	if !ok {
+		if a.spec.Coercion == AggregateCoercionStrict {
+			ref, _ := CoordinatesToCellName(a.col, row)
+			return newCoerceNumberError(cell.RawValue, ref)
+		}
+		return nil
+	}
+	if a.result.Numbers == 0 || number < a.result.Min {
+		a.result.Min = number
+	}
+	if a.result.Numbers == 0 || number > a.result.Max {
+		a.result.Max = number
+	}
+	a.result.Numbers++
+	a.result.Sum += number
+	return nil
+}
+
+// coerce provides a function to coerce the typed cell value to number by the
+// coercion rule of the column aggregation.
+func (a *columnAggregator) coerce(cell RowCell, date1904 bool) (float64, bool) {
+	switch value := cell.Value.(type) {
+	case float64:
+		return value, true
+	case time.Time:
+		if number, err := strconv.ParseFloat(cell.RawValue, 64); err == nil {
+			return number, true
+		}
+		number, err := timeToExcelTime(value, date1904)
+		return number, err == nil
+	}
+	if a.spec.Coercion == AggregateCoercionNone {
+		return 0, false
+	}
+	switch cell.Type {
+	case CellTypeBool:
+		if cell.Value.(bool) {
+			return 1, true
+		}
+		return 0, true
+	case CellTypeFormula, CellTypeInlineString, CellTypeSharedString:
+		number, err := strconv.ParseFloat(strings.TrimSpace(cell.RawValue), 64)
+		return number, err == nil && !math.IsNaN(number) && !math.IsInf(number, 0)
+	}
+	return 0, false
+}
@@ -0,0 +1,107 @@
+package excelize_ch
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAggregateColumns(t *testing.T) {
+	f := NewFile()
+	for idx, row := range [][]interface{}{
+		{"ID", "Amount", "Code", "Date"},
+		{1, 10.5, "A", time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{2, " 20 ", "B", time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC)},
+		{3, true, "A"},
+		{4, -5, "1.5"},
+		{5, nil, nil, nil},
+		{6, "n/a", "B"},
+	} {
+		cell, err := CoordinatesToCellName(1, idx+1)
+		assert.NoError(t, err)
+		assert.NoError(t, f.SetSheetRow("Sheet1", cell, &row))
+	}
+
+	stats, err := f.AggregateColumns("Sheet1", []AggregateSpec{
+		{Column: "A", HeaderRows: 1, Distinct: true},
+		{Column: "B", HeaderRows: 1},
+		{Column: "B", HeaderRows: 1, Coercion: AggregateCoercionLenient, Distinct: true},
+		{Column: "C", HeaderRows: 1, Coercion: AggregateCoercionLenient, Distinct: true},
+		{Column: "D", HeaderRows: 1},
+		{Column: "Z"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []ColumnAggregate{
+		{Column: "A", Count: 6, Numbers: 6, Sum: 21, Min: 1, Max: 6, Mean: 3.5, Distinct: 6},
+		{Column: "B", Count: 5, Numbers: 2, Sum: 5.5, Min: -5, Max: 10.5, Mean: 2.75},
+		{Column: "B", Count: 5, Numbers: 4, Sum: 26.5, Min: -5, Max: 20, Mean: 6.625, Distinct: 5},
+		{Column: "C", Count: 5, Numbers: 1, Sum: 1.5, Min: 1.5, Max: 1.5, Mean: 1.5, Distinct: 3},
+		{Column: "D", Count: 2, Numbers: 2, Sum: 89855, Min: 44927, Max: 44928, Mean: 44927.5},
+		{Column: "Z"},
+	}, stats)
+	// Test aggregate columns with the strict coercion rule
+	_, err = f.AggregateColumns("Sheet1", []AggregateSpec{{Column: "B", HeaderRows: 1, Coercion: AggregateCoercionStrict}})
+	assert.EqualError(t, err, `cannot coerce value "n/a" of cell B7 to number`)
+	stats, err = f.AggregateColumns("Sheet1", []AggregateSpec{{Column: "B", HeaderRows: 1, Coercion: AggregateCoercionStrict}, {Column: "A", HeaderRows: 7}})
+	assert.Error(t, err)
+	assert.Nil(t, stats)
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestAggregateColumns.xlsx")))
+	assert.NoError(t, f.Close())
+
+	// Test aggregate columns with the worksheet in system temporary directory
+	f, err = OpenFile(filepath.Join("test", "TestAggregateColumns.xlsx"), Options{UnzipXMLSizeLimit: 128})
+	assert.NoError(t, err)
+	stats, err = f.AggregateColumns("Sheet1", []AggregateSpec{{Column: "A", HeaderRows: 1}})
+	assert.NoError(t, err)
+	assert.Equal(t, []ColumnAggregate{{Column: "A", Count: 6, Numbers: 6, Sum: 21, Min: 1, Max: 6, Mean: 3.5}}, stats)
+	assert.NoError(t, f.Close())
+
+	// Test aggregate columns with the repaired worksheet
+	f = NewFile()
+	f.Sheet.Delete("xl/worksheets/sheet1.xml")
+	f.checked.Delete("xl/worksheets/sheet1.xml")
+	f.Pkg.Store("xl/worksheets/sheet1.xml", []byte(fmt.Sprintf(`<worksheet xmlns="%s"><sheetData><row r="2"><c r="A2"><v>2</v></c></row><row r="1"><c r="A1"><v>1</v></c></row></sheetData></worksheet>`, NameSpaceSpreadSheet.Value)))
+	stats, err = f.AggregateColumns("Sheet1", []AggregateSpec{{Column: "A", HeaderRows: 1}})
+	assert.NoError(t, err)
+	assert.Equal(t, []ColumnAggregate{{Column: "A", Count: 1, Numbers: 1, Sum: 2, Min: 2, Max: 2, Mean: 2}}, stats)
+	assert.NoError(t, f.Close())
+	// Test aggregate columns with the ISO 8601 date and boolean cells
+	f = NewFile()
+	f.Sheet.Delete("xl/worksheets/sheet1.xml")
+	f.checked.Delete("xl/worksheets/sheet1.xml")
+	f.Pkg.Store("xl/worksheets/sheet1.xml", []byte(fmt.Sprintf(`<worksheet xmlns="%s"><sheetData><row r="1"><c r="A1" t="d"><v>2023-01-01T00:00:00Z</v></c><c r="B1" t="b"><v>0</v></c><c r="C1" t="e"><v>#N/A</v></c></row></sheetData></worksheet>`, NameSpaceSpreadSheet.Value)))
+	stats, err = f.AggregateColumns("Sheet1", []AggregateSpec{{Column: "A"}, {Column: "B", Coercion: AggregateCoercionLenient}, {Column: "C", Coercion: AggregateCoercionLenient}})
+	assert.NoError(t, err)
+	assert.Equal(t, []ColumnAggregate{
+		{Column: "A", Count: 1, Numbers: 1, Sum: 44927, Min: 44927, Max: 44927, Mean: 44927},
+		{Column: "B", Count: 1, Numbers: 1},
+		{Column: "C", Count: 1},
+	}, stats)
+	assert.NoError(t, f.Close())
+	// Test aggregate columns with the unordered cells in strict mode
+	f = NewFile(Options{StrictSheetData: true})
+	f.Sheet.Delete("xl/worksheets/sheet1.xml")
+	f.checked.Delete("xl/worksheets/sheet1.xml")
+	f.Pkg.Store("xl/worksheets/sheet1.xml", []byte(fmt.Sprintf(`<worksheet xmlns="%s"><sheetData><row r="1"><c r="B1"><v>2</v></c><c r="A1"><v>1</v></c></row></sheetData></worksheet>`, NameSpaceSpreadSheet.Value)))
+	_, err = f.AggregateColumns("Sheet1", []AggregateSpec{{Column: "A"}})
+	assert.EqualError(t, err, newUnorderedCellError("A1").Error())
+	assert.NoError(t, f.Close())
+	// Test aggregate columns with invalid settings
+	f = NewFile()
+	_, err = f.AggregateColumns("Sheet1", []AggregateSpec{{Column: "-"}})
+	assert.EqualError(t, err, newInvalidColumnNameError("-").Error())
+	for _, spec := range []AggregateSpec{{Column: "A", HeaderRows: -1}, {Column: "A", Coercion: 3}} {
+		_, err = f.AggregateColumns("Sheet1", []AggregateSpec{spec})
+		assert.Equal(t, ErrParameterInvalid, err)
+	}
+	// Test aggregate columns with not exist worksheet
+	_, err = f.AggregateColumns("SheetN", nil)
+	assert.EqualError(t, err, "sheet SheetN does not exist")
+	// Test aggregate columns with invalid sheet name
+	_, err = f.AggregateColumns("Sheet:1", nil)
+	assert.Equal(t, ErrSheetNameInvalid, err)
+	assert.NoError(t, f.Close())
+}
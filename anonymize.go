@@ -0,0 +1,358 @@
+// Copyright 2016 - 2023 The excelize Authors. All rights reserved. Use of
+// this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+//
+// package excelize_ch providing a set of functions that allow you to write to and
+// read from XLAM / XLSM / XLSX / XLTM / XLTX files. Supports reading and
+// writing spreadsheet documents generated by Microsoft Excel™ 2007 and later.
+// Supports complex components by high compatibility, and provided streaming
+// API for generating or reading data from a worksheet with huge amounts of
+// data. This library needs Go version 1.16 or later.
+
+package excelize_ch
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/xml"
+	"io"
+	"math/rand"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// AnonymizeOptions directly maps the settings of the workbook anonymization.
+type AnonymizeOptions struct {
+	Seed        string
+	KeepNumbers bool
+}
+
+// anonymizer generates the deterministic fake values for the workbook
+// anonymization.
+type anonymizer struct {
+	seed  string
+	texts map[string]string
+}
+
+// Anonymize provides a function to scramble the data of the workbook by given
+// anonymization options, so that the workbook could be shared for debugging
+// without leaking data. The workbook structure, formulas and styles will be
+// kept. The following contents will be anonymized:
+//
+//  1. The string cell values (shared strings, inline strings and the cached
+//     string results of formulas) will be replaced with deterministic fakes in
+//     the same length, the upper and lower case letters, digits and Chinese
+//     characters will be replaced with the characters in the same class, and
+//     the whitespaces and punctuations will be kept. The same strings will be
+//     replaced with the same fakes, and the header cells of the tables will
+//     be kept for keeping tables and structured references valid.
+//
+//  2. The numeric cell values (include the cached numeric results of
+//     formulas) will be perturbed by replacing the digits after the first
+//     significant digit, the sign, magnitude and the number of digits will be
+//     kept. Note that the date and time values will be perturbed too.
+//
+//  3. The comments and their authors will be stripped.
+//
+//  4. The title, subject, creator, keywords, description, last modified by,
+//     identifier, category of the document core properties and the company
+//     of the document application properties will be stripped.
+//
+// Optional settings of the anonymization are:
+//
+// Seed: Specifies the seed used to generate the fakes. The same input with
+// the same seed always produces the same output. The default value is empty.
+//
+// KeepNumbers: Specifies if keep the numeric cell values. The default value
+// is false.
+//
+// Note that the sheet names, defined names, formulas, data validations,
+// hyperlinks, headers and footers, and the cached data of the pivot tables
+// and charts will not be anonymized. For example, anonymize the workbook
+// with seed:
+//
+//	err := f.Anonymize(&excelize.AnonymizeOptions{Seed: "debug"})
+func (f *File) Anonymize(opts *AnonymizeOptions) error {
+	if opts == nil {
+		opts = &AnonymizeOptions{}
+	}
+	a := &anonymizer{seed: opts.Seed, texts: map[string]string{}}
+	if err := f.anonymizeCells(a, opts); err != nil {
+		return err
+	}
+	if err := f.stripComments(); err != nil {
+		return err
+	}
+	return f.stripDocProps()
+}
+
+// anonymizeCells provides a function to replace the string cell values with
+// fakes and perturb the numeric cell values of all worksheets. The shared
+// string table will be rebuilt with the referenced strings only.
+func (f *File) anonymizeCells(a *anonymizer, opts *AnonymizeOptions) error {
+	if err := f.sharedStringsLoader(); err != nil {
+		return err
+	}
+	sst, err := f.sharedStringsReader()
+	if err != nil {
+		return err
+	}
+	sst.mu.Lock()
+	defer sst.mu.Unlock()
+	var (
+		count   int
+		items   []xlsxSI
+		indexes = map[bool]map[int]int{false: {}, true: {}}
+	)
+	if err = f.rangeWorksheets(func(sheet string, ws *xlsxWorksheet) error {
+		headers, err := f.getTableHeaderCells(sheet, ws)
+		if err != nil {
+			return err
+		}
+		for rowIdx := range ws.SheetData.Row {
+			for colIdx := range ws.SheetData.Row[rowIdx].C {
+				c := &ws.SheetData.Row[rowIdx].C[colIdx]
+				keep := headers[c.R]
+				switch c.T {
+				case "s":
+					idx, err := strconv.Atoi(strings.TrimSpace(c.V))
+					if err != nil || idx < 0 || idx >= len(sst.SI) {
+						continue
+					}
+					newIdx, ok := indexes[keep][idx]
+					if !ok {
+						item := sst.SI[idx]
+						if !keep {
+							item = a.si(item)
+						}
+						newIdx, indexes[keep][idx] = len(items), len(items)
+						items = append(items, item)
+					}
+					c.V = strconv.Itoa(newIdx)
+					count++
+				case "inlineStr":
+					if c.IS != nil && !keep {
+						*c.IS = a.si(*c.IS)
+					}
+				case "str":
+					if !keep {
+						c.V = a.text(c.V)
+					}
+				case "", "n":
+					if !keep && !opts.KeepNumbers {
+						c.V = a.number(c.V)
+					}
+				}
+			}
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+	sst.SI, sst.Count, sst.UniqueCount = items, count, len(items)
+	f.sharedStringsMap = make(map[string]int)
+	for i := range sst.SI {
+		if sst.SI[i].T != nil {
+			f.sharedStringsMap[sst.SI[i].T.Val] = i
+		}
+	}
+	return err
+}
+
+// getTableHeaderCells provides a function to get the header cell references
+// of the tables in the worksheet by given worksheet name and worksheet.
+func (f *File) getTableHeaderCells(sheet string, ws *xlsxWorksheet) (map[string]bool, error) {
+	headers := map[string]bool{}
+	if ws.TableParts == nil {
+		return headers, nil
+	}
+	for _, tbl := range ws.TableParts.TableParts {
+		if tbl == nil {
+			continue
+		}
+		target := f.getSheetRelationshipsTargetByID(sheet, tbl.RID)
+		content, ok := f.Pkg.Load(strings.ReplaceAll(target, "..", "xl"))
+		if !ok {
+			continue
+		}
+		var t xlsxTable
+		if err := f.xmlNewDecoder(bytes.NewReader(namespaceStrictToTransitional(content.([]byte)))).
+			Decode(&t); err != nil && err != io.EOF {
+			return headers, err
+		}
+		if t.HeaderRowCount != nil && *t.HeaderRowCount == 0 {
+			continue
+		}
+		coordinates, err := rangeRefToCoordinates(t.Ref)
+		if err != nil {
+			continue
+		}
+		_ = sortCoordinates(coordinates)
+		for col := coordinates[0]; col <= coordinates[2]; col++ {
+			cell, _ := CoordinatesToCellName(col, coordinates[1])
+			headers[cell] = true
+		}
+	}
+	return headers, nil
+}
+
+// stripComments provides a function to remove the comments and their authors
+// of all worksheets, and clear the threaded comments and persons parts.
+func (f *File) stripComments() error {
+	if err := f.rangeWorksheets(func(sheet string, ws *xlsxWorksheet) error {
+		sheetXMLPath, _ := f.getSheetXMLPath(sheet)
+		commentsXML := f.getSheetComments(filepath.Base(sheetXMLPath))
+		if commentsXML == "" {
+			return nil
+		}
+		if !strings.HasPrefix(commentsXML, "/") {
+			commentsXML = "xl" + strings.TrimPrefix(commentsXML, "..")
+		}
+		commentsXML = strings.TrimPrefix(commentsXML, "/")
+		cmts, err := f.commentsReader(commentsXML)
+		if err != nil {
+			return err
+		}
+		if cmts != nil {
+			cmts.Authors.Author, cmts.CommentList.Comment = nil, nil
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+	f.Pkg.Range(func(k, v interface{}) bool {
+		if path := k.(string); strings.HasPrefix(path, "xl/threadedComments/") {
+			f.Pkg.Store(path, []byte(`<ThreadedComments xmlns="http://schemas.microsoft.com/office/spreadsheetml/2018/threadedcomments"/>`))
+		} else if strings.HasPrefix(path, "xl/persons/") {
+			f.Pkg.Store(path, []byte(`<personList xmlns="http://schemas.microsoft.com/office/spreadsheetml/2018/threadedcomments"/>`))
+		}
+		return true
+	})
+	return nil
+}
+
+// stripDocProps provides a function to remove the identifying settings of the
+// document core properties and application properties.
+func (f *File) stripDocProps() error {
+	if _, ok := f.Pkg.Load(defaultXMLPathDocPropsCore); ok {
+		core := new(decodeCoreProperties)
+		if err := f.xmlNewDecoder(bytes.NewReader(namespaceStrictToTransitional(f.readXML(defaultXMLPathDocPropsCore)))).
+			Decode(core); err != nil && err != io.EOF {
+			return err
+		}
+		newProps := &xlsxCoreProperties{
+			Dc:            NameSpaceDublinCore,
+			Dcterms:       NameSpaceDublinCoreTerms,
+			Dcmitype:      NameSpaceDublinCoreMetadataInitiative,
+			XSI:           NameSpaceXMLSchemaInstance,
+			Language:      core.Language,
+			Revision:      core.Revision,
+			ContentStatus: core.ContentStatus,
+			Version:       core.Version,
+		}
+		if core.Created != nil {
+			newProps.Created = &xlsxDcTerms{Type: core.Created.Type, Text: core.Created.Text}
+		}
+		if core.Modified != nil {
+			newProps.Modified = &xlsxDcTerms{Type: core.Modified.Type, Text: core.Modified.Text}
+		}
+		output, _ := xml.Marshal(newProps)
+		f.saveFileList(defaultXMLPathDocPropsCore, output)
+	}
+	if _, ok := f.Pkg.Load(defaultXMLPathDocPropsApp); ok {
+		app, err := f.GetAppProps()
+		if err != nil {
+			return err
+		}
+		app.Company = ""
+		return f.SetAppProps(app)
+	}
+	return nil
+}
+
+// rand provides a function to get the deterministic pseudo-random number
+// generator by given kind and value of the anonymizer.
+func (a *anonymizer) rand(kind, val string) *rand.Rand {
+	sum := sha256.Sum256([]byte(a.seed + "\x00" + kind + "\x00" + val))
+	return rand.New(rand.NewSource(int64(binary.BigEndian.Uint64(sum[:8]))))
+}
+
+// text provides a function to get the fake of the given text in the same
+// length. The upper and lower case letters, digits and Chinese characters
+// will be replaced with the characters in the same class, and the other
+// characters will be kept.
+func (a *anonymizer) text(val string) string {
+	if fake, ok := a.texts[val]; ok {
+		return fake
+	}
+	var (
+		buf strings.Builder
+		rnd = a.rand("text", val)
+	)
+	for _, r := range val {
+		switch {
+		case unicode.IsUpper(r):
+			r = 'A' + rune(rnd.Intn(26))
+		case unicode.IsDigit(r):
+			r = '0' + rune(rnd.Intn(10))
+		case unicode.Is(unicode.Han, r):
+			r = 0x4E00 + rune(rnd.Intn(0x9FA5-0x4E00+1))
+		case unicode.IsLetter(r):
+			r = 'a' + rune(rnd.Intn(26))
+		}
+		buf.WriteRune(r)
+	}
+	a.texts[val] = buf.String()
+	return a.texts[val]
+}
+
+// number provides a function to perturb the given numeric value by replacing
+// the digits after the first significant digit, the sign, decimal point and
+// exponent will be kept. The non-numeric value will be returned directly.
+func (a *anonymizer) number(val string) string {
+	if _, err := strconv.ParseFloat(val, 64); err != nil {
+		return val
+	}
+	var (
+		buf                strings.Builder
+		significant        bool
+		rnd                = a.rand("number", val)
+		mantissa, exponent = val, ""
+	)
+	if idx := strings.IndexAny(val, "eE"); idx != -1 {
+		mantissa, exponent = val[:idx], val[idx:]
+	}
+	for _, r := range mantissa {
+		if '0' <= r && r <= '9' {
+			if significant {
+				r = '0' + rune(rnd.Intn(10))
+			}
+			significant = significant || r != '0'
+		}
+		buf.WriteRune(r)
+	}
+	return buf.String() + exponent
+}
+
+// si provides a function to get the anonymized string item by given string
+// item, the phonetic runs will be removed.
+func (a *anonymizer) si(si xlsxSI) xlsxSI {
+	item := xlsxSI{PhoneticPr: si.PhoneticPr}
+	if si.T != nil {
+		t := *si.T
+		t.Val = a.text(t.Val)
+		item.T = &t
+	}
+	for _, r := range si.R {
+		if r.T != nil {
+			t := *r.T
+			t.Val = a.text(t.Val)
+			r.T = &t
+		}
+		item.R = append(item.R, r)
+	}
+	return item
+}
@@ -0,0 +1,187 @@
+package excelize_ch
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAnonymize(t *testing.T) {
+	prepare := func() *File {
+		f := NewFile()
+		for cell, val := range map[string]interface{}{
+			"A1": "Name", "B1": "Salary", "C1": "Joined",
+			"A2": "Alice Smith", "B2": 12345.67, "C2": 45123,
+			"A3": "张三, Inc.", "B3": -0.0042, "C3": 45000,
+			"A4": "Alice Smith", "B4": 7,
+			"A6": "Total",
+		} {
+			assert.NoError(t, f.SetCellValue("Sheet1", cell, val))
+		}
+		assert.NoError(t, f.SetCellFormula("Sheet1", "B6", "SUM(B2:B4)"))
+		assert.NoError(t, f.SetCellFormula("Sheet1", "C6", `"Secret"&A2`))
+		ws, ok := f.Sheet.Load("xl/worksheets/sheet1.xml")
+		assert.True(t, ok)
+		ws.(*xlsxWorksheet).SheetData.Row[5].C[1].V = "12352.6658"
+		ws.(*xlsxWorksheet).SheetData.Row[5].C[2].T, ws.(*xlsxWorksheet).SheetData.Row[5].C[2].V = "str", "SecretAlice Smith"
+		assert.NoError(t, f.SetCellRichText("Sheet1", "D2", []RichTextRun{{Text: "Rich "}, {Text: "Text", Font: &Font{Bold: true}}}))
+		style, err := f.NewStyle(&Style{Font: &Font{Bold: true}})
+		assert.NoError(t, err)
+		assert.NoError(t, f.SetCellStyle("Sheet1", "A2", "A2", style))
+		assert.NoError(t, f.AddTable("Sheet1", &Table{Range: "A1:C4"}))
+		assert.NoError(t, f.AddComment("Sheet1", Comment{Cell: "A2", Author: "Bob", Text: "Private note"}))
+		assert.NoError(t, f.SetDocProps(&DocProperties{Creator: "Bob", LastModifiedBy: "Bob", Title: "Payroll", Language: "en-US"}))
+		assert.NoError(t, f.SetAppProps(&AppProperties{Company: "ACME", Application: "Microsoft Excel"}))
+		_, err = f.NewSheet("Sheet2")
+		assert.NoError(t, err)
+		assert.NoError(t, f.SetCellValue("Sheet2", "A1", "Alice Smith"))
+		return f
+	}
+	f := prepare()
+	assert.NoError(t, f.Anonymize(&AnonymizeOptions{Seed: "seed"}))
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestAnonymize.xlsx")))
+
+	// Test the table header cells are kept
+	for cell, expected := range map[string]string{"A1": "Name", "B1": "Salary", "C1": "Joined"} {
+		val, err := f.GetCellValue("Sheet1", cell)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, val)
+	}
+	// Test the string cell values are replaced in the same length and classes
+	name, err := f.GetCellValue("Sheet1", "A2")
+	assert.NoError(t, err)
+	assert.NotEqual(t, "Alice Smith", name)
+	assert.Len(t, name, len("Alice Smith"))
+	assert.Equal(t, " ", name[5:6])
+	assert.True(t, strings.ToUpper(name[:1]) == name[:1] && strings.ToLower(name[1:5]) == name[1:5])
+	company, err := f.GetCellValue("Sheet1", "A3")
+	assert.NoError(t, err)
+	assert.NotEqual(t, "张三, Inc.", company)
+	assert.Equal(t, len([]rune("张三, Inc.")), len([]rune(company)))
+	assert.True(t, strings.HasSuffix(company, "."))
+	// Test the same strings are replaced with the same fakes
+	for _, cell := range []string{"Sheet1!A4", "Sheet2!A1"} {
+		ref := strings.Split(cell, "!")
+		val, err := f.GetCellValue(ref[0], ref[1])
+		assert.NoError(t, err)
+		assert.Equal(t, name, val)
+	}
+	runs, err := f.GetCellRichText("Sheet1", "D2")
+	assert.NoError(t, err)
+	assert.Len(t, runs, 2)
+	assert.NotEqual(t, "Text", runs[1].Text)
+	assert.Len(t, runs[1].Text, 4)
+	assert.True(t, runs[1].Font.Bold)
+	// Test the numeric cell values are perturbed
+	for cell, original := range map[string]string{"B2": "12345.67", "B3": "-0.0042", "C2": "45123", "B4": "7"} {
+		val, err := f.GetCellValue("Sheet1", cell, Options{RawCellValue: true})
+		assert.NoError(t, err)
+		assert.Len(t, val, len(original))
+		prefix := original[:strings.IndexAny(original, "123456789")+1]
+		assert.True(t, strings.HasPrefix(val, prefix), cell)
+		if len(original) > 1 {
+			assert.NotEqual(t, original, val, cell)
+		}
+	}
+	// Test the formulas and styles are kept, and the cached values are anonymized
+	formula, err := f.GetCellFormula("Sheet1", "B6")
+	assert.NoError(t, err)
+	assert.Equal(t, "SUM(B2:B4)", formula)
+	formula, err = f.GetCellFormula("Sheet1", "C6")
+	assert.NoError(t, err)
+	assert.Equal(t, `"Secret"&A2`, formula)
+	ws, ok := f.Sheet.Load("xl/worksheets/sheet1.xml")
+	assert.True(t, ok)
+	assert.NotEqual(t, "12352.6658", ws.(*xlsxWorksheet).SheetData.Row[5].C[1].V)
+	assert.NotContains(t, ws.(*xlsxWorksheet).SheetData.Row[5].C[2].V, "Alice")
+	styleID, err := f.GetCellStyle("Sheet1", "A2")
+	assert.NoError(t, err)
+	assert.NotZero(t, styleID)
+	// Test the shared string table only contains referenced strings
+	for _, si := range f.SharedStrings.SI {
+		assert.NotEqual(t, "Alice Smith", si.String())
+	}
+	// Test the comments and document properties are stripped
+	comments, err := f.GetComments("Sheet1")
+	assert.NoError(t, err)
+	assert.Empty(t, comments)
+	docProps, err := f.GetDocProps()
+	assert.NoError(t, err)
+	assert.Equal(t, &DocProperties{Language: "en-US", Created: docProps.Created, Modified: docProps.Modified}, docProps)
+	appProps, err := f.GetAppProps()
+	assert.NoError(t, err)
+	assert.Empty(t, appProps.Company)
+	assert.Equal(t, "Microsoft Excel", appProps.Application)
+	assert.NoError(t, f.Close())
+
+	// Test anonymize is deterministic with the same seed
+	f2 := prepare()
+	assert.NoError(t, f2.Anonymize(&AnonymizeOptions{Seed: "seed", KeepNumbers: true}))
+	val, err := f2.GetCellValue("Sheet1", "A2")
+	assert.NoError(t, err)
+	assert.Equal(t, name, val)
+	val, err = f2.GetCellValue("Sheet1", "B2", Options{RawCellValue: true})
+	assert.NoError(t, err)
+	assert.Equal(t, "12345.67", val)
+	assert.NoError(t, f2.Close())
+	// Test anonymize with different seed
+	f3 := prepare()
+	assert.NoError(t, f3.Anonymize(nil))
+	val, err = f3.GetCellValue("Sheet1", "A2")
+	assert.NoError(t, err)
+	assert.NotEqual(t, name, val)
+	assert.NoError(t, f3.Close())
+
+	// Test anonymize the workbook with threaded comments
+	f = NewFile()
+	f.Pkg.Store("xl/threadedComments/threadedComment1.xml", []byte(`<ThreadedComments><threadedComment><text>Private</text></threadedComment></ThreadedComments>`))
+	f.Pkg.Store("xl/persons/person.xml", []byte(`<personList><person displayName="Bob"/></personList>`))
+	assert.NoError(t, f.Anonymize(nil))
+	for _, part := range []string{"xl/threadedComments/threadedComment1.xml", "xl/persons/person.xml"} {
+		content, ok := f.Pkg.Load(part)
+		assert.True(t, ok)
+		assert.NotContains(t, string(content.([]byte)), "Private")
+		assert.NotContains(t, string(content.([]byte)), "Bob")
+	}
+	assert.NoError(t, f.Close())
+}
+
+func TestAnonymizeErrors(t *testing.T) {
+	// Test anonymize with unsupported charset shared strings table
+	f := NewFile()
+	f.SharedStrings = nil
+	f.Pkg.Store(defaultXMLPathSharedStrings, MacintoshCyrillicCharset)
+	assert.EqualError(t, f.Anonymize(nil), "XML syntax error on line 1: invalid UTF-8")
+	assert.NoError(t, f.Close())
+	// Test anonymize with unsupported charset worksheet
+	f = NewFile()
+	f.Sheet.Delete("xl/worksheets/sheet1.xml")
+	f.Pkg.Store("xl/worksheets/sheet1.xml", MacintoshCyrillicCharset)
+	f.checked.Delete("xl/worksheets/sheet1.xml")
+	assert.EqualError(t, f.Anonymize(nil), "XML syntax error on line 1: invalid UTF-8")
+	assert.NoError(t, f.Close())
+	// Test anonymize with unsupported charset table
+	f = NewFile()
+	assert.NoError(t, f.AddTable("Sheet1", &Table{Range: "A1:B2"}))
+	f.Pkg.Store("xl/tables/table1.xml", MacintoshCyrillicCharset)
+	assert.EqualError(t, f.Anonymize(nil), "XML syntax error on line 1: invalid UTF-8")
+	assert.NoError(t, f.Close())
+	// Test anonymize with unsupported charset comments
+	f = NewFile()
+	assert.NoError(t, f.AddComment("Sheet1", Comment{Cell: "A1", Text: "Note"}))
+	f.Comments["xl/comments1.xml"] = nil
+	f.Pkg.Store("xl/comments1.xml", MacintoshCyrillicCharset)
+	assert.EqualError(t, f.Anonymize(nil), "XML syntax error on line 1: invalid UTF-8")
+	assert.NoError(t, f.Close())
+	// Test anonymize with unsupported charset document properties
+	f = NewFile()
+	f.Pkg.Store(defaultXMLPathDocPropsCore, MacintoshCyrillicCharset)
+	assert.EqualError(t, f.Anonymize(nil), "XML syntax error on line 1: invalid UTF-8")
+	assert.NoError(t, f.Close())
+	f = NewFile()
+	f.Pkg.Store(defaultXMLPathDocPropsApp, MacintoshCyrillicCharset)
+	assert.EqualError(t, f.Anonymize(nil), "XML syntax error on line 1: invalid UTF-8")
+	assert.NoError(t, f.Close())
+}
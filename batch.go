@@ -0,0 +1,350 @@
+// Copyright 2016 - 2023 The excelize Authors. All rights reserved. Use of
+// this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+//
+// package excelize_ch providing a set of functions that allow you to write to and
+// read from XLAM / XLSM / XLSX / XLTM / XLTX files. Supports reading and
+// writing spreadsheet documents generated by Microsoft Excel™ 2007 and later.
+// Supports complex components by high compatibility, and provided streaming
+// API for generating or reading data from a worksheet with huge amounts of
+// data. This library needs Go version 1.16 or later.
+
+package excelize_ch
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"sync"
+	"time"
+)
+
+// Batch defined the type of the cell writes collected by the BatchUpdate
+// function. The functions of the Batch are concurrency safe.
+type Batch struct {
+	mu  sync.Mutex
+	ops []batchOperation
+}
+
+// batchOperation directly maps the cell write operation of the batch.
+type batchOperation struct {
+	sheet, hCell, vCell string
+	value               interface{}
+	opts                []CellValueOpts
+	styleID             int
+	isStyle             bool
+}
+
+// batchCell directly maps the resolved coordinates and the prepared value of
+// the cell write operation of the batch.
+type batchCell struct {
+	op                     *batchOperation
+	hCol, hRow, vCol, vRow int
+	value                  xlsxC
+	isText                 bool
+	numFmt                 int
+}
+
+// batchSheet directly maps the worksheet and the resolved cell write
+// operations of the batch on it.
+type batchSheet struct {
+	name  string
+	ws    *xlsxWorksheet
+	cells []batchCell
+}
+
+// SetCellValue provides a function to collect setting the value of a cell for
+// the batch, the value types are the same as the SetCellValue function of
+// the File.
+func (b *Batch) SetCellValue(sheet, cell string, value interface{}, opts ...CellValueOpts) error {
+	if _, _, err := CellNameToCoordinates(cell); err != nil {
+		return err
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.ops = append(b.ops, batchOperation{sheet: sheet, hCell: cell, value: value, opts: opts})
+	return nil
+}
+
+// SetCellStyle provides a function to collect setting the style of the cells
+// range for the batch by given top-left and right-bottom cell reference and
+// style ID.
+func (b *Batch) SetCellStyle(sheet, hCell, vCell string, styleID int) error {
+	if _, err := cellRefsToCoordinates(hCell, vCell); err != nil {
+		return err
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.ops = append(b.ops, batchOperation{sheet: sheet, hCell: hCell, vCell: vCell, styleID: styleID, isStyle: true})
+	return nil
+}
+
+// BatchUpdate provides a function to set the values and styles of the cells
+// in batch. The cell writes collected by the given function will be applied
+// in order after the function returns, the cells of each worksheet will be
+// set under a single worksheet lock with one growth pass of the worksheet
+// data, which is faster than setting the cells one by one. None of the cell
+// writes will be applied if the given function returns an error. For example,
+// set the values and style of the cells on Sheet1:
+//
+//	err := f.BatchUpdate(func(b *excelize.Batch) error {
+//	    for row := 1; row <= 1000; row++ {
+//	        cell, err := excelize.CoordinatesToCellName(1, row)
+//	        if err != nil {
+//	            return err
+//	        }
+//	        if err := b.SetCellValue("Sheet1", cell, row); err != nil {
+//	            return err
+//	        }
+//	    }
+//	    return b.SetCellStyle("Sheet1", "A1", "A1000", styleID)
+//	})
+func (f *File) BatchUpdate(fn func(b *Batch) error) error {
+	defer f.dispatchWatchEvents()
+	b := &Batch{}
+	if err := fn(b); err != nil {
+		return err
+	}
+	var (
+		sheets []string
+		ops    = map[string][]*batchOperation{}
+		batch  []*batchSheet
+	)
+	for i := range b.ops {
+		op := &b.ops[i]
+		if _, ok := ops[op.sheet]; !ok {
+			sheets = append(sheets, op.sheet)
+		}
+		ops[op.sheet] = append(ops[op.sheet], op)
+	}
+	date1904, err := f.prepareBatch()
+	if err != nil {
+		return err
+	}
+	for _, sheet := range sheets {
+		bs, err := f.prepareBatchSheet(sheet, ops[sheet], date1904)
+		if err != nil {
+			return err
+		}
+		batch = append(batch, bs)
+	}
+	if err = f.prepareBatchStyles(batch); err != nil {
+		return err
+	}
+	for _, bs := range batch {
+		if err = f.applyBatch(bs); err != nil {
+			return err
+		}
+	}
+	return err
+}
+
+// prepareBatch provides a function to load the workbook level parts which
+// required by applying the batch, so that applying the batch will not fail
+// after any cell has been changed. It returns whether the workbook uses the
+// 1904 date system.
+func (f *File) prepareBatch() (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, err := f.stylesReader(); err != nil {
+		return false, err
+	}
+	if _, err := f.calcChainReader(); err != nil {
+		return false, err
+	}
+	if _, err := f.contentTypesReader(); err != nil {
+		return false, err
+	}
+	wb, err := f.workbookReader()
+	if err != nil || wb == nil || wb.WorkbookPr == nil {
+		return false, err
+	}
+	return wb.WorkbookPr.Date1904, err
+}
+
+// prepareBatchSheet provides a function to validate the cell write
+// operations of the batch on the worksheet by given worksheet name, and
+// resolve the coordinates and the values of the cells without changing the
+// worksheet.
+func (f *File) prepareBatchSheet(sheet string, ops []*batchOperation, date1904 bool) (*batchSheet, error) {
+	f.mu.Lock()
+	ws, err := f.workSheetReader(sheet)
+	if err != nil {
+		f.mu.Unlock()
+		return nil, err
+	}
+	s, err := f.stylesReader()
+	f.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	bs := &batchSheet{name: sheet, ws: ws, cells: make([]batchCell, len(ops))}
+	for i, op := range ops {
+		cell := batchCell{op: op}
+		if op.isStyle {
+			s.mu.Lock()
+			invalid := op.styleID < 0 || s.CellXfs == nil || len(s.CellXfs.Xf) <= op.styleID
+			s.mu.Unlock()
+			if invalid {
+				return nil, newInvalidStyleID(op.styleID)
+			}
+			coordinates, _ := cellRefsToCoordinates(op.hCell, op.vCell)
+			_ = sortCoordinates(coordinates)
+			cell.hCol, cell.hRow, cell.vCol, cell.vRow = coordinates[0], coordinates[1], coordinates[2], coordinates[3]
+			bs.cells[i] = cell
+			continue
+		}
+		ws.mu.Lock()
+		ref, err := ws.mergeCellsParser(op.hCell)
+		ws.mu.Unlock()
+		if err != nil {
+			return nil, err
+		}
+		cell.hCol, cell.hRow, _ = CellNameToCoordinates(ref)
+		cell.vCol, cell.vRow = cell.hCol, cell.hRow
+		if cell.isText, cell.numFmt, err = f.prepareBatchCellValue(&cell.value, op.value, date1904, op.opts...); err != nil {
+			return nil, err
+		}
+		bs.cells[i] = cell
+	}
+	return bs, err
+}
+
+// prepareBatchStyles provides a function to add the string values of the
+// batch into the shared string table, and create the default number format
+// styles for the date and duration values of the batch. This function should
+// be called without holding any worksheet lock, because adding the shared
+// strings and styles acquire the workbook lock.
+func (f *File) prepareBatchStyles(batch []*batchSheet) error {
+	styles := map[int]int{}
+	for _, bs := range batch {
+		for i := range bs.cells {
+			cell := &bs.cells[i]
+			if cell.isText {
+				t, v, err := f.setCellString(cell.value.V)
+				if err != nil {
+					return err
+				}
+				cell.value = xlsxC{T: t, V: v}
+			}
+			if _, ok := styles[cell.numFmt]; cell.numFmt == 0 || ok {
+				continue
+			}
+			styleID, err := f.NewStyle(&Style{NumFmt: cell.numFmt})
+			if err != nil {
+				return err
+			}
+			styles[cell.numFmt] = styleID
+		}
+	}
+	for _, bs := range batch {
+		for i := range bs.cells {
+			bs.cells[i].numFmt = styles[bs.cells[i].numFmt]
+		}
+	}
+	return nil
+}
+
+// applyBatch provides a function to apply the prepared cell write operations
+// of the batch on the worksheet under a single worksheet lock.
+func (f *File) applyBatch(bs *batchSheet) error {
+	ws := bs.ws
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+	var (
+		err    error
+		maxRow int
+		cols   = map[int]int{}
+	)
+	for _, cell := range bs.cells {
+		for row := cell.hRow; row <= cell.vRow; row++ {
+			if cols[row] < cell.vCol {
+				cols[row] = cell.vCol
+			}
+		}
+		if cell.vRow > maxRow {
+			maxRow = cell.vRow
+		}
+	}
+	ws.prepareSheetXML(0, maxRow)
+	for row, col := range cols {
+		fillColumns(&ws.SheetData.Row[row-1], col, row)
+	}
+	for _, cell := range bs.cells {
+		if cell.op.isStyle {
+			for row := cell.hRow; row <= cell.vRow; row++ {
+				for col := cell.hCol; col <= cell.vCol; col++ {
+					ws.SheetData.Row[row-1].C[col-1].S = cell.op.styleID
+				}
+			}
+			continue
+		}
+		c := &ws.SheetData.Row[cell.hRow-1].C[cell.hCol-1]
+		c.S = ws.prepareCellStyle(cell.hCol, cell.hRow, c.S)
+		if c.S == 0 {
+			c.S = cell.numFmt
+		}
+		c.T, c.V, c.IS = cell.value.T, cell.value.V, cell.value.IS
+		if err = f.removeFormula(c, ws, bs.name); err != nil {
+			return err
+		}
+	}
+	return err
+}
+
+// prepareBatchCellValue provides a function to prepare the type and value of
+// the cell for the batch by the value helpers of the SetCellValue function.
+// It returns whether the value should be added into the shared string table,
+// and the number format of the default style for the date and duration
+// values.
+func (f *File) prepareBatchCellValue(c *xlsxC, value interface{}, date1904 bool, opts ...CellValueOpts) (isText bool, numFmt int, err error) {
+	switch v := value.(type) {
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		err = setCellIntFunc(c, v)
+	case float32:
+		isText, err = f.prepareBatchCellFloat(c, float64(v), 32)
+	case float64:
+		isText, err = f.prepareBatchCellFloat(c, v, 64)
+	case string:
+		c.V, isText = f.escapeFormula(v, opts...), true
+	case []byte:
+		c.V, isText = f.escapeFormula(string(v), opts...), true
+	case time.Duration:
+		c.T, c.V = setCellDuration(v)
+		numFmt = 21
+	case time.Time:
+		var isNum bool
+		if isNum, err = c.setCellTime(v, date1904); isNum {
+			numFmt = 22
+		}
+	case bool:
+		c.T, c.V = setCellBool(v)
+	case nil:
+		c.setCellDefault("")
+	case *big.Int, *big.Rat, *big.Float, Decimal:
+		if c.V, isText, err = f.formatBigNumber(v, opts...); err == nil && !isText {
+			c.setCellDefault(c.V)
+		}
+	default:
+		c.V, isText = f.escapeFormula(fmt.Sprint(value), opts...), true
+	}
+	return
+}
+
+// prepareBatchCellFloat provides a function to prepare the float value of the
+// cell for the batch, the NaN or infinity value will be written by the
+// NonFinitePolicy of the workbook options. It returns whether the value
+// should be added into the shared string table.
+func (f *File) prepareBatchCellFloat(c *xlsxC, value float64, bitSize int) (bool, error) {
+	if !math.IsNaN(value) && !math.IsInf(value, 0) {
+		c.T, c.V = setCellFloat(value, -1, bitSize)
+		return false, nil
+	}
+	t, v, isText, err := f.formatNonFiniteFloat(value)
+	if err != nil || isText {
+		c.V = v
+		return isText, err
+	}
+	c.T, c.V = t, v
+	return false, err
+}
@@ -0,0 +1,161 @@
+package excelize_ch
+
+import (
+	"fmt"
+	"math"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBatchUpdate(t *testing.T) {
+	f := NewFile()
+	styleID, err := f.NewStyle(&Style{Font: &Font{Bold: true}})
+	assert.NoError(t, err)
+	assert.NoError(t, f.MergeCell("Sheet1", "D1", "E2"))
+	values := []interface{}{1, int64(-2), uint8(3), 4.5, float32(6.25), "text", []byte("bytes"), true, nil, 5 * time.Second, time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC), struct{}{}}
+	assert.NoError(t, f.BatchUpdate(func(b *Batch) error {
+		for i, value := range values {
+			if err := b.SetCellValue("Sheet1", fmt.Sprintf("B%d", 20-i), value); err != nil {
+				return err
+			}
+		}
+		if err := b.SetCellValue("Sheet1", "E2", "merged"); err != nil {
+			return err
+		}
+		return b.SetCellStyle("Sheet1", "C5", "A3", styleID)
+	}))
+	expected := NewFile()
+	for i, value := range values {
+		assert.NoError(t, expected.SetCellValue("Sheet1", fmt.Sprintf("B%d", 20-i), value))
+	}
+	for i := range values {
+		cell := fmt.Sprintf("B%d", 20-i)
+		val, err := f.GetCellValue("Sheet1", cell)
+		assert.NoError(t, err)
+		expectedVal, err := expected.GetCellValue("Sheet1", cell)
+		assert.NoError(t, err)
+		assert.Equal(t, expectedVal, val, cell)
+		cellType, err := f.GetCellType("Sheet1", cell)
+		assert.NoError(t, err)
+		expectedType, err := expected.GetCellType("Sheet1", cell)
+		assert.NoError(t, err)
+		assert.Equal(t, expectedType, cellType, cell)
+	}
+	val, err := f.GetCellValue("Sheet1", "D1")
+	assert.NoError(t, err)
+	assert.Equal(t, "merged", val)
+	for _, cell := range []string{"A3", "B4", "C5"} {
+		style, err := f.GetCellStyle("Sheet1", cell)
+		assert.NoError(t, err)
+		assert.Equal(t, styleID, style, cell)
+	}
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestBatchUpdate.xlsx")))
+
+	// Test batch update with concurrency
+	_, err = f.NewSheet("Sheet2")
+	assert.NoError(t, err)
+	assert.NoError(t, f.BatchUpdate(func(b *Batch) error {
+		var wg sync.WaitGroup
+		for i := 1; i <= 100; i++ {
+			wg.Add(1)
+			go func(row int) {
+				defer wg.Done()
+				assert.NoError(t, b.SetCellValue("Sheet2", fmt.Sprintf("A%d", row), row))
+			}(i)
+		}
+		wg.Wait()
+		return nil
+	}))
+	rows, err := f.GetRows("Sheet2")
+	assert.NoError(t, err)
+	assert.Len(t, rows, 100)
+	for i, row := range rows {
+		assert.Equal(t, []string{fmt.Sprint(i + 1)}, row)
+	}
+	// Test batch update the string values and write the workbook concurrently
+	var wg sync.WaitGroup
+	for i := 1; i <= 10; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			assert.NoError(t, f.BatchUpdate(func(b *Batch) error {
+				for row := 1; row <= 100; row++ {
+					if err := b.SetCellValue("Sheet2", fmt.Sprintf("B%d", row), fmt.Sprintf("value %d-%d", i, row)); err != nil {
+						return err
+					}
+				}
+				return b.SetCellValue("Sheet2", "C1", time.Duration(i))
+			}))
+		}(i)
+		go func() {
+			defer wg.Done()
+			_, err := f.WriteToBuffer()
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+
+	// Test batch update with the error returned by the given function
+	assert.EqualError(t, f.BatchUpdate(func(b *Batch) error {
+		assert.NoError(t, b.SetCellValue("Sheet1", "Z1", "unused"))
+		return b.SetCellValue("Sheet1", "A", "unused")
+	}), newCellNameToCoordinatesError("A", newInvalidCellNameError("A")).Error())
+	val, err = f.GetCellValue("Sheet1", "Z1")
+	assert.NoError(t, err)
+	assert.Empty(t, val)
+	assert.EqualError(t, f.BatchUpdate(func(b *Batch) error {
+		return b.SetCellStyle("Sheet1", "A1", "B", styleID)
+	}), newCellNameToCoordinatesError("B", newInvalidCellNameError("B")).Error())
+	// Test batch update is atomic when the later operation failed
+	for _, fn := range []func(b *Batch) error{
+		func(b *Batch) error {
+			assert.NoError(t, b.SetCellValue("Sheet1", "Z1", "unused"))
+			return b.SetCellValue("Sheet2", "A1", math.NaN())
+		},
+		func(b *Batch) error {
+			assert.NoError(t, b.SetCellValue("Sheet1", "Z1", "unused"))
+			assert.NoError(t, b.SetCellStyle("Sheet1", "Z1", "Z2", styleID))
+			return b.SetCellValue("SheetN", "A1", 1)
+		},
+	} {
+		assert.Error(t, f.BatchUpdate(fn))
+		val, err = f.GetCellValue("Sheet1", "Z1")
+		assert.NoError(t, err)
+		assert.Empty(t, val)
+		style, err := f.GetCellStyle("Sheet1", "Z2")
+		assert.NoError(t, err)
+		assert.Zero(t, style)
+	}
+	// Test batch update with invalid style ID
+	assert.EqualError(t, f.BatchUpdate(func(b *Batch) error {
+		return b.SetCellStyle("Sheet1", "A1", "B2", 10)
+	}), newInvalidStyleID(10).Error())
+	// Test batch update on not exists worksheet
+	assert.EqualError(t, f.BatchUpdate(func(b *Batch) error {
+		return b.SetCellValue("SheetN", "A1", 1)
+	}), "sheet SheetN does not exist")
+	// Test batch update with invalid sheet name
+	assert.EqualError(t, f.BatchUpdate(func(b *Batch) error {
+		return b.SetCellValue("Sheet:1", "A1", 1)
+	}), ErrSheetNameInvalid.Error())
+	// Test batch update with unsupported charset shared strings table
+	f.SharedStrings = nil
+	f.Pkg.Store(defaultXMLPathSharedStrings, MacintoshCyrillicCharset)
+	assert.EqualError(t, f.BatchUpdate(func(b *Batch) error {
+		return b.SetCellValue("Sheet1", "A1", "text")
+	}), "XML syntax error on line 1: invalid UTF-8")
+	assert.NoError(t, f.Close())
+
+	// Test batch update with unsupported charset style sheet
+	f = NewFile()
+	f.Styles = nil
+	f.Pkg.Store(defaultXMLPathStyles, MacintoshCyrillicCharset)
+	assert.EqualError(t, f.BatchUpdate(func(b *Batch) error {
+		return b.SetCellValue("Sheet1", "A1", 1)
+	}), "XML syntax error on line 1: invalid UTF-8")
+	assert.NoError(t, f.Close())
+}
@@ -16,6 +16,7 @@ import (
 	"container/list"
 	"errors"
 	"fmt"
+	"io"
 	"math"
 	"math/big"
 	"math/cmplx"
@@ -211,6 +212,10 @@ var (
 		criteriaL,
 		criteriaG,
 	}
+	// structuredRefExp defined the regular expression of the structured
+	// reference, such as Table1[Sales], Table1[[#All],[Sales]:[Cost]] and
+	// [@Sales].
+	structuredRefExp = regexp.MustCompile(`([A-Za-z_\\][\w.\\]*)?\[(?:[^\[\]]|\[[^\[\]]*\])*\]`)
 )
 
 // calcContext defines the formula execution context.
@@ -220,6 +225,16 @@ type calcContext struct {
 	maxCalcIterations uint
 	iterations        map[string]uint
 	iterationsCache   map[string]formulaArg
+	values            map[string]formulaArg
+	arrayFormulas     map[string]bool
+	images            []calcImage
+}
+
+// calcImage defines the structure of the image evaluated by the formula
+// function IMAGE in the cell.
+type calcImage struct {
+	sheet, cell     string
+	source, altText string
 }
 
 // cellRef defines the structure of a cell reference.
@@ -255,6 +270,10 @@ const (
 	ArgEmpty
 )
 
+// FormulaArg is the argument and result of the user-defined formula function
+// which registered by the RegisterFunction function.
+type FormulaArg = formulaArg
+
 // formulaArg is the argument of a formula or function.
 type formulaArg struct {
 	SheetName            string
@@ -338,6 +357,18 @@ func (fa formulaArg) ToList() []formulaArg {
 	return nil
 }
 
+// ToMatrix returns a formula argument with matrix data type, the argument
+// with list data type will be converted as a single row matrix.
+func (fa formulaArg) ToMatrix() [][]formulaArg {
+	switch fa.Type {
+	case ArgMatrix:
+		return fa.Matrix
+	case ArgList:
+		return [][]formulaArg{fa.List}
+	}
+	return [][]formulaArg{{fa}}
+}
+
 // formulaFuncs is the type of the formula functions.
 type formulaFuncs struct {
 	f           *File
@@ -347,8 +378,14 @@ type formulaFuncs struct {
 
 // CalcCellValue provides a function to get calculated cell value. This feature
 // is currently in working processing. Iterative calculation, implicit
-// intersection, explicit intersection, array formula, table formula and some
-// other formulas are not supported currently.
+// intersection, explicit intersection and some other formulas are not
+// supported currently. The structured references of the tables in the
+// formula, such as Table1[Sales], Table1[[#Totals],[Sales]] and [@Sales] will
+// be converted to the cell references on calculating. For the cell in the
+// range of an array formula, the element of the array formula results at the
+// cell will be returned, use the CalcCellValues function to get the results
+// of the whole range. Use the RegisterFunction function to provide the
+// implementations of the user-defined functions and add-in functions.
 //
 // Supported formula functions:
 //
@@ -445,6 +482,7 @@ type formulaFuncs struct {
 //	CRITBINOM
 //	CSC
 //	CSCH
+//	CUBEVALUE
 //	CUMIPMT
 //	CUMPRINC
 //	DATE
@@ -512,6 +550,7 @@ type formulaFuncs struct {
 //	FLOOR.MATH
 //	FLOOR.PRECISE
 //	FORECAST
+//	FORECAST.ETS
 //	FORECAST.LINEAR
 //	FORMULATEXT
 //	FREQUENCY
@@ -529,6 +568,7 @@ type formulaFuncs struct {
 //	GCD
 //	GEOMEAN
 //	GESTEP
+//	GETPIVOTDATA
 //	GROWTH
 //	HARMEAN
 //	HEX2BIN
@@ -544,6 +584,7 @@ type formulaFuncs struct {
 //	IFNA
 //	IFS
 //	IMABS
+//	IMAGE
 //	IMAGINARY
 //	IMARGUMENT
 //	IMCONJUGATE
@@ -591,12 +632,15 @@ type formulaFuncs struct {
 //	ISREF
 //	ISTEXT
 //	KURT
+//	LAMBDA
 //	LARGE
 //	LCM
 //	LEFT
 //	LEFTB
 //	LEN
 //	LENB
+//	LET
+//	LINEST
 //	LN
 //	LOG
 //	LOG10
@@ -834,6 +878,95 @@ func (f *File) CalcCellValue(sheet, cell string, opts ...Options) (result string
 	return
 }
 
+// CalcCellValues provides a function to get calculated cell values of the
+// given range reference, which returns the results matrix of the array
+// formula when the range reference is the range of an array formula. For
+// example, set an array formula "=A1:A3*B1:B3" for the range "C1:C3" on
+// "Sheet1", and get the calculated values of this range:
+//
+//	formulaType, ref := excelize.STCellFormulaTypeArray, "C1:C3"
+//	if err := f.SetCellFormula("Sheet1", "C1", "=A1:A3*B1:B3",
+//	    excelize.FormulaOpts{Ref: &ref, Type: &formulaType}); err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	results, err := f.CalcCellValues("Sheet1", "C1:C3")
+func (f *File) CalcCellValues(sheet, ref string, opts ...Options) ([][]string, error) {
+	coordinates, err := rangeRefToCoordinates(ref)
+	if err != nil {
+		return nil, err
+	}
+	_ = sortCoordinates(coordinates)
+	results := make([][]string, coordinates[3]-coordinates[1]+1)
+	for row := range results {
+		results[row] = make([]string, coordinates[2]-coordinates[0]+1)
+		for col := range results[row] {
+			cell, err := CoordinatesToCellName(coordinates[0]+col, coordinates[1]+row)
+			if err != nil {
+				return results, err
+			}
+			if results[row][col], err = f.CalcCellValue(sheet, cell, opts...); err != nil {
+				return results, err
+			}
+		}
+	}
+	return results, err
+}
+
+// RegisterFunction provides a function to register the user-defined formula
+// function or add-in function by given function name and the implementation,
+// which will be invoked with the arguments of the function on calculating. The
+// function name is case-insensitive, the "_xll.", "_xludf." and "_xlfn."
+// prefixes of the function name in the formula will be ignored on looking up
+// the registered functions, and the registered function will override the
+// built-in function with the same name. Each argument of the range reference
+// will be passed as the matrix formula argument. For example, register the
+// function BDP for Sheet1!A1 with formula =BDP("IBM US Equity","PX_LAST"):
+//
+//	err := f.RegisterFunction("BDP", func(args ...excelize.FormulaArg) excelize.FormulaArg {
+//	    if len(args) != 2 {
+//	        return excelize.FormulaArg{Type: excelize.ArgError, String: "#VALUE!", Error: "#VALUE!"}
+//	    }
+//	    return excelize.FormulaArg{Type: excelize.ArgNumber, Number: 143.57}
+//	})
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	result, err := f.CalcCellValue("Sheet1", "A1")
+func (f *File) RegisterFunction(name string, fn func(args ...FormulaArg) FormulaArg) error {
+	if name = getUserDefinedFunctionName(name); name == "" || fn == nil {
+		return ErrParameterInvalid
+	}
+	f.functions.Store(name, fn)
+	return nil
+}
+
+// getUserDefinedFunctionName returns the name of the user-defined function
+// without the prefixes in the upper case by given function name in formula.
+func getUserDefinedFunctionName(name string) string {
+	name = strings.ToUpper(strings.TrimSpace(name))
+	for _, prefix := range []string{"_XLL.", "_XLUDF.", "_XLFN."} {
+		name = strings.TrimPrefix(name, prefix)
+	}
+	return name
+}
+
+// calcUserDefinedFunction calculate the user-defined formula function which
+// registered by the RegisterFunction function, and returns if the function
+// was registered by given function name and arguments list.
+func (f *File) calcUserDefinedFunction(name string, argsList *list.List) (formulaArg, bool) {
+	fn, ok := f.functions.Load(getUserDefinedFunctionName(name))
+	if !ok {
+		return newEmptyFormulaArg(), ok
+	}
+	args := make([]FormulaArg, 0, argsList.Len())
+	for arg := argsList.Front(); arg != nil; arg = arg.Next() {
+		args = append(args, arg.Value.(formulaArg))
+	}
+	return fn.(func(args ...FormulaArg) FormulaArg)(args...), ok
+}
+
 // calcCellValue calculate cell value by given context, worksheet name and cell
 // reference.
 func (f *File) calcCellValue(ctx *calcContext, sheet, cell string) (result formulaArg, err error) {
@@ -841,13 +974,1189 @@ func (f *File) calcCellValue(ctx *calcContext, sheet, cell string) (result formu
 	if formula, err = f.GetCellFormula(sheet, cell); err != nil {
 		return
 	}
+	if anchor := f.getArrayFormulaCell(sheet, cell, formula == ""); anchor != "" {
+		return f.calcArrayFormulaCell(ctx, sheet, cell, anchor)
+	}
+	if formula, err = f.parseStructuredReferences(sheet, cell, formula); err != nil {
+		return newErrorFormulaArg(err.Error(), err.Error()), err
+	}
+	ps := efp.ExcelParser()
+	tokens := ps.Parse(formula)
+	if tokens == nil {
+		return f.cellResolver(ctx, sheet, cell)
+	}
+	result, err = f.evalInfixExp(ctx, sheet, cell, tokens)
+	return
+}
+
+// getArrayFormulaCell returns the anchor cell reference of the array formula
+// which range contains the given cell. The array formulas of the worksheet
+// will be searched only if the given cell doesn't have a formula.
+func (f *File) getArrayFormulaCell(sheet, cell string, search bool) string {
+	col, row, err := CellNameToCoordinates(cell)
+	if err != nil {
+		return ""
+	}
+	ws, err := f.workSheetReader(sheet)
+	if err != nil {
+		return ""
+	}
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+	for _, r := range ws.SheetData.Row {
+		if !search && r.R != row {
+			continue
+		}
+		for _, c := range r.C {
+			if c.F == nil || c.F.T != STCellFormulaTypeArray || c.F.Ref == "" {
+				continue
+			}
+			if !search && c.R != cell {
+				continue
+			}
+			coordinates, err := rangeRefToCoordinates(c.F.Ref)
+			if err != nil {
+				continue
+			}
+			_ = sortCoordinates(coordinates)
+			if col >= coordinates[0] && col <= coordinates[2] && row >= coordinates[1] && row <= coordinates[3] {
+				return c.R
+			}
+		}
+	}
+	return ""
+}
+
+// calcArrayFormulaCell evaluate the array formula by given context, worksheet
+// name and the anchor cell of the array formula, and returns the element of
+// the results matrix at the given cell.
+func (f *File) calcArrayFormulaCell(ctx *calcContext, sheet, cell, anchor string) (result formulaArg, err error) {
+	var formula string
+	if formula, err = f.GetCellFormula(sheet, anchor); err != nil {
+		return
+	}
+	if formula, err = f.parseStructuredReferences(sheet, anchor, formula); err != nil {
+		return newErrorFormulaArg(err.Error(), err.Error()), err
+	}
 	ps := efp.ExcelParser()
 	tokens := ps.Parse(formula)
 	if tokens == nil {
 		return f.cellResolver(ctx, sheet, cell)
 	}
-	result, err = f.evalInfixExp(ctx, sheet, cell, tokens)
-	return
+	ctx.mu.Lock()
+	if ctx.arrayFormulas == nil {
+		ctx.arrayFormulas = make(map[string]bool)
+	}
+	ctx.arrayFormulas[fmt.Sprintf("%s!%s", sheet, anchor)] = true
+	ctx.mu.Unlock()
+	if result, err = f.evalInfixExp(ctx, sheet, anchor, tokens); err != nil {
+		return
+	}
+	col, row, _ := CellNameToCoordinates(cell)
+	anchorCol, anchorRow, _ := CellNameToCoordinates(anchor)
+	return getArrayElement(result.ToMatrix(), row-anchorRow, col-anchorCol), err
+}
+
+// isArrayFormula returns if the formula of the given cell is evaluating as an
+// array formula in the calculation context.
+func (ctx *calcContext) isArrayFormula(sheet, cell string) bool {
+	if ctx == nil {
+		return false
+	}
+	ctx.mu.Lock()
+	defer ctx.mu.Unlock()
+	return ctx.arrayFormulas[fmt.Sprintf("%s!%s", sheet, cell)]
+}
+
+// evalRelativeFormula provides a function to evaluate the formula written
+// for the anchor cell at the given cell, the relative references in the
+// formula will be shifted by the offset between the anchor cell and the given
+// cell, and the cell values in the calculation could be replaced by the given
+// formula arguments.
+func (f *File) evalRelativeFormula(sheet, cell, anchor, formula string, values map[string]formulaArg) (formulaArg, error) {
+	formula, err := ConditionalFormatRelativeFormula(cell, anchor, strings.TrimPrefix(formula, "="))
+	if err != nil {
+		return newEmptyFormulaArg(), err
+	}
+	ctx := &calcContext{
+		maxCalcIterations: f.options.MaxCalcIterations,
+		iterations:        make(map[string]uint),
+		iterationsCache:   make(map[string]formulaArg),
+		values:            values,
+	}
+	ps := efp.ExcelParser()
+	tokens := ps.Parse(formula)
+	if len(tokens) == 1 && tokens[0].TType == efp.TokenTypeOperand && tokens[0].TSubType == efp.TokenSubTypeRange {
+		if refTo := f.getDefinedNameRefTo(tokens[0].TValue, sheet); refTo != "" {
+			tokens[0].TValue = refTo
+		}
+		return f.parseReference(ctx, sheet, tokens[0].TValue)
+	}
+	return f.evalInfixExp(ctx, sheet, cell, tokens)
+}
+
+// formulaDeps defines the precedent cell ranges of the formula cell, which is
+// used to build the dependency graph for the partial recalculation.
+type formulaDeps struct {
+	cell     cellRef
+	ranges   []cellRange
+	sheets   map[string]struct{}
+	volatile bool
+}
+
+// formulaIndex directly maps the persistent reverse dependency index of the
+// formula cells for the partial recalculation, which will be built on the
+// first recalculation and updated by the changed cells. The cells and ranges
+// fields map the single cell and the cell range precedents in the lower case
+// worksheet name to the dependent formulas.
+type formulaIndex struct {
+	mu       sync.Mutex
+	built    bool
+	deps     map[cellRef]*formulaDeps
+	cells    map[cellRef]map[*formulaDeps]struct{}
+	ranges   map[string]map[*formulaDeps]struct{}
+	volatile map[*formulaDeps]struct{}
+}
+
+// Calculate provides a function to recalculate all formulas in the workbook
+// by the dependency graph of the formulas, including the cross worksheets
+// references and the references by the defined names, and update the cached
+// values of the formula cells. The precedent formulas are always calculated
+// before the dependent formulas. The formulas with circular references will
+// be calculated iteratively if the iterative calculation is enabled by the
+// SetCalcProps function, and stop if the maximum number of iterations has
+// been reached or the maximum change between the iterations is less than the
+// maximum change setting. Otherwise, the cached values of the formulas with
+// circular references will be kept and an error will be returned after the
+// other formulas have been calculated. For example:
+//
+//	if err := f.Calculate(); err != nil {
+//	    fmt.Println(err)
+//	}
+func (f *File) Calculate() error {
+	return f.Recalculate(false)
+}
+
+// Recalculate provides a function to recalculate the formulas in the
+// workbook and update the cached values of the formula cells. If changedOnly
+// is true, only the formulas transitively depending on the cells which have
+// been changed since the workbook was opened or last recalculated will be
+// recalculated by the dependency graph of the formulas, so the recalculation
+// cost is proportional to the size of edits. The dependency index of the
+// formulas will be built on the first recalculation, and only the formulas
+// of the changed cells will be parsed again later. The formulas which contain
+// volatile functions, such as NOW, RAND and INDIRECT, or references which
+// can't be resolved will always be recalculated. Inserting or removing rows
+// and columns in a worksheet marks the whole worksheet as changed. The
+// cached values of the formulas which contain unsupported functions will be
+// kept. The formulas with circular references will be handled as described
+// in the Calculate function. The callback functions subscribed by the Watch
+// function will be invoked for the formula cells which cached values have
+// been changed. For example, recalculate the formulas depending
+// on the cell A1 after the value changed:
+//
+//	if err := f.SetCellValue("Sheet1", "A1", 100); err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	if err := f.Recalculate(true); err != nil {
+//	    fmt.Println(err)
+//	}
+func (f *File) Recalculate(changedOnly bool) error {
+	defer f.dispatchWatchEvents()
+	f.calcDeps.mu.Lock()
+	deps, changed, err := f.getRecalcFormulas(changedOnly)
+	f.calcDeps.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	circular, err := f.calcFormulas(deps)
+	if err != nil {
+		return err
+	}
+	for _, ref := range changed {
+		f.changedCells.Delete(ref)
+	}
+	if len(circular) > 0 {
+		return newCircularReferenceError(circular)
+	}
+	return nil
+}
+
+// getRecalcFormulas provides a function to update the dependency index of the
+// formula cells by the changed cells, and returns the formulas which need to
+// be recalculated and the changed cells. The dependency index will be rebuilt
+// on the full recalculation, or if it has not been built or the whole
+// worksheet has been changed, otherwise only the formulas of the changed
+// cells will be parsed again.
+func (f *File) getRecalcFormulas(changedOnly bool) ([]*formulaDeps, []cellRef, error) {
+	var changed []cellRef
+	f.changedCells.Range(func(key, _ interface{}) bool {
+		changed = append(changed, key.(cellRef))
+		return true
+	})
+	rebuild := !changedOnly || !f.calcDeps.built
+	for _, ref := range changed {
+		rebuild = rebuild || ref.Col == 0
+	}
+	if rebuild {
+		if err := f.buildFormulaIndex(); err != nil {
+			return nil, changed, err
+		}
+	} else {
+		for _, ref := range changed {
+			if err := f.indexFormula(ref.Sheet, ref.Col, ref.Row); err != nil {
+				return nil, changed, err
+			}
+		}
+	}
+	var deps []*formulaDeps
+	if changedOnly {
+		deps = f.getDirtyFormulas(changed)
+	} else {
+		for _, dep := range f.calcDeps.deps {
+			deps = append(deps, dep)
+		}
+	}
+	f.sortFormulaDeps(deps)
+	return deps, changed, nil
+}
+
+// buildFormulaIndex provides a function to build the dependency index of all
+// formula cells in the workbook.
+func (f *File) buildFormulaIndex() error {
+	var cells []cellRef
+	if err := f.rangeWorksheets(func(sheet string, ws *xlsxWorksheet) error {
+		ws.mu.Lock()
+		defer ws.mu.Unlock()
+		for _, row := range ws.SheetData.Row {
+			for _, c := range row.C {
+				if c.F == nil {
+					continue
+				}
+				col, row, err := CellNameToCoordinates(c.R)
+				if err != nil {
+					return err
+				}
+				cells = append(cells, cellRef{Col: col, Row: row, Sheet: sheet})
+			}
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+	f.calcDeps.built = false
+	f.calcDeps.deps = make(map[cellRef]*formulaDeps)
+	f.calcDeps.cells = make(map[cellRef]map[*formulaDeps]struct{})
+	f.calcDeps.ranges = make(map[string]map[*formulaDeps]struct{})
+	f.calcDeps.volatile = make(map[*formulaDeps]struct{})
+	for _, cell := range cells {
+		if err := f.indexFormula(cell.Sheet, cell.Col, cell.Row); err != nil {
+			return err
+		}
+	}
+	f.calcDeps.built = true
+	return nil
+}
+
+// indexFormula provides a function to parse the formula of the cell again
+// and update the dependency index by given worksheet name and coordinates of
+// the cell. The cell will be removed from the index if it doesn't contain a
+// formula or the worksheet doesn't exist.
+func (f *File) indexFormula(sheet string, col, row int) error {
+	idx := &f.calcDeps
+	key := cellRef{Col: col, Row: row, Sheet: strings.ToLower(sheet)}
+	if dep, ok := idx.deps[key]; ok {
+		delete(idx.deps, key)
+		delete(idx.volatile, dep)
+		for _, r := range dep.ranges {
+			if name := strings.ToLower(r.From.Sheet); r.From == r.To {
+				delete(idx.cells[cellRef{Col: r.From.Col, Row: r.From.Row, Sheet: name}], dep)
+			} else {
+				delete(idx.ranges[name], dep)
+			}
+		}
+	}
+	cell, err := CoordinatesToCellName(col, row)
+	if err != nil {
+		return err
+	}
+	formula, err := f.GetCellFormula(sheet, cell)
+	if err != nil {
+		if _, ok := err.(ErrSheetNotExist); ok {
+			return nil
+		}
+		return err
+	}
+	if formula == "" {
+		return nil
+	}
+	dep := &formulaDeps{cell: cellRef{Col: col, Row: row, Sheet: sheet}, sheets: map[string]struct{}{}}
+	f.getFormulaDeps(dep, sheet, formula, 0)
+	idx.deps[key] = dep
+	if dep.volatile {
+		idx.volatile[dep] = struct{}{}
+	}
+	for _, r := range dep.ranges {
+		name := strings.ToLower(r.From.Sheet)
+		if r.From == r.To {
+			ref := cellRef{Col: r.From.Col, Row: r.From.Row, Sheet: name}
+			if idx.cells[ref] == nil {
+				idx.cells[ref] = make(map[*formulaDeps]struct{})
+			}
+			idx.cells[ref][dep] = struct{}{}
+			continue
+		}
+		if idx.ranges[name] == nil {
+			idx.ranges[name] = make(map[*formulaDeps]struct{})
+		}
+		idx.ranges[name][dep] = struct{}{}
+	}
+	return nil
+}
+
+// sortFormulaDeps provides a function to sort the formulas by the order of
+// the worksheets and the coordinates of the formula cells.
+func (f *File) sortFormulaDeps(deps []*formulaDeps) {
+	sheets := make(map[string]int)
+	for idx, name := range f.GetSheetList() {
+		sheets[strings.ToLower(name)] = idx
+	}
+	sort.Slice(deps, func(i, j int) bool {
+		a, b := deps[i].cell, deps[j].cell
+		if x, y := sheets[strings.ToLower(a.Sheet)], sheets[strings.ToLower(b.Sheet)]; x != y {
+			return x < y
+		}
+		if a.Row != b.Row {
+			return a.Row < b.Row
+		}
+		return a.Col < b.Col
+	})
+}
+
+// calcFormulas provides a function to calculate the formulas in the order of
+// the dependency graph by given formulas with precedent cell ranges, and
+// update the cached values of the formula cells. Returns the references of
+// the formula cells with circular references which have not been calculated
+// if the iterative calculation is disabled.
+func (f *File) calcFormulas(deps []*formulaDeps) ([]string, error) {
+	var (
+		circular []int
+		refs     []string
+		values   = make(map[string]formulaArg)
+	)
+	wb, err := f.workbookReader()
+	if err != nil {
+		return refs, err
+	}
+	calcPr := wb.CalcPr
+	for _, component := range getFormulaCalcOrder(deps) {
+		if !component.circular {
+			dep := deps[component.deps[0]]
+			cell, _ := CoordinatesToCellName(dep.cell.Col, dep.cell.Row)
+			ref := dep.cell.Sheet + "!" + cell
+			result, err := f.calcCellValue(&calcContext{
+				entry:             ref,
+				maxCalcIterations: f.options.MaxCalcIterations,
+				iterations:        make(map[string]uint),
+				iterationsCache:   make(map[string]formulaArg),
+				values:            values,
+			}, dep.cell.Sheet, cell)
+			if err == nil {
+				values[ref] = result
+			}
+			if err = f.setCalcCellValue(dep.cell.Sheet, cell, result, err); err != nil {
+				return refs, err
+			}
+			continue
+		}
+		if calcPr == nil || !calcPr.Iterate {
+			for _, idx := range component.deps {
+				cell, _ := CoordinatesToCellName(deps[idx].cell.Col, deps[idx].cell.Row)
+				ref := deps[idx].cell.Sheet + "!" + cell
+				if arg, err := f.cellResolver(&calcContext{entry: ref}, deps[idx].cell.Sheet, cell); err == nil {
+					values[ref] = arg
+				}
+				circular = append(circular, idx)
+			}
+			continue
+		}
+		if err := f.calcCircularFormulas(deps, component.deps, calcPr, values); err != nil {
+			return refs, err
+		}
+	}
+	sort.Ints(circular)
+	for _, idx := range circular {
+		cell, _ := CoordinatesToCellName(deps[idx].cell.Col, deps[idx].cell.Row)
+		refs = append(refs, deps[idx].cell.Sheet+"!"+cell)
+	}
+	return refs, nil
+}
+
+// calcCircularFormulas provides a function to calculate the formulas with
+// circular references iteratively by given formulas with precedent cell
+// ranges, the indexes of the formulas in the circular references, the
+// calculation properties and the calculated values.
+func (f *File) calcCircularFormulas(deps []*formulaDeps, indexes []int, calcPr *xlsxCalcPr, values map[string]formulaArg) error {
+	count, delta := 100, 0.001
+	if calcPr.IterateCount > 0 {
+		count = calcPr.IterateCount
+	}
+	if calcPr.IterateDelta > 0 {
+		delta = calcPr.IterateDelta
+	}
+	cells, refs := make([]string, len(indexes)), make([]string, len(indexes))
+	for i, idx := range indexes {
+		cells[i], _ = CoordinatesToCellName(deps[idx].cell.Col, deps[idx].cell.Row)
+		refs[i] = deps[idx].cell.Sheet + "!" + cells[i]
+		if arg, err := f.cellResolver(&calcContext{entry: refs[i]}, deps[idx].cell.Sheet, cells[i]); err == nil {
+			values[refs[i]] = arg
+		}
+	}
+	results, errs := make([]formulaArg, len(indexes)), make([]error, len(indexes))
+	for iteration := 0; iteration < count; iteration++ {
+		converged := true
+		for i, idx := range indexes {
+			results[i], errs[i] = f.calcCellValue(&calcContext{
+				entry:             refs[i],
+				maxCalcIterations: f.options.MaxCalcIterations,
+				iterations:        make(map[string]uint),
+				iterationsCache:   make(map[string]formulaArg),
+				values:            values,
+			}, deps[idx].cell.Sheet, cells[i])
+			result := results[i]
+			if errs[i] != nil {
+				result = newErrorFormulaArg(errs[i].Error(), errs[i].Error())
+			}
+			prev := values[refs[i]]
+			if prev.Type == ArgNumber && result.Type == ArgNumber {
+				if math.Abs(result.Number-prev.Number) >= delta {
+					converged = false
+				}
+			} else if prev.Type != result.Type || prev.Value() != result.Value() {
+				converged = false
+			}
+			values[refs[i]] = result
+		}
+		if converged {
+			break
+		}
+	}
+	for i, idx := range indexes {
+		if err := f.setCalcCellValue(deps[idx].cell.Sheet, cells[i], results[i], errs[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// formulaComponent defines the strongly connected component in the
+// dependency graph of the formulas, the formulas in the component have
+// circular references if the component contains more than one formula or the
+// formula references itself.
+type formulaComponent struct {
+	deps     []int
+	circular bool
+}
+
+// getFormulaCalcOrder provides a function to get the calculation order of
+// the formulas by given formulas with precedent cell ranges. The strongly
+// connected components of the dependency graph will be returned in the
+// topological order, so the precedent formulas are always ordered before the
+// dependent formulas.
+func getFormulaCalcOrder(deps []*formulaDeps) []formulaComponent {
+	var (
+		sheets     = map[string][]int{}
+		dependents = make([][]int, len(deps))
+		selfRef    = make([]bool, len(deps))
+		index      = make([]int, len(deps))
+		lowLink    = make([]int, len(deps))
+		onStack    = make([]bool, len(deps))
+		stack      []int
+		counter    int
+		components []formulaComponent
+	)
+	for i, dep := range deps {
+		name := strings.ToLower(dep.cell.Sheet)
+		sheets[name] = append(sheets[name], i)
+	}
+	for _, cells := range sheets {
+		sort.Slice(cells, func(a, b int) bool {
+			x, y := deps[cells[a]].cell, deps[cells[b]].cell
+			return x.Row < y.Row || x.Row == y.Row && x.Col < y.Col
+		})
+	}
+	for i, dep := range deps {
+		linked := map[int]struct{}{}
+		for _, r := range dep.ranges {
+			rangeFormulaCells(deps, sheets[strings.ToLower(r.From.Sheet)], r, func(j int) {
+				if _, ok := linked[j]; ok {
+					return
+				}
+				linked[j] = struct{}{}
+				if i == j {
+					selfRef[i] = true
+					return
+				}
+				dependents[j] = append(dependents[j], i)
+			})
+		}
+	}
+	type frame struct{ v, next int }
+	for root := range deps {
+		if index[root] != 0 {
+			continue
+		}
+		frames := []frame{{v: root}}
+		for len(frames) > 0 {
+			top := &frames[len(frames)-1]
+			v := top.v
+			if top.next == 0 {
+				counter++
+				index[v], lowLink[v] = counter, counter
+				stack, onStack[v] = append(stack, v), true
+			}
+			if top.next < len(dependents[v]) {
+				w := dependents[v][top.next]
+				if top.next++; index[w] == 0 {
+					frames = append(frames, frame{v: w})
+				} else if onStack[w] && index[w] < lowLink[v] {
+					lowLink[v] = index[w]
+				}
+				continue
+			}
+			if frames = frames[:len(frames)-1]; len(frames) > 0 {
+				if parent := frames[len(frames)-1].v; lowLink[v] < lowLink[parent] {
+					lowLink[parent] = lowLink[v]
+				}
+			}
+			if lowLink[v] != index[v] {
+				continue
+			}
+			var component formulaComponent
+			for {
+				w := stack[len(stack)-1]
+				stack, onStack[w] = stack[:len(stack)-1], false
+				if component.deps = append(component.deps, w); w == v {
+					break
+				}
+			}
+			for a, b := 0, len(component.deps)-1; a < b; a, b = a+1, b-1 {
+				component.deps[a], component.deps[b] = component.deps[b], component.deps[a]
+			}
+			component.circular = len(component.deps) > 1 || selfRef[v]
+			components = append(components, component)
+		}
+	}
+	for i, j := 0, len(components)-1; i < j; i, j = i+1, j-1 {
+		components[i], components[j] = components[j], components[i]
+	}
+	return components
+}
+
+// rangeFormulaCells provides a function to call the given function for each
+// formula cell inside the cell range by given formulas with precedent cell
+// ranges and the indexes of the formulas on the worksheet of the cell range,
+// which have been sorted by the row and column number.
+func rangeFormulaCells(deps []*formulaDeps, cells []int, r cellRange, fn func(idx int)) {
+	lowerBound := func(start, row, col int) int {
+		return start + sort.Search(len(cells)-start, func(k int) bool {
+			c := deps[cells[start+k]].cell
+			return c.Row > row || c.Row == row && c.Col >= col
+		})
+	}
+	for i := lowerBound(0, r.From.Row, r.From.Col); i < len(cells); {
+		c := deps[cells[i]].cell
+		if c.Row > r.To.Row {
+			break
+		}
+		if c.Col < r.From.Col {
+			i = lowerBound(i, c.Row, r.From.Col)
+			continue
+		}
+		if c.Col > r.To.Col {
+			i = lowerBound(i, c.Row+1, r.From.Col)
+			continue
+		}
+		fn(cells[i])
+		i++
+	}
+}
+
+// getFormulaDeps provides a function to parse the precedent cell ranges of
+// the formula by given worksheet name, formula and the depth of the defined
+// name reference. The formula will be marked as volatile if it contains
+// volatile functions or the references which can't be resolved.
+func (f *File) getFormulaDeps(dep *formulaDeps, sheet, formula string, depth int) {
+	if depth > 8 {
+		dep.volatile = true
+		return
+	}
+	ps := efp.ExcelParser()
+	for _, token := range ps.Parse(formula) {
+		if isFunctionStartToken(token) {
+			switch strings.ToUpper(strings.TrimPrefix(token.TValue, "_xlfn.")) {
+			case "CELL", "INDIRECT", "INFO", "NOW", "OFFSET", "RAND", "RANDARRAY", "RANDBETWEEN", "TODAY":
+				dep.volatile = true
+			}
+		}
+		if token.TType != efp.TokenTypeOperand || token.TSubType != efp.TokenSubTypeRange {
+			continue
+		}
+		if ranges, ok := f.getFormulaRefRanges(sheet, token.TValue); ok {
+			dep.ranges = append(dep.ranges, ranges...)
+			for _, r := range ranges {
+				dep.sheets[strings.ToLower(r.From.Sheet)] = struct{}{}
+			}
+			continue
+		}
+		if refTo := f.getDefinedNameRefTo(token.TValue, sheet); refTo != "" {
+			f.getFormulaDeps(dep, sheet, strings.TrimPrefix(refTo, "="), depth+1)
+			continue
+		}
+		dep.volatile = true
+	}
+}
+
+// getFormulaRefRanges provides a function to get the cell ranges of the
+// reference in the formula by given worksheet name and reference, such as
+// A1, Sheet1!A1:B2, A:A, 1:1 and 3-D reference Sheet1:Sheet3!A1. Returns
+// false if the reference can't be resolved.
+func (f *File) getFormulaRefRanges(sheet, ref string) ([]cellRange, bool) {
+	if strings.Contains(ref, "[") {
+		return nil, false
+	}
+	sheets := []string{sheet}
+	if idx := strings.LastIndex(ref, "!"); idx != -1 {
+		sheets = strings.Split(ref[:idx], ":")
+		ref = ref[idx+1:]
+		if len(sheets) == 2 {
+			first, _ := f.GetSheetIndex(sheets[0])
+			last, _ := f.GetSheetIndex(sheets[1])
+			if first == -1 || last == -1 {
+				return nil, false
+			}
+			if first > last {
+				first, last = last, first
+			}
+			sheets = f.GetSheetList()[first : last+1]
+		}
+	}
+	cells := strings.Split(strings.ReplaceAll(ref, "$", ""), ":")
+	if len(cells) > 2 {
+		return nil, false
+	}
+	if len(cells) == 1 {
+		cells = append(cells, cells[0])
+		if _, _, err := CellNameToCoordinates(cells[0]); err != nil {
+			return nil, false
+		}
+	}
+	var coordinates []int
+	for idx, cell := range cells {
+		col, row, err := CellNameToCoordinates(cell)
+		if err != nil {
+			if row, err = strconv.Atoi(cell); err == nil {
+				col = []int{1, MaxColumns}[idx]
+			} else if col, err = ColumnNameToNumber(cell); err == nil {
+				row = []int{1, TotalRows}[idx]
+			} else {
+				return nil, false
+			}
+		}
+		coordinates = append(coordinates, col, row)
+	}
+	_ = sortCoordinates(coordinates)
+	var ranges []cellRange
+	for _, name := range sheets {
+		ranges = append(ranges, cellRange{
+			From: cellRef{Col: coordinates[0], Row: coordinates[1], Sheet: name},
+			To:   cellRef{Col: coordinates[2], Row: coordinates[3], Sheet: name},
+		})
+	}
+	return ranges, true
+}
+
+// getDirtyFormulas provides a function to get the formulas transitively
+// depending on the changed cells by the dependency index, only the
+// dependents of the changed cells will be looked up.
+func (f *File) getDirtyFormulas(changed []cellRef) []*formulaDeps {
+	var (
+		dirty  []*formulaDeps
+		idx    = &f.calcDeps
+		queue  = append([]cellRef{}, changed...)
+		marked = map[*formulaDeps]struct{}{}
+		mark   = func(dep *formulaDeps) {
+			if _, ok := marked[dep]; !ok {
+				marked[dep] = struct{}{}
+				dirty = append(dirty, dep)
+				queue = append(queue, dep.cell)
+			}
+		}
+	)
+	for dep := range idx.volatile {
+		mark(dep)
+	}
+	for len(queue) > 0 {
+		ref := queue[0]
+		queue = queue[1:]
+		name := strings.ToLower(ref.Sheet)
+		// The zero column and row of the changed cell means the whole
+		// worksheet has been changed
+		if ref.Col == 0 {
+			for _, dep := range idx.deps {
+				if _, ok := dep.sheets[name]; ok || strings.EqualFold(dep.cell.Sheet, ref.Sheet) {
+					mark(dep)
+				}
+			}
+			continue
+		}
+		key := cellRef{Col: ref.Col, Row: ref.Row, Sheet: name}
+		if dep, ok := idx.deps[key]; ok {
+			mark(dep)
+		}
+		for dep := range idx.cells[key] {
+			mark(dep)
+		}
+		for dep := range idx.ranges[name] {
+			if _, ok := marked[dep]; ok {
+				continue
+			}
+			for _, r := range dep.ranges {
+				if strings.EqualFold(r.From.Sheet, ref.Sheet) && ref.Col >= r.From.Col && ref.Col <= r.To.Col &&
+					ref.Row >= r.From.Row && ref.Row <= r.To.Row {
+					mark(dep)
+					break
+				}
+			}
+		}
+	}
+	return dirty
+}
+
+// setCalcCellValue provides a function to set the cached value of the
+// formula cell by given worksheet name, cell reference and the calculation
+// result. The cached value will be kept if the formula can't be calculated.
+func (f *File) setCalcCellValue(sheet, cell string, result formulaArg, err error) error {
+	if result.Type == ArgMatrix && len(result.Matrix) > 0 && len(result.Matrix[0]) > 0 {
+		result = result.Matrix[0][0]
+	}
+	if err != nil {
+		if inStrSlice([]string{
+			formulaErrorDIV, formulaErrorNAME, formulaErrorNA, formulaErrorNUM, formulaErrorVALUE,
+			formulaErrorREF, formulaErrorNULL, formulaErrorSPILL, formulaErrorCALC, formulaErrorGETTINGDATA,
+		}, err.Error(), true) == -1 {
+			return nil
+		}
+		result = newErrorFormulaArg(err.Error(), err.Error())
+	}
+	ws, err := f.workSheetReader(sheet)
+	if err != nil {
+		return err
+	}
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+	c, col, row, err := ws.prepareCell(cell)
+	if err != nil {
+		return err
+	}
+	t, v := c.T, c.V
+	switch result.Type {
+	case ArgNumber:
+		c.T, c.V = "", strconv.FormatFloat(result.Number, 'f', -1, 64)
+		if result.Boolean {
+			c.T, c.V = setCellBool(result.Number == 1)
+		}
+	case ArgString:
+		c.T, c.V = "str", result.String
+	case ArgError:
+		c.T, c.V = "e", result.String
+	default:
+		c.T, c.V = "", ""
+	}
+	c.IS = nil
+	if c.T != t || c.V != v {
+		f.addWatchEvents(sheet, []int{col, row, col, row})
+	}
+	return err
+}
+
+// FreezeFormulas provides a function to replace the formulas in the range
+// with the static values, like the "paste values" operation in Excel, by
+// given worksheet name and range reference. The formulas will be evaluated by
+// the calculation engine, and the cached values will be used for the formulas
+// which contain unsupported functions. Set the range reference to an empty
+// string to freeze all formulas in the worksheet, and set the worksheet name
+// to an empty string to apply it to all worksheets in the workbook. The
+// shared formulas outside the range which refer to the frozen formula will be
+// converted to normal formulas. For example, freeze the formulas in the range
+// A1:D10 on Sheet1:
+//
+//	err := f.FreezeFormulas("Sheet1", "A1:D10")
+//
+// Freeze all formulas in the workbook before distributing it externally:
+//
+//	err := f.FreezeFormulas("", "")
+func (f *File) FreezeFormulas(sheet, rangeRef string) error {
+	defer f.dispatchWatchEvents()
+	if sheet == "" {
+		return f.rangeWorksheets(func(sheet string, _ *xlsxWorksheet) error {
+			return f.freezeFormulas(sheet, rangeRef)
+		})
+	}
+	return f.freezeFormulas(sheet, rangeRef)
+}
+
+// freezeFormulas provides a function to replace the formulas in the range
+// with the static values by given worksheet name and range reference.
+func (f *File) freezeFormulas(sheet, rangeRef string) error {
+	ws, err := f.workSheetReader(sheet)
+	if err != nil {
+		return err
+	}
+	coordinates := []int{1, 1, MaxColumns, TotalRows}
+	if rangeRef != "" {
+		if !strings.Contains(rangeRef, ":") {
+			rangeRef += ":" + rangeRef
+		}
+		if coordinates, err = rangeRefToCoordinates(rangeRef); err != nil {
+			return err
+		}
+		_ = sortCoordinates(coordinates)
+	}
+	inRange := func(cell string) bool {
+		col, row, err := CellNameToCoordinates(cell)
+		return err == nil && cellInRange([]int{col, row}, coordinates)
+	}
+	var cells []string
+	masters := map[int]xlsxC{}
+	ws.mu.Lock()
+	for _, row := range ws.SheetData.Row {
+		for _, c := range row.C {
+			if c.F == nil || !inRange(c.R) {
+				continue
+			}
+			if c.F.T == STCellFormulaTypeShared && c.F.Ref != "" && c.F.Si != nil {
+				masters[*c.F.Si] = c
+			}
+			cells = append(cells, c.R)
+		}
+	}
+	ws.mu.Unlock()
+	for _, cell := range cells {
+		result, err := f.calcCellValue(&calcContext{
+			entry:             fmt.Sprintf("%s!%s", sheet, cell),
+			maxCalcIterations: f.options.MaxCalcIterations,
+			iterations:        make(map[string]uint),
+			iterationsCache:   make(map[string]formulaArg),
+		}, sheet, cell)
+		if err = f.setCalcCellValue(sheet, cell, result, err); err != nil {
+			return err
+		}
+	}
+	// Add the string results into the shared string table before locking the
+	// worksheet, because adding the shared strings acquire the workbook lock
+	var values []string
+	ws.mu.Lock()
+	for _, row := range ws.SheetData.Row {
+		for _, c := range row.C {
+			if c.F != nil && c.T == "str" && inRange(c.R) {
+				values = append(values, c.V)
+			}
+		}
+	}
+	ws.mu.Unlock()
+	sharedStrings := make(map[string]string, len(values))
+	for _, value := range values {
+		_, v, err := f.setCellString(value)
+		if err != nil {
+			return err
+		}
+		sharedStrings[value] = v
+	}
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+	sheetID := f.getSheetID(sheet)
+	for r := range ws.SheetData.Row {
+		for col := range ws.SheetData.Row[r].C {
+			c := &ws.SheetData.Row[r].C[col]
+			if c.F == nil {
+				continue
+			}
+			if !inRange(c.R) {
+				if c.F.T == STCellFormulaTypeShared && c.F.Si != nil {
+					if master, ok := masters[*c.F.Si]; ok {
+						c.F = &xlsxF{Content: master.getSharedFormula(c.R)}
+					}
+				}
+				continue
+			}
+			if c.T == "str" {
+				if v, ok := sharedStrings[c.V]; ok {
+					c.T, c.V = "s", v
+				} else {
+					c.setInlineStr(c.V)
+				}
+			}
+			c.F = nil
+			f.setCellChanged(sheet, c.R)
+			if err = f.deleteCalcChain(sheetID, c.R); err != nil {
+				return err
+			}
+		}
+	}
+	return err
+}
+
+// ReferenceIssueType is the type of the reference issue.
+type ReferenceIssueType byte
+
+// This section defines the currently supported reference issue types
+// enumeration.
+const (
+	ReferenceIssueInvalid ReferenceIssueType = iota
+	ReferenceIssueMissingSheet
+	ReferenceIssueExternalLink
+	ReferenceIssueCircular
+)
+
+// GetReferenceIssues provides a function to analyze the formulas, cell values
+// and defined names in the workbook, and returns the reference issues with
+// the suggested fixes. The following types of the issue will be detected:
+//
+//	 Type                       | Description
+//	----------------------------+------------------------------------------------
+//	 ReferenceIssueInvalid      | The formula or value contains the #REF! error
+//	 ReferenceIssueMissingSheet | The formula refers to a non-existing worksheet
+//	 ReferenceIssueExternalLink | The external link or the formula referencing it
+//	                            | is broken
+//	 ReferenceIssueCircular     | The formula refers to its own cell directly or
+//	                            | indirectly
+//
+// For example, check the workbook before publication:
+//
+//	issues, err := f.GetReferenceIssues()
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	for _, issue := range issues {
+//	    fmt.Println(issue.Sheet, issue.Cell, issue.Reference, issue.Suggestion)
+//	}
+func (f *File) GetReferenceIssues() ([]ReferenceIssue, error) {
+	issues, links, err := f.getExternalLinkIssues()
+	if err != nil {
+		return issues, err
+	}
+	var deps []*formulaDeps
+	if err = f.rangeWorksheets(func(sheet string, ws *xlsxWorksheet) error {
+		for _, row := range ws.SheetData.Row {
+			for _, c := range row.C {
+				if c.F == nil {
+					if c.T == "e" && c.V == formulaErrorREF {
+						issues = append(issues, ReferenceIssue{
+							Type: ReferenceIssueInvalid, Sheet: sheet, Cell: c.R, Reference: c.V,
+							Suggestion: "replace the error value with a valid value",
+						})
+					}
+					continue
+				}
+				formula, err := f.GetCellFormula(sheet, c.R)
+				if err != nil {
+					return err
+				}
+				found := f.getFormulaRefIssues(ReferenceIssue{Sheet: sheet, Cell: c.R, Formula: formula}, links)
+				if len(found) == 0 && c.T == "e" && c.V == formulaErrorREF {
+					found = append(found, ReferenceIssue{
+						Type: ReferenceIssueInvalid, Sheet: sheet, Cell: c.R, Formula: formula, Reference: c.V,
+						Suggestion: "check the arguments of the formula which results in the #REF! error",
+					})
+				}
+				issues = append(issues, found...)
+				col, row, err := CellNameToCoordinates(c.R)
+				if err != nil {
+					return err
+				}
+				dep := &formulaDeps{cell: cellRef{Col: col, Row: row, Sheet: sheet}, sheets: map[string]struct{}{}}
+				f.getFormulaDeps(dep, sheet, formula, 0)
+				deps = append(deps, dep)
+			}
+		}
+		return nil
+	}); err != nil {
+		return issues, err
+	}
+	wb, _ := f.workbookReader()
+	if wb.DefinedNames != nil {
+		for _, dn := range wb.DefinedNames.DefinedName {
+			var scope string
+			if dn.LocalSheetID != nil {
+				scope = f.GetSheetName(*dn.LocalSheetID)
+			}
+			issues = append(issues, f.getFormulaRefIssues(ReferenceIssue{
+				Sheet: scope, DefinedName: dn.Name, Formula: dn.Data,
+			}, links)...)
+		}
+	}
+	return append(issues, f.getCircularRefIssues(deps)...), err
+}
+
+// getExternalLinkIssues provides a function to get the issues of the external
+// links of the workbook which relationship or part doesn't exist, and returns
+// the indexes of the valid external links.
+func (f *File) getExternalLinkIssues() ([]ReferenceIssue, map[int]bool, error) {
+	var issues []ReferenceIssue
+	links := map[int]bool{}
+	wb, err := f.workbookReader()
+	if err != nil || wb.ExternalReferences == nil {
+		return issues, links, err
+	}
+	rels, err := f.relsReader(f.getWorkbookRelsPath())
+	if err != nil {
+		return issues, links, err
+	}
+	wbPath := f.getWorkbookPath()
+	for idx, ref := range wb.ExternalReferences.ExternalReference {
+		var target string
+		if rels != nil {
+			rels.mu.Lock()
+			for _, rel := range rels.Relationships {
+				if rel.ID == ref.RID && rel.Type == SourceRelationshipExternalLink {
+					target = getRelsTargetPath(wbPath, rel.Target)
+				}
+			}
+			rels.mu.Unlock()
+		}
+		if links[idx+1] = target != "" && f.hasPart(target); !links[idx+1] {
+			issues = append(issues, ReferenceIssue{
+				Type: ReferenceIssueExternalLink, Reference: fmt.Sprintf("[%d]", idx+1),
+				Suggestion: "remove the external link or restore the external link part",
+			})
+		}
+	}
+	return issues, links, err
+}
+
+// getFormulaRefIssues provides a function to get the issues of the references
+// in the formula by given issue with the location and formula, and the
+// indexes of the valid external links.
+func (f *File) getFormulaRefIssues(location ReferenceIssue, links map[int]bool) []ReferenceIssue {
+	var issues []ReferenceIssue
+	add := func(typ ReferenceIssueType, ref, suggestion string) {
+		issue := location
+		issue.Type, issue.Reference, issue.Suggestion = typ, ref, suggestion
+		issues = append(issues, issue)
+	}
+	ps := efp.ExcelParser()
+	for _, token := range ps.Parse(location.Formula) {
+		if token.TType != efp.TokenTypeOperand ||
+			(token.TSubType != efp.TokenSubTypeRange && token.TSubType != efp.TokenSubTypeError) {
+			continue
+		}
+		ref := token.TValue
+		if strings.Contains(strings.ToUpper(ref), formulaErrorREF) {
+			add(ReferenceIssueInvalid, ref, "replace the invalid reference with a valid reference or remove the formula")
+			continue
+		}
+		if matches := regexp.MustCompile(`^'?\[(\d+)\]`).FindStringSubmatch(ref); len(matches) > 1 {
+			if idx, _ := strconv.Atoi(matches[1]); !links[idx] {
+				add(ReferenceIssueExternalLink, ref, "restore the external link or convert the formula to the static value")
+			}
+			continue
+		}
+		idx := strings.LastIndex(ref, "!")
+		if idx == -1 || strings.Contains(ref, "[") {
+			continue
+		}
+		for _, name := range strings.Split(strings.ReplaceAll(strings.Trim(ref[:idx], "'"), "''", "'"), ":") {
+			if sheetIdx, _ := f.GetSheetIndex(name); sheetIdx == -1 {
+				add(ReferenceIssueMissingSheet, ref, fmt.Sprintf("create the worksheet %s or update the reference to an existing worksheet", name))
+				break
+			}
+		}
+	}
+	return issues
+}
+
+// getCircularRefIssues provides a function to get the formulas which refer to
+// their own cells directly or indirectly by given formulas with precedent cell
+// ranges. The strongly connected components of the dependency graph will be
+// found by Tarjan's algorithm.
+func (f *File) getCircularRefIssues(deps []*formulaDeps) []ReferenceIssue {
+	var (
+		issues         []ReferenceIssue
+		stack          []int
+		counter        int
+		indexes, lowes = make([]int, len(deps)), make([]int, len(deps))
+		onStack        = make([]bool, len(deps))
+		edges          = make([][]int, len(deps))
+		selfRef        = make([]bool, len(deps))
+		sheets         = map[string][]int{}
+		strongConnect  func(v int)
+	)
+	for i, dep := range deps {
+		sheet := strings.ToLower(dep.cell.Sheet)
+		sheets[sheet] = append(sheets[sheet], i)
+	}
+	for i, dep := range deps {
+		for _, r := range dep.ranges {
+			for _, j := range sheets[strings.ToLower(r.From.Sheet)] {
+				if cell := deps[j].cell; cell.Col >= r.From.Col && cell.Col <= r.To.Col &&
+					cell.Row >= r.From.Row && cell.Row <= r.To.Row {
+					edges[i], selfRef[i] = append(edges[i], j), selfRef[i] || i == j
+				}
+			}
+		}
+	}
+	strongConnect = func(v int) {
+		counter++
+		indexes[v], lowes[v] = counter, counter
+		stack, onStack[v] = append(stack, v), true
+		for _, w := range edges[v] {
+			if indexes[w] == 0 {
+				if strongConnect(w); lowes[w] < lowes[v] {
+					lowes[v] = lowes[w]
+				}
+			} else if onStack[w] && indexes[w] < lowes[v] {
+				lowes[v] = indexes[w]
+			}
+		}
+		if lowes[v] != indexes[v] {
+			return
+		}
+		var component []int
+		for {
+			w := stack[len(stack)-1]
+			stack, onStack[w] = stack[:len(stack)-1], false
+			if component = append(component, w); w == v {
+				break
+			}
+		}
+		if len(component) == 1 && !selfRef[v] {
+			return
+		}
+		sort.Ints(component)
+		var cells []string
+		for _, i := range component {
+			cell, _ := CoordinatesToCellName(deps[i].cell.Col, deps[i].cell.Row)
+			cells = append(cells, fmt.Sprintf("%s!%s", deps[i].cell.Sheet, cell))
+		}
+		for _, i := range component {
+			cell, _ := CoordinatesToCellName(deps[i].cell.Col, deps[i].cell.Row)
+			formula, _ := f.GetCellFormula(deps[i].cell.Sheet, cell)
+			issues = append(issues, ReferenceIssue{
+				Type: ReferenceIssueCircular, Sheet: deps[i].cell.Sheet, Cell: cell, Formula: formula,
+				Reference:  strings.Join(cells, ","),
+				Suggestion: "break the circular reference or enable the iterative calculation",
+			})
+		}
+	}
+	for v := range deps {
+		if indexes[v] == 0 {
+			strongConnect(v)
+		}
+	}
+	return issues
 }
 
 // getPriority calculate arithmetic operator priority.
@@ -920,11 +2229,23 @@ func newEmptyFormulaArg() formulaArg {
 func (f *File) evalInfixExp(ctx *calcContext, sheet, cell string, tokens []efp.Token) (formulaArg, error) {
 	var err error
 	opdStack, optStack, opfStack, opfdStack, opftStack, argsStack := NewStack(), NewStack(), NewStack(), NewStack(), NewStack(), NewStack()
-	var inArray, inArrayRow bool
+	tokens = f.expandLambdaTokens(sheet, tokens)
 	for i := 0; i < len(tokens); i++ {
 		token := tokens[i]
 
 		// out of function stack
+		if opfStack.Len() == 0 && token.TSubType == efp.TokenSubTypeRange && ctx.isArrayFormula(sheet, cell) {
+			// keep the range reference as a matrix operand in the array formula
+			if refTo := f.getDefinedNameRefTo(token.TValue, sheet); refTo != "" {
+				token.TValue = refTo
+			}
+			result, err := f.parseReference(ctx, sheet, token.TValue)
+			if err != nil {
+				return newEmptyFormulaArg(), errors.New(formulaErrorNAME)
+			}
+			opdStack.Push(result)
+			continue
+		}
 		if opfStack.Len() == 0 {
 			if err = f.parseToken(ctx, sheet, token, opdStack, optStack); err != nil {
 				return newEmptyFormulaArg(), err
@@ -934,11 +2255,17 @@ func (f *File) evalInfixExp(ctx *calcContext, sheet, cell string, tokens []efp.T
 		// function start
 		if isFunctionStartToken(token) {
 			if token.TValue == "ARRAY" {
-				inArray = true
-				continue
-			}
-			if token.TValue == "ARRAYROW" {
-				inArrayRow = true
+				// keep the array constant as an operand
+				stop := matchStopToken(tokens, i)
+				if stop == -1 {
+					return newEmptyFormulaArg(), ErrInvalidFormula
+				}
+				if opfStack.Len() == 0 {
+					opdStack.Push(parseArrayTokens(tokens[i : stop+1]))
+				} else {
+					opfdStack.Push(parseArrayTokens(tokens[i : stop+1]))
+				}
+				i = stop
 				continue
 			}
 			opfStack.Push(token)
@@ -997,6 +2324,17 @@ func (f *File) evalInfixExp(ctx *calcContext, sheet, cell string, tokens []efp.T
 				}
 			}
 
+			// keep the range reference as a matrix operand of the operators
+			if token.TSubType == efp.TokenSubTypeRange {
+				if refTo := f.getDefinedNameRefTo(token.TValue, sheet); refTo != "" {
+					token.TValue = refTo
+				}
+				if result, err := f.parseReference(ctx, sheet, token.TValue); err == nil && result.Type == ArgMatrix {
+					opfdStack.Push(result)
+					continue
+				}
+			}
+
 			// check current token is opft
 			if err = f.parseToken(ctx, sheet, token, opfdStack, opftStack); err != nil {
 				return newEmptyFormulaArg(), err
@@ -1018,18 +2356,6 @@ func (f *File) evalInfixExp(ctx *calcContext, sheet, cell string, tokens []efp.T
 				continue
 			}
 
-			if inArrayRow && isOperand(token) {
-				continue
-			}
-			if inArrayRow && isFunctionStopToken(token) {
-				inArrayRow = false
-				continue
-			}
-			if inArray && isFunctionStopToken(token) {
-				argsStack.Peek().(*list.List).PushBack(opfdStack.Pop())
-				inArray = false
-				continue
-			}
 			if errArg := f.evalInfixExpFunc(ctx, sheet, cell, token, nextToken, opfStack, opdStack, opftStack, opfdStack, argsStack); errArg.Type == ArgError {
 				return errArg, errors.New(errArg.Error)
 			}
@@ -1055,9 +2381,12 @@ func (f *File) evalInfixExpFunc(ctx *calcContext, sheet, cell string, token, nex
 	}
 	prepareEvalInfixExp(opfStack, opftStack, opfdStack, argsStack)
 	// call formula function to evaluate
-	arg := callFuncByName(&formulaFuncs{f: f, sheet: sheet, cell: cell, ctx: ctx}, strings.NewReplacer(
-		"_xlfn.", "", ".", "dot").Replace(opfStack.Peek().(efp.Token).TValue),
-		[]reflect.Value{reflect.ValueOf(argsStack.Peek().(*list.List))})
+	arg, ok := f.calcUserDefinedFunction(opfStack.Peek().(efp.Token).TValue, argsStack.Peek().(*list.List))
+	if !ok {
+		arg = callFuncByName(&formulaFuncs{f: f, sheet: sheet, cell: cell, ctx: ctx}, strings.NewReplacer(
+			"_xlfn.", "", ".", "dot").Replace(opfStack.Peek().(efp.Token).TValue),
+			[]reflect.Value{reflect.ValueOf(argsStack.Peek().(*list.List))})
+	}
 	if arg.Type == ArgError && opfStack.Len() == 1 {
 		return arg
 	}
@@ -1073,7 +2402,7 @@ func (f *File) evalInfixExpFunc(ctx *calcContext, sheet, cell string, token, nex
 		argsStack.Peek().(*list.List).PushBack(arg)
 		return newEmptyFormulaArg()
 	}
-	if arg.Type == ArgMatrix && len(arg.Matrix) > 0 && len(arg.Matrix[0]) > 0 {
+	if arg.Type == ArgMatrix && len(arg.Matrix) > 0 && len(arg.Matrix[0]) > 0 && !ctx.isArrayFormula(sheet, cell) {
 		opdStack.Push(arg.Matrix[0][0])
 		return newEmptyFormulaArg()
 	}
@@ -1281,6 +2610,10 @@ func calculate(opdStack *Stack, opt efp.Token) error {
 			return ErrInvalidFormula
 		}
 		opd := opdStack.Pop().(formulaArg)
+		if opd.Type == ArgMatrix {
+			opdStack.Push(calcMatrix(opd, newNumberFormulaArg(0), calcSubtract))
+			return nil
+		}
 		opdStack.Push(newNumberFormulaArg(0 - opd.ToNumber().Number))
 	}
 	if opt.TValue == "-" && opt.TType == efp.TokenTypeOperatorInfix {
@@ -1289,6 +2622,10 @@ func calculate(opdStack *Stack, opt efp.Token) error {
 		}
 		rOpd := opdStack.Pop().(formulaArg)
 		lOpd := opdStack.Pop().(formulaArg)
+		if rOpd.Type == ArgMatrix || lOpd.Type == ArgMatrix {
+			opdStack.Push(calcMatrix(rOpd, lOpd, calcSubtract))
+			return nil
+		}
 		if err := calcSubtract(rOpd, lOpd, opdStack); err != nil {
 			return err
 		}
@@ -1312,25 +2649,87 @@ func calculate(opdStack *Stack, opt efp.Token) error {
 		}
 		rOpd := opdStack.Pop().(formulaArg)
 		lOpd := opdStack.Pop().(formulaArg)
-		if opt.TValue != "&" {
-			if rOpd.Value() == "" {
-				rOpd = newNumberFormulaArg(0)
+		calc := func(rOpd, lOpd formulaArg, opdStack *Stack) error {
+			if opt.TValue != "&" {
+				if rOpd.Value() == "" {
+					rOpd = newNumberFormulaArg(0)
+				}
+				if lOpd.Value() == "" {
+					lOpd = newNumberFormulaArg(0)
+				}
 			}
-			if lOpd.Value() == "" {
-				lOpd = newNumberFormulaArg(0)
+			if rOpd.Type == ArgError {
+				return errors.New(rOpd.Value())
 			}
+			if lOpd.Type == ArgError {
+				return errors.New(lOpd.Value())
+			}
+			return fn(rOpd, lOpd, opdStack)
 		}
-		if rOpd.Type == ArgError {
-			return errors.New(rOpd.Value())
-		}
-		if lOpd.Type == ArgError {
-			return errors.New(lOpd.Value())
+		if rOpd.Type == ArgMatrix || lOpd.Type == ArgMatrix {
+			opdStack.Push(calcMatrix(rOpd, lOpd, calc))
+			return nil
 		}
-		return fn(rOpd, lOpd, opdStack)
+		return calc(rOpd, lOpd, opdStack)
 	}
 	return nil
 }
 
+// calcMatrix evaluate the arithmetic operations for each element of the
+// operands, and returns the results matrix. The operand with a single row or
+// column will be expanded to the size of the other operand, and the elements
+// out of the range of the operand will be evaluated as #N/A error.
+func calcMatrix(rOpd, lOpd formulaArg, fn func(rOpd, lOpd formulaArg, opdStack *Stack) error) formulaArg {
+	rMtx, lMtx := rOpd.ToMatrix(), lOpd.ToMatrix()
+	rows, cols := len(lMtx), 0
+	if len(rMtx) > rows {
+		rows = len(rMtx)
+	}
+	for _, mtx := range [][][]formulaArg{rMtx, lMtx} {
+		if len(mtx) > 0 && len(mtx[0]) > cols {
+			cols = len(mtx[0])
+		}
+	}
+	mtx := make([][]formulaArg, rows)
+	for row := range mtx {
+		mtx[row] = make([]formulaArg, cols)
+		for col := range mtx[row] {
+			rArg, lArg := getArrayElement(rMtx, row, col), getArrayElement(lMtx, row, col)
+			if lArg.Type == ArgError {
+				mtx[row][col] = lArg
+				continue
+			}
+			if rArg.Type == ArgError {
+				mtx[row][col] = rArg
+				continue
+			}
+			stack := NewStack()
+			if err := fn(rArg, lArg, stack); err != nil {
+				mtx[row][col] = newErrorFormulaArg(err.Error(), err.Error())
+				continue
+			}
+			mtx[row][col] = stack.Pop().(formulaArg)
+		}
+	}
+	return newMatrixFormulaArg(mtx)
+}
+
+// getArrayElement returns the element of the array by given zero-based row
+// and column index. The array with a single row or column will be expanded,
+// and the #N/A error will be returned if the index out of the array range.
+func getArrayElement(mtx [][]formulaArg, row, col int) formulaArg {
+	if len(mtx) == 1 {
+		row = 0
+	}
+	if len(mtx) > 0 && len(mtx[0]) == 1 {
+		col = 0
+	}
+	if row >= len(mtx) || col >= len(mtx[row]) {
+		return newErrorFormulaArg(formulaErrorNA, formulaErrorNA)
+	}
+	return mtx[row][col]
+}
+
 // parseOperatorPrefixToken parse operator prefix token.
 func (f *File) parseOperatorPrefixToken(optStack, opdStack *Stack, token efp.Token) (err error) {
 	if optStack.Len() == 0 {
@@ -1341,7 +2740,8 @@ func (f *File) parseOperatorPrefixToken(optStack, opdStack *Stack, token efp.Tok
 	topOpt := optStack.Peek().(efp.Token)
 	topOptPriority := getPriority(topOpt)
 	if topOpt.TValue == "-" && topOpt.TType == efp.TokenTypeOperatorPrefix && token.TValue == "-" && token.TType == efp.TokenTypeOperatorPrefix {
-		optStack.Pop()
+		// keep the double negation to coerce the logical values to numbers
+		optStack.Push(token)
 		return
 	}
 	if tokenPriority > topOptPriority {
@@ -1393,7 +2793,290 @@ func isOperatorPrefixToken(token efp.Token) bool {
 
 // isOperand determine if the token is parse operand.
 func isOperand(token efp.Token) bool {
-	return token.TType == efp.TokenTypeOperand && (token.TSubType == efp.TokenSubTypeNumber || token.TSubType == efp.TokenSubTypeText || token.TSubType == efp.TokenSubTypeLogical)
+	return token.TType == efp.TokenTypeOperand && (token.TSubType == efp.TokenSubTypeNumber || token.TSubType == efp.TokenSubTypeText || token.TSubType == efp.TokenSubTypeLogical || token.TSubType == efp.TokenSubTypeError)
+}
+
+// parseArrayTokens parse the tokens of the array constant and returns the
+// matrix formula argument, the array constant with only one element will be
+// returned as the element.
+func parseArrayTokens(tokens []efp.Token) formulaArg {
+	var mtx [][]formulaArg
+	for _, row := range splitTokens(tokens[1:len(tokens)-1], isArgumentToken) {
+		if len(row) < 2 || !isFunctionStartToken(row[0]) || row[0].TValue != "ARRAYROW" {
+			return newErrorFormulaArg(formulaErrorVALUE, formulaErrorVALUE)
+		}
+		var mtxRow []formulaArg
+		for _, ele := range splitTokens(row[1:len(row)-1], isArgumentToken) {
+			negative := len(ele) == 2 && ele[0].TType == efp.TokenTypeOperatorPrefix && ele[0].TValue == "-"
+			if negative {
+				ele = ele[1:]
+			}
+			if len(ele) != 1 || !isOperand(ele[0]) {
+				return newErrorFormulaArg(formulaErrorVALUE, formulaErrorVALUE)
+			}
+			arg := tokenToFormulaArg(ele[0])
+			if negative {
+				if arg = arg.ToNumber(); arg.Type == ArgNumber {
+					arg.Number = -arg.Number
+				}
+			}
+			mtxRow = append(mtxRow, arg)
+		}
+		if len(mtx) > 0 && len(mtxRow) != len(mtx[0]) {
+			return newErrorFormulaArg(formulaErrorVALUE, formulaErrorVALUE)
+		}
+		mtx = append(mtx, mtxRow)
+	}
+	if len(mtx) == 1 && len(mtx[0]) == 1 {
+		return mtx[0][0]
+	}
+	return newMatrixFormulaArg(mtx)
+}
+
+// isNameToken determine if the token is a name or reference operand.
+func isNameToken(token efp.Token) bool {
+	return token.TType == efp.TokenTypeOperand && token.TSubType == efp.TokenSubTypeRange
+}
+
+// lambdaTokenName returns the upper case name of the function, LET name or
+// LAMBDA parameter token without the prefixes.
+func lambdaTokenName(token efp.Token) string {
+	return strings.TrimPrefix(strings.TrimPrefix(strings.ToUpper(token.TValue), "_XLFN."), "_XLPM.")
+}
+
+// matchStopToken returns the index of the stop token matching the function
+// or subexpression start token at the given index, returns -1 if not found.
+func matchStopToken(tokens []efp.Token, idx int) int {
+	var depth int
+	for i := idx; i < len(tokens); i++ {
+		switch tokens[i].TSubType {
+		case efp.TokenSubTypeStart:
+			depth++
+		case efp.TokenSubTypeStop:
+			if depth--; depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// splitTokens splits the tokens by the separator tokens at the top level.
+func splitTokens(tokens []efp.Token, sep func(token efp.Token) bool) [][]efp.Token {
+	var (
+		parts        [][]efp.Token
+		depth, start int
+	)
+	if len(tokens) == 0 {
+		return parts
+	}
+	for i, token := range tokens {
+		switch {
+		case token.TSubType == efp.TokenSubTypeStart:
+			depth++
+		case token.TSubType == efp.TokenSubTypeStop:
+			depth--
+		case depth == 0 && sep(token):
+			parts, start = append(parts, tokens[start:i]), i+1
+		}
+	}
+	return append(parts, tokens[start:])
+}
+
+// isArgumentToken determine if the token is the function arguments separator.
+func isArgumentToken(token efp.Token) bool {
+	return token.TType == efp.TokenTypeArgument
+}
+
+// isUnionToken determine if the token is the union operator.
+func isUnionToken(token efp.Token) bool {
+	return token.TType == efp.TokenTypeOperatorInfix && token.TSubType == efp.TokenSubTypeUnion
+}
+
+// wrapTokens returns the tokens enclosed in parentheses if there are more
+// than one token.
+func wrapTokens(tokens []efp.Token) []efp.Token {
+	if len(tokens) == 1 {
+		return tokens
+	}
+	wrapped := []efp.Token{{TValue: "(", TType: efp.TokenTypeSubexpression, TSubType: efp.TokenSubTypeStart}}
+	wrapped = append(wrapped, tokens...)
+	return append(wrapped, efp.Token{TValue: ")", TType: efp.TokenTypeSubexpression, TSubType: efp.TokenSubTypeStop})
+}
+
+// lambdaErrorTokens returns the tokens of the given error for the invalid or
+// uncalled LET and LAMBDA function.
+func lambdaErrorTokens(formulaError string) []efp.Token {
+	return []efp.Token{{TValue: formulaError, TType: efp.TokenTypeOperand, TSubType: efp.TokenSubTypeError}}
+}
+
+// isLambdaTokens determine if the tokens is a LAMBDA function.
+func isLambdaTokens(tokens []efp.Token) bool {
+	return len(tokens) > 0 && isFunctionStartToken(tokens[0]) && lambdaTokenName(tokens[0]) == "LAMBDA" &&
+		matchStopToken(tokens, 0) == len(tokens)-1
+}
+
+// substituteTokens replace the names in the tokens with the tokens of the
+// given values, the function call of the name which value is a LAMBDA
+// function will be replaced by the result of applying the arguments.
+func substituteTokens(tokens []efp.Token, names map[string][]efp.Token) []efp.Token {
+	var result []efp.Token
+	for i := 0; i < len(tokens); i++ {
+		token := tokens[i]
+		if isFunctionStartToken(token) && lambdaTokenName(token) == "LAMBDA" {
+			// the parameters of the LAMBDA function shadow the names
+			if stop := matchStopToken(tokens, i); stop != -1 {
+				parts, scope := splitTokens(tokens[i+1:stop], isArgumentToken), map[string][]efp.Token{}
+				for name, value := range names {
+					scope[name] = value
+				}
+				result = append(result, token)
+				for j, part := range parts {
+					if j == len(parts)-1 {
+						result = append(result, substituteTokens(part, scope)...)
+						break
+					}
+					if len(part) == 1 {
+						delete(scope, lambdaTokenName(part[0]))
+					}
+					result = append(append(result, part...), efp.Token{TValue: ",", TType: efp.TokenTypeArgument})
+				}
+				result, i = append(result, tokens[stop]), stop
+				continue
+			}
+		}
+		value, ok := names[lambdaTokenName(token)]
+		if ok && isNameToken(token) {
+			result = append(result, wrapTokens(value)...)
+			continue
+		}
+		if ok && isFunctionStartToken(token) && isLambdaTokens(value) {
+			if stop := matchStopToken(tokens, i); stop != -1 {
+				var args [][]efp.Token
+				for _, arg := range splitTokens(tokens[i+1:stop], isArgumentToken) {
+					args = append(args, substituteTokens(arg, names))
+				}
+				result, i = append(result, applyLambdaTokens(value, args)...), stop
+				continue
+			}
+		}
+		result = append(result, token)
+	}
+	return result
+}
+
+// applyLambdaTokens returns the tokens of the LAMBDA function calculation
+// which parameters has been replaced by the given arguments.
+func applyLambdaTokens(lambda []efp.Token, args [][]efp.Token) []efp.Token {
+	parts := splitTokens(lambda[1:len(lambda)-1], isArgumentToken)
+	if len(parts) == 0 || len(parts)-1 != len(args) {
+		return lambdaErrorTokens(formulaErrorVALUE)
+	}
+	names := map[string][]efp.Token{}
+	for i, param := range parts[:len(parts)-1] {
+		if len(param) != 1 || !isNameToken(param[0]) || len(args[i]) == 0 {
+			return lambdaErrorTokens(formulaErrorVALUE)
+		}
+		names[lambdaTokenName(param[0])] = args[i]
+	}
+	if len(parts[len(parts)-1]) == 0 {
+		return lambdaErrorTokens(formulaErrorVALUE)
+	}
+	return wrapTokens(substituteTokens(parts[len(parts)-1], names))
+}
+
+// expandLETTokens returns the tokens of the LET function calculation which
+// names has been replaced by the corresponding values.
+func expandLETTokens(args []efp.Token) []efp.Token {
+	parts := splitTokens(args, isArgumentToken)
+	if len(parts) < 3 || len(parts)%2 == 0 {
+		return lambdaErrorTokens(formulaErrorVALUE)
+	}
+	names := map[string][]efp.Token{}
+	for i := 0; i < len(parts)-1; i += 2 {
+		if len(parts[i]) != 1 || !isNameToken(parts[i][0]) || len(parts[i+1]) == 0 {
+			return lambdaErrorTokens(formulaErrorVALUE)
+		}
+		names[lambdaTokenName(parts[i][0])] = substituteTokens(parts[i+1], names)
+	}
+	if len(parts[len(parts)-1]) == 0 {
+		return lambdaErrorTokens(formulaErrorVALUE)
+	}
+	return wrapTokens(substituteTokens(parts[len(parts)-1], names))
+}
+
+// getLambdaDefinedName returns the tokens of the LAMBDA function by given
+// defined name and worksheet name, returns nil if the defined name doesn't
+// exist or doesn't refer to a LAMBDA function.
+func (f *File) getLambdaDefinedName(name, sheet string) []efp.Token {
+	if reflect.ValueOf(&formulaFuncs{}).MethodByName(strings.NewReplacer(
+		"_xlfn.", "", ".", "dot").Replace(name)).IsValid() {
+		return nil
+	}
+	refTo := f.getDefinedNameRefTo(name, sheet)
+	if refTo == "" {
+		return nil
+	}
+	ps := efp.ExcelParser()
+	if tokens := ps.Parse(strings.TrimPrefix(refTo, "=")); isLambdaTokens(tokens) {
+		return tokens
+	}
+	return nil
+}
+
+// expandLambdaTokens provides a function to expand the LET functions, the
+// LAMBDA function calls and the calls of the defined names which refer to
+// LAMBDA functions in the tokens by given worksheet name. The names and
+// parameters will be replaced by the corresponding values and arguments, and
+// the innermost function will be expanded first.
+func (f *File) expandLambdaTokens(sheet string, tokens []efp.Token) []efp.Token {
+	for depth := 0; depth < 256; depth++ {
+		expanded := false
+		for i := len(tokens) - 1; i >= 0 && !expanded; i-- {
+			if !isFunctionStartToken(tokens[i]) {
+				continue
+			}
+			stop := matchStopToken(tokens, i)
+			if stop == -1 {
+				continue
+			}
+			var result []efp.Token
+			switch name := lambdaTokenName(tokens[i]); name {
+			case "LET":
+				result = expandLETTokens(tokens[i+1 : stop])
+			case "LAMBDA":
+				if stop+1 >= len(tokens) || !isBeginParenthesesToken(tokens[stop+1]) {
+					continue
+				}
+				callStop := matchStopToken(tokens, stop+1)
+				if callStop == -1 {
+					continue
+				}
+				result = applyLambdaTokens(tokens[i:stop+1], splitTokens(tokens[stop+2:callStop], isUnionToken))
+				stop = callStop
+			default:
+				lambda := f.getLambdaDefinedName(tokens[i].TValue, sheet)
+				if lambda == nil {
+					continue
+				}
+				result = applyLambdaTokens(lambda, splitTokens(tokens[i+1:stop], isArgumentToken))
+			}
+			result = append(append(append([]efp.Token{}, tokens[:i]...), result...), tokens[stop+1:]...)
+			tokens, expanded = result, true
+		}
+		if !expanded {
+			break
+		}
+	}
+	// the LAMBDA function without calling returns the #CALC! error
+	for i := len(tokens) - 1; i >= 0; i-- {
+		if isFunctionStartToken(tokens[i]) && lambdaTokenName(tokens[i]) == "LAMBDA" {
+			if stop := matchStopToken(tokens, i); stop != -1 {
+				tokens = append(append(append([]efp.Token{}, tokens[:i]...), lambdaErrorTokens(formulaErrorCALC)...), tokens[stop+1:]...)
+			}
+		}
+	}
+	return tokens
 }
 
 // tokenToFormulaArg create a formula argument by given token.
@@ -1404,6 +3087,8 @@ func tokenToFormulaArg(token efp.Token) formulaArg {
 	case efp.TokenSubTypeNumber:
 		num, _ := strconv.ParseFloat(token.TValue, 64)
 		return newNumberFormulaArg(num)
+	case efp.TokenSubTypeError:
+		return newErrorFormulaArg(token.TValue, token.TValue)
 	default:
 		return newStringFormulaArg(token.TValue)
 	}
@@ -1561,6 +3246,236 @@ func (f *File) parseReference(ctx *calcContext, sheet, reference string) (formul
 	return f.rangeResolver(ctx, cellRefs, cellRanges)
 }
 
+// parseStructuredReferences provides a function to convert the structured
+// references of the tables in the formula to the cell references by given
+// worksheet name, cell reference and formula. The text and quoted worksheet
+// names in the formula will be kept.
+func (f *File) parseStructuredReferences(sheet, cell, formula string) (string, error) {
+	if !strings.Contains(formula, "[") {
+		return formula, nil
+	}
+	var result strings.Builder
+	for len(formula) > 0 {
+		idx := strings.IndexAny(formula, `"'`)
+		if idx == -1 {
+			idx = len(formula)
+		}
+		expr, err := f.replaceStructuredReferences(sheet, cell, formula[:idx])
+		if err != nil {
+			return formula, err
+		}
+		result.WriteString(expr)
+		if formula = formula[idx:]; len(formula) == 0 {
+			break
+		}
+		end := strings.IndexByte(formula[1:], formula[0]) + 2
+		if end == 1 {
+			end = len(formula)
+		}
+		result.WriteString(formula[:end])
+		formula = formula[end:]
+	}
+	return result.String(), nil
+}
+
+// replaceStructuredReferences provides a function to replace the structured
+// references with the cell references by given worksheet name, cell
+// reference and the expression without text and quoted worksheet names.
+func (f *File) replaceStructuredReferences(sheet, cell, expr string) (string, error) {
+	var (
+		result strings.Builder
+		last   int
+	)
+	for _, match := range structuredRefExp.FindAllStringSubmatchIndex(expr, -1) {
+		name, start := "", match[0]
+		if match[2] != -1 {
+			name, start = expr[match[2]:match[3]], match[3]
+		}
+		if next := match[1]; name == "" && next < len(expr) &&
+			(expr[next] == '!' || expr[next] == '_' || unicode.IsLetter(rune(expr[next])) || unicode.IsDigit(rune(expr[next]))) {
+			continue // external workbook reference, such as [1]Sheet1!A1
+		}
+		ref, err := f.getStructuredReferenceRange(sheet, cell, name, expr[start+1:match[1]-1])
+		if err != nil {
+			return expr, err
+		}
+		result.WriteString(expr[last:match[0]])
+		result.WriteString(ref)
+		last = match[1]
+	}
+	result.WriteString(expr[last:])
+	return result.String(), nil
+}
+
+// getStructuredReferenceRange provides a function to get the cell reference
+// of the structured reference by given worksheet name, cell reference, table
+// name and the specifier of the structured reference. The table contains
+// the cell will be used if the table name is empty.
+func (f *File) getStructuredReferenceRange(sheet, cell, name, spec string) (string, error) {
+	tbl, tableSheet, err := f.getStructuredReferenceTable(sheet, cell, name)
+	if err != nil {
+		return "", err
+	}
+	specifiers, columns, err := parseStructuredReferenceSpec(spec)
+	if err != nil {
+		return "", err
+	}
+	coordinates, err := rangeRefToCoordinates(tbl.Ref)
+	if err != nil {
+		return "", errors.New(formulaErrorREF)
+	}
+	_ = sortCoordinates(coordinates)
+	headerRows := 1
+	if tbl.HeaderRowCount != nil {
+		headerRows = *tbl.HeaderRowCount
+	}
+	dataRows := []int{coordinates[1] + headerRows, coordinates[3] - tbl.TotalsRowCount}
+	rows := map[string][]int{
+		"#all":     {coordinates[1], coordinates[3]},
+		"#data":    dataRows,
+		"#headers": {coordinates[1], coordinates[1] + headerRows - 1},
+		"#totals":  {coordinates[3] - tbl.TotalsRowCount + 1, coordinates[3]},
+	}
+	if len(specifiers) == 0 {
+		specifiers = append(specifiers, "#data")
+	}
+	fromRow, toRow := TotalRows, 0
+	for _, specifier := range specifiers {
+		rng := rows[specifier]
+		if specifier == "#this row" {
+			_, row, _ := CellNameToCoordinates(cell)
+			if row < dataRows[0] || row > dataRows[1] {
+				return "", errors.New(formulaErrorVALUE)
+			}
+			rng = []int{row, row}
+		}
+		if rng[0] > rng[1] {
+			return "", errors.New(formulaErrorREF)
+		}
+		if rng[0] < fromRow {
+			fromRow = rng[0]
+		}
+		if rng[1] > toRow {
+			toRow = rng[1]
+		}
+	}
+	fromCol, toCol := coordinates[0], coordinates[2]
+	for i, column := range columns {
+		col := -1
+		if tbl.TableColumns != nil {
+			for idx, tableColumn := range tbl.TableColumns.TableColumn {
+				if strings.EqualFold(tableColumn.Name, column) {
+					col = coordinates[0] + idx
+				}
+			}
+		}
+		if col == -1 {
+			return "", errors.New(formulaErrorREF)
+		}
+		if i == 0 {
+			fromCol, toCol = col, col
+			continue
+		}
+		if col < fromCol {
+			fromCol = col
+		}
+		if col > toCol {
+			toCol = col
+		}
+	}
+	ref, err := f.coordinatesToRangeRef([]int{fromCol, fromRow, toCol, toRow}, true)
+	if fromCol == toCol && fromRow == toRow {
+		ref, err = CoordinatesToCellName(fromCol, fromRow, true)
+	}
+	return fmt.Sprintf("'%s'!%s", strings.ReplaceAll(tableSheet, "'", "''"), ref), err
+}
+
+// getStructuredReferenceTable provides a function to get the table and the
+// worksheet name which contains the table by given worksheet name, cell
+// reference and table name. The table contains the cell will be returned if
+// the table name is empty.
+func (f *File) getStructuredReferenceTable(sheet, cell, name string) (*xlsxTable, string, error) {
+	col, row, _ := CellNameToCoordinates(cell)
+	for _, sheetName := range f.GetSheetList() {
+		if name == "" && !strings.EqualFold(sheetName, sheet) {
+			continue
+		}
+		tables, err := f.GetTables(sheetName)
+		if err != nil {
+			return nil, sheetName, err
+		}
+		for _, table := range tables {
+			if name != "" && !strings.EqualFold(table.Name, name) {
+				continue
+			}
+			content, ok := f.Pkg.Load(table.tableXML)
+			if !ok {
+				continue
+			}
+			var tbl xlsxTable
+			if err = f.xmlNewDecoder(bytes.NewReader(namespaceStrictToTransitional(content.([]byte)))).
+				Decode(&tbl); err != nil && err != io.EOF {
+				return nil, sheetName, err
+			}
+			if name == "" {
+				coordinates, err := rangeRefToCoordinates(tbl.Ref)
+				if err != nil || sortCoordinates(coordinates) != nil || !cellInRange([]int{col, row}, coordinates) {
+					continue
+				}
+			}
+			return &tbl, sheetName, nil
+		}
+	}
+	return nil, sheet, errors.New(formulaErrorREF)
+}
+
+// parseStructuredReferenceSpec provides a function to parse the specifier of
+// the structured reference, and returns the lowercase special item
+// specifiers and the column names, such as [#Headers],[Sales]:[Cost].
+func parseStructuredReferenceSpec(spec string) ([]string, []string, error) {
+	var specifiers, columns []string
+	add := func(item string) error {
+		if !strings.HasPrefix(item, "#") {
+			columns = append(columns, item)
+			return nil
+		}
+		switch item = strings.ToLower(item); item {
+		case "#all", "#data", "#headers", "#totals", "#this row":
+			specifiers = append(specifiers, item)
+			return nil
+		}
+		return errors.New(formulaErrorREF)
+	}
+	if strings.HasPrefix(spec, "@") {
+		specifiers, spec = append(specifiers, "#this row"), spec[1:]
+	}
+	if !strings.HasPrefix(spec, "[") {
+		if spec == "" {
+			return specifiers, columns, nil
+		}
+		return specifiers, columns, add(spec)
+	}
+	for len(spec) > 0 {
+		end := strings.Index(spec, "]")
+		if !strings.HasPrefix(spec, "[") || end == -1 {
+			return specifiers, columns, errors.New(formulaErrorREF)
+		}
+		if err := add(spec[1:end]); err != nil {
+			return specifiers, columns, err
+		}
+		if spec = spec[end+1:]; len(spec) > 0 {
+			if spec[0] != ',' && spec[0] != ':' {
+				return specifiers, columns, errors.New(formulaErrorREF)
+			}
+			spec = spec[1:]
+		}
+	}
+	if len(columns) > 2 {
+		return specifiers, columns, errors.New(formulaErrorREF)
+	}
+	return specifiers, columns, nil
+}
+
 // prepareValueRange prepare value range.
 func prepareValueRange(cr cellRange, valueRange []int) {
 	if cr.From.Row < valueRange[0] || valueRange[0] == 0 {
@@ -1601,6 +3516,12 @@ func (f *File) cellResolver(ctx *calcContext, sheet, cell string) (formulaArg, e
 		err   error
 	)
 	ref := fmt.Sprintf("%s!%s", sheet, cell)
+	if arg, ok := ctx.values[ref]; ok {
+		return arg, err
+	}
+	if _, _, ok := splitExternalSheetName(sheet); ok {
+		return f.externalCellResolver(sheet, cell)
+	}
 	if formula, _ := f.GetCellFormula(sheet, cell); len(formula) != 0 {
 		ctx.mu.Lock()
 		if ctx.entry != ref {
@@ -3687,15 +5608,82 @@ func (fn *formulaFuncs) AGGREGATE(argsList *list.List) formulaArg {
 	if opts = argsList.Front().Next().Value.(formulaArg).ToNumber(); opts.Type != ArgNumber {
 		return opts
 	}
-	// TODO: apply option argument values to be ignored during the calculation
 	if int(opts.Number) < 0 || int(opts.Number) > 7 {
 		return newErrorFormulaArg(formulaErrorVALUE, "AGGREGATE has invalid options")
 	}
-	subArgList := list.New().Init()
-	for arg := argsList.Front().Next().Next(); arg != nil; arg = arg.Next() {
-		subArgList.PushBack(arg.Value.(formulaArg))
+	subArgList := list.New().Init()
+	for arg := argsList.Front().Next().Next(); arg != nil; arg = arg.Next() {
+		if token := fn.aggregateArg(arg.Value.(formulaArg), int(opts.Number)); token.Type != ArgEmpty {
+			subArgList.PushBack(token)
+		}
+	}
+	return subFn(subArgList)
+}
+
+// aggregateArg returns the formula argument which values to be ignored by the
+// options of the AGGREGATE function have been replaced by the empty values.
+// The options are:
+//
+//	0 - Ignore nested SUBTOTAL and AGGREGATE functions
+//	1 - Ignore hidden rows, nested SUBTOTAL and AGGREGATE functions
+//	2 - Ignore error values, nested SUBTOTAL and AGGREGATE functions
+//	3 - Ignore hidden rows, error values, nested SUBTOTAL and AGGREGATE functions
+//	4 - Ignore nothing
+//	5 - Ignore hidden rows
+//	6 - Ignore error values
+//	7 - Ignore hidden rows and error values
+func (fn *formulaFuncs) aggregateArg(arg formulaArg, opts int) formulaArg {
+	ignoreNested, ignoreHidden, ignoreErrors := opts < 4, opts%2 == 1, opts%4 > 1
+	if arg.Type == ArgError && ignoreErrors {
+		return newEmptyFormulaArg()
+	}
+	if arg.Type != ArgMatrix {
+		return arg
+	}
+	var (
+		sheet    = fn.sheet
+		col, row int
+		hasRef   = arg.cellRanges != nil && arg.cellRanges.Len() == 1
+		hidden   = map[int]bool{}
+		mtx      = make([][]formulaArg, len(arg.Matrix))
+	)
+	if hasRef {
+		cr := arg.cellRanges.Front().Value.(cellRange)
+		if col, row = cr.From.Col, cr.From.Row; cr.To.Col < col {
+			col = cr.To.Col
+		}
+		if cr.To.Row < row {
+			row = cr.To.Row
+		}
+		if cr.From.Sheet != "" {
+			sheet = cr.From.Sheet
+		}
+	}
+	for r, values := range arg.Matrix {
+		mtx[r] = make([]formulaArg, len(values))
+		if hasRef && ignoreHidden {
+			if _, ok := hidden[row+r]; !ok {
+				visible, _ := fn.f.GetRowVisible(sheet, row+r)
+				hidden[row+r] = !visible
+			}
+		}
+		for c, value := range values {
+			mtx[r][c] = value
+			if (ignoreErrors && value.Type == ArgError) || (hasRef && hidden[row+r]) {
+				mtx[r][c] = newEmptyFormulaArg()
+				continue
+			}
+			if hasRef && ignoreNested {
+				cell, _ := CoordinatesToCellName(col+c, row+r)
+				formula, _ := fn.f.GetCellFormula(sheet, cell)
+				if formula = strings.ToUpper(formula); strings.Contains(formula, "SUBTOTAL(") ||
+					strings.Contains(formula, "AGGREGATE(") {
+					mtx[r][c] = newEmptyFormulaArg()
+				}
+			}
+		}
 	}
-	return subFn(subArgList)
+	return newMatrixFormulaArg(mtx)
 }
 
 // ARABIC function converts a Roman numeral into an Arabic numeral. The syntax
@@ -5894,13 +7882,15 @@ func (fn *formulaFuncs) SUMIFS(argsList *list.List) formulaArg {
 	return newNumberFormulaArg(sum)
 }
 
-// sumproduct is an implementation of the formula function SUMPRODUCT.
+// sumproduct is an implementation of the formula function SUMPRODUCT. The
+// arrays must have the same dimensions, and the non-numeric entries of the
+// arrays will be treated as zeros.
 func (fn *formulaFuncs) sumproduct(argsList *list.List) formulaArg {
 	var (
-		argType ArgType
-		n       int
-		res     []float64
-		sum     float64
+		argType    ArgType
+		rows, cols int
+		res        [][]float64
+		sum        float64
 	)
 	for arg := argsList.Front(); arg != nil; arg = arg.Next() {
 		token := arg.Value.(formulaArg)
@@ -5918,28 +7908,43 @@ func (fn *formulaFuncs) sumproduct(argsList *list.List) formulaArg {
 			}
 			return newErrorFormulaArg(formulaErrorVALUE, formulaErrorVALUE)
 		case ArgMatrix:
-			args := token.ToList()
 			if res == nil {
-				n = len(args)
-				res = make([]float64, n)
-				for i := range res {
-					res[i] = 1.0
+				rows, cols = len(token.Matrix), 0
+				if rows > 0 {
+					cols = len(token.Matrix[0])
+				}
+				res = make([][]float64, rows)
+				for r := range res {
+					res[r] = make([]float64, cols)
+					for c := range res[r] {
+						res[r][c] = 1.0
+					}
 				}
 			}
-			if len(args) != n {
+			if len(token.Matrix) != rows {
 				return newErrorFormulaArg(formulaErrorVALUE, formulaErrorVALUE)
 			}
-			for i, value := range args {
-				num := value.ToNumber()
-				if num.Type != ArgNumber && value.Value() != "" {
+			for r, row := range token.Matrix {
+				if len(row) != cols {
 					return newErrorFormulaArg(formulaErrorVALUE, formulaErrorVALUE)
 				}
-				res[i] = res[i] * num.Number
+				for c, value := range row {
+					if value.Type == ArgError {
+						return value
+					}
+					if value.Type != ArgNumber || value.Boolean {
+						res[r][c] = 0
+						continue
+					}
+					res[r][c] *= value.Number
+				}
 			}
 		}
 	}
-	for _, r := range res {
-		sum += r
+	for _, row := range res {
+		for _, r := range row {
+			sum += r
+		}
 	}
 	return newNumberFormulaArg(sum)
 }
@@ -8056,6 +10061,209 @@ func (fn *formulaFuncs) FORECAST(argsList *list.List) formulaArg {
 	return fn.pearsonProduct("FORECAST", 3, argsList)
 }
 
+// prepareETSArgs checks and prepares the historical values with the constant
+// step of the timeline for the FORECAST.ETS function, the missing points will
+// be completed and the values with the same time will be aggregated.
+func (fn *formulaFuncs) prepareETSArgs(argsList *list.List) (series []float64, start, step float64, seasonality int, errArg formulaArg) {
+	values, timeline := argsList.Front().Next().Value.(formulaArg).ToList(), argsList.Front().Next().Next().Value.(formulaArg).ToList()
+	if len(values) != len(timeline) {
+		errArg = newErrorFormulaArg(formulaErrorNA, formulaErrorNA)
+		return
+	}
+	opts := []float64{1, 1, 1}
+	for i, arg := 0, argsList.Front().Next().Next().Next(); arg != nil; i, arg = i+1, arg.Next() {
+		if arg.Value.(formulaArg).Type == ArgEmpty {
+			continue
+		}
+		num := arg.Value.(formulaArg).ToNumber()
+		if num.Type != ArgNumber {
+			errArg = num
+			return
+		}
+		opts[i] = math.Trunc(num.Number)
+	}
+	seasonality = int(opts[0])
+	aggregate, ok := map[int]func(argsList *list.List) formulaArg{
+		1: fn.AVERAGE, 2: fn.COUNT, 3: fn.COUNTA, 4: fn.MAX, 5: fn.MEDIAN, 6: fn.MIN, 7: fn.SUM,
+	}[int(opts[2])]
+	if seasonality < 0 || seasonality > 8760 || (opts[1] != 0 && opts[1] != 1) || !ok {
+		errArg = newErrorFormulaArg(formulaErrorNUM, formulaErrorNUM)
+		return
+	}
+	points := map[float64]*list.List{}
+	var times []float64
+	for i, t := range timeline {
+		if t.Type != ArgNumber {
+			errArg = newErrorFormulaArg(formulaErrorVALUE, formulaErrorVALUE)
+			return
+		}
+		if values[i].Type == ArgEmpty {
+			continue
+		}
+		if values[i].Type != ArgNumber {
+			errArg = newErrorFormulaArg(formulaErrorVALUE, formulaErrorVALUE)
+			return
+		}
+		if _, ok := points[t.Number]; !ok {
+			points[t.Number] = list.New()
+			times = append(times, t.Number)
+		}
+		points[t.Number].PushBack(values[i])
+	}
+	if len(times) < 2 {
+		errArg = newErrorFormulaArg(formulaErrorNUM, formulaErrorNUM)
+		return
+	}
+	sort.Float64s(times)
+	for i := 1; i < len(times); i++ {
+		if diff := times[i] - times[i-1]; step == 0 || diff < step {
+			step = diff
+		}
+	}
+	start = times[0]
+	series = make([]float64, int(math.Round((times[len(times)-1]-start)/step))+1)
+	filled := make([]bool, len(series))
+	for _, t := range times {
+		idx := (t - start) / step
+		if math.Abs(idx-math.Round(idx)) > 1e-9 {
+			errArg = newErrorFormulaArg(formulaErrorNUM, formulaErrorNUM)
+			return
+		}
+		series[int(math.Round(idx))], filled[int(math.Round(idx))] = aggregate(points[t]).Number, true
+	}
+	// complete the missing points by the linear interpolation or zeros
+	for i, prev := 0, 0; opts[1] == 1 && i < len(series); i++ {
+		if filled[i] {
+			prev = i
+			continue
+		}
+		next := i + 1
+		for !filled[next] {
+			next++
+		}
+		series[i] = series[prev] + (series[next]-series[prev])*float64(i-prev)/float64(next-prev)
+	}
+	if seasonality == 1 {
+		seasonality = detectETSSeasonality(series)
+	}
+	if seasonality > len(series)/2 {
+		errArg = newErrorFormulaArg(formulaErrorNUM, formulaErrorNUM)
+	}
+	return
+}
+
+// detectETSSeasonality detects the length of the seasonal pattern of the
+// series by the autocorrelation of the detrended series, returns 0 if there
+// is no seasonality.
+func detectETSSeasonality(series []float64) int {
+	n := len(series)
+	var sumX, sumY, sumXY, sumXX float64
+	for i, v := range series {
+		sumX, sumY, sumXY, sumXX = sumX+float64(i), sumY+v, sumXY+float64(i)*v, sumXX+float64(i*i)
+	}
+	slope := (float64(n)*sumXY - sumX*sumY) / (float64(n)*sumXX - sumX*sumX)
+	intercept := (sumY - slope*sumX) / float64(n)
+	detrended, variance := make([]float64, n), 0.0
+	for i, v := range series {
+		detrended[i] = v - intercept - slope*float64(i)
+		variance += detrended[i] * detrended[i]
+	}
+	if variance == 0 {
+		return 0
+	}
+	var seasonality int
+	for lag, best := 2, 0.5; lag <= n/2; lag++ {
+		var acf float64
+		for i := lag; i < n; i++ {
+			acf += detrended[i] * detrended[i-lag]
+		}
+		if acf = acf / float64(n-lag) / (variance / float64(n)); acf > best+1e-9 {
+			seasonality, best = lag, acf
+		}
+	}
+	return seasonality
+}
+
+// calcETS fits the additive Holt-Winters model of the series with the given
+// smoothing parameters and seasonality, returns the sum of squared one step
+// ahead errors, the level, trend and seasonal indices at the end of the
+// series.
+func calcETS(series []float64, alpha, beta, gamma float64, seasonality int) (sse, level, trend float64, season []float64) {
+	m, begin := seasonality, 1
+	if m == 0 {
+		level, trend, season = series[0], series[1]-series[0], []float64{0}
+	} else {
+		var mean1, mean2 float64
+		for i := 0; i < m; i++ {
+			mean1, mean2 = mean1+series[i]/float64(m), mean2+series[m+i]/float64(m)
+		}
+		trend, season, begin = (mean2-mean1)/float64(m), make([]float64, m), m
+		for i := 0; i < m; i++ {
+			season[i] = series[i] - (mean1 + (float64(i)-float64(m-1)/2)*trend)
+		}
+		level = mean1 + float64(m-1)/2*trend
+	}
+	for t := begin; t < len(series); t++ {
+		s := season[t%len(season)]
+		err := series[t] - (level + trend + s)
+		sse += err * err
+		prevLevel := level
+		level = alpha*(series[t]-s) + (1-alpha)*(level+trend)
+		trend = beta*(level-prevLevel) + (1-beta)*trend
+		if m > 0 {
+			season[t%m] = gamma*(series[t]-level) + (1-gamma)*s
+		}
+	}
+	return
+}
+
+// FORECASTdotETS function predicts a future value based on the existing
+// historical values by using the additive error, additive trend and additive
+// seasonality (AAA) version of the Exponential Triple Smoothing algorithm.
+// The smoothing parameters are estimated by minimizing the sum of squared
+// errors. The syntax of the function is:
+//
+//	FORECAST.ETS(target_date,values,timeline,[seasonality],[data_completion],[aggregation])
+func (fn *formulaFuncs) FORECASTdotETS(argsList *list.List) formulaArg {
+	if argsList.Len() < 3 {
+		return newErrorFormulaArg(formulaErrorVALUE, "FORECAST.ETS requires at least 3 arguments")
+	}
+	if argsList.Len() > 6 {
+		return newErrorFormulaArg(formulaErrorVALUE, "FORECAST.ETS allows at most 6 arguments")
+	}
+	target := argsList.Front().Value.(formulaArg).ToNumber()
+	if target.Type != ArgNumber {
+		return target
+	}
+	series, start, step, seasonality, errArg := fn.prepareETSArgs(argsList)
+	if errArg.Type == ArgError {
+		return errArg
+	}
+	h := (target.Number-start)/step - float64(len(series)-1)
+	if h < 0 {
+		return newErrorFormulaArg(formulaErrorNUM, formulaErrorNUM)
+	}
+	var best []float64
+	for alpha := 0.1; alpha < 1; alpha += 0.1 {
+		for beta := 0.1; beta < 1; beta += 0.1 {
+			for gamma := 0.1; gamma < 1; gamma += 0.1 {
+				if sse, _, _, _ := calcETS(series, alpha, beta, gamma, seasonality); best == nil || sse < best[0] {
+					best = []float64{sse, alpha, beta, gamma}
+				}
+				if seasonality == 0 {
+					break
+				}
+			}
+		}
+	}
+	_, level, trend, season := calcETS(series, best[1], best[2], best[3], seasonality)
+	idx := (len(series) - 1 + int(math.Ceil(h))) % len(season)
+	if seasonality == 0 {
+		idx = 0
+	}
+	return newNumberFormulaArg(level + h*trend + season[idx])
+}
+
 // FORECASTdotLINEAR function predicts a future point on a linear trend line
 // fitted to a supplied set of x- and y- values. The syntax of the function is:
 //
@@ -9475,6 +11683,216 @@ func (fn *formulaFuncs) FTEST(argsList *list.List) formulaArg {
 	return fn.FdotTEST(argsList)
 }
 
+// invertMatrix calculates the inverse of the square matrix by the
+// Gauss-Jordan elimination with partial pivoting, returns false if the matrix
+// is singular.
+func invertMatrix(mtx [][]float64) ([][]float64, bool) {
+	n := len(mtx)
+	a, inv := make([][]float64, n), make([][]float64, n)
+	for i := range mtx {
+		a[i], inv[i] = append([]float64{}, mtx[i]...), make([]float64, n)
+		inv[i][i] = 1
+	}
+	for col := 0; col < n; col++ {
+		pivot := col
+		for row := col + 1; row < n; row++ {
+			if math.Abs(a[row][col]) > math.Abs(a[pivot][col]) {
+				pivot = row
+			}
+		}
+		if math.Abs(a[pivot][col]) < 1e-12 {
+			return nil, false
+		}
+		a[col], a[pivot] = a[pivot], a[col]
+		inv[col], inv[pivot] = inv[pivot], inv[col]
+		for j, div := 0, a[col][col]; j < n; j++ {
+			a[col][j] /= div
+			inv[col][j] /= div
+		}
+		for row := 0; row < n; row++ {
+			if row == col || a[row][col] == 0 {
+				continue
+			}
+			for j, factor := 0, a[row][col]; j < n; j++ {
+				a[row][j] -= factor * a[col][j]
+				inv[row][j] -= factor * inv[col][j]
+			}
+		}
+	}
+	return inv, true
+}
+
+// prepareLinestArgs checks and prepares the known y's and the variables of
+// the known x's for the LINEST function.
+func prepareLinestArgs(argsList *list.List) (y []float64, x [][]float64, errArg formulaArg) {
+	mtxY, mtxX := argsList.Front().Value.(formulaArg).ToMatrix(), [][]formulaArg{}
+	if argsList.Len() > 1 && argsList.Front().Next().Value.(formulaArg).Type != ArgEmpty {
+		mtxX = argsList.Front().Next().Value.(formulaArg).ToMatrix()
+	}
+	for _, row := range mtxY {
+		for _, cell := range row {
+			if cell.Type != ArgNumber {
+				return nil, nil, newErrorFormulaArg(formulaErrorVALUE, formulaErrorVALUE)
+			}
+			y = append(y, cell.Number)
+		}
+	}
+	rowsY, colsY := len(mtxY), len(mtxY[0])
+	if len(mtxX) == 0 {
+		variable := make([]float64, len(y))
+		for i := range variable {
+			variable[i] = float64(i + 1)
+		}
+		return y, [][]float64{variable}, newEmptyFormulaArg()
+	}
+	rowsX, colsX := len(mtxX), len(mtxX[0])
+	for r, row := range mtxX {
+		if len(row) != colsX {
+			return nil, nil, newErrorFormulaArg(formulaErrorREF, formulaErrorREF)
+		}
+		for c, cell := range row {
+			if cell.Type != ArgNumber {
+				return nil, nil, newErrorFormulaArg(formulaErrorVALUE, formulaErrorVALUE)
+			}
+			switch {
+			case rowsX == rowsY && colsX == colsY:
+				if r == 0 && c == 0 {
+					x = [][]float64{{}}
+				}
+				x[0] = append(x[0], cell.Number)
+			case colsY == 1 && rowsX == rowsY:
+				if r == 0 {
+					x = append(x, make([]float64, rowsX))
+				}
+				x[c][r] = cell.Number
+			case rowsY == 1 && colsX == colsY:
+				if c == 0 {
+					x = append(x, make([]float64, colsX))
+				}
+				x[r][c] = cell.Number
+			default:
+				return nil, nil, newErrorFormulaArg(formulaErrorREF, formulaErrorREF)
+			}
+		}
+	}
+	return y, x, newEmptyFormulaArg()
+}
+
+// LINEST function calculates the statistics for a straight line that best
+// fits the supplied data by using the least squares method, and returns an
+// array that describes the line. The syntax of the function is:
+//
+//	LINEST(known_y's,[known_x's],[const],[stats])
+func (fn *formulaFuncs) LINEST(argsList *list.List) formulaArg {
+	if argsList.Len() < 1 {
+		return newErrorFormulaArg(formulaErrorVALUE, "LINEST requires at least 1 argument")
+	}
+	if argsList.Len() > 4 {
+		return newErrorFormulaArg(formulaErrorVALUE, "LINEST allows at most 4 arguments")
+	}
+	bConst, bStats := newBoolFormulaArg(true), newBoolFormulaArg(false)
+	if argsList.Len() > 2 && argsList.Front().Next().Next().Value.(formulaArg).Type != ArgEmpty {
+		if bConst = argsList.Front().Next().Next().Value.(formulaArg).ToBool(); bConst.Type != ArgNumber {
+			return bConst
+		}
+	}
+	if argsList.Len() > 3 && argsList.Back().Value.(formulaArg).Type != ArgEmpty {
+		if bStats = argsList.Back().Value.(formulaArg).ToBool(); bStats.Type != ArgNumber {
+			return bStats
+		}
+	}
+	y, x, errArg := prepareLinestArgs(argsList)
+	if errArg.Type == ArgError {
+		return errArg
+	}
+	n, k := len(y), len(x)
+	if n < k+int(bConst.Number) {
+		return newErrorFormulaArg(formulaErrorNUM, formulaErrorNUM)
+	}
+	// center the variables by the means for the regression with constant
+	meanX, meanY := make([]float64, k), 0.0
+	if bConst.Number == 1 {
+		for i := 0; i < n; i++ {
+			for j := 0; j < k; j++ {
+				meanX[j] += x[j][i] / float64(n)
+			}
+			meanY += y[i] / float64(n)
+		}
+	}
+	xtx, xty := getNewMatrix(k, k), make([]float64, k)
+	for i := 0; i < k; i++ {
+		for j := 0; j < k; j++ {
+			for r := 0; r < n; r++ {
+				xtx[i][j] += (x[i][r] - meanX[i]) * (x[j][r] - meanX[j])
+			}
+		}
+		for r := 0; r < n; r++ {
+			xty[i] += (x[i][r] - meanX[i]) * (y[r] - meanY)
+		}
+	}
+	inv, ok := invertMatrix(xtx)
+	if !ok {
+		return newErrorFormulaArg(formulaErrorNUM, formulaErrorNUM)
+	}
+	coef, intercept := make([]float64, k), meanY
+	for i := 0; i < k; i++ {
+		for j := 0; j < k; j++ {
+			coef[i] += inv[i][j] * xty[j]
+		}
+		intercept -= coef[i] * meanX[i]
+	}
+	na := newErrorFormulaArg(formulaErrorNA, formulaErrorNA)
+	result := [][]formulaArg{make([]formulaArg, k+1)}
+	for j := 0; j < k; j++ {
+		result[0][k-1-j] = newNumberFormulaArg(coef[j])
+	}
+	result[0][k] = newNumberFormulaArg(intercept)
+	if bStats.Number != 1 {
+		return newMatrixFormulaArg(result)
+	}
+	var ssResid, ssTotal float64
+	for r := 0; r < n; r++ {
+		estimate := intercept
+		for j := 0; j < k; j++ {
+			estimate += x[j][r] * coef[j]
+		}
+		ssResid += (y[r] - estimate) * (y[r] - estimate)
+		ssTotal += (y[r] - meanY) * (y[r] - meanY)
+	}
+	df := n - k - int(bConst.Number)
+	ssReg := ssTotal - ssResid
+	stats := func(v float64, ok bool) formulaArg {
+		if !ok || math.IsNaN(v) || math.IsInf(v, 0) {
+			return newErrorFormulaArg(formulaErrorNUM, formulaErrorNUM)
+		}
+		return newNumberFormulaArg(v)
+	}
+	for r := 1; r < 5; r++ {
+		result = append(result, make([]formulaArg, k+1))
+		for c := range result[r] {
+			result[r][c] = na
+		}
+	}
+	sey := math.Sqrt(ssResid / float64(df))
+	for j := 0; j < k; j++ {
+		result[1][k-1-j] = stats(sey*math.Sqrt(inv[j][j]), df > 0)
+	}
+	if bConst.Number == 1 {
+		varB := 1 / float64(n)
+		for i := 0; i < k; i++ {
+			for j := 0; j < k; j++ {
+				varB += meanX[i] * inv[i][j] * meanX[j]
+			}
+		}
+		result[1][k] = stats(sey*math.Sqrt(varB), df > 0)
+	}
+	result[2][0], result[2][1] = stats(ssReg/ssTotal, ssTotal != 0), stats(sey, df > 0)
+	result[3][0] = stats(ssReg/float64(k)/(ssResid/float64(df)), df > 0 && ssResid != 0)
+	result[3][1] = newNumberFormulaArg(float64(df))
+	result[4][0], result[4][1] = newNumberFormulaArg(ssReg), newNumberFormulaArg(ssResid)
+	return newMatrixFormulaArg(result)
+}
+
 // LOGINV function calculates the inverse of the Cumulative Log-Normal
 // Distribution Function of x, for a supplied probability. The syntax of the
 // function is:
@@ -14765,6 +17183,131 @@ func (fn *formulaFuncs) FORMULATEXT(argsList *list.List) formulaArg {
 	return newStringFormulaArg(formula)
 }
 
+// GETPIVOTDATA function returns the data stored in a pivot table by given the
+// name of the data field, a reference to any cell in the pivot table and the
+// pairs of the field names and items which describe the data to retrieve. The
+// data will be summarized from the source data range of the pivot table by
+// the subtotal function of the data field. The syntax of the function is:
+//
+//	GETPIVOTDATA(data_field,pivot_table,[field1,item1],...)
+func (fn *formulaFuncs) GETPIVOTDATA(argsList *list.List) formulaArg {
+	if argsList.Len() < 2 {
+		return newErrorFormulaArg(formulaErrorVALUE, "GETPIVOTDATA requires at least 2 arguments")
+	}
+	if argsList.Len()%2 != 0 {
+		return newErrorFormulaArg(formulaErrorVALUE, "GETPIVOTDATA requires pairs of field and item arguments")
+	}
+	opts, errArg := fn.getPivotTableByRef(argsList.Front().Next().Value.(formulaArg))
+	if errArg.Type == ArgError {
+		return errArg
+	}
+	dataSheet, coordinates, err := fn.f.getPivotDataRange(opts)
+	if err != nil {
+		return newErrorFormulaArg(formulaErrorREF, err.Error())
+	}
+	cellValue := func(col, row int) (formulaArg, string, error) {
+		if opts.DataSource != nil {
+			val, _, err := fn.f.getPivotDataCellValue(opts, dataSheet, col, row, true)
+			return newStringFormulaArg(val), val, err
+		}
+		cell, _ := CoordinatesToCellName(col, row)
+		value, err := fn.f.cellResolver(fn.ctx, dataSheet, cell)
+		if err != nil {
+			return value, "", err
+		}
+		formatted, err := fn.f.GetCellValue(dataSheet, cell)
+		return value, formatted, err
+	}
+	order, err := fn.f.getTableFieldsOrder(opts)
+	if err != nil {
+		return newErrorFormulaArg(formulaErrorREF, err.Error())
+	}
+	fieldIndex := func(fields []PivotTableField, name string, dataField bool) (int, string) {
+		for _, field := range fields {
+			if strings.EqualFold(field.Data, name) || (dataField && field.Name != "" && strings.EqualFold(field.Name, name)) {
+				return inStrSlice(order, field.Data, true), fn.f.getPivotTableFieldsSubtotal([]PivotTableField{field})[0]
+			}
+		}
+		return -1, ""
+	}
+	dataCol, subtotal := fieldIndex(opts.Data, argsList.Front().Value.(formulaArg).Value(), true)
+	if dataCol == -1 {
+		return newErrorFormulaArg(formulaErrorREF, "GETPIVOTDATA requires valid data field name")
+	}
+	var cols []int
+	var items []formulaArg
+	for arg := argsList.Front().Next().Next(); arg != nil; arg = arg.Next().Next() {
+		name := arg.Value.(formulaArg).Value()
+		col, _ := fieldIndex(append(append(append([]PivotTableField{}, opts.Rows...), opts.Columns...), opts.Filter...), name, false)
+		if col == -1 {
+			return newErrorFormulaArg(formulaErrorREF, "GETPIVOTDATA requires valid field name")
+		}
+		cols, items = append(cols, col), append(items, arg.Next().Value.(formulaArg))
+	}
+	values := list.New()
+	for row := coordinates[1] + 1; row <= coordinates[3]; row++ {
+		matched := true
+		for i := 0; i < len(cols) && matched; i++ {
+			value, formatted, err := cellValue(coordinates[0]+cols[i], row)
+			if err != nil {
+				return newErrorFormulaArg(formulaErrorVALUE, err.Error())
+			}
+			num, item := value.ToNumber(), items[i]
+			matched = strings.EqualFold(value.Value(), item.Value()) || strings.EqualFold(formatted, item.Value()) ||
+				(item.Type == ArgNumber && num.Type == ArgNumber && num.Number == item.Number)
+		}
+		if !matched {
+			continue
+		}
+		value, _, err := cellValue(coordinates[0]+dataCol, row)
+		if err != nil {
+			return newErrorFormulaArg(formulaErrorVALUE, err.Error())
+		}
+		if subtotal == "count" && value.Value() != "" {
+			values.PushBack(value)
+			continue
+		}
+		if num := value.ToNumber(); num.Type == ArgNumber {
+			values.PushBack(num)
+		}
+	}
+	if values.Len() == 0 && len(cols) > 0 {
+		return newErrorFormulaArg(formulaErrorREF, "GETPIVOTDATA no result found")
+	}
+	return map[string]func(*list.List) formulaArg{
+		"average": fn.AVERAGE, "count": fn.COUNTA, "countNums": fn.COUNT, "max": fn.MAX,
+		"min": fn.MIN, "product": fn.PRODUCT, "stdDev": fn.STDEV, "stdDevp": fn.STDEVP,
+		"sum": fn.SUM, "var": fn.VAR, "varp": fn.VARP,
+	}[subtotal](values)
+}
+
+// getPivotTableByRef provides a function to get the options of the pivot
+// table which contains the cell by given reference formula argument.
+func (fn *formulaFuncs) getPivotTableByRef(arg formulaArg) (*PivotTableOptions, formulaArg) {
+	var ref cellRef
+	if arg.cellRanges != nil && arg.cellRanges.Len() > 0 {
+		ref = arg.cellRanges.Front().Value.(cellRange).From
+	} else if arg.cellRefs != nil && arg.cellRefs.Len() > 0 {
+		ref = arg.cellRefs.Front().Value.(cellRef)
+	} else {
+		return nil, newErrorFormulaArg(formulaErrorREF, "GETPIVOTDATA requires reference to the pivot table")
+	}
+	if ref.Sheet == "" {
+		ref.Sheet = fn.sheet
+	}
+	pivotTables, err := fn.f.GetPivotTables(ref.Sheet)
+	if err != nil {
+		return nil, newErrorFormulaArg(formulaErrorREF, err.Error())
+	}
+	for i := range pivotTables {
+		if _, coordinates, err := fn.f.adjustRange(pivotTables[i].PivotTableRange); err == nil &&
+			cellInRange([]int{ref.Col, ref.Row}, coordinates) {
+			return &pivotTables[i], newEmptyFormulaArg()
+		}
+	}
+	return nil, newErrorFormulaArg(formulaErrorREF, "GETPIVOTDATA requires reference to the pivot table")
+}
+
 // checkHVLookupArgs checking arguments, prepare extract mode, lookup value,
 // and data for the formula functions HLOOKUP and VLOOKUP.
 func checkHVLookupArgs(name string, argsList *list.List) (idx int, lookupValue, tableArray, matchMode, errArg formulaArg) {
@@ -14849,6 +17392,59 @@ func (fn *formulaFuncs) HYPERLINK(argsList *list.List) formulaArg {
 	return newStringFormulaArg(argsList.Back().Value.(formulaArg).Value())
 }
 
+// IMAGE function inserts an image into the cell from the given source URL,
+// the alternative text will be returned if specified, otherwise the source
+// URL will be returned. The syntax of the function is:
+//
+//	IMAGE(source,[alt_text],[sizing],[height],[width])
+func (fn *formulaFuncs) IMAGE(argsList *list.List) formulaArg {
+	if argsList.Len() < 1 {
+		return newErrorFormulaArg(formulaErrorVALUE, "IMAGE requires at least 1 argument")
+	}
+	if argsList.Len() > 5 {
+		return newErrorFormulaArg(formulaErrorVALUE, "IMAGE allows at most 5 arguments")
+	}
+	var args []formulaArg
+	for arg := argsList.Front(); arg != nil; arg = arg.Next() {
+		if token := arg.Value.(formulaArg); token.Type == ArgError {
+			return token
+		}
+		args = append(args, arg.Value.(formulaArg))
+	}
+	source, altText := args[0].Value(), ""
+	if source == "" {
+		return newErrorFormulaArg(formulaErrorVALUE, formulaErrorVALUE)
+	}
+	if len(args) > 1 {
+		altText = args[1].Value()
+	}
+	if len(args) > 2 && args[2].Type != ArgEmpty {
+		sizing := args[2].ToNumber()
+		if sizing.Type != ArgNumber || sizing.Number < 0 || sizing.Number > 3 {
+			return newErrorFormulaArg(formulaErrorVALUE, formulaErrorVALUE)
+		}
+		if int(sizing.Number) == 3 {
+			if len(args) < 5 {
+				return newErrorFormulaArg(formulaErrorVALUE, formulaErrorVALUE)
+			}
+			for _, arg := range args[3:] {
+				if num := arg.ToNumber(); num.Type != ArgNumber || num.Number < 1 {
+					return newErrorFormulaArg(formulaErrorVALUE, formulaErrorVALUE)
+				}
+			}
+		}
+	}
+	if fn.ctx != nil {
+		fn.ctx.mu.Lock()
+		fn.ctx.images = append(fn.ctx.images, calcImage{sheet: fn.sheet, cell: fn.cell, source: source, altText: altText})
+		fn.ctx.mu.Unlock()
+	}
+	if altText != "" {
+		return newStringFormulaArg(altText)
+	}
+	return newStringFormulaArg(source)
+}
+
 // calcMatch returns the position of the value by given match type, criteria
 // and lookup array for the formula function MATCH.
 func calcMatch(matchType int, criteria *formulaCriteria, lookupArray []formulaArg) formulaArg {
@@ -18345,6 +20941,28 @@ func (fn *formulaFuncs) YIELDMAT(argsList *list.List) formulaArg {
 	return newNumberFormulaArg(result)
 }
 
+// Cube Functions
+
+// CUBEVALUE function returns an aggregated value from the cube by given the
+// name of the connection to the cube and the member expressions. The
+// connections to the OLAP cubes and data models are not supported currently,
+// so the #N/A error will be returned if the arguments are valid, which could
+// be handled by the IFERROR or IFNA function in the formula. The syntax of the
+// function is:
+//
+//	CUBEVALUE(connection,[member_expression1],...)
+func (fn *formulaFuncs) CUBEVALUE(argsList *list.List) formulaArg {
+	if argsList.Len() < 1 {
+		return newErrorFormulaArg(formulaErrorVALUE, "CUBEVALUE requires at least 1 argument")
+	}
+	for arg := argsList.Front(); arg != nil; arg = arg.Next() {
+		if token := arg.Value.(formulaArg); token.Type == ArgError {
+			return token
+		}
+	}
+	return newErrorFormulaArg(formulaErrorNA, "CUBEVALUE requires valid connection")
+}
+
 // Database Functions
 
 // calcDatabase defines the structure for formula database.
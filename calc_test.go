@@ -2,9 +2,13 @@ package excelize_ch
 
 import (
 	"container/list"
+	"encoding/xml"
+	"fmt"
 	"math"
 	"path/filepath"
+	"strconv"
 	"strings"
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -917,6 +921,11 @@ func TestCalcCellValue(t *testing.T) {
 		"=SUMPRODUCT(A1:A3,B1:B3)":       "14",
 		"=SUMPRODUCT(A1:B3)":             "15",
 		"=SUMPRODUCT(A1:A3,B1:B3,B2:B4)": "20",
+		"=SUMPRODUCT(A1:A3,D1:D3)":       "0",
+		"=SUMPRODUCT({1,2,3},{4,5,6})":   "32",
+		"=SUMPRODUCT(--(A1:A3>1),A1:A3)": "5",
+		"=SUMPRODUCT((A1:A3>1)*A1:A3)":   "5",
+		"=SUMPRODUCT((A1:A3>1),A1:A3)":   "0",
 		// SUMSQ
 		"=SUMSQ(A1:A4)":              "14",
 		"=SUMSQ(A1,B1,A2,B2,6)":      "82",
@@ -1983,6 +1992,12 @@ func TestCalcCellValue(t *testing.T) {
 		// HYPERLINK
 		"=HYPERLINK(\"https://github.com/xuri/excelize\")":              "https://github.com/xuri/excelize",
 		"=HYPERLINK(\"https://github.com/xuri/excelize\",\"Excelize\")": "Excelize",
+		// IMAGE
+		"=IMAGE(\"https://example.com/logo.png\")":                  "https://example.com/logo.png",
+		"=_xlfn.IMAGE(\"https://example.com/logo.png\",\"Logo\")":   "Logo",
+		"=IMAGE(\"https://example.com/logo.png\",\"Logo\",0)":       "Logo",
+		"=IMAGE(\"https://example.com/logo.png\",,)":                "https://example.com/logo.png",
+		"=IMAGE(\"https://example.com/logo.png\",\"Logo\",3,10,20)": "Logo",
 		// VLOOKUP
 		"=VLOOKUP(D2,D:D,1,FALSE)":            "Jan",
 		"=VLOOKUP(D2,D1:D10,1)":               "Jan",
@@ -2862,13 +2877,14 @@ func TestCalcCellValue(t *testing.T) {
 		"=SUMSQ(\"X\")": {"#VALUE!", "strconv.ParseFloat: parsing \"X\": invalid syntax"},
 		"=SUMSQ(C1:D2)": {"#VALUE!", "strconv.ParseFloat: parsing \"Month\": invalid syntax"},
 		// SUMPRODUCT
-		"=SUMPRODUCT()":            {"#VALUE!", "SUMPRODUCT requires at least 1 argument"},
-		"=SUMPRODUCT(A1,B1:B2)":    {"#VALUE!", "#VALUE!"},
-		"=SUMPRODUCT(A1,D1)":       {"#VALUE!", "#VALUE!"},
-		"=SUMPRODUCT(A1:A3,D1:D3)": {"#VALUE!", "#VALUE!"},
-		"=SUMPRODUCT(A1:A2,B1:B3)": {"#VALUE!", "#VALUE!"},
-		"=SUMPRODUCT(\"\")":        {"#VALUE!", "#VALUE!"},
-		"=SUMPRODUCT(A1,NA())":     {"#N/A", "#N/A"},
+		"=SUMPRODUCT()":                {"#VALUE!", "SUMPRODUCT requires at least 1 argument"},
+		"=SUMPRODUCT(A1,B1:B2)":        {"#VALUE!", "#VALUE!"},
+		"=SUMPRODUCT(A1,D1)":           {"#VALUE!", "#VALUE!"},
+		"=SUMPRODUCT(A1:A2,B1:B3)":     {"#VALUE!", "#VALUE!"},
+		"=SUMPRODUCT({1;2;3},{4,5,6})": {"#VALUE!", "#VALUE!"},
+		"=SUMPRODUCT(\"\")":            {"#VALUE!", "#VALUE!"},
+		"=SUMPRODUCT(A1,NA())":         {"#N/A", "#N/A"},
+		"=SUMPRODUCT(A1:A2,{1;#N/A})":  {"#N/A", "#N/A"},
 		// SUMX2MY2
 		"=SUMX2MY2()":         {"#VALUE!", "SUMX2MY2 requires 2 arguments"},
 		"=SUMX2MY2(A1,B1:B2)": {"#N/A", "#N/A"},
@@ -4030,6 +4046,19 @@ func TestCalcCellValue(t *testing.T) {
 		// HYPERLINK
 		"=HYPERLINK()": {"#VALUE!", "HYPERLINK requires at least 1 argument"},
 		"=HYPERLINK(\"https://github.com/xuri/excelize\",\"Excelize\",\"\")": {"#VALUE!", "HYPERLINK allows at most 2 arguments"},
+		// IMAGE
+		"=IMAGE()":                      {"#VALUE!", "IMAGE requires at least 1 argument"},
+		"=IMAGE(\"a\",\"b\",3,10,20,1)": {"#VALUE!", "IMAGE allows at most 5 arguments"},
+		"=IMAGE(\"\")":                  {"#VALUE!", "#VALUE!"},
+		"=IMAGE(NA())":                  {"#N/A", "#N/A"},
+		"=IMAGE(\"a\",\"b\",4)":         {"#VALUE!", "#VALUE!"},
+		"=IMAGE(\"a\",\"b\",\"c\")":     {"#VALUE!", "#VALUE!"},
+		"=IMAGE(\"a\",\"b\",3)":         {"#VALUE!", "#VALUE!"},
+		"=IMAGE(\"a\",\"b\",3,0,20)":    {"#VALUE!", "#VALUE!"},
+		// CUBEVALUE
+		"=CUBEVALUE()": {"#VALUE!", "CUBEVALUE requires at least 1 argument"},
+		"=CUBEVALUE(\"ThisWorkbookDataModel\",\"[Measures].[Sales]\")": {"#N/A", "CUBEVALUE requires valid connection"},
+		"=CUBEVALUE(NA())": {"#N/A", "#N/A"},
 		// VLOOKUP
 		"=VLOOKUP()":                     {"#VALUE!", "VLOOKUP requires at least 3 arguments"},
 		"=VLOOKUP(D2,D1,1,FALSE)":        {"#VALUE!", "VLOOKUP requires second argument of table array"},
@@ -4834,6 +4863,265 @@ func TestCalcTRANSPOSE(t *testing.T) {
 	assert.NoError(t, err, formula)
 }
 
+func TestCalcArrayFormula(t *testing.T) {
+	cellData := [][]interface{}{
+		{1, 4, "a"},
+		{2, 5, "b"},
+		{3, 0, "c"},
+	}
+	f := prepareCalcData(cellData)
+	formulaType := STCellFormulaTypeArray
+	for formula, expected := range map[string][][]string{
+		"=A1:A3*B1:B3":         {{"4"}, {"10"}, {"0"}},
+		"=A1:A3/B1:B3":         {{"0.25"}, {"0.4"}, {"#DIV/0!"}},
+		"=-A1:A3+1":            {{"0"}, {"-1"}, {"-2"}},
+		"=A1:A3&C1:C3":         {{"1a"}, {"2b"}, {"3c"}},
+		"=A1:A2*B1:B3":         {{"4"}, {"10"}, {"#N/A"}},
+		"=A1:A3>1":             {{"FALSE"}, {"TRUE"}, {"TRUE"}},
+		"=SUM(A1:A3*B1:B3)":    {{"14"}, {"14"}, {"14"}},
+		"=TRANSPOSE(C1:C3)":    {{"a"}, {"a"}, {"a"}},
+		"=TRANSPOSE(A1:A3)*10": {{"10"}, {"10"}, {"10"}},
+	} {
+		ref := "E1:E3"
+		assert.NoError(t, f.SetCellFormula("Sheet1", "E1", formula, FormulaOpts{Ref: &ref, Type: &formulaType}))
+		result, err := f.CalcCellValues("Sheet1", ref)
+		assert.NoError(t, err, formula)
+		assert.Equal(t, expected, result, formula)
+	}
+	ref := "E1:G2"
+	assert.NoError(t, f.SetCellFormula("Sheet1", "E1", "=TRANSPOSE(A1:B3)", FormulaOpts{Ref: &ref, Type: &formulaType}))
+	result, err := f.CalcCellValues("Sheet1", ref)
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{{"1", "2", "3"}, {"4", "5", "0"}}, result)
+	value, err := f.CalcCellValue("Sheet1", "F2")
+	assert.NoError(t, err)
+	assert.Equal(t, "5", value)
+	// Test referenced the anchor cell of the array formula
+	assert.NoError(t, f.SetCellFormula("Sheet1", "H1", "=E1+1"))
+	value, err = f.CalcCellValue("Sheet1", "H1")
+	assert.NoError(t, err)
+	assert.Equal(t, "2", value)
+	// Test calculate cell values with invalid range reference
+	_, err = f.CalcCellValues("Sheet1", "E1")
+	assert.Equal(t, ErrParameterInvalid, err)
+	_, err = f.CalcCellValues("Sheet1", "A0:A1")
+	assert.Equal(t, newCellNameToCoordinatesError("A0", newInvalidCellNameError("A0")), err)
+	// Test calculate cell values with not exists worksheet
+	_, err = f.CalcCellValues("SheetN", "E1:E3")
+	assert.EqualError(t, err, "sheet SheetN does not exist")
+}
+
+func TestCalcLINEST(t *testing.T) {
+	cellData := [][]interface{}{
+		{1, 2, 0, 1},
+		{2, 4, 1, 2},
+		{3, 5, 1, 3},
+		{4, 4, 6, 4},
+		{5, 5, 1, 5},
+	}
+	f := prepareCalcData(cellData)
+	formulaType := STCellFormulaTypeArray
+	for formula, expected := range map[string][][]string{
+		"=LINEST(B1:B5,A1:A5)": {{"0.6", "2.2"}},
+		"=LINEST(B1:B5)":       {{"0.6", "2.2"}},
+		"=LINEST(B1:B5,A1:A5,TRUE,TRUE)": {
+			{"0.6", "2.2"},
+			{"0.282842712474619", "0.938083151964686"},
+			{"0.6", "0.894427190999916"},
+			{"4.5", "3"},
+			{"3.6", "2.4"},
+		},
+		"=LINEST(B1:B5,A1:A5,FALSE,TRUE)": {
+			{"1.2", "0"},
+			{"0.175809814598307", "#N/A"},
+			{"0.92093023255814", "1.30384048104053"},
+			{"46.5882352941176", "4"},
+			{"79.2", "6.8"},
+		},
+		"=LINEST(B1:B5,C1:D5)": {{"0.686033519553073", "-0.122905027932961", "2.16312849162011"}},
+	} {
+		ref := fmt.Sprintf("F1:%s", []string{"", "G", "H"}[len(expected[0])-1]+strconv.Itoa(len(expected)))
+		assert.NoError(t, f.SetCellFormula("Sheet1", "F1", formula, FormulaOpts{Ref: &ref, Type: &formulaType}))
+		result, err := f.CalcCellValues("Sheet1", ref)
+		assert.NoError(t, err, formula)
+		assert.Equal(t, expected, result, formula)
+	}
+	for formula, expected := range map[string]string{
+		"=INDEX(LINEST(B1:B5,A1:A5),1,2)":           "2.2",
+		"=LINEST()":                                 "#VALUE!",
+		"=LINEST(B1:B5,A1:A5,TRUE,TRUE,TRUE)":       "#VALUE!",
+		"=LINEST(B1:B5,A1:A5,\"\")":                 "#VALUE!",
+		"=LINEST(B1:B5,A1:A5,TRUE,\"\")":            "#VALUE!",
+		"=LINEST(B1:B5,A1:A4)":                      "#REF!",
+		"=LINEST(B1:B5,E1:E5)":                      "#VALUE!",
+		"=LINEST(E1:E5,A1:A5)":                      "#VALUE!",
+		"=LINEST(B1:B5,{1,1,1,1,1},FALSE)":          "#REF!",
+		"=LINEST(B1:B2,A1:D2)":                      "#NUM!",
+		"=LINEST(B1:B5,C1:C5*0)":                    "#NUM!",
+		"=INDEX(LINEST(B1:B2,A1:A2,TRUE,TRUE),2,1)": "#NUM!",
+	} {
+		assert.NoError(t, f.SetCellFormula("Sheet1", "F1", formula))
+		result, _ := f.CalcCellValue("Sheet1", "F1")
+		assert.Equal(t, expected, result, formula)
+	}
+}
+
+func TestCalcFORECASTdotETS(t *testing.T) {
+	cellData := [][]interface{}{{"Time", "Linear", "Seasonal", "Time", "Value"}}
+	for i := 0; i < 12; i++ {
+		cellData = append(cellData, []interface{}{i + 1, 3 + 2*i, 10 + 0.5*float64(i) + []float64{1, -1, 2, -2}[i%4]})
+	}
+	for i, row := range [][]interface{}{{5, 10}, {1, 2}, {3, 4}, {3, 8}, {"a", 1}, {6, "b"}} {
+		cellData[i+1] = append(cellData[i+1], row...)
+	}
+	f := prepareCalcData(cellData)
+	for formula, expected := range map[string]string{
+		"=FORECAST.ETS(13,B2:B13,A2:A13)":          "27",
+		"=FORECAST.ETS(12.5,B2:B13,A2:A13)":        "26",
+		"=_xlfn.FORECAST.ETS(13,C2:C13,A2:A13)":    "17",
+		"=FORECAST.ETS(14,C2:C13,A2:A13,4)":        "15.5",
+		"=FORECAST.ETS(6,E2:E5,D2:D5)":             "12",
+		"=FORECAST.ETS(6,E2:E5,D2:D5,0,1,7)":       "25.74",
+		"=FORECAST.ETS()":                          "#VALUE!",
+		"=FORECAST.ETS(13,B2:B13,A2:A13,1,1,1,1)":  "#VALUE!",
+		"=FORECAST.ETS(\"\",B2:B13,A2:A13)":        "#VALUE!",
+		"=FORECAST.ETS(13,B2:B13,A2:A13,\"\")":     "#VALUE!",
+		"=FORECAST.ETS(13,B2:B13,A2:A12)":          "#N/A",
+		"=FORECAST.ETS(13,B2:B13,A2:A13,-1)":       "#NUM!",
+		"=FORECAST.ETS(13,B2:B13,A2:A13,1,2)":      "#NUM!",
+		"=FORECAST.ETS(13,B2:B13,A2:A13,1,1,8)":    "#NUM!",
+		"=FORECAST.ETS(13,C2:C13,A2:A13,7)":        "#NUM!",
+		"=FORECAST.ETS(5,B2:B13,A2:A13)":           "#NUM!",
+		"=FORECAST.ETS(13,B2,A2)":                  "#NUM!",
+		"=FORECAST.ETS(7,E2:E7,D2:D7)":             "#VALUE!",
+		"=FORECAST.ETS(7,E2:E5,{1,2.5,3,4.2})":     "#NUM!",
+		"=FORECAST.ETS(7,{1,\"a\",3,4},{1,2,3,4})": "#VALUE!",
+	} {
+		assert.NoError(t, f.SetCellFormula("Sheet1", "F1", formula))
+		result, _ := f.CalcCellValue("Sheet1", "F1")
+		assert.Equal(t, expected, result, formula)
+	}
+}
+
+func TestCalcLETAndLAMBDA(t *testing.T) {
+	cellData := [][]interface{}{{1}, {2}, {3}}
+	f := prepareCalcData(cellData)
+	assert.NoError(t, f.SetDefinedName(&DefinedName{Name: "Double", RefersTo: "=LAMBDA(x,x*2)"}))
+	assert.NoError(t, f.SetDefinedName(&DefinedName{Name: "Hypot", RefersTo: "=_xlfn.LAMBDA(_xlpm.a,_xlpm.b,SQRT(_xlpm.a^2+_xlpm.b^2))"}))
+	for formula, expected := range map[string]string{
+		"=LET(x,1,y,x+A2,x*y)":                     "3",
+		"=_xlfn.LET(_xlpm.x,SUM(A1:A3),_xlpm.x*2)": "12",
+		"=SUM(LET(r,A1:A3,r))":                     "6",
+		"=LET(x,1,LET(x,2,x)+x)":                   "3",
+		"=LET(x,5,IF(x>3,\"big\",\"small\"))":      "big",
+		"=LAMBDA(x,y,x+y)(1,2)":                    "3",
+		"=SUM(LAMBDA(a,b,a*b)(2,3),1)":             "7",
+		"=LET(f,LAMBDA(a,a*2),f(3))":               "6",
+		"=LET(x,2,LAMBDA(a,a*x)(3))":               "6",
+		"=LET(x,LAMBDA(x,x+1),x(x(1)))":            "3",
+		"=Double(5)":                               "10",
+		"=Double(Double(A3))":                      "12",
+		"=Hypot(3,4)":                              "5",
+		"=LET(x,1)":                                "#VALUE!",
+		"=LET(x,,1)":                               "#VALUE!",
+		"=LET(1,2,3)":                              "#VALUE!",
+		"=LAMBDA(x,x)(1,2)":                        "#VALUE!",
+		"=LAMBDA(1,1)(1)":                          "#VALUE!",
+		"=LAMBDA(x,x)":                             "#CALC!",
+		"=LET(f,LAMBDA(x,x),f)":                    "#CALC!",
+	} {
+		assert.NoError(t, f.SetCellFormula("Sheet1", "B1", formula))
+		result, err := f.CalcCellValue("Sheet1", "B1")
+		assert.NoError(t, err, formula)
+		assert.Equal(t, expected, result, formula)
+	}
+}
+
+func TestCalcUserDefinedFunction(t *testing.T) {
+	cellData := [][]interface{}{{1, "IBM US Equity"}, {2}, {3}}
+	f := prepareCalcData(cellData)
+	assert.NoError(t, f.RegisterFunction("BDP", func(args ...FormulaArg) FormulaArg {
+		if len(args) != 2 {
+			return newErrorFormulaArg(formulaErrorVALUE, "BDP requires 2 arguments")
+		}
+		if args[0].Value() == "IBM US Equity" && args[1].Value() == "PX_LAST" {
+			return FormulaArg{Type: ArgNumber, Number: 143.57}
+		}
+		return FormulaArg{Type: ArgError, String: formulaErrorNA, Error: formulaErrorNA}
+	}))
+	assert.NoError(t, f.RegisterFunction("my.total", func(args ...FormulaArg) FormulaArg {
+		var total float64
+		for _, arg := range args {
+			for _, value := range arg.ToList() {
+				if num := value.ToNumber(); num.Type == ArgNumber {
+					total += num.Number
+				}
+			}
+		}
+		return FormulaArg{Type: ArgNumber, Number: total}
+	}))
+	assert.NoError(t, f.RegisterFunction("_xll.GREETING", func(args ...FormulaArg) FormulaArg {
+		return FormulaArg{Type: ArgString, String: "Hello, " + args[0].Value()}
+	}))
+	assert.NoError(t, f.RegisterFunction("PI", func(args ...FormulaArg) FormulaArg {
+		return FormulaArg{Type: ArgNumber, Number: 3}
+	}))
+	for formula, expected := range map[string]string{
+		"=BDP(\"IBM US Equity\",\"PX_LAST\")": "143.57",
+		"=bdp(B1,\"PX_LAST\")":                "143.57",
+		"=_xll.BDP(B1,\"PX_LAST\")*2":         "287.14",
+		"=IFERROR(BDP(B1),0)":                 "0",
+		"=MY.TOTAL(A1:A3,4)":                  "10",
+		"=SUM(MY.TOTAL(A1:A2),1)":             "4",
+		"=_xludf.MY.TOTAL(A3)":                "3",
+		"=GREETING(\"World\")":                "Hello, World",
+		"=_xll.GREETING(B1)":                  "Hello, IBM US Equity",
+		"=PI()":                               "3",
+	} {
+		assert.NoError(t, f.SetCellFormula("Sheet1", "C1", formula))
+		result, err := f.CalcCellValue("Sheet1", "C1")
+		assert.NoError(t, err, formula)
+		assert.Equal(t, expected, result, formula)
+	}
+	for formula, expected := range map[string][]string{
+		"=BDP(B1,\"PX_OPEN\")": {"#N/A", "#N/A"},
+		"=BDP(B1)":             {"#VALUE!", "BDP requires 2 arguments"},
+		// Test calculate unregistered user-defined function
+		"=BDH(B1)": {"#VALUE!", "not support BDH function"},
+	} {
+		assert.NoError(t, f.SetCellFormula("Sheet1", "C1", formula))
+		result, err := f.CalcCellValue("Sheet1", "C1")
+		assert.EqualError(t, err, expected[1], formula)
+		assert.Equal(t, expected[0], result, formula)
+	}
+	// Test register function with invalid parameters
+	assert.Equal(t, ErrParameterInvalid, f.RegisterFunction(" ", func(args ...FormulaArg) FormulaArg { return FormulaArg{} }))
+	assert.Equal(t, ErrParameterInvalid, f.RegisterFunction("BDH", nil))
+}
+
+func TestCalcAGGREGATE(t *testing.T) {
+	cellData := [][]interface{}{{1}, {2}, {nil}, {4}, {nil}, {6}}
+	f := prepareCalcData(cellData)
+	assert.NoError(t, f.SetCellFormula("Sheet1", "A3", "=NA()"))
+	assert.NoError(t, f.SetCellFormula("Sheet1", "A5", "=SUBTOTAL(9,A1:A2)"))
+	assert.NoError(t, f.SetRowVisible("Sheet1", 4, false))
+	for formula, expected := range map[string]string{
+		"=AGGREGATE(9,3,A1:A6)":      "9",
+		"=AGGREGATE(9,4,A1:A2)":      "3",
+		"=AGGREGATE(9,6,A1:A6)":      "16",
+		"=AGGREGATE(9,7,A1:A6)":      "12",
+		"=AGGREGATE(1,7,A1:A6)":      "3",
+		"=AGGREGATE(3,6,A1:A6)":      "5",
+		"=AGGREGATE(14,6,A1:A6,2)":   "4",
+		"=AGGREGATE(9,6,A1:A2,NA())": "3",
+		"=AGGREGATE(9,4,A1:A2,NA())": "#N/A",
+	} {
+		assert.NoError(t, f.SetCellFormula("Sheet1", "B1", formula))
+		result, _ := f.CalcCellValue("Sheet1", "B1")
+		assert.Equal(t, expected, result, formula)
+	}
+}
+
 func TestCalcVLOOKUP(t *testing.T) {
 	cellData := [][]interface{}{
 		{nil, nil, nil, nil, nil, nil},
@@ -6242,3 +6530,552 @@ func TestParseToken(t *testing.T) {
 		efp.Token{TSubType: efp.TokenSubTypeRange, TValue: "1A"}, nil, nil,
 	).Error())
 }
+
+func TestRecalculate(t *testing.T) {
+	f := NewFile()
+	_, err := f.NewSheet("Sheet2")
+	assert.NoError(t, err)
+	assert.NoError(t, f.SetSheetCol("Sheet1", "A1", &[]interface{}{1, 2}))
+	for cell, formula := range map[string]string{
+		"Sheet1!B1": "SUM(A1:A2)", "Sheet1!B2": "B1*2", "Sheet1!C1": "A2+1",
+		"Sheet1!D1": "Base*10", "Sheet1!E1": "1/0", "Sheet1!F1": "FOOBAR()",
+		"Sheet2!A1": "Sheet1!B2+1",
+	} {
+		ref := strings.Split(cell, "!")
+		assert.NoError(t, f.SetCellFormula(ref[0], ref[1], formula))
+	}
+	assert.NoError(t, f.SetDefinedName(&DefinedName{Name: "Base", RefersTo: "Sheet1!$A$2"}))
+	setCachedValue := func(sheet, cell, value string) {
+		ws, err := f.workSheetReader(sheet)
+		assert.NoError(t, err)
+		c, _, _, err := ws.prepareCell(cell)
+		assert.NoError(t, err)
+		c.V = value
+	}
+	setCachedValue("Sheet1", "F1", "cached")
+	assert.NoError(t, f.Recalculate(false))
+	for cell, expected := range map[string]string{
+		"Sheet1!B1": "3", "Sheet1!B2": "6", "Sheet1!C1": "3", "Sheet1!D1": "20", "Sheet1!E1": "#DIV/0!",
+		"Sheet1!F1": "cached", "Sheet2!A1": "7",
+	} {
+		ref := strings.Split(cell, "!")
+		value, err := f.GetCellValue(ref[0], ref[1])
+		assert.NoError(t, err)
+		assert.Equal(t, expected, value, cell)
+	}
+	cellType, err := f.GetCellType("Sheet1", "E1")
+	assert.NoError(t, err)
+	assert.Equal(t, CellTypeError, cellType)
+	// Test recalculate only the formulas depending on the changed cells
+	setCachedValue("Sheet1", "C1", "stale")
+	setCachedValue("Sheet1", "D1", "stale")
+	assert.NoError(t, f.SetCellValue("Sheet1", "A1", 10))
+	assert.NoError(t, f.Recalculate(true))
+	for cell, expected := range map[string]string{
+		"Sheet1!B1": "12", "Sheet1!B2": "24", "Sheet1!C1": "stale", "Sheet1!D1": "stale",
+		"Sheet2!A1": "25",
+	} {
+		ref := strings.Split(cell, "!")
+		value, err := f.GetCellValue(ref[0], ref[1])
+		assert.NoError(t, err)
+		assert.Equal(t, expected, value, cell)
+	}
+	// Test recalculate the formulas depending on the cells referenced by the
+	// defined names
+	assert.NoError(t, f.SetCellValue("Sheet1", "A2", 5))
+	assert.NoError(t, f.Recalculate(true))
+	for cell, expected := range map[string]string{"Sheet1!C1": "6", "Sheet1!D1": "50"} {
+		ref := strings.Split(cell, "!")
+		value, err := f.GetCellValue(ref[0], ref[1])
+		assert.NoError(t, err)
+		assert.Equal(t, expected, value, cell)
+	}
+	// Test recalculate the changed formula and the formulas in the worksheet
+	// after inserting rows
+	assert.NoError(t, f.SetCellFormula("Sheet1", "C1", "A2+2"))
+	assert.NoError(t, f.Recalculate(true))
+	value, err := f.GetCellValue("Sheet1", "C1")
+	assert.NoError(t, err)
+	assert.Equal(t, "7", value)
+	setCachedValue("Sheet1", "C1", "stale")
+	assert.NoError(t, f.InsertRows("Sheet1", 1, 1))
+	assert.NoError(t, f.Recalculate(true))
+	value, err = f.GetCellValue("Sheet1", "C2")
+	assert.NoError(t, err)
+	assert.Equal(t, "7", value)
+	// Test recalculate only the formulas depending on the changed cells by
+	// the dependency index
+	g := NewFile()
+	evaluated := map[string]int{}
+	assert.NoError(t, g.RegisterFunction("TRACE", func(args ...FormulaArg) FormulaArg {
+		evaluated[args[0].Value()]++
+		return args[1].ToNumber()
+	}))
+	for cell, formula := range map[string]string{
+		"A2": `TRACE("A2",A1)`, "A3": `TRACE("A3",A2)`, "B1": `TRACE("B1",B5)`,
+		"C1": `TRACE("C1",SUM(A1:A2))`, "D1": `TRACE("D1",C9)`,
+	} {
+		assert.NoError(t, g.SetCellFormula("Sheet1", cell, formula))
+	}
+	assert.NoError(t, g.Recalculate(true))
+	assert.Equal(t, map[string]int{"A2": 1, "A3": 1, "B1": 1, "C1": 1, "D1": 1}, evaluated)
+	evaluated = map[string]int{}
+	assert.NoError(t, g.SetCellValue("Sheet1", "A1", 5))
+	assert.NoError(t, g.Recalculate(true))
+	assert.Equal(t, map[string]int{"A2": 1, "A3": 1, "C1": 1}, evaluated)
+	// Test recalculate after the formula of the cell has been changed
+	evaluated = map[string]int{}
+	assert.NoError(t, g.SetCellFormula("Sheet1", "B1", `TRACE("B1",A1)`))
+	assert.NoError(t, g.Recalculate(true))
+	assert.Equal(t, map[string]int{"B1": 1}, evaluated)
+	evaluated = map[string]int{}
+	assert.NoError(t, g.SetCellValue("Sheet1", "A1", 6))
+	assert.NoError(t, g.Recalculate(true))
+	assert.Equal(t, map[string]int{"A2": 1, "A3": 1, "B1": 1, "C1": 1}, evaluated)
+	for cell, expected := range map[string]string{"A2": "6", "A3": "6", "B1": "6", "C1": "12"} {
+		value, err := g.GetCellValue("Sheet1", cell)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, value, cell)
+	}
+	// Test recalculate and set cell values concurrently
+	g = NewFile()
+	assert.NoError(t, g.SetCellFormula("Sheet1", "F1", "SUM(E1:E10)"))
+	var wg sync.WaitGroup
+	for i := 1; i <= 10; i++ {
+		wg.Add(2)
+		go func(row int) {
+			defer wg.Done()
+			assert.NoError(t, g.SetCellValue("Sheet1", fmt.Sprintf("E%d", row), row))
+		}(i)
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, g.Recalculate(false))
+		}()
+	}
+	wg.Wait()
+	assert.NoError(t, g.Recalculate(false))
+	value, err = g.GetCellValue("Sheet1", "F1")
+	assert.NoError(t, err)
+	assert.Equal(t, "55", value)
+	// Test recalculate with unsupported charset worksheet
+	f.Sheet.Delete("xl/worksheets/sheet1.xml")
+	f.Pkg.Store("xl/worksheets/sheet1.xml", MacintoshCyrillicCharset)
+	f.checked.Delete("xl/worksheets/sheet1.xml")
+	assert.EqualError(t, f.Recalculate(false), "XML syntax error on line 1: invalid UTF-8")
+	f.resetFormulaIndex()
+	assert.EqualError(t, f.Recalculate(true), "XML syntax error on line 1: invalid UTF-8")
+}
+
+func TestCalculate(t *testing.T) {
+	f := NewFile()
+	_, err := f.NewSheet("Sheet2")
+	assert.NoError(t, err)
+	assert.NoError(t, f.SetCellValue("Sheet1", "A1", 2))
+	// The dependent formulas are placed before the precedent formulas
+	for cell, formula := range map[string]string{
+		"Sheet1!B1": "C1*2", "Sheet1!C1": "Sheet2!A1+1", "Sheet2!A1": "Rate*10",
+		"Sheet2!B1": "SUM(Sheet1!B1:C1)",
+	} {
+		ref := strings.Split(cell, "!")
+		assert.NoError(t, f.SetCellFormula(ref[0], ref[1], formula))
+	}
+	assert.NoError(t, f.SetDefinedName(&DefinedName{Name: "Rate", RefersTo: "Sheet1!$A$1"}))
+	assert.NoError(t, f.Calculate())
+	for cell, expected := range map[string]string{
+		"Sheet1!B1": "42", "Sheet1!C1": "21", "Sheet2!A1": "20", "Sheet2!B1": "63",
+	} {
+		ref := strings.Split(cell, "!")
+		value, err := f.GetCellValue(ref[0], ref[1])
+		assert.NoError(t, err)
+		assert.Equal(t, expected, value, cell)
+	}
+	// Test calculate the formulas with circular references without iterative
+	// calculation
+	assert.NoError(t, f.SetCellFormula("Sheet1", "D1", "E1+1"))
+	assert.NoError(t, f.SetCellFormula("Sheet1", "E1", "D1+1"))
+	assert.NoError(t, f.SetCellFormula("Sheet1", "F1", "F1+1"))
+	assert.NoError(t, f.SetCellFormula("Sheet1", "G1", "A1*3"))
+	assert.EqualError(t, f.Calculate(), "circular reference in cells Sheet1!D1, Sheet1!E1, Sheet1!F1")
+	value, err := f.GetCellValue("Sheet1", "G1")
+	assert.NoError(t, err)
+	assert.Equal(t, "6", value)
+	value, err = f.GetCellValue("Sheet1", "D1")
+	assert.NoError(t, err)
+	assert.Empty(t, value)
+	// Test calculate the formulas with circular references by iterative
+	// calculation
+	assert.NoError(t, f.SetCellFormula("Sheet1", "D1", "E1/2+1"))
+	assert.NoError(t, f.SetCellFormula("Sheet1", "E1", "D1"))
+	assert.NoError(t, f.SetCellFormula("Sheet1", "F1", "MIN(F1+1,5)"))
+	assert.NoError(t, f.SetCalcProps(&CalcPropsOptions{
+		Iterate: boolPtr(true), IterateCount: uintPtr(100), IterateDelta: float64Ptr(0.0001),
+	}))
+	assert.NoError(t, f.Calculate())
+	for cell, expected := range map[string]float64{"D1": 2, "E1": 2, "F1": 5} {
+		value, err := f.GetCellValue("Sheet1", cell)
+		assert.NoError(t, err)
+		num, err := strconv.ParseFloat(value, 64)
+		assert.NoError(t, err)
+		assert.InDelta(t, expected, num, 0.001, cell)
+	}
+	// Test calculate the formulas with circular references by the limited
+	// iterations
+	assert.NoError(t, f.SetCalcProps(&CalcPropsOptions{IterateCount: uintPtr(2)}))
+	assert.NoError(t, f.SetCellFormula("Sheet1", "F1", "F1+1"))
+	assert.NoError(t, f.Calculate())
+	value, err = f.GetCellValue("Sheet1", "F1")
+	assert.NoError(t, err)
+	assert.Equal(t, "2", value)
+	// Test calculate with unsupported charset workbook
+	f.WorkBook = nil
+	f.Pkg.Store(defaultXMLPathWorkbook, MacintoshCyrillicCharset)
+	_, err = f.calcFormulas(nil)
+	assert.EqualError(t, err, "XML syntax error on line 1: invalid UTF-8")
+}
+
+func TestGetFormulaCalcOrder(t *testing.T) {
+	newDep := func(sheet string, col, row int, ranges ...cellRange) *formulaDeps {
+		return &formulaDeps{cell: cellRef{Col: col, Row: row, Sheet: sheet}, ranges: ranges}
+	}
+	newRange := func(sheet string, fromCol, fromRow, toCol, toRow int) cellRange {
+		return cellRange{From: cellRef{Col: fromCol, Row: fromRow, Sheet: sheet}, To: cellRef{Col: toCol, Row: toRow, Sheet: sheet}}
+	}
+	deps := []*formulaDeps{
+		newDep("Sheet1", 2, 2, newRange("sheet1", 1, 1, 3, 3)),
+		newDep("Sheet1", 4, 1),
+		newDep("Sheet1", 1, 3),
+		newDep("Sheet1", 3, 1, newRange("Sheet2", 1, 1, 1, 1)),
+		newDep("Sheet2", 1, 1),
+		newDep("Sheet1", 5, 5, newRange("Sheet1", 6, 5, 6, 5)),
+		newDep("Sheet1", 6, 5, newRange("Sheet1", 5, 5, 5, 5)),
+	}
+	assert.Equal(t, []formulaComponent{
+		{deps: []int{5, 6}, circular: true},
+		{deps: []int{4}}, {deps: []int{3}}, {deps: []int{2}}, {deps: []int{1}},
+		{deps: []int{0}, circular: true},
+	}, getFormulaCalcOrder(deps))
+	// Test get the calculation order of the long chain of the formulas
+	deps = make([]*formulaDeps, 100000)
+	for i := range deps {
+		deps[i] = newDep("Sheet1", 1, len(deps)-i, newRange("Sheet1", 1, len(deps)-i+1, 1, len(deps)-i+1))
+	}
+	components := getFormulaCalcOrder(deps)
+	assert.Len(t, components, len(deps))
+	assert.Equal(t, formulaComponent{deps: []int{0}}, components[0])
+	assert.Equal(t, formulaComponent{deps: []int{len(deps) - 1}}, components[len(deps)-1])
+}
+
+func TestFreezeFormulas(t *testing.T) {
+	f := NewFile()
+	_, err := f.NewSheet("Sheet2")
+	assert.NoError(t, err)
+	assert.NoError(t, f.SetSheetCol("Sheet1", "A1", &[]interface{}{1, 2, 3}))
+	ref, shared := "B1:B3", STCellFormulaTypeShared
+	assert.NoError(t, f.SetCellFormula("Sheet1", "B1", "A1*2", FormulaOpts{Ref: &ref, Type: &shared}))
+	assert.NoError(t, f.SetCellFormula("Sheet1", "C1", "CONCAT(\"a\",A1)"))
+	assert.NoError(t, f.SetCellFormula("Sheet1", "D1", "FOOBAR()"))
+	assert.NoError(t, f.SetCellFormula("Sheet2", "A1", "Sheet1!A3+1"))
+	ws, err := f.workSheetReader("Sheet1")
+	assert.NoError(t, err)
+	c, _, _, err := ws.prepareCell("D1")
+	assert.NoError(t, err)
+	c.V = "cached"
+	assert.NoError(t, f.FreezeFormulas("Sheet1", "D1:B1"))
+	for cell, expected := range map[string][]string{
+		"B1": {"2", ""}, "B2": {"", "A2*2"}, "B3": {"", "A3*2"}, "C1": {"a1", ""}, "D1": {"cached", ""},
+	} {
+		value, err := f.GetCellValue("Sheet1", cell)
+		assert.NoError(t, err)
+		assert.Equal(t, expected[0], value, cell)
+		formula, err := f.GetCellFormula("Sheet1", cell)
+		assert.NoError(t, err)
+		assert.Equal(t, expected[1], formula, cell)
+	}
+	cellType, err := f.GetCellType("Sheet1", "C1")
+	assert.NoError(t, err)
+	assert.Equal(t, CellTypeSharedString, cellType)
+	// Test freeze all formulas in the workbook
+	assert.NoError(t, f.FreezeFormulas("", ""))
+	for _, cell := range []string{"Sheet1!B2", "Sheet1!B3", "Sheet2!A1"} {
+		ref := strings.Split(cell, "!")
+		formula, err := f.GetCellFormula(ref[0], ref[1])
+		assert.NoError(t, err)
+		assert.Empty(t, formula, cell)
+	}
+	value, err := f.GetCellValue("Sheet2", "A1")
+	assert.NoError(t, err)
+	assert.Equal(t, "4", value)
+	// Test freeze formulas with invalid range reference
+	assert.Equal(t, newCellNameToCoordinatesError("A", newInvalidCellNameError("A")), f.FreezeFormulas("Sheet1", "A:B1"))
+	// Test freeze formulas on not exists worksheet
+	assert.EqualError(t, f.FreezeFormulas("SheetN", ""), "sheet SheetN does not exist")
+	// Test freeze formulas with unsupported charset calculation chain
+	f.CalcChain = nil
+	f.Pkg.Store(defaultXMLPathCalcChain, MacintoshCyrillicCharset)
+	assert.NoError(t, f.SetCellFormula("Sheet1", "E1", "A1"))
+	assert.EqualError(t, f.FreezeFormulas("Sheet1", "E1"), "XML syntax error on line 1: invalid UTF-8")
+	// Test freeze formulas on the workbook with unsupported charset worksheet
+	f.Sheet.Delete("xl/worksheets/sheet2.xml")
+	f.Pkg.Store("xl/worksheets/sheet2.xml", MacintoshCyrillicCharset)
+	f.checked.Delete("xl/worksheets/sheet2.xml")
+	assert.EqualError(t, f.FreezeFormulas("", ""), "XML syntax error on line 1: invalid UTF-8")
+
+	// Test freeze formulas and set the string cell values concurrently
+	f = NewFile()
+	var wg sync.WaitGroup
+	for i := 1; i <= 10; i++ {
+		assert.NoError(t, f.SetCellFormula("Sheet1", fmt.Sprintf("A%d", i), fmt.Sprintf("CONCAT(\"a\",%d)", i)))
+	}
+	for i := 1; i <= 10; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			assert.NoError(t, f.FreezeFormulas("Sheet1", fmt.Sprintf("A%d", i)))
+		}(i)
+		go func(i int) {
+			defer wg.Done()
+			assert.NoError(t, f.SetCellValue("Sheet1", fmt.Sprintf("B%d", i), fmt.Sprintf("b%d", i)))
+		}(i)
+	}
+	wg.Wait()
+	for i := 1; i <= 10; i++ {
+		cellType, err := f.GetCellType("Sheet1", fmt.Sprintf("A%d", i))
+		assert.NoError(t, err)
+		assert.Equal(t, CellTypeSharedString, cellType)
+		value, err := f.GetCellValue("Sheet1", fmt.Sprintf("A%d", i))
+		assert.NoError(t, err)
+		assert.Equal(t, fmt.Sprintf("a%d", i), value)
+	}
+	assert.NoError(t, f.Close())
+}
+
+func TestGetReferenceIssues(t *testing.T) {
+	f := NewFile()
+	for cell, formula := range map[string]string{
+		"A1": "Sheet1!#REF!+1", "A2": "SUM(Missing!A1:A2)", "A3": "[1]Sheet1!A1", "A5": "SUM('Sheet 2'!A1,Table1[Col])",
+		"B1": "C1+1", "C1": "B1+1", "D1": "D1", "E1": "SUM(A1:A2)",
+	} {
+		assert.NoError(t, f.SetCellFormula("Sheet1", cell, formula))
+	}
+	ws, err := f.workSheetReader("Sheet1")
+	assert.NoError(t, err)
+	for cell, formula := range map[string]bool{"A4": false, "A6": true} {
+		c, _, _, err := ws.prepareCell(cell)
+		assert.NoError(t, err)
+		if c.T, c.V = "e", formulaErrorREF; formula {
+			c.F = &xlsxF{Content: "INDEX(E1:E2,3)"}
+		}
+	}
+	wb, err := f.workbookReader()
+	assert.NoError(t, err)
+	wb.ExternalReferences = &xlsxExternalReferences{ExternalReference: []xlsxExternalReference{{RID: "rId99"}}}
+	wb.DefinedNames = &xlsxDefinedNames{DefinedName: []xlsxDefinedName{
+		{Name: "Broken", Data: "#REF!"}, {Name: "Local", Data: "'My Sheet'!$A$1", LocalSheetID: intPtr(0)},
+	}}
+	issues, err := f.GetReferenceIssues()
+	assert.NoError(t, err)
+	var results []string
+	for _, issue := range issues {
+		assert.NotEmpty(t, issue.Suggestion)
+		results = append(results, fmt.Sprintf("%d|%s|%s|%s|%s", issue.Type, issue.Sheet, issue.Cell, issue.DefinedName, issue.Reference))
+	}
+	assert.Equal(t, []string{
+		"2||||[1]",
+		"0|Sheet1|A1||#REF!",
+		"1|Sheet1|A2||Missing!A1:A2",
+		"2|Sheet1|A3||[1]Sheet1!A1",
+		"0|Sheet1|A4||#REF!",
+		"1|Sheet1|A5||Sheet 2!A1",
+		"0|Sheet1|A6||#REF!",
+		"0|||Broken|#REF!",
+		"1|Sheet1||Local|My Sheet!$A$1",
+		"3|Sheet1|B1||Sheet1!B1,Sheet1!C1",
+		"3|Sheet1|C1||Sheet1!B1,Sheet1!C1",
+		"3|Sheet1|D1||Sheet1!D1",
+	}, results)
+	// Test get reference issues with unsupported charset workbook relationships
+	f.Relationships.Delete(defaultXMLPathWorkbookRels)
+	f.Pkg.Store(defaultXMLPathWorkbookRels, MacintoshCyrillicCharset)
+	_, err = f.GetReferenceIssues()
+	assert.EqualError(t, err, "XML syntax error on line 1: invalid UTF-8")
+	// Test get reference issues with unsupported charset workbook
+	f = NewFile()
+	f.WorkBook = nil
+	f.Pkg.Store(defaultXMLPathWorkbook, MacintoshCyrillicCharset)
+	_, err = f.GetReferenceIssues()
+	assert.EqualError(t, err, "XML syntax error on line 1: invalid UTF-8")
+	// Test get reference issues with unsupported charset worksheet
+	f = NewFile()
+	f.Sheet.Delete("xl/worksheets/sheet1.xml")
+	f.Pkg.Store("xl/worksheets/sheet1.xml", MacintoshCyrillicCharset)
+	f.checked.Delete("xl/worksheets/sheet1.xml")
+	_, err = f.GetReferenceIssues()
+	assert.EqualError(t, err, "XML syntax error on line 1: invalid UTF-8")
+}
+
+func TestGetFormulaRefRanges(t *testing.T) {
+	f := NewFile()
+	for _, ref := range []string{"[1]Sheet1!A1", "SheetN:Sheet1!A1", "A1:B2:C3", "A1:XYZ", "Name"} {
+		_, ok := f.getFormulaRefRanges("Sheet1", ref)
+		assert.False(t, ok, ref)
+	}
+	ranges, ok := f.getFormulaRefRanges("Sheet1", "$2:$1")
+	assert.True(t, ok)
+	assert.Equal(t, []cellRange{{From: cellRef{Col: 1, Row: 1, Sheet: "Sheet1"}, To: cellRef{Col: MaxColumns, Row: 2, Sheet: "Sheet1"}}}, ranges)
+	_, err := f.NewSheet("Sheet2")
+	assert.NoError(t, err)
+	ranges, ok = f.getFormulaRefRanges("Sheet1", "Sheet2:Sheet1!A5")
+	assert.True(t, ok)
+	assert.Equal(t, []cellRange{
+		{From: cellRef{Col: 1, Row: 5, Sheet: "Sheet1"}, To: cellRef{Col: 1, Row: 5, Sheet: "Sheet1"}},
+		{From: cellRef{Col: 1, Row: 5, Sheet: "Sheet2"}, To: cellRef{Col: 1, Row: 5, Sheet: "Sheet2"}},
+	}, ranges)
+	// Test get formula dependencies with circular defined names
+	assert.NoError(t, f.SetDefinedName(&DefinedName{Name: "Loop", RefersTo: "Loop+1"}))
+	dep := &formulaDeps{sheets: map[string]struct{}{}}
+	f.getFormulaDeps(dep, "Sheet1", "Loop", 0)
+	assert.True(t, dep.volatile)
+}
+
+func TestCalcStructuredReference(t *testing.T) {
+	f := NewFile()
+	for r, row := range [][]interface{}{
+		{"Region", "Sales", "Cost", "Margin"},
+		{"East", 100, 60},
+		{"West", 200, 150},
+		{"East", 300, 120},
+		{"Total", 600, 330},
+	} {
+		assert.NoError(t, f.SetSheetRow("Sheet1", fmt.Sprintf("A%d", r+1), &row))
+	}
+	assert.NoError(t, f.AddTable("Sheet1", &Table{Range: "A1:D5", Name: "Sales"}))
+	tbl, _, err := f.getStructuredReferenceTable("Sheet1", "A1", "Sales")
+	assert.NoError(t, err)
+	tbl.TotalsRowCount = 1
+	table, err := xml.Marshal(tbl)
+	assert.NoError(t, err)
+	f.Pkg.Store("xl/tables/table1.xml", table)
+	_, err = f.NewSheet("Sheet 2")
+	assert.NoError(t, err)
+	for formula, expected := range map[string]string{
+		"=SUM(Sales[Sales])":                          "600",
+		"=SUM(sales[[#All],[Sales]])":                 "1200",
+		"=SUM(Sales[[#Data],[Sales]:[Cost]])":         "930",
+		"=SUM(Sales[[#Totals],[Sales]])":              "600",
+		"=COUNTA(Sales[#Headers])":                    "4",
+		"=COUNTA(Sales[[#All],[Region]:[Cost]])":      "15",
+		"=Sales[[#Headers],[Cost]]&\"[x]\"":           "Cost[x]",
+		"=SUMIF(Sales[Region],\"East\",Sales[Sales])": "400",
+	} {
+		assert.NoError(t, f.SetCellFormula("Sheet 2", "A1", formula))
+		result, err := f.CalcCellValue("Sheet 2", "A1")
+		assert.NoError(t, err, formula)
+		assert.Equal(t, expected, result, formula)
+	}
+	for cell, formula := range map[string]string{
+		"D3": "=[@Sales]-[@Cost]",
+		"E3": "=Sales[@Sales]*2",
+		"F3": "=Sales[[#This Row],[Cost]]",
+	} {
+		assert.NoError(t, f.SetCellFormula("Sheet1", cell, formula))
+	}
+	for cell, expected := range map[string]string{"D3": "50", "E3": "400", "F3": "150"} {
+		result, err := f.CalcCellValue("Sheet1", cell)
+		assert.NoError(t, err, cell)
+		assert.Equal(t, expected, result, cell)
+	}
+	// Test calculate structured references with invalid table, column and row
+	for formula, expected := range map[string]string{
+		"=SUM(SalesN[Sales])":                  "#REF!",
+		"=SUM(Sales[SalesN])":                  "#REF!",
+		"=SUM(Sales[#Invalid])":                "#REF!",
+		"=SUM(Sales[[Sales]:[Cost]:[Region]])": "#REF!",
+		"=SUM(Sales[[Sales],Cost])":            "#REF!",
+		"=Sales[@Sales]":                       "#VALUE!",
+		"=[@Sales]":                            "#REF!",
+	} {
+		assert.NoError(t, f.SetCellFormula("Sheet1", "A8", formula))
+		result, err := f.CalcCellValue("Sheet1", "A8")
+		assert.EqualError(t, err, expected, formula)
+		assert.Equal(t, expected, result, formula)
+	}
+	// Test calculate structured reference in the table without header row
+	tbl.HeaderRowCount = intPtr(0)
+	table, err = xml.Marshal(tbl)
+	assert.NoError(t, err)
+	f.Pkg.Store("xl/tables/table1.xml", table)
+	assert.NoError(t, f.SetCellFormula("Sheet1", "A8", "=COUNTA(Sales[#Headers])"))
+	result, err := f.CalcCellValue("Sheet1", "A8")
+	assert.EqualError(t, err, "#REF!")
+	assert.Equal(t, "#REF!", result)
+	// Test calculate structured reference with unsupported charset table
+	f.Pkg.Store("xl/tables/table1.xml", MacintoshCyrillicCharset)
+	assert.NoError(t, f.SetCellFormula("Sheet1", "A8", "=SUM(Sales[Sales])"))
+	_, err = f.CalcCellValue("Sheet1", "A8")
+	assert.EqualError(t, err, "XML syntax error on line 1: invalid UTF-8")
+	// Test calculate the formula with external workbook reference
+	assert.NoError(t, f.SetCellFormula("Sheet1", "A8", "=[1]Sheet1!A1"))
+	_, err = f.CalcCellValue("Sheet1", "A8")
+	assert.NotEqual(t, "XML syntax error on line 1: invalid UTF-8", fmt.Sprint(err))
+}
+
+func TestCalcGETPIVOTDATA(t *testing.T) {
+	f := NewFile()
+	for r, row := range [][]interface{}{
+		{"Month", "Year", "Type", "Sales"},
+		{"Jan", 2017, "Meat", 100},
+		{"Feb", 2017, "Dairy", 200},
+		{"Jan", 2018, "Meat", 300},
+		{"Jan", 2018, "Dairy", 400},
+	} {
+		assert.NoError(t, f.SetSheetRow("Sheet1", fmt.Sprintf("A%d", r+1), &row))
+	}
+	assert.NoError(t, f.AddPivotTable(&PivotTableOptions{
+		DataRange:       "Sheet1!A1:D5",
+		PivotTableRange: "Sheet1!G2:J10",
+		Rows:            []PivotTableField{{Data: "Month"}, {Data: "Year"}},
+		Columns:         []PivotTableField{{Data: "Type"}},
+		Data:            []PivotTableField{{Data: "Sales", Name: "Total Sales"}},
+	}))
+	assert.NoError(t, f.AddPivotTable(&PivotTableOptions{
+		DataRange:       "Sheet1!A1:D5",
+		PivotTableRange: "Sheet1!L2:N10",
+		Rows:            []PivotTableField{{Data: "Month"}},
+		Data:            []PivotTableField{{Data: "Sales", Subtotal: "Average"}},
+	}))
+	for formula, expected := range map[string]string{
+		"=GETPIVOTDATA(\"Sales\",G2)":                                        "1000",
+		"=GETPIVOTDATA(\"Total Sales\",$H$5)":                                "1000",
+		"=GETPIVOTDATA(\"Sales\",G2:H3,\"Month\",\"jan\")":                   "800",
+		"=GETPIVOTDATA(\"Sales\",Sheet1!G2,\"Month\",\"Jan\",\"Year\",2018)": "700",
+		"=GETPIVOTDATA(\"Sales\",G2,\"Type\",\"Dairy\",\"Year\",\"2017\")":   "200",
+		"=GETPIVOTDATA(\"Sales\",L2,\"Month\",\"Jan\")":                      "266.666666666667",
+	} {
+		assert.NoError(t, f.SetCellFormula("Sheet1", "F1", formula))
+		result, err := f.CalcCellValue("Sheet1", "F1")
+		assert.NoError(t, err, formula)
+		assert.Equal(t, expected, result, formula)
+	}
+	for formula, expected := range map[string][]string{
+		"=GETPIVOTDATA(\"Sales\")":                      {"#VALUE!", "GETPIVOTDATA requires at least 2 arguments"},
+		"=GETPIVOTDATA(\"Sales\",G2,\"Month\")":         {"#VALUE!", "GETPIVOTDATA requires pairs of field and item arguments"},
+		"=GETPIVOTDATA(\"Sales\",\"G2\")":               {"#REF!", "GETPIVOTDATA requires reference to the pivot table"},
+		"=GETPIVOTDATA(\"Sales\",A1)":                   {"#REF!", "GETPIVOTDATA requires reference to the pivot table"},
+		"=GETPIVOTDATA(\"Cost\",G2)":                    {"#REF!", "GETPIVOTDATA requires valid data field name"},
+		"=GETPIVOTDATA(\"Sales\",G2,\"Sales\",100)":     {"#REF!", "GETPIVOTDATA requires valid field name"},
+		"=GETPIVOTDATA(\"Sales\",G2,\"Month\",\"Mar\")": {"#REF!", "GETPIVOTDATA no result found"},
+	} {
+		assert.NoError(t, f.SetCellFormula("Sheet1", "F1", formula))
+		result, err := f.CalcCellValue("Sheet1", "F1")
+		assert.EqualError(t, err, expected[1], formula)
+		assert.Equal(t, expected[0], result, formula)
+	}
+	// Test get pivot data with not exists worksheet
+	fn := formulaFuncs{f: f, sheet: "Sheet1", cell: "F1"}
+	_, arg := fn.getPivotTableByRef(formulaArg{cellRefs: list.New()})
+	assert.Equal(t, formulaErrorREF, arg.String)
+	refs := list.New()
+	refs.PushBack(cellRef{Sheet: "SheetN", Col: 7, Row: 2})
+	_, arg = fn.getPivotTableByRef(formulaArg{cellRefs: refs})
+	assert.Equal(t, "sheet SheetN does not exist", arg.Error)
+}
@@ -15,6 +15,8 @@ import (
 	"bytes"
 	"encoding/xml"
 	"fmt"
+	"math"
+	"math/big"
 	"os"
 	"reflect"
 	"strconv"
@@ -117,6 +119,10 @@ func (f *File) GetCellType(sheet, cell string) (CellType, error) {
 //	time.Time
 //	bool
 //	nil
+//	*big.Int
+//	*big.Rat
+//	*big.Float
+//	Decimal
 //
 // Note that default date format is m/d/yy h:mm of time.Time type value. You
 // can set numbers format by the SetCellStyle function. If you need to set the
@@ -124,7 +130,35 @@ func (f *File) GetCellType(sheet, cell string) (CellType, error) {
 // times can not representation in Go language time.Time data type. Please set
 // the cell value as number 0 or 60, then create and bind the date-time number
 // format style for the cell.
-func (f *File) SetCellValue(sheet, cell string, value interface{}) error {
+//
+// The string value begins with '=', '+', '-' or '@' character will be escaped
+// by prefixing an apostrophe when the EscapeFormula field of the workbook
+// options is enabled, which prevents formula injection when writing untrusted
+// data. Use the optional CellValueOpts to override this setting for a call.
+// For example, keep the original value for the cell:
+//
+//	escape := false
+//	err := f.SetCellValue("Sheet1", "A1", "=SUM(A2:A3)", excelize.CellValueOpts{
+//	    EscapeFormula: &escape,
+//	})
+//
+// The big number types and the Decimal will be written as numbers without
+// precision loss if they have no more than 15 significant digits, which is
+// the precision limit of the spreadsheet applications. Otherwise, the value
+// will be rounded to 15 significant digits, or written as text when the
+// BigNumberAsText field of the workbook options or cell value options is
+// enabled. The Precision field of the CellValueOpts specifies the number of
+// digits after the decimal point for these types, the value will be rounded
+// half away from zero. The non-terminating decimals will be rounded to 15
+// significant digits if the precision was not specified. For example, write
+// an amount rounded to 2 decimal places:
+//
+//	precision := 2
+//	err := f.SetCellValue("Sheet1", "A1", big.NewRat(10, 3), excelize.CellValueOpts{
+//	    Precision: &precision,
+//	})
+func (f *File) SetCellValue(sheet, cell string, value interface{}, opts ...CellValueOpts) error {
+	defer f.dispatchWatchEvents()
 	var err error
 	switch v := value.(type) {
 	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
@@ -134,9 +168,9 @@ func (f *File) SetCellValue(sheet, cell string, value interface{}) error {
 	case float64:
 		err = f.SetCellFloat(sheet, cell, v, -1, 64)
 	case string:
-		err = f.SetCellStr(sheet, cell, v)
+		err = f.setCellStrFunc(sheet, cell, f.escapeFormula(v, opts...))
 	case []byte:
-		err = f.SetCellStr(sheet, cell, string(v))
+		err = f.setCellStrFunc(sheet, cell, f.escapeFormula(string(v), opts...))
 	case time.Duration:
 		_, d := setCellDuration(v)
 		err = f.SetCellDefault(sheet, cell, d)
@@ -150,12 +184,151 @@ func (f *File) SetCellValue(sheet, cell string, value interface{}) error {
 		err = f.SetCellBool(sheet, cell, v)
 	case nil:
 		err = f.SetCellDefault(sheet, cell, "")
+	case *big.Int, *big.Rat, *big.Float, Decimal:
+		var (
+			val    string
+			isText bool
+		)
+		if val, isText, err = f.formatBigNumber(v, opts...); err != nil {
+			return err
+		}
+		if isText {
+			return f.setCellStrFunc(sheet, cell, val)
+		}
+		err = f.SetCellDefault(sheet, cell, val)
 	default:
-		err = f.SetCellStr(sheet, cell, fmt.Sprint(value))
+		err = f.setCellStrFunc(sheet, cell, f.escapeFormula(fmt.Sprint(value), opts...))
 	}
 	return err
 }
 
+// CellValueOpts can be passed to SetCellValue to override the workbook
+// options for setting the cell value. The Precision specifies the number of
+// digits after the decimal point for the big number types and the Decimal.
+type CellValueOpts struct {
+	EscapeFormula   *bool
+	BigNumberAsText *bool
+	Precision       *int
+}
+
+// Decimal is the interface implemented by the decimal number types which
+// could be converted to a rational number without precision loss, such as the
+// Decimal type of the github.com/shopspring/decimal package.
+type Decimal interface {
+	Rat() *big.Rat
+}
+
+// formatBigNumber provides a function to convert the big number types and the
+// Decimal to the cell value by the precision rules, and returns whether the
+// value should be written as text.
+func (f *File) formatBigNumber(value interface{}, opts ...CellValueOpts) (string, bool, error) {
+	var (
+		asText    bool
+		precision = -1
+		r         *big.Rat
+	)
+	if f.options != nil {
+		asText = f.options.BigNumberAsText
+	}
+	for _, opt := range opts {
+		if opt.BigNumberAsText != nil {
+			asText = *opt.BigNumberAsText
+		}
+		if opt.Precision != nil {
+			precision = *opt.Precision
+		}
+	}
+	switch v := value.(type) {
+	case *big.Int:
+		if v != nil {
+			r = new(big.Rat).SetInt(v)
+		}
+	case *big.Rat:
+		r = v
+	case *big.Float:
+		if v != nil {
+			if v.IsInf() {
+				return "", false, ErrParameterInvalid
+			}
+			r, _ = v.Rat(nil)
+		}
+	case Decimal:
+		if rv := reflect.ValueOf(v); rv.Kind() != reflect.Ptr || !rv.IsNil() {
+			r = v.Rat()
+		}
+	}
+	if r == nil {
+		return "", false, nil
+	}
+	if precision >= 0 {
+		r, _ = new(big.Rat).SetString(r.FloatString(precision))
+	}
+	places, ok := getDecimalPlaces(r.Denom())
+	if ok {
+		val := r.FloatString(places)
+		if asText && countSignificantDigits(val) > 15 {
+			return val, true, nil
+		}
+		if countSignificantDigits(val) <= 15 {
+			return val, false, nil
+		}
+	}
+	num, _ := r.Float64()
+	num, _ = strconv.ParseFloat(strconv.FormatFloat(num, 'g', 15, 64), 64)
+	return strconv.FormatFloat(num, 'f', -1, 64), false, nil
+}
+
+// getDecimalPlaces returns the number of decimal places of the terminating
+// decimal by given denominator of the rational number, and returns false if
+// it's a non-terminating decimal.
+func getDecimalPlaces(denom *big.Int) (int, bool) {
+	d := new(big.Int).Set(denom)
+	twos := int(d.TrailingZeroBits())
+	d.Rsh(d, uint(twos))
+	var fives int
+	five, mod := big.NewInt(5), new(big.Int)
+	for {
+		q, m := new(big.Int).QuoRem(d, five, mod)
+		if m.Sign() != 0 {
+			break
+		}
+		d, fives = q, fives+1
+	}
+	if d.Cmp(big.NewInt(1)) != 0 {
+		return 0, false
+	}
+	if twos > fives {
+		return twos, true
+	}
+	return fives, true
+}
+
+// countSignificantDigits returns the number of significant digits by given
+// decimal string, the leading and trailing zeros are not significant.
+func countSignificantDigits(val string) int {
+	val = strings.TrimLeft(strings.ReplaceAll(strings.TrimPrefix(val, "-"), ".", ""), "0")
+	return len(strings.TrimRight(val, "0"))
+}
+
+// escapeFormula provides a function to prefix an apostrophe for the string
+// value which begins with the formula trigger characters, if the formula
+// escaping was enabled by the workbook options or cell value options.
+func (f *File) escapeFormula(value string, opts ...CellValueOpts) string {
+	var escape bool
+	if f.options != nil {
+		escape = f.options.EscapeFormula
+	}
+	for _, opt := range opts {
+		if opt.EscapeFormula != nil {
+			escape = *opt.EscapeFormula
+		}
+	}
+	if escape && value != "" && strings.ContainsRune("=+-@", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
+
 // String extracts characters from a string item.
 func (x xlsxSI) String() string {
 	var value strings.Builder
@@ -178,8 +351,25 @@ func (c *xlsxC) hasValue() bool {
 	return c.S != 0 || c.V != "" || c.F != nil || c.T != ""
 }
 
+// setCellChanged provides a function to mark the cell as changed for the
+// partial recalculation by given worksheet name and cell reference.
+func (f *File) setCellChanged(sheet, cell string) {
+	if col, row, err := CellNameToCoordinates(cell); err == nil {
+		f.changedCells.Store(cellRef{Col: col, Row: row, Sheet: sheet}, struct{}{})
+		f.addWatchEvents(sheet, []int{col, row, col, row})
+	}
+}
+
+// resetFormulaIndex provides a function to mark the dependency index of the
+// formulas to be rebuilt on the next partial recalculation, after the
+// worksheets or defined names of the workbook have been changed.
+func (f *File) resetFormulaIndex() {
+	f.changedCells.Store(cellRef{}, struct{}{})
+}
+
 // removeFormula delete formula for the cell.
 func (f *File) removeFormula(c *xlsxC, ws *xlsxWorksheet, sheet string) error {
+	f.setCellChanged(sheet, c.R)
 	if c.F != nil && c.Vm == nil {
 		sheetID := f.getSheetID(sheet)
 		if err := f.deleteCalcChain(sheetID, c.R); err != nil {
@@ -254,6 +444,7 @@ func (f *File) setCellTimeFunc(sheet, cell string, value time.Time) error {
 	if isNum, err = c.setCellTime(value, date1904); err != nil {
 		return err
 	}
+	f.setCellChanged(sheet, cell)
 	if isNum {
 		_ = f.setDefaultTimeStyle(sheet, cell, 22)
 	}
@@ -288,6 +479,7 @@ func setCellDuration(value time.Duration) (t string, v string) {
 // SetCellInt provides a function to set int type value of a cell by given
 // worksheet name, cell reference and cell value.
 func (f *File) SetCellInt(sheet, cell string, value int) error {
+	defer f.dispatchWatchEvents()
 	f.mu.Lock()
 	ws, err := f.workSheetReader(sheet)
 	if err != nil {
@@ -316,6 +508,7 @@ func setCellInt(value int) (t string, v string) {
 // SetCellUint provides a function to set uint type value of a cell by given
 // worksheet name, cell reference and cell value.
 func (f *File) SetCellUint(sheet, cell string, value uint64) error {
+	defer f.dispatchWatchEvents()
 	f.mu.Lock()
 	ws, err := f.workSheetReader(sheet)
 	if err != nil {
@@ -345,6 +538,7 @@ func setCellUint(value uint64) (t string, v string) {
 // SetCellBool provides a function to set bool type value of a cell by given
 // worksheet name, cell reference and cell value.
 func (f *File) SetCellBool(sheet, cell string, value bool) error {
+	defer f.dispatchWatchEvents()
 	f.mu.Lock()
 	ws, err := f.workSheetReader(sheet)
 	if err != nil {
@@ -385,6 +579,7 @@ func setCellBool(value bool) (t string, v string) {
 //	var x float32 = 1.325
 //	f.SetCellFloat("Sheet1", "A1", float64(x), 2, 32)
 func (f *File) SetCellFloat(sheet, cell string, value float64, precision, bitSize int) error {
+	defer f.dispatchWatchEvents()
 	f.mu.Lock()
 	ws, err := f.workSheetReader(sheet)
 	if err != nil {
@@ -399,7 +594,19 @@ func (f *File) SetCellFloat(sheet, cell string, value float64, precision, bitSiz
 		return err
 	}
 	c.S = ws.prepareCellStyle(col, row, c.S)
-	c.T, c.V = setCellFloat(value, precision, bitSize)
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		var isText bool
+		if c.T, c.V, isText, err = f.formatNonFiniteFloat(value); err != nil {
+			return err
+		}
+		if isText {
+			if c.T, c.V, err = f.setCellString(c.V); err != nil {
+				return err
+			}
+		}
+	} else {
+		c.T, c.V = setCellFloat(value, precision, bitSize)
+	}
 	c.IS = nil
 	return f.removeFormula(c, ws, sheet)
 }
@@ -411,9 +618,49 @@ func setCellFloat(value float64, precision, bitSize int) (t string, v string) {
 	return
 }
 
+// NonFinitePolicy is the type of the behavior on writing the NaN or infinity
+// float values.
+type NonFinitePolicy byte
+
+// This section defines the currently supported behaviors enumeration on
+// writing the NaN or infinity float values.
+const (
+	NonFinitePolicyError NonFinitePolicy = iota
+	NonFinitePolicyBlank
+	NonFinitePolicyNumError
+	NonFinitePolicyString
+)
+
+// formatNonFiniteFloat provides a function to convert the NaN or infinity
+// float value to the cell type and value by the NonFinitePolicy of the
+// workbook options, and returns whether the value should be written as text.
+func (f *File) formatNonFiniteFloat(value float64) (t, v string, isText bool, err error) {
+	var policy NonFinitePolicy
+	if f.options != nil {
+		policy = f.options.NonFinitePolicy
+	}
+	switch policy {
+	case NonFinitePolicyBlank:
+	case NonFinitePolicyNumError:
+		t, v = "e", formulaErrorNUM
+	case NonFinitePolicyString:
+		v, isText = strconv.FormatFloat(value, 'f', -1, 64), true
+	default:
+		err = ErrNonFiniteNumber
+	}
+	return
+}
+
 // SetCellStr provides a function to set string type value of a cell. Total
 // number of characters that a cell can contain 32767 characters.
 func (f *File) SetCellStr(sheet, cell, value string) error {
+	defer f.dispatchWatchEvents()
+	return f.setCellStrFunc(sheet, cell, f.escapeFormula(value))
+}
+
+// setCellStrFunc provides a function to set string type value of a cell
+// without escaping the value.
+func (f *File) setCellStrFunc(sheet, cell, value string) error {
 	f.mu.Lock()
 	ws, err := f.workSheetReader(sheet)
 	if err != nil {
@@ -432,7 +679,13 @@ func (f *File) SetCellStr(sheet, cell, value string) error {
 		return err
 	}
 	c.IS = nil
-	return f.removeFormula(c, ws, sheet)
+	if err = f.removeFormula(c, ws, sheet); err != nil {
+		return err
+	}
+	if f.options != nil && f.options.AutoFitRowHeight {
+		return f.fitWrappedTextRowHeight(ws, col, row, value, c.S)
+	}
+	return err
 }
 
 // setCellString provides a function to set string type to shared string table.
@@ -451,9 +704,16 @@ func (f *File) setCellString(value string) (t, v string, err error) {
 
 // sharedStringsLoader load shared string table from system temporary file to
 // memory, and reset shared string table for reader.
-func (f *File) sharedStringsLoader() (err error) {
+func (f *File) sharedStringsLoader() error {
 	f.mu.Lock()
 	defer f.mu.Unlock()
+	return f.loadSharedStrings()
+}
+
+// loadSharedStrings provides a function to load shared string table from
+// system temporary file to memory without acquiring the workbook lock, the
+// caller should hold the lock.
+func (f *File) loadSharedStrings() (err error) {
 	if path, ok := f.tempFiles.Load(defaultXMLPathSharedStrings); ok {
 		f.Pkg.Store(defaultXMLPathSharedStrings, f.readBytes(defaultXMLPathSharedStrings))
 		f.tempFiles.Delete(defaultXMLPathSharedStrings)
@@ -468,11 +728,63 @@ func (f *File) sharedStringsLoader() (err error) {
 		}
 		f.tempFiles.Delete(defaultTempFileSST)
 		f.sharedStringItem, err = nil, os.Remove(f.sharedStringTemp.Name())
-		f.sharedStringTemp = nil
+		f.sharedStringTemp, f.sharedStringCache = nil, nil
 	}
 	return
 }
 
+// pruneSharedStrings provides a function to remove the shared strings which
+// not referenced by the cells of worksheets, and update the shared string
+// index of the cells.
+func (f *File) pruneSharedStrings() error {
+	if err := f.sharedStringsLoader(); err != nil {
+		return err
+	}
+	sst, err := f.sharedStringsReader()
+	if err != nil {
+		return err
+	}
+	sst.mu.Lock()
+	defer sst.mu.Unlock()
+	var (
+		count   int
+		items   []xlsxSI
+		indexes = map[int]int{}
+	)
+	if err = f.rangeWorksheets(func(sheet string, ws *xlsxWorksheet) error {
+		for rowIdx := range ws.SheetData.Row {
+			for colIdx := range ws.SheetData.Row[rowIdx].C {
+				c := &ws.SheetData.Row[rowIdx].C[colIdx]
+				if c.T != "s" || c.V == "" {
+					continue
+				}
+				idx, err := strconv.Atoi(strings.TrimSpace(c.V))
+				if err != nil || idx < 0 || idx >= len(sst.SI) {
+					continue
+				}
+				newIdx, ok := indexes[idx]
+				if !ok {
+					newIdx, indexes[idx] = len(items), len(items)
+					items = append(items, sst.SI[idx])
+				}
+				c.V = strconv.Itoa(newIdx)
+				count++
+			}
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+	sst.SI, sst.Count, sst.UniqueCount = items, count, len(items)
+	f.sharedStringsMap = make(map[string]int)
+	for i := range sst.SI {
+		if sst.SI[i].T != nil {
+			f.sharedStringsMap[sst.SI[i].T.Val] = i
+		}
+	}
+	return err
+}
+
 // setSharedString provides a function to add string to the share string table.
 func (f *File) setSharedString(val string) (int, error) {
 	if err := f.sharedStringsLoader(); err != nil {
@@ -639,6 +951,7 @@ func (c *xlsxC) getValueFrom(f *File, d *xlsxSST, raw bool) (string, error) {
 // SetCellDefault provides a function to set string type value of a cell as
 // default format without escaping the cell.
 func (f *File) SetCellDefault(sheet, cell, value string) error {
+	defer f.dispatchWatchEvents()
 	f.mu.Lock()
 	ws, err := f.workSheetReader(sheet)
 	if err != nil {
@@ -684,6 +997,9 @@ type FormulaOpts struct {
 // can get the calculated cell value. If the Excel application doesn't
 // calculate the formula automatically when the workbook has been opened,
 // please call "UpdateLinkedValue" after setting the cell formula functions.
+// The references to the external workbooks by the file name, such as
+// [Budget.xlsx]Sheet1!A1, will be converted to use the index of the external
+// workbook links, and the links will be added if not exist.
 //
 // Example 1, set normal formula "=SUM(A1,B1)" for the cell "A3" on "Sheet1":
 //
@@ -760,6 +1076,7 @@ type FormulaOpts struct {
 //	    }
 //	}
 func (f *File) SetCellFormula(sheet, cell, formula string, opts ...FormulaOpts) error {
+	defer f.dispatchWatchEvents()
 	ws, err := f.workSheetReader(sheet)
 	if err != nil {
 		return err
@@ -768,11 +1085,14 @@ func (f *File) SetCellFormula(sheet, cell, formula string, opts ...FormulaOpts)
 	if err != nil {
 		return err
 	}
+	f.setCellChanged(sheet, cell)
 	if formula == "" {
 		c.F = nil
 		return f.deleteCalcChain(f.getSheetID(sheet), cell)
 	}
-
+	if formula, err = f.setExternalLinkFormula(formula); err != nil {
+		return err
+	}
 	if c.F != nil {
 		c.F.Content = formula
 	} else {
@@ -789,6 +1109,7 @@ func (f *File) SetCellFormula(sheet, cell, formula string, opts ...FormulaOpts)
 				if err = ws.setSharedFormula(*opt.Ref); err != nil {
 					return err
 				}
+				f.resetFormulaIndex()
 			}
 		}
 		if opt.Ref != nil {
@@ -836,7 +1157,8 @@ func (ws *xlsxWorksheet) countSharedFormula() (count int) {
 // GetCellHyperLink gets a cell hyperlink based on the given worksheet name and
 // cell reference. If the cell has a hyperlink, it will return 'true' and
 // the link address, otherwise it will return 'false' and an empty link
-// address.
+// address. The hyperlink defined over a cell range or a merged cell will be
+// returned when querying any cell covered by it.
 //
 // For example, get a hyperlink to a 'H6' cell on a worksheet named 'Sheet1':
 //
@@ -850,7 +1172,14 @@ func (f *File) GetCellHyperLink(sheet, cell string) (bool, string, error) {
 	if err != nil {
 		return false, "", err
 	}
-	if ws.Hyperlinks != nil {
+	if ws.Hyperlinks == nil {
+		return false, "", err
+	}
+	cells := []string{cell}
+	if mergedCell, err := ws.mergeCellsParser(cell); err == nil && mergedCell != strings.ToUpper(cell) {
+		cells = append(cells, mergedCell)
+	}
+	for _, cell := range cells {
 		for _, link := range ws.Hyperlinks.Hyperlink {
 			ok, err := f.checkCellInRangeRef(cell, link.Ref)
 			if err != nil {
@@ -868,10 +1197,12 @@ func (f *File) GetCellHyperLink(sheet, cell string) (bool, string, error) {
 }
 
 // HyperlinkOpts can be passed to SetCellHyperlink to set optional hyperlink
-// attributes (e.g. display value)
+// attributes (e.g. display value). The ApplyStyle specifies whether to apply
+// the built-in Hyperlink cell style to the cell.
 type HyperlinkOpts struct {
-	Display *string
-	Tooltip *string
+	Display    *string
+	Tooltip    *string
+	ApplyStyle bool
 }
 
 // SetCellHyperLink provides a function to set cell hyperlink by given
@@ -903,6 +1234,13 @@ type HyperlinkOpts struct {
 // This is another example for "Location":
 //
 //	err := f.SetCellHyperLink("Sheet1", "A3", "Sheet1!A40", "Location")
+//
+// Set the ApplyStyle field of the options to apply the built-in Hyperlink
+// cell style (blue and underline font) to the cell, and the built-in
+// Followed Hyperlink cell style will also be registered in the workbook:
+//
+//	err := f.SetCellHyperLink("Sheet1", "A3", "https://github.com/xuri/excelize",
+//	    "External", excelize.HyperlinkOpts{ApplyStyle: true})
 func (f *File) SetCellHyperLink(sheet, cell, link, linkType string, opts ...HyperlinkOpts) error {
 	// Check for correct cell name
 	if _, _, err := SplitCellName(cell); err != nil {
@@ -953,6 +1291,7 @@ func (f *File) SetCellHyperLink(sheet, cell, link, linkType string, opts ...Hype
 		return newInvalidLinkTypeError(linkType)
 	}
 
+	var applyStyle bool
 	for _, o := range opts {
 		if o.Display != nil {
 			linkData.Display = *o.Display
@@ -960,12 +1299,16 @@ func (f *File) SetCellHyperLink(sheet, cell, link, linkType string, opts ...Hype
 		if o.Tooltip != nil {
 			linkData.Tooltip = *o.Tooltip
 		}
+		applyStyle = applyStyle || o.ApplyStyle
 	}
 	if idx == -1 {
 		ws.Hyperlinks.Hyperlink = append(ws.Hyperlinks.Hyperlink, linkData)
-		return err
+	} else {
+		ws.Hyperlinks.Hyperlink[idx] = linkData
+	}
+	if applyStyle {
+		return f.setHyperlinkCellStyle(sheet, cell)
 	}
-	ws.Hyperlinks.Hyperlink[idx] = linkData
 	return err
 }
 
@@ -1213,6 +1556,7 @@ func setRichText(runs []RichTextRun) ([]xlsxR, error) {
 //	    }
 //	}
 func (f *File) SetCellRichText(sheet, cell string, runs []RichTextRun) error {
+	defer f.dispatchWatchEvents()
 	ws, err := f.workSheetReader(sheet)
 	if err != nil {
 		return err
@@ -1221,6 +1565,7 @@ func (f *File) SetCellRichText(sheet, cell string, runs []RichTextRun) error {
 	if err != nil {
 		return err
 	}
+	f.setCellChanged(sheet, cell)
 	if err := f.sharedStringsLoader(); err != nil {
 		return err
 	}
@@ -1567,22 +1912,58 @@ func getSharedFormula(ws *xlsxWorksheet, si int, cell string) string {
 	for _, r := range ws.SheetData.Row {
 		for _, c := range r.C {
 			if c.F != nil && c.F.Ref != "" && c.F.T == STCellFormulaTypeShared && c.F.Si != nil && *c.F.Si == si {
-				col, row, _ := CellNameToCoordinates(cell)
-				sharedCol, sharedRow, _ := CellNameToCoordinates(c.R)
-				dCol := col - sharedCol
-				dRow := row - sharedRow
-				orig := []byte(c.F.Content)
-				res, start := parseSharedFormula(dCol, dRow, orig)
-				if start < len(orig) {
-					res += string(orig[start:])
-				}
-				return res
+				return c.getSharedFormula(cell)
 			}
 		}
 	}
 	return ""
 }
 
+// getSharedFormula provides a function to get the formula of the cell which
+// refers to the master cell of the shared formula by given cell reference.
+func (c *xlsxC) getSharedFormula(cell string) string {
+	col, row, _ := CellNameToCoordinates(cell)
+	sharedCol, sharedRow, _ := CellNameToCoordinates(c.R)
+	orig := []byte(c.F.Content)
+	res, start := parseSharedFormula(col-sharedCol, row-sharedRow, orig)
+	if start < len(orig) {
+		res += string(orig[start:])
+	}
+	return res
+}
+
+// expandSharedFormulas provides a function to convert the shared formulas
+// which may refer to the given worksheet into normal formulas by given
+// worksheet name and the worksheet name of the formulas located. All the
+// shared formulas will be converted if the formulas located in the given
+// worksheet.
+func (ws *xlsxWorksheet) expandSharedFormulas(sheet, sheetN string) {
+	masters := map[int]xlsxC{}
+	for _, row := range ws.SheetData.Row {
+		for _, c := range row.C {
+			if c.F != nil && c.F.T == STCellFormulaTypeShared && c.F.Ref != "" && c.F.Si != nil {
+				if _, ok := moveFormulaRows(sheet, sheetN, c.F.Content, func(row int) int { return row }); ok || sheet == sheetN {
+					masters[*c.F.Si] = c
+				}
+			}
+		}
+	}
+	if len(masters) == 0 {
+		return
+	}
+	for r := range ws.SheetData.Row {
+		for col := range ws.SheetData.Row[r].C {
+			c := &ws.SheetData.Row[r].C[col]
+			if c.F == nil || c.F.T != STCellFormulaTypeShared || c.F.Si == nil {
+				continue
+			}
+			if master, ok := masters[*c.F.Si]; ok {
+				c.F = &xlsxF{Content: master.getSharedFormula(c.R)}
+			}
+		}
+	}
+}
+
 // shiftCell returns the cell shifted according to dCol and dRow taking into
 // consideration absolute references with dollar sign ($)
 func shiftCell(cellID string, dCol, dRow int) string {
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	_ "image/jpeg"
 	"math"
+	"math/big"
 	"os"
 	"path/filepath"
 	"reflect"
@@ -177,6 +178,39 @@ func TestSetCellFloat(t *testing.T) {
 	assert.EqualError(t, f.SetCellFloat("Sheet:1", "A1", 123.42, -1, 64), ErrSheetNameInvalid.Error())
 }
 
+func TestSetCellFloatNonFinite(t *testing.T) {
+	f := NewFile()
+	// Test set NaN and infinity float values with default non-finite policy
+	assert.Equal(t, ErrNonFiniteNumber, f.SetCellFloat("Sheet1", "A1", math.NaN(), -1, 64))
+	assert.Equal(t, ErrNonFiniteNumber, f.SetCellValue("Sheet1", "A1", math.Inf(1)))
+	assert.Equal(t, ErrNonFiniteNumber, f.SetCellValue("Sheet1", "A1", float32(math.Inf(-1))))
+	assert.NoError(t, f.Close())
+
+	for policy, expected := range map[NonFinitePolicy][]string{
+		NonFinitePolicyBlank:    {"", "", ""},
+		NonFinitePolicyNumError: {"#NUM!", "#NUM!", "#NUM!"},
+		NonFinitePolicyString:   {"NaN", "+Inf", "-Inf"},
+	} {
+		f := NewFile(Options{NonFinitePolicy: policy})
+		assert.NoError(t, f.SetCellValue("Sheet1", "A1", 1))
+		assert.NoError(t, f.SetCellValue("Sheet1", "A1", math.NaN()))
+		assert.NoError(t, f.SetCellValue("Sheet1", "B1", math.Inf(1)))
+		assert.NoError(t, f.SetCellFloat("Sheet1", "C1", math.Inf(-1), 2, 64))
+		for i, cell := range []string{"A1", "B1", "C1"} {
+			val, err := f.GetCellValue("Sheet1", cell)
+			assert.NoError(t, err)
+			assert.Equal(t, expected[i], val, cell)
+		}
+		assert.NoError(t, f.Close())
+	}
+	// Test set NaN float value as text with unsupported charset shared strings table
+	f = NewFile(Options{NonFinitePolicy: NonFinitePolicyString})
+	f.SharedStrings = nil
+	f.Pkg.Store(defaultXMLPathSharedStrings, MacintoshCyrillicCharset)
+	assert.EqualError(t, f.SetCellValue("Sheet1", "A1", math.NaN()), "XML syntax error on line 1: invalid UTF-8")
+	assert.NoError(t, f.Close())
+}
+
 func TestSetCellUint(t *testing.T) {
 	f := NewFile()
 	assert.NoError(t, f.SetCellValue("Sheet1", "A1", uint8(math.MaxUint8)))
@@ -262,6 +296,105 @@ func TestSetCellValue(t *testing.T) {
 	assert.EqualError(t, f.SetCellValue("Sheet1", "A1", time.Now().UTC()), "XML syntax error on line 1: invalid UTF-8")
 }
 
+func TestSetCellValueEscapeFormula(t *testing.T) {
+	f := NewFile(Options{EscapeFormula: true})
+	for cell, value := range map[string]interface{}{
+		"A1": "=HYPERLINK(\"https://example.com\")", "A2": "+1", "A3": "-1",
+		"A4": []byte("@SUM(1)"), "A5": "text", "A6": -1,
+	} {
+		assert.NoError(t, f.SetCellValue("Sheet1", cell, value))
+	}
+	assert.NoError(t, f.SetCellStr("Sheet1", "A7", "=1+1"))
+	escape := false
+	assert.NoError(t, f.SetCellValue("Sheet1", "A8", "=1+1", CellValueOpts{EscapeFormula: &escape}))
+	for cell, expected := range map[string]string{
+		"A1": "'=HYPERLINK(\"https://example.com\")", "A2": "'+1", "A3": "'-1",
+		"A4": "'@SUM(1)", "A5": "text", "A6": "-1", "A7": "'=1+1", "A8": "=1+1",
+	} {
+		val, err := f.GetCellValue("Sheet1", cell)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, val, cell)
+	}
+	// Test escape formula by the cell value options
+	f = NewFile()
+	escape = true
+	assert.NoError(t, f.SetCellValue("Sheet1", "A1", "=1+1", CellValueOpts{EscapeFormula: &escape}))
+	assert.NoError(t, f.SetCellValue("Sheet1", "A2", "=1+1"))
+	for cell, expected := range map[string]string{"A1": "'=1+1", "A2": "=1+1"} {
+		val, err := f.GetCellValue("Sheet1", cell)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, val, cell)
+	}
+}
+
+// testDecimal is a decimal number type for testing, which implements the
+// Decimal interface.
+type testDecimal struct {
+	value string
+}
+
+func (d *testDecimal) Rat() *big.Rat {
+	r, _ := new(big.Rat).SetString(d.value)
+	return r
+}
+
+func TestSetCellValueBigNumber(t *testing.T) {
+	f := NewFile()
+	bigInt, _ := new(big.Int).SetString("12345678901234567890", 10)
+	bigRat, _ := new(big.Rat).SetString("-1234567890.12345678")
+	var nilInt *big.Int
+	var nilDecimal *testDecimal
+	precision, asText := 2, true
+	for cell, value := range map[string]interface{}{
+		"A1": big.NewInt(123), "A2": bigInt, "A3": big.NewRat(1, 3),
+		"A4": bigRat, "A5": big.NewFloat(1.5), "A6": &testDecimal{value: "12.50"},
+		"A7": nilInt, "A8": nilDecimal, "A9": big.NewRat(1, 8),
+	} {
+		assert.NoError(t, f.SetCellValue("Sheet1", cell, value))
+	}
+	for cell, value := range map[string]interface{}{
+		"B1": big.NewRat(10, 3), "B2": big.NewRat(5, 1000), "B3": big.NewRat(-5, 1000),
+		"B4": &testDecimal{value: "1.005"},
+	} {
+		assert.NoError(t, f.SetCellValue("Sheet1", cell, value, CellValueOpts{Precision: &precision}))
+	}
+	assert.NoError(t, f.SetCellValue("Sheet1", "C1", bigInt, CellValueOpts{BigNumberAsText: &asText}))
+	assert.NoError(t, f.SetCellValue("Sheet1", "C2", bigRat, CellValueOpts{BigNumberAsText: &asText}))
+	assert.NoError(t, f.SetCellValue("Sheet1", "C3", big.NewRat(1, 3), CellValueOpts{BigNumberAsText: &asText}))
+	for cell, expected := range map[string]string{
+		"A1": "123", "A2": "12345678901234600000", "A3": "0.333333333333333",
+		"A4": "-1234567890.12346", "A5": "1.5", "A6": "12.5", "A7": "", "A8": "",
+		"A9": "0.125", "B1": "3.33", "B2": "0.01", "B3": "-0.01", "B4": "1.01",
+		"C1": "12345678901234567890", "C2": "-1234567890.12345678", "C3": "0.333333333333333",
+	} {
+		val, err := f.GetCellValue("Sheet1", cell, Options{RawCellValue: true})
+		assert.NoError(t, err)
+		assert.Equal(t, expected, val, cell)
+	}
+	for cell, expected := range map[string]CellType{
+		"A2": CellTypeUnset, "C1": CellTypeSharedString, "C2": CellTypeSharedString, "C3": CellTypeUnset,
+	} {
+		cellType, err := f.GetCellType("Sheet1", cell)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, cellType, cell)
+	}
+	// Test set big number by the workbook options
+	f = NewFile(Options{BigNumberAsText: true})
+	asText = false
+	assert.NoError(t, f.SetCellValue("Sheet1", "A1", bigInt))
+	assert.NoError(t, f.SetCellValue("Sheet1", "A2", bigInt, CellValueOpts{BigNumberAsText: &asText}))
+	for cell, expected := range map[string]CellType{"A1": CellTypeSharedString, "A2": CellTypeUnset} {
+		cellType, err := f.GetCellType("Sheet1", cell)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, cellType, cell)
+	}
+	// Test set big number with infinity value
+	assert.Equal(t, ErrParameterInvalid, f.SetCellValue("Sheet1", "A3", new(big.Float).SetInf(false)))
+	// Test set big number on not exists worksheet
+	assert.EqualError(t, f.SetCellValue("SheetN", "A1", bigInt), "sheet SheetN does not exist")
+	assert.EqualError(t, f.SetCellValue("SheetN", "A1", big.NewInt(1)), "sheet SheetN does not exist")
+}
+
 func TestSetCellValues(t *testing.T) {
 	f := NewFile()
 	err := f.SetCellValue("Sheet1", "A1", time.Date(2010, time.December, 31, 0, 0, 0, 0, time.UTC))
@@ -12,8 +12,12 @@
 package excelize_ch
 
 import (
+	"bytes"
 	"encoding/xml"
 	"fmt"
+	"io"
+	"math"
+	"path"
 	"strconv"
 	"strings"
 )
@@ -90,6 +94,25 @@ const (
 	ChartLineAutomatic
 )
 
+// ChartDataLabelPositionType is the type of supported chart data labels
+// position types.
+type ChartDataLabelPositionType byte
+
+// This section defines the supported chart data labels position types
+// enumeration.
+const (
+	ChartDataLabelsPositionUnset ChartDataLabelPositionType = iota
+	ChartDataLabelsPositionBestFit
+	ChartDataLabelsPositionBelow
+	ChartDataLabelsPositionCenter
+	ChartDataLabelsPositionInsideBase
+	ChartDataLabelsPositionInsideEnd
+	ChartDataLabelsPositionLeft
+	ChartDataLabelsPositionOutsideEnd
+	ChartDataLabelsPositionRight
+	ChartDataLabelsPositionAbove
+)
+
 // This section defines the default value of chart properties.
 var (
 	chartView3DRotX = map[ChartType]int{
@@ -488,6 +511,33 @@ var (
 		Contour:          "none",
 		WireframeContour: "none",
 	}
+	chartDataLabelsPositionTypes = map[ChartDataLabelPositionType]string{
+		ChartDataLabelsPositionBestFit:    "bestFit",
+		ChartDataLabelsPositionBelow:      "b",
+		ChartDataLabelsPositionCenter:     "ctr",
+		ChartDataLabelsPositionInsideBase: "inBase",
+		ChartDataLabelsPositionInsideEnd:  "inEnd",
+		ChartDataLabelsPositionLeft:       "l",
+		ChartDataLabelsPositionOutsideEnd: "outEnd",
+		ChartDataLabelsPositionRight:      "r",
+		ChartDataLabelsPositionAbove:      "t",
+	}
+	supportedChartDataLabelsPosition = map[ChartType][]ChartDataLabelPositionType{
+		Bar:               {ChartDataLabelsPositionCenter, ChartDataLabelsPositionInsideBase, ChartDataLabelsPositionInsideEnd, ChartDataLabelsPositionOutsideEnd},
+		BarStacked:        {ChartDataLabelsPositionCenter, ChartDataLabelsPositionInsideBase, ChartDataLabelsPositionInsideEnd},
+		BarPercentStacked: {ChartDataLabelsPositionCenter, ChartDataLabelsPositionInsideBase, ChartDataLabelsPositionInsideEnd},
+		Col:               {ChartDataLabelsPositionCenter, ChartDataLabelsPositionInsideBase, ChartDataLabelsPositionInsideEnd, ChartDataLabelsPositionOutsideEnd},
+		ColStacked:        {ChartDataLabelsPositionCenter, ChartDataLabelsPositionInsideBase, ChartDataLabelsPositionInsideEnd},
+		ColPercentStacked: {ChartDataLabelsPositionCenter, ChartDataLabelsPositionInsideBase, ChartDataLabelsPositionInsideEnd},
+		Line:              {ChartDataLabelsPositionBelow, ChartDataLabelsPositionCenter, ChartDataLabelsPositionLeft, ChartDataLabelsPositionRight, ChartDataLabelsPositionAbove},
+		Pie:               {ChartDataLabelsPositionBestFit, ChartDataLabelsPositionCenter, ChartDataLabelsPositionInsideEnd, ChartDataLabelsPositionOutsideEnd},
+		Pie3D:             {ChartDataLabelsPositionBestFit, ChartDataLabelsPositionCenter, ChartDataLabelsPositionInsideEnd, ChartDataLabelsPositionOutsideEnd},
+		PieOfPie:          {ChartDataLabelsPositionBestFit, ChartDataLabelsPositionCenter, ChartDataLabelsPositionInsideEnd, ChartDataLabelsPositionOutsideEnd},
+		BarOfPie:          {ChartDataLabelsPositionBestFit, ChartDataLabelsPositionCenter, ChartDataLabelsPositionInsideEnd, ChartDataLabelsPositionOutsideEnd},
+		Scatter:           {ChartDataLabelsPositionBelow, ChartDataLabelsPositionCenter, ChartDataLabelsPositionLeft, ChartDataLabelsPositionRight, ChartDataLabelsPositionAbove},
+		Bubble:            {ChartDataLabelsPositionBelow, ChartDataLabelsPositionCenter, ChartDataLabelsPositionLeft, ChartDataLabelsPositionRight, ChartDataLabelsPositionAbove},
+		Bubble3D:          {ChartDataLabelsPositionBelow, ChartDataLabelsPositionCenter, ChartDataLabelsPositionLeft, ChartDataLabelsPositionRight, ChartDataLabelsPositionAbove},
+	}
 )
 
 // parseChartOptions provides a function to parse the format settings of the
@@ -688,11 +738,16 @@ func (opts *Chart) parseTitle() {
 //
 //	Name
 //	Categories
+//	CategoriesData
 //	Sizes
+//	SizesData
 //	Values
+//	ValuesData
 //	Fill
 //	Line
 //	Marker
+//	DataLabel
+//	SecondaryAxis
 //
 // Name: Set the name for the series. The name is displayed in the chart legend
 // and in the formula bar. The 'Name' property is optional and if it isn't
@@ -703,12 +758,35 @@ func (opts *Chart) parseTitle() {
 // the same as the X axis. In most chart types the 'Categories' property is
 // optional and the chart will just assume a sequential series from 1..n.
 //
+// CategoriesData: This sets the literal chart category labels without the
+// worksheet data, which will be used if the 'Categories' property is empty.
+//
 // Sizes: This sets the bubble size in a data series.
 //
+// SizesData: This sets the literal bubble size in a data series without the
+// worksheet data, which will be used if the 'Sizes' property is empty.
+//
 // Values: This is the most important property of a series and is the only
 // mandatory option for every chart object. This option links the chart with
 // the worksheet data that it displays.
 //
+// ValuesData: This sets the literal values of a series without the worksheet
+// data, which will be used if the 'Values' property is empty. The NaN and
+// infinity numbers will be treated as blank. With the literal data, a chart
+// could be created without writing the helper cells in the worksheet, for
+// example:
+//
+//	err := f.AddChart("Sheet1", "E1", &excelize.Chart{
+//	    Type: excelize.Col,
+//	    Series: []excelize.ChartSeries{
+//	        {
+//	            Name:           "Sheet1!$A$1",
+//	            CategoriesData: []string{"Apple", "Orange", "Pear"},
+//	            ValuesData:     []float64{2, 3, 3},
+//	        },
+//	    },
+//	})
+//
 // Fill: This set the format for the data series fill.
 //
 // Line: This sets the line format of the line chart. The 'Line' property is
@@ -733,6 +811,76 @@ func (opts *Chart) parseTitle() {
 //	x
 //	auto
 //
+// DataLabel: This sets the data labels of the series, which overrides the data
+// labels settings of the plot area for the series. The options that can be set
+// are:
+//
+//	Range
+//	NumFmt
+//	Position
+//	ShowVal
+//	ShowCatName
+//	ShowSerName
+//	ShowPercent
+//	ShowBubbleSize
+//	ShowLeaderLines
+//	CalloutShape
+//
+// Range: Specifies the worksheet range which the text of the data labels comes
+// from, such as Sheet1!$E$2:$E$5. The text of the cells in the range will be
+// cached in the chart. This feature requires Excel 2013 or later.
+//
+// NumFmt: Specifies the number format of the data labels.
+//
+// Position: Specifies the position of the data labels, the position will be
+// ignored if it's not supported by the chart type. The supported positions of
+// the chart types are:
+//
+//	 Chart Type                     | Positions
+//	--------------------------------+-----------------------------------------
+//	 Bar, Col                       | Center, InsideBase, InsideEnd, OutsideEnd
+//	 Stacked Bar, Stacked Col       | Center, InsideBase, InsideEnd
+//	 Line, Scatter, Bubble          | Below, Center, Left, Right, Above
+//	 Pie, Pie3D, PieOfPie, BarOfPie | BestFit, Center, InsideEnd, OutsideEnd
+//
+// ShowVal, ShowCatName, ShowSerName, ShowPercent, ShowBubbleSize: Specifies the
+// values, category names, series name, percentage and bubble size shall be
+// shown in the data labels.
+//
+// ShowLeaderLines: Specifies the leader lines shall be shown for the data
+// labels.
+//
+// CalloutShape: Specifies the preset shape of the data labels, such as
+// wedgeRectCallout, wedgeRoundRectCallout, wedgeEllipseCallout and
+// borderCallout1. This feature requires Excel 2013 or later.
+//
+// For example, create a clustered column chart with the data labels comes from
+// the worksheet range in callout shape:
+//
+//	err := f.AddChart("Sheet1", "E1", &excelize.Chart{
+//	    Type: excelize.Col,
+//	    Series: []excelize.ChartSeries{
+//	        {
+//	            Name:       "Sheet1!$A$1",
+//	            Categories: "Sheet1!$B$1:$D$1",
+//	            Values:     "Sheet1!$B$2:$D$2",
+//	            DataLabel: excelize.ChartDataLabel{
+//	                Range:        "Sheet1!$B$3:$D$3",
+//	                Position:     excelize.ChartDataLabelsPositionOutsideEnd,
+//	                CalloutShape: "wedgeRectCallout",
+//	            },
+//	        },
+//	    },
+//	})
+//
+// SecondaryAxis: Specifies the series plotted on the secondary vertical axis,
+// the series will be placed in another chart group in the same type with a
+// secondary value axis on the right side of the plot area. This is useful for
+// plotting the series in different magnitudes, such as the revenue and the
+// percentage of growth in the same chart. This only works for the chart types
+// which have axes, and at least one series of the first chart in the combo
+// chart should be plotted on the primary axis. The default value is false.
+//
 // Set properties of the chart legend. The options that can be set are:
 //
 //	Position
@@ -754,12 +902,19 @@ func (opts *Chart) parseTitle() {
 // Set properties of the chart title. The properties that can be set are:
 //
 //	Title
+//	TitleRef
 //
 // Title: Set the name (title) for the chart. The name is displayed above the
 // chart. The name can also be a formula such as Sheet1!$A$1 or a list with a
 // sheet name. The name property is optional. The default is to have no chart
 // title.
 //
+// TitleRef: Set the cell reference of the chart title, such as Sheet1!$A$1,
+// the title will be linked to the cell and updated when the value of the cell
+// changes. The font of the first run of the 'Title' will be used as the font
+// of the title if the 'Title' is also specified. The 'TitleRef' property is
+// optional.
+//
 // Specifies how blank cells are plotted on the chart by 'ShowBlanksAs'. The
 // default value is gap. The options that can be set are:
 //
@@ -824,6 +979,7 @@ func (opts *Chart) parseTitle() {
 //	Font
 //	NumFmt
 //	Title
+//	TitleRef
 //
 // The properties of 'YAxis' that can be set are:
 //
@@ -839,6 +995,7 @@ func (opts *Chart) parseTitle() {
 //	LogBase
 //	NumFmt
 //	Title
+//	TitleRef
 //
 // None: Disable axes.
 //
@@ -851,8 +1008,9 @@ func (opts *Chart) parseTitle() {
 // default value is auto.
 //
 // Secondary: Specifies the current series vertical axis as the secondary axis,
-// this only works for the second and later chart in the combo chart. The
-// default value is false.
+// this only works for the second and later chart in the combo chart. Use the
+// 'SecondaryAxis' property of the series for plotting some series of the chart
+// on the secondary axis. The default value is false.
 //
 // TickLabelSkip: Specifies how many tick labels to skip between label that is
 // drawn. The 'TickLabelSkip' property is optional. The default value is auto.
@@ -888,12 +1046,18 @@ func (opts *Chart) parseTitle() {
 // Title: Specifies that the primary horizontal or vertical axis title and
 // resize chart. The 'Title' property is optional.
 //
+// TitleRef: Specifies the cell reference of the axis title, the title will be
+// linked to the cell in the same way as the 'TitleRef' of the chart. The
+// 'TitleRef' property is optional.
+//
 // Set chart size by 'Dimension' property. The 'Dimension' property is optional.
 // The default width is 480, and height is 260.
 //
 // combo: Specifies the create a chart that combines two or more chart types in
-// a single chart. For example, create a clustered column - line chart with
-// data Sheet1!$E$1:$L$15:
+// a single chart, the series type of each series could be specified by adding
+// the series to the chart in that type, and the charts in the same type will
+// be placed in separate chart groups. For example, create a clustered column -
+// line chart with data Sheet1!$E$1:$L$15:
 //
 //	package main
 //
@@ -998,33 +1162,58 @@ func (opts *Chart) parseTitle() {
 //	    }
 //	}
 func (f *File) AddChart(sheet, cell string, chart *Chart, combo ...*Chart) error {
+	_, err := f.AddChartObject(sheet, cell, chart, combo...)
+	return err
+}
+
+// AddChartObject provides the method to add chart in a sheet by given chart
+// format set and properties set as the AddChart function, and returns the
+// handle of the chart, which could be used with the DeleteDrawingObject,
+// MoveDrawingObject and UpdateDrawingObject functions. For example:
+//
+//	obj, err := f.AddChartObject("Sheet1", "E1", &excelize.Chart{
+//	    Type: excelize.Col,
+//	    Series: []excelize.ChartSeries{
+//	        {
+//	            Name:       "Sheet1!$A$2",
+//	            Categories: "Sheet1!$B$1:$D$1",
+//	            Values:     "Sheet1!$B$2:$D$2",
+//	        },
+//	    },
+//	})
+func (f *File) AddChartObject(sheet, cell string, chart *Chart, combo ...*Chart) (DrawingObject, error) {
+	obj := DrawingObject{Sheet: sheet}
 	// Read worksheet data
 	ws, err := f.workSheetReader(sheet)
 	if err != nil {
-		return err
+		return obj, err
+	}
+	if err = f.checkObjectsProtection(ws); err != nil {
+		return obj, err
 	}
 	opts, comboCharts, err := f.getChartOptions(chart, combo)
 	if err != nil {
-		return err
+		return obj, err
 	}
 	// Add first picture for given sheet, create xl/drawings/ and xl/drawings/_rels/ folder.
 	drawingID := f.countDrawings() + 1
-	chartID := f.countCharts() + 1
+	chartID := f.getUnusedPartID("xl/charts/chart")
 	drawingXML := "xl/drawings/drawing" + strconv.Itoa(drawingID) + ".xml"
 	drawingID, drawingXML = f.prepareDrawing(ws, drawingID, sheet, drawingXML)
 	drawingRels := "xl/drawings/_rels/drawing" + strconv.Itoa(drawingID) + ".xml.rels"
 	drawingRID := f.addRels(drawingRels, SourceRelationshipChart, "../charts/chart"+strconv.Itoa(chartID)+".xml", "")
-	err = f.addDrawingChart(sheet, drawingXML, cell, int(opts.Dimension.Width), int(opts.Dimension.Height), drawingRID, &opts.Format)
+	obj.ID, err = f.addDrawingChart(sheet, drawingXML, cell, int(opts.Dimension.Width), int(opts.Dimension.Height), drawingRID, &opts.Format)
 	if err != nil {
-		return err
+		return obj, err
 	}
-	f.addChart(opts, comboCharts)
+	obj.Name = "Chart " + strconv.Itoa(obj.ID)
+	f.addChart(chartID, opts, comboCharts)
 	if err = f.addContentTypePart(chartID, "chart"); err != nil {
-		return err
+		return obj, err
 	}
 	_ = f.addContentTypePart(drawingID, "drawings")
 	f.addSheetNameSpace(sheet, SourceRelationship)
-	return err
+	return obj, err
 }
 
 // AddChartSheet provides the method to create a chartsheet by given chart
@@ -1062,7 +1251,7 @@ func (f *File) AddChartSheet(sheet string, chart *Chart, combo ...*Chart) error
 	f.sheetMap[sheet] = path
 	f.Sheet.Store(path, nil)
 	drawingID := f.countDrawings() + 1
-	chartID := f.countCharts() + 1
+	chartID := f.getUnusedPartID("xl/charts/chart")
 	drawingXML := "xl/drawings/drawing" + strconv.Itoa(drawingID) + ".xml"
 	f.prepareChartSheetDrawing(&cs, drawingID, sheet)
 	drawingRels := "xl/drawings/_rels/drawing" + strconv.Itoa(drawingID) + ".xml.rels"
@@ -1070,7 +1259,7 @@ func (f *File) AddChartSheet(sheet string, chart *Chart, combo ...*Chart) error
 	if err = f.addSheetDrawingChart(drawingXML, drawingRID, &opts.Format); err != nil {
 		return err
 	}
-	f.addChart(opts, comboCharts)
+	f.addChart(chartID, opts, comboCharts)
 	if err = f.addContentTypePart(chartID, "chart"); err != nil {
 		return err
 	}
@@ -1102,35 +1291,845 @@ func (f *File) getChartOptions(opts *Chart, combo []*Chart) (*Chart, []*Chart, e
 		if _, ok := chartValAxNumFmtFormatCode[comboChart.Type]; !ok {
 			return options, comboCharts, newUnsupportedChartType(comboChart.Type)
 		}
-		comboCharts = append(comboCharts, comboChart)
+		primary, secondary := splitChartSeries(comboChart, true)
+		for _, chart := range []*Chart{primary, secondary} {
+			if chart != nil {
+				comboCharts = append(comboCharts, chart)
+			}
+		}
 	}
 	if _, ok := chartValAxNumFmtFormatCode[options.Type]; !ok {
 		return options, comboCharts, newUnsupportedChartType(options.Type)
 	}
-	return options, comboCharts, err
+	primary, secondary := splitChartSeries(options, false)
+	if secondary != nil {
+		comboCharts = append([]*Chart{secondary}, comboCharts...)
+	}
+	return primary, comboCharts, err
+}
+
+// splitChartSeries provides a function to split the series which plotted on
+// the secondary axis into another chart in the same type by given chart
+// options. The primary chart of the combo chart could not be plotted on the
+// secondary axis only, so all series of it will be plotted on the primary
+// axis if no series on the primary axis.
+func splitChartSeries(opts *Chart, combo bool) (*Chart, *Chart) {
+	var primary, secondary []ChartSeries
+	for _, series := range opts.Series {
+		if series.SecondaryAxis {
+			secondary = append(secondary, series)
+			continue
+		}
+		primary = append(primary, series)
+	}
+	if len(secondary) == 0 || (len(primary) == 0 && !combo) {
+		return opts, nil
+	}
+	secondaryChart := *opts
+	secondaryChart.Series, secondaryChart.YAxis.Secondary = secondary, true
+	if len(primary) == 0 {
+		return nil, &secondaryChart
+	}
+	primaryChart := *opts
+	primaryChart.Series = primary
+	return &primaryChart, &secondaryChart
+}
+
+// DeleteChart provides a function to delete the chart in the worksheet by
+// given worksheet name and the cell reference or the name of the chart. All
+// charts anchored at the cell will be deleted if a cell reference was given,
+// and the pictures and shapes anchored at the same cell will be kept. The
+// chart part and the parts referenced by the chart, such as the chart colors
+// and chart style parts, as well as their relationships and content types
+// will be removed. For example, delete the chart named "Chart 1" in the
+// worksheet named Sheet1:
+//
+//	err := f.DeleteChart("Sheet1", "Chart 1")
+func (f *File) DeleteChart(sheet, cellOrName string) error {
+	drawingXML, wsDr, anchors, err := f.getChartAnchors(sheet, cellOrName)
+	if err != nil || len(anchors) == 0 {
+		return err
+	}
+	drawingRels := strings.ReplaceAll(strings.ReplaceAll(drawingXML, "xl/drawings", "xl/drawings/_rels"), ".xml", ".xml.rels")
+	var rIDs []string
+	wsDr.mu.Lock()
+	for _, anchor := range anchors {
+		rIDs = append(rIDs, f.getCellAnchorChartRID(anchor))
+		wsDr.deleteCellAnchor(anchor)
+	}
+	wsDr.mu.Unlock()
+	f.Drawings.Store(drawingXML, wsDr)
+	for _, rID := range rIDs {
+		if err = f.deleteChartPart(drawingRels, rID); err != nil {
+			return err
+		}
+	}
+	return err
 }
 
-// DeleteChart provides a function to delete chart in spreadsheet by given
-// worksheet name and cell reference.
-func (f *File) DeleteChart(sheet, cell string) error {
-	col, row, err := CellNameToCoordinates(cell)
+// MoveChart provides a function to move the chart in the worksheet to the
+// given cell of the same or another worksheet by given worksheet name, the
+// cell reference or the name of the chart, the destination worksheet name and
+// cell reference. All charts anchored at the cell will be moved if a cell
+// reference was given. The size of the chart in cells and the offsets with
+// the anchor cells will be kept. For example, move the chart named "Chart 1"
+// in the worksheet named Sheet1 to the cell B2 of the worksheet named Sheet2:
+//
+//	err := f.MoveChart("Sheet1", "Chart 1", "Sheet2", "B2")
+func (f *File) MoveChart(sheet, cellOrName, toSheet, toCell string) error {
+	col, row, err := CellNameToCoordinates(toCell)
 	if err != nil {
 		return err
 	}
-	col--
-	row--
-	ws, err := f.workSheetReader(sheet)
+	drawingXML, wsDr, anchors, err := f.getChartAnchors(sheet, cellOrName)
+	if err != nil {
+		return err
+	}
+	if len(anchors) == 0 {
+		return newNoExistChartError(sheet, cellOrName)
+	}
+	if strings.EqualFold(sheet, toSheet) {
+		wsDr.mu.Lock()
+		defer wsDr.mu.Unlock()
+		for _, anchor := range anchors {
+			from, to := anchor.From, anchor.To
+			pos := f.getCellAnchorPos(anchor)
+			if from == nil {
+				from, to = pos.From, pos.To
+			}
+			if from == nil {
+				continue
+			}
+			if err = moveCellAnchor(from, to, col-1, row-1); err != nil {
+				return err
+			}
+			if anchor.From == nil {
+				anchor.setCellAnchorPos(pos)
+			}
+		}
+		f.Drawings.Store(drawingXML, wsDr)
+		return err
+	}
+	ws, err := f.workSheetReader(toSheet)
+	if err != nil {
+		return err
+	}
+	if err = f.checkObjectsProtection(ws); err != nil {
+		return err
+	}
+	var moved []*xdrCellAnchor
+	drawingRels := strings.ReplaceAll(strings.ReplaceAll(drawingXML, "xl/drawings", "xl/drawings/_rels"), ".xml", ".xml.rels")
+	toDrawingID := f.countDrawings() + 1
+	toDrawingXML := "xl/drawings/drawing" + strconv.Itoa(toDrawingID) + ".xml"
+	toDrawingID, toDrawingXML = f.prepareDrawing(ws, toDrawingID, toSheet, toDrawingXML)
+	toDrawingRels := "xl/drawings/_rels/drawing" + strconv.Itoa(toDrawingID) + ".xml.rels"
+	toWsDr, cNvPrID, err := f.drawingParser(toDrawingXML)
 	if err != nil {
 		return err
 	}
+	for _, anchor := range anchors {
+		rID := f.getCellAnchorChartRID(anchor)
+		drawRel := f.getDrawingRelationships(drawingRels, rID)
+		if drawRel == nil {
+			continue
+		}
+		deCellAnchor := &decodeCellAnchor{ClientData: &decodeClientData{FLocksWithSheet: true, FPrintsWithSheet: true}}
+		_ = f.xmlNewDecoder(strings.NewReader("<decodeCellAnchor>" + anchor.GraphicFrame + "</decodeCellAnchor>")).Decode(deCellAnchor)
+		pos := f.getCellAnchorPos(anchor)
+		cellAnchor := &xdrCellAnchor{EditAs: anchor.EditAs, From: anchor.From, To: anchor.To, Ext: anchor.Ext, ClientData: anchor.ClientData}
+		if cellAnchor.From == nil {
+			cellAnchor.EditAs, cellAnchor.From, cellAnchor.To = deCellAnchor.EditAs, pos.From, pos.To
+		}
+		if cellAnchor.ClientData == nil {
+			cellAnchor.ClientData = &xdrClientData{
+				FLocksWithSheet:  deCellAnchor.ClientData.FLocksWithSheet,
+				FPrintsWithSheet: deCellAnchor.ClientData.FPrintsWithSheet,
+			}
+		}
+		if cellAnchor.From == nil {
+			continue
+		}
+		if err = moveCellAnchor(cellAnchor.From, cellAnchor.To, col-1, row-1); err != nil {
+			return err
+		}
+		cNvPr := &xlsxCNvPr{ID: cNvPrID, Name: "Chart " + strconv.Itoa(cNvPrID)}
+		if c := deCellAnchor.GraphicFrame.NvGraphicFramePr.CNvPr; c != nil {
+			cNvPr.Name, cNvPr.Descr, cNvPr.Title = c.Name, c.Descr, c.Title
+		}
+		graphic, _ := xml.Marshal(xlsxGraphicFrame{
+			NvGraphicFramePr: xlsxNvGraphicFramePr{CNvPr: cNvPr},
+			Graphic: &xlsxGraphic{
+				GraphicData: &xlsxGraphicData{
+					URI: NameSpaceDrawingMLChart.Value,
+					Chart: &xlsxChart{
+						C:   NameSpaceDrawingMLChart.Value,
+						R:   SourceRelationship.Value,
+						RID: "rId" + strconv.Itoa(f.addRels(toDrawingRels, SourceRelationshipChart, drawRel.Target, "")),
+					},
+				},
+			},
+		})
+		cellAnchor.GraphicFrame = string(graphic)
+		toWsDr.mu.Lock()
+		if cellAnchor.To == nil {
+			toWsDr.OneCellAnchor = append(toWsDr.OneCellAnchor, cellAnchor)
+		} else {
+			toWsDr.TwoCellAnchor = append(toWsDr.TwoCellAnchor, cellAnchor)
+		}
+		toWsDr.mu.Unlock()
+		f.deleteDrawingRels(drawingRels, rID)
+		moved = append(moved, anchor)
+		cNvPrID++
+	}
+	wsDr.mu.Lock()
+	for _, anchor := range moved {
+		wsDr.deleteCellAnchor(anchor)
+	}
+	wsDr.mu.Unlock()
+	f.Drawings.Store(drawingXML, wsDr)
+	f.Drawings.Store(toDrawingXML, toWsDr)
+	_ = f.addContentTypePart(toDrawingID, "drawings")
+	f.addSheetNameSpace(toSheet, SourceRelationship)
+	return err
+}
+
+// getChartAnchors provides a function to get the drawing part path, the
+// drawing and the cell anchors of the charts by given worksheet name and the
+// cell reference or the name of the chart.
+func (f *File) getChartAnchors(sheet, cellOrName string) (string, *xlsxWsDr, []*xdrCellAnchor, error) {
+	var anchors []*xdrCellAnchor
+	col, row, err := CellNameToCoordinates(cellOrName)
+	if err != nil && cellOrName == "" {
+		return "", nil, anchors, err
+	}
+	byName := err != nil
+	ws, err := f.workSheetReader(sheet)
+	if err != nil {
+		return "", nil, anchors, err
+	}
+	if err = f.checkObjectsProtection(ws); err != nil {
+		return "", nil, anchors, err
+	}
 	if ws.Drawing == nil {
+		return "", nil, anchors, err
+	}
+	target := f.getSheetRelationshipsTargetByID(sheet, ws.Drawing.RID)
+	drawingXML := strings.TrimPrefix(strings.ReplaceAll(target, "..", "xl"), "/")
+	wsDr, _, err := f.drawingParser(drawingXML)
+	if err != nil {
+		return drawingXML, wsDr, anchors, err
+	}
+	wsDr.mu.Lock()
+	defer wsDr.mu.Unlock()
+	for _, list := range [][]*xdrCellAnchor{wsDr.OneCellAnchor, wsDr.TwoCellAnchor} {
+		for _, anchor := range list {
+			if f.getCellAnchorChartRID(anchor) == "" {
+				continue
+			}
+			if byName {
+				if _, name := f.getCellAnchorCNvPr(anchor); name == cellOrName {
+					anchors = append(anchors, anchor)
+				}
+				continue
+			}
+			from := anchor.From
+			if from == nil {
+				from = f.getCellAnchorPos(anchor).From
+			}
+			if from != nil && from.Col == col-1 && from.Row == row-1 {
+				anchors = append(anchors, anchor)
+			}
+		}
+	}
+	return drawingXML, wsDr, anchors, err
+}
+
+// getCellAnchorChartRID provides a function to get the relationship ID of
+// the chart in the cell anchor, it returns an empty string if the cell anchor
+// doesn't contain a chart.
+func (f *File) getCellAnchorChartRID(a *xdrCellAnchor) string {
+	if a.Pic != nil || a.GraphicFrame == "" {
+		return ""
+	}
+	deCellAnchor := decodeCellAnchor{}
+	_ = f.xmlNewDecoder(strings.NewReader("<decodeCellAnchor>" + a.GraphicFrame + "</decodeCellAnchor>")).Decode(&deCellAnchor)
+	if graphicFrame := deCellAnchor.GraphicFrame; graphicFrame != nil && graphicFrame.Graphic != nil &&
+		graphicFrame.Graphic.GraphicData != nil && graphicFrame.Graphic.GraphicData.Chart != nil {
+		return graphicFrame.Graphic.GraphicData.Chart.RID
+	}
+	return ""
+}
+
+// deleteCellAnchor provides a function to delete the given cell anchor in the
+// drawing.
+func (wsDr *xlsxWsDr) deleteCellAnchor(anchor *xdrCellAnchor) {
+	for _, anchors := range []*[]*xdrCellAnchor{&wsDr.OneCellAnchor, &wsDr.TwoCellAnchor} {
+		for idx := 0; idx < len(*anchors); idx++ {
+			if (*anchors)[idx] == anchor {
+				*anchors = append((*anchors)[:idx], (*anchors)[idx+1:]...)
+				idx--
+			}
+		}
+	}
+}
+
+// deleteChartPart provides a function to delete the chart part and the parts
+// referenced by the chart, such as the chart colors and chart style parts,
+// with their relationships and content types by given drawing relationships
+// part path and the relationship ID of the chart.
+func (f *File) deleteChartPart(drawingRels, rID string) error {
+	drawRel := f.getDrawingRelationships(drawingRels, rID)
+	f.deleteDrawingRels(drawingRels, rID)
+	if drawRel == nil {
+		return nil
+	}
+	chartXML := strings.TrimPrefix(strings.ReplaceAll(drawRel.Target, "..", "xl"), "/")
+	parts := []string{chartXML}
+	chartRels := strings.ReplaceAll(chartXML, "xl/charts/", "xl/charts/_rels/") + ".rels"
+	if rels, _ := f.relsReader(chartRels); rels != nil {
+		rels.mu.Lock()
+		for _, rel := range rels.Relationships {
+			if rel.TargetMode == "External" {
+				continue
+			}
+			if strings.HasPrefix(rel.Target, "/") {
+				parts = append(parts, strings.TrimPrefix(rel.Target, "/"))
+				continue
+			}
+			parts = append(parts, path.Join(path.Dir(chartXML), rel.Target))
+		}
+		rels.mu.Unlock()
+	}
+	content, err := f.contentTypesReader()
+	if err != nil {
 		return err
 	}
-	drawingXML := strings.ReplaceAll(f.getSheetRelationshipsTargetByID(sheet, ws.Drawing.RID), "..", "xl")
-	_, err = f.deleteDrawing(col, row, drawingXML, "Chart")
+	content.mu.Lock()
+	defer content.mu.Unlock()
+	for idx := 0; idx < len(content.Overrides); idx++ {
+		if inStrSlice(parts, strings.TrimPrefix(content.Overrides[idx].PartName, "/"), true) != -1 {
+			content.Overrides = append(content.Overrides[:idx], content.Overrides[idx+1:]...)
+			idx--
+		}
+	}
+	for _, part := range parts {
+		partRels := path.Join(path.Dir(part), "_rels", path.Base(part)+".rels")
+		f.Pkg.Delete(part)
+		f.Pkg.Delete(partRels)
+		f.Drawings.Delete(part)
+		f.Relationships.Delete(partRels)
+	}
 	return err
 }
 
+// GetCharts provides a function to get the format settings of all charts in
+// the worksheet by given worksheet name. The chart type, series references
+// and literal data, titles, legend, plot area, axes settings and colors will
+// be parsed back into the Chart options, so that the charts created by Excel
+// can be read, modified and written with the AddChart function. For a combo
+// chart, only the first chart type in the plot area and its series will be
+// returned, and the series plotted on the secondary axis will be marked by the
+// 'SecondaryAxis' property. The settings which not supported by the chart
+// options will be ignored. For example, get the charts in the worksheet named
+// Sheet1:
+//
+//	charts, err := f.GetCharts("Sheet1")
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	for _, chart := range charts {
+//	    for _, series := range chart.Series {
+//	        fmt.Println(series.Name, series.Categories, series.Values)
+//	    }
+//	}
+func (f *File) GetCharts(sheet string) ([]Chart, error) {
+	var charts []Chart
+	f.mu.Lock()
+	ws, err := f.workSheetReader(sheet)
+	if err != nil {
+		f.mu.Unlock()
+		return charts, err
+	}
+	f.mu.Unlock()
+	if ws.Drawing == nil {
+		return charts, err
+	}
+	target := f.getSheetRelationshipsTargetByID(sheet, ws.Drawing.RID)
+	drawingXML := strings.TrimPrefix(strings.ReplaceAll(target, "..", "xl"), "/")
+	drawingRelationships := strings.ReplaceAll(strings.ReplaceAll(target, "../drawings", "xl/drawings/_rels"), ".xml", ".xml.rels")
+	wsDr, _, err := f.drawingParser(drawingXML)
+	if err != nil {
+		return charts, err
+	}
+	wsDr.mu.Lock()
+	anchors := append(append([]*xdrCellAnchor{}, wsDr.OneCellAnchor...), wsDr.TwoCellAnchor...)
+	wsDr.mu.Unlock()
+	for _, anchor := range anchors {
+		chart, err := f.getChart(sheet, anchor, drawingRelationships)
+		if err != nil {
+			return charts, err
+		}
+		if chart != nil {
+			charts = append(charts, *chart)
+		}
+	}
+	return charts, err
+}
+
+// getChart provides a function to get the format settings of the chart by
+// given worksheet name, drawing cell anchor and drawing relationships part
+// path. It returns nil if the cell anchor doesn't contain a chart.
+func (f *File) getChart(sheet string, anchor *xdrCellAnchor, drawingRelationships string) (*Chart, error) {
+	var (
+		deCellAnchor = new(decodeCellAnchor)
+		chartSpace   decodeChartSpace
+	)
+	if err := f.xmlNewDecoder(strings.NewReader("<decodeCellAnchor>" + anchor.GraphicFrame + "</decodeCellAnchor>")).
+		Decode(deCellAnchor); err != nil && err != io.EOF {
+		return nil, err
+	}
+	graphicFrame := deCellAnchor.GraphicFrame
+	if graphicFrame == nil || graphicFrame.Graphic == nil || graphicFrame.Graphic.GraphicData == nil ||
+		graphicFrame.Graphic.GraphicData.Chart == nil {
+		return nil, nil
+	}
+	drawRel := f.getDrawingRelationships(drawingRelationships, graphicFrame.Graphic.GraphicData.Chart.RID)
+	if drawRel == nil {
+		return nil, nil
+	}
+	chartXML := strings.TrimPrefix(strings.ReplaceAll(drawRel.Target, "..", "xl"), "/")
+	if err := f.xmlNewDecoder(bytes.NewReader(namespaceStrictToTransitional(f.readXML(chartXML)))).
+		Decode(&chartSpace); err != nil && err != io.EOF {
+		return nil, err
+	}
+	if chartSpace.Chart.PlotArea == nil {
+		return nil, nil
+	}
+	chart := f.extractChart(&chartSpace)
+	if graphicFrame.NvGraphicFramePr.CNvPr != nil {
+		chart.Format.AltText = graphicFrame.NvGraphicFramePr.CNvPr.Descr
+	}
+	f.extractChartAnchor(sheet, anchor, deCellAnchor, chart)
+	return chart, nil
+}
+
+// extractChartAnchor provides a function to extract the dimension, offset,
+// positioning and print settings of the chart by given worksheet name,
+// drawing cell anchor and the decoded cell anchor.
+func (f *File) extractChartAnchor(sheet string, anchor *xdrCellAnchor, deCellAnchor *decodeCellAnchor, chart *Chart) {
+	from, to := deCellAnchor.From, deCellAnchor.To
+	if anchor.From != nil {
+		from = &decodeFrom{Col: anchor.From.Col, ColOff: anchor.From.ColOff, Row: anchor.From.Row, RowOff: anchor.From.RowOff}
+	}
+	if anchor.To != nil {
+		to = &decodeTo{Col: anchor.To.Col, ColOff: anchor.To.ColOff, Row: anchor.To.Row, RowOff: anchor.To.RowOff}
+	}
+	chart.Format.ScaleX, chart.Format.ScaleY = defaultDrawingScale, defaultDrawingScale
+	chart.Format.Positioning = anchor.EditAs
+	if anchor.ClientData != nil {
+		chart.Format.Locked = boolPtr(anchor.ClientData.FLocksWithSheet)
+		chart.Format.PrintObject = boolPtr(anchor.ClientData.FPrintsWithSheet)
+	} else if deCellAnchor.ClientData != nil {
+		chart.Format.Locked = boolPtr(deCellAnchor.ClientData.FLocksWithSheet)
+		chart.Format.PrintObject = boolPtr(deCellAnchor.ClientData.FPrintsWithSheet)
+	}
+	if from == nil || to == nil {
+		return
+	}
+	chart.Format.OffsetX, chart.Format.OffsetY = from.ColOff/EMU, from.RowOff/EMU
+	width, height := to.ColOff/EMU-from.ColOff/EMU, to.RowOff/EMU-from.RowOff/EMU
+	for col := from.Col; col < to.Col; col++ {
+		width += f.getColWidth(sheet, col+1)
+	}
+	for row := from.Row; row < to.Row; row++ {
+		height += f.getRowHeight(sheet, row+1)
+	}
+	if width > 0 && height > 0 {
+		chart.Dimension = ChartDimension{Width: uint(width), Height: uint(height)}
+	}
+}
+
+// extractChart provides a function to extract the format settings of the
+// chart by given decoded chart space.
+func (f *File) extractChart(chartSpace *decodeChartSpace) *Chart {
+	chart := &Chart{
+		Title:    extractChartTitle(chartSpace.Chart.Title),
+		TitleRef: extractChartTitleRef(chartSpace.Chart.Title),
+		Legend:   ChartLegend{Position: "none"},
+		Border:   ChartLine{Type: ChartLineAutomatic},
+	}
+	if legend := chartSpace.Chart.Legend; legend != nil {
+		chart.Legend.Position = "right"
+		if legend.LegendPos != nil && legend.LegendPos.Val != nil {
+			for position, val := range chartLegendPosition {
+				if val == *legend.LegendPos.Val {
+					chart.Legend.Position = position
+				}
+			}
+		}
+	}
+	if dispBlanksAs := chartSpace.Chart.DispBlanksAs; dispBlanksAs != nil && dispBlanksAs.Val != nil {
+		chart.ShowBlanksAs = *dispBlanksAs.Val
+	}
+	if chartSpace.SpPr != nil && chartSpace.SpPr.Ln != nil {
+		chart.Border = ChartLine{Type: ChartLineSolid, Width: float64(chartSpace.SpPr.Ln.W) / 12700}
+		if chartSpace.SpPr.Ln.NoFill != nil {
+			chart.Border.Type = ChartLineNone
+		}
+	}
+	plotArea := chartSpace.Chart.PlotArea
+	chartType, groups := f.getChartType(plotArea)
+	if len(groups) == 0 {
+		return chart
+	}
+	charts := groups[0]
+	chart.Type = chartType
+	if charts.VaryColors != nil {
+		chart.VaryColors = boolPtr(charts.VaryColors.Val == nil || *charts.VaryColors.Val)
+	}
+	if charts.HoleSize != nil && charts.HoleSize.Val != nil {
+		chart.HoleSize = *charts.HoleSize.Val
+	}
+	if charts.SplitPos != nil && charts.SplitPos.Val != nil {
+		chart.PlotArea.SecondPlotValues = *charts.SplitPos.Val
+	}
+	if dLbls := charts.DLbls; dLbls != nil {
+		isTrue := func(val *attrValBool) bool { return val != nil && (val.Val == nil || *val.Val) }
+		chart.Legend.ShowLegendKey = isTrue(dLbls.ShowLegendKey)
+		chart.PlotArea.ShowBubbleSize = isTrue(dLbls.ShowBubbleSize)
+		chart.PlotArea.ShowCatName = isTrue(dLbls.ShowCatName)
+		chart.PlotArea.ShowLeaderLines = isTrue(dLbls.ShowLeaderLines)
+		chart.PlotArea.ShowPercent = isTrue(dLbls.ShowPercent)
+		chart.PlotArea.ShowSerName = isTrue(dLbls.ShowSerName)
+		chart.PlotArea.ShowVal = isTrue(dLbls.ShowVal)
+		if dLbls.NumFmt != nil {
+			chart.PlotArea.NumFmt = ChartNumFmt{CustomNumFmt: dLbls.NumFmt.FormatCode, SourceLinked: dLbls.NumFmt.SourceLinked}
+		}
+	}
+	for _, group := range groups {
+		secondary := len(group.AxID) > 0 && len(charts.AxID) > 0 && group.AxID[0].Val != nil &&
+			charts.AxID[0].Val != nil && *group.AxID[0].Val != *charts.AxID[0].Val
+		for _, ser := range group.Ser {
+			series := extractChartSeries(ser)
+			if dataLabel := extractChartDataLabel(ser); dataLabel != extractChartDataLabel(&decodeChartSer{DLbls: group.DLbls}) {
+				series.DataLabel = dataLabel
+			}
+			series.SecondaryAxis = secondary
+			chart.Series = append(chart.Series, series)
+		}
+	}
+	axes := append(append(append(plotArea.CatAx, plotArea.DateAx...), plotArea.ValAx...), plotArea.SerAx...)
+	for idx, axis := range []*ChartAxis{&chart.XAxis, &chart.YAxis} {
+		if idx >= len(charts.AxID) || charts.AxID[idx].Val == nil {
+			break
+		}
+		for _, ax := range axes {
+			if ax.AxID != nil && ax.AxID.Val != nil && *ax.AxID.Val == *charts.AxID[idx].Val {
+				*axis = extractChartAxis(ax)
+				break
+			}
+		}
+	}
+	return chart
+}
+
+// getChartType provides a function to get the chart type and the decoded
+// chart type elements by given decoded plot area. The first chart type in the
+// plot area will be used for the combo chart, and all chart type elements in
+// that type will be returned.
+func (f *File) getChartType(plotArea *decodeChartPlotArea) (ChartType, []*decodeCharts) {
+	for _, chart := range []struct {
+		charts []*decodeCharts
+		types  []ChartType
+	}{
+		{plotArea.AreaChart, []ChartType{Area, AreaStacked, AreaPercentStacked}},
+		{plotArea.Area3DChart, []ChartType{Area3D, Area3DStacked, Area3DPercentStacked}},
+		{plotArea.BarChart, []ChartType{Col, ColStacked, ColPercentStacked, Bar, BarStacked, BarPercentStacked}},
+		{plotArea.Bar3DChart, []ChartType{
+			Col3D, Col3DClustered, Col3DStacked, Col3DPercentStacked,
+			Col3DCone, Col3DConeClustered, Col3DConeStacked, Col3DConePercentStacked,
+			Col3DPyramid, Col3DPyramidClustered, Col3DPyramidStacked, Col3DPyramidPercentStacked,
+			Col3DCylinder, Col3DCylinderClustered, Col3DCylinderStacked, Col3DCylinderPercentStacked,
+			Bar3DClustered, Bar3DStacked, Bar3DPercentStacked,
+			Bar3DConeClustered, Bar3DConeStacked, Bar3DConePercentStacked,
+			Bar3DPyramidClustered, Bar3DPyramidStacked, Bar3DPyramidPercentStacked,
+			Bar3DCylinderClustered, Bar3DCylinderStacked, Bar3DCylinderPercentStacked,
+		}},
+		{plotArea.BubbleChart, []ChartType{Bubble, Bubble3D}},
+		{plotArea.DoughnutChart, []ChartType{Doughnut}},
+		{plotArea.LineChart, []ChartType{Line}},
+		{plotArea.Line3DChart, []ChartType{Line3D}},
+		{plotArea.PieChart, []ChartType{Pie}},
+		{plotArea.Pie3DChart, []ChartType{Pie3D}},
+		{plotArea.OfPieChart, []ChartType{PieOfPie, BarOfPie}},
+		{plotArea.RadarChart, []ChartType{Radar}},
+		{plotArea.ScatterChart, []ChartType{Scatter}},
+		{plotArea.Surface3DChart, []ChartType{Surface3D, WireframeSurface3D}},
+		{plotArea.SurfaceChart, []ChartType{Contour, WireframeContour}},
+	} {
+		if len(chart.charts) == 0 {
+			continue
+		}
+		for _, chartType := range chart.types {
+			if f.matchChartType(chart.charts[0], chartType) {
+				return chartType, chart.charts
+			}
+		}
+		return chart.types[0], chart.charts
+	}
+	return Area, nil
+}
+
+// matchChartType provides a function to check if the decoded chart type
+// element matches the bar direction, grouping, shape, type of pie of pie
+// chart, wireframe and 3-D bubble settings of the given chart type.
+func (f *File) matchChartType(charts *decodeCharts, chartType ChartType) bool {
+	getVal := func(val *attrValString, defaultVal string) string {
+		if val != nil && val.Val != nil {
+			return *val.Val
+		}
+		return defaultVal
+	}
+	isTrue := func(val *attrValBool) bool { return val != nil && (val.Val == nil || *val.Val) }
+	if barDir, ok := plotAreaChartBarDir[chartType]; ok && barDir != getVal(charts.BarDir, "col") {
+		return false
+	}
+	if grouping, ok := plotAreaChartGrouping[chartType]; ok && grouping != getVal(charts.Grouping, "clustered") {
+		return false
+	}
+	shape := "box"
+	if val := f.drawChartShape(&Chart{Type: chartType}); val != nil {
+		shape = *val.Val
+	}
+	if shape != getVal(charts.Shape, "box") {
+		return false
+	}
+	if ofPieType, ok := map[ChartType]string{PieOfPie: "pie", BarOfPie: "bar"}[chartType]; ok && ofPieType != getVal(charts.OfPieType, "pie") {
+		return false
+	}
+	if isTrue(charts.Wireframe) != (chartType == WireframeSurface3D || chartType == WireframeContour) {
+		return false
+	}
+	var bubble3D bool
+	for _, ser := range charts.Ser {
+		bubble3D = bubble3D || isTrue(ser.Bubble3D)
+	}
+	return bubble3D == (chartType == Bubble3D)
+}
+
+// extractChartTitle provides a function to extract the rich text runs of the
+// chart or axis title by given decoded title.
+func extractChartTitle(title *decodeChartTitle) []RichTextRun {
+	var runs []RichTextRun
+	if title == nil || title.Tx.Rich == nil {
+		return runs
+	}
+	for _, p := range title.Tx.Rich.P {
+		for _, r := range p.R {
+			run := RichTextRun{Text: r.T}
+			if rPr := r.RPr; rPr != nil {
+				var color string
+				if rPr.SolidFill != nil && rPr.SolidFill.SrgbClr != nil && rPr.SolidFill.SrgbClr.Val != nil {
+					color = *rPr.SolidFill.SrgbClr.Val
+				}
+				if rPr.B || rPr.I || rPr.Sz > 0 || color != "" {
+					run.Font = &Font{Bold: rPr.B, Italic: rPr.I, Size: rPr.Sz / 100, Color: color}
+				}
+			}
+			runs = append(runs, run)
+		}
+	}
+	return runs
+}
+
+// extractChartTitleRef provides a function to extract the cell reference of
+// the chart or axis title by given decoded title.
+func extractChartTitleRef(title *decodeChartTitle) string {
+	if title == nil || title.Tx.StrRef == nil {
+		return ""
+	}
+	return title.Tx.StrRef.F
+}
+
+// extractChartDataLabel provides a function to extract the data labels format
+// settings by given decoded series.
+func extractChartDataLabel(ser *decodeChartSer) ChartDataLabel {
+	var dataLabel ChartDataLabel
+	isTrue := func(val *attrValBool) bool { return val != nil && (val.Val == nil || *val.Val) }
+	if ser.ExtLst != nil {
+		for _, ext := range ser.ExtLst.Ext {
+			if ext.DataLabelsRange != nil {
+				dataLabel.Range = ext.DataLabelsRange.F
+			}
+		}
+	}
+	dLbls := ser.DLbls
+	if dLbls == nil {
+		return dataLabel
+	}
+	if dLbls.NumFmt != nil {
+		dataLabel.NumFmt = ChartNumFmt{CustomNumFmt: dLbls.NumFmt.FormatCode, SourceLinked: dLbls.NumFmt.SourceLinked}
+	}
+	if dLbls.DLblPos != nil && dLbls.DLblPos.Val != nil {
+		for position, val := range chartDataLabelsPositionTypes {
+			if val == *dLbls.DLblPos.Val {
+				dataLabel.Position = position
+			}
+		}
+	}
+	dataLabel.ShowVal, dataLabel.ShowCatName = isTrue(dLbls.ShowVal), isTrue(dLbls.ShowCatName)
+	dataLabel.ShowSerName, dataLabel.ShowPercent = isTrue(dLbls.ShowSerName), isTrue(dLbls.ShowPercent)
+	dataLabel.ShowBubbleSize, dataLabel.ShowLeaderLines = isTrue(dLbls.ShowBubbleSize), isTrue(dLbls.ShowLeaderLines)
+	if dLbls.ExtLst != nil {
+		for _, ext := range dLbls.ExtLst.Ext {
+			if ext.SpPr != nil && ext.SpPr.PrstGeom != nil {
+				dataLabel.CalloutShape = ext.SpPr.PrstGeom.Prst
+			}
+			dataLabel.ShowLeaderLines = dataLabel.ShowLeaderLines || isTrue(ext.ShowLeaderLines)
+		}
+	}
+	return dataLabel
+}
+
+// extractChartSeries provides a function to extract the format settings of
+// the chart series by given decoded series.
+func extractChartSeries(ser *decodeChartSer) ChartSeries {
+	var series ChartSeries
+	if ser.Tx != nil && ser.Tx.StrRef != nil {
+		series.Name = ser.Tx.StrRef.F
+	}
+	cat, val := ser.Cat, ser.Val
+	if cat == nil {
+		cat = ser.XVal
+	}
+	if val == nil {
+		val = ser.YVal
+	}
+	series.Categories, series.CategoriesData, _ = extractChartData(cat)
+	series.Values, _, series.ValuesData = extractChartData(val)
+	series.Sizes, _, series.SizesData = extractChartData(ser.BubbleSize)
+	if spPr := ser.SpPr; spPr != nil {
+		fill := spPr.SolidFill
+		if ln := spPr.Ln; ln != nil && ln.NoFill == nil {
+			if fill == nil {
+				fill = ln.SolidFill
+			}
+			series.Line.Width = float64(ln.W) / 12700
+		}
+		if fill != nil && fill.SrgbClr != nil && fill.SrgbClr.Val != nil {
+			series.Fill = Fill{Type: "pattern", Pattern: 1, Color: []string{*fill.SrgbClr.Val}}
+		}
+	}
+	series.Line.Smooth = ser.Smooth != nil && (ser.Smooth.Val == nil || *ser.Smooth.Val)
+	if marker := ser.Marker; marker != nil {
+		if marker.Symbol != nil && marker.Symbol.Val != nil {
+			series.Marker.Symbol = *marker.Symbol.Val
+		}
+		if marker.Size != nil && marker.Size.Val != nil {
+			series.Marker.Size = *marker.Size.Val
+		}
+	}
+	return series
+}
+
+// extractChartData provides a function to extract the reference, literal
+// strings and literal numbers of the series data source by given decoded data
+// source. The missing literal numbers will be treated as NaN.
+func extractChartData(data *decodeChartData) (string, []string, []float64) {
+	var (
+		ref     string
+		strs    []string
+		numbers []float64
+	)
+	if data == nil {
+		return ref, strs, numbers
+	}
+	if data.StrRef != nil {
+		ref = data.StrRef.F
+	}
+	if data.NumRef != nil {
+		ref = data.NumRef.F
+	}
+	getPoints := func(ptCount *attrValInt, pts []*cPt) []*string {
+		var count int
+		if ptCount != nil && ptCount.Val != nil {
+			count = *ptCount.Val
+		}
+		for _, pt := range pts {
+			if pt.IDx >= count {
+				count = pt.IDx + 1
+			}
+		}
+		points := make([]*string, count)
+		for _, pt := range pts {
+			if pt.IDx >= 0 {
+				points[pt.IDx] = pt.V
+			}
+		}
+		return points
+	}
+	if data.StrLit != nil {
+		for _, pt := range getPoints(data.StrLit.PtCount, data.StrLit.Pt) {
+			var str string
+			if pt != nil {
+				str = *pt
+			}
+			strs = append(strs, str)
+		}
+	}
+	if data.NumLit != nil {
+		for _, pt := range getPoints(data.NumLit.PtCount, data.NumLit.Pt) {
+			str, number := "", math.NaN()
+			if pt != nil {
+				if str = *pt; str != "" {
+					if val, err := strconv.ParseFloat(str, 64); err == nil {
+						number = val
+					}
+				}
+			}
+			strs = append(strs, str)
+			numbers = append(numbers, number)
+		}
+	}
+	return ref, strs, numbers
+}
+
+// extractChartAxis provides a function to extract the format settings of the
+// chart axis by given decoded axis.
+func extractChartAxis(ax *decodeChartAxs) ChartAxis {
+	axis := ChartAxis{
+		None:           ax.Delete != nil && (ax.Delete.Val == nil || *ax.Delete.Val),
+		MajorGridLines: ax.MajorGridlines != nil,
+		MinorGridLines: ax.MinorGridlines != nil,
+		Title:          extractChartTitle(ax.Title),
+		TitleRef:       extractChartTitleRef(ax.Title),
+	}
+	if ax.MajorUnit != nil && ax.MajorUnit.Val != nil {
+		axis.MajorUnit = *ax.MajorUnit.Val
+	}
+	if ax.TickLblSkip != nil && ax.TickLblSkip.Val != nil {
+		axis.TickLabelSkip = *ax.TickLblSkip.Val
+	}
+	if scaling := ax.Scaling; scaling != nil {
+		axis.ReverseOrder = scaling.Orientation != nil && scaling.Orientation.Val != nil &&
+			*scaling.Orientation.Val == orientation[true]
+		if scaling.Max != nil {
+			axis.Maximum = scaling.Max.Val
+		}
+		if scaling.Min != nil {
+			axis.Minimum = scaling.Min.Val
+		}
+		if scaling.LogBase != nil && scaling.LogBase.Val != nil {
+			axis.LogBase = *scaling.LogBase.Val
+		}
+	}
+	if ax.NumFmt != nil {
+		axis.NumFmt = ChartNumFmt{CustomNumFmt: ax.NumFmt.FormatCode, SourceLinked: ax.NumFmt.SourceLinked}
+	}
+	return axis
+}
+
 // countCharts provides a function to get chart files count storage in the
 // folder xl/charts.
 func (f *File) countCharts() int {
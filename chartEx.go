@@ -0,0 +1,428 @@
+// Copyright 2016 - 2023 The excelize Authors. All rights reserved. Use of
+// this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+//
+// package excelize_ch providing a set of functions that allow you to write to and
+// read from XLAM / XLSM / XLSX / XLTM / XLTX files. Supports reading and
+// writing spreadsheet documents generated by Microsoft Excel™ 2007 and later.
+// Supports complex components by high compatibility, and provided streaming
+// API for generating or reading data from a worksheet with huge amounts of
+// data. This library needs Go version 1.16 or later.
+
+package excelize_ch
+
+import (
+	"encoding/xml"
+	"strconv"
+	"strings"
+)
+
+var (
+	// chartExLayoutIDs defined the layout IDs of the series for each chartEx
+	// chart type.
+	chartExLayoutIDs = map[ChartExType]string{
+		Waterfall:  "waterfall",
+		Funnel:     "funnel",
+		Histogram:  "clusteredColumn",
+		BoxWhisker: "boxWhisker",
+		Treemap:    "treemap",
+		Sunburst:   "sunburst",
+	}
+	// chartExDataLabels defined the default data labels of the series for
+	// the chartEx chart types.
+	chartExDataLabels = map[ChartExType]*cxDataLabels{
+		Waterfall: {Pos: "outEnd", Visibility: &cxVisibility{Value: true}},
+		Funnel:    {Pos: "ctr", Visibility: &cxVisibility{Value: true}},
+		Treemap:   {Pos: "inEnd", Visibility: &cxVisibility{CategoryName: true}},
+		Sunburst:  {Pos: "ctr", Visibility: &cxVisibility{CategoryName: true}},
+	}
+	// chartExCatGapWidth defined the gap width of the category axis for the
+	// chartEx chart types with axes.
+	chartExCatGapWidth = map[ChartExType]string{
+		Waterfall:  "0.5",
+		Funnel:     "0.06",
+		Histogram:  "0",
+		BoxWhisker: "1",
+	}
+)
+
+// parseChartExOptions provides a function to parse the format settings of
+// the chartEx chart with default value.
+func parseChartExOptions(opts *ChartEx) (*ChartEx, error) {
+	if opts == nil {
+		return nil, ErrParameterInvalid
+	}
+	if _, ok := chartExLayoutIDs[opts.Type]; !ok {
+		return nil, newUnsupportedChartType(ChartType(opts.Type))
+	}
+	if len(opts.Series) == 0 || (opts.Type != BoxWhisker && len(opts.Series) > 1) {
+		return nil, ErrParameterInvalid
+	}
+	for _, series := range opts.Series {
+		if series.Values == "" {
+			return nil, ErrParameterInvalid
+		}
+	}
+	if opts.BinCount < 0 || opts.BinSize < 0 {
+		return nil, ErrParameterInvalid
+	}
+	if opts.Dimension.Width == 0 {
+		opts.Dimension.Width = defaultChartDimensionWidth
+	}
+	if opts.Dimension.Height == 0 {
+		opts.Dimension.Height = defaultChartDimensionHeight
+	}
+	if opts.Format.PrintObject == nil {
+		opts.Format.PrintObject = boolPtr(true)
+	}
+	if opts.Format.Locked == nil {
+		opts.Format.Locked = boolPtr(false)
+	}
+	if opts.Format.ScaleX == 0 {
+		opts.Format.ScaleX = defaultDrawingScale
+	}
+	if opts.Format.ScaleY == 0 {
+		opts.Format.ScaleY = defaultDrawingScale
+	}
+	if opts.Legend.Position == "" {
+		opts.Legend.Position = defaultChartLegendPosition
+	}
+	return opts, nil
+}
+
+// AddChartEx provides the method to add the Excel 2016 chartEx chart in a
+// worksheet by given chart format set (such as offset, scale, aspect ratio
+// setting and print settings) and properties set, and returns the handle of
+// the chart. The chartEx chart types can't be expressed in the chartSpace of
+// the classic charts, and they will be displayed in Excel 2016 or later. For
+// example, create a waterfall chart with the total at the last data point:
+//
+//	package main
+//
+//	import (
+//	    "fmt"
+//
+//	    "github.com/xuri/excelize/v2"
+//	)
+//
+//	func main() {
+//	    f := excelize.NewFile()
+//	    defer func() {
+//	        if err := f.Close(); err != nil {
+//	            fmt.Println(err)
+//	        }
+//	    }()
+//	    for idx, row := range [][]interface{}{
+//	        {nil, "Amount"}, {"Start", 100}, {"Sales", 50},
+//	        {"Costs", -30}, {"Taxes", -20}, {"End", 100},
+//	    } {
+//	        cell, err := excelize.CoordinatesToCellName(1, idx+1)
+//	        if err != nil {
+//	            fmt.Println(err)
+//	            return
+//	        }
+//	        if err := f.SetSheetRow("Sheet1", cell, &row); err != nil {
+//	            fmt.Println(err)
+//	            return
+//	        }
+//	    }
+//	    if _, err := f.AddChartEx("Sheet1", "D1", &excelize.ChartEx{
+//	        Type: excelize.Waterfall,
+//	        Series: []excelize.ChartExSeries{
+//	            {
+//	                Name:       "Sheet1!$B$1",
+//	                Categories: "Sheet1!$A$2:$A$6",
+//	                Values:     "Sheet1!$B$2:$B$6",
+//	            },
+//	        },
+//	        Title:     []excelize.RichTextRun{{Text: "Cash Flow"}},
+//	        Subtotals: []int{4},
+//	    }); err != nil {
+//	        fmt.Println(err)
+//	        return
+//	    }
+//	    if err := f.SaveAs("Book1.xlsx"); err != nil {
+//	        fmt.Println(err)
+//	    }
+//	}
+//
+// The following shows the type of chartEx chart supported by excelize:
+//
+//	 ID | Enumeration | Chart
+//	----+-------------+------------------
+//	 0  | Waterfall   | Waterfall chart
+//	 1  | Funnel      | Funnel chart
+//	 2  | Histogram   | Histogram chart
+//	 3  | BoxWhisker  | Box and whisker chart
+//	 4  | Treemap     | Treemap chart
+//	 5  | Sunburst    | Sunburst chart
+//
+// The series options that can be set are:
+//
+//	Name
+//	Categories
+//	Values
+//
+// Name: Set the name for the series, which could be a cell reference or the
+// text of the name.
+//
+// Categories: Set the range reference of the categories. The categories of
+// the treemap and sunburst charts could be a multiple columns range, such as
+// Sheet1!$A$2:$C$10, and the columns from left to right will be the levels
+// of the hierarchy from the root to the leaf. This setting is optional for
+// the histogram and box and whisker charts.
+//
+// Values: Set the range reference of the values, this setting is required.
+//
+// Only the box and whisker chart supports multiple series, the other chartEx
+// chart types support only one series.
+//
+// Subtotals: Set the zero-based indexes of the data points which will be
+// displayed as the totals in the waterfall chart.
+//
+// BinCount: Set the number of the bins of the histogram chart.
+//
+// BinSize: Set the width of the bins of the histogram chart, the bins will
+// be computed automatically if both the BinCount and BinSize are not
+// specified.
+//
+// The Format, Dimension and Legend options are the same as the AddChart
+// function, and the text of the runs in the Title will be used as the title
+// of the chart.
+func (f *File) AddChartEx(sheet, cell string, chart *ChartEx) (DrawingObject, error) {
+	obj := DrawingObject{Sheet: sheet}
+	ws, err := f.workSheetReader(sheet)
+	if err != nil {
+		return obj, err
+	}
+	if err = f.checkObjectsProtection(ws); err != nil {
+		return obj, err
+	}
+	opts, err := parseChartExOptions(chart)
+	if err != nil {
+		return obj, err
+	}
+	drawingID := f.countDrawings() + 1
+	chartExID := f.getUnusedPartID("xl/charts/chartEx")
+	drawingXML := "xl/drawings/drawing" + strconv.Itoa(drawingID) + ".xml"
+	drawingID, drawingXML = f.prepareDrawing(ws, drawingID, sheet, drawingXML)
+	drawingRels := "xl/drawings/_rels/drawing" + strconv.Itoa(drawingID) + ".xml.rels"
+	drawingRID := f.addRels(drawingRels, SourceRelationshipChartEx, "../charts/chartEx"+strconv.Itoa(chartExID)+".xml", "")
+	if obj.ID, err = f.addDrawingChartEx(sheet, drawingXML, cell, drawingRID, opts); err != nil {
+		return obj, err
+	}
+	obj.Name = "Chart " + strconv.Itoa(obj.ID)
+	chartEx, err := xml.Marshal(f.drawChartEx(opts))
+	if err != nil {
+		return obj, err
+	}
+	f.saveFileList("xl/charts/chartEx"+strconv.Itoa(chartExID)+".xml", chartEx)
+	if err = f.addContentTypePart(chartExID, "chartEx"); err != nil {
+		return obj, err
+	}
+	_ = f.addContentTypePart(drawingID, "drawings")
+	f.addSheetNameSpace(sheet, SourceRelationship)
+	return obj, err
+}
+
+// drawChartEx provides a function to create the chartEx part by given format
+// settings of the chartEx chart.
+func (f *File) drawChartEx(opts *ChartEx) *xlsxChartExSpace {
+	chartSpace := &xlsxChartExSpace{
+		XMLNSA:  NameSpaceDrawingML.Value,
+		XMLNSR:  SourceRelationship.Value,
+		XMLNSCx: NameSpaceDrawingMLChartEx.Value,
+	}
+	if len(opts.Title) > 0 {
+		var title strings.Builder
+		for _, run := range opts.Title {
+			title.WriteString(run.Text)
+		}
+		chartSpace.Chart.Title = &cxTitle{Pos: "t", Align: "ctr", Tx: &cxTx{TxData: &cxTxData{V: title.String()}}}
+	}
+	valType := "val"
+	if opts.Type == Treemap || opts.Type == Sunburst {
+		valType = "size"
+	}
+	for i, series := range opts.Series {
+		data := &cxData{ID: i}
+		if series.Categories != "" {
+			data.StrDim = []*cxDim{{Type: "cat", F: series.Categories, Lvl: f.getChartExDimLevels(series.Categories, false)}}
+		}
+		data.NumDim = []*cxDim{{Type: valType, F: series.Values, Lvl: f.getChartExDimLevels(series.Values, true)}}
+		chartSpace.ChartData.Data = append(chartSpace.ChartData.Data, data)
+		ser := &cxSeries{
+			LayoutID:   chartExLayoutIDs[opts.Type],
+			DataLabels: chartExDataLabels[opts.Type],
+			DataID:     attrValInt{Val: intPtr(i)},
+			LayoutPr:   f.drawChartExLayoutPr(opts),
+		}
+		if series.Name != "" {
+			ser.Tx = &cxTx{TxData: &cxTxData{V: series.Name}}
+			if lvl := f.getChartExDimLevels(series.Name, false); len(lvl) == 1 {
+				ser.Tx.TxData.F, ser.Tx.TxData.V = series.Name, ""
+				if len(lvl[0].Pt) > 0 {
+					ser.Tx.TxData.V = lvl[0].Pt[0].V
+				}
+			}
+		}
+		chartSpace.Chart.PlotArea.PlotAreaRegion.Series = append(chartSpace.Chart.PlotArea.PlotAreaRegion.Series, ser)
+	}
+	if gapWidth, ok := chartExCatGapWidth[opts.Type]; ok {
+		chartSpace.Chart.PlotArea.Axis = []*cxAxis{{ID: 0, CatScaling: &cxCatScaling{GapWidth: gapWidth}, TickLabels: &cxTickLabels{}}}
+		if opts.Type != Funnel {
+			chartSpace.Chart.PlotArea.Axis = append(chartSpace.Chart.PlotArea.Axis, &cxAxis{
+				ID: 1, ValScaling: &cxValScaling{}, MajorGridlines: &cxGridlines{}, TickLabels: &cxTickLabels{},
+			})
+		}
+	}
+	if pos, ok := chartLegendPosition[opts.Legend.Position]; ok {
+		if pos == "tr" {
+			pos = "r"
+		}
+		chartSpace.Chart.Legend = &cxLegend{Pos: pos, Align: "ctr"}
+	}
+	return chartSpace
+}
+
+// drawChartExLayoutPr provides a function to create the layout properties of
+// the chartEx chart series by given format settings of the chartEx chart.
+func (f *File) drawChartExLayoutPr(opts *ChartEx) *cxLayoutPr {
+	switch opts.Type {
+	case Waterfall:
+		if len(opts.Subtotals) == 0 {
+			return nil
+		}
+		subtotals := &cxSubtotals{}
+		for _, idx := range opts.Subtotals {
+			subtotals.Idx = append(subtotals.Idx, &attrValInt{Val: intPtr(idx)})
+		}
+		return &cxLayoutPr{Subtotals: subtotals}
+	case Histogram:
+		binning := &cxBinning{IntervalClosed: "r"}
+		if opts.BinCount > 0 {
+			binning.BinCount = &attrValInt{Val: intPtr(opts.BinCount)}
+		} else if opts.BinSize > 0 {
+			binning.BinSize = &attrValFloat{Val: float64Ptr(opts.BinSize)}
+		}
+		return &cxLayoutPr{Binning: binning}
+	case BoxWhisker:
+		return &cxLayoutPr{
+			Visibility: &cxSeriesElementVisibilities{MeanMarker: true, Outliers: true},
+			Statistics: &cxStatistics{QuartileMethod: "exclusive"},
+		}
+	case Treemap:
+		return &cxLayoutPr{ParentLabelLayout: &attrValString{Val: stringPtr("overlapping")}}
+	}
+	return nil
+}
+
+// getChartExDimLevels provides a function to get the cached levels of the
+// chartEx chart data dimension by given worksheet range reference, such as
+// Sheet1!$A$1:$A$5. The columns of the range will be the levels from the
+// last column to the first column, and only the numeric values will be
+// cached for the numeric dimension. This function returns nil if the
+// reference is invalid.
+func (f *File) getChartExDimLevels(ref string, numeric bool) []*cxLvl {
+	idx := strings.LastIndex(ref, "!")
+	if idx == -1 {
+		return nil
+	}
+	sheet, rangeRef := ref[:idx], strings.ReplaceAll(ref[idx+1:], "$", "")
+	if strings.HasPrefix(sheet, "'") && strings.HasSuffix(sheet, "'") {
+		sheet = strings.ReplaceAll(sheet[1:len(sheet)-1], "''", "'")
+	}
+	if !strings.Contains(rangeRef, ":") {
+		rangeRef += ":" + rangeRef
+	}
+	coordinates, err := rangeRefToCoordinates(rangeRef)
+	if err != nil {
+		return nil
+	}
+	_ = sortCoordinates(coordinates)
+	var levels []*cxLvl
+	for col := coordinates[2]; col >= coordinates[0]; col-- {
+		lvl := &cxLvl{PtCount: coordinates[3] - coordinates[1] + 1}
+		if numeric {
+			lvl.FormatCode = "General"
+		}
+		for row := coordinates[1]; row <= coordinates[3]; row++ {
+			cell, _ := CoordinatesToCellName(col, row)
+			val, err := f.GetCellValue(sheet, cell, Options{RawCellValue: numeric})
+			if err != nil {
+				return nil
+			}
+			if _, err := strconv.ParseFloat(val, 64); val == "" || (numeric && err != nil) {
+				continue
+			}
+			lvl.Pt = append(lvl.Pt, &cxPt{Idx: row - coordinates[1], V: val})
+		}
+		levels = append(levels, lvl)
+	}
+	return levels
+}
+
+// addDrawingChartEx provides a function to add the chartEx chart graphic
+// frame with the fallback shape by given worksheet name, drawing part path,
+// cell reference, relationship index and format settings of the chartEx
+// chart, and returns the ID of the graphic frame.
+func (f *File) addDrawingChartEx(sheet, drawingXML, cell string, rID int, opts *ChartEx) (int, error) {
+	content, twoCellAnchor, cNvPrID, err := f.twoCellAnchorShape(sheet, drawingXML, cell, opts.Dimension.Width, opts.Dimension.Height, opts.Format)
+	if err != nil {
+		return cNvPrID, err
+	}
+	graphicFrame := xlsxGraphicFrame{
+		NvGraphicFramePr: xlsxNvGraphicFramePr{
+			CNvPr: &xlsxCNvPr{
+				ID:   cNvPrID,
+				Name: "Chart " + strconv.Itoa(cNvPrID),
+			},
+		},
+		Graphic: &xlsxGraphic{
+			GraphicData: &xlsxGraphicData{
+				URI: NameSpaceDrawingMLChartEx.Value,
+				ChartEx: &xlsxChartEx{
+					XMLNSCx: NameSpaceDrawingMLChartEx.Value,
+					XMLNSR:  SourceRelationship.Value,
+					RID:     "rId" + strconv.Itoa(rID),
+				},
+			},
+		},
+	}
+	graphic, _ := xml.Marshal(graphicFrame)
+	sp := xdrSp{
+		NvSpPr: &xdrNvSpPr{
+			CNvPr:   &xlsxCNvPr{ID: cNvPrID},
+			CNvSpPr: &xdrCNvSpPr{TxBox: true},
+		},
+		SpPr: &xlsxSpPr{
+			Xfrm:      xlsxXfrm{Off: xlsxOff{}, Ext: aExt{Cx: int(opts.Dimension.Width) * EMU, Cy: int(opts.Dimension.Height) * EMU}},
+			SolidFill: &xlsxInnerXML{Content: "<a:prstClr val=\"white\"/>"},
+			PrstGeom:  xlsxPrstGeom{Prst: "rect"},
+			Ln:        xlsxLineProperties{W: 1, SolidFill: &xlsxInnerXML{Content: "<a:prstClr val=\"black\"/>"}},
+		},
+		TxBody: &xdrTxBody{
+			BodyPr: &aBodyPr{VertOverflow: "clip", HorzOverflow: "clip"},
+			P: []*aP{{R: []*aR{{T: "This chart isn't available in your version of Excel. " +
+				"Editing this shape or saving this workbook into a different file format will permanently break the chart."}}}},
+		},
+	}
+	shape, _ := xml.Marshal(sp)
+	choice := xlsxChoice{Requires: NameSpaceDrawingMLChartEx1.Name.Local, XMLNSCx1: NameSpaceDrawingMLChartEx1.Value, Content: string(graphic)}
+	if opts.Type == Funnel {
+		choice = xlsxChoice{Requires: NameSpaceDrawingMLChartEx2.Name.Local, XMLNSCx2: NameSpaceDrawingMLChartEx2.Value, Content: string(graphic)}
+	}
+	choiceBytes, _ := xml.Marshal(choice)
+	fallbackBytes, _ := xml.Marshal(xlsxFallback{Content: string(shape)})
+	twoCellAnchor.AlternateContent = append(twoCellAnchor.AlternateContent, &xlsxAlternateContent{
+		XMLNSMC: SourceRelationshipCompatibility.Value,
+		Content: string(choiceBytes) + string(fallbackBytes),
+	})
+	twoCellAnchor.ClientData = &xdrClientData{
+		FLocksWithSheet:  *opts.Format.Locked,
+		FPrintsWithSheet: *opts.Format.PrintObject,
+	}
+	content.TwoCellAnchor = append(content.TwoCellAnchor, twoCellAnchor)
+	f.Drawings.Store(drawingXML, content)
+	return cNvPrID, err
+}
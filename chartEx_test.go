@@ -0,0 +1,113 @@
+package excelize_ch
+
+import (
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddChartEx(t *testing.T) {
+	f := NewFile()
+	for idx, row := range [][]interface{}{
+		{"Group", "Item", "Amount", "Score"},
+		{"A", "Start", 100, 12},
+		{"A", "Sales", 50, 15},
+		{"B", "Costs", -30, "N/A"},
+		{"B", "Taxes", -20, 9},
+		{"B", "End", 100, 21},
+	} {
+		cell, err := CoordinatesToCellName(1, idx+1)
+		assert.NoError(t, err)
+		assert.NoError(t, f.SetSheetRow("Sheet1", cell, &row))
+	}
+	for _, chart := range []*ChartEx{
+		{Type: Waterfall, Series: []ChartExSeries{{Name: "Sheet1!$C$1", Categories: "Sheet1!$B$2:$B$6", Values: "Sheet1!$C$2:$C$6"}}, Title: []RichTextRun{{Text: "Cash "}, {Text: "Flow"}}, Subtotals: []int{4}},
+		{Type: Funnel, Series: []ChartExSeries{{Name: "Amount", Categories: "Sheet1!$B$2:$B$6", Values: "Sheet1!$C$2:$C$6"}}, Legend: ChartLegend{Position: "none"}},
+		{Type: Histogram, Series: []ChartExSeries{{Values: "Sheet1!$D$2:$D$6"}}, BinCount: 3},
+		{Type: Histogram, Series: []ChartExSeries{{Values: "Sheet1!$D$2:$D$6"}}, BinSize: 5, Legend: ChartLegend{Position: "top_right"}},
+		{Type: BoxWhisker, Series: []ChartExSeries{{Values: "Sheet1!$C$2:$C$6"}, {Values: "Sheet1!$D$2:$D$6"}}},
+		{Type: Treemap, Series: []ChartExSeries{{Categories: "Sheet1!$A$2:$B$6", Values: "Sheet1!$D$2:$D$6"}}},
+		{Type: Sunburst, Series: []ChartExSeries{{Categories: "'Sheet1'!$A$2:$B$6", Values: "Sheet1!$D$2:$D$6"}}},
+	} {
+		obj, err := f.AddChartEx("Sheet1", "F1", chart)
+		assert.NoError(t, err)
+		assert.Equal(t, DrawingObject{Sheet: "Sheet1", ID: obj.ID, Name: "Chart " + strconv.Itoa(obj.ID)}, obj)
+	}
+	content, ok := f.Pkg.Load("xl/charts/chartEx1.xml")
+	assert.True(t, ok)
+	chartEx := string(content.([]byte))
+	assert.Contains(t, chartEx, `<cx:title pos="t" align="ctr" overlay="false"><cx:tx><cx:txData><cx:v>Cash Flow</cx:v></cx:txData></cx:tx></cx:title>`)
+	assert.Contains(t, chartEx, `<cx:strDim type="cat"><cx:f>Sheet1!$B$2:$B$6</cx:f><cx:lvl ptCount="5"><cx:pt idx="0">Start</cx:pt>`)
+	assert.Contains(t, chartEx, `<cx:numDim type="val"><cx:f>Sheet1!$C$2:$C$6</cx:f><cx:lvl ptCount="5" formatCode="General"><cx:pt idx="0">100</cx:pt>`)
+	assert.Contains(t, chartEx, `<cx:series layoutId="waterfall"><cx:tx><cx:txData><cx:f>Sheet1!$C$1</cx:f><cx:v>Amount</cx:v></cx:txData></cx:tx>`)
+	assert.Contains(t, chartEx, `<cx:subtotals><cx:idx val="4"></cx:idx></cx:subtotals>`)
+	assert.Contains(t, chartEx, `<cx:legend pos="b" align="ctr" overlay="false"></cx:legend>`)
+	content, ok = f.Pkg.Load("xl/charts/chartEx2.xml")
+	assert.True(t, ok)
+	assert.Contains(t, string(content.([]byte)), `<cx:series layoutId="funnel"><cx:tx><cx:txData><cx:v>Amount</cx:v></cx:txData></cx:tx>`)
+	assert.NotContains(t, string(content.([]byte)), "<cx:legend")
+	content, ok = f.Pkg.Load("xl/charts/chartEx3.xml")
+	assert.True(t, ok)
+	assert.Contains(t, string(content.([]byte)), `<cx:binning intervalClosed="r"><cx:binCount val="3"></cx:binCount></cx:binning>`)
+	// Test the non-numeric values are not cached in the numeric dimension
+	assert.Contains(t, string(content.([]byte)), `<cx:pt idx="1">15</cx:pt><cx:pt idx="3">9</cx:pt>`)
+	content, ok = f.Pkg.Load("xl/charts/chartEx4.xml")
+	assert.True(t, ok)
+	assert.Contains(t, string(content.([]byte)), `<cx:binSize val="5"></cx:binSize>`)
+	assert.Contains(t, string(content.([]byte)), `<cx:legend pos="r"`)
+	content, ok = f.Pkg.Load("xl/charts/chartEx5.xml")
+	assert.True(t, ok)
+	assert.Contains(t, string(content.([]byte)), `<cx:dataId val="1"></cx:dataId><cx:layoutPr><cx:visibility meanLine="false" meanMarker="true" nonoutliers="false" outliers="true"></cx:visibility><cx:statistics quartileMethod="exclusive"></cx:statistics></cx:layoutPr>`)
+	content, ok = f.Pkg.Load("xl/charts/chartEx6.xml")
+	assert.True(t, ok)
+	// Test the levels of the hierarchical categories from the leaf to the root
+	assert.Contains(t, string(content.([]byte)), `<cx:strDim type="cat"><cx:f>Sheet1!$A$2:$B$6</cx:f><cx:lvl ptCount="5"><cx:pt idx="0">Start</cx:pt>`)
+	assert.Contains(t, string(content.([]byte)), `<cx:lvl ptCount="5"><cx:pt idx="0">A</cx:pt>`)
+	assert.Contains(t, string(content.([]byte)), `<cx:numDim type="size">`)
+	assert.NotContains(t, string(content.([]byte)), "<cx:axis")
+	drawing, ok := f.Drawings.Load("xl/drawings/drawing1.xml")
+	assert.True(t, ok)
+	assert.Len(t, drawing.(*xlsxWsDr).TwoCellAnchor, 7)
+	assert.Contains(t, drawing.(*xlsxWsDr).TwoCellAnchor[0].AlternateContent[0].Content, `<mc:Choice xmlns:cx1="http://schemas.microsoft.com/office/drawing/2015/9/8/chartex" Requires="cx1">`)
+	assert.Contains(t, drawing.(*xlsxWsDr).TwoCellAnchor[1].AlternateContent[0].Content, `<mc:Choice xmlns:cx2="http://schemas.microsoft.com/office/drawing/2015/10/21/chartex" Requires="cx2">`)
+	issues, err := f.CompatibilityReport(ExcelVersion2013)
+	assert.NoError(t, err)
+	assert.Equal(t, []CompatibilityIssue{{Feature: CompatibilityFeatureChartEx, MinVersion: ExcelVersion2016, Ref: "xl/charts/chartEx1.xml", Count: 7}}, issues)
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestAddChartEx.xlsx")))
+	assert.NoError(t, f.Close())
+
+	// Test add chartEx chart on the worksheet which contains the chartEx chart
+	f, err = OpenFile(filepath.Join("test", "TestAddChartEx.xlsx"))
+	assert.NoError(t, err)
+	_, err = f.AddChartEx("Sheet1", "F20", &ChartEx{Type: Waterfall, Series: []ChartExSeries{{Values: "Sheet1!$C$2:$C$6"}}})
+	assert.NoError(t, err)
+	assert.True(t, f.hasPart("xl/charts/chartEx8.xml"))
+	drawing, ok = f.Drawings.Load("xl/drawings/drawing1.xml")
+	assert.True(t, ok)
+	assert.Len(t, drawing.(*xlsxWsDr).TwoCellAnchor, 8)
+	// Test add chartEx chart with invalid options
+	for _, chart := range []*ChartEx{
+		nil,
+		{Type: Waterfall},
+		{Type: Waterfall, Series: []ChartExSeries{{}}},
+		{Type: Funnel, Series: []ChartExSeries{{Values: "Sheet1!$C$2:$C$6"}, {Values: "Sheet1!$D$2:$D$6"}}},
+		{Type: Histogram, Series: []ChartExSeries{{Values: "Sheet1!$D$2:$D$6"}}, BinCount: -1},
+	} {
+		_, err = f.AddChartEx("Sheet1", "F1", chart)
+		assert.Equal(t, ErrParameterInvalid, err)
+	}
+	_, err = f.AddChartEx("Sheet1", "F1", &ChartEx{Type: 0xFF, Series: []ChartExSeries{{Values: "Sheet1!$C$2:$C$6"}}})
+	assert.Equal(t, newUnsupportedChartType(0xFF), err)
+	// Test add chartEx chart with invalid cell reference
+	_, err = f.AddChartEx("Sheet1", "A", &ChartEx{Type: Waterfall, Series: []ChartExSeries{{Values: "Sheet1!$C$2:$C$6"}}})
+	assert.Equal(t, newCellNameToCoordinatesError("A", newInvalidCellNameError("A")), err)
+	// Test add chartEx chart on not exists worksheet
+	_, err = f.AddChartEx("SheetN", "F1", &ChartEx{Type: Waterfall, Series: []ChartExSeries{{Values: "Sheet1!$C$2:$C$6"}}})
+	assert.EqualError(t, err, "sheet SheetN does not exist")
+	// Test add chartEx chart with invalid data references
+	_, err = f.AddChartEx("Sheet1", "F40", &ChartEx{Type: Waterfall, Series: []ChartExSeries{{Name: "Sheet1!A", Categories: "SheetN!$A$1:$A$2", Values: "C2:C6"}}})
+	assert.NoError(t, err)
+	assert.NoError(t, f.Close())
+}
@@ -4,7 +4,9 @@ import (
 	"bytes"
 	"encoding/xml"
 	"fmt"
+	"math"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -102,11 +104,13 @@ func TestChartSize(t *testing.T) {
 
 func TestAddDrawingChart(t *testing.T) {
 	f := NewFile()
-	assert.EqualError(t, f.addDrawingChart("SheetN", "", "", 0, 0, 0, nil), newCellNameToCoordinatesError("", newInvalidCellNameError("")).Error())
+	_, err := f.addDrawingChart("SheetN", "", "", 0, 0, 0, nil)
+	assert.EqualError(t, err, newCellNameToCoordinatesError("", newInvalidCellNameError("")).Error())
 
 	path := "xl/drawings/drawing1.xml"
 	f.Pkg.Store(path, MacintoshCyrillicCharset)
-	assert.EqualError(t, f.addDrawingChart("Sheet1", path, "A1", 0, 0, 0, &GraphicOptions{PrintObject: boolPtr(true), Locked: boolPtr(false)}), "XML syntax error on line 1: invalid UTF-8")
+	_, err = f.addDrawingChart("Sheet1", path, "A1", 0, 0, 0, &GraphicOptions{PrintObject: boolPtr(true), Locked: boolPtr(false)})
+	assert.EqualError(t, err, "XML syntax error on line 1: invalid UTF-8")
 }
 
 func TestAddSheetDrawingChart(t *testing.T) {
@@ -310,6 +314,235 @@ func TestAddChart(t *testing.T) {
 	assert.EqualError(t, f.AddChart("Sheet1", "P1", &Chart{Type: Col, Series: []ChartSeries{{Name: "Sheet1!$A$30", Categories: "Sheet1!$B$29:$D$29", Values: "Sheet1!$B$30:$D$30"}}, Title: []RichTextRun{{Text: "2D Column Chart"}}}), "XML syntax error on line 1: invalid UTF-8")
 }
 
+func TestAddChartWithLiteralData(t *testing.T) {
+	f := NewFile()
+	assert.NoError(t, f.AddChart("Sheet1", "A1", &Chart{
+		Type: Col,
+		Series: []ChartSeries{
+			{Name: "Sheet1!$A$1", CategoriesData: []string{"Apple", "Orange & Pear"}, ValuesData: []float64{2, math.NaN(), 3.5}},
+			{Name: "Sheet1!$A$2", Categories: "Sheet1!$B$1:$C$1", CategoriesData: []string{"Unused"}, Values: "Sheet1!$B$2:$C$2", ValuesData: []float64{1}},
+		},
+	}))
+	assert.NoError(t, f.AddChart("Sheet1", "J1", &Chart{
+		Type:   Bubble,
+		Series: []ChartSeries{{Name: "Sheet1!$A$1", CategoriesData: []string{"1", "2"}, ValuesData: []float64{3, 4}, SizesData: []float64{5, 6}}},
+	}))
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestAddChartWithLiteralData.xlsx")))
+	rows, err := f.GetRows("Sheet1")
+	assert.NoError(t, err)
+	assert.Empty(t, rows)
+	for chart, expected := range map[string][]string{
+		"xl/charts/chart1.xml": {
+			`<cat><strLit><ptCount val="2"></ptCount><pt idx="0"><v>Apple</v></pt><pt idx="1"><v>Orange &amp; Pear</v></pt></strLit></cat>`,
+			`<val><numLit><formatCode>General</formatCode><ptCount val="3"></ptCount><pt idx="0"><v>2</v></pt><pt idx="2"><v>3.5</v></pt></numLit></val>`,
+			`<cat><strRef><f>Sheet1!$B$1:$C$1</f></strRef></cat>`,
+			`<val><numRef><f>Sheet1!$B$2:$C$2</f></numRef></val>`,
+		},
+		"xl/charts/chart2.xml": {
+			`<xVal><strLit><ptCount val="2"></ptCount><pt idx="0"><v>1</v></pt><pt idx="1"><v>2</v></pt></strLit></xVal>`,
+			`<yVal><numLit><formatCode>General</formatCode><ptCount val="2"></ptCount><pt idx="0"><v>3</v></pt><pt idx="1"><v>4</v></pt></numLit></yVal>`,
+			`<bubbleSize><numLit><formatCode>General</formatCode><ptCount val="2"></ptCount><pt idx="0"><v>5</v></pt><pt idx="1"><v>6</v></pt></numLit></bubbleSize>`,
+		},
+	} {
+		content, ok := f.Pkg.Load(chart)
+		assert.True(t, ok)
+		for _, str := range expected {
+			assert.Contains(t, string(content.([]byte)), str)
+		}
+	}
+	assert.NoError(t, f.Close())
+}
+
+func TestAddChartSecondaryAxis(t *testing.T) {
+	f := NewFile()
+	for idx, row := range [][]interface{}{
+		{nil, "Q1", "Q2", "Q3"}, {"Revenue", 1200, 1500, 1800},
+		{"Cost", 800, 900, 1100}, {"Growth", 0.05, 0.25, 0.2}, {"Margin", 0.33, 0.4, 0.39},
+	} {
+		cell, err := CoordinatesToCellName(1, idx+1)
+		assert.NoError(t, err)
+		assert.NoError(t, f.SetSheetRow("Sheet1", cell, &row))
+	}
+	series := make([]ChartSeries, 4)
+	for idx := range series {
+		series[idx] = ChartSeries{
+			Name:       fmt.Sprintf("Sheet1!$A$%d", idx+2),
+			Categories: "Sheet1!$B$1:$D$1",
+			Values:     fmt.Sprintf("Sheet1!$B$%d:$D$%d", idx+2, idx+2),
+		}
+	}
+	series[3].SecondaryAxis = true
+	err := f.AddChart("Sheet1", "F1", &Chart{Type: Col, Series: series[:2]},
+		&Chart{Type: Line, Series: series[2:3], YAxis: ChartAxis{Secondary: true}},
+		&Chart{Type: Line, Series: series[3:]},
+	)
+	assert.NoError(t, err)
+	// Test add chart with the series on the secondary axis in the first chart
+	chart := &Chart{Type: Col, Series: []ChartSeries{series[0], series[3]}}
+	assert.NoError(t, f.AddChart("Sheet1", "F20", chart, &Chart{Type: Line, Series: series[2:3]}))
+	assert.False(t, chart.YAxis.Secondary)
+	assert.Len(t, chart.Series, 2)
+	// Test add chart with all series on the secondary axis in the first chart
+	assert.NoError(t, f.AddChart("Sheet1", "F40", &Chart{Type: Col, Series: series[3:]}))
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestAddChartSecondaryAxis.xlsx")))
+
+	for chartXML, expected := range map[string][]string{
+		"xl/charts/chart1.xml": {"barChart", "lineChart", "lineChart"},
+		"xl/charts/chart2.xml": {"barChart", "lineChart", "barChart"},
+		"xl/charts/chart3.xml": {"barChart"},
+	} {
+		content, ok := f.Pkg.Load(chartXML)
+		assert.True(t, ok)
+		var plotArea struct {
+			Charts []struct {
+				XMLName xml.Name
+				AxID    []attrValInt `xml:"axId"`
+			} `xml:",any"`
+		}
+		var chartSpace struct {
+			PlotArea *struct {
+				Inner []byte `xml:",innerxml"`
+			} `xml:"chart>plotArea"`
+		}
+		assert.NoError(t, xml.Unmarshal(content.([]byte), &chartSpace))
+		assert.NoError(t, xml.Unmarshal(append(append([]byte("<plotArea>"), chartSpace.PlotArea.Inner...), []byte("</plotArea>")...), &plotArea))
+		var charts []string
+		axIDs := map[int]int{}
+		for _, c := range plotArea.Charts {
+			if strings.HasSuffix(c.XMLName.Local, "Ax") {
+				axIDs[*c.AxID[0].Val]++
+			}
+			if strings.HasSuffix(c.XMLName.Local, "Chart") {
+				charts = append(charts, c.XMLName.Local)
+			}
+		}
+		assert.Equal(t, expected, charts, chartXML)
+		if len(expected) > 1 {
+			assert.Equal(t, map[int]int{100000000: 1, 100000001: 1, 100000003: 1, 100000004: 1}, axIDs, chartXML)
+		}
+	}
+	// Test get charts with the series on the secondary axis
+	charts, err := f.GetCharts("Sheet1")
+	assert.NoError(t, err)
+	assert.Len(t, charts, 3)
+	assert.Equal(t, Col, charts[1].Type)
+	assert.Len(t, charts[1].Series, 2)
+	assert.False(t, charts[1].Series[0].SecondaryAxis)
+	assert.True(t, charts[1].Series[1].SecondaryAxis)
+	assert.NoError(t, f.Close())
+}
+
+func TestAddChartDataLabel(t *testing.T) {
+	f := NewFile()
+	for idx, row := range [][]interface{}{
+		{nil, "Q1", "Q2", "Q3"}, {"Revenue", 1200, 1500, 1800}, {"Note", "Low", nil, "Peak"},
+	} {
+		cell, err := CoordinatesToCellName(1, idx+1)
+		assert.NoError(t, err)
+		assert.NoError(t, f.SetSheetRow("Sheet1", cell, &row))
+	}
+	dataLabel := ChartDataLabel{
+		Range:        "Sheet1!$B$3:$D$3",
+		NumFmt:       ChartNumFmt{CustomNumFmt: "0.0"},
+		Position:     ChartDataLabelsPositionOutsideEnd,
+		ShowVal:      true,
+		CalloutShape: "wedgeRectCallout",
+	}
+	series := []ChartSeries{
+		{Name: "Sheet1!$A$2", Categories: "Sheet1!$B$1:$D$1", Values: "Sheet1!$B$2:$D$2", DataLabel: dataLabel},
+		{Name: "Sheet1!$A$2", Categories: "Sheet1!$B$1:$D$1", Values: "Sheet1!$B$2:$D$2"},
+	}
+	err := f.AddChart("Sheet1", "F1", &Chart{Type: Col, Series: series}, &Chart{Type: Line, Series: []ChartSeries{
+		{Name: "Sheet1!$A$2", Values: "Sheet1!$B$2:$D$2", DataLabel: ChartDataLabel{Position: ChartDataLabelsPositionOutsideEnd, ShowSerName: true, ShowLeaderLines: true}},
+	}})
+	assert.NoError(t, err)
+	err = f.AddChart("Sheet1", "F20", &Chart{Type: Pie, Series: []ChartSeries{
+		{Name: "Sheet1!$A$2", Values: "Sheet1!$B$2:$D$2", DataLabel: ChartDataLabel{Range: "'Sheet1'!B3", Position: ChartDataLabelsPositionBestFit, ShowPercent: true}},
+	}})
+	assert.NoError(t, err)
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestAddChartDataLabel.xlsx")))
+	content, ok := f.Pkg.Load("xl/charts/chart1.xml")
+	assert.True(t, ok)
+	for _, str := range []string{
+		`<dLbls><numFmt formatCode="0.0" sourceLinked="false"></numFmt><dLblPos val="outEnd"></dLblPos><showLegendKey val="0"></showLegendKey><showVal val="1"></showVal>`,
+		`<extLst><ext uri="{CE6537A1-D6FC-4f65-9D91-7224C49458BB}" xmlns:c15="http://schemas.microsoft.com/office/drawing/2012/chart"><c15:spPr><a:prstGeom prst="wedgeRectCallout"></a:prstGeom></c15:spPr><c15:showDataLabelsRange val="1"></c15:showDataLabelsRange></ext></extLst></dLbls>`,
+		`<extLst><ext uri="{02D57815-91ED-43cb-92C2-25804820EDAC}" xmlns:c15="http://schemas.microsoft.com/office/drawing/2012/chart"><c15:datalabelsRange><c15:f>Sheet1!$B$3:$D$3</c15:f><c15:dlblRangeCache><ptCount val="3"></ptCount><pt idx="0"><v>Low</v></pt><pt idx="2"><v>Peak</v></pt></c15:dlblRangeCache></c15:datalabelsRange></ext></extLst></ser>`,
+		`<showSerName val="1"></showSerName><showPercent val="0"></showPercent><showBubbleSize val="0"></showBubbleSize><showLeaderLines val="1"></showLeaderLines><extLst><ext uri="{CE6537A1-D6FC-4f65-9D91-7224C49458BB}" xmlns:c15="http://schemas.microsoft.com/office/drawing/2012/chart"><c15:showLeaderLines val="1"></c15:showLeaderLines></ext></extLst>`,
+	} {
+		assert.Contains(t, string(content.([]byte)), str)
+	}
+	// Test the unsupported data labels position of the chart type will be ignored
+	assert.Equal(t, 1, strings.Count(string(content.([]byte)), "<dLblPos"))
+	content, ok = f.Pkg.Load("xl/charts/chart2.xml")
+	assert.True(t, ok)
+	assert.Contains(t, string(content.([]byte)), `<dLblPos val="bestFit"></dLblPos>`)
+	assert.Contains(t, string(content.([]byte)), `<c15:f>&#39;Sheet1&#39;!B3</c15:f><c15:dlblRangeCache><ptCount val="1"></ptCount><pt idx="0"><v>Low</v></pt></c15:dlblRangeCache>`)
+
+	// Test get charts with the data labels of the series
+	charts, err := f.GetCharts("Sheet1")
+	assert.NoError(t, err)
+	assert.Len(t, charts, 2)
+	assert.Len(t, charts[0].Series, 2)
+	assert.Equal(t, dataLabel, charts[0].Series[0].DataLabel)
+	assert.Equal(t, ChartDataLabel{}, charts[0].Series[1].DataLabel)
+	assert.Equal(t, ChartDataLabel{Range: "'Sheet1'!B3", Position: ChartDataLabelsPositionBestFit, ShowPercent: true}, charts[1].Series[0].DataLabel)
+	assert.NoError(t, f.Close())
+
+	// Test get data labels range cache with invalid references
+	f = NewFile()
+	for _, ref := range []string{"A1:A2", "Sheet1!A:A", "SheetN!A1"} {
+		assert.Nil(t, f.getChartDataLabelsRangeCache(ref))
+	}
+	assert.NoError(t, f.Close())
+}
+
+func TestAddChartTitleRef(t *testing.T) {
+	f := NewFile()
+	for idx, row := range [][]interface{}{
+		{"Sales Report", "Q1", "Q2", "Q3"}, {"Revenue", 1200, 1500, 1800}, {"Quarter", "Amount"},
+	} {
+		cell, err := CoordinatesToCellName(1, idx+1)
+		assert.NoError(t, err)
+		assert.NoError(t, f.SetSheetRow("Sheet1", cell, &row))
+	}
+	err := f.AddChart("Sheet1", "F1", &Chart{
+		Type:     Col,
+		Series:   []ChartSeries{{Name: "Sheet1!$A$2", Categories: "Sheet1!$B$1:$D$1", Values: "Sheet1!$B$2:$D$2"}},
+		Title:    []RichTextRun{{Text: "Ignored", Font: &Font{Bold: true, Color: "FF0000"}}},
+		TitleRef: "Sheet1!$A$1",
+		XAxis:    ChartAxis{TitleRef: "Sheet1!$A$3"},
+		YAxis:    ChartAxis{TitleRef: "'Sheet1'!$B$3"},
+	})
+	assert.NoError(t, err)
+	content, ok := f.Pkg.Load("xl/charts/chart1.xml")
+	assert.True(t, ok)
+	chart := string(content.([]byte))
+	assert.Contains(t, chart, `<title><tx><strRef><f>Sheet1!$A$1</f><strCache><ptCount val="1"></ptCount><pt idx="0"><v>Sales Report</v></pt></strCache></strRef></tx>`)
+	assert.Contains(t, chart, `<a:defRPr b="true" baseline="0" i="false" kern="0" spc="0" sz="1400"><a:solidFill><a:srgbClr val="FF0000"></a:srgbClr></a:solidFill>`)
+	assert.Contains(t, chart, `<f>Sheet1!$A$3</f><strCache><ptCount val="1"></ptCount><pt idx="0"><v>Quarter</v></pt></strCache>`)
+	assert.Contains(t, chart, `<f>&#39;Sheet1&#39;!$B$3</f><strCache><ptCount val="1"></ptCount><pt idx="0"><v>Amount</v></pt></strCache>`)
+	assert.NotContains(t, chart, "Ignored")
+	charts, err := f.GetCharts("Sheet1")
+	assert.NoError(t, err)
+	assert.Len(t, charts, 1)
+	assert.Equal(t, "Sheet1!$A$1", charts[0].TitleRef)
+	assert.Empty(t, charts[0].Title)
+	assert.Equal(t, "Sheet1!$A$3", charts[0].XAxis.TitleRef)
+	assert.Equal(t, "'Sheet1'!$B$3", charts[0].YAxis.TitleRef)
+	// Test add chart with the title linked to the invalid cell reference
+	err = f.AddChart("Sheet1", "F20", &Chart{
+		Type:     Col,
+		Series:   []ChartSeries{{Values: "Sheet1!$B$2:$D$2"}},
+		TitleRef: "Sheet1!A",
+	})
+	assert.NoError(t, err)
+	content, ok = f.Pkg.Load("xl/charts/chart2.xml")
+	assert.True(t, ok)
+	assert.Contains(t, string(content.([]byte)), `<title><tx><strRef><f>Sheet1!A</f></strRef></tx>`)
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestAddChartTitleRef.xlsx")))
+	assert.NoError(t, f.Close())
+}
+
 func TestAddChartSheet(t *testing.T) {
 	categories := map[string]string{"A2": "Small", "A3": "Normal", "A4": "Large", "B1": "Apple", "C1": "Orange", "D1": "Pear"}
 	values := map[string]int{"B2": 2, "C2": 3, "D2": 3, "B3": 5, "C3": 2, "D3": 4, "B4": 6, "C4": 7, "D4": 8}
@@ -360,6 +593,27 @@ func TestDeleteChart(t *testing.T) {
 	f, err := OpenFile(filepath.Join("test", "Book1.xlsx"))
 	assert.NoError(t, err)
 	assert.NoError(t, f.DeleteChart("Sheet1", "A1"))
+	// Test the chart, colors and style parts have been removed
+	for _, part := range []string{"xl/charts/chart1.xml", "xl/charts/_rels/chart1.xml.rels", "xl/charts/colors1.xml", "xl/charts/style1.xml"} {
+		_, ok := f.Pkg.Load(part)
+		assert.False(t, ok, part)
+	}
+	for _, part := range []string{"xl/charts/chart2.xml", "xl/charts/colors2.xml", "xl/charts/style2.xml"} {
+		_, ok := f.Pkg.Load(part)
+		assert.True(t, ok, part)
+	}
+	for _, override := range f.ContentTypes.Overrides {
+		assert.NotContains(t, []string{"/xl/charts/chart1.xml", "/xl/charts/colors1.xml", "/xl/charts/style1.xml"}, override.PartName)
+	}
+	assert.Nil(t, f.getDrawingRelationships("xl/drawings/_rels/drawing1.xml.rels", "rId1"))
+	charts, err := f.GetCharts("Sheet1")
+	assert.NoError(t, err)
+	assert.Len(t, charts, 1)
+	// Test delete chart by name
+	assert.NoError(t, f.DeleteChart("Sheet1", "Chart 2"))
+	charts, err = f.GetCharts("Sheet1")
+	assert.NoError(t, err)
+	assert.Empty(t, charts)
 	series := []ChartSeries{
 		{Name: "Sheet1!$A$30", Categories: "Sheet1!$B$29:$D$29", Values: "Sheet1!$B$30:$D$30"},
 		{Name: "Sheet1!$A$31", Categories: "Sheet1!$B$29:$D$29", Values: "Sheet1!$B$31:$D$31"},
@@ -390,7 +644,28 @@ func TestDeleteChart(t *testing.T) {
 	}
 	assert.NoError(t, f.AddChart("Sheet1", "P1", &Chart{Type: Col, Series: series, Format: format, Legend: legend, Title: []RichTextRun{{Text: "2D Column Chart"}}, PlotArea: plotArea, ShowBlanksAs: "zero"}))
 	assert.NoError(t, f.DeleteChart("Sheet1", "P1"))
+	// Test delete chart and keep the shape anchored at the same cell
+	assert.NoError(t, f.AddChart("Sheet1", "P1", &Chart{Type: Col, Series: series}))
+	assert.NoError(t, f.AddChart("Sheet1", "P20", &Chart{Type: Line, Series: series}))
+	assert.NoError(t, f.AddShape("Sheet1", &Shape{Cell: "P1", Type: "rect", Paragraph: []RichTextRun{{Text: "Rectangle"}}}))
+	assert.NoError(t, f.DeleteChart("Sheet1", "P1"))
+	charts, err = f.GetCharts("Sheet1")
+	assert.NoError(t, err)
+	assert.Len(t, charts, 1)
+	assert.Equal(t, Line, charts[0].Type)
+	wsDr, _, err := f.drawingParser("xl/drawings/drawing1.xml")
+	assert.NoError(t, err)
+	assert.Len(t, wsDr.TwoCellAnchor, 2)
+	// Test add chart after deleting chart with the part name has been used
+	assert.NoError(t, f.AddChart("Sheet1", "P40", &Chart{Type: Bar, Series: series}))
+	charts, err = f.GetCharts("Sheet1")
+	assert.NoError(t, err)
+	assert.Len(t, charts, 2)
 	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestDeleteChart.xlsx")))
+	// Test delete chart with unsupported charset content types
+	f.ContentTypes = nil
+	f.Pkg.Store(defaultXMLPathContentTypes, MacintoshCyrillicCharset)
+	assert.EqualError(t, f.DeleteChart("Sheet1", "P20"), "XML syntax error on line 1: invalid UTF-8")
 	// Test delete chart with invalid sheet name
 	assert.EqualError(t, f.DeleteChart("Sheet:1", "P1"), ErrSheetNameInvalid.Error())
 	// Test delete chart on not exists worksheet
@@ -402,6 +677,58 @@ func TestDeleteChart(t *testing.T) {
 	assert.NoError(t, f.Close())
 }
 
+func TestMoveChart(t *testing.T) {
+	f, err := OpenFile(filepath.Join("test", "Book1.xlsx"))
+	assert.NoError(t, err)
+	// Test move chart in the same worksheet
+	assert.NoError(t, f.MoveChart("Sheet1", "Chart 1", "Sheet1", "C3"))
+	_, _, anchors, err := f.getChartAnchors("Sheet1", "C3")
+	assert.NoError(t, err)
+	assert.Len(t, anchors, 1)
+	assert.Equal(t, &xlsxFrom{Col: 2, Row: 2, RowOff: 6350}, f.getCellAnchorPos(anchors[0]).From)
+	assert.NoError(t, f.AddChart("Sheet1", "M1", &Chart{Type: Col, Series: []ChartSeries{{Values: "Sheet1!$B$30:$D$30"}}}))
+	assert.NoError(t, f.MoveChart("Sheet1", "M1", "Sheet1", "M10"))
+	_, _, anchors, err = f.getChartAnchors("Sheet1", "M10")
+	assert.NoError(t, err)
+	assert.Len(t, anchors, 1)
+	// Test move chart to another worksheet
+	assert.NoError(t, f.MoveChart("Sheet1", "Chart 2", "Sheet2", "B2"))
+	assert.NoError(t, f.MoveChart("Sheet1", "M10", "Sheet2", "B20"))
+	charts, err := f.GetCharts("Sheet1")
+	assert.NoError(t, err)
+	assert.Len(t, charts, 1)
+	charts, err = f.GetCharts("Sheet2")
+	assert.NoError(t, err)
+	assert.Len(t, charts, 2)
+	assert.Equal(t, Col, charts[1].Type)
+	_, _, anchors, err = f.getChartAnchors("Sheet2", "Chart 2")
+	assert.NoError(t, err)
+	assert.Len(t, anchors, 1)
+	assert.Equal(t, 1, anchors[0].From.Col)
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestMoveChart.xlsx")))
+	// Test the moved chart can be deleted with its parts
+	assert.NoError(t, f.DeleteChart("Sheet2", "Chart 2"))
+	_, ok := f.Pkg.Load("xl/charts/chart2.xml")
+	assert.False(t, ok)
+	// Test move chart with invalid cell reference
+	assert.EqualError(t, f.MoveChart("Sheet1", "Chart 1", "Sheet1", "A"), newCellNameToCoordinatesError("A", newInvalidCellNameError("A")).Error())
+	// Test move chart with not exist chart
+	assert.EqualError(t, f.MoveChart("Sheet1", "Chart 3", "Sheet1", "A1"), "chart Chart 3 does not exist in sheet Sheet1")
+	assert.EqualError(t, NewFile().MoveChart("Sheet1", "A1", "Sheet1", "B1"), "chart A1 does not exist in sheet Sheet1")
+	// Test move chart with not exist worksheet
+	assert.EqualError(t, f.MoveChart("SheetN", "Chart 1", "Sheet1", "A1"), "sheet SheetN does not exist")
+	assert.EqualError(t, f.MoveChart("Sheet1", "Chart 1", "SheetN", "A1"), "sheet SheetN does not exist")
+	// Test move chart exceeds the worksheet boundary
+	assert.EqualError(t, f.MoveChart("Sheet1", "Chart 1", "Sheet1", "XFD1"), ErrColumnNumber.Error())
+	assert.EqualError(t, f.MoveChart("Sheet1", "Chart 1", "Sheet2", "XFD1"), ErrColumnNumber.Error())
+	// Test move chart with unsupported charset drawing
+	f.Drawings.Delete("xl/drawings/drawing2.xml")
+	f.Pkg.Store("xl/drawings/drawing2.xml", MacintoshCyrillicCharset)
+	assert.EqualError(t, f.MoveChart("Sheet1", "Chart 1", "Sheet2", "A1"), "XML syntax error on line 1: invalid UTF-8")
+	assert.EqualError(t, f.MoveChart("Sheet2", "B20", "Sheet1", "A1"), "XML syntax error on line 1: invalid UTF-8")
+	assert.NoError(t, f.Close())
+}
+
 func TestChartWithLogarithmicBase(t *testing.T) {
 	// Create test XLSX file with data
 	f := NewFile()
@@ -503,3 +830,183 @@ func TestChartWithLogarithmicBase(t *testing.T) {
 		}
 	}
 }
+
+func TestGetCharts(t *testing.T) {
+	f := NewFile()
+	// Test get charts on no chart worksheet
+	charts, err := f.GetCharts("Sheet1")
+	assert.NoError(t, err)
+	assert.Empty(t, charts)
+	maximum, minimum := 100.0, 10.0
+	err = f.AddChart("Sheet1", "E1", &Chart{
+		Type: ColStacked,
+		Series: []ChartSeries{
+			{Name: "Sheet1!$A$2", Categories: "Sheet1!$B$1:$D$1", Values: "Sheet1!$B$2:$D$2", Fill: Fill{Type: "pattern", Pattern: 1, Color: []string{"FF0000"}}},
+			{Name: "Sheet1!$A$3", CategoriesData: []string{"Apple", "Orange"}, ValuesData: []float64{2, math.NaN(), 3.5}},
+		},
+		Format:       GraphicOptions{OffsetX: 15, OffsetY: 10},
+		Legend:       ChartLegend{Position: "top", ShowLegendKey: true},
+		Title:        []RichTextRun{{Text: "Fruit "}, {Text: "Sales", Font: &Font{Bold: true, Size: 14, Color: "777777"}}},
+		VaryColors:   boolPtr(false),
+		PlotArea:     ChartPlotArea{ShowVal: true, ShowSerName: true},
+		Border:       ChartLine{Type: ChartLineNone},
+		ShowBlanksAs: "span",
+		XAxis:        ChartAxis{MajorGridLines: true, TickLabelSkip: 2, ReverseOrder: true, Title: []RichTextRun{{Text: "Fruits"}}},
+		YAxis:        ChartAxis{None: true, MinorGridLines: true, MajorUnit: 10, Maximum: &maximum, Minimum: &minimum, LogBase: 10, NumFmt: ChartNumFmt{CustomNumFmt: "0.00"}},
+	})
+	assert.NoError(t, err)
+	err = f.AddChart("Sheet1", "E20", &Chart{
+		Type: Line,
+		Series: []ChartSeries{
+			{Name: "Sheet1!$A$2", Categories: "Sheet1!$B$1:$D$1", Values: "Sheet1!$B$2:$D$2", Fill: Fill{Color: []string{"00FF00"}}, Line: ChartLine{Smooth: true, Width: 3}, Marker: ChartMarker{Symbol: "square", Size: 7}},
+		},
+		Dimension: ChartDimension{Width: 640, Height: 320},
+		Legend:    ChartLegend{Position: "none"},
+	})
+	assert.NoError(t, err)
+	err = f.AddChart("Sheet1", "E40", &Chart{
+		Type:     Doughnut,
+		Series:   []ChartSeries{{Name: "Sheet1!$A$2", Categories: "Sheet1!$B$1:$D$1", Values: "Sheet1!$B$2:$D$2"}},
+		HoleSize: 30,
+	})
+	assert.NoError(t, err)
+	err = f.AddChart("Sheet1", "E60", &Chart{
+		Type:   Bubble3D,
+		Series: []ChartSeries{{Name: "Sheet1!$A$2", Categories: "Sheet1!$B$1:$D$1", Values: "Sheet1!$B$2:$D$2", Sizes: "Sheet1!$B$3:$D$3"}},
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestGetCharts.xlsx")))
+	assert.NoError(t, f.Close())
+
+	f, err = OpenFile(filepath.Join("test", "TestGetCharts.xlsx"))
+	assert.NoError(t, err)
+	charts, err = f.GetCharts("Sheet1")
+	assert.NoError(t, err)
+	assert.Len(t, charts, 4)
+
+	chart := charts[0]
+	assert.Equal(t, ColStacked, chart.Type)
+	assert.Equal(t, GraphicOptions{
+		PrintObject: boolPtr(true), Locked: boolPtr(false),
+		OffsetX: 15, OffsetY: 10, ScaleX: defaultDrawingScale, ScaleY: defaultDrawingScale,
+	}, chart.Format)
+	assert.Equal(t, ChartDimension{Width: defaultChartDimensionWidth, Height: defaultChartDimensionHeight}, chart.Dimension)
+	assert.Equal(t, ChartSeries{
+		Name: "Sheet1!$A$2", Categories: "Sheet1!$B$1:$D$1", Values: "Sheet1!$B$2:$D$2",
+		Fill: Fill{Type: "pattern", Pattern: 1, Color: []string{"FF0000"}},
+	}, chart.Series[0])
+	assert.Equal(t, []string{"Apple", "Orange"}, chart.Series[1].CategoriesData)
+	assert.Len(t, chart.Series[1].ValuesData, 3)
+	assert.Equal(t, 2.0, chart.Series[1].ValuesData[0])
+	assert.True(t, math.IsNaN(chart.Series[1].ValuesData[1]))
+	assert.Equal(t, 3.5, chart.Series[1].ValuesData[2])
+	assert.Equal(t, ChartLegend{Position: "top", ShowLegendKey: true}, chart.Legend)
+	assert.Equal(t, []RichTextRun{{Text: "Fruit ", Font: &Font{Size: 14, Color: "595959"}}, {Text: "Sales", Font: &Font{Bold: true, Size: 14, Color: "777777"}}}, chart.Title)
+	assert.Equal(t, boolPtr(false), chart.VaryColors)
+	assert.Equal(t, ChartPlotArea{ShowVal: true, ShowSerName: true}, chart.PlotArea)
+	assert.Equal(t, ChartLine{Type: ChartLineNone, Width: 0.75}, chart.Border)
+	assert.Equal(t, "span", chart.ShowBlanksAs)
+	assert.Equal(t, ChartAxis{
+		MajorGridLines: true, TickLabelSkip: 2, ReverseOrder: true,
+		NumFmt: ChartNumFmt{CustomNumFmt: "General"}, Title: []RichTextRun{{Text: "Fruits"}},
+	}, chart.XAxis)
+	assert.Equal(t, ChartAxis{
+		None: true, MinorGridLines: true, MajorUnit: 10, Maximum: &maximum, Minimum: &minimum,
+		LogBase: 10, NumFmt: ChartNumFmt{CustomNumFmt: "0.00"},
+	}, chart.YAxis)
+
+	chart = charts[1]
+	assert.Equal(t, Line, chart.Type)
+	assert.Equal(t, ChartDimension{Width: 640, Height: 320}, chart.Dimension)
+	assert.Equal(t, "none", chart.Legend.Position)
+	assert.Equal(t, ChartSeries{
+		Name: "Sheet1!$A$2", Categories: "Sheet1!$B$1:$D$1", Values: "Sheet1!$B$2:$D$2",
+		Fill:   Fill{Type: "pattern", Pattern: 1, Color: []string{"00FF00"}},
+		Line:   ChartLine{Smooth: true, Width: 3},
+		Marker: ChartMarker{Symbol: "square", Size: 7},
+	}, chart.Series[0])
+
+	chart = charts[2]
+	assert.Equal(t, Doughnut, chart.Type)
+	assert.Equal(t, 30, chart.HoleSize)
+	assert.Equal(t, ChartAxis{}, chart.XAxis)
+
+	chart = charts[3]
+	assert.Equal(t, Bubble3D, chart.Type)
+	assert.Equal(t, ChartSeries{
+		Name: "Sheet1!$A$2", Categories: "Sheet1!$B$1:$D$1", Values: "Sheet1!$B$2:$D$2", Sizes: "Sheet1!$B$3:$D$3",
+	}, chart.Series[0])
+
+	// Test read-modify-write the chart
+	chart = charts[0]
+	chart.Type = BarStacked
+	chart.Title = []RichTextRun{{Text: "Modified"}}
+	err = f.AddChart("Sheet2", "A1", &chart)
+	assert.EqualError(t, err, "sheet Sheet2 does not exist")
+	_, err = f.NewSheet("Sheet2")
+	assert.NoError(t, err)
+	assert.NoError(t, f.AddChart("Sheet2", "A1", &chart))
+	charts, err = f.GetCharts("Sheet2")
+	assert.NoError(t, err)
+	assert.Len(t, charts, 1)
+	assert.Equal(t, BarStacked, charts[0].Type)
+	assert.Equal(t, chart.Series[0], charts[0].Series[0])
+	assert.Equal(t, []RichTextRun{{Text: "Modified", Font: &Font{Size: 14, Color: "595959"}}}, charts[0].Title)
+
+	// Test get charts with invalid sheet name
+	_, err = f.GetCharts("Sheet:1")
+	assert.EqualError(t, err, ErrSheetNameInvalid.Error())
+	// Test get charts on not exists worksheet
+	_, err = f.GetCharts("SheetN")
+	assert.EqualError(t, err, "sheet SheetN does not exist")
+	// Test get charts with unsupported charset chart part
+	f.Pkg.Store("xl/charts/chart1.xml", MacintoshCyrillicCharset)
+	_, err = f.GetCharts("Sheet1")
+	assert.EqualError(t, err, "XML syntax error on line 1: invalid UTF-8")
+	assert.NoError(t, f.Close())
+
+	// Test get charts created by Excel
+	f, err = OpenFile(filepath.Join("test", "Book1.xlsx"))
+	assert.NoError(t, err)
+	charts, err = f.GetCharts("Sheet1")
+	assert.NoError(t, err)
+	assert.Len(t, charts, 2)
+	assert.Equal(t, Doughnut, charts[0].Type)
+	assert.Equal(t, "Sheet2!$B$2:$B$5", charts[0].Series[0].Values)
+	assert.Equal(t, Col, charts[1].Type)
+	assert.Equal(t, "Sheet2!$D$2:$D$11", charts[1].Series[0].Values)
+	assert.NoError(t, f.Close())
+
+	// Test get charts with unsupported charset drawing part
+	f, err = OpenFile(filepath.Join("test", "TestGetCharts.xlsx"))
+	assert.NoError(t, err)
+	f.Drawings.Delete("xl/drawings/drawing1.xml")
+	f.Pkg.Store("xl/drawings/drawing1.xml", MacintoshCyrillicCharset)
+	_, err = f.GetCharts("Sheet1")
+	assert.EqualError(t, err, "XML syntax error on line 1: invalid UTF-8")
+	assert.NoError(t, f.Close())
+}
+
+func TestGetChartsType(t *testing.T) {
+	f := NewFile()
+	series := []ChartSeries{{Name: "Sheet1!$A$2", Categories: "Sheet1!$B$1:$D$1", Values: "Sheet1!$B$2:$D$2"}}
+	var chartTypes []ChartType
+	for chartType := Area; chartType <= Bubble3D; chartType++ {
+		assert.NoError(t, f.AddChart("Sheet1", "A1", &Chart{Type: chartType, Series: series}))
+		chartTypes = append(chartTypes, chartType)
+	}
+	charts, err := f.GetCharts("Sheet1")
+	assert.NoError(t, err)
+	assert.Len(t, charts, len(chartTypes))
+	for idx, chart := range charts {
+		assert.Equal(t, chartTypes[idx], chart.Type, idx)
+	}
+	// Test get chart type with unknown element settings
+	chartType, charts2 := f.getChartType(&decodeChartPlotArea{BarChart: []*decodeCharts{{BarDir: &attrValString{Val: stringPtr("unknown")}}}})
+	assert.Equal(t, Col, chartType)
+	assert.NotNil(t, charts2)
+	chartType, charts2 = f.getChartType(&decodeChartPlotArea{})
+	assert.Equal(t, Area, chartType)
+	assert.Nil(t, charts2)
+	assert.NoError(t, f.Close())
+}
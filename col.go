@@ -25,6 +25,8 @@ import (
 const (
 	defaultColWidth        float64 = 9.140625
 	defaultColWidthPixels  float64 = 64
+	defaultFontSize        float64 = 11
+	defaultMaxDigitWidth   float64 = 7
 	defaultRowHeight       float64 = 15
 	defaultRowHeightPixels float64 = 20
 	EMU                    int     = 9525
@@ -332,25 +334,76 @@ func (f *File) SetColVisible(sheet, columns string, visible bool) error {
 //
 //	level, err := f.GetColOutlineLevel("Sheet1", "D")
 func (f *File) GetColOutlineLevel(sheet, col string) (uint8, error) {
-	level := uint8(0)
 	colNum, err := ColumnNameToNumber(col)
 	if err != nil {
-		return level, err
+		return 0, err
 	}
 	ws, err := f.workSheetReader(sheet)
 	if err != nil {
 		return 0, err
 	}
+	return ws.getColOutlineLevel(colNum), err
+}
+
+// getColOutlineLevel provides a function to get outline level of a single
+// column by given column number.
+func (ws *xlsxWorksheet) getColOutlineLevel(col int) uint8 {
+	var level uint8
 	if ws.Cols == nil {
-		return level, err
+		return level
 	}
 	for c := range ws.Cols.Col {
-		colData := &ws.Cols.Col[c]
-		if colData.Min <= colNum && colNum <= colData.Max {
+		if colData := &ws.Cols.Col[c]; colData.Min <= col && col <= colData.Max {
 			level = colData.OutlineLevel
 		}
 	}
-	return level, err
+	return level
+}
+
+// collapseOutlineCols provides a function to set the hidden and collapsed
+// flags of the grouped columns by given collapse outline level.
+func (ws *xlsxWorksheet) collapseOutlineCols(level uint8) {
+	if ws.Cols == nil {
+		return
+	}
+	summaryRight := ws.SheetPr == nil || ws.SheetPr.OutlinePr == nil ||
+		ws.SheetPr.OutlinePr.SummaryRight == nil || *ws.SheetPr.OutlinePr.SummaryRight
+	summaryCols := map[int]bool{}
+	for c := range ws.Cols.Col {
+		colData := &ws.Cols.Col[c]
+		if colData.OutlineLevel == 0 {
+			continue
+		}
+		colData.Hidden = level > 0 && colData.OutlineLevel >= level
+		summary := colData.Min - 1
+		if summaryRight {
+			summary = colData.Max + 1
+		}
+		if summary < 1 || summary > MaxColumns {
+			continue
+		}
+		if summaryLevel := ws.getColOutlineLevel(summary); summaryLevel < colData.OutlineLevel {
+			summaryCols[summary] = isOutlineCollapsed(summaryLevel, level)
+		}
+	}
+	for c := range ws.Cols.Col {
+		ws.Cols.Col[c].Collapsed = false
+	}
+	for col, collapsed := range summaryCols {
+		if !collapsed {
+			continue
+		}
+		ws.Cols.Col = flatCols(xlsxCol{Min: col, Max: col, Collapsed: true}, ws.Cols.Col, func(fc, c xlsxCol) xlsxCol {
+			fc.BestFit = c.BestFit
+			fc.CustomWidth = c.CustomWidth
+			fc.Hidden = c.Hidden
+			fc.OutlineLevel = c.OutlineLevel
+			fc.Phonetic = c.Phonetic
+			fc.Style = c.Style
+			fc.Width = c.Width
+			return fc
+		})
+	}
 }
 
 // parseColRange parse and convert column range with column name to the column number.
@@ -524,6 +577,46 @@ func (f *File) SetColWidth(sheet, startCol, endCol string, width float64) error
 	return err
 }
 
+// SetColWidthPixels provides a function to set the width of a single column
+// or multiple columns in pixels. The width will be converted with the maximum
+// digit width of the workbook default font, and stored in the same precision
+// as Excel, so that the width in pixels will not drift after the workbook be
+// saved by Excel. This function is concurrency safe. For example, set the
+// width of column A to H in 100 pixels:
+//
+//	err := f.SetColWidthPixels("Sheet1", "A", "H", 100)
+func (f *File) SetColWidthPixels(sheet, startCol, endCol string, pixels int) error {
+	if pixels < 0 {
+		return ErrColumnWidth
+	}
+	f.mu.Lock()
+	maxDigitWidth := f.getMaxDigitWidth()
+	f.mu.Unlock()
+	return f.SetColWidth(sheet, startCol, endCol, convertPixelsToColWidth(float64(pixels), maxDigitWidth))
+}
+
+// GetColWidthPixels provides a function to get column width in pixels by
+// given worksheet name and column name. The width will be converted with the
+// maximum digit width of the workbook default font. This function is
+// concurrency safe.
+func (f *File) GetColWidthPixels(sheet, col string) (int, error) {
+	colNum, err := ColumnNameToNumber(col)
+	if err != nil {
+		return int(defaultColWidthPixels), err
+	}
+	f.mu.Lock()
+	maxDigitWidth := f.getMaxDigitWidth()
+	ws, err := f.workSheetReader(sheet)
+	if err != nil {
+		f.mu.Unlock()
+		return int(defaultColWidthPixels), err
+	}
+	f.mu.Unlock()
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+	return ws.getColWidthPixels(colNum, maxDigitWidth, convertColWidthToExactPixels), err
+}
+
 // flatCols provides a method for the column's operation functions to flatten
 // and check the worksheet columns.
 func flatCols(col xlsxCol, cols []xlsxCol, replacer func(fc, c xlsxCol) xlsxCol) []xlsxCol {
@@ -642,9 +735,24 @@ func (f *File) positionObjectPixels(sheet string, col, row, x1, y1, width, heigh
 // getColWidth provides a function to get column width in pixels by given
 // sheet name and column number.
 func (f *File) getColWidth(sheet string, col int) int {
+	maxDigitWidth := f.getMaxDigitWidth()
 	ws, _ := f.workSheetReader(sheet)
 	ws.mu.Lock()
 	defer ws.mu.Unlock()
+	return ws.getColWidth(col, maxDigitWidth)
+}
+
+// getColWidth provides a function to get column width in pixels for the
+// placement of the graphical objects by given column number and the maximum
+// digit width of the default font.
+func (ws *xlsxWorksheet) getColWidth(col int, maxDigitWidth float64) int {
+	return ws.getColWidthPixels(col, maxDigitWidth, convertColWidthToPixels)
+}
+
+// getColWidthPixels provides a function to get column width in pixels by
+// given column number, the maximum digit width of the default font and the
+// function which converts the column width to pixels.
+func (ws *xlsxWorksheet) getColWidthPixels(col int, maxDigitWidth float64, convert func(width, maxDigitWidth float64) float64) int {
 	if ws.Cols != nil {
 		var width float64
 		for _, v := range ws.Cols.Col {
@@ -653,14 +761,19 @@ func (f *File) getColWidth(sheet string, col int) int {
 			}
 		}
 		if width != 0 {
-			return int(convertColWidthToPixels(width))
+			return int(convert(width, maxDigitWidth))
 		}
 	}
 	if ws.SheetFormatPr != nil && ws.SheetFormatPr.DefaultColWidth > 0 {
-		return int(convertColWidthToPixels(ws.SheetFormatPr.DefaultColWidth))
+		return int(convert(ws.SheetFormatPr.DefaultColWidth, maxDigitWidth))
 	}
-	// Optimization for when the column widths haven't changed.
-	return int(defaultColWidthPixels)
+	if maxDigitWidth == defaultMaxDigitWidth {
+		// Optimization for when the column widths haven't changed.
+		return int(defaultColWidthPixels)
+	}
+	// The default column width is 8 characters with padding, and rounded up
+	// to the nearest multiple of 8 pixels
+	return int(math.Ceil((8*maxDigitWidth+5)/8) * 8)
 }
 
 // GetColStyle provides a function to get column style ID by given worksheet
@@ -735,6 +848,7 @@ func (f *File) GetColWidth(sheet, col string) (float64, error) {
 // worksheet, it will cause a file error when you open it. The excelize only
 // partially updates these references currently.
 func (f *File) InsertCols(sheet, col string, n int) error {
+	defer f.dispatchWatchEvents()
 	num, err := ColumnNameToNumber(col)
 	if err != nil {
 		return err
@@ -755,6 +869,7 @@ func (f *File) InsertCols(sheet, col string, n int) error {
 // worksheet, it will cause a file error when you open it. The excelize only
 // partially updates these references currently.
 func (f *File) RemoveCol(sheet, col string) error {
+	defer f.dispatchWatchEvents()
 	num, err := ColumnNameToNumber(col)
 	if err != nil {
 		return err
@@ -780,11 +895,11 @@ func (f *File) RemoveCol(sheet, col string) error {
 // convertColWidthToPixels provides function to convert the width of a cell
 // from user's units to pixels. Excel rounds the column width to the nearest
 // pixel. If the width hasn't been set by the user we use the default value.
-// If the column is hidden it has a value of zero.
-func convertColWidthToPixels(width float64) float64 {
+// If the column is hidden it has a value of zero. The maxDigitWidth is the
+// maximum digit width of the default font in pixels.
+func convertColWidthToPixels(width, maxDigitWidth float64) float64 {
 	var padding float64 = 5
 	var pixels float64
-	var maxDigitWidth float64 = 7
 	if width == 0 {
 		return pixels
 	}
@@ -795,3 +910,32 @@ func convertColWidthToPixels(width float64) float64 {
 	pixels = (width*maxDigitWidth + 0.5) + padding
 	return math.Ceil(pixels)
 }
+
+// convertColWidthToExactPixels provides function to convert the width of a
+// cell from user's units to the pixels displayed by Excel. The width stored
+// in the worksheet includes the 5 pixels of cell padding, and Excel truncates
+// the column width to the whole pixel by the formula of ECMA-376 Part 1,
+// 18.3.1.13:
+//
+//	Truncate(((256 * {width} + Truncate(128/{Maximum Digit Width}))/256)*{Maximum Digit Width})
+//
+// If the column is hidden it has a value of zero. The maxDigitWidth is the
+// maximum digit width of the default font in pixels.
+func convertColWidthToExactPixels(width, maxDigitWidth float64) float64 {
+	if width <= 0 {
+		return 0
+	}
+	return math.Trunc((256*width + math.Trunc(128/maxDigitWidth)) / 256 * maxDigitWidth)
+}
+
+// convertPixelsToColWidth provides function to convert the width of a cell
+// from pixels to user's units in the precision of 1/256 of the maximum digit
+// width, the result is the same as the width saved by Excel, which could be
+// converted back to the same pixels without drift. The maxDigitWidth is the
+// maximum digit width of the default font in pixels.
+func convertPixelsToColWidth(pixels, maxDigitWidth float64) float64 {
+	if pixels <= 0 {
+		return 0
+	}
+	return math.Trunc(pixels/maxDigitWidth*256) / 256
+}
@@ -375,6 +375,12 @@ func TestColWidth(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, 10.0, width)
 	assert.Equal(t, 76, f.getColWidth("Sheet1", 1))
+	// Test the placement of the graphical objects with the column width
+	colIdx, _, colEnd, _, x2, _ := f.positionObjectPixels("Sheet1", 1, 1, 0, 0, 80, 10)
+	assert.Equal(t, []int{0, 1, 4}, []int{colIdx, colEnd, x2})
+	pixels, err := f.GetColWidthPixels("Sheet1", "A")
+	assert.NoError(t, err)
+	assert.Equal(t, 70, pixels)
 
 	// Test set and get column width with illegal cell reference
 	width, err = f.GetColWidth("Sheet1", "*")
@@ -461,6 +467,53 @@ func TestRemoveCol(t *testing.T) {
 	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestRemoveCol.xlsx")))
 }
 
+func TestColWidthPixels(t *testing.T) {
+	f := NewFile()
+	width, err := f.GetColWidthPixels("Sheet1", "A")
+	assert.NoError(t, err)
+	assert.Equal(t, 64, width)
+	// Test set and get column width in pixels without drift
+	for pixels := 1; pixels <= 500; pixels++ {
+		assert.NoError(t, f.SetColWidthPixels("Sheet1", "A", "B", pixels))
+		width, err = f.GetColWidthPixels("Sheet1", "B")
+		assert.NoError(t, err)
+		assert.Equal(t, pixels, width)
+	}
+	assert.NoError(t, f.SetColWidthPixels("Sheet1", "C", "C", 100))
+	colWidth, err := f.GetColWidth("Sheet1", "C")
+	assert.NoError(t, err)
+	assert.Equal(t, 14.28515625, colWidth)
+	// Test get column width in pixels of the column width saved by Excel
+	assert.NoError(t, f.SetColWidth("Sheet1", "D", "D", 9.140625))
+	width, err = f.GetColWidthPixels("Sheet1", "D")
+	assert.NoError(t, err)
+	assert.Equal(t, 64, width)
+	// Test set and get column width in pixels with the default font size
+	assert.NoError(t, f.SetDefaultFontSize(14))
+	assert.NoError(t, f.SetColWidthPixels("Sheet1", "E", "E", 100))
+	colWidth, err = f.GetColWidth("Sheet1", "E")
+	assert.NoError(t, err)
+	assert.Equal(t, 11.109375, colWidth)
+	width, err = f.GetColWidthPixels("Sheet1", "E")
+	assert.NoError(t, err)
+	assert.Equal(t, 100, width)
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestColWidthPixels.xlsx")))
+	// Test set column width in pixels with invalid width
+	assert.Equal(t, ErrColumnWidth, f.SetColWidthPixels("Sheet1", "A", "A", -1))
+	assert.Equal(t, ErrColumnWidth, f.SetColWidthPixels("Sheet1", "A", "A", 3000))
+	// Test set and get column width in pixels with illegal column name
+	assert.EqualError(t, f.SetColWidthPixels("Sheet1", "*", "A", 100), newInvalidColumnNameError("*").Error())
+	_, err = f.GetColWidthPixels("Sheet1", "*")
+	assert.EqualError(t, err, newInvalidColumnNameError("*").Error())
+	// Test get column width in pixels on not exists worksheet
+	_, err = f.GetColWidthPixels("SheetN", "A")
+	assert.EqualError(t, err, "sheet SheetN does not exist")
+}
+
 func TestConvertColWidthToPixels(t *testing.T) {
-	assert.Equal(t, -11.0, convertColWidthToPixels(-1))
+	assert.Equal(t, -11.0, convertColWidthToPixels(-1, defaultMaxDigitWidth))
+	assert.Equal(t, 0.0, convertColWidthToExactPixels(-1, defaultMaxDigitWidth))
+	assert.Equal(t, 64.0, convertColWidthToExactPixels(defaultColWidth, defaultMaxDigitWidth))
+	assert.Equal(t, 0.0, convertPixelsToColWidth(0, defaultMaxDigitWidth))
+	assert.Equal(t, defaultColWidth, convertPixelsToColWidth(defaultColWidthPixels, defaultMaxDigitWidth))
 }
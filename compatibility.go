@@ -0,0 +1,199 @@
+// Copyright 2016 - 2023 The excelize Authors. All rights reserved. Use of
+// this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+//
+// package excelize_ch providing a set of functions that allow you to write to and
+// read from XLAM / XLSM / XLSX / XLTM / XLTX files. Supports reading and
+// writing spreadsheet documents generated by Microsoft Excel™ 2007 and later.
+// Supports complex components by high compatibility, and provided streaming
+// API for generating or reading data from a worksheet with huge amounts of
+// data. This library needs Go version 1.16 or later.
+
+package excelize_ch
+
+import (
+	"strings"
+
+	"github.com/xuri/efp"
+)
+
+// ExcelVersion is the type of Excel application versions.
+type ExcelVersion byte
+
+// This section defines the currently supported Excel application versions.
+const (
+	ExcelVersion2007 ExcelVersion = iota
+	ExcelVersion2010
+	ExcelVersion2013
+	ExcelVersion2016
+	ExcelVersion2019
+	ExcelVersion2021
+	ExcelVersion365
+)
+
+// This section defines the features could be listed in the compatibility
+// report.
+const (
+	CompatibilityFeatureChartEx         = "chartEx charts"
+	CompatibilityFeatureDynamicArray    = "dynamic array formulas"
+	CompatibilityFeatureSlicer          = "slicers"
+	CompatibilityFeatureSparkline       = "sparklines"
+	CompatibilityFeatureTableSlicer     = "table slicers"
+	CompatibilityFeatureThreadedComment = "threaded comments"
+	CompatibilityFeatureTimeline        = "timelines"
+)
+
+// CompatibilityIssue directly maps the feature used in the workbook which is
+// not supported by the target version of Excel. The Sheet is empty for the
+// features of the workbook level, the Ref is the first cell reference or the
+// package part name that uses the feature, and the Count is the number of
+// cells or package parts that use the feature.
+type CompatibilityIssue struct {
+	Feature    string
+	MinVersion ExcelVersion
+	Sheet      string
+	Ref        string
+	Count      int
+}
+
+// compatibilityPartFeatures defined the features detected by the content type
+// of the package parts and the minimum Excel versions supporting them.
+var compatibilityPartFeatures = []struct {
+	contentTypes []string
+	feature      string
+	minVersion   ExcelVersion
+}{
+	{[]string{ContentTypeSlicer}, CompatibilityFeatureSlicer, ExcelVersion2010},
+	{[]string{ContentTypeTimeline, ContentTypeTimelineCache}, CompatibilityFeatureTimeline, ExcelVersion2013},
+	{[]string{ContentTypeChartEx}, CompatibilityFeatureChartEx, ExcelVersion2016},
+	{[]string{ContentTypeThreadedComments}, CompatibilityFeatureThreadedComment, ExcelVersion2021},
+}
+
+// dynamicArrayFunctions defined the functions which return dynamic arrays.
+var dynamicArrayFunctions = map[string]bool{
+	"FILTER": true, "RANDARRAY": true, "SEQUENCE": true,
+	"SORT": true, "SORTBY": true, "UNIQUE": true,
+}
+
+// CompatibilityReport provides a function to list the features used in the
+// workbook which are not supported by the given target version of Excel, so
+// that the users could be warned before distributing the workbook to older
+// environments. The features currently detected are sparklines, slicers,
+// table slicers, timelines, chartEx charts (such as waterfall, funnel,
+// histogram and treemap charts), threaded comments, and dynamic array
+// formulas. For example, check the workbook for Excel 2010:
+//
+//	issues, err := f.CompatibilityReport(excelize.ExcelVersion2010)
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	for _, issue := range issues {
+//	    fmt.Printf("%s used in %s %s is not supported\n",
+//	        issue.Feature, issue.Sheet, issue.Ref)
+//	}
+func (f *File) CompatibilityReport(targetVersion ExcelVersion) ([]CompatibilityIssue, error) {
+	var issues []CompatibilityIssue
+	if targetVersion > ExcelVersion365 {
+		return issues, ErrParameterInvalid
+	}
+	f.mu.Lock()
+	err := f.rangeWorksheets(func(sheet string, ws *xlsxWorksheet) error {
+		ws.mu.Lock()
+		defer ws.mu.Unlock()
+		if targetVersion < ExcelVersion2010 && ws.ExtLst != nil &&
+			strings.Contains(ws.ExtLst.Ext, ExtURISparklineGroups) {
+			issues = append(issues, CompatibilityIssue{
+				Feature: CompatibilityFeatureSparkline, MinVersion: ExcelVersion2010,
+				Sheet: sheet, Count: 1,
+			})
+		}
+		if targetVersion < ExcelVersion2021 {
+			if issue := getDynamicArrayIssue(sheet, ws); issue.Count > 0 {
+				issues = append(issues, issue)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		f.mu.Unlock()
+		return issues, err
+	}
+	wb, err := f.workbookReader()
+	f.mu.Unlock()
+	if err != nil {
+		return issues, err
+	}
+	if targetVersion < ExcelVersion2013 && wb.ExtLst != nil &&
+		strings.Contains(wb.ExtLst.Ext, ExtURISlicerCachesX15) {
+		issues = append(issues, CompatibilityIssue{
+			Feature: CompatibilityFeatureTableSlicer, MinVersion: ExcelVersion2013, Count: 1,
+		})
+	}
+	content, err := f.contentTypesReader()
+	if err != nil {
+		return issues, err
+	}
+	content.mu.Lock()
+	defer content.mu.Unlock()
+	for _, feature := range compatibilityPartFeatures {
+		if targetVersion >= feature.minVersion {
+			continue
+		}
+		issue := CompatibilityIssue{Feature: feature.feature, MinVersion: feature.minVersion}
+		for _, override := range content.Overrides {
+			if inStrSlice(feature.contentTypes, override.ContentType, true) == -1 {
+				continue
+			}
+			if issue.Count == 0 {
+				issue.Ref = strings.TrimPrefix(override.PartName, "/")
+			}
+			issue.Count++
+		}
+		if issue.Count > 0 {
+			issues = append(issues, issue)
+		}
+	}
+	return issues, err
+}
+
+// getDynamicArrayIssue provides a function to get the compatibility issue of
+// the dynamic array formulas in the worksheet.
+func getDynamicArrayIssue(sheet string, ws *xlsxWorksheet) CompatibilityIssue {
+	issue := CompatibilityIssue{
+		Feature: CompatibilityFeatureDynamicArray, MinVersion: ExcelVersion2021,
+		Sheet: sheet,
+	}
+	for rowIdx := range ws.SheetData.Row {
+		for colIdx := range ws.SheetData.Row[rowIdx].C {
+			c := &ws.SheetData.Row[rowIdx].C[colIdx]
+			if c.F == nil || (c.Cm == nil && !isDynamicArrayFormula(c.F.Content)) {
+				continue
+			}
+			if issue.Count == 0 {
+				issue.Ref = c.R
+			}
+			issue.Count++
+		}
+	}
+	return issue
+}
+
+// isDynamicArrayFormula provides a function to check if the given formula
+// uses the functions which return dynamic arrays.
+func isDynamicArrayFormula(formula string) bool {
+	if formula == "" {
+		return false
+	}
+	ps := efp.ExcelParser()
+	for _, token := range ps.Parse(formula) {
+		if !isFunctionStartToken(token) {
+			continue
+		}
+		name := strings.TrimPrefix(strings.TrimPrefix(strings.ToUpper(token.TValue), "_XLFN."), "_XLWS.")
+		if dynamicArrayFunctions[name] {
+			return true
+		}
+	}
+	return false
+}
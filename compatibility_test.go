@@ -0,0 +1,94 @@
+package excelize_ch
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompatibilityReport(t *testing.T) {
+	f := NewFile()
+	issues, err := f.CompatibilityReport(ExcelVersion2007)
+	assert.NoError(t, err)
+	assert.Empty(t, issues)
+
+	assert.NoError(t, f.AddSparkline("Sheet1", &SparklineOptions{
+		Location: []string{"F1"},
+		Range:    []string{"Sheet1!A1:E1"},
+	}))
+	assert.NoError(t, f.AddTable("Sheet1", &Table{Name: "Table1", Range: "A1:D5"}))
+	assert.NoError(t, f.AddSlicer("Sheet1", &SlicerOptions{
+		Name:       "Column1",
+		Cell:       "H1",
+		TableSheet: "Sheet1",
+		TableName:  "Table1",
+		Caption:    "Column1",
+	}))
+	_, err = f.NewSheet("Sheet2")
+	assert.NoError(t, err)
+	assert.NoError(t, f.SetCellFormula("Sheet2", "A1", "SUM(B1:B3)"))
+	assert.NoError(t, f.SetCellFormula("Sheet2", "A2", "_xlfn._xlws.SORT(B1:B3)"))
+	assert.NoError(t, f.SetCellFormula("Sheet2", "A5", "SUM(unique(B1:B3))"))
+	assert.NoError(t, f.SetCellFormula("Sheet2", "A6", "\"FILTER(\"&B1"))
+	content, err := f.contentTypesReader()
+	assert.NoError(t, err)
+	content.Overrides = append(content.Overrides,
+		xlsxOverride{PartName: "/xl/charts/chartEx1.xml", ContentType: ContentTypeChartEx},
+		xlsxOverride{PartName: "/xl/charts/chartEx2.xml", ContentType: ContentTypeChartEx},
+		xlsxOverride{PartName: "/xl/threadedComments/threadedComment1.xml", ContentType: ContentTypeThreadedComments},
+	)
+
+	issues, err = f.CompatibilityReport(ExcelVersion2007)
+	assert.NoError(t, err)
+	assert.Equal(t, []CompatibilityIssue{
+		{Feature: CompatibilityFeatureSparkline, MinVersion: ExcelVersion2010, Sheet: "Sheet1", Count: 1},
+		{Feature: CompatibilityFeatureDynamicArray, MinVersion: ExcelVersion2021, Sheet: "Sheet2", Ref: "A2", Count: 2},
+		{Feature: CompatibilityFeatureTableSlicer, MinVersion: ExcelVersion2013, Count: 1},
+		{Feature: CompatibilityFeatureSlicer, MinVersion: ExcelVersion2010, Ref: "xl/slicers/slicer1.xml", Count: 1},
+		{Feature: CompatibilityFeatureChartEx, MinVersion: ExcelVersion2016, Ref: "xl/charts/chartEx1.xml", Count: 2},
+		{Feature: CompatibilityFeatureThreadedComment, MinVersion: ExcelVersion2021, Ref: "xl/threadedComments/threadedComment1.xml", Count: 1},
+	}, issues)
+
+	issues, err = f.CompatibilityReport(ExcelVersion2016)
+	assert.NoError(t, err)
+	assert.Equal(t, []CompatibilityIssue{
+		{Feature: CompatibilityFeatureDynamicArray, MinVersion: ExcelVersion2021, Sheet: "Sheet2", Ref: "A2", Count: 2},
+		{Feature: CompatibilityFeatureThreadedComment, MinVersion: ExcelVersion2021, Ref: "xl/threadedComments/threadedComment1.xml", Count: 1},
+	}, issues)
+
+	issues, err = f.CompatibilityReport(ExcelVersion365)
+	assert.NoError(t, err)
+	assert.Empty(t, issues)
+
+	// Test get compatibility report with the cell metadata of dynamic arrays
+	ws, err := f.workSheetReader("Sheet2")
+	assert.NoError(t, err)
+	cm := uint(1)
+	ws.SheetData.Row[0].C[0].Cm = &cm
+	issues, err = f.CompatibilityReport(ExcelVersion2019)
+	assert.NoError(t, err)
+	assert.Equal(t, CompatibilityIssue{Feature: CompatibilityFeatureDynamicArray, MinVersion: ExcelVersion2021, Sheet: "Sheet2", Ref: "A1", Count: 3}, issues[0])
+
+	// Test get compatibility report with invalid target version
+	_, err = f.CompatibilityReport(ExcelVersion365 + 1)
+	assert.Equal(t, ErrParameterInvalid, err)
+	assert.NoError(t, f.Close())
+
+	// Test get compatibility report with unsupported charset worksheet
+	f = NewFile()
+	f.Sheet.Delete("xl/worksheets/sheet1.xml")
+	f.Pkg.Store("xl/worksheets/sheet1.xml", MacintoshCyrillicCharset)
+	f.checked = sync.Map{}
+	_, err = f.CompatibilityReport(ExcelVersion2007)
+	assert.EqualError(t, err, "XML syntax error on line 1: invalid UTF-8")
+	assert.NoError(t, f.Close())
+
+	// Test get compatibility report with unsupported charset content types
+	f = NewFile()
+	f.ContentTypes = nil
+	f.Pkg.Store(defaultXMLPathContentTypes, MacintoshCyrillicCharset)
+	_, err = f.CompatibilityReport(ExcelVersion2007)
+	assert.EqualError(t, err, "XML syntax error on line 1: invalid UTF-8")
+	assert.NoError(t, f.Close())
+}
@@ -12,10 +12,17 @@
 package excelize_ch
 
 import (
+	"encoding/xml"
 	"fmt"
+	"io"
 	"math"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
 	"unicode/utf16"
+
+	"github.com/xuri/efp"
 )
 
 // DataValidationType defined the type of data validation.
@@ -207,6 +214,16 @@ func (dv *DataValidation) SetRange(f1, f2 interface{}, t DataValidationType, o D
 //	dv.Sqref = "A7:B8"
 //	dv.SetSqrefDropList("$E$1:$E$3")
 //	err := f.AddDataValidation("Sheet1", dv)
+//
+// The source reference range could be on another worksheet, such data
+// validation will be stored in the worksheet extension list, which requires
+// Excel 2010 or later. For example, set data validation on Sheet1!A9:B10
+// with validation criteria source Sheet2!A1:A10:
+//
+//	dv := excelize.NewDataValidation(true)
+//	dv.Sqref = "A9:B10"
+//	dv.SetSqrefDropList("Sheet2!$A$1:$A$10")
+//	err := f.AddDataValidation("Sheet1", dv)
 func (dv *DataValidation) SetSqrefDropList(sqref string) {
 	dv.Formula1 = sqref
 	dv.Type = dataValidationTypeMap[DataValidationTypeList]
@@ -221,6 +238,95 @@ func (dv *DataValidation) SetSqref(sqref string) {
 	dv.Sqref = fmt.Sprintf("%s %s", dv.Sqref, sqref)
 }
 
+// GetType provides a function to get the type of the data validation rule,
+// the DataValidationTypeNone will be returned if the type is not set, and
+// zero will be returned for the unsupported type.
+func (dv *DataValidation) GetType() DataValidationType {
+	if dv.Type == "" {
+		return DataValidationTypeNone
+	}
+	for typ, name := range dataValidationTypeMap {
+		if name == dv.Type {
+			return typ
+		}
+	}
+	return 0
+}
+
+// GetOperator provides a function to get the operator of the data validation
+// rule, the DataValidationOperatorBetween will be returned if the operator is
+// not set, and zero will be returned for the unsupported operator.
+func (dv *DataValidation) GetOperator() DataValidationOperator {
+	if dv.Operator == "" {
+		return DataValidationOperatorBetween
+	}
+	for operator, name := range dataValidationOperatorMap {
+		if name == dv.Operator {
+			return operator
+		}
+	}
+	return 0
+}
+
+// GetErrorStyle provides a function to get the error alert style of the data
+// validation rule, the DataValidationErrorStyleStop will be returned if the
+// style is not set, and zero will be returned for the unsupported style.
+func (dv *DataValidation) GetErrorStyle() DataValidationErrorStyle {
+	if dv.ErrorStyle == nil {
+		return DataValidationErrorStyleStop
+	}
+	switch *dv.ErrorStyle {
+	case "", styleStop:
+		return DataValidationErrorStyleStop
+	case styleWarning:
+		return DataValidationErrorStyleWarning
+	case styleInformation:
+		return DataValidationErrorStyleInformation
+	}
+	return 0
+}
+
+// GetRanges provides a function to get the cell ranges of the data validation
+// rule by parsing the reference sequence, each cell range is represented by
+// the sorted coordinates of the top-left and bottom-right cells in the order
+// of column and row. For example, get the cell ranges of the sequence
+// "A1:B2 D4":
+//
+//	ranges, err := dv.GetRanges()
+//
+// The ranges are: [[1 1 2 2] [4 4 4 4]]
+func (dv *DataValidation) GetRanges() ([][]int, error) {
+	var ranges [][]int
+	for _, ref := range strings.Fields(dv.Sqref) {
+		coordinates, err := getDropListMappingRange(ref)
+		if err != nil {
+			return ranges, err
+		}
+		ranges = append(ranges, coordinates)
+	}
+	return ranges, nil
+}
+
+// AppliesTo provides a function to check if the data validation rule applies
+// to the given cell reference, returns false if the cell reference or the
+// reference sequence of the rule is invalid.
+func (dv *DataValidation) AppliesTo(cell string) bool {
+	col, row, err := CellNameToCoordinates(cell)
+	if err != nil {
+		return false
+	}
+	ranges, err := dv.GetRanges()
+	if err != nil {
+		return false
+	}
+	for _, coordinates := range ranges {
+		if coordinates[0] <= col && col <= coordinates[2] && coordinates[1] <= row && row <= coordinates[3] {
+			return true
+		}
+	}
+	return false
+}
+
 // AddDataValidation provides set data validation on a range of the worksheet
 // by given data validation object and worksheet name. The data validation
 // object can be created by NewDataValidation function.
@@ -256,14 +362,42 @@ func (f *File) AddDataValidation(sheet string, dv *DataValidation) error {
 	if err != nil {
 		return err
 	}
+	return f.addDataValidation(ws, sheet, dv)
+}
+
+// addDataValidation provides a function to append the data validation rule
+// to the worksheet, the rule referencing the cells of other worksheets will
+// be appended to the worksheet extension list.
+func (f *File) addDataValidation(ws *xlsxWorksheet, sheet string, dv *DataValidation) error {
+	dvs, err := f.getX14DataValidations(ws)
+	if err != nil {
+		return err
+	}
+	var count int
+	if ws.DataValidations != nil {
+		count = len(ws.DataValidations.DataValidation)
+	}
+	order := ws.dataValidationsOrder(count, len(dvs))
+	if isCrossSheetDataValidation(sheet, dv) {
+		dataValidation := *dv
+		dataValidation.Formula1 = formulaUnescaper.Replace(dv.Formula1)
+		dataValidation.Formula2 = formulaUnescaper.Replace(dv.Formula2)
+		f.addSheetNameSpace(sheet, NameSpaceSpreadSheetX14)
+		if err = f.setX14DataValidations(ws, append(dvs, &dataValidation)); err == nil {
+			ws.dvOrder = append(order, true)
+		}
+		return err
+	}
 	if nil == ws.DataValidations {
 		ws.DataValidations = new(xlsxDataValidations)
 	}
+	ws.dvOrder = append(order, false)
 	dataValidation := &xlsxDataValidation{
 		AllowBlank:       dv.AllowBlank,
 		Error:            dv.Error,
 		ErrorStyle:       dv.ErrorStyle,
 		ErrorTitle:       dv.ErrorTitle,
+		ImeMode:          dv.ImeMode,
 		Operator:         dv.Operator,
 		Prompt:           dv.Prompt,
 		PromptTitle:      dv.PromptTitle,
@@ -281,26 +415,100 @@ func (f *File) AddDataValidation(sheet string, dv *DataValidation) error {
 	}
 	ws.DataValidations.DataValidation = append(ws.DataValidations.DataValidation, dataValidation)
 	ws.DataValidations.Count = len(ws.DataValidations.DataValidation)
-	return err
+	return nil
 }
 
-// GetDataValidations returns data validations list by given worksheet name.
-func (f *File) GetDataValidations(sheet string) ([]*DataValidation, error) {
-	ws, err := f.workSheetReader(sheet)
-	if err != nil {
-		return nil, err
+// isCrossSheetDataValidation provides a function to check if the formulas of
+// the data validation rule referencing the range on the other worksheet than
+// the given worksheet, such rules should be stored in the worksheet extension
+// list.
+func isCrossSheetDataValidation(sheet string, dv *DataValidation) bool {
+	for _, formula := range []string{dv.Formula1, dv.Formula2} {
+		if formula == "" {
+			continue
+		}
+		ps := efp.ExcelParser()
+		for _, token := range ps.Parse(formulaUnescaper.Replace(formula)) {
+			if token.TType != efp.TokenTypeOperand || token.TSubType != efp.TokenSubTypeRange {
+				continue
+			}
+			if idx := strings.LastIndex(token.TValue, "!"); idx != -1 {
+				ref := strings.Trim(strings.ReplaceAll(token.TValue[:idx], "''", "'"), "'")
+				if !strings.EqualFold(ref, sheet) {
+					return true
+				}
+			}
+		}
 	}
-	if ws.DataValidations == nil || len(ws.DataValidations.DataValidation) == 0 {
-		return nil, err
+	return false
+}
+
+// dataValidationsOrder provides a function to get the insertion order of the
+// data validation rules by given number of the rules in the worksheet and in
+// the worksheet extension list, each element indicates if the rule is stored
+// in the worksheet extension list. The rules in the worksheet extension list
+// will be placed after others if the order is unknown.
+func (ws *xlsxWorksheet) dataValidationsOrder(count, x14Count int) []bool {
+	var n int
+	for _, ext := range ws.dvOrder {
+		if ext {
+			n++
+		}
+	}
+	if len(ws.dvOrder) != count+x14Count || n != x14Count {
+		ws.dvOrder = make([]bool, count+x14Count)
+		for i := count; i < len(ws.dvOrder); i++ {
+			ws.dvOrder[i] = true
+		}
+	}
+	return ws.dvOrder
+}
+
+// deleteDataValidationOrder provides a function to remove the data validation
+// rule from the insertion order by given index of the rule in the worksheet,
+// or in the worksheet extension list if ext is true.
+func (ws *xlsxWorksheet) deleteDataValidationOrder(ext bool, idx int) {
+	for i, n := 0, 0; i < len(ws.dvOrder); i++ {
+		if ws.dvOrder[i] != ext {
+			continue
+		}
+		if n == idx {
+			ws.dvOrder = append(ws.dvOrder[:i], ws.dvOrder[i+1:]...)
+			return
+		}
+		n++
 	}
+	ws.dvOrder = nil
+}
+
+// getX14DataValidations provides a function to get the data validation rules
+// in the worksheet extension list.
+func (f *File) getX14DataValidations(ws *xlsxWorksheet) ([]*DataValidation, error) {
 	var dvs []*DataValidation
-	for _, dv := range ws.DataValidations.DataValidation {
-		if dv != nil {
+	if ws.ExtLst == nil {
+		return dvs, nil
+	}
+	decodeExtLst := new(decodeExtLst)
+	if err := f.xmlNewDecoder(strings.NewReader("<extLst>" + ws.ExtLst.Ext + "</extLst>")).
+		Decode(decodeExtLst); err != nil && err != io.EOF {
+		return dvs, err
+	}
+	for _, ext := range decodeExtLst.Ext {
+		if ext.URI != ExtURIDataValidations {
+			continue
+		}
+		decodeDataValidations := new(decodeX14DataValidations)
+		if err := f.xmlNewDecoder(strings.NewReader(ext.Content)).
+			Decode(decodeDataValidations); err != nil && err != io.EOF {
+			return dvs, err
+		}
+		for _, dv := range decodeDataValidations.DataValidation {
 			dataValidation := &DataValidation{
 				AllowBlank:       dv.AllowBlank,
 				Error:            dv.Error,
 				ErrorStyle:       dv.ErrorStyle,
 				ErrorTitle:       dv.ErrorTitle,
+				ImeMode:          dv.ImeMode,
 				Operator:         dv.Operator,
 				Prompt:           dv.Prompt,
 				PromptTitle:      dv.PromptTitle,
@@ -311,15 +519,642 @@ func (f *File) GetDataValidations(sheet string) ([]*DataValidation, error) {
 				Type:             dv.Type,
 			}
 			if dv.Formula1 != nil {
-				dataValidation.Formula1 = unescapeDataValidationFormula(dv.Formula1.Content)
+				dataValidation.Formula1 = dv.Formula1.F
 			}
 			if dv.Formula2 != nil {
-				dataValidation.Formula2 = unescapeDataValidationFormula(dv.Formula2.Content)
+				dataValidation.Formula2 = dv.Formula2.F
 			}
 			dvs = append(dvs, dataValidation)
 		}
 	}
-	return dvs, err
+	return dvs, nil
+}
+
+// setX14DataValidations provides a function to replace the data validation
+// rules in the worksheet extension list, the extension will be removed if
+// the given rules are empty.
+func (f *File) setX14DataValidations(ws *xlsxWorksheet, dvs []*DataValidation) error {
+	decodeExtLst := new(decodeExtLst)
+	if ws.ExtLst != nil {
+		if err := f.xmlNewDecoder(strings.NewReader("<extLst>" + ws.ExtLst.Ext + "</extLst>")).
+			Decode(decodeExtLst); err != nil && err != io.EOF {
+			return err
+		}
+	}
+	for i := 0; i < len(decodeExtLst.Ext); i++ {
+		if decodeExtLst.Ext[i].URI == ExtURIDataValidations {
+			decodeExtLst.Ext = append(decodeExtLst.Ext[:i], decodeExtLst.Ext[i+1:]...)
+			i--
+		}
+	}
+	if len(dvs) > 0 {
+		dataValidations := &xlsxX14DataValidations{
+			XMLNSXM: NameSpaceSpreadSheetExcel2006Main.Value,
+			Count:   len(dvs),
+		}
+		for _, dv := range dvs {
+			dataValidation := &xlsxX14DataValidation{
+				AllowBlank:       dv.AllowBlank,
+				Error:            dv.Error,
+				ErrorStyle:       dv.ErrorStyle,
+				ErrorTitle:       dv.ErrorTitle,
+				ImeMode:          dv.ImeMode,
+				Operator:         dv.Operator,
+				Prompt:           dv.Prompt,
+				PromptTitle:      dv.PromptTitle,
+				ShowDropDown:     dv.ShowDropDown,
+				ShowErrorMessage: dv.ShowErrorMessage,
+				ShowInputMessage: dv.ShowInputMessage,
+				Sqref:            dv.Sqref,
+				Type:             dv.Type,
+			}
+			if dv.Formula1 != "" {
+				dataValidation.Formula1 = &xlsxX14DataFormula{F: dv.Formula1}
+			}
+			if dv.Formula2 != "" {
+				dataValidation.Formula2 = &xlsxX14DataFormula{F: dv.Formula2}
+			}
+			dataValidations.DataValidation = append(dataValidations.DataValidation, dataValidation)
+		}
+		dataValidationsBytes, _ := xml.Marshal(dataValidations)
+		decodeExtLst.Ext = append(decodeExtLst.Ext, &xlsxExt{
+			URI: ExtURIDataValidations, Content: string(dataValidationsBytes),
+		})
+	}
+	if len(decodeExtLst.Ext) == 0 {
+		ws.ExtLst = nil
+		return nil
+	}
+	sort.Slice(decodeExtLst.Ext, func(i, j int) bool {
+		return inStrSlice(worksheetExtURIPriority, decodeExtLst.Ext[i].URI, false) <
+			inStrSlice(worksheetExtURIPriority, decodeExtLst.Ext[j].URI, false)
+	})
+	extLstBytes, err := xml.Marshal(decodeExtLst)
+	ws.ExtLst = &xlsxExtLst{Ext: strings.TrimSuffix(strings.TrimPrefix(string(extLstBytes), "<extLst>"), "</extLst>")}
+	return err
+}
+
+// AddDropListMapping provides a function to add a drop list which displays
+// the labels and maps them to the codes on a range of the worksheet by given
+// worksheet name and the drop list mapping options. The lookup table of the
+// codes and labels will be created in the next two empty columns on a hidden
+// worksheet, and two defined names with the suffix "_Codes" and "_Labels" of
+// the given name will be created to refer the codes and labels. The formulas
+// resolving the code of the selected label will be set in the cells of the
+// code range if it has been specified. For example, add the drop list of the
+// country names on Sheet1!A2:A10, and get the country codes in B2:B10:
+//
+//	err := f.AddDropListMapping("Sheet1", &excelize.DropListMappingOptions{
+//	    Name:      "Country",
+//	    Codes:     []string{"CN", "FR", "US"},
+//	    Labels:    []string{"China", "France", "United States"},
+//	    Sqref:     "A2:A10",
+//	    CodeRange: "B2:B10",
+//	})
+func (f *File) AddDropListMapping(sheet string, opts *DropListMappingOptions) error {
+	if opts == nil || opts.Sqref == "" || len(opts.Codes) == 0 || len(opts.Codes) != len(opts.Labels) {
+		return ErrParameterInvalid
+	}
+	if err := checkDefinedName(opts.Name); err != nil {
+		return err
+	}
+	if _, err := f.workSheetReader(sheet); err != nil {
+		return err
+	}
+	var inputs, codes []int
+	var err error
+	if opts.CodeRange != "" {
+		if inputs, err = getDropListMappingRange(opts.Sqref); err != nil {
+			return err
+		}
+		if codes, err = getDropListMappingRange(opts.CodeRange); err != nil {
+			return err
+		}
+		if inputs[2]-inputs[0] != codes[2]-codes[0] || inputs[3]-inputs[1] != codes[3]-codes[1] {
+			return ErrParameterInvalid
+		}
+	}
+	listSheet, col, err := f.prepareDropListSheet(opts.ListSheet)
+	if err != nil {
+		return err
+	}
+	for idx, items := range [][]string{opts.Codes, opts.Labels} {
+		cell, err := CoordinatesToCellName(col+idx+1, 1)
+		if err != nil {
+			return err
+		}
+		if err = f.SetSheetCol(listSheet, cell, &items); err != nil {
+			return err
+		}
+		ref, _ := f.coordinatesToRangeRef([]int{col + idx + 1, 1, col + idx + 1, len(items)}, true)
+		if err = f.SetDefinedName(&DefinedName{
+			Name:     opts.Name + []string{"_Codes", "_Labels"}[idx],
+			RefersTo: escapeSheetName(listSheet) + "!" + ref,
+		}); err != nil {
+			return err
+		}
+	}
+	dv := NewDataValidation(true)
+	dv.Sqref = opts.Sqref
+	dv.SetSqrefDropList(opts.Name + "_Labels")
+	if err = f.AddDataValidation(sheet, dv); err != nil || opts.CodeRange == "" {
+		return err
+	}
+	for r := 0; r <= inputs[3]-inputs[1]; r++ {
+		for c := 0; c <= inputs[2]-inputs[0]; c++ {
+			input, _ := CoordinatesToCellName(inputs[0]+c, inputs[1]+r)
+			cell, _ := CoordinatesToCellName(codes[0]+c, codes[1]+r)
+			if err = f.SetCellFormula(sheet, cell, fmt.Sprintf(
+				`IFERROR(INDEX(%s_Codes,MATCH(%s,%s_Labels,0)),"")`, opts.Name, input, opts.Name,
+			)); err != nil {
+				return err
+			}
+		}
+	}
+	return err
+}
+
+// prepareDropListSheet provides a function to create the hidden worksheet for
+// storing the drop list items if not exists, and returns the worksheet name
+// and the number of the used columns in it.
+func (f *File) prepareDropListSheet(listSheet string) (string, int, error) {
+	if listSheet == "" {
+		listSheet = "Lists"
+	}
+	if idx, _ := f.GetSheetIndex(listSheet); idx == -1 {
+		if _, err := f.NewSheet(listSheet); err != nil {
+			return listSheet, 0, err
+		}
+		if err := f.SetSheetVisible(listSheet, false); err != nil {
+			return listSheet, 0, err
+		}
+	}
+	rows, err := f.GetRows(listSheet)
+	if err != nil {
+		return listSheet, 0, err
+	}
+	var col int
+	for _, row := range rows {
+		if len(row) > col {
+			col = len(row)
+		}
+	}
+	return listSheet, col, err
+}
+
+// getDropListMappingRange provides a function to get the coordinates of the
+// cell range of the drop list mapping by given cell or range reference.
+func getDropListMappingRange(ref string) ([]int, error) {
+	if !strings.Contains(ref, ":") {
+		ref += ":" + ref
+	}
+	coordinates, err := rangeRefToCoordinates(ref)
+	if err != nil {
+		return coordinates, err
+	}
+	_ = sortCoordinates(coordinates)
+	return coordinates, err
+}
+
+// AddCascadingDropList provides a function to add the cascading (dependent)
+// drop lists in a worksheet by given worksheet name and the hierarchy of the
+// drop list items. The items of each level will be stored in a hidden
+// worksheet, and referenced by the defined names which derived from the Name
+// and the values of the parent items, spaces in the values will be replaced
+// by underscores. The Sqref specifies the cell range of each level with the
+// same size, and the drop list of each level depends on the selected values
+// of the previous levels in the same relative position. For example, create
+// the country and city drop lists in the cells A2:A10 and B2:B10 on Sheet1:
+//
+//	err := f.AddCascadingDropList("Sheet1", &excelize.CascadingDropListOptions{
+//	    Name: "Country",
+//	    Items: []excelize.CascadingDropListItem{
+//	        {Value: "China", Children: []excelize.CascadingDropListItem{
+//	            {Value: "Beijing"}, {Value: "Shanghai"},
+//	        }},
+//	        {Value: "France", Children: []excelize.CascadingDropListItem{
+//	            {Value: "Paris"}, {Value: "Lyon"},
+//	        }},
+//	    },
+//	    Sqref: []string{"A2:A10", "B2:B10"},
+//	})
+func (f *File) AddCascadingDropList(sheet string, opts *CascadingDropListOptions) error {
+	if opts == nil || len(opts.Items) == 0 || len(opts.Sqref) == 0 {
+		return ErrParameterInvalid
+	}
+	if _, err := f.workSheetReader(sheet); err != nil {
+		return err
+	}
+	ranges := make([][]int, len(opts.Sqref))
+	for idx, ref := range opts.Sqref {
+		coordinates, err := getDropListMappingRange(ref)
+		if err != nil {
+			return err
+		}
+		if idx > 0 && (coordinates[2]-coordinates[0] != ranges[0][2]-ranges[0][0] ||
+			coordinates[3]-coordinates[1] != ranges[0][3]-ranges[0][1]) {
+			return ErrParameterInvalid
+		}
+		ranges[idx] = coordinates
+	}
+	lists := getCascadingDropLists(opts.Name, opts.Items, len(opts.Sqref))
+	for _, list := range lists {
+		if err := checkDefinedName(list.name); err != nil {
+			return err
+		}
+	}
+	listSheet, col, err := f.prepareDropListSheet(opts.ListSheet)
+	if err != nil {
+		return err
+	}
+	for idx, list := range lists {
+		cell, err := CoordinatesToCellName(col+idx+1, 1)
+		if err != nil {
+			return err
+		}
+		if err = f.SetSheetCol(listSheet, cell, &list.values); err != nil {
+			return err
+		}
+		ref, _ := f.coordinatesToRangeRef([]int{col + idx + 1, 1, col + idx + 1, len(list.values)}, true)
+		if err = f.SetDefinedName(&DefinedName{
+			Name:     list.name,
+			RefersTo: escapeSheetName(listSheet) + "!" + ref,
+		}); err != nil {
+			return err
+		}
+	}
+	var parents []string
+	for idx, coordinates := range ranges {
+		dv := NewDataValidation(true)
+		dv.Sqref = opts.Sqref[idx]
+		dv.Type = dataValidationTypeMap[DataValidationTypeList]
+		dv.Formula1 = opts.Name
+		if idx > 0 {
+			dv.Formula1 = formulaEscaper.Replace(fmt.Sprintf(`INDIRECT("%s_"&%s)`, opts.Name, strings.Join(parents, `&"_"&`)))
+		}
+		if err = f.AddDataValidation(sheet, dv); err != nil {
+			return err
+		}
+		cell, _ := CoordinatesToCellName(coordinates[0], coordinates[1])
+		parents = append(parents, fmt.Sprintf(`SUBSTITUTE(%s," ","_")`, cell))
+	}
+	return err
+}
+
+// AddDependentDropList provides a function to add a drop list and a dependent
+// drop list in a worksheet by given worksheet name, the cell ranges of the
+// parent and child drop lists with the same size, and the mapping of the
+// parent items to the child items. The parent items will be sorted, and the
+// items will be stored in the hidden worksheet "Lists", and referenced by the
+// defined names with the automatically generated prefix "DependentList" and
+// a sequence number. Spaces in the parent items will be replaced by
+// underscores in the defined names, and the parent items should be valid in
+// the defined names. For example, create the category and subcategory drop
+// lists in the cells A2:A10 and B2:B10 on Sheet1:
+//
+//	err := f.AddDependentDropList("Sheet1", "A2:A10", "B2:B10", map[string][]string{
+//	    "Fruit":     {"Apple", "Orange"},
+//	    "Vegetable": {"Carrot", "Potato", "Tomato"},
+//	})
+func (f *File) AddDependentDropList(sheet, parentSqref, childSqref string, mapping map[string][]string) error {
+	if len(mapping) == 0 {
+		return ErrParameterInvalid
+	}
+	keys := make([]string, 0, len(mapping))
+	for key := range mapping {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	opts := &CascadingDropListOptions{Sqref: []string{parentSqref, childSqref}}
+	for _, key := range keys {
+		item := CascadingDropListItem{Value: key}
+		for _, value := range mapping[key] {
+			item.Children = append(item.Children, CascadingDropListItem{Value: value})
+		}
+		opts.Items = append(opts.Items, item)
+	}
+	definedNames, err := f.ExportDefinedNames()
+	if err != nil {
+		return err
+	}
+	names := map[string]struct{}{}
+	for _, dn := range definedNames {
+		names[strings.ToLower(dn.Name)] = struct{}{}
+	}
+	for i := 1; opts.Name == ""; i++ {
+		name := "DependentList" + strconv.Itoa(i)
+		if _, ok := names[strings.ToLower(name)]; !ok {
+			opts.Name = name
+		}
+	}
+	return f.AddCascadingDropList(sheet, opts)
+}
+
+// cascadingDropList directly maps the defined name and items of a level in
+// the cascading drop lists.
+type cascadingDropList struct {
+	name   string
+	values []string
+}
+
+// getCascadingDropLists provides a function to flatten the hierarchy of the
+// cascading drop list items into the lists with the defined names, the items
+// deeper than the given depth will be ignored.
+func getCascadingDropLists(name string, items []CascadingDropListItem, depth int) []cascadingDropList {
+	lists := []cascadingDropList{{name: name}}
+	for _, item := range items {
+		lists[0].values = append(lists[0].values, item.Value)
+		if depth > 1 && len(item.Children) > 0 {
+			lists = append(lists, getCascadingDropLists(
+				name+"_"+strings.ReplaceAll(item.Value, " ", "_"), item.Children, depth-1)...)
+		}
+	}
+	return lists
+}
+
+// GetDataValidations returns data validations list by given worksheet name.
+// Use the GetType, GetOperator and GetErrorStyle functions of the data
+// validation to get the settings with the default values resolved, and use
+// the AppliesTo function to check which cells the rule applies to. For
+// example, get the data validation rule of the cell B3 on Sheet1:
+//
+//	dvs, err := f.GetDataValidations("Sheet1")
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	for _, dv := range dvs {
+//	    if dv.AppliesTo("B3") {
+//	        fmt.Println(dv.GetType(), dv.GetOperator(), dv.Formula1)
+//	    }
+//	}
+func (f *File) GetDataValidations(sheet string) ([]*DataValidation, error) {
+	ws, err := f.workSheetReader(sheet)
+	if err != nil {
+		return nil, err
+	}
+	var dvs []*DataValidation
+	x14DataValidations, err := f.getX14DataValidations(ws)
+	if err != nil {
+		return dvs, err
+	}
+	var classic []*xlsxDataValidation
+	if ws.DataValidations != nil {
+		classic = ws.DataValidations.DataValidation
+	}
+	var i, j int
+	for _, ext := range ws.dataValidationsOrder(len(classic), len(x14DataValidations)) {
+		if ext {
+			dvs, j = append(dvs, x14DataValidations[j]), j+1
+			continue
+		}
+		dv := classic[i]
+		if i++; dv == nil {
+			continue
+		}
+		dataValidation := &DataValidation{
+			AllowBlank:       dv.AllowBlank,
+			Error:            dv.Error,
+			ErrorStyle:       dv.ErrorStyle,
+			ErrorTitle:       dv.ErrorTitle,
+			ImeMode:          dv.ImeMode,
+			Operator:         dv.Operator,
+			Prompt:           dv.Prompt,
+			PromptTitle:      dv.PromptTitle,
+			ShowDropDown:     dv.ShowDropDown,
+			ShowErrorMessage: dv.ShowErrorMessage,
+			ShowInputMessage: dv.ShowInputMessage,
+			Sqref:            dv.Sqref,
+			Type:             dv.Type,
+		}
+		if dv.Formula1 != nil {
+			dataValidation.Formula1 = unescapeDataValidationFormula(dv.Formula1.Content)
+		}
+		if dv.Formula2 != nil {
+			dataValidation.Formula2 = unescapeDataValidationFormula(dv.Formula2.Content)
+		}
+		dvs = append(dvs, dataValidation)
+	}
+	return dvs, nil
+}
+
+// ValidateCellValue provides a function to check if the given value meets the
+// data validation rule applied to the cell by given worksheet name, cell
+// reference and value, without setting the value of the cell. The formulas
+// of the rule will be evaluated by the formula calculation engine, and the
+// relative references in the formulas will be resolved from the given cell.
+// This function returns whether the value is valid and the data validation
+// rule applied to the cell, and returns true with a nil rule if there is no
+// data validation rule applied to the cell. The supported value types are
+// the same as the SetCellValue function. For example, check the value of the
+// cell B3 on Sheet1 before setting it:
+//
+//	valid, dv, err := f.ValidateCellValue("Sheet1", "B3", 15)
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	if !valid && dv.Error != nil {
+//	    fmt.Println(*dv.Error)
+//	}
+func (f *File) ValidateCellValue(sheet, cell string, value interface{}) (bool, *DataValidation, error) {
+	col, row, err := CellNameToCoordinates(cell)
+	if err != nil {
+		return false, nil, err
+	}
+	if cell, err = CoordinatesToCellName(col, row); err != nil {
+		return false, nil, err
+	}
+	dvs, err := f.GetDataValidations(sheet)
+	if err != nil {
+		return false, nil, err
+	}
+	for _, dv := range dvs {
+		if !dv.AppliesTo(cell) {
+			continue
+		}
+		arg, err := f.dataValidationValueArg(value)
+		if err != nil {
+			return false, dv, err
+		}
+		valid, err := f.validateDataValidationValue(sheet, cell, dv, arg)
+		return valid, dv, err
+	}
+	return true, nil, err
+}
+
+// dataValidationValueArg provides a function to convert the value to be
+// validated to the formula argument, the date and time values will be
+// converted to the serial numbers by the date system of the workbook.
+func (f *File) dataValidationValueArg(value interface{}) (formulaArg, error) {
+	switch v := value.(type) {
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		n, _ := strconv.ParseFloat(fmt.Sprint(v), 64)
+		return newNumberFormulaArg(n), nil
+	case bool:
+		return newBoolFormulaArg(v), nil
+	case nil:
+		return newEmptyFormulaArg(), nil
+	case string:
+		return newStringFormulaArg(v), nil
+	case []byte:
+		return newStringFormulaArg(string(v)), nil
+	case time.Duration:
+		return newNumberFormulaArg(v.Hours() / 24), nil
+	case time.Time:
+		var date1904 bool
+		wb, err := f.workbookReader()
+		if err != nil {
+			return newEmptyFormulaArg(), err
+		}
+		if wb != nil && wb.WorkbookPr != nil {
+			date1904 = wb.WorkbookPr.Date1904
+		}
+		n, err := timeToExcelTime(v, date1904)
+		return newNumberFormulaArg(n), err
+	}
+	return newStringFormulaArg(fmt.Sprint(value)), nil
+}
+
+// validateDataValidationValue provides a function to check if the formula
+// argument of the value meets the data validation rule by given worksheet
+// name and cell reference.
+func (f *File) validateDataValidationValue(sheet, cell string, dv *DataValidation, value formulaArg) (bool, error) {
+	if value.Type == ArgEmpty || (value.Type == ArgString && value.String == "") {
+		return dv.AllowBlank, nil
+	}
+	typ := dv.GetType()
+	switch typ {
+	case DataValidationTypeNone:
+		return true, nil
+	case DataValidationTypeCustom:
+		result, err := f.evalDataValidationFormula(sheet, cell, dv, dv.Formula1, value)
+		if err != nil {
+			return false, err
+		}
+		if list := result.ToList(); len(list) > 0 {
+			result = list[0]
+		}
+		if result.Type == ArgString {
+			return strings.EqualFold(result.String, "TRUE"), err
+		}
+		return result.Type == ArgNumber && result.Number != 0, err
+	case DataValidationTypeList:
+		return f.validateDataValidationList(sheet, cell, dv, value)
+	case DataValidationTypeTextLength:
+		value = newNumberFormulaArg(float64(len([]rune(value.Value()))))
+	default:
+		if value.Type == ArgNumber && value.Boolean {
+			return false, nil
+		}
+		if value = value.ToNumber(); value.Type != ArgNumber {
+			return false, nil
+		}
+		if typ == DataValidationTypeWhole && value.Number != math.Trunc(value.Number) {
+			return false, nil
+		}
+	}
+	var operands []float64
+	for _, formula := range []string{dv.Formula1, dv.Formula2} {
+		if formula == "" {
+			break
+		}
+		result, err := f.evalDataValidationFormula(sheet, cell, dv, formula, value)
+		if err != nil {
+			return false, err
+		}
+		n, ok := dataValidationNumber(result)
+		if !ok {
+			return false, err
+		}
+		operands = append(operands, n)
+	}
+	return compareDataValidationOperands(dv.GetOperator(), value.Number, operands), nil
+}
+
+// compareDataValidationOperands provides a function to compare the number
+// with the operands of the data validation rule by given operator.
+func compareDataValidationOperands(operator DataValidationOperator, n float64, operands []float64) bool {
+	if len(operands) == 0 {
+		return false
+	}
+	switch operator {
+	case DataValidationOperatorBetween, DataValidationOperatorNotBetween:
+		if len(operands) < 2 {
+			return false
+		}
+		low, high := operands[0], operands[1]
+		if low > high {
+			low, high = high, low
+		}
+		return (low <= n && n <= high) == (operator == DataValidationOperatorBetween)
+	case DataValidationOperatorEqual:
+		return n == operands[0]
+	case DataValidationOperatorNotEqual:
+		return n != operands[0]
+	case DataValidationOperatorGreaterThan:
+		return n > operands[0]
+	case DataValidationOperatorGreaterThanOrEqual:
+		return n >= operands[0]
+	case DataValidationOperatorLessThan:
+		return n < operands[0]
+	case DataValidationOperatorLessThanOrEqual:
+		return n <= operands[0]
+	}
+	return false
+}
+
+// validateDataValidationList provides a function to check if the formula
+// argument of the value is one of the items of the drop list data validation
+// rule, the items are compared case-insensitively.
+func (f *File) validateDataValidationList(sheet, cell string, dv *DataValidation, value formulaArg) (bool, error) {
+	var items []string
+	if strings.HasPrefix(dv.Formula1, "\"") {
+		items = strings.Split(strings.Trim(dv.Formula1, "\""), ",")
+	} else {
+		result, err := f.evalDataValidationFormula(sheet, cell, dv, dv.Formula1, value)
+		if err != nil {
+			return false, err
+		}
+		for _, item := range result.ToList() {
+			items = append(items, item.Value())
+		}
+	}
+	for _, item := range items {
+		if strings.EqualFold(strings.TrimSpace(item), value.Value()) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// evalDataValidationFormula provides a function to evaluate the formula of
+// the data validation rule for the given cell, the relative references in
+// the formula will be resolved from the cell, and the value of the cell will
+// be replaced with the given formula argument in the calculation.
+func (f *File) evalDataValidationFormula(sheet, cell string, dv *DataValidation, formula string, value formulaArg) (formulaArg, error) {
+	ranges, _ := dv.GetRanges()
+	anchor, _ := CoordinatesToCellName(ranges[0][0], ranges[0][1])
+	return f.evalRelativeFormula(sheet, cell, anchor, formula, map[string]formulaArg{fmt.Sprintf("%s!%s", sheet, cell): value})
+}
+
+// dataValidationNumber provides a function to convert the formula argument
+// to the number for the data validation rule, the date and time string
+// returned by the formula functions will be converted to the serial number.
+func dataValidationNumber(arg formulaArg) (float64, bool) {
+	if list := arg.ToList(); len(list) > 0 {
+		arg = list[0]
+	}
+	if arg.Type == ArgString {
+		if t, err := time.Parse("2006-01-02 15:04:05 -0700 MST", arg.String); err == nil {
+			n, err := timeToExcelTime(t, false)
+			return n, err == nil
+		}
+	}
+	if arg = arg.ToNumber(); arg.Type != ArgNumber {
+		return 0, false
+	}
+	return arg.Number, true
 }
 
 // DeleteDataValidation delete data validation by given worksheet name and
@@ -330,46 +1165,73 @@ func (f *File) DeleteDataValidation(sheet string, sqref ...string) error {
 	if err != nil {
 		return err
 	}
-	if ws.DataValidations == nil {
-		return nil
+	x14DataValidations, err := f.getX14DataValidations(ws)
+	if err != nil {
+		return err
 	}
 	if sqref == nil {
 		ws.DataValidations = nil
-		return nil
+		if x14DataValidations == nil {
+			return nil
+		}
+		return f.setX14DataValidations(ws, nil)
 	}
 	delCells, err := f.flatSqref(sqref[0])
 	if err != nil {
 		return err
 	}
-	dv := ws.DataValidations
-	for i := 0; i < len(dv.DataValidation); i++ {
-		var applySqref []string
-		colCells, err := f.flatSqref(dv.DataValidation[i].Sqref)
-		if err != nil {
-			return err
-		}
-		for col, cells := range delCells {
-			for _, cell := range cells {
-				idx := inCoordinates(colCells[col], cell)
-				if idx != -1 {
-					colCells[col] = append(colCells[col][:idx], colCells[col][idx+1:]...)
-				}
+	if dv := ws.DataValidations; dv != nil {
+		for i := 0; i < len(dv.DataValidation); i++ {
+			if dv.DataValidation[i].Sqref, err = f.deleteSqrefCells(dv.DataValidation[i].Sqref, delCells); err != nil {
+				return err
 			}
+			if dv.DataValidation[i].Sqref == "" {
+				ws.deleteDataValidationOrder(false, i)
+				dv.DataValidation = append(dv.DataValidation[:i], dv.DataValidation[i+1:]...)
+				i--
+			}
+		}
+		dv.Count = len(dv.DataValidation)
+		if dv.Count == 0 {
+			ws.DataValidations = nil
 		}
-		for _, col := range colCells {
-			applySqref = append(applySqref, f.squashSqref(col)...)
+	}
+	if x14DataValidations == nil {
+		return nil
+	}
+	for i := 0; i < len(x14DataValidations); i++ {
+		if x14DataValidations[i].Sqref, err = f.deleteSqrefCells(x14DataValidations[i].Sqref, delCells); err != nil {
+			return err
 		}
-		dv.DataValidation[i].Sqref = strings.Join(applySqref, " ")
-		if len(applySqref) == 0 {
-			dv.DataValidation = append(dv.DataValidation[:i], dv.DataValidation[i+1:]...)
+		if x14DataValidations[i].Sqref == "" {
+			ws.deleteDataValidationOrder(true, i)
+			x14DataValidations = append(x14DataValidations[:i], x14DataValidations[i+1:]...)
 			i--
 		}
 	}
-	dv.Count = len(dv.DataValidation)
-	if dv.Count == 0 {
-		ws.DataValidations = nil
+	return f.setX14DataValidations(ws, x14DataValidations)
+}
+
+// deleteSqrefCells provides a function to remove the given cells from the
+// reference sequence, and returns the remaining reference sequence.
+func (f *File) deleteSqrefCells(sqref string, delCells map[int][][]int) (string, error) {
+	var applySqref []string
+	colCells, err := f.flatSqref(sqref)
+	if err != nil {
+		return sqref, err
 	}
-	return nil
+	for col, cells := range delCells {
+		for _, cell := range cells {
+			idx := inCoordinates(colCells[col], cell)
+			if idx != -1 {
+				colCells[col] = append(colCells[col][:idx], colCells[col][idx+1:]...)
+			}
+		}
+	}
+	for _, col := range colCells {
+		applySqref = append(applySqref, f.squashSqref(col)...)
+	}
+	return strings.Join(applySqref, " "), err
 }
 
 // squashSqref generates cell reference sequence by given cells coordinates list.
@@ -12,10 +12,12 @@
 package excelize_ch
 
 import (
+	"fmt"
 	"math"
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -170,6 +172,168 @@ func TestDataValidationError(t *testing.T) {
 	assert.EqualError(t, f.AddDataValidation("Sheet:1", nil), ErrSheetNameInvalid.Error())
 }
 
+func TestGetDataValidationSettings(t *testing.T) {
+	f := NewFile()
+	dv := NewDataValidation(true)
+	dv.Sqref = "A1:B2 D4"
+	dv.ImeMode = "hiragana"
+	assert.NoError(t, dv.SetRange(10, 20, DataValidationTypeWhole, DataValidationOperatorNotBetween))
+	dv.SetError(DataValidationErrorStyleWarning, "title", "error")
+	assert.NoError(t, f.AddDataValidation("Sheet1", dv))
+	dv = NewDataValidation(true)
+	dv.SetSqref("C3")
+	assert.NoError(t, f.AddDataValidation("Sheet1", dv))
+	dv = NewDataValidation(true)
+	dv.Sqref = "E1:E10"
+	dv.ImeMode = "off"
+	dv.SetSqrefDropList("Sheet2!$A$1:$A$3")
+	assert.NoError(t, f.AddDataValidation("Sheet1", dv))
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestGetDataValidationSettings.xlsx")))
+	assert.NoError(t, f.Close())
+
+	f, err := OpenFile(filepath.Join("test", "TestGetDataValidationSettings.xlsx"))
+	assert.NoError(t, err)
+	dvs, err := f.GetDataValidations("Sheet1")
+	assert.NoError(t, err)
+	assert.Len(t, dvs, 3)
+	assert.Equal(t, "hiragana", dvs[0].ImeMode)
+	assert.Equal(t, DataValidationTypeWhole, dvs[0].GetType())
+	assert.Equal(t, DataValidationOperatorNotBetween, dvs[0].GetOperator())
+	assert.Equal(t, DataValidationErrorStyleWarning, dvs[0].GetErrorStyle())
+	ranges, err := dvs[0].GetRanges()
+	assert.NoError(t, err)
+	assert.Equal(t, [][]int{{1, 1, 2, 2}, {4, 4, 4, 4}}, ranges)
+	for cell, expected := range map[string]bool{"A1": true, "B2": true, "C3": false, "D4": true, "A3": false, "A": false} {
+		assert.Equal(t, expected, dvs[0].AppliesTo(cell), cell)
+	}
+	assert.Equal(t, DataValidationTypeNone, dvs[1].GetType())
+	assert.Equal(t, DataValidationOperatorBetween, dvs[1].GetOperator())
+	assert.Equal(t, DataValidationErrorStyleStop, dvs[1].GetErrorStyle())
+	assert.True(t, dvs[1].AppliesTo("C3"))
+	assert.Equal(t, "off", dvs[2].ImeMode)
+	assert.Equal(t, DataValidationTypeList, dvs[2].GetType())
+	assert.True(t, dvs[2].AppliesTo("E10"))
+	assert.NoError(t, f.Close())
+
+	// Test get data validation settings with unsupported values
+	dv = &DataValidation{Type: "unknown", Operator: "unknown", ErrorStyle: stringPtr("unknown"), Sqref: "A1:B"}
+	assert.Equal(t, DataValidationType(0), dv.GetType())
+	assert.Equal(t, DataValidationOperator(0), dv.GetOperator())
+	assert.Equal(t, DataValidationErrorStyle(0), dv.GetErrorStyle())
+	_, err = dv.GetRanges()
+	assert.Equal(t, newCellNameToCoordinatesError("B", newInvalidCellNameError("B")), err)
+	assert.False(t, dv.AppliesTo("A1"))
+	dv.ErrorStyle = stringPtr("")
+	assert.Equal(t, DataValidationErrorStyleStop, dv.GetErrorStyle())
+	dv.ErrorStyle = stringPtr(styleInformation)
+	assert.Equal(t, DataValidationErrorStyleInformation, dv.GetErrorStyle())
+}
+
+func TestValidateCellValue(t *testing.T) {
+	f := NewFile()
+	_, err := f.NewSheet("Sheet2")
+	assert.NoError(t, err)
+	assert.NoError(t, f.SetSheetCol("Sheet2", "A1", &[]interface{}{"Apple", "Banana", 3}))
+	assert.NoError(t, f.SetCellValue("Sheet1", "Z1", 100))
+	addDataValidation := func(sqref string, fn func(dv *DataValidation)) {
+		dv := NewDataValidation(false)
+		dv.Sqref = sqref
+		fn(dv)
+		assert.NoError(t, f.AddDataValidation("Sheet1", dv))
+	}
+	addDataValidation("A1:A10 C1", func(dv *DataValidation) {
+		assert.NoError(t, dv.SetRange(10, 20, DataValidationTypeWhole, DataValidationOperatorBetween))
+	})
+	addDataValidation("B1:B10", func(dv *DataValidation) {
+		assert.NoError(t, dv.SetRange(1.5, 0, DataValidationTypeDecimal, DataValidationOperatorGreaterThan))
+		dv.AllowBlank = true
+	})
+	addDataValidation("D1:D10", func(dv *DataValidation) {
+		assert.NoError(t, dv.SetDropList([]string{"Yes", "No"}))
+	})
+	addDataValidation("E1:E10", func(dv *DataValidation) {
+		dv.SetSqrefDropList("Sheet2!$A$1:$A$3")
+	})
+	addDataValidation("F1:F10", func(dv *DataValidation) {
+		assert.NoError(t, dv.SetRange(3, 5, DataValidationTypeTextLength, DataValidationOperatorNotBetween))
+	})
+	addDataValidation("G1:G10", func(dv *DataValidation) {
+		assert.NoError(t, dv.SetRange("DATE(2023,1,1)", "DATE(2023,12,31)", DataValidationTypeDate, DataValidationOperatorBetween))
+	})
+	addDataValidation("H1:H10", func(dv *DataValidation) {
+		assert.NoError(t, dv.SetRange(0.5, 0, DataValidationTypeTime, DataValidationOperatorLessThan))
+	})
+	addDataValidation("I2:I10", func(dv *DataValidation) {
+		dv.Type, dv.Formula1 = "custom", "AND(ISNUMBER(I2),I2<$Z$1)"
+	})
+	addDataValidation("J1:J10", func(dv *DataValidation) {
+		dv.Type = "none"
+	})
+	for _, c := range []struct {
+		cell  string
+		value interface{}
+		valid bool
+	}{
+		{"A1", 15, true}, {"A10", int64(20), true}, {"C1", uint8(10), true},
+		{"A5", 15.5, false}, {"A5", 21, false}, {"A5", "12", true}, {"A5", "text", false},
+		{"A5", true, false}, {"A5", nil, false}, {"A5", "", false},
+		{"B1", 1.6, true}, {"B1", float32(1.5), false}, {"B1", nil, true},
+		{"D2", "yes", true}, {"D2", "Maybe", false},
+		{"E3", "banana", true}, {"E3", []byte("Cherry"), false}, {"E3", 3, true},
+		{"F1", "ab", true}, {"F1", "abcd", false}, {"F1", 123456, true},
+		{"G1", time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC), true},
+		{"G1", time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), false},
+		{"H1", 6 * time.Hour, true}, {"H1", 18 * time.Hour, false},
+		{"I2", 50, true}, {"I9", 150, false}, {"I9", "50", false},
+		{"J1", "any", true}, {"Z9", "any", true},
+	} {
+		valid, dv, err := f.ValidateCellValue("Sheet1", c.cell, c.value)
+		assert.NoError(t, err)
+		assert.Equal(t, c.valid, valid, c.cell, c.value)
+		assert.Equal(t, c.cell == "Z9", dv == nil, c.cell)
+	}
+	// Test validate cell value with the formula result of the custom rule
+	assert.NoError(t, f.SetCellFormula("Sheet1", "Z1", "10*2"))
+	valid, _, err := f.ValidateCellValue("Sheet1", "I5", 50)
+	assert.NoError(t, err)
+	assert.False(t, valid)
+
+	// Test validate cell value with invalid cell reference
+	_, _, err = f.ValidateCellValue("Sheet1", "A", 1)
+	assert.EqualError(t, err, newCellNameToCoordinatesError("A", newInvalidCellNameError("A")).Error())
+	// Test validate cell value on not exists worksheet
+	_, _, err = f.ValidateCellValue("SheetN", "A1", 1)
+	assert.EqualError(t, err, "sheet SheetN does not exist")
+	// Test validate cell value with the relative reference out of range
+	addDataValidation("K2:K10", func(dv *DataValidation) {
+		dv.Type, dv.Formula1 = "custom", "K1>0"
+	})
+	_, _, err = f.ValidateCellValue("Sheet1", "K2", 1)
+	assert.NoError(t, err)
+	ws, ok := f.Sheet.Load("xl/worksheets/sheet1.xml")
+	assert.True(t, ok)
+	ws.(*xlsxWorksheet).DataValidations.DataValidation[8].Sqref = "K2:K10 K1"
+	_, _, err = f.ValidateCellValue("Sheet1", "K1", 1)
+	assert.EqualError(t, err, newInvalidRowNumberError(0).Error())
+	// Test validate cell value with unsupported operator
+	assert.False(t, compareDataValidationOperands(DataValidationOperatorBetween, 1, []float64{1}))
+	assert.False(t, compareDataValidationOperands(DataValidationOperator(0), 1, []float64{1}))
+	assert.False(t, compareDataValidationOperands(DataValidationOperatorEqual, 1, nil))
+	assert.NoError(t, f.Close())
+
+	// Test validate cell value with unsupported charset workbook
+	f = NewFile()
+	dv := NewDataValidation(true)
+	dv.Sqref = "A1"
+	assert.NoError(t, dv.SetRange(0, 1, DataValidationTypeDate, DataValidationOperatorBetween))
+	assert.NoError(t, f.AddDataValidation("Sheet1", dv))
+	f.WorkBook = nil
+	f.Pkg.Store(defaultXMLPathWorkbook, MacintoshCyrillicCharset)
+	_, _, err = f.ValidateCellValue("Sheet1", "A1", time.Now())
+	assert.EqualError(t, err, "XML syntax error on line 1: invalid UTF-8")
+	assert.NoError(t, f.Close())
+}
+
 func TestDeleteDataValidation(t *testing.T) {
 	f := NewFile()
 	assert.NoError(t, f.DeleteDataValidation("Sheet1", "A1:B2"))
@@ -217,3 +381,302 @@ func TestDeleteDataValidation(t *testing.T) {
 	assert.NoError(t, f.DeleteDataValidation("Sheet1"))
 	assert.Nil(t, ws.(*xlsxWorksheet).DataValidations)
 }
+
+func TestCrossSheetDataValidation(t *testing.T) {
+	f := NewFile()
+	_, err := f.NewSheet("Sheet2")
+	assert.NoError(t, err)
+	assert.NoError(t, f.SetSheetCol("Sheet2", "A1", &[]string{"A", "B", "C"}))
+	dv := NewDataValidation(true)
+	dv.Sqref = "A1:A5"
+	dv.SetSqrefDropList("$E$1:$E$3")
+	assert.NoError(t, f.AddDataValidation("Sheet1", dv))
+	dv = NewDataValidation(true)
+	dv.Sqref = "B1:B5"
+	dv.SetSqrefDropList("Sheet2!$A$1:$A$10")
+	assert.NoError(t, f.AddDataValidation("Sheet1", dv))
+	dv = NewDataValidation(true)
+	dv.Sqref = "C1:C5"
+	assert.NoError(t, dv.SetRange("'Sheet2'!A1", 10, DataValidationTypeWhole, DataValidationOperatorBetween))
+	assert.NoError(t, f.AddDataValidation("Sheet1", dv))
+
+	ws, ok := f.Sheet.Load("xl/worksheets/sheet1.xml")
+	assert.True(t, ok)
+	assert.Len(t, ws.(*xlsxWorksheet).DataValidations.DataValidation, 1)
+	assert.Contains(t, ws.(*xlsxWorksheet).ExtLst.Ext, `<x14:dataValidations xmlns:xm="http://schemas.microsoft.com/office/excel/2006/main" count="2"><x14:dataValidation allowBlank="true" type="list"><x14:formula1><xm:f>Sheet2!$A$1:$A$10</xm:f></x14:formula1><xm:sqref>B1:B5</xm:sqref></x14:dataValidation>`)
+	dvs, err := f.GetDataValidations("Sheet1")
+	assert.NoError(t, err)
+	assert.Len(t, dvs, 3)
+	assert.Equal(t, "Sheet2!$A$1:$A$10", dvs[1].Formula1)
+	assert.Equal(t, "B1:B5", dvs[1].Sqref)
+	assert.Equal(t, "'Sheet2'!A1", dvs[2].Formula1)
+	assert.Equal(t, "10", dvs[2].Formula2)
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestCrossSheetDataValidation.xlsx")))
+
+	// Test adjust the data validations in the worksheet extension list
+	assert.NoError(t, f.InsertRows("Sheet2", 1, 1))
+	assert.NoError(t, f.InsertCols("Sheet1", "A", 1))
+	dvs, err = f.GetDataValidations("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, "Sheet2!$A$2:$A$11", dvs[1].Formula1)
+	assert.Equal(t, "C1:C5", dvs[1].Sqref)
+	assert.Equal(t, "Sheet2!A2", dvs[2].Formula1)
+	assert.Equal(t, "D1:D5", dvs[2].Sqref)
+
+	// Test delete the data validations in the worksheet extension list
+	assert.NoError(t, f.DeleteDataValidation("Sheet1", "C1:C5"))
+	dvs, err = f.GetDataValidations("Sheet1")
+	assert.NoError(t, err)
+	assert.Len(t, dvs, 2)
+	assert.Equal(t, "D1:D5", dvs[1].Sqref)
+	assert.NoError(t, f.DeleteDataValidation("Sheet1"))
+	ws, ok = f.Sheet.Load("xl/worksheets/sheet1.xml")
+	assert.True(t, ok)
+	assert.Nil(t, ws.(*xlsxWorksheet).ExtLst)
+
+	// Test add cross worksheet data validation with the stream writer
+	sw, err := f.NewStreamWriter("Sheet1")
+	assert.NoError(t, err)
+	dv = NewDataValidation(true)
+	dv.Sqref = "A1:A5"
+	dv.SetSqrefDropList("Sheet2!$A$1:$A$10")
+	assert.NoError(t, sw.AddDataValidation(dv))
+	assert.NoError(t, sw.Flush())
+	dvs, err = f.GetDataValidations("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, []*DataValidation{dv}, dvs)
+
+	// Test cross worksheet data validation with unsupported charset extension
+	ws, ok = f.Sheet.Load("xl/worksheets/sheet2.xml")
+	assert.True(t, ok)
+	ws.(*xlsxWorksheet).ExtLst = &xlsxExtLst{Ext: "<ext><x14:dataValidations>\x00</x14:dataValidations></ext>"}
+	_, err = f.GetDataValidations("Sheet2")
+	assert.EqualError(t, err, "XML syntax error on line 1: illegal character code U+0000")
+	assert.EqualError(t, f.AddDataValidation("Sheet2", dv), "XML syntax error on line 1: illegal character code U+0000")
+	assert.EqualError(t, f.DeleteDataValidation("Sheet2"), "XML syntax error on line 1: illegal character code U+0000")
+	ws.(*xlsxWorksheet).ExtLst.Ext = "<ext uri=\"" + ExtURIDataValidations + "\"><x14:dataValidations>\x00</x14:dataValidations></ext>"
+	_, err = f.GetDataValidations("Sheet2")
+	assert.EqualError(t, err, "XML syntax error on line 1: illegal character code U+0000")
+	assert.EqualError(t, f.InsertRows("Sheet2", 1, 1), "XML syntax error on line 1: illegal character code U+0000")
+	assert.NoError(t, f.Close())
+
+	// Test keep the insertion order of the data validations
+	f = NewFile()
+	_, err = f.NewSheet("Sheet 2")
+	assert.NoError(t, err)
+	for i, source := range []string{"'Sheet 2'!$A$1:$A$3", "$E$1:$E$3", "Sheet1!$F$1:$F$3", "'Sheet 2'!$B$1:$B$3", "$G$1:$G$3"} {
+		dv = NewDataValidation(true)
+		dv.Sqref = fmt.Sprintf("A%d", i+1)
+		dv.SetSqrefDropList(source)
+		assert.NoError(t, f.AddDataValidation("Sheet1", dv))
+	}
+	ws, ok = f.Sheet.Load("xl/worksheets/sheet1.xml")
+	assert.True(t, ok)
+	assert.Len(t, ws.(*xlsxWorksheet).DataValidations.DataValidation, 3)
+	assert.Equal(t, "Sheet1!$F$1:$F$3", ws.(*xlsxWorksheet).DataValidations.DataValidation[1].Formula1.Content)
+	dvs, err = f.GetDataValidations("Sheet1")
+	assert.NoError(t, err)
+	var sources []string
+	for _, dv := range dvs {
+		sources = append(sources, dv.Formula1)
+	}
+	assert.Equal(t, []string{"'Sheet 2'!$A$1:$A$3", "$E$1:$E$3", "Sheet1!$F$1:$F$3", "'Sheet 2'!$B$1:$B$3", "$G$1:$G$3"}, sources)
+	assert.NoError(t, f.DeleteDataValidation("Sheet1", "A1:A2"))
+	dvs, err = f.GetDataValidations("Sheet1")
+	assert.NoError(t, err)
+	var sqrefs []string
+	for _, dv := range dvs {
+		sqrefs = append(sqrefs, dv.Sqref)
+	}
+	assert.Equal(t, []string{"A3", "A4", "A5"}, sqrefs)
+	assert.NoError(t, f.Close())
+}
+
+func TestAddDropListMapping(t *testing.T) {
+	f := NewFile()
+	assert.NoError(t, f.AddDropListMapping("Sheet1", &DropListMappingOptions{
+		Name: "Country", Codes: []string{"CN", "FR", "US"}, Labels: []string{"China", "France", "United States"},
+		Sqref: "A2:A3", CodeRange: "B2:B3",
+	}))
+	assert.NoError(t, f.AddDropListMapping("Sheet1", &DropListMappingOptions{
+		Name: "Unit", Codes: []string{"kg", "g"}, Labels: []string{"Kilogram", "Gram"}, Sqref: "C2",
+	}))
+	visible, err := f.GetSheetVisible("Lists")
+	assert.NoError(t, err)
+	assert.False(t, visible)
+	rows, err := f.GetRows("Lists")
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{
+		{"CN", "China", "kg", "Kilogram"}, {"FR", "France", "g", "Gram"}, {"US", "United States"},
+	}, rows)
+	var names []string
+	for _, dn := range f.GetDefinedName() {
+		names = append(names, dn.Name+"="+dn.RefersTo)
+	}
+	assert.Equal(t, []string{
+		"Country_Codes=Lists!$A$1:$A$3", "Country_Labels=Lists!$B$1:$B$3",
+		"Unit_Codes=Lists!$C$1:$C$2", "Unit_Labels=Lists!$D$1:$D$2",
+	}, names)
+	dvs, err := f.GetDataValidations("Sheet1")
+	assert.NoError(t, err)
+	assert.Len(t, dvs, 2)
+	assert.Equal(t, "A2:A3", dvs[0].Sqref)
+	assert.Equal(t, "Country_Labels", dvs[0].Formula1)
+	formula, err := f.GetCellFormula("Sheet1", "B3")
+	assert.NoError(t, err)
+	assert.Equal(t, `IFERROR(INDEX(Country_Codes,MATCH(A3,Country_Labels,0)),"")`, formula)
+	assert.NoError(t, f.SetCellValue("Sheet1", "A3", "France"))
+	result, err := f.CalcCellValue("Sheet1", "B3")
+	assert.NoError(t, err)
+	assert.Equal(t, "FR", result)
+	// Test add drop list mapping with invalid options
+	for _, opts := range []*DropListMappingOptions{
+		nil, {Name: "A", Sqref: "A1"},
+		{Name: "A", Codes: []string{"a"}, Labels: []string{"a", "b"}, Sqref: "A1"},
+		{Name: "A", Codes: []string{"a"}, Labels: []string{"a"}, Sqref: "A1:A2", CodeRange: "B1"},
+	} {
+		assert.Equal(t, ErrParameterInvalid, f.AddDropListMapping("Sheet1", opts))
+	}
+	assert.Equal(t, newInvalidNameError("1A"), f.AddDropListMapping("Sheet1", &DropListMappingOptions{
+		Name: "1A", Codes: []string{"a"}, Labels: []string{"a"}, Sqref: "A1",
+	}))
+	for _, opts := range []*DropListMappingOptions{
+		{Name: "A", Codes: []string{"a"}, Labels: []string{"a"}, Sqref: "A", CodeRange: "B1"},
+		{Name: "A", Codes: []string{"a"}, Labels: []string{"a"}, Sqref: "A1", CodeRange: "A"},
+	} {
+		assert.Equal(t, newCellNameToCoordinatesError("A", newInvalidCellNameError("A")), f.AddDropListMapping("Sheet1", opts))
+	}
+	// Test add drop list mapping on not exists worksheet
+	assert.EqualError(t, f.AddDropListMapping("SheetN", &DropListMappingOptions{
+		Name: "A", Codes: []string{"a"}, Labels: []string{"a"}, Sqref: "A1",
+	}), "sheet SheetN does not exist")
+	// Test add drop list mapping with invalid list sheet name
+	assert.EqualError(t, f.AddDropListMapping("Sheet1", &DropListMappingOptions{
+		Name: "A", ListSheet: "Sheet:1", Codes: []string{"a"}, Labels: []string{"a"}, Sqref: "A1",
+	}), ErrSheetNameInvalid.Error())
+	assert.NoError(t, f.Close())
+}
+
+func TestAddCascadingDropList(t *testing.T) {
+	f := NewFile()
+	assert.NoError(t, f.AddCascadingDropList("Sheet1", &CascadingDropListOptions{
+		Name: "Country",
+		Items: []CascadingDropListItem{
+			{Value: "China", Children: []CascadingDropListItem{
+				{Value: "Beijing", Children: []CascadingDropListItem{{Value: "Haidian"}, {Value: "Chaoyang"}}},
+				{Value: "Shanghai"},
+			}},
+			{Value: "United States", Children: []CascadingDropListItem{{Value: "New York"}}},
+			{Value: "France"},
+		},
+		Sqref: []string{"A2:A10", "B2:B10", "C2:C10"},
+	}))
+	visible, err := f.GetSheetVisible("Lists")
+	assert.NoError(t, err)
+	assert.False(t, visible)
+	var names []string
+	for _, dn := range f.GetDefinedName() {
+		names = append(names, dn.Name+"="+dn.RefersTo)
+	}
+	assert.Equal(t, []string{
+		"Country=Lists!$A$1:$A$3", "Country_China=Lists!$B$1:$B$2",
+		"Country_China_Beijing=Lists!$C$1:$C$2", "Country_United_States=Lists!$D$1:$D$1",
+	}, names)
+	for cell, value := range map[string]string{
+		"A1": "China", "A2": "United States", "A3": "France", "B1": "Beijing", "B2": "Shanghai",
+		"C1": "Haidian", "C2": "Chaoyang", "D1": "New York", "D2": "",
+	} {
+		val, err := f.GetCellValue("Lists", cell)
+		assert.NoError(t, err)
+		assert.Equal(t, value, val)
+	}
+	dvs, err := f.GetDataValidations("Sheet1")
+	assert.NoError(t, err)
+	assert.Len(t, dvs, 3)
+	for idx, formula := range []string{
+		"Country",
+		`INDIRECT("Country_"&SUBSTITUTE(A2," ","_"))`,
+		`INDIRECT("Country_"&SUBSTITUTE(A2," ","_")&"_"&SUBSTITUTE(B2," ","_"))`,
+	} {
+		assert.Equal(t, "list", dvs[idx].Type)
+		assert.Equal(t, formula, dvs[idx].Formula1)
+	}
+	// Test add cascading drop list with the items deeper than the levels
+	assert.NoError(t, f.AddCascadingDropList("Sheet1", &CascadingDropListOptions{
+		Name:  "Unit",
+		Items: []CascadingDropListItem{{Value: "Mass", Children: []CascadingDropListItem{{Value: "kg"}}}},
+		Sqref: []string{"D2"},
+	}))
+	val, err := f.GetCellValue("Lists", "E1")
+	assert.NoError(t, err)
+	assert.Equal(t, "Mass", val)
+	val, err = f.GetCellValue("Lists", "F1")
+	assert.NoError(t, err)
+	assert.Empty(t, val)
+	// Test add cascading drop list with invalid options
+	for _, opts := range []*CascadingDropListOptions{
+		nil, {Name: "A", Sqref: []string{"A1"}},
+		{Name: "A", Items: []CascadingDropListItem{{Value: "a"}}},
+		{Name: "A", Items: []CascadingDropListItem{{Value: "a"}}, Sqref: []string{"A1:A2", "B1"}},
+	} {
+		assert.Equal(t, ErrParameterInvalid, f.AddCascadingDropList("Sheet1", opts))
+	}
+	assert.Equal(t, newCellNameToCoordinatesError("A", newInvalidCellNameError("A")), f.AddCascadingDropList("Sheet1", &CascadingDropListOptions{
+		Name: "A", Items: []CascadingDropListItem{{Value: "a"}}, Sqref: []string{"A"},
+	}))
+	assert.Equal(t, newInvalidNameError("A_1-2"), f.AddCascadingDropList("Sheet1", &CascadingDropListOptions{
+		Name:  "A",
+		Items: []CascadingDropListItem{{Value: "1-2", Children: []CascadingDropListItem{{Value: "a"}}}},
+		Sqref: []string{"A1", "B1"},
+	}))
+	// Test add cascading drop list on not exists worksheet
+	assert.EqualError(t, f.AddCascadingDropList("SheetN", &CascadingDropListOptions{
+		Name: "A", Items: []CascadingDropListItem{{Value: "a"}}, Sqref: []string{"A1"},
+	}), "sheet SheetN does not exist")
+	// Test add cascading drop list with invalid list sheet name
+	assert.EqualError(t, f.AddCascadingDropList("Sheet1", &CascadingDropListOptions{
+		Name: "A", ListSheet: "Sheet:1", Items: []CascadingDropListItem{{Value: "a"}}, Sqref: []string{"A1"},
+	}), ErrSheetNameInvalid.Error())
+	// Test add cascading drop list with duplicate defined name
+	assert.Equal(t, ErrDefinedNameDuplicate, f.AddCascadingDropList("Sheet1", &CascadingDropListOptions{
+		Name: "Country", Items: []CascadingDropListItem{{Value: "a"}}, Sqref: []string{"E1"},
+	}))
+	assert.NoError(t, f.Close())
+}
+
+func TestAddDependentDropList(t *testing.T) {
+	f := NewFile()
+	assert.NoError(t, f.SetDefinedName(&DefinedName{Name: "DependentList1", RefersTo: "Sheet1!$A$1"}))
+	assert.NoError(t, f.AddDependentDropList("Sheet1", "A2:A10", "B2:B10", map[string][]string{
+		"Vegetable":  {"Carrot", "Potato"},
+		"Fruit":      {"Apple", "Orange"},
+		"Dried Food": nil,
+	}))
+	var names []string
+	for _, dn := range f.GetDefinedName() {
+		names = append(names, dn.Name+"="+dn.RefersTo)
+	}
+	assert.Equal(t, []string{
+		"DependentList1=Sheet1!$A$1", "DependentList2=Lists!$A$1:$A$3",
+		"DependentList2_Fruit=Lists!$B$1:$B$2", "DependentList2_Vegetable=Lists!$C$1:$C$2",
+	}, names)
+	rows, err := f.GetRows("Lists")
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{{"Dried Food", "Apple", "Carrot"}, {"Fruit", "Orange", "Potato"}, {"Vegetable"}}, rows)
+	dvs, err := f.GetDataValidations("Sheet1")
+	assert.NoError(t, err)
+	assert.Len(t, dvs, 2)
+	assert.Equal(t, "DependentList2", dvs[0].Formula1)
+	assert.Equal(t, `INDIRECT("DependentList2_"&SUBSTITUTE(A2," ","_"))`, dvs[1].Formula1)
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestAddDependentDropList.xlsx")))
+	// Test add dependent drop list with the ranges in different size
+	assert.Equal(t, ErrParameterInvalid, f.AddDependentDropList("Sheet1", "C2:C10", "D2:D5", map[string][]string{"a": {"b"}}))
+	// Test add dependent drop list without mapping
+	assert.Equal(t, ErrParameterInvalid, f.AddDependentDropList("Sheet1", "C2:C10", "D2:D10", nil))
+	// Test add dependent drop list with unsupported charset workbook
+	f.WorkBook = nil
+	f.Pkg.Store(defaultXMLPathWorkbook, MacintoshCyrillicCharset)
+	assert.EqualError(t, f.AddDependentDropList("Sheet1", "C2:C10", "D2:D10", map[string][]string{"a": {"b"}}),
+		"XML syntax error on line 1: invalid UTF-8")
+	assert.NoError(t, f.Close())
+}
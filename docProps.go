@@ -16,6 +16,7 @@ import (
 	"encoding/xml"
 	"io"
 	"reflect"
+	"strings"
 )
 
 // SetAppProps provides a function to set document application properties. The
@@ -23,6 +24,8 @@ import (
 //
 //	 Property          | Description
 //	-------------------+--------------------------------------------------------------------------
+//	 Template          | The name of the template used to create this document.
+//	                   |
 //	 Application       | The name of the application that created this document.
 //	                   |
 //	 ScaleCrop         | Indicates the display mode of the document thumbnail. Set this element
@@ -56,6 +59,7 @@ import (
 // For example:
 //
 //	err := f.SetAppProps(&excelize.AppProperties{
+//	    Template:          "Report.xltx",
 //	    Application:       "Microsoft Excel",
 //	    ScaleCrop:         true,
 //	    DocSecurity:       3,
@@ -78,7 +82,7 @@ func (f *File) SetAppProps(appProperties *AppProperties) error {
 		Decode(app); err != nil && err != io.EOF {
 		return err
 	}
-	fields = []string{"Application", "ScaleCrop", "DocSecurity", "Company", "LinksUpToDate", "HyperlinksChanged", "AppVersion"}
+	fields = []string{"Template", "Application", "ScaleCrop", "DocSecurity", "Company", "LinksUpToDate", "HyperlinksChanged", "AppVersion"}
 	immutable, mutable = reflect.ValueOf(*appProperties), reflect.ValueOf(app).Elem()
 	for _, field = range fields {
 		immutableField := immutable.FieldByName(field)
@@ -105,6 +109,7 @@ func (f *File) GetAppProps() (ret *AppProperties, err error) {
 		return
 	}
 	ret, err = &AppProperties{
+		Template:          app.Template,
 		Application:       app.Application,
 		ScaleCrop:         app.ScaleCrop,
 		DocSecurity:       app.DocSecurity,
@@ -116,6 +121,68 @@ func (f *File) GetAppProps() (ret *AppProperties, err error) {
 	return
 }
 
+// appPropsWriter provides a function to regenerate the heading pairs and the
+// titles of parts in the document application properties by the worksheets,
+// chart sheets and named ranges of the workbook, the other properties will
+// be kept.
+func (f *File) appPropsWriter() {
+	if _, ok := f.Pkg.Load(defaultXMLPathDocPropsApp); !ok {
+		return
+	}
+	app := new(xlsxProperties)
+	if err := f.xmlNewDecoder(bytes.NewReader(namespaceStrictToTransitional(f.readXML(defaultXMLPathDocPropsApp)))).
+		Decode(app); err != nil && err != io.EOF {
+		return
+	}
+	wb, err := f.workbookReader()
+	if err != nil {
+		return
+	}
+	var worksheets, charts, names []string
+	for _, sheet := range wb.Sheets.Sheet {
+		if name, ok := f.getSheetXMLPath(sheet.Name); ok && strings.HasPrefix(name, "xl/chartsheets") {
+			charts = append(charts, sheet.Name)
+			continue
+		}
+		worksheets = append(worksheets, sheet.Name)
+	}
+	if wb.DefinedNames != nil {
+		for _, dn := range wb.DefinedNames.DefinedName {
+			if dn.Hidden {
+				continue
+			}
+			name := strings.TrimPrefix(dn.Name, "_xlnm.")
+			if dn.LocalSheetID != nil && *dn.LocalSheetID >= 0 && *dn.LocalSheetID < len(wb.Sheets.Sheet) {
+				name = escapeSheetName(wb.Sheets.Sheet[*dn.LocalSheetID].Name) + "!" + name
+			}
+			names = append(names, name)
+		}
+	}
+	headingPairs, titlesOfParts := xlsxVector{BaseType: "variant"}, xlsxVector{BaseType: "lpstr"}
+	for _, part := range []struct {
+		heading string
+		titles  []string
+	}{
+		{"Worksheets", worksheets},
+		{"Charts", charts},
+		{"Named Ranges", names},
+	} {
+		if len(part.titles) == 0 {
+			continue
+		}
+		headingPairs.Variant = append(headingPairs.Variant, xlsxVariant{Lpstr: part.heading}, xlsxVariant{I4: len(part.titles)})
+		titlesOfParts.Lpstr = append(titlesOfParts.Lpstr, part.titles...)
+	}
+	headingPairs.Size, titlesOfParts.Size = len(headingPairs.Variant), len(titlesOfParts.Lpstr)
+	heading, _ := xml.Marshal(headingPairs)
+	titles, _ := xml.Marshal(titlesOfParts)
+	app.HeadingPairs = &xlsxVectorVariant{Content: string(heading)}
+	app.TitlesOfParts = &xlsxVectorLpstr{Content: string(titles)}
+	app.Vt = NameSpaceDocumentPropertiesVariantTypes.Value
+	output, _ := xml.Marshal(app)
+	f.saveFileList(defaultXMLPathDocPropsApp, output)
+}
+
 // SetDocProps provides a function to set document core properties. The
 // properties that can be set are:
 //
@@ -12,6 +12,7 @@
 package excelize_ch
 
 import (
+	"bytes"
 	"path/filepath"
 	"testing"
 
@@ -26,6 +27,7 @@ func TestSetAppProps(t *testing.T) {
 		t.FailNow()
 	}
 	assert.NoError(t, f.SetAppProps(&AppProperties{
+		Template:          "Report.xltx",
 		Application:       "Microsoft Excel",
 		ScaleCrop:         true,
 		DocSecurity:       3,
@@ -65,6 +67,60 @@ func TestGetAppProps(t *testing.T) {
 	assert.EqualError(t, err, "XML syntax error on line 1: invalid UTF-8")
 }
 
+func TestAppPropsWriter(t *testing.T) {
+	f := NewFile()
+	_, err := f.NewSheet("Sheet & 2")
+	assert.NoError(t, err)
+	assert.NoError(t, f.AddChartSheet("Chart1", &Chart{
+		Type:   Col,
+		Series: []ChartSeries{{Name: "Sheet1!$A$1", Categories: "Sheet1!$B$1:$D$1", Values: "Sheet1!$B$2:$D$2"}},
+	}))
+	assert.NoError(t, f.SetDefinedName(&DefinedName{Name: "Amount", RefersTo: "Sheet1!$A$1"}))
+	assert.NoError(t, f.SetDefinedName(&DefinedName{Name: "_xlnm.Print_Area", RefersTo: "'Sheet & 2'!$A$1:$B$2", Scope: "Sheet & 2"}))
+	wb, err := f.workbookReader()
+	assert.NoError(t, err)
+	wb.DefinedNames.DefinedName = append(wb.DefinedNames.DefinedName, xlsxDefinedName{Name: "Hidden", Data: "Sheet1!$A$1", Hidden: true})
+	assert.NoError(t, f.SetAppProps(&AppProperties{Template: "Report.xltx", Company: "Company Name", DocSecurity: 2}))
+	f.appPropsWriter()
+	app := new(xlsxProperties)
+	assert.NoError(t, f.xmlNewDecoder(bytes.NewReader(f.readXML(defaultXMLPathDocPropsApp))).Decode(app))
+	assert.Equal(t, `<vt:vector size="6" baseType="variant"><vt:variant><vt:lpstr>Worksheets</vt:lpstr></vt:variant><vt:variant><vt:i4>2</vt:i4></vt:variant><vt:variant><vt:lpstr>Charts</vt:lpstr></vt:variant><vt:variant><vt:i4>1</vt:i4></vt:variant><vt:variant><vt:lpstr>Named Ranges</vt:lpstr></vt:variant><vt:variant><vt:i4>2</vt:i4></vt:variant></vt:vector>`, app.HeadingPairs.Content)
+	assert.Equal(t, `<vt:vector size="5" baseType="lpstr"><vt:lpstr>Sheet1</vt:lpstr><vt:lpstr>Sheet &amp; 2</vt:lpstr><vt:lpstr>Chart1</vt:lpstr><vt:lpstr>Amount</vt:lpstr><vt:lpstr>&#39;Sheet &amp; 2&#39;!Print_Area</vt:lpstr></vt:vector>`, app.TitlesOfParts.Content)
+	props, err := f.GetAppProps()
+	assert.NoError(t, err)
+	assert.Equal(t, &AppProperties{Template: "Report.xltx", Company: "Company Name", DocSecurity: 2}, props)
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestAppPropsWriter.xlsx")))
+	assert.NoError(t, f.Close())
+
+	// Test regenerate application properties after delete the worksheet
+	f, err = OpenFile(filepath.Join("test", "TestAppPropsWriter.xlsx"))
+	assert.NoError(t, err)
+	assert.NoError(t, f.DeleteSheet("Sheet & 2"))
+	f.appPropsWriter()
+	app = new(xlsxProperties)
+	assert.NoError(t, f.xmlNewDecoder(bytes.NewReader(f.readXML(defaultXMLPathDocPropsApp))).Decode(app))
+	assert.Equal(t, `<vt:vector size="3" baseType="lpstr"><vt:lpstr>Sheet1</vt:lpstr><vt:lpstr>Chart1</vt:lpstr><vt:lpstr>Amount</vt:lpstr></vt:vector>`, app.TitlesOfParts.Content)
+	assert.NoError(t, f.Close())
+
+	// Test regenerate application properties without the part
+	f = NewFile()
+	f.Pkg.Delete(defaultXMLPathDocPropsApp)
+	f.appPropsWriter()
+	_, ok := f.Pkg.Load(defaultXMLPathDocPropsApp)
+	assert.False(t, ok)
+	// Test regenerate application properties with unsupported charset
+	f.Pkg.Store(defaultXMLPathDocPropsApp, MacintoshCyrillicCharset)
+	f.appPropsWriter()
+	content, _ := f.Pkg.Load(defaultXMLPathDocPropsApp)
+	assert.Equal(t, MacintoshCyrillicCharset, content)
+	f.Pkg.Store(defaultXMLPathDocPropsApp, []byte(templateDocpropsApp))
+	f.WorkBook = nil
+	f.Pkg.Store(defaultXMLPathWorkbook, MacintoshCyrillicCharset)
+	f.appPropsWriter()
+	content, _ = f.Pkg.Load(defaultXMLPathDocPropsApp)
+	assert.Equal(t, []byte(templateDocpropsApp), content)
+}
+
 func TestSetDocProps(t *testing.T) {
 	f, err := OpenFile(filepath.Join("test", "Book1.xlsx"))
 	if !assert.NoError(t, err) {
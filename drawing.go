@@ -15,11 +15,22 @@ import (
 	"bytes"
 	"encoding/xml"
 	"io"
+	"math"
 	"reflect"
+	"regexp"
 	"strconv"
 	"strings"
 )
 
+var (
+	// drawingCNvPrExp is the regular expression for matching the start
+	// element of the non-visual drawing properties in the cell anchor.
+	drawingCNvPrExp = regexp.MustCompile(`<(\w+:)?cNvPr\b[^>]*>`)
+	// drawingClientDataExp is the regular expression for matching the start
+	// element of the client data in the cell anchor.
+	drawingClientDataExp = regexp.MustCompile(`<(\w+:)?clientData\b[^>]*>`)
+)
+
 // prepareDrawing provides a function to prepare drawing ID and XML by given
 // drawingID, worksheet name and default drawingXML.
 func (f *File) prepareDrawing(ws *xlsxWorksheet, drawingID int, sheet, drawingXML string) (int, string) {
@@ -55,16 +66,15 @@ func (f *File) prepareChartSheetDrawing(cs *xlsxChartsheet, drawingID int, sheet
 }
 
 // addChart provides a function to create chart as xl/charts/chart%d.xml by
-// given format sets.
-func (f *File) addChart(opts *Chart, comboCharts []*Chart) {
-	count := f.countCharts()
+// given chart ID and format sets.
+func (f *File) addChart(chartID int, opts *Chart, comboCharts []*Chart) {
 	xlsxChartSpace := xlsxChartSpace{
 		XMLNSa:         NameSpaceDrawingML.Value,
 		Date1904:       &attrValBool{Val: boolPtr(false)},
 		Lang:           &attrValString{Val: stringPtr("en-US")},
 		RoundedCorners: &attrValBool{Val: boolPtr(false)},
 		Chart: cChart{
-			Title: f.drawPlotAreaTitles(opts.Title, ""),
+			Title: f.drawPlotAreaTitles(opts.Title, opts.TitleRef, ""),
 			View3D: &cView3D{
 				RotX:        &attrValInt{Val: intPtr(chartView3DRotX[opts.Type])},
 				RotY:        &attrValInt{Val: intPtr(chartView3DRotY[opts.Type])},
@@ -107,6 +117,9 @@ func (f *File) addChart(opts *Chart, comboCharts []*Chart) {
 			},
 		},
 	}
+	if opts.pivotSource != "" {
+		xlsxChartSpace.PivotSource = &cPivotSource{Name: opts.pivotSource, FmtID: &attrValInt{Val: intPtr(0)}}
+	}
 	plotAreaFunc := map[ChartType]func(*Chart) *cPlotArea{
 		Area:                        f.drawBaseChart,
 		AreaStacked:                 f.drawBaseChart,
@@ -170,11 +183,23 @@ func (f *File) addChart(opts *Chart, comboCharts []*Chart) {
 	addChart := func(c, p *cPlotArea) {
 		immutable, mutable := reflect.ValueOf(c).Elem(), reflect.ValueOf(p).Elem()
 		for i := 0; i < mutable.NumField(); i++ {
-			field := mutable.Field(i)
+			field, fieldType := mutable.Field(i), mutable.Type().Field(i)
 			if field.IsNil() {
 				continue
 			}
-			immutable.FieldByName(mutable.Type().Field(i).Name).Set(field)
+			target := immutable.FieldByName(fieldType.Name)
+			if target.IsNil() {
+				target.Set(field)
+				continue
+			}
+			switch value := field.Interface().(type) {
+			case *cCharts:
+				c.ComboCharts = append(c.ComboCharts, &cComboChart{
+					XMLName: xml.Name{Local: fieldType.Tag.Get("xml")}, cCharts: *value,
+				})
+			case []*cAxs:
+				target.Set(reflect.ValueOf(mergeChartAxes(target.Interface().([]*cAxs), value)))
+			}
 		}
 	}
 	addChart(xlsxChartSpace.Chart.PlotArea, plotAreaFunc[opts.Type](opts))
@@ -185,10 +210,27 @@ func (f *File) addChart(opts *Chart, comboCharts []*Chart) {
 		order += len(comboCharts[idx].Series)
 	}
 	chart, _ := xml.Marshal(xlsxChartSpace)
-	media := "xl/charts/chart" + strconv.Itoa(count+1) + ".xml"
+	media := "xl/charts/chart" + strconv.Itoa(chartID) + ".xml"
 	f.saveFileList(media, chart)
 }
 
+// mergeChartAxes provides a function to append the axes which have not been
+// defined in the plot area by axis ID, the existing axes will be kept.
+func mergeChartAxes(axs, newAxs []*cAxs) []*cAxs {
+	for _, newAx := range newAxs {
+		var exist bool
+		for _, ax := range axs {
+			if exist = *ax.AxID.Val == *newAx.AxID.Val; exist {
+				break
+			}
+		}
+		if !exist {
+			axs = append(axs, newAx)
+		}
+	}
+	return axs
+}
+
 // drawBaseChart provides a function to draw the c:plotArea element for bar,
 // and column series charts by given format sets.
 func (f *File) drawBaseChart(opts *Chart) *cPlotArea {
@@ -710,7 +752,7 @@ func (f *File) drawChartSeries(opts *Chart) *[]cSer {
 			SpPr:             f.drawChartSeriesSpPr(k, opts),
 			Marker:           f.drawChartSeriesMarker(k, opts),
 			DPt:              f.drawChartSeriesDPt(k, opts),
-			DLbls:            f.drawChartSeriesDLbls(opts),
+			DLbls:            f.drawChartSeriesDLbls(k, opts),
 			InvertIfNegative: &attrValBool{Val: boolPtr(false)},
 			Cat:              f.drawChartSeriesCat(opts.Series[k], opts),
 			Smooth:           &attrValBool{Val: boolPtr(opts.Series[k].Line.Smooth)},
@@ -719,6 +761,7 @@ func (f *File) drawChartSeries(opts *Chart) *[]cSer {
 			YVal:             f.drawChartSeriesYVal(opts.Series[k], opts),
 			BubbleSize:       f.drawCharSeriesBubbleSize(opts.Series[k], opts),
 			Bubble3D:         f.drawCharSeriesBubble3D(opts),
+			ExtLst:           f.drawChartSeriesExtLst(opts.Series[k]),
 		})
 	}
 	return &ser
@@ -796,31 +839,53 @@ func (f *File) drawChartSeriesDPt(i int, opts *Chart) []*cDPt {
 // drawChartSeriesCat provides a function to draw the c:cat element by given
 // chart series and format sets.
 func (f *File) drawChartSeriesCat(v ChartSeries, opts *Chart) *cCat {
-	cat := &cCat{
-		StrRef: &cStrRef{
-			F: v.Categories,
-		},
-	}
 	chartSeriesCat := map[ChartType]*cCat{Scatter: nil, Bubble: nil, Bubble3D: nil}
-	if _, ok := chartSeriesCat[opts.Type]; ok || v.Categories == "" {
+	if _, ok := chartSeriesCat[opts.Type]; ok || (v.Categories == "" && len(v.CategoriesData) == 0) {
 		return nil
 	}
-	return cat
+	return drawChartSeriesCatData(v)
+}
+
+// drawChartSeriesCatData provides a function to draw the c:cat or c:xVal
+// element by given chart series, the literal categories will be used if the
+// categories range of the series is empty.
+func drawChartSeriesCatData(v ChartSeries) *cCat {
+	if v.Categories != "" || len(v.CategoriesData) == 0 {
+		return &cCat{StrRef: &cStrRef{F: v.Categories}}
+	}
+	strLit := &cStrLit{PtCount: &attrValInt{Val: intPtr(len(v.CategoriesData))}}
+	for idx, val := range v.CategoriesData {
+		strLit.Pt = append(strLit.Pt, &cPt{IDx: idx, V: stringPtr(val)})
+	}
+	return &cCat{StrLit: strLit}
+}
+
+// drawChartSeriesNumData provides a function to draw the c:val, c:yVal or
+// c:bubbleSize element by given reference and literal numbers, the literal
+// numbers will be used if the reference is empty. The NaN and infinity
+// numbers will be treated as blank.
+func drawChartSeriesNumData(ref string, data []float64) *cVal {
+	if ref != "" || len(data) == 0 {
+		return &cVal{NumRef: &cNumRef{F: ref}}
+	}
+	numLit := &cNumLit{FormatCode: "General", PtCount: &attrValInt{Val: intPtr(len(data))}}
+	for idx, val := range data {
+		if math.IsNaN(val) || math.IsInf(val, 0) {
+			continue
+		}
+		numLit.Pt = append(numLit.Pt, &cPt{IDx: idx, V: stringPtr(strconv.FormatFloat(val, 'f', -1, 64))})
+	}
+	return &cVal{NumLit: numLit}
 }
 
 // drawChartSeriesVal provides a function to draw the c:val element by given
 // chart series and format sets.
 func (f *File) drawChartSeriesVal(v ChartSeries, opts *Chart) *cVal {
-	val := &cVal{
-		NumRef: &cNumRef{
-			F: v.Values,
-		},
-	}
 	chartSeriesVal := map[ChartType]*cVal{Scatter: nil, Bubble: nil, Bubble3D: nil}
 	if _, ok := chartSeriesVal[opts.Type]; ok {
 		return nil
 	}
-	return val
+	return drawChartSeriesNumData(v.Values, v.ValuesData)
 }
 
 // drawChartSeriesMarker provides a function to draw the c:marker element by
@@ -861,11 +926,7 @@ func (f *File) drawChartSeriesMarker(i int, opts *Chart) *cMarker {
 // drawChartSeriesXVal provides a function to draw the c:xVal element by given
 // chart series and format sets.
 func (f *File) drawChartSeriesXVal(v ChartSeries, opts *Chart) *cCat {
-	cat := &cCat{
-		StrRef: &cStrRef{
-			F: v.Categories,
-		},
-	}
+	cat := drawChartSeriesCatData(v)
 	chartSeriesXVal := map[ChartType]*cCat{Scatter: cat, Bubble: cat, Bubble3D: cat}
 	return chartSeriesXVal[opts.Type]
 }
@@ -873,11 +934,7 @@ func (f *File) drawChartSeriesXVal(v ChartSeries, opts *Chart) *cCat {
 // drawChartSeriesYVal provides a function to draw the c:yVal element by given
 // chart series and format sets.
 func (f *File) drawChartSeriesYVal(v ChartSeries, opts *Chart) *cVal {
-	val := &cVal{
-		NumRef: &cNumRef{
-			F: v.Values,
-		},
-	}
+	val := drawChartSeriesNumData(v.Values, v.ValuesData)
 	chartSeriesYVal := map[ChartType]*cVal{Scatter: val, Bubble: val, Bubble3D: val}
 	return chartSeriesYVal[opts.Type]
 }
@@ -885,14 +942,10 @@ func (f *File) drawChartSeriesYVal(v ChartSeries, opts *Chart) *cVal {
 // drawCharSeriesBubbleSize provides a function to draw the c:bubbleSize
 // element by given chart series and format sets.
 func (f *File) drawCharSeriesBubbleSize(v ChartSeries, opts *Chart) *cVal {
-	if _, ok := map[ChartType]bool{Bubble: true, Bubble3D: true}[opts.Type]; !ok || v.Sizes == "" {
+	if _, ok := map[ChartType]bool{Bubble: true, Bubble3D: true}[opts.Type]; !ok || (v.Sizes == "" && len(v.SizesData) == 0) {
 		return nil
 	}
-	return &cVal{
-		NumRef: &cNumRef{
-			F: v.Sizes,
-		},
-	}
+	return drawChartSeriesNumData(v.Sizes, v.SizesData)
 }
 
 // drawCharSeriesBubble3D provides a function to draw the c:bubble3D element
@@ -933,18 +986,107 @@ func (f *File) drawChartDLbls(opts *Chart) *cDLbls {
 }
 
 // drawChartSeriesDLbls provides a function to draw the c:dLbls element by
-// given format sets.
-func (f *File) drawChartSeriesDLbls(opts *Chart) *cDLbls {
-	dLbls := f.drawChartDLbls(opts)
-	chartSeriesDLbls := map[ChartType]*cDLbls{
-		Scatter: nil, Surface3D: nil, WireframeSurface3D: nil, Contour: nil, WireframeContour: nil, Bubble: nil, Bubble3D: nil,
-	}
-	if _, ok := chartSeriesDLbls[opts.Type]; ok {
+// given data index and format sets. The data labels of the plot area will be
+// used if the data labels of the series are not specified.
+func (f *File) drawChartSeriesDLbls(i int, opts *Chart) *cDLbls {
+	dataLabel := opts.Series[i].DataLabel
+	if _, ok := map[ChartType]bool{
+		Surface3D: true, WireframeSurface3D: true, Contour: true, WireframeContour: true,
+	}[opts.Type]; ok {
 		return nil
 	}
+	if dataLabel == (ChartDataLabel{}) {
+		if _, ok := map[ChartType]bool{Scatter: true, Bubble: true, Bubble3D: true}[opts.Type]; ok {
+			return nil
+		}
+		return f.drawChartDLbls(opts)
+	}
+	dLbls := &cDLbls{
+		NumFmt:          f.drawChartNumFmt(dataLabel.NumFmt),
+		ShowLegendKey:   &attrValBool{Val: boolPtr(false)},
+		ShowVal:         &attrValBool{Val: boolPtr(dataLabel.ShowVal)},
+		ShowCatName:     &attrValBool{Val: boolPtr(dataLabel.ShowCatName)},
+		ShowSerName:     &attrValBool{Val: boolPtr(dataLabel.ShowSerName)},
+		ShowPercent:     &attrValBool{Val: boolPtr(dataLabel.ShowPercent)},
+		ShowBubbleSize:  &attrValBool{Val: boolPtr(dataLabel.ShowBubbleSize)},
+		ShowLeaderLines: &attrValBool{Val: boolPtr(dataLabel.ShowLeaderLines)},
+	}
+	for _, position := range supportedChartDataLabelsPosition[opts.Type] {
+		if position == dataLabel.Position {
+			dLbls.DLblPos = &attrValString{Val: stringPtr(chartDataLabelsPositionTypes[position])}
+		}
+	}
+	ext := cDLblsExt{URI: ExtURIChartDataLabels, XMLNSC15: NameSpaceDrawingMLChartX15.Value}
+	if dataLabel.CalloutShape != "" {
+		ext.SpPr = &cDLblsSpPr{PrstGeom: xlsxPrstGeom{Prst: dataLabel.CalloutShape}}
+	}
+	if dataLabel.Range != "" {
+		ext.ShowDataLabelsRange = &attrValBool{Val: boolPtr(true)}
+	}
+	if dataLabel.ShowLeaderLines {
+		ext.ShowLeaderLines = &attrValBool{Val: boolPtr(true)}
+	}
+	if ext.SpPr != nil || ext.ShowDataLabelsRange != nil || ext.ShowLeaderLines != nil {
+		extBytes, _ := xml.Marshal(ext)
+		dLbls.ExtLst = &xlsxExtLst{Ext: string(extBytes)}
+	}
 	return dLbls
 }
 
+// drawChartSeriesExtLst provides a function to draw the c:extLst element of
+// the series by given series options, which specifies the worksheet range of
+// the data labels text.
+func (f *File) drawChartSeriesExtLst(series ChartSeries) *xlsxExtLst {
+	if series.DataLabel.Range == "" {
+		return nil
+	}
+	extBytes, _ := xml.Marshal(cSerExt{
+		URI: ExtURIChartSeriesDataLabelsRange, XMLNSC15: NameSpaceDrawingMLChartX15.Value,
+		DataLabelsRange: &cDataLabelsRange{
+			F:              series.DataLabel.Range,
+			DlblRangeCache: f.getChartDataLabelsRangeCache(series.DataLabel.Range),
+		},
+	})
+	return &xlsxExtLst{Ext: string(extBytes)}
+}
+
+// getChartDataLabelsRangeCache provides a function to get the cached text of
+// the data labels or titles by given worksheet range reference, such as
+// Sheet1!$A$1:$A$5. This function returns nil if the reference is invalid.
+func (f *File) getChartDataLabelsRangeCache(ref string) *cStrLit {
+	idx := strings.LastIndex(ref, "!")
+	if idx == -1 {
+		return nil
+	}
+	sheet, rangeRef := ref[:idx], strings.ReplaceAll(ref[idx+1:], "$", "")
+	if strings.HasPrefix(sheet, "'") && strings.HasSuffix(sheet, "'") {
+		sheet = strings.ReplaceAll(sheet[1:len(sheet)-1], "''", "'")
+	}
+	if !strings.Contains(rangeRef, ":") {
+		rangeRef += ":" + rangeRef
+	}
+	coordinates, err := rangeRefToCoordinates(rangeRef)
+	if err != nil {
+		return nil
+	}
+	_ = sortCoordinates(coordinates)
+	var cache cStrLit
+	for row, idx := coordinates[1], 0; row <= coordinates[3]; row++ {
+		for col := coordinates[0]; col <= coordinates[2]; col, idx = col+1, idx+1 {
+			cell, _ := CoordinatesToCellName(col, row)
+			val, err := f.GetCellValue(sheet, cell)
+			if err != nil {
+				return nil
+			}
+			if val != "" {
+				cache.Pt = append(cache.Pt, &cPt{IDx: idx, V: stringPtr(val)})
+			}
+		}
+	}
+	cache.PtCount = &attrValInt{Val: intPtr((coordinates[3] - coordinates[1] + 1) * (coordinates[2] - coordinates[0] + 1))}
+	return &cache
+}
+
 // drawPlotAreaCatAx provides a function to draw the c:catAx element.
 func (f *File) drawPlotAreaCatAx(opts *Chart) []*cAxs {
 	max := &attrValFloat{Val: opts.XAxis.Maximum}
@@ -968,7 +1110,7 @@ func (f *File) drawPlotAreaCatAx(opts *Chart) []*cAxs {
 			NumFmt:        &cNumFmt{FormatCode: "General"},
 			MajorTickMark: &attrValString{Val: stringPtr("none")},
 			MinorTickMark: &attrValString{Val: stringPtr("none")},
-			Title:         f.drawPlotAreaTitles(opts.XAxis.Title, ""),
+			Title:         f.drawPlotAreaTitles(opts.XAxis.Title, opts.XAxis.TitleRef, ""),
 			TickLblPos:    &attrValString{Val: stringPtr("nextTo")},
 			SpPr:          f.drawPlotAreaSpPr(),
 			TxPr:          f.drawPlotAreaTxPr(&opts.YAxis),
@@ -1042,7 +1184,7 @@ func (f *File) drawPlotAreaValAx(opts *Chart) []*cAxs {
 			},
 			Delete: &attrValBool{Val: boolPtr(opts.YAxis.None)},
 			AxPos:  &attrValString{Val: stringPtr(valAxPos[opts.YAxis.ReverseOrder])},
-			Title:  f.drawPlotAreaTitles(opts.YAxis.Title, "horz"),
+			Title:  f.drawPlotAreaTitles(opts.YAxis.Title, opts.YAxis.TitleRef, "horz"),
 			NumFmt: &cNumFmt{
 				FormatCode: chartValAxNumFmtFormatCode[opts.Type],
 			},
@@ -1122,26 +1264,34 @@ func (f *File) drawPlotAreaSerAx(opts *Chart) []*cAxs {
 	}
 }
 
-// drawPlotAreaTitles provides a function to draw the c:title element.
-func (f *File) drawPlotAreaTitles(runs []RichTextRun, vert string) *cTitle {
+// drawPlotAreaTitles provides a function to draw the c:title element by
+// given rich text runs, cell reference and text direction of the title. The
+// title will be linked to the cell if the cell reference is specified.
+func (f *File) drawPlotAreaTitles(runs []RichTextRun, ref, vert string) *cTitle {
+	if ref != "" {
+		title := &cTitle{Tx: cTx{StrRef: &cStrRef{F: ref}}, Overlay: &attrValBool{Val: boolPtr(false)}}
+		if cache := f.getChartDataLabelsRangeCache(ref); cache != nil {
+			title.Tx.StrRef.StrCache = &cStrCache{PtCount: cache.PtCount, Pt: cache.Pt}
+		}
+		var rPr aRPr
+		if len(runs) > 0 {
+			rPr = drawChartTitleRunPr(runs[0].Font)
+		}
+		title.TxPr.P = aP{PPr: &aPPr{DefRPr: rPr}, EndParaRPr: &aEndParaRPr{Lang: "en-US"}}
+		if vert == "horz" {
+			title.TxPr.BodyPr = aBodyPr{Rot: -5400000, Vert: vert}
+		}
+		return title
+	}
 	if len(runs) == 0 {
 		return nil
 	}
 	title := &cTitle{Tx: cTx{Rich: &cRich{}}, Overlay: &attrValBool{Val: boolPtr(false)}}
 	for _, run := range runs {
-		r := &aR{T: run.Text}
-		if run.Font != nil {
-			r.RPr.B, r.RPr.I = run.Font.Bold, run.Font.Italic
-			if run.Font.Color != "" {
-				r.RPr.SolidFill = &aSolidFill{SrgbClr: &attrValString{Val: stringPtr(run.Font.Color)}}
-			}
-			if run.Font.Size > 0 {
-				r.RPr.Sz = run.Font.Size * 100
-			}
-		}
+		r := &aR{T: run.Text, RPr: drawChartTitleRunPr(run.Font)}
 		title.Tx.Rich.P = append(title.Tx.Rich.P, aP{
 			PPr:        &aPPr{DefRPr: aRPr{}},
-			R:          r,
+			R:          []*aR{r},
 			EndParaRPr: &aEndParaRPr{Lang: "en-US", AltLang: "en-US"},
 		})
 	}
@@ -1151,6 +1301,22 @@ func (f *File) drawPlotAreaTitles(runs []RichTextRun, vert string) *cTitle {
 	return title
 }
 
+// drawChartTitleRunPr provides a function to draw the run properties of the
+// chart or axis title by given font settings.
+func drawChartTitleRunPr(font *Font) aRPr {
+	var rPr aRPr
+	if font != nil {
+		rPr.B, rPr.I = font.Bold, font.Italic
+		if font.Color != "" {
+			rPr.SolidFill = &aSolidFill{SrgbClr: &attrValString{Val: stringPtr(font.Color)}}
+		}
+		if font.Size > 0 {
+			rPr.Sz = font.Size * 100
+		}
+	}
+	return rPr
+}
+
 // drawPlotAreaSpPr provides a function to draw the c:spPr element.
 func (f *File) drawPlotAreaSpPr() *cSpPr {
 	return &cSpPr{
@@ -1269,11 +1435,13 @@ func (f *File) drawingParser(path string) (*xlsxWsDr, int, error) {
 		}
 		if _, ok = f.Pkg.Load(path); ok { // Append Model
 			decodeWsDr := decodeWsDr{}
-			if err = f.xmlNewDecoder(bytes.NewReader(namespaceStrictToTransitional(f.readXML(path)))).
+			drawing := namespaceStrictToTransitional(f.readXML(path))
+			if err = f.xmlNewDecoder(bytes.NewReader(drawing)).
 				Decode(&decodeWsDr); err != nil && err != io.EOF {
 				return nil, 0, err
 			}
 			content.R = decodeWsDr.R
+			content.Attrs = getDrawingRootAttrs(getRootElement(f.xmlNewDecoder(bytes.NewReader(drawing))))
 			for _, v := range decodeWsDr.AlternateContent {
 				content.AlternateContent = append(content.AlternateContent, &xlsxAlternateContent{
 					Content: v.Content,
@@ -1301,22 +1469,55 @@ func (f *File) drawingParser(path string) (*xlsxWsDr, int, error) {
 	}
 	wsDr.mu.Lock()
 	defer wsDr.mu.Unlock()
-	return wsDr, len(wsDr.OneCellAnchor) + len(wsDr.TwoCellAnchor) + 2, nil
+	cNvPrID := len(wsDr.OneCellAnchor) + len(wsDr.TwoCellAnchor) + 2
+	for _, anchors := range [][]*xdrCellAnchor{wsDr.AbsoluteAnchor, wsDr.OneCellAnchor, wsDr.TwoCellAnchor} {
+		for _, anchor := range anchors {
+			if id, _ := f.getCellAnchorCNvPr(anchor); id >= cNvPrID {
+				cNvPrID = id + 1
+			}
+		}
+	}
+	return wsDr, cNvPrID, nil
+}
+
+// getDrawingRootAttrs provides a function to get the namespace declarations
+// and attributes of the drawing root element except the namespaces which
+// serialized by default, such as the equations (OMML) used namespaces, by
+// given root element attributes.
+func getDrawingRootAttrs(attrs []xml.Attr) []xml.Attr {
+	var rootAttrs []xml.Attr
+	for _, attr := range attrs {
+		if attr.Name.Space == "" {
+			if attr.Name.Local != "xmlns" {
+				rootAttrs = append(rootAttrs, attr)
+			}
+			continue
+		}
+		if attr.Name.Space == "xmlns" {
+			if inStrSlice([]string{"a", "r", "xdr"}, attr.Name.Local, true) == -1 {
+				rootAttrs = append(rootAttrs, xml.Attr{Name: xml.Name{Local: "xmlns:" + attr.Name.Local}, Value: attr.Value})
+			}
+			continue
+		}
+		rootAttrs = append(rootAttrs, xml.Attr{Name: xml.Name{Local: getXMLNamespace(attr.Name.Space, attrs) + ":" + attr.Name.Local}, Value: attr.Value})
+	}
+	return rootAttrs
 }
 
 // addDrawingChart provides a function to add chart graphic frame by given
-// sheet, drawingXML, cell, width, height, relationship index and format sets.
-func (f *File) addDrawingChart(sheet, drawingXML, cell string, width, height, rID int, opts *GraphicOptions) error {
+// sheet, drawingXML, cell, width, height, relationship index and format sets,
+// and returns the ID of the chart graphic frame.
+func (f *File) addDrawingChart(sheet, drawingXML, cell string, width, height, rID int, opts *GraphicOptions) (int, error) {
 	col, row, err := CellNameToCoordinates(cell)
 	if err != nil {
-		return err
+		return 0, err
 	}
 	width = int(float64(width) * opts.ScaleX)
 	height = int(float64(height) * opts.ScaleY)
 	colStart, rowStart, colEnd, rowEnd, x2, y2 := f.positionObjectPixels(sheet, col, row, opts.OffsetX, opts.OffsetY, width, height)
 	content, cNvPrID, err := f.drawingParser(drawingXML)
 	if err != nil {
-		return err
+		return cNvPrID, err
 	}
 	twoCellAnchor := xdrCellAnchor{}
 	twoCellAnchor.EditAs = opts.Positioning
@@ -1359,7 +1560,7 @@ func (f *File) addDrawingChart(sheet, drawingXML, cell string, width, height, rI
 	}
 	content.TwoCellAnchor = append(content.TwoCellAnchor, &twoCellAnchor)
 	f.Drawings.Store(drawingXML, content)
-	return err
+	return cNvPrID, err
 }
 
 // addSheetDrawingChart provides a function to add chart graphic frame for
@@ -1462,6 +1663,326 @@ func (f *File) deleteDrawing(col, row int, drawingXML, drawingType string) (stri
 	return rID, err
 }
 
+// getCellAnchorPos provides a function to get the position and the content of
+// the cell anchor which decoded from the drawing part.
+func (f *File) getCellAnchorPos(a *xdrCellAnchor) xlsxCellAnchorPos {
+	deCellAnchor := decodeCellAnchor{}
+	deCellAnchorPos := decodeCellAnchorPos{}
+	_ = f.xmlNewDecoder(strings.NewReader("<decodeCellAnchor>" + a.GraphicFrame + "</decodeCellAnchor>")).Decode(&deCellAnchor)
+	_ = f.xmlNewDecoder(strings.NewReader("<decodeCellAnchorPos>" + a.GraphicFrame + "</decodeCellAnchorPos>")).Decode(&deCellAnchorPos)
+	xlsxCellAnchorPos := xlsxCellAnchorPos(deCellAnchorPos)
+	for i := 0; i < len(xlsxCellAnchorPos.AlternateContent); i++ {
+		xlsxCellAnchorPos.AlternateContent[i].XMLNSMC = SourceRelationshipCompatibility.Value
+	}
+	if deCellAnchor.From != nil {
+		xlsxCellAnchorPos.From = &xlsxFrom{
+			Col: deCellAnchor.From.Col, ColOff: deCellAnchor.From.ColOff,
+			Row: deCellAnchor.From.Row, RowOff: deCellAnchor.From.RowOff,
+		}
+	}
+	if deCellAnchor.To != nil {
+		xlsxCellAnchorPos.To = &xlsxTo{
+			Col: deCellAnchor.To.Col, ColOff: deCellAnchor.To.ColOff,
+			Row: deCellAnchor.To.Row, RowOff: deCellAnchor.To.RowOff,
+		}
+	}
+	return xlsxCellAnchorPos
+}
+
+// setCellAnchorPos provides a function to set the content of the cell anchor
+// which decoded from the drawing part by given cell anchor position.
+func (a *xdrCellAnchor) setCellAnchorPos(pos xlsxCellAnchorPos) {
+	cellAnchor, _ := xml.Marshal(pos)
+	a.GraphicFrame = strings.TrimSuffix(strings.TrimPrefix(string(cellAnchor), "<xlsxCellAnchorPos>"), "</xlsxCellAnchorPos>")
+}
+
+// getCellAnchorCNvPr provides a function to get the ID and name of the
+// non-visual drawing properties of the object in the cell anchor.
+func (f *File) getCellAnchorCNvPr(a *xdrCellAnchor) (int, string) {
+	if a.Pic != nil {
+		return a.Pic.NvPicPr.CNvPr.ID, a.Pic.NvPicPr.CNvPr.Name
+	}
+	if a.Sp != nil && a.Sp.NvSpPr != nil && a.Sp.NvSpPr.CNvPr != nil {
+		return a.Sp.NvSpPr.CNvPr.ID, a.Sp.NvSpPr.CNvPr.Name
+	}
+	content := a.GraphicFrame
+	for _, alternateContent := range a.AlternateContent {
+		content += "<mc:AlternateContent>" + alternateContent.Content + "</mc:AlternateContent>"
+	}
+	if content == "" {
+		return 0, ""
+	}
+	var obj decodeDrawingObject
+	_ = f.xmlNewDecoder(strings.NewReader("<decodeDrawingObject>" + content + "</decodeDrawingObject>")).Decode(&obj)
+	return getDrawingObjectCNvPr(&obj)
+}
+
+// getDrawingObjectCNvPr provides a function to get the ID and name of the
+// first drawing object, or the drawing object in the choice of the alternate
+// content by given decoded drawing objects.
+func getDrawingObjectCNvPr(obj *decodeDrawingObject) (int, string) {
+	for _, element := range obj.Elements {
+		if element.Choice != nil {
+			if id, name := getDrawingObjectCNvPr(element.Choice); id != 0 {
+				return id, name
+			}
+		}
+		for _, nvPr := range element.NvPr {
+			if nvPr.CNvPr != nil {
+				return nvPr.CNvPr.ID, nvPr.CNvPr.Name
+			}
+		}
+	}
+	return 0, ""
+}
+
+// getCellAnchorEmbedRID provides a function to get the embed relationship ID
+// of the picture in the cell anchor.
+func (f *File) getCellAnchorEmbedRID(a *xdrCellAnchor) string {
+	if a.Pic != nil || a.GraphicFrame == "" {
+		rID, _ := extractEmbedRID(a.Pic, nil, nil)
+		return rID
+	}
+	deCellAnchor := decodeCellAnchor{}
+	_ = f.xmlNewDecoder(strings.NewReader("<decodeCellAnchor>" + a.GraphicFrame + "</decodeCellAnchor>")).Decode(&deCellAnchor)
+	rID, _ := extractEmbedRID(nil, deCellAnchor.Pic, nil)
+	return rID
+}
+
+// getDrawingObject provides a function to get the drawing part path, the
+// drawing and the cell anchors list which containing the object by given
+// drawing object handle, and returns the index of the object in the list.
+func (f *File) getDrawingObject(obj DrawingObject) (string, *xlsxWsDr, *[]*xdrCellAnchor, int, error) {
+	ws, err := f.workSheetReader(obj.Sheet)
+	if err != nil {
+		return "", nil, nil, -1, err
+	}
+	if err = f.checkObjectsProtection(ws); err != nil {
+		return "", nil, nil, -1, err
+	}
+	if ws.Drawing == nil || obj.ID <= 0 {
+		return "", nil, nil, -1, newNoExistDrawingObjectError(obj.Sheet, obj.ID)
+	}
+	target := f.getSheetRelationshipsTargetByID(obj.Sheet, ws.Drawing.RID)
+	drawingXML := strings.TrimPrefix(strings.ReplaceAll(target, "..", "xl"), "/")
+	wsDr, _, err := f.drawingParser(drawingXML)
+	if err != nil {
+		return drawingXML, wsDr, nil, -1, err
+	}
+	for _, anchors := range []*[]*xdrCellAnchor{&wsDr.AbsoluteAnchor, &wsDr.OneCellAnchor, &wsDr.TwoCellAnchor} {
+		for idx, anchor := range *anchors {
+			if id, _ := f.getCellAnchorCNvPr(anchor); id == obj.ID {
+				return drawingXML, wsDr, anchors, idx, err
+			}
+		}
+	}
+	return drawingXML, wsDr, nil, -1, newNoExistDrawingObjectError(obj.Sheet, obj.ID)
+}
+
+// DeleteDrawingObject provides a function to delete the chart, picture or
+// shape in the worksheet by given drawing object handle which returned by the
+// AddChartObject, AddPictureObject, AddPictureFromBytesObject or
+// AddShapeObject function. Unlike the DeleteChart and DeletePicture
+// functions, only the given object will be deleted when multiple objects
+// share the same anchor cell. For example:
+//
+//	obj, err := f.AddPictureObject("Sheet1", "A2", "image.jpg", nil)
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	err = f.DeleteDrawingObject(obj)
+func (f *File) DeleteDrawingObject(obj DrawingObject) error {
+	drawingXML, wsDr, anchors, idx, err := f.getDrawingObject(obj)
+	if err != nil {
+		return err
+	}
+	wsDr.mu.Lock()
+	rID := f.getCellAnchorEmbedRID((*anchors)[idx])
+	*anchors = append((*anchors)[:idx], (*anchors)[idx+1:]...)
+	for _, list := range [][]*xdrCellAnchor{wsDr.AbsoluteAnchor, wsDr.OneCellAnchor, wsDr.TwoCellAnchor} {
+		for _, anchor := range list {
+			if rID != "" && f.getCellAnchorEmbedRID(anchor) == rID {
+				rID = ""
+			}
+		}
+	}
+	wsDr.mu.Unlock()
+	f.Drawings.Store(drawingXML, wsDr)
+	if rID != "" {
+		f.deletePictureRels(strings.ReplaceAll(strings.ReplaceAll(drawingXML, "xl/drawings", "xl/drawings/_rels"), ".xml", ".xml.rels"), rID)
+	}
+	return err
+}
+
+// MoveDrawingObject provides a function to move the chart, picture or shape
+// in the worksheet to the given cell by given drawing object handle which
+// returned by the AddChartObject, AddPictureObject, AddPictureFromBytesObject
+// or AddShapeObject function. The size of the object and the offsets with the
+// anchor cells will be kept. The object with absolute anchor which not
+// anchored to the cells could not be moved. For example, move the chart to
+// Sheet1!H2:
+//
+//	err := f.MoveDrawingObject(obj, "H2")
+func (f *File) MoveDrawingObject(obj DrawingObject, cell string) error {
+	col, row, err := CellNameToCoordinates(cell)
+	if err != nil {
+		return err
+	}
+	drawingXML, wsDr, anchors, idx, err := f.getDrawingObject(obj)
+	if err != nil {
+		return err
+	}
+	if anchors == &wsDr.AbsoluteAnchor {
+		return ErrParameterInvalid
+	}
+	wsDr.mu.Lock()
+	defer wsDr.mu.Unlock()
+	anchor := (*anchors)[idx]
+	if anchor.From != nil {
+		if err = moveCellAnchor(anchor.From, anchor.To, col-1, row-1); err != nil {
+			return err
+		}
+		f.Drawings.Store(drawingXML, wsDr)
+		return err
+	}
+	pos := f.getCellAnchorPos(anchor)
+	if pos.From == nil {
+		return err
+	}
+	if err = moveCellAnchor(pos.From, pos.To, col-1, row-1); err != nil {
+		return err
+	}
+	anchor.setCellAnchorPos(pos)
+	f.Drawings.Store(drawingXML, wsDr)
+	return err
+}
+
+// UpdateDrawingObject provides a function to update the properties of the
+// chart, picture or shape in the worksheet by given drawing object handle
+// which returned by the AddChartObject, AddPictureObject,
+// AddPictureFromBytesObject or AddShapeObject function and graphic options.
+// The "AltText", "PrintObject", "Locked" and "Positioning" options are
+// supported, the alternative text and positioning will be kept if the option
+// is empty, the print and locked settings will be kept if the option is nil,
+// and other options will be ignored. The positioning could be only set for
+// the object with two cell anchor. For example, set the alternative text of
+// the picture and not print it with the worksheet:
+//
+//	disable := false
+//	err := f.UpdateDrawingObject(obj, &excelize.GraphicOptions{
+//	    AltText:     "Excel Logo",
+//	    PrintObject: &disable,
+//	})
+func (f *File) UpdateDrawingObject(obj DrawingObject, opts *GraphicOptions) error {
+	if opts == nil {
+		return ErrParameterInvalid
+	}
+	if opts.Positioning != "" && inStrSlice(supportedPositioning, opts.Positioning, true) == -1 {
+		return ErrParameterInvalid
+	}
+	drawingXML, wsDr, anchors, idx, err := f.getDrawingObject(obj)
+	if err != nil {
+		return err
+	}
+	if opts.Positioning != "" && anchors != &wsDr.TwoCellAnchor {
+		return ErrParameterInvalid
+	}
+	wsDr.mu.Lock()
+	defer wsDr.mu.Unlock()
+	anchor := (*anchors)[idx]
+	if opts.Positioning != "" {
+		anchor.EditAs = opts.Positioning
+	}
+	if opts.AltText != "" {
+		anchor.setCellAnchorAltText(opts.AltText)
+	}
+	if opts.PrintObject != nil || opts.Locked != nil {
+		anchor.setCellAnchorClientData(opts.PrintObject, opts.Locked)
+	}
+	f.Drawings.Store(drawingXML, wsDr)
+	return err
+}
+
+// setCellAnchorAltText provides a function to set the alternative text of
+// the non-visual drawing properties of the object in the cell anchor.
+func (a *xdrCellAnchor) setCellAnchorAltText(altText string) {
+	if a.Pic != nil {
+		a.Pic.NvPicPr.CNvPr.Descr = altText
+		return
+	}
+	if a.Sp != nil && a.Sp.NvSpPr != nil && a.Sp.NvSpPr.CNvPr != nil {
+		a.Sp.NvSpPr.CNvPr.Descr = altText
+		return
+	}
+	if loc := drawingCNvPrExp.FindStringIndex(a.GraphicFrame); loc != nil {
+		a.GraphicFrame = a.GraphicFrame[:loc[0]] +
+			setStartElementAttr(a.GraphicFrame[loc[0]:loc[1]], "descr", altText) +
+			a.GraphicFrame[loc[1]:]
+	}
+}
+
+// setCellAnchorClientData provides a function to set the print and locked
+// settings of the client data in the cell anchor, the setting will be kept if
+// the given value is nil.
+func (a *xdrCellAnchor) setCellAnchorClientData(printObject, locked *bool) {
+	if a.ClientData != nil {
+		if printObject != nil {
+			a.ClientData.FPrintsWithSheet = *printObject
+		}
+		if locked != nil {
+			a.ClientData.FLocksWithSheet = *locked
+		}
+		return
+	}
+	loc := drawingClientDataExp.FindStringIndex(a.GraphicFrame)
+	if loc == nil {
+		return
+	}
+	element := a.GraphicFrame[loc[0]:loc[1]]
+	if printObject != nil {
+		element = setStartElementAttr(element, "fPrintsWithSheet", strconv.FormatBool(*printObject))
+	}
+	if locked != nil {
+		element = setStartElementAttr(element, "fLocksWithSheet", strconv.FormatBool(*locked))
+	}
+	a.GraphicFrame = a.GraphicFrame[:loc[0]] + element + a.GraphicFrame[loc[1]:]
+}
+
+// setStartElementAttr provides a function to set the attribute value of the
+// XML start element string by given attribute name and value, the attribute
+// will be appended if not exists.
+func setStartElementAttr(element, name, value string) string {
+	var buf bytes.Buffer
+	_ = xml.EscapeText(&buf, []byte(value))
+	attr := " " + name + "=\"" + buf.String() + "\""
+	if idx := strings.Index(element, " "+name+"=\""); idx != -1 {
+		end := strings.Index(element[idx+len(name)+3:], "\"")
+		return element[:idx] + attr + element[idx+len(name)+3+end+1:]
+	}
+	end := len(element) - 1
+	if strings.HasSuffix(element, "/>") {
+		end--
+	}
+	return element[:end] + attr + element[end:]
+}
+
+// moveCellAnchor provides a function to move the starting and ending anchors
+// of the drawing object to the given zero-based column and row number.
+func moveCellAnchor(from *xlsxFrom, to *xlsxTo, col, row int) error {
+	if to != nil {
+		if to.Col+col-from.Col >= MaxColumns {
+			return ErrColumnNumber
+		}
+		if to.Row+row-from.Row >= TotalRows {
+			return ErrMaxRows
+		}
+		to.Col, to.Row = to.Col+col-from.Col, to.Row+row-from.Row
+	}
+	from.Col, from.Row = col, row
+	return nil
+}
+
 // extractEmbedRID returns embed relationship ID and all relationship ID lists
 // for giving cell anchor.
 func extractEmbedRID(pic *xlsxPic, decodePic *decodePic, rIDs []string) (string, []string) {
@@ -13,6 +13,7 @@ package excelize_ch
 
 import (
 	"encoding/xml"
+	"path/filepath"
 	"sync"
 	"testing"
 
@@ -39,6 +40,26 @@ func TestDrawingParser(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestDrawingParserKeepNameSpaces(t *testing.T) {
+	f := NewFile()
+	f.Pkg.Store("xl/drawings/drawing1.xml", []byte(xml.Header+`<xdr:wsDr xmlns:xdr="http://schemas.openxmlformats.org/drawingml/2006/spreadsheetDrawing" xmlns:a="http://schemas.openxmlformats.org/drawingml/2006/main" xmlns:mc="http://schemas.openxmlformats.org/markup-compatibility/2006" xmlns:a14="http://schemas.microsoft.com/office/drawing/2010/main" xmlns:m="http://schemas.openxmlformats.org/officeDocument/2006/math" mc:Ignorable="a14"><xdr:twoCellAnchor><xdr:from><xdr:col>1</xdr:col><xdr:colOff>0</xdr:colOff><xdr:row>1</xdr:row><xdr:rowOff>0</xdr:rowOff></xdr:from><xdr:to><xdr:col>3</xdr:col><xdr:colOff>0</xdr:colOff><xdr:row>3</xdr:row><xdr:rowOff>0</xdr:rowOff></xdr:to><xdr:sp macro="" textlink=""><xdr:nvSpPr><xdr:cNvPr id="2" name="TextBox 1"/><xdr:cNvSpPr txBox="1"/></xdr:nvSpPr><xdr:spPr/><xdr:txBody><a:bodyPr/><a:p><a14:m><m:oMathPara><m:oMath><m:r><m:t>x</m:t></m:r></m:oMath></m:oMathPara></a14:m></a:p></xdr:txBody></xdr:sp><xdr:clientData/></xdr:twoCellAnchor></xdr:wsDr>`))
+	wsDr, _, err := f.drawingParser("xl/drawings/drawing1.xml")
+	assert.NoError(t, err)
+	assert.Equal(t, []xml.Attr{
+		{Name: xml.Name{Local: "xmlns:mc"}, Value: SourceRelationshipCompatibility.Value},
+		{Name: xml.Name{Local: "xmlns:a14"}, Value: NameSpaceDrawingMLA14.Value},
+		{Name: xml.Name{Local: "xmlns:m"}, Value: NameSpaceOfficeMath.Value},
+		{Name: xml.Name{Local: "mc:Ignorable"}, Value: "a14"},
+	}, wsDr.Attrs)
+	f.drawingsWriter()
+	drawing, ok := f.Pkg.Load("xl/drawings/drawing1.xml")
+	assert.True(t, ok)
+	assert.Contains(t, string(drawing.([]byte)), `xmlns:a14="http://schemas.microsoft.com/office/drawing/2010/main" xmlns:m="http://schemas.openxmlformats.org/officeDocument/2006/math" mc:Ignorable="a14">`)
+	assert.Contains(t, string(drawing.([]byte)), `<a14:m><m:oMathPara><m:oMath><m:r><m:t>x</m:t></m:r></m:oMath></m:oMathPara></a14:m>`)
+	// Test the decoded drawing is well-formed after serialization
+	assert.NoError(t, xml.Unmarshal(drawing.([]byte), &decodeWsDr{}))
+}
+
 func TestDeleteDrawingRels(t *testing.T) {
 	f := NewFile()
 	// Test delete drawing relationships with unsupported charset
@@ -47,3 +68,141 @@ func TestDeleteDrawingRels(t *testing.T) {
 	f.Pkg.Store(rels, MacintoshCyrillicCharset)
 	f.deleteDrawingRels(rels, "")
 }
+
+func TestDrawingObject(t *testing.T) {
+	f := NewFile()
+	pic, err := f.AddPictureObject("Sheet1", "A1", filepath.Join("test", "images", "excel.jpg"), nil)
+	assert.NoError(t, err)
+	assert.Equal(t, DrawingObject{Sheet: "Sheet1", ID: 2, Name: "Picture 2"}, pic)
+	chart, err := f.AddChartObject("Sheet1", "A1", &Chart{Type: Col, Series: []ChartSeries{{Name: "Sheet1!$A$1", Values: "Sheet1!$B$1:$D$1"}}})
+	assert.NoError(t, err)
+	assert.Equal(t, DrawingObject{Sheet: "Sheet1", ID: 3, Name: "Chart 3"}, chart)
+	shape, err := f.AddShapeObject("Sheet1", &Shape{Cell: "A1", Type: "rect"})
+	assert.NoError(t, err)
+	assert.Equal(t, DrawingObject{Sheet: "Sheet1", ID: 4, Name: "Shape 4"}, shape)
+
+	// Test move and delete the drawing objects which share the same anchor cell
+	assert.NoError(t, f.MoveDrawingObject(chart, "H2"))
+	assert.NoError(t, f.DeleteDrawingObject(pic))
+	_, ok := f.Pkg.Load("xl/media/image1.jpg")
+	assert.False(t, ok)
+	wsDr, _, err := f.drawingParser("xl/drawings/drawing1.xml")
+	assert.NoError(t, err)
+	assert.Len(t, wsDr.TwoCellAnchor, 2)
+	assert.Equal(t, 7, wsDr.TwoCellAnchor[0].From.Col)
+	assert.Equal(t, 1, wsDr.TwoCellAnchor[0].From.Row)
+	assert.Equal(t, 0, wsDr.TwoCellAnchor[1].From.Col)
+
+	// Test the ID of the drawing object is unique after deleting objects
+	pic, err = f.AddPictureObject("Sheet1", "A1", filepath.Join("test", "images", "excel.png"), nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 5, pic.ID)
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestDrawingObject.xlsx")))
+	assert.NoError(t, f.Close())
+
+	// Test move and delete the drawing objects in the opened workbook
+	f, err = OpenFile(filepath.Join("test", "TestDrawingObject.xlsx"))
+	assert.NoError(t, err)
+	assert.NoError(t, f.MoveDrawingObject(DrawingObject{Sheet: "Sheet1", ID: shape.ID}, "C3"))
+	assert.NoError(t, f.DeleteDrawingObject(DrawingObject{Sheet: "Sheet1", ID: pic.ID}))
+	_, ok = f.Pkg.Load("xl/media/image2.png")
+	assert.False(t, ok)
+	assert.EqualError(t, f.DeleteDrawingObject(pic), newNoExistDrawingObjectError("Sheet1", pic.ID).Error())
+	wsDr, _, err = f.drawingParser("xl/drawings/drawing1.xml")
+	assert.NoError(t, err)
+	assert.Len(t, wsDr.TwoCellAnchor, 2)
+	assert.Equal(t, xlsxFrom{Col: 2, Row: 2}, *f.getCellAnchorPos(wsDr.TwoCellAnchor[1]).From)
+	assert.Equal(t, ErrColumnNumber, f.MoveDrawingObject(shape, "XFD1"))
+	assert.Equal(t, ErrMaxRows, f.MoveDrawingObject(shape, "A1048576"))
+	assert.Equal(t, ErrColumnNumber, f.MoveDrawingObject(chart, "XFD1"))
+	assert.Equal(t, ErrMaxRows, f.MoveDrawingObject(chart, "A1048576"))
+	// Test move the drawing object with invalid cell reference
+	assert.EqualError(t, f.MoveDrawingObject(chart, "A"), newCellNameToCoordinatesError("A", newInvalidCellNameError("A")).Error())
+	// Test update the drawing objects in the opened workbook
+	disable := false
+	assert.NoError(t, f.UpdateDrawingObject(shape, &GraphicOptions{AltText: "Text <Box>", PrintObject: &disable, Locked: &disable, Positioning: "oneCell"}))
+	wsDr, _, err = f.drawingParser("xl/drawings/drawing1.xml")
+	assert.NoError(t, err)
+	assert.Equal(t, "oneCell", wsDr.TwoCellAnchor[1].EditAs)
+	assert.Contains(t, wsDr.TwoCellAnchor[1].GraphicFrame, `descr="Text &lt;Box&gt;"`)
+	assert.Contains(t, wsDr.TwoCellAnchor[1].GraphicFrame, `<xdr:clientData fPrintsWithSheet="false" fLocksWithSheet="false"`)
+	assert.NoError(t, f.UpdateDrawingObject(shape, &GraphicOptions{AltText: "Text Box"}))
+	assert.Contains(t, wsDr.TwoCellAnchor[1].GraphicFrame, `descr="Text Box"`)
+	// Test update the drawing object with invalid options
+	assert.Equal(t, ErrParameterInvalid, f.UpdateDrawingObject(shape, nil))
+	assert.Equal(t, ErrParameterInvalid, f.UpdateDrawingObject(shape, &GraphicOptions{Positioning: "x"}))
+	assert.EqualError(t, f.UpdateDrawingObject(pic, &GraphicOptions{}), newNoExistDrawingObjectError("Sheet1", pic.ID).Error())
+	// Test move and delete the drawing object on the worksheet without drawing
+	_, err = f.NewSheet("Sheet2")
+	assert.NoError(t, err)
+	assert.EqualError(t, f.MoveDrawingObject(DrawingObject{Sheet: "Sheet2", ID: 2}, "A1"), newNoExistDrawingObjectError("Sheet2", 2).Error())
+	assert.EqualError(t, f.DeleteDrawingObject(DrawingObject{Sheet: "Sheet2", ID: 2}), newNoExistDrawingObjectError("Sheet2", 2).Error())
+	// Test move and delete the drawing object on not exists worksheet
+	assert.EqualError(t, f.MoveDrawingObject(DrawingObject{Sheet: "SheetN", ID: 2}, "A1"), "sheet SheetN does not exist")
+	assert.EqualError(t, f.DeleteDrawingObject(DrawingObject{Sheet: "SheetN", ID: 2}), "sheet SheetN does not exist")
+	// Test delete the drawing object with unsupported charset drawing part
+	f.Drawings.Delete("xl/drawings/drawing1.xml")
+	f.Pkg.Store("xl/drawings/drawing1.xml", MacintoshCyrillicCharset)
+	assert.EqualError(t, f.DeleteDrawingObject(shape), "XML syntax error on line 1: invalid UTF-8")
+	assert.NoError(t, f.Close())
+}
+
+func TestUpdateDrawingObject(t *testing.T) {
+	f := NewFile()
+	pic, err := f.AddPictureObject("Sheet1", "A1", filepath.Join("test", "images", "excel.jpg"), nil)
+	assert.NoError(t, err)
+	chart, err := f.AddChartObject("Sheet1", "E1", &Chart{Type: Col, Series: []ChartSeries{{Name: "Sheet1!$A$1", Values: "Sheet1!$B$1:$D$1"}}})
+	assert.NoError(t, err)
+	shape, err := f.AddShapeObject("Sheet1", &Shape{Cell: "A10", Type: "rect"})
+	assert.NoError(t, err)
+	enable, disable := true, false
+	for _, obj := range []DrawingObject{pic, chart, shape} {
+		assert.NoError(t, f.UpdateDrawingObject(obj, &GraphicOptions{AltText: obj.Name, PrintObject: &disable, Locked: &enable, Positioning: "absolute"}))
+	}
+	wsDr, _, err := f.drawingParser("xl/drawings/drawing1.xml")
+	assert.NoError(t, err)
+	assert.Len(t, wsDr.TwoCellAnchor, 3)
+	for _, anchor := range wsDr.TwoCellAnchor {
+		assert.Equal(t, "absolute", anchor.EditAs)
+		assert.Equal(t, &xdrClientData{FLocksWithSheet: true, FPrintsWithSheet: false}, anchor.ClientData)
+	}
+	assert.Equal(t, pic.Name, wsDr.TwoCellAnchor[0].Pic.NvPicPr.CNvPr.Descr)
+	assert.Contains(t, wsDr.TwoCellAnchor[1].GraphicFrame, `descr="`+chart.Name+`"`)
+	assert.Equal(t, shape.Name, wsDr.TwoCellAnchor[2].Sp.NvSpPr.CNvPr.Descr)
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestUpdateDrawingObject.xlsx")))
+	assert.NoError(t, f.Close())
+
+	f, err = OpenFile(filepath.Join("test", "TestUpdateDrawingObject.xlsx"))
+	assert.NoError(t, err)
+	pics, err := f.GetPictures("Sheet1", "A1")
+	assert.NoError(t, err)
+	assert.Len(t, pics, 1)
+	assert.Equal(t, pic.Name, pics[0].Format.AltText)
+	// Test update, move and delete the drawing object with absolute anchor
+	wsDr, _, err = f.drawingParser("xl/drawings/drawing1.xml")
+	assert.NoError(t, err)
+	wsDr.AbsoluteAnchor = append(wsDr.AbsoluteAnchor, &xdrCellAnchor{
+		Pos: &xlsxPoint2D{}, Ext: &aExt{},
+		Sp:         &xdrSp{NvSpPr: &xdrNvSpPr{CNvPr: &xlsxCNvPr{ID: 100, Name: "Shape 100"}}},
+		ClientData: &xdrClientData{},
+	})
+	obj := DrawingObject{Sheet: "Sheet1", ID: 100}
+	assert.NoError(t, f.UpdateDrawingObject(obj, &GraphicOptions{AltText: "Absolute", PrintObject: &enable}))
+	assert.Equal(t, "Absolute", wsDr.AbsoluteAnchor[0].Sp.NvSpPr.CNvPr.Descr)
+	assert.True(t, wsDr.AbsoluteAnchor[0].ClientData.FPrintsWithSheet)
+	assert.Equal(t, ErrParameterInvalid, f.UpdateDrawingObject(obj, &GraphicOptions{Positioning: "oneCell"}))
+	assert.Equal(t, ErrParameterInvalid, f.MoveDrawingObject(obj, "A1"))
+	assert.NoError(t, f.DeleteDrawingObject(obj))
+	assert.Empty(t, wsDr.AbsoluteAnchor)
+	assert.NoError(t, f.Close())
+}
+
+func TestSetStartElementAttr(t *testing.T) {
+	for _, c := range []struct{ element, expected string }{
+		{`<xdr:cNvPr id="2" name="Picture 2" descr="">`, `<xdr:cNvPr id="2" name="Picture 2" descr="a&amp;b">`},
+		{`<xdr:cNvPr id="2" name="Picture 2"/>`, `<xdr:cNvPr id="2" name="Picture 2" descr="a&amp;b"/>`},
+		{`<cNvPr id="2">`, `<cNvPr id="2" descr="a&amp;b">`},
+	} {
+		assert.Equal(t, c.expected, setStartElementAttr(c.element, "descr", "a&b"))
+	}
+}
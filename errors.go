@@ -14,6 +14,7 @@ package excelize_ch
 import (
 	"errors"
 	"fmt"
+	"strings"
 )
 
 var (
@@ -37,6 +38,9 @@ var (
 	// ErrCoordinates defined the error message on invalid coordinates tuples
 	// length.
 	ErrCoordinates = errors.New("coordinates length must be 4")
+	// ErrCustomUI defined the error message on receive the invalid custom UI
+	// definition.
+	ErrCustomUI = errors.New("the root element of the custom UI must be customUI")
 	// ErrCustomNumFmt defined the error message on receive the empty custom number format.
 	ErrCustomNumFmt = errors.New("custom number format can not be empty")
 	// ErrDataValidationFormulaLength defined the error message for receiving a
@@ -65,6 +69,9 @@ var (
 	ErrFormControlValue = fmt.Errorf("scroll value must be between 0 and %d", MaxFormControlValue)
 	// ErrGroupSheets defined the error message on group sheets.
 	ErrGroupSheets = errors.New("group worksheet must contain an active worksheet")
+	// ErrHeaderFooterLength defined the error message on receive the header or
+	// footer exceeds the limit.
+	ErrHeaderFooterLength = fmt.Errorf("the header or footer must be less than or equal to %d characters", MaxFieldLength)
 	// ErrImgExt defined the error message on receive an unsupported image
 	// extension.
 	ErrImgExt = errors.New("unsupported image extension")
@@ -79,9 +86,15 @@ var (
 	ErrMaxRowHeight = fmt.Errorf("the height of the row must be less than or equal to %d points", MaxRowHeight)
 	// ErrMaxRows defined the error message on receive a row number exceeds maximum limit.
 	ErrMaxRows = errors.New("row number exceeds maximum limit")
+	// ErrMoveMergedCells defined the error message on moving the rows which
+	// cuts the part of the merged cells.
+	ErrMoveMergedCells = errors.New("cannot move a part of the merged cells")
 	// ErrNameLength defined the error message on receiving the defined name or
 	// table name length exceeds the limit.
 	ErrNameLength = fmt.Errorf("the name length exceeds the %d characters limit", MaxFieldLength)
+	// ErrNonFiniteNumber defined the error message on set the NaN or infinity
+	// float value for a cell.
+	ErrNonFiniteNumber = errors.New("the NaN or infinity number is not supported in the cell")
 	// ErrOptionsUnzipSizeLimit defined the error message for receiving
 	// invalid UnzipSizeLimit and UnzipXMLSizeLimit.
 	ErrOptionsUnzipSizeLimit = errors.New("the value of UnzipSizeLimit should be greater than or equal to UnzipXMLSizeLimit")
@@ -97,11 +110,20 @@ var (
 	// ErrPasswordLengthInvalid defined the error message on invalid password
 	// length.
 	ErrPasswordLengthInvalid = errors.New("password length invalid")
+	// ErrProtectedObjects defined the error message on adding, moving or
+	// deleting the objects of the worksheet which protected the objects.
+	ErrProtectedObjects = errors.New("the objects of the worksheet are protected")
 	// ErrSave defined the error message for saving file.
 	ErrSave = errors.New("no path defined for file, consider File.WriteTo or File.Write")
+	// ErrScanNoDataRow defined the error message on scanning the rows iterator
+	// without any data row after the header row.
+	ErrScanNoDataRow = errors.New("no data row to scan after the header row")
 	// ErrSheetIdx defined the error message on receive the invalid worksheet
 	// index.
 	ErrSheetIdx = errors.New("invalid worksheet index")
+	// ErrShapeEquation defined the error message on receive the invalid
+	// equation of the shape.
+	ErrShapeEquation = errors.New("the equation must be Office Math Markup with the oMath or oMathPara root element")
 	// ErrSheetNameBlank defined the error message on receive the blank sheet
 	// name.
 	ErrSheetNameBlank = errors.New("the sheet name can not be blank")
@@ -114,6 +136,9 @@ var (
 	// ErrSheetNameSingleQuote defined the error message on the first or last
 	// character of the sheet name was a single quote.
 	ErrSheetNameSingleQuote = errors.New("the first or last character of the sheet name can not be a single quote")
+	// ErrSheetViewType defined the error message on receive the invalid sheet
+	// view type.
+	ErrSheetViewType = errors.New("the view type must be one of 'normal', 'pageLayout' or 'pageBreakPreview'")
 	// ErrSparkline defined the error message on receive the invalid sparkline
 	// parameters.
 	ErrSparkline = errors.New("must have the same number of 'Location' and 'Range' parameters")
@@ -162,9 +187,15 @@ var (
 	// ErrUnsupportedNumberFormat defined the error message on unsupported number format
 	// expression.
 	ErrUnsupportedNumberFormat = errors.New("unsupported number format token")
+	// ErrVBAProjectNotExist defined the error message on saving the workbook
+	// as the macro-enabled file format without VBA project.
+	ErrVBAProjectNotExist = errors.New("the macro-enabled file format requires a VBA project")
 	// ErrWorkbookFileFormat defined the error message on receive an
 	// unsupported workbook file format.
 	ErrWorkbookFileFormat = errors.New("unsupported workbook file format")
+	// ErrWorkbookLocked defined the error message on saving the workbook which
+	// is opened by other applications.
+	ErrWorkbookLocked = errors.New("the workbook is locked by another application")
 	// ErrWorkbookPassword defined the error message on receiving the incorrect
 	// workbook password.
 	ErrWorkbookPassword = errors.New("the supplied open workbook password is not correct")
@@ -186,12 +217,31 @@ func newCellNameToCoordinatesError(cell string, err error) error {
 	return fmt.Errorf("cannot convert cell %q to coordinates: %v", cell, err)
 }
 
+// newCircularReferenceError defined the error message on the formulas with
+// circular references which can't be calculated without iterative
+// calculation.
+func newCircularReferenceError(cells []string) error {
+	return fmt.Errorf("circular reference in cells %s", strings.Join(cells, ", "))
+}
+
+// newCoerceNumberError defined the error message on receiving the cell value
+// which could not be coerced to number in the column aggregation.
+func newCoerceNumberError(value, cell string) error {
+	return fmt.Errorf("cannot coerce value %q of cell %s to number", value, cell)
+}
+
 // newCoordinatesToCellNameError defined the error message on converts [X, Y]
 // coordinates to alpha-numeric cell name.
 func newCoordinatesToCellNameError(col, row int) error {
 	return fmt.Errorf("invalid cell reference [%d, %d]", col, row)
 }
 
+// newDuplicateCustomUIIDError defined the error message on receiving the
+// duplicate control identifier in the custom UI definition.
+func newDuplicateCustomUIIDError(id string) error {
+	return fmt.Errorf("duplicate custom UI control id %s", id)
+}
+
 // newFieldLengthError defined the error message on receiving the field length
 // overflow.
 func newFieldLengthError(name string) error {
@@ -234,6 +284,12 @@ func newInvalidExcelDateError(dateValue float64) error {
 	return fmt.Errorf("invalid date value %f, negative values are not supported", dateValue)
 }
 
+// newInvalidExternalRefError defined the error message on receiving the
+// invalid external workbook cell reference.
+func newInvalidExternalRefError(ref string) error {
+	return fmt.Errorf("invalid external reference %q", ref)
+}
+
 // newInvalidLinkTypeError defined the error message on receiving the invalid
 // hyper link type.
 func newInvalidLinkTypeError(linkType string) error {
@@ -264,6 +320,48 @@ func newInvalidStyleID(styleID int) error {
 	return fmt.Errorf("invalid style ID %d", styleID)
 }
 
+// newInvalidTimelineNameError defined the error message on receiving the
+// invalid timeline name.
+func newInvalidTimelineNameError(name string) error {
+	return fmt.Errorf("invalid timeline name %q", name)
+}
+
+// newNoExistChartError defined the error message on receiving the non
+// existing chart in the worksheet by given cell reference or chart name.
+func newNoExistChartError(sheet, cellOrName string) error {
+	return fmt.Errorf("chart %s does not exist in sheet %s", cellOrName, sheet)
+}
+
+// newNoExistCustomUIImageError defined the error message on receiving the non
+// existing image identifier referenced in the custom UI definition.
+func newNoExistCustomUIImageError(id string) error {
+	return fmt.Errorf("custom UI image %s does not exist", id)
+}
+
+// newNoExistDrawingObjectError defined the error message on receiving the non
+// existing drawing object in the worksheet.
+func newNoExistDrawingObjectError(sheet string, id int) error {
+	return fmt.Errorf("drawing object %d does not exist in sheet %s", id, sheet)
+}
+
+// newNoExistExternalLinkError defined the error message on receiving the non
+// existing external workbook link.
+func newNoExistExternalLinkError(book string) error {
+	return fmt.Errorf("external link %s does not exist", book)
+}
+
+// newNoExistImageError defined the error message on receiving the non existing
+// registered image ID.
+func newNoExistImageError(imageID int) error {
+	return fmt.Errorf("image %d does not exist", imageID)
+}
+
+// newNoExistPartError defined the error message on receiving the non existing
+// part name of the package.
+func newNoExistPartError(name string) error {
+	return fmt.Errorf("part %s does not exist", name)
+}
+
 // newNoExistTableError defined the error message on receiving the non existing
 // table name.
 func newNoExistTableError(name string) error {
@@ -288,6 +386,18 @@ func newPivotTableRangeError(msg string) error {
 	return fmt.Errorf("parameter 'PivotTableRange' parsing error: %s", msg)
 }
 
+// newScanFieldError defined the error message on scanning the cell value
+// which can't be converted to the type of the struct field.
+func newScanFieldError(value, cell, field string) error {
+	return fmt.Errorf("cannot scan value %q of cell %s into field %s", value, cell, field)
+}
+
+// newSeekRowError defined the error message on seeking the rows iterator to
+// the row before the current row.
+func newSeekRowError(row int) error {
+	return fmt.Errorf("cannot seek to row %d before the current row", row)
+}
+
 // newStreamSetRowError defined the error message on the stream writer
 // receiving the non-ascending row number.
 func newStreamSetRowError(row int) error {
@@ -300,6 +410,18 @@ func newUnknownFilterTokenError(token string) error {
 	return fmt.Errorf("unknown operator: %s", token)
 }
 
+// newUnorderedCellError defined the error message on receiving the out of
+// order or duplicated cell in a row of the worksheet.
+func newUnorderedCellError(cell string) error {
+	return fmt.Errorf("cell %s is out of order or duplicated", cell)
+}
+
+// newUnorderedRowError defined the error message on receiving the out of
+// order or duplicated row of the worksheet.
+func newUnorderedRowError(row int) error {
+	return fmt.Errorf("row %d is out of order or duplicated", row)
+}
+
 // newUnsupportedChartType defined the error message on receiving the chart
 // type are unsupported.
 func newUnsupportedChartType(chartType ChartType) error {
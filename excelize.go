@@ -19,42 +19,64 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"regexp"
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 
+	"github.com/xuri/efp"
 	"golang.org/x/net/html/charset"
 )
 
+var (
+	// externalLinkIndexExp is the regular expression for matching the
+	// external link index and the sheet names at the beginning of the
+	// reference operand in the formulas and defined names, such as
+	// "[1]Sheet1!" and "[1]!".
+	externalLinkIndexExp = regexp.MustCompile(`^\[(\d+)\]([^!\[\]]*)!`)
+	// embeddedObjectExp is the regular expression for matching the start
+	// element of the embedded OLE object or the ActiveX control in the
+	// alternate content of the worksheet.
+	embeddedObjectExp = regexp.MustCompile(`<(\w+:)?(oleObject|control)\b`)
+)
+
 // File define a populated spreadsheet file struct.
 type File struct {
-	mu               sync.Mutex
-	checked          sync.Map
-	options          *Options
-	sharedStringItem [][]uint
-	sharedStringsMap map[string]int
-	sharedStringTemp *os.File
-	sheetMap         map[string]string
-	streams          map[string]*StreamWriter
-	tempFiles        sync.Map
-	xmlAttr          sync.Map
-	CalcChain        *xlsxCalcChain
-	CharsetReader    charsetTranscoderFn
-	Comments         map[string]*xlsxComments
-	ContentTypes     *xlsxTypes
-	DecodeVMLDrawing map[string]*decodeVmlDrawing
-	Drawings         sync.Map
-	Path             string
-	Pkg              sync.Map
-	Relationships    sync.Map
-	SharedStrings    *xlsxSST
-	Sheet            sync.Map
-	SheetCount       int
-	Styles           *xlsxStyleSheet
-	Theme            *decodeTheme
-	VMLDrawing       map[string]*vmlDrawing
-	VolatileDeps     *xlsxVolTypes
-	WorkBook         *xlsxWorkbook
+	mu                sync.Mutex
+	calcDeps          formulaIndex
+	changedCells      sync.Map
+	checked           sync.Map
+	functions         sync.Map
+	images            imageRegistry
+	options           *Options
+	rawParts          sync.Map
+	sharedStringCache *lruCache
+	sharedStringItem  [][]uint
+	sharedStringsMap  map[string]int
+	sharedStringTemp  *os.File
+	sheetMap          map[string]string
+	streams           map[string]*StreamWriter
+	tempFiles         sync.Map
+	watch             cellWatchers
+	xmlAttr           sync.Map
+	CalcChain         *xlsxCalcChain
+	CharsetReader     charsetTranscoderFn
+	Comments          map[string]*xlsxComments
+	ContentTypes      *xlsxTypes
+	DecodeVMLDrawing  map[string]*decodeVmlDrawing
+	Drawings          sync.Map
+	Path              string
+	Pkg               sync.Map
+	Relationships     sync.Map
+	SharedStrings     *xlsxSST
+	Sheet             sync.Map
+	SheetCount        int
+	Styles            *xlsxStyleSheet
+	Theme             *decodeTheme
+	VMLDrawing        map[string]*vmlDrawing
+	VolatileDeps      *xlsxVolTypes
+	WorkBook          *xlsxWorkbook
 }
 
 // charsetTranscoderFn set user-defined codepage transcoder function for open
@@ -95,6 +117,81 @@ type charsetTranscoderFn func(charset string, input io.Reader) (rdr io.Reader, e
 //
 // CultureInfo specifies the country code for applying built-in language number
 // format code these effect by the system's local language settings.
+//
+// StrictSheetData specifies if the rows iterator reports the malformed
+// worksheet data generated by some applications as an error instead of
+// repairing it, such as out of order or duplicated rows and cells, and cells
+// beyond the maximum column XFD. By default, the functions which read all
+// rows of the worksheet, such as GetRows, will read the rows from an in-memory
+// copy of the worksheet, which out of order rows are sorted and duplicated
+// rows and cells are merged, without changing the workbook, and the cells
+// beyond the maximum column will be skipped. The rows iterator returned by the
+// Rows function will continue from the sorted copy of the worksheet after the
+// out of order or duplicated row was found, or stops at that row and reports
+// it by the Error function in the strict mode.
+//
+// EscapeFormula specifies if escape the string cell value which begins with
+// '=', '+', '-' or '@' character by prefixing an apostrophe when setting the
+// cell value, to prevent formula injection when writing untrusted data.
+//
+// PadRows specifies if pad each row returned by the GetRows function with
+// empty strings to the width of the used range of the worksheet, so the
+// returned rows will be a rectangular two-dimensional array.
+//
+// BigNumberAsText specifies if write the big number types and the Decimal
+// values which have more than 15 significant digits as text instead of
+// rounding them, to prevent the precision loss of the financial amounts.
+//
+// NonFinitePolicy specifies the behavior on writing the NaN or infinity
+// float values, which can't be stored as the number in the spreadsheet. By
+// default, an ErrNonFiniteNumber error will be returned. Use
+// NonFinitePolicyBlank to write a blank cell, NonFinitePolicyNumError to
+// write the #NUM! error value, or NonFinitePolicyString to write the values
+// as text "NaN", "+Inf" or "-Inf".
+//
+// SSTCacheSize specifies the number of the shared strings cached in memory
+// when reading the cell values. If this value is greater than 0, the shared
+// string table will be extracted to system temporary directory and indexed
+// by offset on open the spreadsheet, and the shared strings will be resolved
+// lazily through a least recently used cache with the given size, to reduce
+// the memory usage of the spreadsheet which has a huge shared string table.
+// Note that the whole shared string table will be loaded into memory when
+// writing the string cell values.
+//
+// Logger specifies the receiver of the structured events on opening and
+// reading the spreadsheet, such as the package parts parsed with the time
+// spent, the malformed worksheet data repaired, the unsupported features
+// preserved, and the temporary files created.
+//
+// ForceEditObjects specifies if allow adding, moving or deleting the charts,
+// pictures, shapes, comments and form controls on the worksheet which is
+// protected without the EditObjects permission. By default, an
+// ErrProtectedObjects error will be returned.
+//
+// AutoFitRowHeight specifies if increase the height of the row to fit the
+// text when setting the string cell value with the wrap text style, the
+// width of the merged cell will be used if the cell is merged. The height of
+// the row will not be decreased.
+//
+// ImageFetcher specifies the function to fetch the image content by given
+// source URL of the IMAGE formula function. If this function was specified,
+// the GetPictures function will return the images of the cell which
+// contains the IMAGE formula function, so that the images could be embedded
+// when exporting the worksheet to other formats.
+//
+// Deterministic specifies if write the spreadsheet in the deterministic mode,
+// the package parts will be written in a stable order, so saving the same
+// logical workbook twice yields byte-identical files, which is useful for
+// content-addressed caching and comparing the generated files. Note that
+// the encrypted workbook always uses the random salt to derive the keys, and
+// the volatile functions such as NOW and RAND still depend on the time of
+// the calculation.
+//
+// IgnoreLockFile specifies if skip detecting the lock file created by
+// Microsoft Excel or LibreOffice when saving the spreadsheet by the SaveAs
+// function, such as the stale lock file left by a crashed application. By
+// default, an ErrWorkbookLocked error will be returned if the lock file of
+// the spreadsheet exists.
 type Options struct {
 	MaxCalcIterations uint
 	Password          string
@@ -105,6 +202,18 @@ type Options struct {
 	LongDatePattern   string
 	LongTimePattern   string
 	CultureInfo       CultureName
+	StrictSheetData   bool
+	EscapeFormula     bool
+	PadRows           bool
+	BigNumberAsText   bool
+	NonFinitePolicy   NonFinitePolicy
+	SSTCacheSize      int
+	Logger            Logger
+	ForceEditObjects  bool
+	AutoFitRowHeight  bool
+	ImageFetcher      func(source string) ([]byte, error)
+	Deterministic     bool
+	IgnoreLockFile    bool
 }
 
 // OpenFile take the name of a spreadsheet file and returns a populated
@@ -136,6 +245,7 @@ func newFile() *File {
 		options:          &Options{UnzipSizeLimit: UnzipSizeLimit, UnzipXMLSizeLimit: StreamChunkSize},
 		xmlAttr:          sync.Map{},
 		checked:          sync.Map{},
+		functions:        sync.Map{},
 		sheetMap:         make(map[string]string),
 		tempFiles:        sync.Map{},
 		Comments:         make(map[string]*xlsxComments),
@@ -202,6 +312,10 @@ func OpenReader(r io.Reader, opts ...Options) (*File, error) {
 	for k, v := range file {
 		f.Pkg.Store(k, v)
 	}
+	if _, ok := f.tempFiles.Load(defaultXMLPathSharedStrings); ok && f.options.SSTCacheSize > 0 {
+		f.sharedStringCache = newLRUCache(f.options.SSTCacheSize)
+		_ = f.getFromStringItem(0)
+	}
 	if f.CalcChain, err = f.calcChainReader(); err != nil {
 		return f, err
 	}
@@ -211,8 +325,10 @@ func OpenReader(r io.Reader, opts ...Options) (*File, error) {
 	if f.Styles, err = f.stylesReader(); err != nil {
 		return f, err
 	}
-	f.Theme, err = f.themeReader()
-	return f, err
+	if f.Theme, err = f.themeReader(); err != nil {
+		return f, err
+	}
+	return f, f.logPreservedFeatures()
 }
 
 // getOptions provides a function to parse the optional settings for open
@@ -276,69 +392,80 @@ func (f *File) workSheetReader(sheet string) (ws *xlsxWorksheet, err error) {
 		}
 	}
 	ws = new(xlsxWorksheet)
+	start, content := time.Now(), f.readBytes(name)
 	if attrs, ok := f.xmlAttr.Load(name); !ok {
-		d := f.xmlNewDecoder(bytes.NewReader(namespaceStrictToTransitional(f.readBytes(name))))
+		d := f.xmlNewDecoder(bytes.NewReader(namespaceStrictToTransitional(content)))
 		if attrs == nil {
 			attrs = []xml.Attr{}
 		}
 		attrs = append(attrs.([]xml.Attr), getRootElement(d)...)
 		f.xmlAttr.Store(name, attrs)
 	}
-	if err = f.xmlNewDecoder(bytes.NewReader(namespaceStrictToTransitional(f.readBytes(name)))).
+	if err = f.xmlNewDecoder(bytes.NewReader(namespaceStrictToTransitional(content))).
 		Decode(ws); err != nil && err != io.EOF {
 		return
 	}
 	err = nil
 	if _, ok = f.checked.Load(name); !ok {
-		ws.checkSheet()
-		if err = ws.checkRow(); err != nil {
-			return
+		sheetRepaired := ws.checkSheet()
+		rowRepaired, err := ws.checkRow()
+		if err != nil {
+			return ws, err
+		}
+		if sheetRepaired || rowRepaired {
+			f.logEvent(LogEvent{
+				Type: LogEventRepairPerformed, Part: name,
+				Message: "sorted and merged the out of order or duplicated rows and cells",
+			})
 		}
 		f.checked.Store(name, true)
 	}
+	f.logPartParsed(name, len(content), start)
 	f.Sheet.Store(name, ws)
 	return
 }
 
 // checkSheet provides a function to fill each row element and make that is
-// continuous in a worksheet of XML.
-func (ws *xlsxWorksheet) checkSheet() {
-	var row int
-	var r0 xlsxRow
+// continuous in a worksheet of XML. The out of order rows will be sorted, and
+// the duplicated rows will be merged. This function returns true if the
+// worksheet has been repaired.
+func (ws *xlsxWorksheet) checkSheet() bool {
+	var (
+		row, maxRow int
+		r0          xlsxRow
+		repaired    bool
+	)
+	if len(ws.SheetData.Row) > 0 && ws.SheetData.Row[0].R == 0 {
+		r0, ws.SheetData.Row = ws.SheetData.Row[0], ws.SheetData.Row[1:]
+		repaired = len(r0.C) > 0
+	}
+	rowNums := make([]int, len(ws.SheetData.Row))
 	for i, r := range ws.SheetData.Row {
-		if i == 0 && r.R == 0 {
-			r0 = r
-			ws.SheetData.Row = ws.SheetData.Row[1:]
-			continue
-		}
-		if r.R != 0 && r.R > row {
+		if row++; r.R > 0 {
+			if r.R < row {
+				repaired = true
+			}
 			row = r.R
-			continue
 		}
-		if r.R != row {
-			row++
+		if rowNums[i] = row; row > maxRow {
+			maxRow = row
 		}
 	}
-	sheetData := xlsxSheetData{Row: make([]xlsxRow, row)}
-	row = 0
-	for _, r := range ws.SheetData.Row {
-		if r.R == row && row > 0 {
-			sheetData.Row[r.R-1].C = append(sheetData.Row[r.R-1].C, r.C...)
-			continue
-		}
-		if r.R != 0 {
-			sheetData.Row[r.R-1] = r
-			row = r.R
+	sheetData := xlsxSheetData{Row: make([]xlsxRow, maxRow)}
+	for i, r := range ws.SheetData.Row {
+		rowIdx := rowNums[i] - 1
+		if sheetData.Row[rowIdx].R != 0 {
+			sheetData.Row[rowIdx].C = append(sheetData.Row[rowIdx].C, r.C...)
 			continue
 		}
-		row++
-		r.R = row
-		sheetData.Row[row-1] = r
+		r.R = rowIdx + 1
+		sheetData.Row[rowIdx] = r
 	}
-	for i := 1; i <= row; i++ {
-		sheetData.Row[i-1].R = i
+	for i := range sheetData.Row {
+		sheetData.Row[i].R = i + 1
 	}
 	ws.checkSheetR0(&sheetData, &r0)
+	return repaired
 }
 
 // checkSheetR0 handle the row element with r="0" attribute, cells in this row
@@ -563,3 +690,457 @@ func (f *File) setContentTypePartProjectExtensions(contentType string) error {
 	}
 	return err
 }
+
+// ActiveContentOptions directly maps the settings of the active content to be
+// kept when stripping the active content from the workbook. All types of the
+// active content will be removed by default.
+type ActiveContentOptions struct {
+	KeepVBAProject    bool
+	KeepMacroSheets   bool
+	KeepOLEObjects    bool
+	KeepExternalLinks bool
+}
+
+// StripActiveContent provides a function to remove the active content from the
+// workbook for sanitizing the untrusted spreadsheet, including the VBA
+// project, Excel 4.0 (XLM) macro sheets, embedded OLE objects, ActiveX
+// controls and suspicious external links. The DDE links, OLE links and
+// external workbook links which target to a remote location are considered
+// suspicious, the formulas and defined names referencing these links will be
+// removed, and the cached values of the cells will be kept. The content type
+// of the macro-enabled workbook will be converted to the macro-free one,
+// please save the workbook with XLSX or XLTX extension after stripping. Use
+// the optional ActiveContentOptions to keep specified types of the active
+// content. For example, remove all active content and save as XLSX:
+//
+//	f, err := excelize.OpenFile("Book1.xlsm")
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	if err := f.StripActiveContent(); err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	if err := f.SaveAs("Book1.xlsx"); err != nil {
+//	    fmt.Println(err)
+//	}
+func (f *File) StripActiveContent(opts ...ActiveContentOptions) error {
+	var options ActiveContentOptions
+	for _, opt := range opts {
+		options = opt
+	}
+	if !options.KeepVBAProject {
+		if err := f.stripVBAProject(); err != nil {
+			return err
+		}
+	}
+	if !options.KeepMacroSheets {
+		if err := f.stripMacroSheets(); err != nil {
+			return err
+		}
+	}
+	if !options.KeepOLEObjects {
+		if err := f.stripOLEObjects(); err != nil {
+			return err
+		}
+	}
+	if options.KeepExternalLinks {
+		return nil
+	}
+	return f.stripExternalLinks()
+}
+
+// stripVBAProject provides a function to remove the VBA project and the
+// digital signatures of it from the workbook, and convert the content type of
+// the workbook to macro-free.
+func (f *File) stripVBAProject() error {
+	wbPath, relsPath := f.getWorkbookPath(), f.getWorkbookRelsPath()
+	rels, err := f.relsReader(relsPath)
+	if err != nil {
+		return err
+	}
+	var parts []string
+	if rels != nil {
+		rels.mu.Lock()
+		for i := 0; i < len(rels.Relationships); i++ {
+			if rel := rels.Relationships[i]; rel.Type == SourceRelationshipVBAProject {
+				parts = append(parts, getRelsTargetPath(wbPath, rel.Target))
+				rels.Relationships = append(rels.Relationships[:i], rels.Relationships[i+1:]...)
+				i--
+			}
+		}
+		rels.mu.Unlock()
+	}
+	for _, partName := range parts {
+		// Remove the digital signatures of the VBA project
+		signatures, err := f.relsReader(getPartRelsPath(partName))
+		if err != nil {
+			return err
+		}
+		if signatures != nil {
+			for _, rel := range signatures.Relationships {
+				if err = f.deletePart(getRelsTargetPath(partName, rel.Target)); err != nil {
+					return err
+				}
+			}
+		}
+		if err = f.deletePart(partName); err != nil {
+			return err
+		}
+	}
+	content, err := f.contentTypesReader()
+	if err != nil {
+		return err
+	}
+	content.mu.Lock()
+	defer content.mu.Unlock()
+	for i := 0; i < len(content.Defaults); i++ {
+		if content.Defaults[i].ContentType == ContentTypeVBA {
+			content.Defaults = append(content.Defaults[:i], content.Defaults[i+1:]...)
+			i--
+		}
+	}
+	macroFree := map[string]string{
+		ContentTypeAddinMacro:    ContentTypeSheetML,
+		ContentTypeMacro:         ContentTypeSheetML,
+		ContentTypeTemplateMacro: ContentTypeTemplate,
+	}
+	for i, o := range content.Overrides {
+		if contentType, ok := macroFree[o.ContentType]; ok && o.PartName == "/"+wbPath {
+			content.Overrides[i].ContentType = contentType
+		}
+	}
+	return err
+}
+
+// stripMacroSheets provides a function to remove the Excel 4.0 (XLM) macro
+// sheets and the defined names referencing them from the workbook.
+func (f *File) stripMacroSheets() error {
+	wb, err := f.workbookReader()
+	if err != nil {
+		return err
+	}
+	rels, err := f.relsReader(f.getWorkbookRelsPath())
+	if err != nil || rels == nil {
+		return err
+	}
+	activeSheetName := f.GetSheetName(f.GetActiveSheetIndex())
+	for idx := 0; idx < len(wb.Sheets.Sheet); idx++ {
+		sheet := wb.Sheets.Sheet[idx]
+		target, ok := "", false
+		rels.mu.Lock()
+		for i, rel := range rels.Relationships {
+			if rel.ID == sheet.ID && (rel.Type == SourceRelationshipMacrosheet || rel.Type == SourceRelationshipIntlMacrosheet) {
+				target, ok = rel.Target, true
+				rels.Relationships = append(rels.Relationships[:i], rels.Relationships[i+1:]...)
+				break
+			}
+		}
+		rels.mu.Unlock()
+		if !ok {
+			continue
+		}
+		deleteAndAdjustDefinedNames(wb, idx)
+		if wb.DefinedNames != nil {
+			refs := []string{sheet.Name + "!", "'" + strings.ReplaceAll(sheet.Name, "'", "''") + "'!"}
+			for i := 0; i < len(wb.DefinedNames.DefinedName); i++ {
+				for _, ref := range refs {
+					if strings.Contains(wb.DefinedNames.DefinedName[i].Data, ref) {
+						wb.DefinedNames.DefinedName = append(wb.DefinedNames.DefinedName[:i], wb.DefinedNames.DefinedName[i+1:]...)
+						i--
+						break
+					}
+				}
+			}
+		}
+		sheetXMLPath := f.getWorksheetPath(target)
+		if err = f.deletePart(sheetXMLPath); err != nil {
+			return err
+		}
+		delete(f.sheetMap, sheet.Name)
+		f.Sheet.Delete(sheetXMLPath)
+		f.xmlAttr.Delete(sheetXMLPath)
+		f.checked.Delete(sheetXMLPath)
+		wb.Sheets.Sheet = append(wb.Sheets.Sheet[:idx], wb.Sheets.Sheet[idx+1:]...)
+		idx--
+	}
+	index, err := f.GetSheetIndex(activeSheetName)
+	f.SetActiveSheet(index)
+	return err
+}
+
+// stripOLEObjects provides a function to remove the embedded OLE objects and
+// ActiveX controls from the worksheets.
+func (f *File) stripOLEObjects() error {
+	for _, sheet := range f.GetSheetList() {
+		ws, err := f.workSheetReader(sheet)
+		if err != nil {
+			if err.Error() == newNotWorksheetError(sheet).Error() {
+				continue
+			}
+			return err
+		}
+		ws.mu.Lock()
+		ws.OleObjects, ws.Controls = nil, nil
+		var contents []string
+		if ws.DecodeAlternateContent != nil {
+			contents = append(contents, ws.DecodeAlternateContent.Content)
+		}
+		if ws.AlternateContent != nil {
+			contents = append(contents, ws.AlternateContent.Content)
+		}
+		for _, content := range contents {
+			if embeddedObjectExp.MatchString(content) {
+				ws.DecodeAlternateContent, ws.AlternateContent = nil, nil
+			}
+		}
+		ws.mu.Unlock()
+		sheetXMLPath, _ := f.getSheetXMLPath(sheet)
+		rels, err := f.relsReader(getPartRelsPath(sheetXMLPath))
+		if err != nil {
+			return err
+		}
+		if rels == nil {
+			continue
+		}
+		var parts []string
+		rels.mu.Lock()
+		for i := 0; i < len(rels.Relationships); i++ {
+			switch rel := rels.Relationships[i]; rel.Type {
+			case SourceRelationshipOLEObject, SourceRelationshipPackage, SourceRelationshipControl:
+				if rel.TargetMode != "External" {
+					parts = append(parts, getRelsTargetPath(sheetXMLPath, rel.Target))
+				}
+				rels.Relationships = append(rels.Relationships[:i], rels.Relationships[i+1:]...)
+				i--
+			}
+		}
+		rels.mu.Unlock()
+		for _, partName := range parts {
+			// Remove the binary data of the ActiveX control
+			controlRels, err := f.relsReader(getPartRelsPath(partName))
+			if err != nil {
+				return err
+			}
+			if controlRels != nil {
+				for _, rel := range controlRels.Relationships {
+					if err = f.deletePart(getRelsTargetPath(partName, rel.Target)); err != nil {
+						return err
+					}
+				}
+			}
+			if err = f.deletePart(partName); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// adjustFormulaExternalLinks provides a function to update the external link
+// indexes of the reference operands in the formula by given indexes map, in
+// which the new index 0 means the external link was removed. Only the
+// reference operands which begin with the external link index followed by
+// the sheet names and the exclamation mark will be updated, so that the
+// structured references and the text in the formula will not be changed. The
+// formula will be returned as-is if nothing changed. Returns the adjusted
+// formula and false if the formula references the removed external link.
+func adjustFormulaExternalLinks(formula string, indexes map[int]int) (string, bool) {
+	var (
+		val     string
+		changed bool
+		ps      = efp.ExcelParser()
+	)
+	for _, token := range ps.Parse(formula) {
+		isRange := token.TType == efp.TokenTypeOperand && token.TSubType == efp.TokenSubTypeRange
+		if isRange || token.TType == efp.TokenTypeUnknown {
+			if matches := externalLinkIndexExp.FindStringSubmatch(token.TValue); len(matches) > 2 {
+				idx, _ := strconv.Atoi(matches[1])
+				if newIdx, ok := indexes[idx]; ok {
+					if newIdx == 0 {
+						return formula, false
+					}
+					prefix := "[" + strconv.Itoa(newIdx) + "]" + matches[2]
+					for _, name := range strings.Split(matches[2], ":") {
+						if name != "" && escapeSheetName(name) != name {
+							prefix = "'" + strings.ReplaceAll(prefix, "'", "''") + "'"
+							break
+						}
+					}
+					val, changed = val+prefix+token.TValue[len(matches[0])-1:], true
+					continue
+				}
+			}
+			if token.TType == efp.TokenTypeUnknown {
+				return formula, true
+			}
+			if idx := strings.LastIndex(token.TValue, "!"); idx != -1 && !strings.ContainsAny(token.TValue, "[]") {
+				val += escapeSheetName(token.TValue[:idx]) + token.TValue[idx:]
+				continue
+			}
+		}
+		if isFunctionStart(token) {
+			val += token.TValue + string(efp.ParenOpen)
+			continue
+		}
+		if isFunctionStop(token) {
+			val += token.TValue + string(efp.ParenClose)
+			continue
+		}
+		if token.TType == efp.TokenTypeOperand && token.TSubType == efp.TokenSubTypeText {
+			val += string(efp.QuoteDouble) + strings.ReplaceAll(token.TValue, "\"", "\"\"") + string(efp.QuoteDouble)
+			continue
+		}
+		val += token.TValue
+	}
+	if !changed {
+		return formula, true
+	}
+	return val, true
+}
+
+// isRemoteTarget provides a function to check if the given external
+// relationship target is a remote location, such as a URL or UNC path.
+func isRemoteTarget(target string) bool {
+	target = strings.ToLower(strings.ReplaceAll(target, "\\", "/"))
+	if strings.HasPrefix(target, "//") {
+		return true
+	}
+	if idx := strings.Index(target, "://"); idx > 0 {
+		return !strings.HasPrefix(target, "file:///")
+	}
+	return false
+}
+
+// stripExternalLinks provides a function to remove the DDE links, OLE links and
+// the external workbook links which target to a remote location, and remove
+// the formulas and defined names referencing these links.
+func (f *File) stripExternalLinks() error {
+	wb, err := f.workbookReader()
+	if err != nil || wb.ExternalReferences == nil {
+		return err
+	}
+	wbPath := f.getWorkbookPath()
+	rels, err := f.relsReader(f.getWorkbookRelsPath())
+	if err != nil || rels == nil {
+		return err
+	}
+	var refs []xlsxExternalReference
+	indexes := map[int]int{}
+	for idx, ref := range wb.ExternalReferences.ExternalReference {
+		var target string
+		rels.mu.Lock()
+		for _, rel := range rels.Relationships {
+			if rel.ID == ref.RID && rel.Type == SourceRelationshipExternalLink {
+				target = getRelsTargetPath(wbPath, rel.Target)
+			}
+		}
+		rels.mu.Unlock()
+		suspicious, err := f.isSuspiciousExternalLink(target)
+		if err != nil {
+			return err
+		}
+		if !suspicious {
+			refs = append(refs, ref)
+			indexes[idx+1] = len(refs)
+			continue
+		}
+		indexes[idx+1] = 0
+		rels.mu.Lock()
+		for i, rel := range rels.Relationships {
+			if rel.ID == ref.RID {
+				rels.Relationships = append(rels.Relationships[:i], rels.Relationships[i+1:]...)
+				break
+			}
+		}
+		rels.mu.Unlock()
+		if target == "" {
+			continue
+		}
+		if err = f.deletePart(target); err != nil {
+			return err
+		}
+	}
+	if len(refs) == len(wb.ExternalReferences.ExternalReference) {
+		return err
+	}
+	if wb.ExternalReferences.ExternalReference = refs; len(refs) == 0 {
+		wb.ExternalReferences = nil
+	}
+	return f.adjustExternalLinkIndexes(wb, indexes)
+}
+
+// isSuspiciousExternalLink provides a function to check if the external link
+// part is a DDE link, OLE link or an external workbook link which target to a
+// remote location by given part name.
+func (f *File) isSuspiciousExternalLink(partName string) (bool, error) {
+	if partName == "" {
+		return true, nil
+	}
+	var link xlsxExternalLink
+	if err := f.xmlNewDecoder(bytes.NewReader(namespaceStrictToTransitional(f.readXML(partName)))).
+		Decode(&link); err != nil && err != io.EOF {
+		return false, err
+	}
+	if link.DDELink != nil || link.OLELink != nil {
+		return true, nil
+	}
+	if link.ExternalBook == nil {
+		return false, nil
+	}
+	rels, err := f.relsReader(getPartRelsPath(partName))
+	if err != nil || rels == nil {
+		return false, err
+	}
+	for _, rel := range rels.Relationships {
+		if rel.ID == link.ExternalBook.RID && isRemoteTarget(rel.Target) {
+			return true, nil
+		}
+	}
+	return false, err
+}
+
+// adjustExternalLinkIndexes provides a function to update the external link
+// indexes in the formulas and defined names by given indexes map, in which
+// the new index 0 means the external link was removed. The formula or defined
+// name referencing the removed external link will be deleted.
+func (f *File) adjustExternalLinkIndexes(wb *xlsxWorkbook, indexes map[int]int) error {
+	if wb.DefinedNames != nil {
+		for i := 0; i < len(wb.DefinedNames.DefinedName); i++ {
+			data, ok := adjustFormulaExternalLinks(wb.DefinedNames.DefinedName[i].Data, indexes)
+			if !ok {
+				wb.DefinedNames.DefinedName = append(wb.DefinedNames.DefinedName[:i], wb.DefinedNames.DefinedName[i+1:]...)
+				i--
+				continue
+			}
+			wb.DefinedNames.DefinedName[i].Data = data
+		}
+	}
+	for _, sheet := range f.GetSheetList() {
+		ws, err := f.workSheetReader(sheet)
+		if err != nil {
+			if err.Error() == newNotWorksheetError(sheet).Error() {
+				continue
+			}
+			return err
+		}
+		for rowIdx := range ws.SheetData.Row {
+			for colIdx := range ws.SheetData.Row[rowIdx].C {
+				c := &ws.SheetData.Row[rowIdx].C[colIdx]
+				if c.F == nil {
+					continue
+				}
+				content, ok := adjustFormulaExternalLinks(c.F.Content, indexes)
+				if !ok {
+					if err = f.removeFormula(c, ws, sheet); err != nil {
+						return err
+					}
+					continue
+				}
+				c.F.Content = content
+			}
+		}
+	}
+	return nil
+}
@@ -14,6 +14,7 @@ import (
 	"math"
 	"os"
 	"path/filepath"
+	"runtime"
 	"strconv"
 	"strings"
 	"sync"
@@ -218,11 +219,181 @@ func TestSaveAsWrongPath(t *testing.T) {
 	assert.NoError(t, f.Close())
 }
 
+func TestSaveAsLocked(t *testing.T) {
+	f := NewFile()
+	path := filepath.Join("test", "TestSaveAsLocked.xlsx")
+	assert.NoError(t, f.SaveAs(path))
+	// Test save the workbook which is opened by Microsoft Excel or LibreOffice
+	for _, lockFile := range []string{"~$TestSaveAsLocked.xlsx", ".~lock.TestSaveAsLocked.xlsx#"} {
+		lockPath := filepath.Join("test", lockFile)
+		assert.NoError(t, os.WriteFile(lockPath, nil, 0o644))
+		assert.NoError(t, f.SetCellValue("Sheet1", "A1", lockFile))
+		assert.Equal(t, ErrWorkbookLocked, f.SaveAs(path))
+		assert.NoError(t, os.Remove(lockPath))
+	}
+	// Test save the workbook with the stale lock file
+	lockPath := filepath.Join("test", "~$TestSaveAsLocked.xlsx")
+	assert.NoError(t, os.WriteFile(lockPath, nil, 0o644))
+	assert.NoError(t, f.SaveAs(path, Options{IgnoreLockFile: true}))
+	assert.NoError(t, os.Remove(lockPath))
+	assert.NoError(t, f.SaveAs(path))
+	assert.NoError(t, f.Close())
+	f, err := OpenFile(path)
+	assert.NoError(t, err)
+	val, err := f.GetCellValue("Sheet1", "A1")
+	assert.NoError(t, err)
+	assert.Equal(t, ".~lock.TestSaveAsLocked.xlsx#", val)
+	assert.NoError(t, f.Close())
+}
+
+func TestWriteToBufferConcurrency(t *testing.T) {
+	f := NewFile()
+	// Test set the string cell values and write the workbook concurrently
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		wg := new(sync.WaitGroup)
+		for i := 1; i <= 10; i++ {
+			wg.Add(2)
+			go func(i int) {
+				defer wg.Done()
+				for row := 1; row <= 500; row++ {
+					assert.NoError(t, f.SetCellValue("Sheet1", fmt.Sprintf("A%d", row), fmt.Sprintf("value %d-%d", i, row)))
+				}
+			}(i)
+			go func() {
+				defer wg.Done()
+				for j := 0; j < 20; j++ {
+					_, err := f.WriteToBuffer()
+					assert.NoError(t, err)
+				}
+			}()
+		}
+		wg.Wait()
+	}()
+	select {
+	case <-done:
+	case <-time.After(30 * time.Second):
+		t.Fatal("set cell values and write the workbook concurrently deadlocked")
+	}
+	buf, err := f.WriteToBuffer()
+	assert.NoError(t, err)
+	assert.NoError(t, f.Close())
+	f, err = OpenReader(buf)
+	assert.NoError(t, err)
+	_, err = f.GetRows("Sheet1")
+	assert.NoError(t, err)
+	assert.NoError(t, f.Close())
+}
+
+func TestSaveAsConcurrency(t *testing.T) {
+	dir := filepath.Join("test", "TestSaveAsConcurrency")
+	assert.NoError(t, os.MkdirAll(dir, os.ModePerm))
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "Book1.xlsx")
+	// Test save the workbooks on the same path concurrently
+	wg := new(sync.WaitGroup)
+	for i := 1; i <= 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			f := NewFile()
+			for row := 1; row <= 100; row++ {
+				assert.NoError(t, f.SetCellValue("Sheet1", fmt.Sprintf("A%d", row), i))
+			}
+			assert.NoError(t, f.SaveAs(path))
+			assert.NoError(t, f.Close())
+		}(i)
+	}
+	wg.Wait()
+	// Test the locks of the spreadsheet paths have been released
+	saveLocks.mu.Lock()
+	assert.Empty(t, saveLocks.locks)
+	saveLocks.mu.Unlock()
+	f, err := OpenFile(path)
+	assert.NoError(t, err)
+	rows, err := f.GetRows("Sheet1")
+	assert.NoError(t, err)
+	assert.Len(t, rows, 100)
+	for _, row := range rows {
+		assert.Equal(t, rows[0], row)
+	}
+	// Test save the workbook failed will keep the existing file
+	f.ContentTypes = nil
+	f.Pkg.Store(defaultXMLPathContentTypes, MacintoshCyrillicCharset)
+	assert.EqualError(t, f.SaveAs(path), "XML syntax error on line 1: invalid UTF-8")
+	assert.NoError(t, f.Close())
+	// Test the temporary files has been removed
+	entries, err := os.ReadDir(dir)
+	assert.NoError(t, err)
+	assert.Len(t, entries, 1)
+	f, err = OpenFile(path)
+	assert.NoError(t, err)
+	rows, err = f.GetRows("Sheet1")
+	assert.NoError(t, err)
+	assert.Len(t, rows, 100)
+	assert.NoError(t, f.Close())
+	// Test save the workbook keeps the permission of the existing file
+	assert.NoError(t, os.Chmod(path, 0o600))
+	f = NewFile()
+	assert.NoError(t, f.SaveAs(path))
+	assert.NoError(t, f.Close())
+	fi, err := os.Stat(path)
+	assert.NoError(t, err)
+	if runtime.GOOS != "windows" {
+		assert.Equal(t, os.FileMode(0o600), fi.Mode().Perm())
+		// Test save the workbook through the symbolic link
+		link := filepath.Join(dir, "Link.xlsx")
+		assert.NoError(t, os.Symlink("Book1.xlsx", link))
+		f = NewFile()
+		assert.NoError(t, f.SetCellValue("Sheet1", "A1", "Link"))
+		assert.NoError(t, f.SaveAs(link))
+		assert.NoError(t, f.Close())
+		fi, err = os.Lstat(link)
+		assert.NoError(t, err)
+		assert.NotZero(t, fi.Mode()&os.ModeSymlink)
+		f, err = OpenFile(path)
+		assert.NoError(t, err)
+		val, err := f.GetCellValue("Sheet1", "A1")
+		assert.NoError(t, err)
+		assert.Equal(t, "Link", val)
+		assert.NoError(t, f.Close())
+	}
+	// Test replace the spreadsheet by not exists temporary file
+	assert.Error(t, renameSaveTempFile(filepath.Join(dir, "not_exists.tmp"), path))
+}
+
 func TestCharsetTranscoder(t *testing.T) {
 	f := NewFile()
 	f.CharsetTranscoder(*new(charsetTranscoderFn))
 }
 
+func TestOpenFileWithSSTCacheSize(t *testing.T) {
+	f, err := OpenFile(filepath.Join("test", "Book1.xlsx"))
+	assert.NoError(t, err)
+	expected, err := f.GetRows("Sheet1")
+	assert.NoError(t, err)
+	assert.NoError(t, f.Close())
+
+	f, err = OpenFile(filepath.Join("test", "Book1.xlsx"), Options{SSTCacheSize: 2})
+	assert.NoError(t, err)
+	_, ok := f.tempFiles.Load(defaultXMLPathSharedStrings)
+	assert.True(t, ok)
+	assert.NotNil(t, f.sharedStringCache)
+	assert.NotEmpty(t, f.sharedStringItem)
+	rows, err := f.GetRows("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, expected, rows)
+	assert.Equal(t, 2, f.sharedStringCache.order.Len())
+	// Test set cell value after the shared strings table loaded into memory
+	assert.NoError(t, f.SetCellValue("Sheet1", "A1", "A1"))
+	assert.Nil(t, f.sharedStringCache)
+	value, err := f.GetCellValue("Sheet1", "A1")
+	assert.NoError(t, err)
+	assert.Equal(t, "A1", value)
+	assert.NoError(t, f.Close())
+}
+
 func TestOpenReader(t *testing.T) {
 	_, err := OpenReader(strings.NewReader(""))
 	assert.EqualError(t, err, zip.ErrFormat.Error())
@@ -431,6 +602,53 @@ func TestSetCellHyperLink(t *testing.T) {
 	assert.Equal(t, link, true)
 	assert.Equal(t, "https://github.com/xuri/excelize", target)
 	assert.NoError(t, err)
+
+	// Test set cell hyperlink with the built-in hyperlink cell style
+	f = NewFile()
+	centerStyle, err := f.NewStyle(&Style{Alignment: &Alignment{Horizontal: "center"}})
+	assert.NoError(t, err)
+	assert.NoError(t, f.SetCellStyle("Sheet1", "B1", "B1", centerStyle))
+	for _, cell := range []string{"A1", "A2", "B1"} {
+		assert.NoError(t, f.SetCellHyperLink("Sheet1", cell, "https://github.com/xuri/excelize", "External", HyperlinkOpts{ApplyStyle: true}))
+	}
+	styleID, err := f.GetCellStyle("Sheet1", "A1")
+	assert.NoError(t, err)
+	linkStyleID, err := f.GetCellStyle("Sheet1", "A2")
+	assert.NoError(t, err)
+	assert.Equal(t, styleID, linkStyleID)
+	style, err := f.GetStyle(styleID)
+	assert.NoError(t, err)
+	assert.Equal(t, "single", style.Font.Underline)
+	styleID, err = f.GetCellStyle("Sheet1", "B1")
+	assert.NoError(t, err)
+	assert.NotEqual(t, linkStyleID, styleID)
+	style, err = f.GetStyle(styleID)
+	assert.NoError(t, err)
+	assert.Equal(t, "single", style.Font.Underline)
+	assert.Equal(t, "center", style.Alignment.Horizontal)
+	var names []string
+	for _, cellStyle := range f.Styles.CellStyles.CellStyle {
+		names = append(names, cellStyle.Name)
+	}
+	assert.Equal(t, []string{"Normal", "Hyperlink", "Followed Hyperlink"}, names)
+	assert.Equal(t, 1, *f.Styles.CellXfs.Xf[linkStyleID].XfID)
+	assert.Equal(t, 10, *f.Styles.Fonts.Font[*f.Styles.CellStyleXfs.Xf[1].FontID].Color.Theme)
+	assert.Equal(t, 11, *f.Styles.Fonts.Font[*f.Styles.CellStyleXfs.Xf[2].FontID].Color.Theme)
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestSetCellHyperLinkStyle.xlsx")))
+	// Test set cell hyperlink with the built-in hyperlink cell style without cell styles
+	f = NewFile()
+	f.Styles.CellStyles, f.Styles.CellStyleXfs = nil, nil
+	assert.NoError(t, f.SetCellHyperLink("Sheet1", "A1", "Sheet1!A2", "Location", HyperlinkOpts{ApplyStyle: true}))
+	assert.Equal(t, 2, f.Styles.CellStyles.Count)
+	// Test set cell hyperlink with the built-in hyperlink cell style exceeds the cell styles limit
+	f = NewFile()
+	f.Styles.CellXfs.Xf = make([]xlsxXf, MaxCellStyles)
+	assert.Equal(t, ErrCellStyles, f.SetCellHyperLink("Sheet1", "A1", "Sheet1!A2", "Location", HyperlinkOpts{ApplyStyle: true}))
+	// Test set cell hyperlink with the built-in hyperlink cell style with unsupported charset style sheet
+	f = NewFile()
+	f.Styles = nil
+	f.Pkg.Store(defaultXMLPathStyles, MacintoshCyrillicCharset)
+	assert.EqualError(t, f.SetCellHyperLink("Sheet1", "A1", "Sheet1!A2", "Location", HyperlinkOpts{ApplyStyle: true}), "XML syntax error on line 1: invalid UTF-8")
 }
 
 func TestGetCellHyperLink(t *testing.T) {
@@ -481,6 +699,21 @@ func TestGetCellHyperLink(t *testing.T) {
 	// Test get cell hyperlink with invalid sheet name
 	_, _, err = f.GetCellHyperLink("Sheet:1", "A1")
 	assert.EqualError(t, err, ErrSheetNameInvalid.Error())
+
+	// Test get cell hyperlink defined over a cell range or a merged cell
+	f = NewFile()
+	ws, ok = f.Sheet.Load("xl/worksheets/sheet1.xml")
+	assert.True(t, ok)
+	ws.(*xlsxWorksheet).Hyperlinks = &xlsxHyperlinks{
+		Hyperlink: []xlsxHyperlink{{Ref: "B2:C3", Location: "Sheet1!A1"}, {Ref: "D4", Location: "Sheet1!A2"}},
+	}
+	assert.NoError(t, f.MergeCell("Sheet1", "D4", "E5"))
+	for cell, expected := range map[string]string{"B2": "Sheet1!A1", "C3": "Sheet1!A1", "D4": "Sheet1!A2", "e5": "Sheet1!A2", "F6": ""} {
+		link, target, err = f.GetCellHyperLink("Sheet1", cell)
+		assert.NoError(t, err)
+		assert.Equal(t, expected != "", link, cell)
+		assert.Equal(t, expected, target, cell)
+	}
 }
 
 func TestSetSheetBackground(t *testing.T) {
@@ -1386,6 +1619,53 @@ func TestProtectSheet(t *testing.T) {
 	assert.EqualError(t, f.ProtectSheet("Sheet:1", nil), ErrSheetNameInvalid.Error())
 }
 
+func TestProtectSheetObjects(t *testing.T) {
+	f := NewFile()
+	pic, err := f.AddPictureObject("Sheet1", "B2", filepath.Join("test", "images", "excel.jpg"), nil)
+	assert.NoError(t, err)
+	assert.NoError(t, f.AddComment("Sheet1", Comment{Cell: "A1", Author: "Excelize", Text: "comment"}))
+	assert.NoError(t, f.ProtectSheet("Sheet1", &SheetProtectionOptions{}))
+	// Test add, move or delete objects on the worksheet with objects protected
+	err = f.AddPicture("Sheet1", "B20", filepath.Join("test", "images", "excel.jpg"), nil)
+	assert.Equal(t, ErrProtectedObjects, err)
+	err = f.AddShape("Sheet1", &Shape{Cell: "B30", Type: "rect"})
+	assert.Equal(t, ErrProtectedObjects, err)
+	err = f.AddChart("Sheet1", "E1", &Chart{Type: Col, Series: []ChartSeries{{Values: "Sheet1!$A$1:$A$2"}}})
+	assert.Equal(t, ErrProtectedObjects, err)
+	_, err = f.AddChartEx("Sheet1", "E1", &ChartEx{Type: Funnel, Series: []ChartExSeries{{Values: "Sheet1!$A$1:$A$2"}}})
+	assert.Equal(t, ErrProtectedObjects, err)
+	assert.Equal(t, ErrProtectedObjects, f.AddComment("Sheet1", Comment{Cell: "A2", Text: "comment"}))
+	assert.Equal(t, ErrProtectedObjects, f.AddFormControl("Sheet1", FormControl{Cell: "A3", Type: FormControlButton}))
+	assert.Equal(t, ErrProtectedObjects, f.DeleteComment("Sheet1", "A1"))
+	assert.Equal(t, ErrProtectedObjects, f.DeleteFormControl("Sheet1", "A3"))
+	assert.Equal(t, ErrProtectedObjects, f.DeletePicture("Sheet1", "B2"))
+	assert.Equal(t, ErrProtectedObjects, f.DeleteChart("Sheet1", "E1"))
+	assert.Equal(t, ErrProtectedObjects, f.MoveDrawingObject(pic, "D2"))
+	assert.Equal(t, ErrProtectedObjects, f.DeleteDrawingObject(pic))
+	comments, err := f.GetComments("Sheet1")
+	assert.NoError(t, err)
+	assert.Len(t, comments, 1)
+
+	// Test add, move or delete objects on the worksheet with objects editable
+	assert.NoError(t, f.ProtectSheet("Sheet1", &SheetProtectionOptions{EditObjects: true, EditScenarios: true}))
+	ws, ok := f.Sheet.Load("xl/worksheets/sheet1.xml")
+	assert.True(t, ok)
+	assert.False(t, ws.(*xlsxWorksheet).SheetProtection.Objects)
+	assert.False(t, ws.(*xlsxWorksheet).SheetProtection.Scenarios)
+	assert.NoError(t, f.AddComment("Sheet1", Comment{Cell: "A2", Text: "comment"}))
+	assert.NoError(t, f.MoveDrawingObject(pic, "D2"))
+
+	// Test add, move or delete objects on the protected worksheet by force
+	assert.NoError(t, f.ProtectSheet("Sheet1", &SheetProtectionOptions{}))
+	f.options.ForceEditObjects = true
+	assert.NoError(t, f.DeleteComment("Sheet1", "A2"))
+	assert.NoError(t, f.DeleteDrawingObject(pic))
+	assert.NoError(t, f.Close())
+
+	// Test delete comment on not exists worksheet
+	assert.EqualError(t, f.DeleteComment("SheetN", "A1"), "sheet SheetN does not exist")
+}
+
 func TestUnprotectSheet(t *testing.T) {
 	f, err := OpenFile(filepath.Join("test", "Book1.xlsx"))
 	assert.NoError(t, err)
@@ -1503,6 +1783,183 @@ func TestAddVBAProject(t *testing.T) {
 	assert.EqualError(t, f.AddVBAProject(file), "XML syntax error on line 1: invalid UTF-8")
 }
 
+func TestAdjustFormulaExternalLinks(t *testing.T) {
+	indexes := map[int]int{1: 0, 2: 1}
+	for formula, expected := range map[string][]interface{}{
+		"[2]Sheet1!A1":                      {"[1]Sheet1!A1", true},
+		"SUM([2]Sheet1:Sheet2!A1:A2)":       {"SUM([1]Sheet1:Sheet2!A1:A2)", true},
+		"'[2]My Sheet'!A1&\"[2]\"":          {"'[1]My Sheet'!A1&\"[2]\"", true},
+		"[2]!Name+'My Sheet'!A1":            {"[1]!Name+'My Sheet'!A1", true},
+		"[1]Sheet1!A1+1":                    {"[1]Sheet1!A1+1", false},
+		"[1]!'!A0'":                         {"[1]!'!A0'", false},
+		"Table1[2]+Table1[[#This Row],[1]]": {"Table1[2]+Table1[[#This Row],[1]]", true},
+		"\"[1]\"&A1":                        {"\"[1]\"&A1", true},
+		"[3]Sheet1!A1":                      {"[3]Sheet1!A1", true},
+	} {
+		result, ok := adjustFormulaExternalLinks(formula, indexes)
+		assert.Equal(t, expected, []interface{}{result, ok}, formula)
+	}
+}
+
+func TestStripActiveContent(t *testing.T) {
+	f := NewFile()
+	file, err := os.ReadFile(filepath.Join("test", "vbaProject.bin"))
+	assert.NoError(t, err)
+	assert.NoError(t, f.AddVBAProject(file))
+	f.Pkg.Store("xl/_rels/vbaProject.bin.rels", []byte(`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.microsoft.com/office/2006/relationships/vbaProjectSignature" Target="vbaProjectSignature.bin"/></Relationships>`))
+	f.Pkg.Store("xl/vbaProjectSignature.bin", []byte{})
+	content, err := f.contentTypesReader()
+	assert.NoError(t, err)
+	for i, o := range content.Overrides {
+		if o.PartName == "/xl/workbook.xml" {
+			content.Overrides[i].ContentType = ContentTypeMacro
+		}
+	}
+	// Prepare a macro sheet with a defined name referencing it
+	_, err = f.NewSheet("Sheet2")
+	assert.NoError(t, err)
+	rID := f.addRels(f.getWorkbookRelsPath(), SourceRelationshipMacrosheet, "macrosheets/sheet1.xml", "")
+	wb, err := f.workbookReader()
+	assert.NoError(t, err)
+	wb.Sheets.Sheet = append(wb.Sheets.Sheet, xlsxSheet{Name: "Macro1", SheetID: 3, ID: "rId" + strconv.Itoa(rID)})
+	f.sheetMap["Macro1"] = "xl/macrosheets/sheet1.xml"
+	f.Pkg.Store("xl/macrosheets/sheet1.xml", []byte(`<xm:macrosheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:xm="http://schemas.microsoft.com/office/excel/2006/main"><sheetData><row r="1"><c r="A1"><f>EXEC("calc.exe")</f></c></row></sheetData></xm:macrosheet>`))
+	content.Overrides = append(content.Overrides, xlsxOverride{PartName: "/xl/macrosheets/sheet1.xml", ContentType: ContentTypeMacrosheet})
+	assert.NoError(t, f.SetDefinedName(&DefinedName{Name: "Auto_Open", RefersTo: "Macro1!$A$1"}))
+	f.SetActiveSheet(1)
+	// Prepare an embedded OLE object and an ActiveX control on the worksheet
+	ws, err := f.workSheetReader("Sheet1")
+	assert.NoError(t, err)
+	ws.OleObjects = &xlsxInnerXML{Content: `<oleObject progId="Package" shapeId="1025" r:id="rId1"/>`}
+	ws.Controls = &xlsxInnerXML{Content: `<control shapeId="1026" r:id="rId2" name="CommandButton1"/>`}
+	f.addRels("xl/worksheets/_rels/sheet1.xml.rels", SourceRelationshipOLEObject, "../embeddings/oleObject1.bin", "")
+	f.addRels("xl/worksheets/_rels/sheet1.xml.rels", SourceRelationshipControl, "../activeX/activeX1.xml", "")
+	f.Pkg.Store("xl/embeddings/oleObject1.bin", []byte{})
+	f.Pkg.Store("xl/activeX/activeX1.xml", []byte{})
+	f.Pkg.Store("xl/activeX/_rels/activeX1.xml.rels", []byte(`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.microsoft.com/office/2006/relationships/activeXControlBinary" Target="activeX1.bin"/></Relationships>`))
+	f.Pkg.Store("xl/activeX/activeX1.bin", []byte{})
+	ws.DecodeAlternateContent = &xlsxInnerXML{Content: `<mc:Choice Requires="x14"><controls><mc:AlternateContent><mc:Choice Requires="x14"><control shapeId="1026" r:id="rId2" name="CommandButton1"/></mc:Choice></mc:AlternateContent></controls></mc:Choice>`}
+	// Prepare an alternate content which only mentions "control" in the formula
+	ws2, err := f.workSheetReader("Sheet2")
+	assert.NoError(t, err)
+	alternateContent := `<mc:Choice Requires="x14"><x14:sparklineGroups><x14:sparklineGroup><x14:sparklines><x14:sparkline><xm:f>control!A1:B1</xm:f><xm:sqref>C1</xm:sqref></x14:sparkline></x14:sparklines></x14:sparklineGroup></x14:sparklineGroups></mc:Choice>`
+	ws2.DecodeAlternateContent = &xlsxInnerXML{Content: alternateContent}
+	// Prepare a DDE link, a remote external workbook link and a local one
+	wb.ExternalReferences = &xlsxExternalReferences{}
+	for i, link := range []string{
+		`<ddeLink ddeService="cmd" ddeTopic="/c calc"/>`,
+		`<externalBook r:id="rId1"/>`,
+		`<externalBook r:id="rId1"/>`,
+	} {
+		name := "externalLinks/externalLink" + strconv.Itoa(i+1) + ".xml"
+		rID := f.addRels(f.getWorkbookRelsPath(), SourceRelationshipExternalLink, name, "")
+		wb.ExternalReferences.ExternalReference = append(wb.ExternalReferences.ExternalReference, xlsxExternalReference{RID: "rId" + strconv.Itoa(rID)})
+		f.Pkg.Store("xl/"+name, []byte(`<externalLink xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">`+link+`</externalLink>`))
+		content.Overrides = append(content.Overrides, xlsxOverride{PartName: "/xl/" + name, ContentType: ContentTypeSpreadSheetMLExternalLink})
+	}
+	f.addRels("xl/externalLinks/_rels/externalLink2.xml.rels", SourceRelationshipExternalLinkPath, `\\attacker\share\Book1.xlsx`, "External")
+	f.addRels("xl/externalLinks/_rels/externalLink3.xml.rels", SourceRelationshipExternalLinkPath, "Book2.xlsx", "External")
+	assert.NoError(t, f.SetCellFormula("Sheet1", "A1", "[1]!'!A0'"))
+	assert.NoError(t, f.SetCellFormula("Sheet1", "A2", "[2]Sheet1!A1"))
+	assert.NoError(t, f.SetCellFormula("Sheet1", "A3", "[3]Sheet1!A1"))
+	assert.NoError(t, f.SetCellFormula("Sheet1", "A4", "SUM(Table1[2])"))
+	assert.NoError(t, f.SetCellFormula("Sheet1", "A5", `"[1]"&B1`))
+	assert.NoError(t, f.SetCellFormula("Sheet1", "A6", "'[3]Other Sheet'!A1+'My Sheet'!A1"))
+	assert.NoError(t, f.SetCellFormula("Sheet2", "A1", "SUM([3]Sheet1!A1:A2)"))
+	assert.NoError(t, f.SetDefinedName(&DefinedName{Name: "Remote", RefersTo: "[2]Sheet1!$A$1"}))
+	assert.NoError(t, f.SetDefinedName(&DefinedName{Name: "Local", RefersTo: "[3]Sheet1!$A$1"}))
+
+	assert.NoError(t, f.StripActiveContent())
+	for _, partName := range []string{
+		"xl/vbaProject.bin", "xl/vbaProjectSignature.bin", "xl/macrosheets/sheet1.xml",
+		"xl/embeddings/oleObject1.bin", "xl/activeX/activeX1.xml", "xl/activeX/activeX1.bin",
+		"xl/externalLinks/externalLink1.xml", "xl/externalLinks/externalLink2.xml",
+	} {
+		_, ok := f.Pkg.Load(partName)
+		assert.False(t, ok, partName)
+	}
+	_, ok := f.Pkg.Load("xl/externalLinks/externalLink3.xml")
+	assert.True(t, ok)
+	for _, o := range content.Overrides {
+		if o.PartName == "/xl/workbook.xml" {
+			assert.Equal(t, ContentTypeSheetML, o.ContentType)
+		}
+	}
+	for _, d := range content.Defaults {
+		assert.NotEqual(t, ContentTypeVBA, d.ContentType)
+	}
+	assert.Equal(t, []string{"Sheet1", "Sheet2"}, f.GetSheetList())
+	assert.Equal(t, 1, f.GetActiveSheetIndex())
+	assert.Nil(t, ws.OleObjects)
+	assert.Nil(t, ws.Controls)
+	assert.Nil(t, ws.DecodeAlternateContent)
+	assert.Equal(t, &xlsxInnerXML{Content: alternateContent}, ws2.DecodeAlternateContent)
+	assert.Len(t, wb.ExternalReferences.ExternalReference, 1)
+	for cell, expected := range map[string]string{
+		"A1": "", "A2": "", "A3": "[1]Sheet1!A1", "A4": "SUM(Table1[2])", "A5": `"[1]"&B1`,
+		"A6": "'[1]Other Sheet'!A1+'My Sheet'!A1",
+	} {
+		formula, err := f.GetCellFormula("Sheet1", cell)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, formula)
+	}
+	formula, err := f.GetCellFormula("Sheet2", "A1")
+	assert.NoError(t, err)
+	assert.Equal(t, "SUM([1]Sheet1!A1:A2)", formula)
+	assert.Equal(t, []DefinedName{{Name: "Local", RefersTo: "[1]Sheet1!$A$1", Scope: "Workbook"}}, f.GetDefinedName())
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestStripActiveContent.xlsx")))
+
+	// Test strip active content with keeping all types of active content
+	f = NewFile()
+	assert.NoError(t, f.AddVBAProject(file))
+	assert.NoError(t, f.StripActiveContent(ActiveContentOptions{KeepVBAProject: true, KeepMacroSheets: true, KeepOLEObjects: true, KeepExternalLinks: true}))
+	_, ok = f.Pkg.Load("xl/vbaProject.bin")
+	assert.True(t, ok)
+	// Test strip active content on the workbook without active content
+	assert.NoError(t, NewFile().StripActiveContent())
+	// Test strip active content with unsupported charset workbook relationships
+	f = NewFile()
+	f.Relationships.Delete(defaultXMLPathWorkbookRels)
+	f.Pkg.Store(defaultXMLPathWorkbookRels, MacintoshCyrillicCharset)
+	assert.EqualError(t, f.StripActiveContent(), "XML syntax error on line 1: invalid UTF-8")
+	// Test strip active content with unsupported charset content types
+	f = NewFile()
+	f.ContentTypes = nil
+	f.Pkg.Store(defaultXMLPathContentTypes, MacintoshCyrillicCharset)
+	assert.EqualError(t, f.StripActiveContent(), "XML syntax error on line 1: invalid UTF-8")
+	// Test strip active content with unsupported charset workbook
+	f = NewFile()
+	f.WorkBook = nil
+	f.Pkg.Store(defaultXMLPathWorkbook, MacintoshCyrillicCharset)
+	assert.EqualError(t, f.StripActiveContent(ActiveContentOptions{KeepVBAProject: true}), "XML syntax error on line 1: invalid UTF-8")
+	// Test strip active content with unsupported charset worksheet
+	f = NewFile()
+	f.Sheet.Delete("xl/worksheets/sheet1.xml")
+	f.Pkg.Store("xl/worksheets/sheet1.xml", MacintoshCyrillicCharset)
+	f.checked = sync.Map{}
+	assert.EqualError(t, f.StripActiveContent(), "XML syntax error on line 1: invalid UTF-8")
+	// Test strip active content with unsupported charset external link
+	f = NewFile()
+	wb, err = f.workbookReader()
+	assert.NoError(t, err)
+	rID = f.addRels(f.getWorkbookRelsPath(), SourceRelationshipExternalLink, "externalLinks/externalLink1.xml", "")
+	wb.ExternalReferences = &xlsxExternalReferences{ExternalReference: []xlsxExternalReference{{RID: "rId" + strconv.Itoa(rID)}}}
+	f.Pkg.Store("xl/externalLinks/externalLink1.xml", MacintoshCyrillicCharset)
+	assert.EqualError(t, f.StripActiveContent(), "XML syntax error on line 1: invalid UTF-8")
+}
+
+func TestIsRemoteTarget(t *testing.T) {
+	for target, expected := range map[string]bool{
+		`\\server\share\Book1.xlsx`:      true,
+		"//server/share/Book1.xlsx":      true,
+		"https://example.com/Book1.xlsx": true,
+		"file:///C:/Book1.xlsx":          false,
+		"Book1.xlsx":                     false,
+	} {
+		assert.Equal(t, expected, isRemoteTarget(target), target)
+	}
+}
+
 func TestContentTypesReader(t *testing.T) {
 	// Test unsupported charset
 	f := NewFile()
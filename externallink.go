@@ -0,0 +1,553 @@
+// Copyright 2016 - 2023 The excelize Authors. All rights reserved. Use of
+// this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+//
+// package excelize_ch providing a set of functions that allow you to write to and
+// read from XLAM / XLSM / XLSX / XLTM / XLTX files. Supports reading and
+// writing spreadsheet documents generated by Microsoft Excel™ 2007 and later.
+// Supports complex components by high compatibility, and provided streaming
+// API for generating or reading data from a worksheet with huge amounts of
+// data. This library needs Go version 1.16 or later.
+
+package excelize_ch
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// externalBookLink directly maps the external workbook link of the workbook
+// with the index, part name and target of it.
+type externalBookLink struct {
+	index    int
+	partName string
+	target   string
+	link     *xlsxExternalLink
+}
+
+// GetExternalLinks provides a function to get the external workbook links of
+// the workbook, including the index of the link in the formulas, the path
+// of the external workbook, the worksheet names and the cached cell values
+// of the external workbook. The DDE and OLE links will be skipped. For
+// example, get the external workbook links:
+//
+//	links, err := f.GetExternalLinks()
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	for _, link := range links {
+//	    fmt.Printf("[%d] %s\n", link.Index, link.Target)
+//	}
+func (f *File) GetExternalLinks() ([]ExternalLink, error) {
+	var extLinks []ExternalLink
+	links, err := f.getExternalBookLinks()
+	if err != nil {
+		return extLinks, err
+	}
+	for _, l := range links {
+		extLink := ExternalLink{Index: l.index, Target: l.target}
+		book := l.link.ExternalBook
+		if book.SheetNames != nil {
+			for _, name := range book.SheetNames.SheetName {
+				extLink.SheetNames = append(extLink.SheetNames, name.Val)
+			}
+		}
+		if book.SheetDataSet != nil {
+			for _, sheetData := range book.SheetDataSet.SheetData {
+				var sheet string
+				if sheetData.SheetID >= 0 && sheetData.SheetID < len(extLink.SheetNames) {
+					sheet = extLink.SheetNames[sheetData.SheetID]
+				}
+				for _, row := range sheetData.Row {
+					for _, c := range row.Cell {
+						extLink.Values = append(extLink.Values, ExternalLinkValue{Sheet: sheet, Cell: c.R, Value: c.V})
+					}
+				}
+			}
+		}
+		extLinks = append(extLinks, extLink)
+	}
+	return extLinks, err
+}
+
+// AddExternalLink provides a function to add the external workbook link by
+// given path of the external workbook, and returns the index of the link in
+// the formulas. The index of the existing link will be returned if the
+// workbook already links to the given path. The cell formulas referencing
+// the external workbook by the file name, such as [Budget.xlsx]Sheet1!A1,
+// will be converted to use the index of the link when setting the formula
+// by the SetCellFormula function, and the link will be added automatically.
+// For example, link to the external workbook Budget.xlsx in the same folder
+// and reference the cell A1 on Sheet1 of it:
+//
+//	idx, err := f.AddExternalLink("Budget.xlsx")
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	err = f.SetCellFormula("Sheet1", "A1", fmt.Sprintf("[%d]Sheet1!A1", idx))
+func (f *File) AddExternalLink(target string) (int, error) {
+	if target == "" {
+		return 0, ErrParameterInvalid
+	}
+	links, err := f.getExternalBookLinks()
+	if err != nil {
+		return 0, err
+	}
+	for _, l := range links {
+		if strings.EqualFold(normalizeExternalLinkTarget(l.target), normalizeExternalLinkTarget(target)) {
+			return l.index, err
+		}
+	}
+	l, err := f.addExternalLink(target)
+	return l.index, err
+}
+
+// UpdateExternalLinkValues provides a function to update the cached cell
+// values of the external workbook links by given values map, the key of the
+// map is the external cell reference with the index or file name of the
+// external workbook, such as [1]Sheet1!A1 or '[Budget.xlsx]My Sheet'!B2, and
+// the supported value types are integers, floats, string, boolean and nil,
+// the cached value will be removed for the nil value. The cached values will
+// be used when calculating the formulas referencing the external workbooks.
+// For example, refresh the cached values of the external workbook
+// Budget.xlsx:
+//
+//	err := f.UpdateExternalLinkValues(map[string]interface{}{
+//	    "[Budget.xlsx]Sheet1!A1": 100,
+//	    "[Budget.xlsx]Sheet1!B1": "Q1",
+//	})
+func (f *File) UpdateExternalLinkValues(values map[string]interface{}) error {
+	links, err := f.getExternalBookLinks()
+	if err != nil {
+		return err
+	}
+	refs := make([]string, 0, len(values))
+	for ref := range values {
+		refs = append(refs, ref)
+	}
+	sort.Strings(refs)
+	updated := map[string]*xlsxExternalLink{}
+	for _, ref := range refs {
+		ast, err := ParseFormula(ref)
+		if err != nil || ast.Root.Type != FormulaNodeRef {
+			return newInvalidExternalRefError(ref)
+		}
+		book, sheet, ok := splitExternalSheetName(ast.Root.Sheet)
+		if !ok || sheet == "" || strings.Contains(sheet, ":") {
+			return newInvalidExternalRefError(ref)
+		}
+		l := findExternalBookLink(links, book)
+		if l == nil {
+			return newNoExistExternalLinkError(book)
+		}
+		if err = setExternalCellValue(l.link.ExternalBook, sheet, ast.Root.Value, values[ref]); err != nil {
+			return err
+		}
+		updated[l.partName] = l.link
+	}
+	for partName, link := range updated {
+		f.externalLinkWriter(partName, link)
+	}
+	return err
+}
+
+// getExternalLinkParts provides a function to get the part names of the
+// external links of the workbook in order, the part name will be empty if the
+// relationship of the external link doesn't exist.
+func (f *File) getExternalLinkParts() ([]string, error) {
+	var parts []string
+	wb, err := f.workbookReader()
+	if err != nil || wb.ExternalReferences == nil {
+		return parts, err
+	}
+	rels, err := f.relsReader(f.getWorkbookRelsPath())
+	if err != nil {
+		return parts, err
+	}
+	wbPath := f.getWorkbookPath()
+	for _, ref := range wb.ExternalReferences.ExternalReference {
+		var partName string
+		if rels != nil {
+			rels.mu.Lock()
+			for _, rel := range rels.Relationships {
+				if rel.ID == ref.RID && rel.Type == SourceRelationshipExternalLink {
+					partName = getRelsTargetPath(wbPath, rel.Target)
+				}
+			}
+			rels.mu.Unlock()
+		}
+		parts = append(parts, partName)
+	}
+	return parts, err
+}
+
+// getExternalBookLinks provides a function to get the external workbook links
+// of the workbook, the DDE and OLE links and the links which part doesn't
+// exist will be skipped.
+func (f *File) getExternalBookLinks() ([]*externalBookLink, error) {
+	var links []*externalBookLink
+	parts, err := f.getExternalLinkParts()
+	if err != nil {
+		return links, err
+	}
+	for idx, partName := range parts {
+		if partName == "" || !f.hasPart(partName) {
+			continue
+		}
+		link, err := f.externalLinkReader(partName)
+		if err != nil {
+			return links, err
+		}
+		if link.ExternalBook == nil {
+			continue
+		}
+		target, err := f.getExternalLinkTarget(partName, link.ExternalBook.RID)
+		if err != nil {
+			return links, err
+		}
+		links = append(links, &externalBookLink{index: idx + 1, partName: partName, target: target, link: link})
+	}
+	return links, err
+}
+
+// getExternalLinkTarget provides a function to get the path of the external
+// workbook by given external link part name and relationship ID.
+func (f *File) getExternalLinkTarget(partName, rID string) (string, error) {
+	rels, err := f.relsReader(getPartRelsPath(partName))
+	if err != nil || rels == nil {
+		return "", err
+	}
+	rels.mu.Lock()
+	defer rels.mu.Unlock()
+	for _, rel := range rels.Relationships {
+		if rel.ID == rID {
+			return rel.Target, err
+		}
+	}
+	return "", err
+}
+
+// externalLinkReader provides a function to get the pointer to the structure
+// after deserialization of the external link part by given part name.
+func (f *File) externalLinkReader(partName string) (*xlsxExternalLink, error) {
+	link := new(xlsxExternalLink)
+	content := namespaceStrictToTransitional(f.readXML(partName))
+	if _, ok := f.xmlAttr.Load(partName); !ok {
+		f.xmlAttr.Store(partName, getExternalLinkRootAttrs(getRootElement(f.xmlNewDecoder(bytes.NewReader(content)))))
+	}
+	if err := f.xmlNewDecoder(bytes.NewReader(content)).
+		Decode(link); err != nil && err != io.EOF {
+		return link, err
+	}
+	return link, nil
+}
+
+// externalLinkWriter provides a function to save the external link part after
+// serialize structure by given part name.
+func (f *File) externalLinkWriter(partName string, link *xlsxExternalLink) {
+	output, _ := xml.Marshal(link)
+	f.saveFileList(partName, replaceRelationshipsBytes(f.replaceNameSpaceBytes(partName, output)))
+}
+
+// getExternalLinkRootAttrs provides a function to get the attributes of the
+// root element of the external link part by given attributes of the original
+// root element, the spreadsheet and relationships namespaces will be added
+// if not exist.
+func getExternalLinkRootAttrs(attrs []xml.Attr) []xml.Attr {
+	var ns, r bool
+	for _, attr := range attrs {
+		if attr.Name.Space == "" && attr.Name.Local == NameSpaceSpreadSheet.Name.Local {
+			ns = true
+		}
+		if attr.Name.Space == SourceRelationship.Name.Space && attr.Name.Local == SourceRelationship.Name.Local {
+			r = true
+		}
+	}
+	if !ns {
+		attrs = append([]xml.Attr{NameSpaceSpreadSheet}, attrs...)
+	}
+	if !r {
+		attrs = append(attrs, SourceRelationship)
+	}
+	return attrs
+}
+
+// addExternalLink provides a function to add the external workbook link part
+// and relationships by given path of the external workbook.
+func (f *File) addExternalLink(target string) (*externalBookLink, error) {
+	wb, err := f.workbookReader()
+	if err != nil {
+		return nil, err
+	}
+	partName := "xl/externalLinks/externalLink" + strconv.Itoa(f.getUnusedPartID("xl/externalLinks/externalLink")) + ".xml"
+	rID := f.addRels(getPartRelsPath(partName), SourceRelationshipExternalLinkPath, target, "External")
+	link := &xlsxExternalLink{ExternalBook: &xlsxExternalBook{RID: "rId" + strconv.Itoa(rID)}}
+	f.xmlAttr.Store(partName, []xml.Attr{NameSpaceSpreadSheet, SourceRelationship})
+	f.externalLinkWriter(partName, link)
+	wbRID := f.addRels(f.getWorkbookRelsPath(), SourceRelationshipExternalLink, getRelsTarget(f.getWorkbookPath(), partName), "")
+	if wb.ExternalReferences == nil {
+		wb.ExternalReferences = &xlsxExternalReferences{}
+	}
+	wb.ExternalReferences.ExternalReference = append(wb.ExternalReferences.ExternalReference,
+		xlsxExternalReference{RID: "rId" + strconv.Itoa(wbRID)})
+	l := &externalBookLink{index: len(wb.ExternalReferences.ExternalReference), partName: partName, target: target, link: link}
+	return l, f.setContentTypes("/"+partName, ContentTypeSpreadSheetMLExternalLink)
+}
+
+// normalizeExternalLinkTarget provides a function to remove the file scheme
+// and convert the backslashes to the slashes of the external workbook path.
+func normalizeExternalLinkTarget(target string) string {
+	return strings.ReplaceAll(strings.TrimPrefix(target, "file:///"), "\\", "/")
+}
+
+// findExternalBookLink provides a function to find the external workbook link
+// by given index or path of the external workbook, the file name of the path
+// will be matched if there are no links which target to the full path.
+func findExternalBookLink(links []*externalBookLink, book string) *externalBookLink {
+	if idx, err := strconv.Atoi(book); err == nil {
+		for _, l := range links {
+			if l.index == idx {
+				return l
+			}
+		}
+		return nil
+	}
+	book = normalizeExternalLinkTarget(book)
+	for _, l := range links {
+		if strings.EqualFold(normalizeExternalLinkTarget(l.target), book) {
+			return l
+		}
+	}
+	for _, l := range links {
+		if strings.EqualFold(path.Base(normalizeExternalLinkTarget(l.target)), path.Base(book)) {
+			return l
+		}
+	}
+	return nil
+}
+
+// splitExternalSheetName provides a function to split the external workbook
+// and the worksheet name by given worksheet name of the reference, such as
+// [1]Sheet1 or C:\Data\[Budget.xlsx]Sheet1, returns false if the reference
+// doesn't refer to an external workbook.
+func splitExternalSheetName(sheet string) (string, string, bool) {
+	start, end := strings.Index(sheet, "["), strings.Index(sheet, "]")
+	if start == -1 || end <= start+1 {
+		return "", sheet, false
+	}
+	return sheet[:start] + sheet[start+1:end], sheet[end+1:], true
+}
+
+// setExternalLinkFormula provides a function to convert the external workbook
+// references by the file name in the formula to use the index of the external
+// workbook links, such as convert [Budget.xlsx]Sheet1!A1 to [1]Sheet1!A1, the
+// external workbook links and the worksheet names of them will be added if
+// not exist.
+func (f *File) setExternalLinkFormula(formula string) (string, error) {
+	if !strings.Contains(formula, "[") {
+		return formula, nil
+	}
+	ast, err := ParseFormula(formula)
+	if err != nil {
+		return formula, nil
+	}
+	var (
+		links   []*externalBookLink
+		loaded  bool
+		updated = map[string]*xlsxExternalLink{}
+	)
+	ast.Walk(func(node *FormulaNode) bool {
+		book, sheet, ok := splitExternalSheetName(node.Sheet)
+		if !ok {
+			return true
+		}
+		if _, e := strconv.Atoi(book); e == nil {
+			return true
+		}
+		if !loaded {
+			if links, err = f.getExternalBookLinks(); err != nil {
+				return false
+			}
+			loaded = true
+		}
+		l := findExternalBookLink(links, book)
+		if l == nil {
+			if l, err = f.addExternalLink(book); err != nil {
+				return false
+			}
+			links = append(links, l)
+		}
+		for _, name := range strings.Split(sheet, ":") {
+			if name != "" {
+				addExternalBookSheet(l.link.ExternalBook, name)
+			}
+		}
+		updated[l.partName], node.Sheet = l.link, "["+strconv.Itoa(l.index)+"]"+sheet
+		return true
+	})
+	if err != nil || len(updated) == 0 {
+		return formula, err
+	}
+	for partName, link := range updated {
+		f.externalLinkWriter(partName, link)
+	}
+	if strings.HasPrefix(strings.TrimSpace(formula), "=") {
+		return "=" + ast.String(), err
+	}
+	return ast.String(), err
+}
+
+// addExternalBookSheet provides a function to add the worksheet name to the
+// external workbook if not exist, and returns the zero-based index of the
+// worksheet in the external workbook.
+func addExternalBookSheet(book *xlsxExternalBook, sheet string) int {
+	if book.SheetNames == nil {
+		book.SheetNames = &xlsxExternalSheetNames{}
+	}
+	for idx, name := range book.SheetNames.SheetName {
+		if strings.EqualFold(name.Val, sheet) {
+			return idx
+		}
+	}
+	book.SheetNames.SheetName = append(book.SheetNames.SheetName, xlsxExternalSheetName{Val: sheet})
+	return len(book.SheetNames.SheetName) - 1
+}
+
+// getExternalCellValue provides a function to get the type and value of the
+// cached cell of the external workbook by given cell value.
+func getExternalCellValue(value interface{}) (string, string, error) {
+	switch v := value.(type) {
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return "", fmt.Sprint(v), nil
+	case float32:
+		return "", strconv.FormatFloat(float64(v), 'f', -1, 32), nil
+	case float64:
+		return "", strconv.FormatFloat(v, 'f', -1, 64), nil
+	case string:
+		return "str", v, nil
+	case bool:
+		if v {
+			return "b", "1", nil
+		}
+		return "b", "0", nil
+	}
+	return "", "", ErrParameterInvalid
+}
+
+// setExternalCellValue provides a function to set the cached cell value of the
+// external workbook by given worksheet name, cell reference and value, the
+// cached cell will be removed for the nil value.
+func setExternalCellValue(book *xlsxExternalBook, sheet, cell string, value interface{}) error {
+	col, row, err := CellNameToCoordinates(strings.ReplaceAll(cell, "$", ""))
+	if err != nil {
+		return err
+	}
+	var t, v string
+	if value != nil {
+		if t, v, err = getExternalCellValue(value); err != nil {
+			return err
+		}
+	}
+	cell, _ = CoordinatesToCellName(col, row)
+	sheetID := addExternalBookSheet(book, sheet)
+	if book.SheetDataSet == nil {
+		book.SheetDataSet = &xlsxExternalSheetDataSet{}
+	}
+	dataSet := book.SheetDataSet
+	sheetIdx := sort.Search(len(dataSet.SheetData), func(i int) bool { return dataSet.SheetData[i].SheetID >= sheetID })
+	if sheetIdx == len(dataSet.SheetData) || dataSet.SheetData[sheetIdx].SheetID != sheetID {
+		dataSet.SheetData = append(dataSet.SheetData, xlsxExternalSheetData{})
+		copy(dataSet.SheetData[sheetIdx+1:], dataSet.SheetData[sheetIdx:])
+		dataSet.SheetData[sheetIdx] = xlsxExternalSheetData{SheetID: sheetID}
+	}
+	sheetData := &dataSet.SheetData[sheetIdx]
+	rowIdx := sort.Search(len(sheetData.Row), func(i int) bool { return sheetData.Row[i].R >= row })
+	if rowIdx == len(sheetData.Row) || sheetData.Row[rowIdx].R != row {
+		if value == nil {
+			return err
+		}
+		sheetData.Row = append(sheetData.Row, xlsxExternalRow{})
+		copy(sheetData.Row[rowIdx+1:], sheetData.Row[rowIdx:])
+		sheetData.Row[rowIdx] = xlsxExternalRow{R: row}
+	}
+	r := &sheetData.Row[rowIdx]
+	cellIdx := sort.Search(len(r.Cell), func(i int) bool {
+		c, _, _ := CellNameToCoordinates(r.Cell[i].R)
+		return c >= col
+	})
+	if cellIdx < len(r.Cell) && r.Cell[cellIdx].R == cell {
+		if value == nil {
+			if r.Cell = append(r.Cell[:cellIdx], r.Cell[cellIdx+1:]...); len(r.Cell) == 0 {
+				sheetData.Row = append(sheetData.Row[:rowIdx], sheetData.Row[rowIdx+1:]...)
+			}
+			return err
+		}
+		r.Cell[cellIdx].T, r.Cell[cellIdx].V = t, v
+		return err
+	}
+	if value == nil {
+		return err
+	}
+	r.Cell = append(r.Cell, xlsxExternalCell{})
+	copy(r.Cell[cellIdx+1:], r.Cell[cellIdx:])
+	r.Cell[cellIdx] = xlsxExternalCell{R: cell, T: t, V: v}
+	return err
+}
+
+// externalCellResolver provides a function to get the cached cell value of the
+// external workbook by given worksheet name with the external workbook, such
+// as [1]Sheet1, and the cell reference. The #REF! error will be returned if
+// the external workbook or the worksheet doesn't exist.
+func (f *File) externalCellResolver(sheet, cell string) (formulaArg, error) {
+	book, sheet, _ := splitExternalSheetName(sheet)
+	links, err := f.getExternalBookLinks()
+	if err != nil {
+		return newErrorFormulaArg(formulaErrorREF, formulaErrorREF), err
+	}
+	l := findExternalBookLink(links, book)
+	if l == nil || l.link.ExternalBook.SheetNames == nil {
+		return newErrorFormulaArg(formulaErrorREF, formulaErrorREF), err
+	}
+	sheetID := -1
+	for idx, name := range l.link.ExternalBook.SheetNames.SheetName {
+		if strings.EqualFold(name.Val, sheet) {
+			sheetID = idx
+		}
+	}
+	if sheetID == -1 {
+		return newErrorFormulaArg(formulaErrorREF, formulaErrorREF), err
+	}
+	if l.link.ExternalBook.SheetDataSet == nil {
+		return newEmptyFormulaArg(), err
+	}
+	for _, sheetData := range l.link.ExternalBook.SheetDataSet.SheetData {
+		if sheetData.SheetID != sheetID {
+			continue
+		}
+		for _, row := range sheetData.Row {
+			for _, c := range row.Cell {
+				if c.R != cell {
+					continue
+				}
+				switch c.T {
+				case "b":
+					return newStringFormulaArg(c.V).ToBool(), err
+				case "e":
+					return newErrorFormulaArg(c.V, c.V), err
+				case "str", "s", "inlineStr":
+					return newStringFormulaArg(c.V), err
+				}
+				return newStringFormulaArg(c.V).ToNumber(), err
+			}
+		}
+	}
+	return newEmptyFormulaArg(), err
+}
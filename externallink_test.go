@@ -0,0 +1,177 @@
+package excelize_ch
+
+import (
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExternalLink(t *testing.T) {
+	f := NewFile()
+	links, err := f.GetExternalLinks()
+	assert.NoError(t, err)
+	assert.Empty(t, links)
+	// Test add external workbook link
+	idx, err := f.AddExternalLink("Budget.xlsx")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, idx)
+	idx, err = f.AddExternalLink("budget.xlsx")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, idx)
+	// Test set formula referencing the external workbook by the file name
+	assert.NoError(t, f.SetCellFormula("Sheet1", "A1", "=[Budget.xlsx]Sheet1!A1*2"))
+	assert.NoError(t, f.SetCellFormula("Sheet1", "A2", "SUM('[Budget.xlsx]Q1 Sales'!B1:B2)+[Report.xlsx]Sheet1!$C$3"))
+	assert.NoError(t, f.SetCellFormula("Sheet1", "A3", "[1]Sheet1!B1"))
+	assert.NoError(t, f.SetCellFormula("Sheet1", "A4", "Table1[[#This Row],[Sales]]"))
+	for cell, expected := range map[string]string{
+		"A1": "=[1]Sheet1!A1*2",
+		"A2": "SUM('[1]Q1 Sales'!B1:B2)+[2]Sheet1!$C$3",
+		"A3": "[1]Sheet1!B1",
+		"A4": "Table1[[#This Row],[Sales]]",
+	} {
+		formula, err := f.GetCellFormula("Sheet1", cell)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, formula, cell)
+	}
+	// Test update the cached values of the external workbooks
+	assert.NoError(t, f.UpdateExternalLinkValues(map[string]interface{}{
+		"[Budget.xlsx]Sheet1!A1":       100,
+		"[Budget.xlsx]Sheet1!$B$1":     "Q1",
+		"'[1]Q1 Sales'!B2":             2.5,
+		"'[1]Q1 Sales'!B1":             int64(3),
+		"[Budget.xlsx]Sheet1!A3":       true,
+		"[Budget.xlsx]Sheet1!C3":       false,
+		"[Budget.xlsx]Sheet1!B3":       float32(1.5),
+		"[Report.xlsx]Sheet1!C3":       uint8(4),
+		"[Report.xlsx]Sheet1!A1":       nil,
+		"'[Budget.xlsx]New Sheet'!A10": "New",
+	}))
+	assert.NoError(t, f.UpdateExternalLinkValues(map[string]interface{}{
+		"[Budget.xlsx]Sheet1!C3": nil,
+		"[Budget.xlsx]Sheet1!A3": nil,
+		"[Budget.xlsx]Sheet1!B1": "Q2",
+	}))
+	links, err = f.GetExternalLinks()
+	assert.NoError(t, err)
+	assert.Equal(t, []ExternalLink{
+		{
+			Index: 1, Target: "Budget.xlsx", SheetNames: []string{"Sheet1", "Q1 Sales", "New Sheet"},
+			Values: []ExternalLinkValue{
+				{Sheet: "Sheet1", Cell: "A1", Value: "100"},
+				{Sheet: "Sheet1", Cell: "B1", Value: "Q2"},
+				{Sheet: "Sheet1", Cell: "B3", Value: "1.5"},
+				{Sheet: "Q1 Sales", Cell: "B1", Value: "3"},
+				{Sheet: "Q1 Sales", Cell: "B2", Value: "2.5"},
+				{Sheet: "New Sheet", Cell: "A10", Value: "New"},
+			},
+		},
+		{
+			Index: 2, Target: "Report.xlsx", SheetNames: []string{"Sheet1"},
+			Values: []ExternalLinkValue{{Sheet: "Sheet1", Cell: "C3", Value: "4"}},
+		},
+	}, links)
+	// Test calculate the formulas referencing the external workbooks
+	for cell, expected := range map[string]string{"A1": "200", "A2": "9.5", "A3": "Q2"} {
+		result, err := f.CalcCellValue("Sheet1", cell)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, result, cell)
+	}
+	for formula, expected := range map[string]string{
+		"[1]Sheet1!A2":           "",
+		"[1]Sheet2!A1":           "#REF!",
+		"[3]Sheet1!A1":           "#REF!",
+		"[Budget.xlsx]Sheet1!B1": "Q2",
+	} {
+		assert.NoError(t, f.SetCellFormula("Sheet1", "B1", formula))
+		result, _ := f.CalcCellValue("Sheet1", "B1")
+		assert.Equal(t, expected, result, formula)
+	}
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestExternalLink.xlsx")))
+	assert.NoError(t, f.Close())
+	// Test open the workbook with the external workbook links
+	f, err = OpenFile(filepath.Join("test", "TestExternalLink.xlsx"))
+	assert.NoError(t, err)
+	reopened, err := f.GetExternalLinks()
+	assert.NoError(t, err)
+	assert.Equal(t, links, reopened)
+	content, ok := f.Pkg.Load("xl/externalLinks/externalLink1.xml")
+	assert.True(t, ok)
+	assert.True(t, strings.Contains(string(content.([]byte)), `<externalBook r:id="rId1">`))
+	result, err := f.CalcCellValue("Sheet1", "A1")
+	assert.NoError(t, err)
+	assert.Equal(t, "200", result)
+	assert.NoError(t, f.Close())
+
+	f = NewFile()
+	// Test add external workbook link with invalid path
+	_, err = f.AddExternalLink("")
+	assert.Equal(t, ErrParameterInvalid, err)
+	// Test update the cached values with invalid references
+	_, err = f.AddExternalLink(`C:\Data\Budget.xlsx`)
+	assert.NoError(t, err)
+	for ref, expected := range map[string]string{
+		"A1":                     newInvalidExternalRefError("A1").Error(),
+		"[1]Sheet1!A1:B2":        newInvalidExternalRefError("[1]Sheet1!A1:B2").Error(),
+		"[1]Sheet1:Sheet2!A1":    newInvalidExternalRefError("[1]Sheet1:Sheet2!A1").Error(),
+		"[1]!Name":               newInvalidExternalRefError("[1]!Name").Error(),
+		"SUM(":                   newInvalidExternalRefError("SUM(").Error(),
+		"[2]Sheet1!A1":           newNoExistExternalLinkError("2").Error(),
+		"[Report.xlsx]Sheet1!A1": newNoExistExternalLinkError("Report.xlsx").Error(),
+	} {
+		assert.EqualError(t, f.UpdateExternalLinkValues(map[string]interface{}{ref: 1}), expected, ref)
+	}
+	assert.Equal(t, ErrParameterInvalid, f.UpdateExternalLinkValues(map[string]interface{}{"[Budget.xlsx]Sheet1!A1": []int{1}}))
+	assert.NoError(t, f.UpdateExternalLinkValues(map[string]interface{}{`'C:\Data\[Budget.xlsx]Sheet1'!A1`: 1}))
+	assert.NoError(t, f.SetCellFormula("Sheet1", "A1", `'C:\Data\[Budget.xlsx]Sheet1'!A1+1`))
+	formula, err := f.GetCellFormula("Sheet1", "A1")
+	assert.NoError(t, err)
+	assert.Equal(t, "[1]Sheet1!A1+1", formula)
+	result, err = f.CalcCellValue("Sheet1", "A1")
+	assert.NoError(t, err)
+	assert.Equal(t, "2", result)
+	// Test set the invalid formula with the external workbook reference
+	assert.NoError(t, f.SetCellFormula("Sheet1", "A2", "[Report.xlsx]Sheet1!A1+"))
+	formula, err = f.GetCellFormula("Sheet1", "A2")
+	assert.NoError(t, err)
+	assert.Equal(t, "[Report.xlsx]Sheet1!A1+", formula)
+	// Test get the external workbook links with the DDE link and missing parts
+	f.Pkg.Store("xl/externalLinks/externalLink2.xml", []byte(`<externalLink xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><ddeLink ddeService="Excel" ddeTopic="Book1"/></externalLink>`))
+	for _, target := range []string{"externalLinks/externalLink2.xml", "externalLinks/externalLink3.xml"} {
+		rID := f.addRels(f.getWorkbookRelsPath(), SourceRelationshipExternalLink, target, "")
+		f.WorkBook.ExternalReferences.ExternalReference = append(f.WorkBook.ExternalReferences.ExternalReference,
+			xlsxExternalReference{RID: "rId" + strconv.Itoa(rID)})
+	}
+	f.WorkBook.ExternalReferences.ExternalReference = append(f.WorkBook.ExternalReferences.ExternalReference, xlsxExternalReference{RID: "rId100"})
+	links, err = f.GetExternalLinks()
+	assert.NoError(t, err)
+	assert.Len(t, links, 1)
+	idx, err = f.AddExternalLink("Report.xlsx")
+	assert.NoError(t, err)
+	assert.Equal(t, 5, idx)
+	// Test get the external workbook links with unsupported charset
+	f.Pkg.Store("xl/externalLinks/externalLink1.xml", MacintoshCyrillicCharset)
+	_, err = f.GetExternalLinks()
+	assert.EqualError(t, err, "XML syntax error on line 1: invalid UTF-8")
+	_, err = f.AddExternalLink("Book1.xlsx")
+	assert.EqualError(t, err, "XML syntax error on line 1: invalid UTF-8")
+	assert.EqualError(t, f.UpdateExternalLinkValues(nil), "XML syntax error on line 1: invalid UTF-8")
+	assert.EqualError(t, f.SetCellFormula("Sheet1", "A1", "[Book1.xlsx]Sheet1!A1"), "XML syntax error on line 1: invalid UTF-8")
+	assert.NoError(t, f.SetCellFormula("Sheet1", "A1", "[1]Sheet1!A1"))
+	_, err = f.CalcCellValue("Sheet1", "A1")
+	assert.EqualError(t, err, formulaErrorNAME)
+	// Test get the external workbook links with unsupported charset relationships
+	f.Pkg.Store("xl/externalLinks/externalLink1.xml", []byte(`<externalLink xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><externalBook xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships" r:id="rId1"/></externalLink>`))
+	f.Relationships.Delete("xl/externalLinks/_rels/externalLink1.xml.rels")
+	f.Pkg.Store("xl/externalLinks/_rels/externalLink1.xml.rels", MacintoshCyrillicCharset)
+	_, err = f.GetExternalLinks()
+	assert.EqualError(t, err, "XML syntax error on line 1: invalid UTF-8")
+	// Test get the external workbook links with unsupported charset workbook
+	f.WorkBook = nil
+	f.Pkg.Store(defaultXMLPathWorkbook, MacintoshCyrillicCharset)
+	_, err = f.GetExternalLinks()
+	assert.EqualError(t, err, "XML syntax error on line 1: invalid UTF-8")
+	assert.NoError(t, f.Close())
+}
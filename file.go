@@ -15,13 +15,39 @@ import (
 	"archive/zip"
 	"bytes"
 	"encoding/xml"
+	"fmt"
 	"io"
 	"os"
 	"path/filepath"
+	"runtime"
+	"sort"
 	"strings"
 	"sync"
+	"time"
 )
 
+// FileFormat is the type of the workbook file format.
+type FileFormat byte
+
+// Workbook file formats enumeration.
+const (
+	FileFormatXLSX FileFormat = iota
+	FileFormatXLSM
+	FileFormatXLTX
+	FileFormatXLTM
+	FileFormatXLAM
+)
+
+// fileFormatExtensions defined the file extensions of the workbook file
+// formats.
+var fileFormatExtensions = map[FileFormat]string{
+	FileFormatXLSX: ".xlsx",
+	FileFormatXLSM: ".xlsm",
+	FileFormatXLTX: ".xltx",
+	FileFormatXLTM: ".xltm",
+	FileFormatXLAM: ".xlam",
+}
+
 // NewFile provides a function to create new file by default template.
 // For example:
 //
@@ -65,7 +91,14 @@ func (f *File) Save(opts ...Options) error {
 }
 
 // SaveAs provides a function to create or update to a spreadsheet at the
-// provided path.
+// provided path. The workbook will be written into a temporary file in the
+// same directory first, and then renamed to the provided path, so the
+// existing spreadsheet will not be corrupted if the saving failed, and the
+// concurrent writers on the same path will be serialized in the process. The
+// ErrWorkbookLocked error will be returned if the spreadsheet is opened by
+// Microsoft Excel or LibreOffice, which is detected by the lock file of the
+// application, such as "~$Book1.xlsx" in the same directory, unless the
+// IgnoreLockFile option was specified.
 func (f *File) SaveAs(name string, opts ...Options) error {
 	if len(name) > MaxFilePathLength {
 		return ErrMaxFilePathLength
@@ -74,12 +107,188 @@ func (f *File) SaveAs(name string, opts ...Options) error {
 	if _, ok := supportedContentTypes[strings.ToLower(filepath.Ext(f.Path))]; !ok {
 		return ErrWorkbookFileFormat
 	}
-	file, err := os.OpenFile(filepath.Clean(name), os.O_WRONLY|os.O_TRUNC|os.O_CREATE, os.ModePerm)
+	name = filepath.Clean(name)
+	if target, err := filepath.EvalSymlinks(name); err == nil {
+		name = target
+	}
+	options := f.options
+	for i := range opts {
+		options = &opts[i]
+	}
+	if (options == nil || !options.IgnoreLockFile) && isWorkbookLocked(name) {
+		return ErrWorkbookLocked
+	}
+	name = lockSavePath(name)
+	defer unlockSavePath(name)
+	file, err := createSaveTempFile(name)
 	if err != nil {
 		return err
 	}
-	defer file.Close()
-	return f.Write(file, opts...)
+	if err = f.Write(file, opts...); err != nil {
+		_ = file.Close()
+		_ = os.Remove(file.Name())
+		return err
+	}
+	if err = file.Close(); err != nil {
+		_ = os.Remove(file.Name())
+		return err
+	}
+	if err = renameSaveTempFile(file.Name(), name); err != nil {
+		_ = os.Remove(file.Name())
+	}
+	return err
+}
+
+// saveLock directly maps the lock of the spreadsheet path with the number of
+// the savings which are holding or waiting for the lock.
+type saveLock struct {
+	mu   sync.Mutex
+	refs int
+}
+
+// saveLocks stores the locks of the spreadsheet paths for serializing the
+// concurrent saving on the same path, the lock will be released from the map
+// when no saving is holding or waiting for it.
+var saveLocks = struct {
+	mu    sync.Mutex
+	locks map[string]*saveLock
+}{locks: map[string]*saveLock{}}
+
+// lockSavePath provides a function to acquire the lock of the spreadsheet by
+// given path, and returns the absolute path for releasing the lock.
+func lockSavePath(name string) string {
+	if abs, err := filepath.Abs(name); err == nil {
+		name = abs
+	}
+	saveLocks.mu.Lock()
+	lock, ok := saveLocks.locks[name]
+	if !ok {
+		lock = &saveLock{}
+		saveLocks.locks[name] = lock
+	}
+	lock.refs++
+	saveLocks.mu.Unlock()
+	lock.mu.Lock()
+	return name
+}
+
+// unlockSavePath provides a function to release the lock of the spreadsheet
+// by given absolute path, which returned by the lockSavePath function.
+func unlockSavePath(name string) {
+	saveLocks.mu.Lock()
+	defer saveLocks.mu.Unlock()
+	lock := saveLocks.locks[name]
+	lock.mu.Unlock()
+	if lock.refs--; lock.refs == 0 {
+		delete(saveLocks.locks, name)
+	}
+}
+
+// isWorkbookLocked provides a function to detect whether the spreadsheet by
+// given path is opened by Microsoft Excel or LibreOffice, both of them create
+// a lock file next to the opened spreadsheet.
+func isWorkbookLocked(name string) bool {
+	dir, base := filepath.Split(name)
+	for _, lockFile := range []string{"~$" + base, ".~lock." + base + "#"} {
+		if _, err := os.Stat(filepath.Join(dir, lockFile)); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// createSaveTempFile provides a function to create a temporary file in the
+// directory of the spreadsheet by given path for saving. The temporary file
+// has the same permission of the existing spreadsheet.
+func createSaveTempFile(name string) (*os.File, error) {
+	dir, base := filepath.Split(name)
+	perm, exist := os.ModePerm, false
+	if fi, err := os.Stat(name); err == nil {
+		perm, exist = fi.Mode().Perm(), true
+	}
+	for i := 0; ; i++ {
+		tempName := filepath.Join(dir, fmt.Sprintf(".%s.%d.%d.tmp", base, os.Getpid(), time.Now().UnixNano()))
+		file, err := os.OpenFile(tempName, os.O_WRONLY|os.O_CREATE|os.O_EXCL, perm)
+		if os.IsExist(err) && i < 100 {
+			continue
+		}
+		if err == nil && exist {
+			if err = file.Chmod(perm); err != nil {
+				_ = file.Close()
+				_ = os.Remove(tempName)
+			}
+		}
+		return file, err
+	}
+}
+
+// Retry limits for replacing the spreadsheet by the saved temporary file.
+const (
+	saveRenameRetries       = 5
+	saveRenameRetryInterval = 50 * time.Millisecond
+)
+
+// renameSaveTempFile provides a function to replace the spreadsheet by the
+// saved temporary file. On Windows, the rename may fail temporarily when the
+// spreadsheet is being read by other processes, such as antivirus software
+// or search indexer, so it will be retried several times.
+func renameSaveTempFile(tempName, name string) error {
+	var err error
+	for i := 0; i < saveRenameRetries; i++ {
+		if err = os.Rename(tempName, name); err == nil || runtime.GOOS != "windows" {
+			return err
+		}
+		time.Sleep(time.Duration(i+1) * saveRenameRetryInterval)
+	}
+	return err
+}
+
+// SaveAsFormat provides a function to convert the workbook to the given file
+// format and save it at the provided path. The content type of the workbook
+// part will be adjusted for the file format, and the extension of the path
+// will be changed to the one of the file format. The VBA project will be
+// removed on converting to the macro-free file formats XLSX and XLTX, and the
+// macro-enabled file formats XLSM, XLTM and XLAM require the workbook contains
+// a VBA project. For example, convert a macro-enabled workbook to the
+// template:
+//
+//	f, err := excelize.OpenFile("Book1.xlsm")
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	if err := f.SaveAsFormat("Book1.xltm", excelize.FileFormatXLTM); err != nil {
+//	    fmt.Println(err)
+//	}
+func (f *File) SaveAsFormat(name string, format FileFormat, opts ...Options) error {
+	ext, ok := fileFormatExtensions[format]
+	if !ok {
+		return ErrWorkbookFileFormat
+	}
+	if _, ok = supportedContentTypes[strings.ToLower(filepath.Ext(name))]; ok {
+		name = strings.TrimSuffix(name, filepath.Ext(name))
+	}
+	switch format {
+	case FileFormatXLSX, FileFormatXLTX:
+		if err := f.stripVBAProject(); err != nil {
+			return err
+		}
+	default:
+		rels, err := f.relsReader(f.getWorkbookRelsPath())
+		if err != nil {
+			return err
+		}
+		var hasVBAProject bool
+		if rels != nil {
+			for _, rel := range rels.Relationships {
+				hasVBAProject = hasVBAProject || rel.Type == SourceRelationshipVBAProject
+			}
+		}
+		if !hasVBAProject {
+			return ErrVBAProjectNotExist
+		}
+	}
+	return f.SaveAs(name+ext, opts...)
 }
 
 // Close closes and cleanup the open temporary file for the spreadsheet.
@@ -170,8 +379,17 @@ func (f *File) writeDirectToWriter(w io.Writer) error {
 	return zw.Close()
 }
 
-// writeToZip provides a function to write to zip.Writer
-func (f *File) writeToZip(zw *zip.Writer) error {
+// writeParts provides a function to serialize the parsed parts in memory into
+// the package. The worksheets which have been checked will be unloaded from
+// memory after serialized. Each worksheet is serialized under its own lock
+// without holding the workbook lock, because the cell setters acquire the
+// workbook lock while holding the worksheet lock, and then the workbook lock
+// will be held until the workbook level parts are written.
+func (f *File) writeParts() {
+	f.workSheetWriter()
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.appPropsWriter()
 	f.calcChainWriter()
 	f.commentsWriter()
 	f.contentTypesWriter()
@@ -179,52 +397,71 @@ func (f *File) writeToZip(zw *zip.Writer) error {
 	f.volatileDepsWriter()
 	f.vmlDrawingWriter()
 	f.workBookWriter()
-	f.workSheetWriter()
 	f.relsWriter()
-	_ = f.sharedStringsLoader()
+	_ = f.loadSharedStrings()
 	f.sharedStringsWriter()
 	f.styleSheetWriter()
 	f.themeWriter()
+	f.rawParts.Range(func(name, content interface{}) bool {
+		f.Pkg.Store(name, content)
+		return true
+	})
+}
 
-	for path, stream := range f.streams {
-		fi, err := zw.Create(path)
-		if err != nil {
-			return err
-		}
-		var from io.Reader
-		if from, err = stream.rawData.Reader(); err != nil {
-			_ = stream.rawData.Close()
-			return err
-		}
-		if _, err = io.Copy(fi, from); err != nil {
-			return err
-		}
+// writeToZip provides a function to write to zip.Writer. The package parts
+// will be written in the order of the part names if the Deterministic option
+// was specified, and the content types part will be written first.
+func (f *File) writeToZip(zw *zip.Writer) error {
+	f.writeParts()
+	var names []string
+	for path := range f.streams {
+		names = append(names, path)
 	}
-	var err error
 	f.Pkg.Range(func(path, content interface{}) bool {
-		if err != nil {
-			return false
-		}
-		if _, ok := f.streams[path.(string)]; ok {
-			return true
-		}
-		var fi io.Writer
-		if fi, err = zw.Create(path.(string)); err != nil {
-			return false
+		if _, ok := f.streams[path.(string)]; !ok {
+			names = append(names, path.(string))
 		}
-		_, err = fi.Write(content.([]byte))
 		return true
 	})
 	f.tempFiles.Range(func(path, content interface{}) bool {
-		if _, ok := f.Pkg.Load(path); ok {
-			return true
-		}
-		var fi io.Writer
-		if fi, err = zw.Create(path.(string)); err != nil {
-			return false
+		if _, ok := f.Pkg.Load(path); !ok {
+			names = append(names, path.(string))
 		}
-		_, err = fi.Write(f.readBytes(path.(string)))
 		return true
 	})
-	return err
+	if f.options != nil && f.options.Deterministic {
+		sort.Slice(names, func(i, j int) bool {
+			if (names[i] == defaultXMLPathContentTypes) != (names[j] == defaultXMLPathContentTypes) {
+				return names[i] == defaultXMLPathContentTypes
+			}
+			return names[i] < names[j]
+		})
+	}
+	for _, path := range names {
+		fi, err := zw.Create(path)
+		if err != nil {
+			return err
+		}
+		if stream, ok := f.streams[path]; ok {
+			var from io.Reader
+			if from, err = stream.rawData.Reader(); err != nil {
+				_ = stream.rawData.Close()
+				return err
+			}
+			if _, err = io.Copy(fi, from); err != nil {
+				return err
+			}
+			continue
+		}
+		if content, ok := f.Pkg.Load(path); ok {
+			if _, err = fi.Write(content.([]byte)); err != nil {
+				return err
+			}
+			continue
+		}
+		if _, err = fi.Write(f.readBytes(path)); err != nil {
+			return err
+		}
+	}
+	return nil
 }
@@ -1,10 +1,12 @@
 package excelize_ch
 
 import (
+	"archive/zip"
 	"bufio"
 	"bytes"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"sync"
 	"testing"
@@ -90,8 +92,79 @@ func TestWriteTo(t *testing.T) {
 	}
 }
 
+func TestWriteDeterministic(t *testing.T) {
+	genWorkbook := func() []byte {
+		f := NewFile()
+		_, err := f.NewSheet("Sheet2")
+		assert.NoError(t, err)
+		assert.NoError(t, f.SetSheetRow("Sheet1", "A1", &[]interface{}{"Month", "Sales"}))
+		assert.NoError(t, f.SetSheetRow("Sheet1", "A2", &[]interface{}{"Jan", 100}))
+		assert.NoError(t, f.AddPicture("Sheet1", "D1", filepath.Join("test", "images", "excel.png"), nil))
+		assert.NoError(t, f.AddComment("Sheet1", Comment{Cell: "A1", Author: "Excelize", Text: "Comment"}))
+		sw, err := f.NewStreamWriter("Sheet2")
+		assert.NoError(t, err)
+		assert.NoError(t, sw.SetRow("A1", []interface{}{1, 2, 3}))
+		assert.NoError(t, sw.Flush())
+		buf := new(bytes.Buffer)
+		assert.NoError(t, f.Write(buf, Options{Deterministic: true}))
+		assert.NoError(t, f.Close())
+		return buf.Bytes()
+	}
+	expected := genWorkbook()
+	for i := 0; i < 5; i++ {
+		assert.Equal(t, expected, genWorkbook())
+	}
+	zr, err := zip.NewReader(bytes.NewReader(expected), int64(len(expected)))
+	assert.NoError(t, err)
+	var names []string
+	for _, file := range zr.File {
+		names = append(names, file.Name)
+	}
+	assert.Equal(t, defaultXMLPathContentTypes, names[0])
+	assert.True(t, sort.StringsAreSorted(names[1:]))
+	assert.Contains(t, names, "xl/worksheets/sheet2.xml")
+}
+
 func TestClose(t *testing.T) {
 	f := NewFile()
 	f.tempFiles.Store("/d/", "/d/")
 	require.Error(t, f.Close())
 }
+
+func TestSaveAsFormat(t *testing.T) {
+	f := NewFile()
+	file, err := os.ReadFile(filepath.Join("test", "vbaProject.bin"))
+	assert.NoError(t, err)
+	// Test save as macro-enabled file format without VBA project
+	assert.Equal(t, ErrVBAProjectNotExist, f.SaveAsFormat(filepath.Join("test", "TestSaveAsFormat"), FileFormatXLSM))
+	assert.NoError(t, f.AddVBAProject(file))
+	for _, c := range []struct {
+		format      FileFormat
+		name        string
+		contentType string
+	}{
+		{format: FileFormatXLTM, name: "TestSaveAsFormat.xlsx", contentType: ContentTypeTemplateMacro},
+		{format: FileFormatXLAM, name: "TestSaveAsFormat", contentType: ContentTypeAddinMacro},
+		{format: FileFormatXLSM, name: "TestSaveAsFormat.xltm", contentType: ContentTypeMacro},
+		{format: FileFormatXLTX, name: "TestSaveAsFormat.xlsm", contentType: ContentTypeTemplate},
+		{format: FileFormatXLSX, name: "TestSaveAsFormat.v1", contentType: ContentTypeSheetML},
+	} {
+		assert.NoError(t, f.SaveAsFormat(filepath.Join("test", c.name), c.format))
+		assert.Equal(t, fileFormatExtensions[c.format], filepath.Ext(f.Path))
+		contentType, err := f.GetPartContentType("xl/workbook.xml")
+		assert.NoError(t, err)
+		assert.Equal(t, c.contentType, contentType)
+	}
+	assert.Equal(t, filepath.Join("test", "TestSaveAsFormat.v1.xlsx"), f.Path)
+	_, ok := f.Pkg.Load("xl/vbaProject.bin")
+	assert.False(t, ok)
+	// Test save as macro-enabled file format after the VBA project was removed
+	assert.Equal(t, ErrVBAProjectNotExist, f.SaveAsFormat(filepath.Join("test", "TestSaveAsFormat"), FileFormatXLTM))
+	// Test save as unsupported file format
+	assert.Equal(t, ErrWorkbookFileFormat, f.SaveAsFormat(filepath.Join("test", "TestSaveAsFormat"), FileFormat(5)))
+	// Test save as format with unsupported charset workbook relationships
+	f.Relationships.Delete(defaultXMLPathWorkbookRels)
+	f.Pkg.Store(defaultXMLPathWorkbookRels, MacintoshCyrillicCharset)
+	assert.EqualError(t, f.SaveAsFormat(filepath.Join("test", "TestSaveAsFormat"), FileFormatXLSM), "XML syntax error on line 1: invalid UTF-8")
+	assert.EqualError(t, f.SaveAsFormat(filepath.Join("test", "TestSaveAsFormat"), FileFormatXLSX), "XML syntax error on line 1: invalid UTF-8")
+}
@@ -0,0 +1,485 @@
+// Copyright 2016 - 2023 The excelize Authors. All rights reserved. Use of
+// this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+//
+// package excelize_ch providing a set of functions that allow you to write to and
+// read from XLAM / XLSM / XLSX / XLTM / XLTX files. Supports reading and
+// writing spreadsheet documents generated by Microsoft Excel™ 2007 and later.
+// Supports complex components by high compatibility, and provided streaming
+// API for generating or reading data from a worksheet with huge amounts of
+// data. This library needs Go version 1.16 or later.
+
+package excelize_ch
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/xuri/efp"
+)
+
+// FormulaNodeType is the type of the node in the formula abstract syntax
+// tree.
+type FormulaNodeType byte
+
+// Formula abstract syntax tree node types enumeration.
+const (
+	FormulaNodeEmpty FormulaNodeType = iota
+	FormulaNodeNumber
+	FormulaNodeText
+	FormulaNodeLogical
+	FormulaNodeError
+	FormulaNodeRef
+	FormulaNodeRange
+	FormulaNodeName
+	FormulaNodeFunction
+	FormulaNodeOperator
+	FormulaNodeParen
+	FormulaNodeArray
+	FormulaNodeArrayRow
+)
+
+// FormulaNode directly maps the node of the formula abstract syntax tree. The
+// Value field of the node is:
+//
+//	FormulaNodeEmpty    | Empty string, the omitted function argument
+//	FormulaNodeNumber   | Number literal, such as 1.5
+//	FormulaNodeText     | Text literal without quotation marks
+//	FormulaNodeLogical  | TRUE or FALSE
+//	FormulaNodeError    | Error literal, such as #N/A
+//	FormulaNodeRef      | Cell reference without worksheet name, such as $A$1
+//	FormulaNodeRange    | Range reference without worksheet name, such as A1:B2,
+//	                    | A:A or 1:3
+//	FormulaNodeName     | Defined name, structured reference or other name
+//	FormulaNodeFunction | Function name, the children are the arguments
+//	FormulaNodeOperator | Operator, such as +, -, *, /, ^, &, =, <>, <, >, <=,
+//	                    | >=, %, the range operator :, the union operator ,
+//	                    | and the intersection operator " ". The children are
+//	                    | the operands, the operator is the prefix or postfix
+//	                    | (%) operator when it has only one child
+//	FormulaNodeParen    | Empty string, the child is the expression in the
+//	                    | parentheses
+//	FormulaNodeArray    | Empty string, the children are the rows of array
+//	FormulaNodeArrayRow | Empty string, the children are the elements of the
+//	                    | array row
+//
+// The Sheet field is the worksheet name of the reference or name without
+// quotation marks, which may start with the external workbook index, such
+// as [1]Sheet1, or be a 3-D reference worksheets range, such as
+// Sheet1:Sheet3.
+type FormulaNode struct {
+	Type     FormulaNodeType
+	Value    string
+	Sheet    string
+	Children []*FormulaNode
+}
+
+// FormulaAST directly maps the abstract syntax tree of the formula.
+type FormulaAST struct {
+	Root *FormulaNode
+}
+
+// formulaParser is the parser to build the formula abstract syntax tree.
+type formulaParser struct {
+	tokens []efp.Token
+	pos    int
+}
+
+var (
+	// formulaCellRefExp is the regular expression for matching the cell
+	// reference in the formula.
+	formulaCellRefExp = regexp.MustCompile(`^\$?[A-Za-z]{1,3}\$?[0-9]+$`)
+	// formulaColRefExp is the regular expression for matching the column
+	// reference in the formula.
+	formulaColRefExp = regexp.MustCompile(`^\$?[A-Za-z]{1,3}$`)
+	// formulaRowRefExp is the regular expression for matching the row
+	// reference in the formula.
+	formulaRowRefExp = regexp.MustCompile(`^\$?[0-9]+$`)
+	// formulaInfixOperatorPrecedence defined the precedence of the infix
+	// operators in the formula.
+	formulaInfixOperatorPrecedence = map[string]int{
+		"=": 1, "<>": 1, "<": 1, ">": 1, "<=": 1, ">=": 1,
+		"&": 2, "+": 3, "-": 3, "*": 4, "/": 4, "^": 5,
+		",": 8, " ": 9, ":": 10,
+	}
+)
+
+// Formula operators precedence of the postfix and prefix operators.
+const (
+	formulaPostfixOperatorPrecedence = 6
+	formulaPrefixOperatorPrecedence  = 7
+)
+
+// ParseFormula provides a function to parse the formula into the abstract
+// syntax tree, which contains the nodes of the references, ranges, names,
+// functions, operators and literals, the String function of the abstract
+// syntax tree renders the formula back to the string. The formula could be
+// started with or without the equal sign, and the rendered formula is
+// without the equal sign. For example, move the references on Sheet1 to
+// Sheet2 in the formula:
+//
+//	ast, err := excelize.ParseFormula("SUM(Sheet1!A1:A10)+'Sheet 3'!B2")
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	ast.Walk(func(node *excelize.FormulaNode) bool {
+//	    if node.Sheet == "Sheet1" {
+//	        node.Sheet = "Sheet2"
+//	    }
+//	    return true
+//	})
+//	fmt.Println(ast.String()) // SUM(Sheet2!A1:A10)+'Sheet 3'!B2
+func ParseFormula(expr string) (*FormulaAST, error) {
+	expr = strings.TrimPrefix(strings.TrimSpace(expr), "=")
+	if expr == "" {
+		return nil, ErrInvalidFormula
+	}
+	expr, structuredRefs := replaceFormulaStructuredRefs(expr)
+	ps := efp.ExcelParser()
+	p := &formulaParser{}
+	for _, token := range ps.Parse(expr) {
+		if token.TType == efp.TokenTypeUnknown {
+			return nil, ErrInvalidFormula
+		}
+		if token.TType == efp.TokenTypeOperand {
+			token.TValue = structuredRefs.Replace(token.TValue)
+		}
+		p.tokens = append(p.tokens, splitFormulaRangeToken(token)...)
+	}
+	root, err := p.parse(0)
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.tokens) {
+		return nil, ErrInvalidFormula
+	}
+	return &FormulaAST{Root: root}, err
+}
+
+// String provides a function to render the formula abstract syntax tree back
+// to the formula without the equal sign.
+func (ast *FormulaAST) String() string {
+	if ast == nil || ast.Root == nil {
+		return ""
+	}
+	return ast.Root.String()
+}
+
+// Walk provides a function to traverse the nodes of the formula abstract
+// syntax tree in depth-first order by given callback function, and the
+// children of the node will be skipped if the callback function returns
+// false. The nodes could be modified in the callback function.
+func (ast *FormulaAST) Walk(fn func(node *FormulaNode) bool) {
+	if ast != nil && ast.Root != nil {
+		ast.Root.walk(fn)
+	}
+}
+
+// walk provides a function to traverse the node and the children of the node
+// by given callback function.
+func (n *FormulaNode) walk(fn func(node *FormulaNode) bool) {
+	if n == nil || !fn(n) {
+		return
+	}
+	for _, child := range n.Children {
+		child.walk(fn)
+	}
+}
+
+// String provides a function to render the node of the formula abstract
+// syntax tree to the formula string.
+func (n *FormulaNode) String() string {
+	if n == nil {
+		return ""
+	}
+	children := make([]string, len(n.Children))
+	for i, child := range n.Children {
+		children[i] = child.String()
+	}
+	switch n.Type {
+	case FormulaNodeText:
+		return "\"" + strings.ReplaceAll(n.Value, "\"", "\"\"") + "\""
+	case FormulaNodeRef, FormulaNodeRange, FormulaNodeName:
+		if n.Sheet == "" {
+			return n.Value
+		}
+		return formatFormulaSheetName(n.Sheet) + "!" + n.Value
+	case FormulaNodeFunction:
+		return n.Value + "(" + strings.Join(children, ",") + ")"
+	case FormulaNodeOperator:
+		if len(children) == 1 {
+			if n.Value == "%" {
+				return children[0] + n.Value
+			}
+			return n.Value + children[0]
+		}
+		return strings.Join(children, n.Value)
+	case FormulaNodeParen:
+		return "(" + strings.Join(children, "") + ")"
+	case FormulaNodeArray:
+		return "{" + strings.Join(children, ";") + "}"
+	case FormulaNodeArrayRow:
+		return strings.Join(children, ",")
+	}
+	return n.Value
+}
+
+// formatFormulaSheetName provides a function to enclose the worksheet name of
+// the reference in single quotation marks if the worksheet name includes
+// spaces or non-alphabetical characters, the external workbook index of the
+// worksheet name will be kept.
+func formatFormulaSheetName(sheet string) string {
+	var book string
+	if strings.HasPrefix(sheet, "[") {
+		if idx := strings.Index(sheet, "]"); idx != -1 {
+			book, sheet = sheet[:idx+1], sheet[idx+1:]
+		}
+	}
+	var sheets []string
+	for _, name := range strings.Split(sheet, ":") {
+		sheets = append(sheets, escapeSheetName(name))
+	}
+	if name := strings.Join(sheets, ":"); name != sheet {
+		return "'" + book + strings.ReplaceAll(sheet, "'", "''") + "'"
+	}
+	return book + sheet
+}
+
+// replaceFormulaStructuredRefs provides a function to replace the structured
+// references in the formula with the placeholders, because the structured
+// references with nested brackets can't be tokenized. Returns the replaced
+// formula and the replacer for restoring the structured references.
+func replaceFormulaStructuredRefs(formula string) (string, *strings.Replacer) {
+	var (
+		result    strings.Builder
+		oldNew    []string
+		replaceFn = func(expr string) string {
+			var (
+				buf  strings.Builder
+				last int
+			)
+			for _, match := range structuredRefExp.FindAllStringSubmatchIndex(expr, -1) {
+				if next := match[1]; match[2] == -1 && next < len(expr) &&
+					(expr[next] == '!' || expr[next] == '_' || unicode.IsLetter(rune(expr[next])) || unicode.IsDigit(rune(expr[next]))) {
+					continue // external workbook reference, such as [1]Sheet1!A1
+				}
+				placeholder := fmt.Sprintf("EXCELIZESTRUCTUREDREF%dX", len(oldNew)/2)
+				oldNew = append(oldNew, placeholder, expr[match[0]:match[1]])
+				buf.WriteString(expr[last:match[0]])
+				buf.WriteString(placeholder)
+				last = match[1]
+			}
+			buf.WriteString(expr[last:])
+			return buf.String()
+		}
+	)
+	if !strings.Contains(formula, "[") {
+		return formula, strings.NewReplacer()
+	}
+	for len(formula) > 0 {
+		idx := strings.IndexAny(formula, `"'`)
+		if idx == -1 {
+			idx = len(formula)
+		}
+		result.WriteString(replaceFn(formula[:idx]))
+		if formula = formula[idx:]; len(formula) == 0 {
+			break
+		}
+		end := strings.IndexByte(formula[1:], formula[0]) + 2
+		if end == 1 {
+			end = len(formula)
+		}
+		result.WriteString(formula[:end])
+		formula = formula[end:]
+	}
+	return result.String(), strings.NewReplacer(oldNew...)
+}
+
+// splitFormulaRangeToken provides a function to split the range operator
+// from the token which merged by the tokenizer, such as the function name
+// "A1:INDEX" of the formula A1:INDEX(B:B,2), and the range operand ":B2" of
+// the formula INDEX(A:A,1):B2.
+func splitFormulaRangeToken(token efp.Token) []efp.Token {
+	rangeOperator := efp.Token{TValue: ":", TType: efp.TokenTypeOperatorInfix, TSubType: efp.TokenSubTypeRange}
+	if token.TType == efp.TokenTypeFunction && token.TSubType == efp.TokenSubTypeStart {
+		if idx := strings.LastIndex(token.TValue, ":"); idx != -1 {
+			return []efp.Token{
+				{TValue: token.TValue[:idx], TType: efp.TokenTypeOperand, TSubType: efp.TokenSubTypeRange},
+				rangeOperator,
+				{TValue: token.TValue[idx+1:], TType: efp.TokenTypeFunction, TSubType: efp.TokenSubTypeStart},
+			}
+		}
+	}
+	if token.TType == efp.TokenTypeOperand && token.TSubType == efp.TokenSubTypeRange && strings.HasPrefix(token.TValue, ":") {
+		return []efp.Token{rangeOperator, {TValue: token.TValue[1:], TType: efp.TokenTypeOperand, TSubType: efp.TokenSubTypeRange}}
+	}
+	return []efp.Token{token}
+}
+
+// peek returns the current token of the formula parser, and returns false if
+// there are no more tokens.
+func (p *formulaParser) peek() (efp.Token, bool) {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos], true
+	}
+	return efp.Token{}, false
+}
+
+// parse provides a function to parse the expression which infix operators
+// precedence not less than the given precedence.
+func (p *formulaParser) parse(precedence int) (*FormulaNode, error) {
+	left, err := p.parsePrimary()
+	if err != nil {
+		return left, err
+	}
+	for {
+		token, ok := p.peek()
+		if !ok {
+			return left, err
+		}
+		if token.TType == efp.TokenTypeOperatorPostfix {
+			if formulaPostfixOperatorPrecedence < precedence {
+				return left, err
+			}
+			p.pos++
+			left = &FormulaNode{Type: FormulaNodeOperator, Value: token.TValue, Children: []*FormulaNode{left}}
+			continue
+		}
+		if token.TType != efp.TokenTypeOperatorInfix {
+			return left, err
+		}
+		operator := token.TValue
+		if token.TSubType == efp.TokenSubTypeIntersection {
+			operator = " "
+		}
+		opPrecedence, ok := formulaInfixOperatorPrecedence[operator]
+		if !ok {
+			return left, ErrInvalidFormula
+		}
+		if opPrecedence < precedence {
+			return left, err
+		}
+		p.pos++
+		right, err := p.parse(opPrecedence + 1)
+		if err != nil {
+			return left, err
+		}
+		left = &FormulaNode{Type: FormulaNodeOperator, Value: operator, Children: []*FormulaNode{left, right}}
+	}
+}
+
+// parsePrimary provides a function to parse the operand, prefix operator,
+// parentheses, function or array of the formula.
+func (p *formulaParser) parsePrimary() (*FormulaNode, error) {
+	token, ok := p.peek()
+	if !ok {
+		return nil, ErrInvalidFormula
+	}
+	p.pos++
+	switch token.TType {
+	case efp.TokenTypeOperand:
+		return newFormulaOperandNode(token)
+	case efp.TokenTypeOperatorPrefix:
+		operand, err := p.parse(formulaPrefixOperatorPrecedence)
+		return &FormulaNode{Type: FormulaNodeOperator, Value: token.TValue, Children: []*FormulaNode{operand}}, err
+	case efp.TokenTypeSubexpression:
+		if token.TSubType != efp.TokenSubTypeStart {
+			return nil, ErrInvalidFormula
+		}
+		expr, err := p.parse(0)
+		if err != nil {
+			return nil, err
+		}
+		if token, ok = p.peek(); !ok || token.TType != efp.TokenTypeSubexpression || token.TSubType != efp.TokenSubTypeStop {
+			return nil, ErrInvalidFormula
+		}
+		p.pos++
+		return &FormulaNode{Type: FormulaNodeParen, Children: []*FormulaNode{expr}}, err
+	case efp.TokenTypeFunction:
+		if token.TSubType != efp.TokenSubTypeStart {
+			return nil, ErrInvalidFormula
+		}
+		return p.parseFunction(token)
+	}
+	return nil, ErrInvalidFormula
+}
+
+// parseFunction provides a function to parse the arguments of the function or
+// the elements of the array by given function start token.
+func (p *formulaParser) parseFunction(start efp.Token) (*FormulaNode, error) {
+	node := &FormulaNode{Type: FormulaNodeFunction, Value: start.TValue}
+	switch start.TValue {
+	case "ARRAY":
+		node.Type, node.Value = FormulaNodeArray, ""
+	case "ARRAYROW":
+		node.Type, node.Value = FormulaNodeArrayRow, ""
+	}
+	if token, ok := p.peek(); ok && isFunctionStop(token) {
+		p.pos++
+		return node, nil
+	}
+	for {
+		arg := &FormulaNode{Type: FormulaNodeEmpty}
+		token, ok := p.peek()
+		if !ok {
+			return nil, ErrInvalidFormula
+		}
+		if token.TType != efp.TokenTypeArgument && !isFunctionStop(token) {
+			var err error
+			if arg, err = p.parse(0); err != nil {
+				return nil, err
+			}
+		}
+		node.Children = append(node.Children, arg)
+		if token, ok = p.peek(); !ok {
+			return nil, ErrInvalidFormula
+		}
+		p.pos++
+		if isFunctionStop(token) {
+			return node, nil
+		}
+		if token.TType != efp.TokenTypeArgument {
+			return nil, ErrInvalidFormula
+		}
+	}
+}
+
+// newFormulaOperandNode provides a function to create the node of the formula
+// abstract syntax tree by given operand token.
+func newFormulaOperandNode(token efp.Token) (*FormulaNode, error) {
+	switch token.TSubType {
+	case efp.TokenSubTypeNumber:
+		return &FormulaNode{Type: FormulaNodeNumber, Value: token.TValue}, nil
+	case efp.TokenSubTypeText:
+		return &FormulaNode{Type: FormulaNodeText, Value: token.TValue}, nil
+	case efp.TokenSubTypeLogical:
+		return &FormulaNode{Type: FormulaNodeLogical, Value: strings.ToUpper(token.TValue)}, nil
+	case efp.TokenSubTypeError:
+		return &FormulaNode{Type: FormulaNodeError, Value: token.TValue}, nil
+	}
+	node := &FormulaNode{Type: FormulaNodeName, Value: token.TValue}
+	if !strings.HasPrefix(node.Value, "[") || strings.Contains(node.Value, "!") {
+		if idx := strings.LastIndex(node.Value, "!"); idx != -1 {
+			node.Sheet, node.Value = node.Value[:idx], node.Value[idx+1:]
+		}
+	}
+	if node.Value == "" || strings.HasSuffix(node.Value, ":") {
+		return nil, ErrInvalidFormula
+	}
+	if formulaCellRefExp.MatchString(node.Value) {
+		node.Type = FormulaNodeRef
+		return node, nil
+	}
+	if parts := strings.Split(node.Value, ":"); len(parts) == 2 {
+		for _, exp := range []*regexp.Regexp{formulaCellRefExp, formulaColRefExp, formulaRowRefExp} {
+			if exp.MatchString(parts[0]) && exp.MatchString(parts[1]) {
+				node.Type = FormulaNodeRange
+				return node, nil
+			}
+		}
+	}
+	return node, nil
+}
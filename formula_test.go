@@ -0,0 +1,111 @@
+package excelize_ch
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseFormula(t *testing.T) {
+	for formula, expected := range map[string]string{
+		"=1+2*3":                             "1+2*3",
+		"SUM(A1:B2,Sheet1!$C$3)":             "SUM(A1:B2,Sheet1!$C$3)",
+		"-A1^2%":                             "-A1^2%",
+		"(A1+B1)*C1":                         "(A1+B1)*C1",
+		"IF(A1>=1,\"a\"\"b\",FALSE)":         "IF(A1>=1,\"a\"\"b\",FALSE)",
+		"'My Sheet'!A1&'Bob''s'!B:B":         "'My Sheet'!A1&'Bob''s'!B:B",
+		"SUM(Sheet1:Sheet3!B2)":              "SUM(Sheet1:Sheet3!B2)",
+		"[1]Sheet1!A1+'[2]My Sheet'!1:3":     "[1]Sheet1!A1+'[2]My Sheet'!1:3",
+		"{1,2;3,4}":                          "{1,2;3,4}",
+		"A1:INDEX(B:B,2)":                    "A1:INDEX(B:B,2)",
+		"INDEX(A:A,1):B2":                    "INDEX(A:A,1):B2",
+		"SUM(Table1[[#Totals],[Sales]])":     "SUM(Table1[[#Totals],[Sales]])",
+		"NOW()+IF(A1,,1)":                    "NOW()+IF(A1,,1)",
+		"SUM(A1:C3 B2:D4)":                   "SUM(A1:C3 B2:D4)",
+		"SUM((A1,B2))":                       "SUM((A1,B2))",
+		"MyName*#N/A":                        "MyName*#N/A",
+		"\"'[a]'\"&Table1[@Sales]":           "\"'[a]'\"&Table1[@Sales]",
+		"1-2-3":                              "1-2-3",
+		"_xlfn.CONCAT(\"a\",\"b\")":          "_xlfn.CONCAT(\"a\",\"b\")",
+		"A1=B1":                              "A1=B1",
+		"SUM(1,2)<>3":                        "SUM(1,2)<>3",
+		"{\"a\",TRUE}":                       "{\"a\",TRUE}",
+		"'Sheet 1:Sheet 3'!A1+[1]!DefName+1": "'Sheet 1:Sheet 3'!A1+[1]!DefName+1",
+	} {
+		ast, err := ParseFormula(formula)
+		assert.NoError(t, err, formula)
+		assert.Equal(t, expected, ast.String(), formula)
+	}
+	// Test parse formula and check the nodes
+	ast, err := ParseFormula("=SUM('My Sheet'!A1:B2,C3,D:D)*-2+Name1")
+	assert.NoError(t, err)
+	assert.Equal(t, &FormulaNode{Type: FormulaNodeOperator, Value: "+", Children: []*FormulaNode{
+		{Type: FormulaNodeOperator, Value: "*", Children: []*FormulaNode{
+			{Type: FormulaNodeFunction, Value: "SUM", Children: []*FormulaNode{
+				{Type: FormulaNodeRange, Value: "A1:B2", Sheet: "My Sheet"},
+				{Type: FormulaNodeRef, Value: "C3"},
+				{Type: FormulaNodeRange, Value: "D:D"},
+			}},
+			{Type: FormulaNodeOperator, Value: "-", Children: []*FormulaNode{
+				{Type: FormulaNodeNumber, Value: "2"},
+			}},
+		}},
+		{Type: FormulaNodeName, Value: "Name1"},
+	}}, ast.Root)
+	// Test parse formula with operators precedence
+	ast, err = ParseFormula("1+2*3^4&5")
+	assert.NoError(t, err)
+	assert.Equal(t, "&", ast.Root.Value)
+	assert.Equal(t, "+", ast.Root.Children[0].Value)
+	assert.Equal(t, "*", ast.Root.Children[0].Children[1].Value)
+	assert.Equal(t, "^", ast.Root.Children[0].Children[1].Children[1].Value)
+	// Test parse formula with the array and empty arguments
+	ast, err = ParseFormula("IF(,{1,2;3,4})")
+	assert.NoError(t, err)
+	assert.Equal(t, FormulaNodeEmpty, ast.Root.Children[0].Type)
+	assert.Equal(t, FormulaNodeArray, ast.Root.Children[1].Type)
+	assert.Len(t, ast.Root.Children[1].Children, 2)
+	assert.Equal(t, FormulaNodeArrayRow, ast.Root.Children[1].Children[0].Type)
+	// Test parse formula with the structured reference
+	ast, err = ParseFormula("Table1[[#This Row],[Sales]]*2")
+	assert.NoError(t, err)
+	assert.Equal(t, &FormulaNode{Type: FormulaNodeName, Value: "Table1[[#This Row],[Sales]]"}, ast.Root.Children[0])
+	// Test parse invalid formulas
+	for _, formula := range []string{
+		"", "=", "1)", "(1", "A1:", "SUM(1", "SUM(1,", "1+", "+", ")", "1 2(", "{1,2", "=1+)",
+	} {
+		_, err = ParseFormula(formula)
+		assert.Equal(t, ErrInvalidFormula, err, formula)
+	}
+	// Test render the empty abstract syntax tree
+	assert.Empty(t, (*FormulaAST)(nil).String())
+	assert.Empty(t, (&FormulaAST{}).String())
+	assert.Empty(t, (*FormulaNode)(nil).String())
+}
+
+func TestFormulaASTWalk(t *testing.T) {
+	ast, err := ParseFormula("SUM(Sheet1!A1:A10,'Sheet 2'!B2)+Sheet1!C3*IF(Sheet1!D4,1,2)")
+	assert.NoError(t, err)
+	// Test collect the dependencies of the formula
+	var refs []string
+	ast.Walk(func(node *FormulaNode) bool {
+		if node.Type == FormulaNodeRef || node.Type == FormulaNodeRange {
+			refs = append(refs, node.String())
+		}
+		return true
+	})
+	assert.Equal(t, []string{"Sheet1!A1:A10", "'Sheet 2'!B2", "Sheet1!C3", "Sheet1!D4"}, refs)
+	// Test rename the worksheet of the references and skip the children
+	ast.Walk(func(node *FormulaNode) bool {
+		if node.Type == FormulaNodeFunction && node.Value == "IF" {
+			return false
+		}
+		if node.Sheet == "Sheet1" {
+			node.Sheet = "Sales Data"
+		}
+		return true
+	})
+	assert.Equal(t, "SUM('Sales Data'!A1:A10,'Sheet 2'!B2)+'Sales Data'!C3*IF(Sheet1!D4,1,2)", ast.String())
+	// Test walk on the empty abstract syntax tree
+	(*FormulaAST)(nil).Walk(func(node *FormulaNode) bool { return true })
+}
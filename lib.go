@@ -21,9 +21,13 @@ import (
 	"math"
 	"math/big"
 	"os"
+	"path"
+	"path/filepath"
 	"regexp"
 	"strconv"
 	"strings"
+	"sync"
+	"unicode"
 )
 
 // ReadZipReader extract spreadsheet with given options.
@@ -48,7 +52,7 @@ func (f *File) ReadZipReader(r *zip.Reader) (map[string][]byte, int, error) {
 		if partName, ok := docPart[strings.ToLower(fileName)]; ok {
 			fileName = partName
 		}
-		if strings.EqualFold(fileName, defaultXMLPathSharedStrings) && fileSize > f.options.UnzipXMLSizeLimit {
+		if strings.EqualFold(fileName, defaultXMLPathSharedStrings) && (fileSize > f.options.UnzipXMLSizeLimit || f.options.SSTCacheSize > 0) {
 			tempFile, err := f.unzipToTemp(v)
 			if tempFile != "" {
 				f.tempFiles.Store(fileName, tempFile)
@@ -83,6 +87,7 @@ func (f *File) unzipToTemp(zipFile *zip.File) (string, error) {
 	if err != nil {
 		return "", err
 	}
+	f.logEvent(LogEvent{Type: LogEventTempFileCreated, Part: zipFile.Name, Size: zipFile.FileInfo().Size(), Path: tmp.Name()})
 	rc, err := zipFile.Open()
 	if err != nil {
 		return tmp.Name(), err
@@ -139,6 +144,142 @@ func (f *File) saveFileList(name string, content []byte) {
 	f.Pkg.Store(name, append([]byte(xml.Header), content...))
 }
 
+// getPartRelsPath provides a function to get the relationships part path of
+// the given part in the package.
+func getPartRelsPath(partName string) string {
+	return path.Join(path.Dir(partName), "_rels", path.Base(partName)+".rels")
+}
+
+// getRelsTargetPath provides a function to get the part name in the package
+// by given source part name and the relationship target.
+func getRelsTargetPath(partName, target string) string {
+	if strings.HasPrefix(target, "/") {
+		return strings.TrimPrefix(path.Clean(target), "/")
+	}
+	return strings.TrimPrefix(path.Join(path.Dir(partName), target), "/")
+}
+
+// getRelsTarget provides a function to get the relationship target relative
+// to the given source part by given source part name and target part name.
+func getRelsTarget(source, partName string) string {
+	rel, err := filepath.Rel(path.Dir(source), partName)
+	if err != nil {
+		return "/" + partName
+	}
+	return filepath.ToSlash(rel)
+}
+
+// getUniquePartName provides a function to get the part name which doesn't
+// exist in the package by given part name, the number at the end of the part
+// name will be increased if the part already exists, for example,
+// xl/drawings/drawing2.xml will be returned for xl/drawings/drawing1.xml.
+func (f *File) getUniquePartName(partName string) string {
+	if !f.hasPart(partName) {
+		return partName
+	}
+	ext := path.Ext(partName)
+	base := strings.TrimRightFunc(strings.TrimSuffix(partName, ext), unicode.IsDigit)
+	for num := 1; ; num++ {
+		if name := base + strconv.Itoa(num) + ext; !f.hasPart(name) {
+			return name
+		}
+	}
+}
+
+// deletePart provides a function to delete the part, the relationships part
+// of it and the content type override of it by given part name.
+func (f *File) deletePart(partName string) error {
+	relsPath := getPartRelsPath(partName)
+	f.Pkg.Delete(partName)
+	f.Pkg.Delete(relsPath)
+	f.rawParts.Delete(partName)
+	f.rawParts.Delete(relsPath)
+	f.Sheet.Delete(partName)
+	f.Drawings.Delete(partName)
+	delete(f.Comments, partName)
+	delete(f.VMLDrawing, partName)
+	delete(f.DecodeVMLDrawing, partName)
+	f.Relationships.Delete(relsPath)
+	content, err := f.contentTypesReader()
+	if err != nil {
+		return err
+	}
+	content.mu.Lock()
+	defer content.mu.Unlock()
+	for i := 0; i < len(content.Overrides); i++ {
+		if strings.TrimPrefix(content.Overrides[i].PartName, "/") == partName {
+			content.Overrides = append(content.Overrides[:i], content.Overrides[i+1:]...)
+			i--
+		}
+	}
+	return err
+}
+
+// removeUnreachableParts provides a function to remove the parts which can't
+// be reached from the package relationships, the relationships parts of the
+// removed parts will be removed too.
+func (f *File) removeUnreachableParts() error {
+	reachable := map[string]struct{}{defaultXMLPathContentTypes: {}, "_rels/.rels": {}}
+	queue := []string{""}
+	for len(queue) > 0 {
+		partName := queue[0]
+		queue = queue[1:]
+		relsPath := "_rels/.rels"
+		if partName != "" {
+			relsPath = getPartRelsPath(partName)
+		}
+		rels, err := f.relsReader(relsPath)
+		if err != nil {
+			return err
+		}
+		if rels == nil {
+			continue
+		}
+		reachable[relsPath] = struct{}{}
+		for _, rel := range rels.Relationships {
+			if rel.TargetMode == "External" {
+				continue
+			}
+			target := getRelsTargetPath(partName, rel.Target)
+			if _, ok := reachable[target]; !ok {
+				reachable[target] = struct{}{}
+				queue = append(queue, target)
+			}
+		}
+	}
+	var parts []string
+	for _, m := range []*sync.Map{&f.Pkg, &f.tempFiles} {
+		m.Range(func(k, _ interface{}) bool {
+			if _, ok := reachable[k.(string)]; !ok {
+				parts = append(parts, k.(string))
+			}
+			return true
+		})
+	}
+	for _, partName := range parts {
+		if err := f.deletePart(partName); err != nil {
+			return err
+		}
+		if tempFile, ok := f.tempFiles.Load(partName); ok {
+			f.tempFiles.Delete(partName)
+			_ = os.Remove(tempFile.(string))
+		}
+	}
+	content, err := f.contentTypesReader()
+	if err != nil {
+		return err
+	}
+	content.mu.Lock()
+	defer content.mu.Unlock()
+	for i := 0; i < len(content.Overrides); i++ {
+		if _, ok := reachable[strings.TrimPrefix(content.Overrides[i].PartName, "/")]; !ok {
+			content.Overrides = append(content.Overrides[:i], content.Overrides[i+1:]...)
+			i--
+		}
+	}
+	return err
+}
+
 // Read file content as string in an archive file.
 func readFile(file *zip.File) ([]byte, error) {
 	rc, err := file.Open()
@@ -691,6 +832,25 @@ func (f *File) replaceNameSpaceBytes(path string, contentMarshal []byte) []byte
 	return bytesReplace(contentMarshal, sourceXmlns, bytes.ReplaceAll(targetXmlns, []byte(" mc:Ignorable=\"r\""), []byte{}), -1)
 }
 
+// replaceRevisionBytes provides a function to replace the namespace prefixes
+// of the revision identifier attributes generated by the XML encoder with the
+// xr, xr2 and xr3 prefixes, and returns the namespaces which should be declared
+// in the root element of the part.
+func replaceRevisionBytes(content []byte) ([]byte, []xml.Attr) {
+	var nameSpaces []xml.Attr
+	for _, ns := range []xml.Attr{NameSpaceSpreadSheetXR, NameSpaceSpreadSheetXR2, NameSpaceSpreadSheetXR3} {
+		prefix := ns.Value[strings.LastIndex(ns.Value, "/")+1:]
+		sourceXmlns := []byte(fmt.Sprintf(" xmlns:%s=\"%s\"", prefix, ns.Value))
+		if !bytes.Contains(content, sourceXmlns) {
+			continue
+		}
+		nameSpaces = append(nameSpaces, ns)
+		content = bytesReplace(bytesReplace(content, sourceXmlns, []byte{}, -1),
+			[]byte(fmt.Sprintf(" %s:uid=", prefix)), []byte(fmt.Sprintf(" %s:uid=", ns.Name.Local)), -1)
+	}
+	return content, nameSpaces
+}
+
 // addNameSpaces provides a function to add an XML attribute by the given
 // component part path.
 func (f *File) addNameSpaces(path string, ns xml.Attr) {
@@ -862,6 +1022,57 @@ func continuedFraction(n float64, i int64, limit int64, prec float64) *big.Rat {
 	return res
 }
 
+// lruCache defined a concurrency safe least recently used cache for the
+// values indexed by integer keys.
+type lruCache struct {
+	mu       sync.Mutex
+	capacity int
+	items    map[int]*list.Element
+	order    *list.List
+}
+
+// lruCacheEntry directly maps the key and value of the item in the least
+// recently used cache.
+type lruCacheEntry struct {
+	key   int
+	value string
+}
+
+// newLRUCache create a new least recently used cache by given capacity.
+func newLRUCache(capacity int) *lruCache {
+	return &lruCache{capacity: capacity, items: make(map[int]*list.Element), order: list.New()}
+}
+
+// get the value of the item by given key, and mark the item as the most
+// recently used.
+func (c *lruCache) get(key int) (string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if e, ok := c.items[key]; ok {
+		c.order.MoveToFront(e)
+		return e.Value.(*lruCacheEntry).value, true
+	}
+	return "", false
+}
+
+// put the value of the item by given key into the cache, and evict the least
+// recently used item if the cache exceeds the capacity.
+func (c *lruCache) put(key int, value string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if e, ok := c.items[key]; ok {
+		c.order.MoveToFront(e)
+		e.Value.(*lruCacheEntry).value = value
+		return
+	}
+	c.items[key] = c.order.PushFront(&lruCacheEntry{key: key, value: value})
+	if c.order.Len() > c.capacity {
+		e := c.order.Back()
+		c.order.Remove(e)
+		delete(c.items, e.Value.(*lruCacheEntry).key)
+	}
+}
+
 // Stack defined an abstract data type that serves as a collection of elements.
 type Stack struct {
 	list *list.List
@@ -307,6 +307,26 @@ func TestStack(t *testing.T) {
 	assert.Equal(t, s.Pop(), nil)
 }
 
+func TestLRUCache(t *testing.T) {
+	c := newLRUCache(2)
+	c.put(1, "a")
+	c.put(2, "b")
+	value, ok := c.get(1)
+	assert.True(t, ok)
+	assert.Equal(t, "a", value)
+	c.put(3, "c")
+	_, ok = c.get(2)
+	assert.False(t, ok)
+	c.put(1, "d")
+	value, ok = c.get(1)
+	assert.True(t, ok)
+	assert.Equal(t, "d", value)
+	value, ok = c.get(3)
+	assert.True(t, ok)
+	assert.Equal(t, "c", value)
+	assert.Equal(t, 2, c.order.Len())
+}
+
 func TestGenXMLNamespace(t *testing.T) {
 	assert.Equal(t, genXMLNamespace([]xml.Attr{
 		{Name: xml.Name{Space: NameSpaceXML, Local: "space"}, Value: "preserve"},
@@ -0,0 +1,119 @@
+// Copyright 2016 - 2023 The excelize Authors. All rights reserved. Use of
+// this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+//
+// package excelize_ch providing a set of functions that allow you to write to and
+// read from XLAM / XLSM / XLSX / XLTM / XLTX files. Supports reading and
+// writing spreadsheet documents generated by Microsoft Excel™ 2007 and later.
+// Supports complex components by high compatibility, and provided streaming
+// API for generating or reading data from a worksheet with huge amounts of
+// data. This library needs Go version 1.16 or later.
+
+package excelize_ch
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// LogEventType is the type of the structured events reported to the Logger.
+type LogEventType byte
+
+// This section defines the currently supported log event types.
+const (
+	LogEventPartParsed LogEventType = iota
+	LogEventRepairPerformed
+	LogEventFeaturePreserved
+	LogEventTempFileCreated
+)
+
+// String returns the description of the log event type.
+func (t LogEventType) String() string {
+	switch t {
+	case LogEventPartParsed:
+		return "part parsed"
+	case LogEventRepairPerformed:
+		return "repair performed"
+	case LogEventFeaturePreserved:
+		return "feature preserved but unsupported"
+	case LogEventTempFileCreated:
+		return "temp file created"
+	}
+	return fmt.Sprintf("LogEventType(%d)", t)
+}
+
+// LogEvent directly maps the structured event reported to the Logger. The
+// Part is the package part name related to the event, the Size is the size
+// of the part in bytes, the Duration is the time spent on parsing the part,
+// the Path is the path of the created temporary file, and the Message
+// describes the repair performed or the feature preserved.
+type LogEvent struct {
+	Type     LogEventType
+	Part     string
+	Size     int64
+	Duration time.Duration
+	Path     string
+	Message  string
+}
+
+// Logger is the interface for receiving the structured events on opening and
+// reading the spreadsheet, which could be used to trace why a spreadsheet is
+// slow to open or altered after saving. The Log function may be called from
+// multiple goroutines concurrently.
+type Logger interface {
+	Log(event LogEvent)
+}
+
+// unsupportedContentTypes defined the content types of the package parts for
+// the features which are preserved as-is on saving the spreadsheet, but not
+// supported by the library.
+var unsupportedContentTypes = map[string]string{
+	"application/vnd.ms-excel.rdrichvalue+xml":                                        "rich data values",
+	"application/vnd.ms-office.activeX+xml":                                           "ActiveX controls",
+	"application/vnd.ms-office.webextension+xml":                                      "web extensions",
+	"application/vnd.openxmlformats-officedocument.customXmlProperties+xml":           "custom XML parts",
+	"application/vnd.openxmlformats-officedocument.spreadsheetml.connections+xml":     "data connections",
+	"application/vnd.openxmlformats-officedocument.spreadsheetml.dialogsheet+xml":     "dialog sheets",
+	"application/vnd.openxmlformats-officedocument.spreadsheetml.queryTable+xml":      "query tables",
+	"application/vnd.openxmlformats-officedocument.spreadsheetml.revisionHeaders+xml": "shared workbook revisions",
+	ContentTypeIntlMacrosheet:                                                         "international macro sheets",
+	ContentTypeMacrosheet:                                                             "macro sheets",
+}
+
+// logEvent provides a function to report the event to the Logger if it has
+// been specified in the options.
+func (f *File) logEvent(event LogEvent) {
+	if f.options != nil && f.options.Logger != nil {
+		f.options.Logger.Log(event)
+	}
+}
+
+// logPartParsed provides a function to report the part parsed event by given
+// part name, size of the part and the start time of parsing.
+func (f *File) logPartParsed(part string, size int, start time.Time) {
+	f.logEvent(LogEvent{Type: LogEventPartParsed, Part: part, Size: int64(size), Duration: time.Since(start)})
+}
+
+// logPreservedFeatures provides a function to report the features of the
+// package parts which are preserved but unsupported.
+func (f *File) logPreservedFeatures() error {
+	if f.options == nil || f.options.Logger == nil {
+		return nil
+	}
+	content, err := f.contentTypesReader()
+	if err != nil {
+		return err
+	}
+	content.mu.Lock()
+	defer content.mu.Unlock()
+	for _, override := range content.Overrides {
+		if feature, ok := unsupportedContentTypes[override.ContentType]; ok {
+			f.logEvent(LogEvent{
+				Type: LogEventFeaturePreserved, Part: strings.TrimPrefix(override.PartName, "/"),
+				Message: feature,
+			})
+		}
+	}
+	return err
+}
@@ -0,0 +1,103 @@
+package excelize_ch
+
+import (
+	"encoding/xml"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testLogger struct {
+	mu     sync.Mutex
+	events []LogEvent
+}
+
+func (l *testLogger) Log(event LogEvent) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.events = append(l.events, event)
+}
+
+func (l *testLogger) find(typ LogEventType, part string) *LogEvent {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for i := range l.events {
+		if l.events[i].Type == typ && l.events[i].Part == part {
+			return &l.events[i]
+		}
+	}
+	return nil
+}
+
+func TestLogger(t *testing.T) {
+	f := NewFile()
+	assert.NoError(t, f.SetCellValue("Sheet1", "A1", "text"))
+	content, err := f.contentTypesReader()
+	assert.NoError(t, err)
+	content.Overrides = append(content.Overrides, xlsxOverride{
+		PartName: "/xl/activeX/activeX1.xml", ContentType: "application/vnd.ms-office.activeX+xml",
+	})
+	f.Pkg.Store("xl/activeX/activeX1.xml", []byte(xml.Header+`<ax:ocx xmlns:ax="http://schemas.microsoft.com/office/2006/activeX"/>`))
+	path := filepath.Join("test", "TestLogger.xlsx")
+	assert.NoError(t, f.SaveAs(path))
+	assert.NoError(t, f.Close())
+
+	logger := &testLogger{}
+	f, err = OpenFile(path, Options{Logger: logger, UnzipXMLSizeLimit: 128})
+	assert.NoError(t, err)
+	assert.NotNil(t, logger.find(LogEventPartParsed, defaultXMLPathStyles))
+	assert.NotNil(t, logger.find(LogEventPartParsed, "xl/workbook.xml"))
+	event := logger.find(LogEventTempFileCreated, "xl/worksheets/sheet1.xml")
+	assert.NotNil(t, event)
+	assert.NotEmpty(t, event.Path)
+	event = logger.find(LogEventFeaturePreserved, "xl/activeX/activeX1.xml")
+	assert.NotNil(t, event)
+	assert.Equal(t, "ActiveX controls", event.Message)
+	val, err := f.GetCellValue("Sheet1", "A1")
+	assert.NoError(t, err)
+	assert.Equal(t, "text", val)
+	event = logger.find(LogEventPartParsed, "xl/worksheets/sheet1.xml")
+	assert.NotNil(t, event)
+	assert.Greater(t, event.Size, int64(0))
+	assert.NotNil(t, logger.find(LogEventPartParsed, defaultXMLPathSharedStrings))
+	assert.Nil(t, logger.find(LogEventRepairPerformed, "xl/worksheets/sheet1.xml"))
+	assert.NoError(t, f.Close())
+
+	// Test report the repair performed on the malformed worksheet
+	logger = &testLogger{}
+	f = NewFile(Options{Logger: logger})
+	f.Sheet.Delete("xl/worksheets/sheet1.xml")
+	f.checked = sync.Map{}
+	f.Pkg.Store("xl/worksheets/sheet1.xml", []byte(`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData><row r="2"><c r="A2"><v>2</v></c></row><row r="1"><c r="B1"><v>1</v></c><c r="A1"><v>0</v></c></row></sheetData></worksheet>`))
+	rows, err := f.GetRows("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{{"0", "1"}, {"2"}}, rows)
+	assert.NotNil(t, logger.find(LogEventRepairPerformed, "xl/worksheets/sheet1.xml"))
+	assert.NoError(t, f.Close())
+
+	// Test report the temporary file created for the shared string table
+	logger = &testLogger{}
+	f, err = OpenFile(path, Options{Logger: logger, SSTCacheSize: 1})
+	assert.NoError(t, err)
+	assert.NotNil(t, logger.find(LogEventTempFileCreated, defaultXMLPathSharedStrings))
+	assert.NoError(t, f.Close())
+
+	// Test open spreadsheet with unsupported charset content types
+	f = NewFile(Options{Logger: &testLogger{}})
+	f.ContentTypes = nil
+	f.Pkg.Store(defaultXMLPathContentTypes, MacintoshCyrillicCharset)
+	assert.EqualError(t, f.logPreservedFeatures(), "XML syntax error on line 1: invalid UTF-8")
+	assert.NoError(t, f.Close())
+
+	for typ, str := range map[LogEventType]string{
+		LogEventPartParsed:          "part parsed",
+		LogEventRepairPerformed:     "repair performed",
+		LogEventFeaturePreserved:    "feature preserved but unsupported",
+		LogEventTempFileCreated:     "temp file created",
+		LogEventTempFileCreated + 1: "LogEventType(4)",
+	} {
+		assert.Equal(t, str, typ.String())
+	}
+}
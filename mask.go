@@ -0,0 +1,222 @@
+// Copyright 2016 - 2023 The excelize Authors. All rights reserved. Use of
+// this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+//
+// package excelize_ch providing a set of functions that allow you to write to and
+// read from XLAM / XLSM / XLSX / XLTM / XLTX files. Supports reading and
+// writing spreadsheet documents generated by Microsoft Excel™ 2007 and later.
+// Supports complex components by high compatibility, and provided streaming
+// API for generating or reading data from a worksheet with huge amounts of
+// data. This library needs Go version 1.16 or later.
+
+package excelize_ch
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// DataMaskOptions directly maps the settings of the data masking of the
+// columns.
+type DataMaskOptions struct {
+	Columns   string
+	StartRow  int
+	KeepLeft  int
+	KeepRight int
+	MaskChar  string
+	Truncate  bool
+	Protect   bool
+}
+
+// SetDataMask provides a function to mask the sensitive data of the columns
+// by given worksheet name and data masking options, each option specifies
+// the masking of the columns, such as display ****1234 for the account
+// numbers. The letters and digits of the cell value will be replaced with
+// the mask character except the kept characters, and the other characters
+// such as whitespaces, hyphens and punctuations will be kept. All letters and
+// digits will be masked if the cell value doesn't have more letters and
+// digits than the kept characters. The empty cells will be skipped.
+//
+// Optional settings of the data masking are:
+//
+// Columns: Specifies the columns to be masked, such as "H" or "C:F", this
+// field is required.
+//
+// StartRow: Specifies the first row to be masked, for skipping the header
+// rows. The default value is 1.
+//
+// KeepLeft: Specifies the number of letters and digits at the beginning of
+// the cell value to be displayed. The default value is 0.
+//
+// KeepRight: Specifies the number of letters and digits at the end of the
+// cell value to be displayed. The default value is 0.
+//
+// MaskChar: Specifies the single mask character. The default value is "*".
+//
+// Truncate: Specifies if the stored cell values will be replaced by the
+// masked values. The default value is false, the full cell values will be
+// stored, and the masked values will be rendered by the custom number formats
+// of the cells. The formulas of the cells will be removed when this field is
+// true.
+//
+// Protect: Specifies if set the cells to be locked and hidden, which takes
+// effect after protecting the worksheet by the ProtectSheet function. The
+// default value is false.
+//
+// Note that the masked displays by the custom number formats are generated
+// from the current cell values, and the full cell values still stored in the
+// workbook, the Truncate option should be used for the compliance exports
+// which requires the sensitive data not to be stored. For example, display
+// the last 4 digits of the account numbers in column B, and truncate the
+// stored values in column C except the first character of the names on
+// Sheet1, skip the header row:
+//
+//	err := f.SetDataMask("Sheet1",
+//	    excelize.DataMaskOptions{Columns: "B", StartRow: 2, KeepRight: 4, Protect: true},
+//	    excelize.DataMaskOptions{Columns: "C", StartRow: 2, KeepLeft: 1, Truncate: true},
+//	)
+func (f *File) SetDataMask(sheet string, opts ...DataMaskOptions) error {
+	styles := map[string]int{}
+	for _, opt := range opts {
+		if err := f.setDataMask(sheet, &opt, styles); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// setDataMask provides a function to mask the cell values of the columns by
+// given worksheet name, data masking options and the cache of the masking
+// styles.
+func (f *File) setDataMask(sheet string, opts *DataMaskOptions, styles map[string]int) error {
+	if opts.StartRow < 0 || opts.KeepLeft < 0 || opts.KeepRight < 0 {
+		return ErrParameterInvalid
+	}
+	if opts.MaskChar == "" {
+		opts.MaskChar = "*"
+	}
+	if utf8.RuneCountInString(opts.MaskChar) != 1 {
+		return ErrParameterInvalid
+	}
+	minCol, maxCol, err := f.parseColRange(opts.Columns)
+	if err != nil {
+		return err
+	}
+	ws, err := f.workSheetReader(sheet)
+	if err != nil {
+		return err
+	}
+	var cells []string
+	ws.mu.Lock()
+	for _, row := range ws.SheetData.Row {
+		for _, c := range row.C {
+			col, rowNum, err := CellNameToCoordinates(c.R)
+			if err != nil || col < minCol || col > maxCol || rowNum < opts.StartRow {
+				continue
+			}
+			cells = append(cells, c.R)
+		}
+	}
+	ws.mu.Unlock()
+	for _, cell := range cells {
+		val, err := f.GetCellValue(sheet, cell)
+		if err != nil {
+			return err
+		}
+		if val == "" {
+			continue
+		}
+		masked := maskText(val, []rune(opts.MaskChar)[0], opts.KeepLeft, opts.KeepRight)
+		styleID, err := f.GetCellStyle(sheet, cell)
+		if err != nil {
+			return err
+		}
+		var numFmt string
+		if opts.Truncate {
+			if err = f.SetCellStr(sheet, cell, masked); err != nil {
+				return err
+			}
+		} else {
+			numFmt = literalNumFmt(masked)
+		}
+		if numFmt == "" && !opts.Protect {
+			continue
+		}
+		if styleID, err = f.getDataMaskStyle(styleID, numFmt, opts.Protect, styles); err != nil {
+			return err
+		}
+		if err = f.SetCellStyle(sheet, cell, cell, styleID); err != nil {
+			return err
+		}
+	}
+	return err
+}
+
+// getDataMaskStyle provides a function to get the style ID of the masked cell
+// based on the given style ID, custom number format and if the cell should
+// be locked and hidden. The created styles will be cached by the given map.
+func (f *File) getDataMaskStyle(styleID int, numFmt string, protect bool, styles map[string]int) (int, error) {
+	key := fmt.Sprintf("%d\x00%s\x00%t", styleID, numFmt, protect)
+	if id, ok := styles[key]; ok {
+		return id, nil
+	}
+	style, err := f.GetStyle(styleID)
+	if err != nil {
+		return styleID, err
+	}
+	if numFmt != "" {
+		style.NumFmt, style.DecimalPlaces, style.CustomNumFmt = 0, nil, &numFmt
+	}
+	if protect {
+		style.Protection = &Protection{Hidden: true, Locked: true}
+	}
+	if styleID, err = f.NewStyle(style); err != nil {
+		return styleID, err
+	}
+	styles[key] = styleID
+	return styleID, err
+}
+
+// maskText provides a function to replace the letters and digits of the given
+// text with the mask character except the given number of letters and digits
+// at the beginning and end of the text. All letters and digits will be masked
+// if the text doesn't have more letters and digits than the kept characters.
+func maskText(text string, maskChar rune, keepLeft, keepRight int) string {
+	var total int
+	for _, r := range text {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			total++
+		}
+	}
+	if total <= keepLeft+keepRight {
+		keepLeft, keepRight = 0, 0
+	}
+	var (
+		buf strings.Builder
+		idx int
+	)
+	for _, r := range text {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if idx >= keepLeft && idx < total-keepRight {
+				r = maskChar
+			}
+			idx++
+		}
+		buf.WriteRune(r)
+	}
+	return buf.String()
+}
+
+// literalNumFmt provides a function to get the custom number format which
+// displays the given text for the positive, negative, zero and text values,
+// each character of the text will be escaped as the literal character.
+func literalNumFmt(text string) string {
+	var section strings.Builder
+	for _, r := range text {
+		section.WriteRune('\\')
+		section.WriteRune(r)
+	}
+	return strings.Join([]string{section.String(), section.String(), section.String(), section.String()}, ";")
+}
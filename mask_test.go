@@ -0,0 +1,98 @@
+package excelize_ch
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetDataMask(t *testing.T) {
+	f := NewFile()
+	for cell, val := range map[string]interface{}{
+		"A1": "Name", "B1": "Account", "C1": "Card", "D1": "Phone",
+		"A2": "Alice Smith", "B2": 62284801, "C2": "4111-1111-1111-1234", "D2": "+1 (555) 010-9999",
+		"A3": "张三丰", "B3": "AC\"99", "C3": "12", "D3": "555",
+		"A5": "Bob",
+	} {
+		assert.NoError(t, f.SetCellValue("Sheet1", cell, val))
+	}
+	assert.NoError(t, f.SetCellFormula("Sheet1", "D4", `"555"&"1234"`))
+	ws, ok := f.Sheet.Load("xl/worksheets/sheet1.xml")
+	assert.True(t, ok)
+	ws.(*xlsxWorksheet).SheetData.Row[3].C[3].T, ws.(*xlsxWorksheet).SheetData.Row[3].C[3].V = "str", "5551234"
+	style, err := f.NewStyle(&Style{Font: &Font{Bold: true}})
+	assert.NoError(t, err)
+	assert.NoError(t, f.SetCellStyle("Sheet1", "B2", "B3", style))
+	assert.NoError(t, f.SetDataMask("Sheet1",
+		DataMaskOptions{Columns: "B", StartRow: 2, KeepRight: 4, Protect: true},
+		DataMaskOptions{Columns: "A", StartRow: 2, KeepLeft: 1, Truncate: true},
+		DataMaskOptions{Columns: "C:D", StartRow: 2, KeepRight: 4, MaskChar: "X"},
+	))
+	// Test the displayed and stored values of the masked cells
+	for cell, expected := range map[string][]string{
+		"A1": {"Name", "Name"},
+		"B1": {"Account", "Account"},
+		"A2": {"A**** *****", "A**** *****"},
+		"A3": {"张**", "张**"},
+		"A5": {"B**", "B**"},
+		"B2": {"****4801", "62284801"},
+		"B3": {"**\"**", "AC\"99"},
+		"C2": {"XXXX-XXXX-XXXX-1234", "4111-1111-1111-1234"},
+		"C3": {"XX", "12"},
+		"D2": {"+X (XXX) XXX-9999", "+1 (555) 010-9999"},
+		"D3": {"XXX", "555"},
+	} {
+		val, err := f.GetCellValue("Sheet1", cell)
+		assert.NoError(t, err)
+		assert.Equal(t, expected[0], val, cell)
+		val, err = f.GetCellValue("Sheet1", cell, Options{RawCellValue: true})
+		assert.NoError(t, err)
+		assert.Equal(t, expected[1], val, cell)
+	}
+	// Test the styles of the masked cells
+	styleID, err := f.GetCellStyle("Sheet1", "B2")
+	assert.NoError(t, err)
+	maskStyle, err := f.GetStyle(styleID)
+	assert.NoError(t, err)
+	assert.True(t, maskStyle.Font.Bold)
+	assert.Equal(t, &Protection{Hidden: true, Locked: true}, maskStyle.Protection)
+	assert.Equal(t, `\*\*\*\*\4\8\0\1;\*\*\*\*\4\8\0\1;\*\*\*\*\4\8\0\1;\*\*\*\*\4\8\0\1`, *maskStyle.CustomNumFmt)
+	styleID, err = f.GetCellStyle("Sheet1", "A2")
+	assert.NoError(t, err)
+	assert.Equal(t, 0, styleID)
+	// Test the formula of the masked cell will be kept without truncate
+	formula, err := f.GetCellFormula("Sheet1", "D4")
+	assert.NoError(t, err)
+	assert.Equal(t, `"555"&"1234"`, formula)
+	styleID, err = f.GetCellStyle("Sheet1", "D4")
+	assert.NoError(t, err)
+	maskStyle, err = f.GetStyle(styleID)
+	assert.NoError(t, err)
+	assert.Nil(t, maskStyle.Protection)
+	assert.Equal(t, `\X\X\X\1\2\3\4;\X\X\X\1\2\3\4;\X\X\X\1\2\3\4;\X\X\X\1\2\3\4`, *maskStyle.CustomNumFmt)
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestSetDataMask.xlsx")))
+	// Test set data mask with invalid options
+	for _, opts := range []DataMaskOptions{
+		{Columns: "A", StartRow: -1},
+		{Columns: "A", KeepLeft: -1},
+		{Columns: "A", KeepRight: -1},
+		{Columns: "A", MaskChar: "**"},
+	} {
+		assert.Equal(t, ErrParameterInvalid, f.SetDataMask("Sheet1", opts))
+	}
+	assert.Equal(t, newInvalidColumnNameError(""), f.SetDataMask("Sheet1", DataMaskOptions{}))
+	assert.Equal(t, newInvalidColumnNameError("*"), f.SetDataMask("Sheet1", DataMaskOptions{Columns: "*"}))
+	// Test set data mask on not exists worksheet
+	assert.EqualError(t, f.SetDataMask("SheetN", DataMaskOptions{Columns: "A"}), "sheet SheetN does not exist")
+	// Test set data mask with invalid sheet name
+	assert.Equal(t, ErrSheetNameInvalid, f.SetDataMask("Sheet:1", DataMaskOptions{Columns: "A"}))
+	assert.NoError(t, f.Close())
+	// Test set data mask with unsupported charset style sheet
+	f = NewFile()
+	assert.NoError(t, f.SetCellValue("Sheet1", "A1", "Alice"))
+	f.Styles = nil
+	f.Pkg.Store(defaultXMLPathStyles, MacintoshCyrillicCharset)
+	assert.EqualError(t, f.SetDataMask("Sheet1", DataMaskOptions{Columns: "A"}), "XML syntax error on line 1: invalid UTF-8")
+	assert.NoError(t, f.Close())
+}
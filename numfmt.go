@@ -4709,6 +4709,34 @@ func (f *File) getBuiltInNumFmtCode(numFmtID int) (string, bool) {
 	return "", false
 }
 
+// isDateTimeStyle provides a function to check if the number format of the
+// cell style is a date or time format by given style index.
+func (f *File) isDateTimeStyle(styleID int) bool {
+	styleSheet, err := f.stylesReader()
+	if err != nil || styleSheet.CellXfs == nil || styleID <= 0 || styleID >= len(styleSheet.CellXfs.Xf) {
+		return false
+	}
+	var numFmtID int
+	if styleSheet.CellXfs.Xf[styleID].NumFmtID != nil {
+		numFmtID = *styleSheet.CellXfs.Xf[styleID].NumFmtID
+	}
+	fmtCode, ok := styleSheet.getCustomNumFmtCode(numFmtID)
+	if !ok {
+		if fmtCode, ok = f.getBuiltInNumFmtCode(numFmtID); !ok {
+			return false
+		}
+	}
+	p := nfp.NumberFormatParser()
+	if sections := p.Parse(fmtCode); len(sections) > 0 {
+		for _, token := range sections[0].Items {
+			if token.TType == nfp.TokenTypeDateTimes || token.TType == nfp.TokenTypeElapsedDateTimes {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 // prepareNumberic split the number into two before and after parts by a
 // decimal point.
 func (nf *numberFormat) prepareNumberic(value string) {
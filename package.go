@@ -0,0 +1,290 @@
+// Copyright 2016 - 2023 The excelize Authors. All rights reserved. Use of
+// this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+//
+// package excelize_ch providing a set of functions that allow you to write to and
+// read from XLAM / XLSM / XLSX / XLTM / XLTX files. Supports reading and
+// writing spreadsheet documents generated by Microsoft Excel™ 2007 and later.
+// Supports complex components by high compatibility, and provided streaming
+// API for generating or reading data from a worksheet with huge amounts of
+// data. This library needs Go version 1.16 or later.
+
+package excelize_ch
+
+import (
+	"io"
+	"os"
+	"path"
+	"sort"
+	"strings"
+)
+
+// GetPackageParts provides a function to get all parts in the workbook
+// package and the content types of them, sorted by the part name. Note that
+// this function has the same side effect as saving the workbook: the parsed
+// parts in memory will be serialized into the package under the workbook
+// lock before listing and the loaded worksheets will be released from
+// memory, so the part list reflects the current state of the workbook. For
+// example, list all parts of the workbook:
+//
+//	parts, err := f.GetPackageParts()
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	for _, part := range parts {
+//	    fmt.Println(part.Name, part.ContentType, part.Size)
+//	}
+func (f *File) GetPackageParts() ([]PackagePart, error) {
+	var (
+		parts []PackagePart
+		names = map[string]struct{}{}
+	)
+	f.writeParts()
+	f.Pkg.Range(func(name, _ interface{}) bool {
+		names[name.(string)] = struct{}{}
+		return true
+	})
+	f.tempFiles.Range(func(name, _ interface{}) bool {
+		names[name.(string)] = struct{}{}
+		return true
+	})
+	for name := range f.streams {
+		names[name] = struct{}{}
+	}
+	for name := range names {
+		content, err := f.readPart(name)
+		if err != nil {
+			return parts, err
+		}
+		contentType, err := f.getPartContentType(name)
+		if err != nil {
+			return parts, err
+		}
+		parts = append(parts, PackagePart{Name: name, ContentType: contentType, Size: int64(len(content))})
+	}
+	sort.Slice(parts, func(i, j int) bool { return parts[i].Name < parts[j].Name })
+	return parts, nil
+}
+
+// GetPartContentType provides a function to get the content type of the part
+// in the workbook package by given part name. The content type will be
+// resolved from the override content type of the part first, and then the
+// default content type of the part extension. For example, get the content
+// type of the workbook part:
+//
+//	contentType, err := f.GetPartContentType("xl/workbook.xml")
+func (f *File) GetPartContentType(partName string) (string, error) {
+	partName = strings.TrimPrefix(partName, "/")
+	if !f.hasPart(partName) {
+		return "", newNoExistPartError(partName)
+	}
+	return f.getPartContentType(partName)
+}
+
+// GetPartRelationships provides a function to get the relationships of the
+// part in the workbook package by given part name. Get the package level
+// relationships with empty part name. For example, get the relationships of
+// the workbook part:
+//
+//	rels, err := f.GetPartRelationships("xl/workbook.xml")
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	for _, rel := range rels {
+//	    fmt.Println(rel.ID, rel.Type, rel.TargetPart)
+//	}
+func (f *File) GetPartRelationships(partName string) ([]PackageRelationship, error) {
+	var rels []PackageRelationship
+	partName = strings.TrimPrefix(partName, "/")
+	relsPath := "_rels/.rels"
+	if partName != "" {
+		if !f.hasPart(partName) {
+			return rels, newNoExistPartError(partName)
+		}
+		relsPath = getPartRelsPath(partName)
+	}
+	relationships, err := f.relsReader(relsPath)
+	if err != nil || relationships == nil {
+		return rels, err
+	}
+	relationships.mu.Lock()
+	defer relationships.mu.Unlock()
+	for _, rel := range relationships.Relationships {
+		packageRel := PackageRelationship{
+			Source: partName, ID: rel.ID, Type: rel.Type, Target: rel.Target, TargetMode: rel.TargetMode,
+		}
+		if rel.TargetMode != "External" {
+			packageRel.TargetPart = getRelsTargetPath(partName, rel.Target)
+		}
+		rels = append(rels, packageRel)
+	}
+	return rels, err
+}
+
+// GetPartContent provides a function to get the raw bytes of the part in the
+// workbook package by given part name. Note that this function has the same
+// side effect as saving the workbook: the parsed parts in memory will be
+// serialized into the package under the workbook lock before reading and the
+// loaded worksheets will be released from memory, so the content reflects the
+// current state of the workbook. Please don't modify the returned bytes
+// directly. For example, get the content of the custom XML part:
+//
+//	content, err := f.GetPartContent("customXml/item1.xml")
+func (f *File) GetPartContent(partName string) ([]byte, error) {
+	partName = strings.TrimPrefix(partName, "/")
+	if !f.hasPart(partName) {
+		return nil, newNoExistPartError(partName)
+	}
+	f.writeParts()
+	return f.readPart(partName)
+}
+
+// hasPart provides a function to check if the part exists in the workbook
+// package by given part name.
+func (f *File) hasPart(partName string) bool {
+	if _, ok := f.Pkg.Load(partName); ok {
+		return true
+	}
+	if _, ok := f.tempFiles.Load(partName); ok {
+		return true
+	}
+	_, ok := f.streams[partName]
+	return ok
+}
+
+// readPart provides a function to read the raw bytes of the part from the
+// stream writer, package or system temporary directory by given part name
+// without caching the content in memory.
+func (f *File) readPart(partName string) ([]byte, error) {
+	if stream, ok := f.streams[partName]; ok {
+		r, err := stream.rawData.Reader()
+		if err != nil {
+			return nil, err
+		}
+		return io.ReadAll(r)
+	}
+	if content, ok := f.Pkg.Load(partName); ok {
+		return content.([]byte), nil
+	}
+	tempFile, ok := f.tempFiles.Load(partName)
+	if !ok {
+		return nil, newNoExistPartError(partName)
+	}
+	return os.ReadFile(tempFile.(string))
+}
+
+// getPartContentType provides a function to get the content type of the part
+// in the workbook package by given part name.
+func (f *File) getPartContentType(partName string) (string, error) {
+	content, err := f.contentTypesReader()
+	if err != nil {
+		return "", err
+	}
+	content.mu.Lock()
+	defer content.mu.Unlock()
+	for _, override := range content.Overrides {
+		if strings.TrimPrefix(override.PartName, "/") == partName {
+			return override.ContentType, err
+		}
+	}
+	ext := strings.TrimPrefix(path.Ext(partName), ".")
+	for _, def := range content.Defaults {
+		if strings.EqualFold(def.Extension, ext) {
+			return def.ContentType, err
+		}
+	}
+	return "", err
+}
+
+// SetRawPart provides a function to inject or override the part in the
+// workbook package with the raw bytes by given part name and content. The raw
+// part takes precedence over the content generated by the library at save
+// time, so this function could be used to add the parts that the library
+// doesn't model. Use the optional RawPartOptions to register the content
+// type of the part and create the relationship to the part. For example, add
+// a custom XML part with the relationship from the workbook:
+//
+//	err := f.SetRawPart("customXml/item1.xml", []byte(`<root/>`),
+//	    excelize.RawPartOptions{
+//	        ContentType:        "application/xml",
+//	        RelationshipSource: "xl/workbook.xml",
+//	        RelationshipType:   "http://schemas.openxmlformats.org/officeDocument/2006/relationships/customXml",
+//	    },
+//	)
+func (f *File) SetRawPart(partName string, content []byte, opts ...RawPartOptions) error {
+	partName = strings.TrimPrefix(partName, "/")
+	if partName == "" || path.Clean(partName) != partName || strings.HasPrefix(partName, "../") {
+		return ErrParameterInvalid
+	}
+	var options RawPartOptions
+	for _, opt := range opts {
+		options = opt
+	}
+	options.RelationshipSource = strings.TrimPrefix(options.RelationshipSource, "/")
+	if options.RelationshipType != "" && options.RelationshipSource != "" && !f.hasPart(options.RelationshipSource) {
+		return newNoExistPartError(options.RelationshipSource)
+	}
+	if options.ContentType != "" {
+		if err := f.setPartContentType(partName, options.ContentType); err != nil {
+			return err
+		}
+	}
+	if options.RelationshipType != "" {
+		if err := f.addPartRels(options.RelationshipSource, options.RelationshipType, partName); err != nil {
+			return err
+		}
+	}
+	f.Relationships.Delete(partName)
+	f.Sheet.Delete(partName)
+	f.checked.Delete(partName)
+	f.rawParts.Store(partName, content)
+	f.Pkg.Store(partName, content)
+	return nil
+}
+
+// setPartContentType provides a function to set the override content type of
+// the part by given part name and content type.
+func (f *File) setPartContentType(partName, contentType string) error {
+	content, err := f.contentTypesReader()
+	if err != nil {
+		return err
+	}
+	content.mu.Lock()
+	defer content.mu.Unlock()
+	for idx, override := range content.Overrides {
+		if strings.TrimPrefix(override.PartName, "/") == partName {
+			content.Overrides[idx].ContentType = contentType
+			return err
+		}
+	}
+	content.Overrides = append(content.Overrides, xlsxOverride{
+		PartName:    "/" + partName,
+		ContentType: contentType,
+	})
+	return err
+}
+
+// addPartRels provides a function to create the relationship from the source
+// part to the target part if it doesn't exist by given source part name,
+// relationship type and target part name.
+func (f *File) addPartRels(source, relType, partName string) error {
+	relsPath, target := "_rels/.rels", partName
+	if source != "" {
+		relsPath, target = getPartRelsPath(source), getRelsTarget(source, partName)
+	}
+	rels, err := f.relsReader(relsPath)
+	if err != nil {
+		return err
+	}
+	if rels != nil {
+		for _, rel := range rels.Relationships {
+			if rel.Type == relType && rel.TargetMode != "External" && getRelsTargetPath(source, rel.Target) == partName {
+				return err
+			}
+		}
+	}
+	f.addRels(relsPath, relType, target, "")
+	return err
+}
@@ -0,0 +1,227 @@
+package excelize_ch
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetPackageParts(t *testing.T) {
+	f := NewFile()
+	assert.NoError(t, f.SetCellValue("Sheet1", "A1", "Hello"))
+	parts, err := f.GetPackageParts()
+	assert.NoError(t, err)
+	var names []string
+	for _, part := range parts {
+		names = append(names, part.Name)
+		assert.NotZero(t, part.Size)
+	}
+	assert.Equal(t, []string{
+		"[Content_Types].xml", "_rels/.rels", "docProps/app.xml", "docProps/core.xml",
+		"xl/_rels/workbook.xml.rels", "xl/sharedStrings.xml", "xl/styles.xml", "xl/theme/theme1.xml",
+		"xl/workbook.xml", "xl/worksheets/sheet1.xml",
+	}, names)
+	assert.Equal(t, PackagePart{Name: "xl/worksheets/sheet1.xml", ContentType: ContentTypeSpreadSheetMLWorksheet, Size: parts[9].Size}, parts[9])
+	assert.Equal(t, "application/vnd.openxmlformats-package.relationships+xml", parts[1].ContentType)
+	// Test get package parts with stream writer and temporary files
+	sw, err := f.NewStreamWriter("Sheet1")
+	assert.NoError(t, err)
+	assert.NoError(t, sw.SetRow("A1", []interface{}{"Hello"}))
+	f.tempFiles.Store("xl/media/image1.png", filepath.Join("test", "images", "excel.png"))
+	parts, err = f.GetPackageParts()
+	assert.NoError(t, err)
+	assert.Len(t, parts, 11)
+	f.tempFiles.Store("xl/media/image1.png", filepath.Join("test", "images", "non-existing.png"))
+	_, err = f.GetPackageParts()
+	assert.Error(t, err)
+	f.tempFiles.Delete("xl/media/image1.png")
+	assert.NoError(t, sw.Flush())
+	// Test get package parts with unsupported charset content types
+	f.ContentTypes = nil
+	f.Pkg.Store(defaultXMLPathContentTypes, MacintoshCyrillicCharset)
+	_, err = f.GetPackageParts()
+	assert.EqualError(t, err, "XML syntax error on line 1: invalid UTF-8")
+}
+
+func TestGetPartContentType(t *testing.T) {
+	f := NewFile()
+	contentType, err := f.GetPartContentType("/xl/workbook.xml")
+	assert.NoError(t, err)
+	assert.Equal(t, ContentTypeSheetML, contentType)
+	f.Pkg.Store("xl/media/image1.PNG", []byte{})
+	contentType, err = f.GetPartContentType("xl/media/image1.PNG")
+	assert.NoError(t, err)
+	assert.Equal(t, "", contentType)
+	assert.NoError(t, f.setContentTypePartImageExtensions())
+	contentType, err = f.GetPartContentType("xl/media/image1.PNG")
+	assert.NoError(t, err)
+	assert.Equal(t, "image/png", contentType)
+	// Test get content type of the non-existing part
+	_, err = f.GetPartContentType("xl/media/image2.png")
+	assert.EqualError(t, err, "part xl/media/image2.png does not exist")
+	// Test get content type with unsupported charset content types
+	f.ContentTypes = nil
+	f.Pkg.Store(defaultXMLPathContentTypes, MacintoshCyrillicCharset)
+	_, err = f.GetPartContentType("xl/workbook.xml")
+	assert.EqualError(t, err, "XML syntax error on line 1: invalid UTF-8")
+}
+
+func TestGetPartRelationships(t *testing.T) {
+	f := NewFile()
+	rels, err := f.GetPartRelationships("")
+	assert.NoError(t, err)
+	assert.Len(t, rels, 3)
+	assert.Equal(t, PackageRelationship{ID: "rId3", Type: SourceRelationshipExtendProperties, Target: "docProps/app.xml", TargetPart: "docProps/app.xml"}, rels[0])
+	assert.NoError(t, f.SetCellHyperLink("Sheet1", "A1", "https://github.com/xuri/excelize", "External"))
+	assert.NoError(t, f.AddPicture("Sheet1", "B2", filepath.Join("test", "images", "excel.png"), nil))
+	rels, err = f.GetPartRelationships("xl/worksheets/sheet1.xml")
+	assert.NoError(t, err)
+	assert.Equal(t, []PackageRelationship{
+		{Source: "xl/worksheets/sheet1.xml", ID: "rId1", Type: SourceRelationshipHyperLink, Target: "https://github.com/xuri/excelize", TargetMode: "External"},
+		{Source: "xl/worksheets/sheet1.xml", ID: "rId2", Type: SourceRelationshipDrawingML, Target: "../drawings/drawing1.xml", TargetPart: "xl/drawings/drawing1.xml"},
+	}, rels)
+	rels, err = f.GetPartRelationships("xl/styles.xml")
+	assert.NoError(t, err)
+	assert.Empty(t, rels)
+	// Test get relationships of the non-existing part
+	_, err = f.GetPartRelationships("xl/worksheets/sheet2.xml")
+	assert.EqualError(t, err, "part xl/worksheets/sheet2.xml does not exist")
+	// Test get relationships with unsupported charset relationships
+	f.Relationships.Delete(defaultXMLPathWorkbookRels)
+	f.Pkg.Store(defaultXMLPathWorkbookRels, MacintoshCyrillicCharset)
+	_, err = f.GetPartRelationships("xl/workbook.xml")
+	assert.EqualError(t, err, "XML syntax error on line 1: invalid UTF-8")
+}
+
+func TestGetPartContent(t *testing.T) {
+	f := NewFile()
+	assert.NoError(t, f.SetCellValue("Sheet1", "A1", 1))
+	content, err := f.GetPartContent("xl/worksheets/sheet1.xml")
+	assert.NoError(t, err)
+	assert.Contains(t, string(content), `<c r="A1"><v>1</v></c>`)
+	// Test get content of the part in the system temporary directory
+	file, err := os.ReadFile(filepath.Join("test", "images", "excel.png"))
+	assert.NoError(t, err)
+	f.tempFiles.Store("xl/media/image1.png", filepath.Join("test", "images", "excel.png"))
+	content, err = f.GetPartContent("xl/media/image1.png")
+	assert.NoError(t, err)
+	assert.Equal(t, file, content)
+	_, ok := f.Pkg.Load("xl/media/image1.png")
+	assert.False(t, ok)
+	f.tempFiles.Delete("xl/media/image1.png")
+	// Test get content of the stream writer part
+	sw, err := f.NewStreamWriter("Sheet1")
+	assert.NoError(t, err)
+	assert.NoError(t, sw.SetRow("A1", []interface{}{2}))
+	assert.NoError(t, sw.Flush())
+	content, err = f.GetPartContent("xl/worksheets/sheet1.xml")
+	assert.NoError(t, err)
+	assert.Contains(t, string(content), `<c r="A1"><v>2</v></c>`)
+	// Test get content of the non-existing part
+	_, err = f.GetPartContent("xl/media/image1.png")
+	assert.EqualError(t, err, "part xl/media/image1.png does not exist")
+	assert.NoError(t, f.Close())
+}
+
+func TestGetPartContentConcurrency(t *testing.T) {
+	f := NewFile()
+	var wg sync.WaitGroup
+	for i := 1; i <= 10; i++ {
+		wg.Add(2)
+		go func(row int) {
+			defer wg.Done()
+			assert.NoError(t, f.SetCellValue("Sheet1", fmt.Sprintf("A%d", row), fmt.Sprintf("value %d", row)))
+		}(i)
+		go func() {
+			defer wg.Done()
+			_, err := f.GetPartContent("xl/worksheets/sheet1.xml")
+			assert.NoError(t, err)
+			_, err = f.GetPackageParts()
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+	for i := 1; i <= 10; i++ {
+		val, err := f.GetCellValue("Sheet1", fmt.Sprintf("A%d", i))
+		assert.NoError(t, err)
+		assert.Equal(t, fmt.Sprintf("value %d", i), val)
+	}
+	assert.NoError(t, f.Close())
+}
+
+func TestSetRawPart(t *testing.T) {
+	f := NewFile()
+	relType := "http://schemas.openxmlformats.org/officeDocument/2006/relationships/customXml"
+	assert.NoError(t, f.SetRawPart("/customXml/item1.xml", []byte(`<root/>`), RawPartOptions{
+		ContentType: "application/xml", RelationshipSource: "xl/workbook.xml", RelationshipType: relType,
+	}))
+	// Test set raw part twice without creating duplicate relationship
+	assert.NoError(t, f.SetRawPart("customXml/item1.xml", []byte(`<root>1</root>`), RawPartOptions{
+		ContentType: "text/xml", RelationshipSource: "xl/workbook.xml", RelationshipType: relType,
+	}))
+	rels, err := f.GetPartRelationships("xl/workbook.xml")
+	assert.NoError(t, err)
+	var count int
+	for _, rel := range rels {
+		if rel.Type == relType {
+			count++
+			assert.Equal(t, "../customXml/item1.xml", rel.Target)
+			assert.Equal(t, "customXml/item1.xml", rel.TargetPart)
+		}
+	}
+	assert.Equal(t, 1, count)
+	contentType, err := f.GetPartContentType("customXml/item1.xml")
+	assert.NoError(t, err)
+	assert.Equal(t, "text/xml", contentType)
+	// Test set raw part with package level relationship
+	assert.NoError(t, f.SetRawPart("customUI/customUI14.xml", []byte(`<customUI/>`), RawPartOptions{
+		RelationshipType: "http://schemas.microsoft.com/office/2007/relationships/ui/extensibility",
+	}))
+	rels, err = f.GetPartRelationships("")
+	assert.NoError(t, err)
+	assert.Equal(t, "customUI/customUI14.xml", rels[len(rels)-1].Target)
+	// Test the raw part takes precedence over the generated content
+	assert.NoError(t, f.SetRawPart("docProps/app.xml", []byte(`<Properties xmlns="http://schemas.openxmlformats.org/officeDocument/2006/extended-properties"/>`)))
+	assert.NoError(t, f.SetAppProps(&AppProperties{Company: "Company"}))
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestSetRawPart.xlsx")))
+	assert.NoError(t, f.Close())
+	f, err = OpenFile(filepath.Join("test", "TestSetRawPart.xlsx"))
+	assert.NoError(t, err)
+	for partName, expected := range map[string]string{
+		"customXml/item1.xml":     `<root>1</root>`,
+		"customUI/customUI14.xml": `<customUI/>`,
+	} {
+		content, err := f.GetPartContent(partName)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, string(content))
+	}
+	content, err := f.GetPartContent("docProps/app.xml")
+	assert.NoError(t, err)
+	assert.NotContains(t, string(content), "Company")
+	assert.NoError(t, f.Close())
+	// Test set raw part with invalid part name
+	for _, partName := range []string{"", "/", "../item1.xml", "customXml//item1.xml"} {
+		assert.Equal(t, ErrParameterInvalid, f.SetRawPart(partName, nil))
+	}
+	// Test set raw part with non-existing relationship source part
+	assert.EqualError(t, f.SetRawPart("customXml/item1.xml", nil, RawPartOptions{
+		RelationshipSource: "xl/workbook2.xml", RelationshipType: relType,
+	}), "part xl/workbook2.xml does not exist")
+	// Test set raw part with unsupported charset relationships
+	f = NewFile()
+	f.Relationships.Delete(defaultXMLPathWorkbookRels)
+	f.Pkg.Store(defaultXMLPathWorkbookRels, MacintoshCyrillicCharset)
+	assert.EqualError(t, f.SetRawPart("customXml/item1.xml", nil, RawPartOptions{
+		RelationshipSource: "xl/workbook.xml", RelationshipType: relType,
+	}), "XML syntax error on line 1: invalid UTF-8")
+	// Test set raw part with unsupported charset content types
+	f.ContentTypes = nil
+	f.Pkg.Store(defaultXMLPathContentTypes, MacintoshCyrillicCharset)
+	assert.EqualError(t, f.SetRawPart("customXml/item1.xml", nil, RawPartOptions{
+		ContentType: "application/xml",
+	}), "XML syntax error on line 1: invalid UTF-8")
+}
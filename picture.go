@@ -13,15 +13,37 @@ package excelize_ch
 
 import (
 	"bytes"
+	"crypto/sha256"
 	"encoding/xml"
+	"fmt"
 	"image"
 	"os"
 	"path"
 	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
 )
 
+// imageRegistry directly maps the registered images of the workbook, the
+// images are indexed by the SHA-256 checksums of the image data.
+type imageRegistry struct {
+	mu     sync.Mutex
+	images []*registeredImage
+	hashes map[[sha256.Size]byte]int
+}
+
+// registeredImage directly maps the registered image data, extension name,
+// size and the part name of the image in the workbook, the part name will be
+// empty until the image is stored in the workbook.
+type registeredImage struct {
+	ext      string
+	file     []byte
+	config   *image.Config
+	partName string
+}
+
 // parseGraphicOptions provides a function to parse the format settings of
 // the picture with default value.
 func parseGraphicOptions(opts *GraphicOptions) *GraphicOptions {
@@ -150,17 +172,29 @@ func parseGraphicOptions(opts *GraphicOptions) *GraphicOptions {
 // size with cells). If you don't set this parameter, the default positioning
 // is to move and size with cells.
 func (f *File) AddPicture(sheet, cell, name string, opts *GraphicOptions) error {
+	_, err := f.AddPictureObject(sheet, cell, name, opts)
+	return err
+}
+
+// AddPictureObject provides the method to add picture in a sheet by given
+// picture format set and file path as the AddPicture function, and returns
+// the handle of the picture, which could be used with the
+// DeleteDrawingObject, MoveDrawingObject and UpdateDrawingObject functions.
+// For example:
+//
+//	obj, err := f.AddPictureObject("Sheet1", "A2", "image.jpg", nil)
+func (f *File) AddPictureObject(sheet, cell, name string, opts *GraphicOptions) (DrawingObject, error) {
 	var err error
 	// Check picture exists first.
 	if _, err = os.Stat(name); os.IsNotExist(err) {
-		return err
+		return DrawingObject{Sheet: sheet}, err
 	}
 	ext, ok := supportedImageTypes[strings.ToLower(path.Ext(name))]
 	if !ok {
-		return ErrImgExt
+		return DrawingObject{Sheet: sheet}, ErrImgExt
 	}
 	file, _ := os.ReadFile(filepath.Clean(name))
-	return f.AddPictureFromBytes(sheet, cell, &Picture{Extension: ext, File: file, Format: opts})
+	return f.AddPictureFromBytesObject(sheet, cell, &Picture{Extension: ext, File: file, Format: opts})
 }
 
 // AddPictureFromBytes provides the method to add picture in a sheet by given
@@ -204,32 +238,94 @@ func (f *File) AddPicture(sheet, cell, name string, opts *GraphicOptions) error
 //	    }
 //	}
 func (f *File) AddPictureFromBytes(sheet, cell string, pic *Picture) error {
-	var drawingHyperlinkRID int
-	var hyperlinkType string
+	_, err := f.AddPictureFromBytesObject(sheet, cell, pic)
+	return err
+}
+
+// AddPictureFromBytesObject provides the method to add picture in a sheet by
+// given picture format set, extension name and file bytes as the
+// AddPictureFromBytes function, and returns the handle of the picture, which
+// could be used with the DeleteDrawingObject, MoveDrawingObject and
+// UpdateDrawingObject functions.
+func (f *File) AddPictureFromBytesObject(sheet, cell string, pic *Picture) (DrawingObject, error) {
 	ext, ok := supportedImageTypes[strings.ToLower(pic.Extension)]
 	if !ok {
-		return ErrImgExt
+		return DrawingObject{Sheet: sheet}, ErrImgExt
 	}
-	options := parseGraphicOptions(pic.Format)
-	img, _, err := image.DecodeConfig(bytes.NewReader(pic.File))
+	imageID, err := f.registerImage(pic.File, ext)
 	if err != nil {
-		return err
+		return DrawingObject{Sheet: sheet}, err
+	}
+	return f.AddRegisteredPicture(sheet, cell, imageID, pic.Format)
+}
+
+// RegisterImage provides a function to register the image by given extension
+// name and image data, and returns the ID of the image in the image registry
+// of the workbook, supported image types: EMF, EMZ, GIF, JPEG, JPG, PNG, SVG,
+// TIF, TIFF, WMF, and WMZ. The same image data will be registered only once
+// and the ID of the registered image will be returned. The image will be
+// stored in the workbook when it is added to the worksheet by the
+// AddRegisteredPicture function, and all pictures of the image share the
+// single media part and the single relationship of each drawing, which keeps
+// the workbook size small when the same image is added to thousands of cells.
+// For example, add the same product photo to the cells A1:A1000 on Sheet1:
+//
+//	file, err := os.ReadFile("image.png")
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	imageID, err := f.RegisterImage(".png", file)
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	for row := 1; row <= 1000; row++ {
+//	    if _, err := f.AddRegisteredPicture("Sheet1", fmt.Sprintf("A%d", row),
+//	        imageID, &excelize.GraphicOptions{AutoFit: true}); err != nil {
+//	        fmt.Println(err)
+//	        return
+//	    }
+//	}
+func (f *File) RegisterImage(extension string, file []byte) (int, error) {
+	ext, ok := supportedImageTypes[strings.ToLower(extension)]
+	if !ok {
+		return 0, ErrImgExt
+	}
+	return f.registerImage(file, ext)
+}
+
+// AddRegisteredPicture provides a function to add picture in a sheet by given
+// worksheet name, cell reference, ID of the image registered by the
+// RegisterImage function and picture format set. The GraphicOptions are the
+// same as the AddPicture function.
+func (f *File) AddRegisteredPicture(sheet, cell string, imageID int, opts *GraphicOptions) (DrawingObject, error) {
+	obj := DrawingObject{Sheet: sheet}
+	var drawingHyperlinkRID int
+	var hyperlinkType string
+	options := parseGraphicOptions(opts)
+	img, err := f.getRegisteredImage(imageID)
+	if err != nil {
+		return obj, err
 	}
 	// Read sheet data
 	f.mu.Lock()
 	ws, err := f.workSheetReader(sheet)
 	if err != nil {
 		f.mu.Unlock()
-		return err
+		return obj, err
 	}
 	f.mu.Unlock()
+	if err = f.checkObjectsProtection(ws); err != nil {
+		return obj, err
+	}
 	ws.mu.Lock()
 	// Add first picture for given sheet, create xl/drawings/ and xl/drawings/_rels/ folder.
 	drawingID := f.countDrawings() + 1
 	drawingXML := "xl/drawings/drawing" + strconv.Itoa(drawingID) + ".xml"
 	drawingID, drawingXML = f.prepareDrawing(ws, drawingID, sheet, drawingXML)
 	drawingRels := "xl/drawings/_rels/drawing" + strconv.Itoa(drawingID) + ".xml.rels"
-	mediaStr := ".." + strings.TrimPrefix(f.addMedia(pic.File, ext), "xl")
+	mediaStr := ".." + strings.TrimPrefix(f.storeRegisteredImage(imageID), "xl")
 	var drawingRID int
 	if rels, _ := f.relsReader(drawingRels); rels != nil {
 		for _, rel := range rels.Relationships {
@@ -250,15 +346,16 @@ func (f *File) AddPictureFromBytes(sheet, cell string, pic *Picture) error {
 		drawingHyperlinkRID = f.addRels(drawingRels, SourceRelationshipHyperLink, options.Hyperlink, hyperlinkType)
 	}
 	ws.mu.Unlock()
-	err = f.addDrawingPicture(sheet, drawingXML, cell, ext, drawingRID, drawingHyperlinkRID, img, options)
+	obj.ID, err = f.addDrawingPicture(sheet, drawingXML, cell, img.ext, drawingRID, drawingHyperlinkRID, *img.config, options)
 	if err != nil {
-		return err
+		return obj, err
 	}
+	obj.Name = "Picture " + strconv.Itoa(obj.ID)
 	if err = f.addContentTypePart(drawingID, "drawings"); err != nil {
-		return err
+		return obj, err
 	}
 	f.addSheetNameSpace(sheet, SourceRelationship)
-	return err
+	return obj, err
 }
 
 // addSheetLegacyDrawing provides a function to add legacy drawing element to
@@ -313,19 +410,19 @@ func (f *File) countDrawings() int {
 
 // addDrawingPicture provides a function to add picture by given sheet,
 // drawingXML, cell, file name, width, height relationship index and format
-// sets.
-func (f *File) addDrawingPicture(sheet, drawingXML, cell, ext string, rID, hyperlinkRID int, img image.Config, opts *GraphicOptions) error {
+// sets, and returns the ID of the picture.
+func (f *File) addDrawingPicture(sheet, drawingXML, cell, ext string, rID, hyperlinkRID int, img image.Config, opts *GraphicOptions) (int, error) {
 	col, row, err := CellNameToCoordinates(cell)
 	if err != nil {
-		return err
+		return 0, err
 	}
 	if opts.Positioning != "" && inStrSlice(supportedPositioning, opts.Positioning, true) == -1 {
-		return ErrParameterInvalid
+		return 0, ErrParameterInvalid
 	}
 	width, height := img.Width, img.Height
 	if opts.AutoFit {
 		if width, height, col, row, err = f.drawingResize(sheet, cell, float64(width), float64(height), opts); err != nil {
-			return err
+			return 0, err
 		}
 	} else {
 		width = int(float64(width) * opts.ScaleX)
@@ -334,7 +431,7 @@ func (f *File) addDrawingPicture(sheet, drawingXML, cell, ext string, rID, hyper
 	colStart, rowStart, colEnd, rowEnd, x2, y2 := f.positionObjectPixels(sheet, col, row, opts.OffsetX, opts.OffsetY, width, height)
 	content, cNvPrID, err := f.drawingParser(drawingXML)
 	if err != nil {
-		return err
+		return cNvPrID, err
 	}
 	twoCellAnchor := xdrCellAnchor{}
 	twoCellAnchor.EditAs = opts.Positioning
@@ -387,7 +484,7 @@ func (f *File) addDrawingPicture(sheet, drawingXML, cell, ext string, rID, hyper
 	defer content.mu.Unlock()
 	content.TwoCellAnchor = append(content.TwoCellAnchor, &twoCellAnchor)
 	f.Drawings.Store(drawingXML, content)
-	return err
+	return cNvPrID, err
 }
 
 // countMedia provides a function to get media files count storage in the
@@ -407,30 +504,92 @@ func (f *File) countMedia() int {
 // given file and extension name. Duplicate images are only actually stored once
 // and drawings that use it will reference the same image.
 func (f *File) addMedia(file []byte, ext string) string {
-	count := f.countMedia()
-	var name string
-	f.Pkg.Range(func(k, existing interface{}) bool {
-		if !strings.HasPrefix(k.(string), "xl/media/image") {
+	f.images.mu.Lock()
+	imageID := f.images.add(f, file, ext)
+	f.images.mu.Unlock()
+	return f.storeRegisteredImage(imageID)
+}
+
+// add provides a function to add the image into the image registry by given
+// workbook, image data and extension name, and returns the ID of the image.
+// The existing media parts of the workbook will be loaded into the registry
+// when adding the first image.
+func (r *imageRegistry) add(f *File, file []byte, ext string) int {
+	if r.hashes == nil {
+		r.hashes = map[[sha256.Size]byte]int{}
+		var names []string
+		f.Pkg.Range(func(k, v interface{}) bool {
+			if strings.HasPrefix(k.(string), "xl/media/image") {
+				names = append(names, k.(string))
+			}
 			return true
+		})
+		sort.Strings(names)
+		for _, name := range names {
+			content, _ := f.Pkg.Load(name)
+			imageID := r.add(f, content.([]byte), path.Ext(name))
+			r.images[imageID-1].partName = name
 		}
-		if bytes.Equal(file, existing.([]byte)) {
-			name = k.(string)
-			return false
+	}
+	sum := sha256.Sum256(file)
+	if imageID, ok := r.hashes[sum]; ok && bytes.Equal(r.images[imageID-1].file, file) {
+		return imageID
+	}
+	r.images = append(r.images, &registeredImage{ext: ext, file: file})
+	r.hashes[sum] = len(r.images)
+	return len(r.images)
+}
+
+// registerImage provides a function to add the image into the image registry
+// by given image data and extension name, and returns the ID of the image.
+func (f *File) registerImage(file []byte, ext string) (int, error) {
+	f.images.mu.Lock()
+	imageID := f.images.add(f, file, ext)
+	f.images.mu.Unlock()
+	_, err := f.getRegisteredImage(imageID)
+	return imageID, err
+}
+
+// getRegisteredImage provides a function to get the registered image by given
+// image ID, the size of the image will be decoded if not exist.
+func (f *File) getRegisteredImage(imageID int) (*registeredImage, error) {
+	f.images.mu.Lock()
+	defer f.images.mu.Unlock()
+	if imageID < 1 || imageID > len(f.images.images) {
+		return nil, newNoExistImageError(imageID)
+	}
+	img := f.images.images[imageID-1]
+	if img.config == nil {
+		config, _, err := image.DecodeConfig(bytes.NewReader(img.file))
+		if err != nil {
+			return img, err
 		}
-		return true
-	})
-	if name != "" {
-		return name
+		img.config = &config
 	}
-	media := "xl/media/image" + strconv.Itoa(count+1) + ext
-	f.Pkg.Store(media, file)
-	return media
+	return img, nil
+}
+
+// storeRegisteredImage provides a function to store the registered image into
+// the folder xl/media/image by given image ID if it's not stored in the
+// workbook, and returns the part name of the image.
+func (f *File) storeRegisteredImage(imageID int) string {
+	f.images.mu.Lock()
+	defer f.images.mu.Unlock()
+	img := f.images.images[imageID-1]
+	if img.partName != "" && f.hasPart(img.partName) {
+		return img.partName
+	}
+	img.partName = f.getUniquePartName("xl/media/image" + strconv.Itoa(f.countMedia()+1) + img.ext)
+	f.Pkg.Store(img.partName, img.file)
+	return img.partName
 }
 
 // GetPictures provides a function to get picture meta info and raw content
 // embed in spreadsheet by given worksheet and cell name. This function
 // returns the image contents as []byte data types. This function is
-// concurrency safe. For example:
+// concurrency safe. The images of the IMAGE formula function in the cell will
+// be fetched by the ImageFetcher of the workbook options if specified. For
+// example:
 //
 //	f, err := excelize.OpenFile("Book1.xlsx")
 //	if err != nil {
@@ -466,14 +625,54 @@ func (f *File) GetPictures(sheet, cell string) ([]Picture, error) {
 		return nil, err
 	}
 	f.mu.Unlock()
-	if ws.Drawing == nil {
-		return nil, err
+	var pics []Picture
+	if ws.Drawing != nil {
+		target := f.getSheetRelationshipsTargetByID(sheet, ws.Drawing.RID)
+		drawingXML := strings.TrimPrefix(strings.ReplaceAll(target, "..", "xl"), "/")
+		drawingRelationships := strings.ReplaceAll(strings.ReplaceAll(target, "../drawings", "xl/drawings/_rels"), ".xml", ".xml.rels")
+		if pics, err = f.getPicture(row, col, drawingXML, drawingRelationships); err != nil {
+			return pics, err
+		}
 	}
-	target := f.getSheetRelationshipsTargetByID(sheet, ws.Drawing.RID)
-	drawingXML := strings.TrimPrefix(strings.ReplaceAll(target, "..", "xl"), "/")
-	drawingRelationships := strings.ReplaceAll(strings.ReplaceAll(target, "../drawings", "xl/drawings/_rels"), ".xml", ".xml.rels")
+	images, err := f.getImageFunctionPictures(sheet, cell)
+	return append(pics, images...), err
+}
 
-	return f.getPicture(row, col, drawingXML, drawingRelationships)
+// getImageFunctionPictures provides a function to get the images of the
+// IMAGE formula function in the cell by given worksheet name and cell
+// reference, the images will be fetched by the ImageFetcher of the workbook
+// options.
+func (f *File) getImageFunctionPictures(sheet, cell string) ([]Picture, error) {
+	var pics []Picture
+	if f.options == nil || f.options.ImageFetcher == nil {
+		return pics, nil
+	}
+	formula, err := f.GetCellFormula(sheet, cell)
+	if err != nil || !strings.Contains(strings.ToUpper(formula), "IMAGE(") {
+		return pics, err
+	}
+	ctx := &calcContext{
+		entry:             fmt.Sprintf("%s!%s", sheet, cell),
+		maxCalcIterations: f.options.MaxCalcIterations,
+		iterations:        make(map[string]uint),
+		iterationsCache:   make(map[string]formulaArg),
+	}
+	_, _ = f.calcCellValue(ctx, sheet, cell)
+	for _, img := range ctx.images {
+		if img.sheet != sheet || img.cell != cell {
+			continue
+		}
+		file, err := f.options.ImageFetcher(img.source)
+		if err != nil {
+			return pics, err
+		}
+		_, format, err := image.DecodeConfig(bytes.NewReader(file))
+		if err != nil {
+			return pics, err
+		}
+		pics = append(pics, Picture{Extension: "." + format, File: file, Format: &GraphicOptions{AltText: img.altText}})
+	}
+	return pics, err
 }
 
 // GetPictureCells returns all picture cell references in a worksheet by a
@@ -510,6 +709,9 @@ func (f *File) DeletePicture(sheet, cell string) error {
 	if err != nil {
 		return err
 	}
+	if err = f.checkObjectsProtection(ws); err != nil {
+		return err
+	}
 	if ws.Drawing == nil {
 		return err
 	}
@@ -519,9 +721,17 @@ func (f *File) DeletePicture(sheet, cell string) error {
 	if err != nil {
 		return err
 	}
+	f.deletePictureRels(drawingRels, rID)
+	return err
+}
+
+// deletePictureRels provides a function to delete the image relationship of
+// the drawing by given drawing relationships part path and relationship ID,
+// the image will be deleted if it is not used by other drawings.
+func (f *File) deletePictureRels(drawingRels, rID string) {
 	rels := f.getDrawingRelationships(drawingRels, rID)
 	if rels == nil {
-		return err
+		return
 	}
 	var used bool
 	checkPicRef := func(k, v interface{}) bool {
@@ -545,7 +755,6 @@ func (f *File) DeletePicture(sheet, cell string) error {
 		f.Pkg.Delete(strings.Replace(rels.Target, "../", "xl/", -1))
 	}
 	f.deleteDrawingRels(drawingRels, rID)
-	return err
 }
 
 // getPicture provides a function to get picture base name and raw content
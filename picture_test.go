@@ -236,17 +236,76 @@ func TestGetPicture(t *testing.T) {
 	assert.EqualError(t, err, "XML syntax error on line 1: invalid UTF-8")
 }
 
+func TestGetPicturesImageFunction(t *testing.T) {
+	file, err := os.ReadFile(filepath.Join("test", "images", "excel.png"))
+	assert.NoError(t, err)
+	var sources []string
+	f := NewFile(Options{ImageFetcher: func(source string) ([]byte, error) {
+		sources = append(sources, source)
+		if source == "https://example.com/error.png" {
+			return nil, ErrParameterInvalid
+		}
+		return file, nil
+	}})
+	assert.NoError(t, f.SetCellValue("Sheet1", "B1", "https://example.com/logo.png"))
+	assert.NoError(t, f.SetCellFormula("Sheet1", "A1", "_xlfn.IMAGE(B1,\"Logo\")"))
+	vm := uint(1)
+	ws, err := f.workSheetReader("Sheet1")
+	assert.NoError(t, err)
+	ws.SheetData.Row[0].C[0].Vm = &vm
+	assert.NoError(t, f.AddPicture("Sheet1", "A1", filepath.Join("test", "images", "excel.jpg"), nil))
+	pics, err := f.GetPictures("Sheet1", "A1")
+	assert.NoError(t, err)
+	assert.Len(t, pics, 2)
+	assert.Equal(t, ".jpeg", pics[0].Extension)
+	assert.Equal(t, Picture{Extension: ".png", File: file, Format: &GraphicOptions{AltText: "Logo"}}, pics[1])
+	assert.Equal(t, []string{"https://example.com/logo.png"}, sources)
+	result, err := f.CalcCellValue("Sheet1", "A1")
+	assert.NoError(t, err)
+	assert.Equal(t, "Logo", result)
+
+	// Test get pictures of the IMAGE formula function which is not in the cell
+	assert.NoError(t, f.SetCellFormula("Sheet1", "A2", "A1&IMAGE(\"\")"))
+	pics, err = f.GetPictures("Sheet1", "A2")
+	assert.NoError(t, err)
+	assert.Empty(t, pics)
+	// Test get pictures of the IMAGE formula function with fetch error
+	assert.NoError(t, f.SetCellFormula("Sheet1", "A4", "IMAGE(\"https://example.com/error.png\")"))
+	_, err = f.GetPictures("Sheet1", "A4")
+	assert.Equal(t, ErrParameterInvalid, err)
+
+	// Test preserve the value metadata of the IMAGE formula function cell
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestGetPicturesImageFunction.xlsx")))
+	assert.NoError(t, f.Close())
+	f, err = OpenFile(filepath.Join("test", "TestGetPicturesImageFunction.xlsx"))
+	assert.NoError(t, err)
+	ws, err = f.workSheetReader("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, &vm, ws.SheetData.Row[0].C[0].Vm)
+	formula, err := f.GetCellFormula("Sheet1", "A1")
+	assert.NoError(t, err)
+	assert.Equal(t, "_xlfn.IMAGE(B1,\"Logo\")", formula)
+	// Test get pictures of the IMAGE formula function without image fetcher
+	pics, err = f.GetPictures("Sheet1", "A1")
+	assert.NoError(t, err)
+	assert.Len(t, pics, 1)
+	assert.NoError(t, f.Close())
+}
+
 func TestAddDrawingPicture(t *testing.T) {
 	// Test addDrawingPicture with illegal cell reference
 	f := NewFile()
 	opts := &GraphicOptions{PrintObject: boolPtr(true), Locked: boolPtr(false)}
-	assert.EqualError(t, f.addDrawingPicture("sheet1", "", "A", "", 0, 0, image.Config{}, opts), newCellNameToCoordinatesError("A", newInvalidCellNameError("A")).Error())
+	_, err := f.addDrawingPicture("sheet1", "", "A", "", 0, 0, image.Config{}, opts)
+	assert.EqualError(t, err, newCellNameToCoordinatesError("A", newInvalidCellNameError("A")).Error())
 	// Test addDrawingPicture with invalid positioning types
-	assert.Equal(t, f.addDrawingPicture("sheet1", "", "A1", "", 0, 0, image.Config{}, &GraphicOptions{Positioning: "x"}), ErrParameterInvalid)
+	_, err = f.addDrawingPicture("sheet1", "", "A1", "", 0, 0, image.Config{}, &GraphicOptions{Positioning: "x"})
+	assert.Equal(t, err, ErrParameterInvalid)
 
 	path := "xl/drawings/drawing1.xml"
 	f.Pkg.Store(path, MacintoshCyrillicCharset)
-	assert.EqualError(t, f.addDrawingPicture("sheet1", path, "A1", "", 0, 0, image.Config{}, opts), "XML syntax error on line 1: invalid UTF-8")
+	_, err = f.addDrawingPicture("sheet1", path, "A1", "", 0, 0, image.Config{}, opts)
+	assert.EqualError(t, err, "XML syntax error on line 1: invalid UTF-8")
 }
 
 func TestAddPictureFromBytes(t *testing.T) {
@@ -269,6 +328,104 @@ func TestAddPictureFromBytes(t *testing.T) {
 	assert.EqualError(t, f.AddPictureFromBytes("Sheet:1", fmt.Sprint("A", 1), &Picture{Extension: ".png", File: imgFile, Format: &GraphicOptions{AltText: "logo"}}), ErrSheetNameInvalid.Error())
 }
 
+func TestRegisterImage(t *testing.T) {
+	f, err := OpenFile(filepath.Join("test", "Book1.xlsx"))
+	assert.NoError(t, err)
+	countMedia := func() int {
+		count := 0
+		f.Pkg.Range(func(k, v interface{}) bool {
+			if strings.HasPrefix(k.(string), "xl/media/image") {
+				count++
+			}
+			return true
+		})
+		return count
+	}
+	existing := countMedia()
+	png, err := os.ReadFile(filepath.Join("test", "images", "excel.png"))
+	assert.NoError(t, err)
+	jpg, err := os.ReadFile(filepath.Join("test", "images", "excel.jpg"))
+	assert.NoError(t, err)
+	// Test register the same image data multiple times
+	pngID, err := f.RegisterImage(".PNG", png)
+	assert.NoError(t, err)
+	imageID, err := f.RegisterImage(".png", append([]byte{}, png...))
+	assert.NoError(t, err)
+	assert.Equal(t, pngID, imageID)
+	jpgID, err := f.RegisterImage(".jpg", jpg)
+	assert.NoError(t, err)
+	assert.NotEqual(t, pngID, jpgID)
+	// Test the registered image will not be stored until it is added to a worksheet
+	assert.Equal(t, existing, countMedia())
+	for row := 1; row <= 100; row++ {
+		for _, sheet := range []string{"Sheet1", "Sheet2"} {
+			_, err = f.AddRegisteredPicture(sheet, fmt.Sprintf("L%d", row), pngID, &GraphicOptions{AutoFit: true})
+			assert.NoError(t, err)
+		}
+	}
+	assert.NoError(t, f.AddPictureFromBytes("Sheet1", "M1", &Picture{Extension: ".png", File: png}))
+	_, err = f.AddRegisteredPicture("Sheet1", "M2", jpgID, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, existing+2, countMedia())
+	// Test the pictures of the same image share the single relationship of each drawing
+	for _, drawingRels := range []string{"xl/drawings/_rels/drawing1.xml.rels", "xl/drawings/_rels/drawing2.xml.rels"} {
+		rels, err := f.relsReader(drawingRels)
+		assert.NoError(t, err)
+		targets := map[string]int{}
+		for _, rel := range rels.Relationships {
+			if rel.Type == SourceRelationshipImage {
+				targets[rel.Target]++
+			}
+		}
+		for target, count := range targets {
+			assert.Equal(t, 1, count, target)
+		}
+	}
+	pics, err := f.GetPictures("Sheet2", "L100")
+	assert.NoError(t, err)
+	assert.Len(t, pics, 1)
+	assert.Equal(t, png, pics[0].File)
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestRegisterImage.xlsx")))
+	assert.NoError(t, f.Close())
+	// Test register the image which is already exists in the workbook
+	f, err = OpenFile(filepath.Join("test", "TestRegisterImage.xlsx"))
+	assert.NoError(t, err)
+	existing = countMedia()
+	imageID, err = f.RegisterImage(".png", png)
+	assert.NoError(t, err)
+	_, err = f.AddRegisteredPicture("Sheet1", "N1", imageID, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, existing, countMedia())
+	// Test the deleted media will be stored again
+	for row := 1; row <= 100; row++ {
+		for _, sheet := range []string{"Sheet1", "Sheet2"} {
+			assert.NoError(t, f.DeletePicture(sheet, fmt.Sprintf("L%d", row)))
+		}
+	}
+	assert.NoError(t, f.DeletePicture("Sheet1", "M1"))
+	assert.NoError(t, f.DeletePicture("Sheet1", "N1"))
+	assert.Equal(t, existing-1, countMedia())
+	_, err = f.AddRegisteredPicture("Sheet1", "N1", imageID, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, existing, countMedia())
+	pics, err = f.GetPictures("Sheet1", "N1")
+	assert.NoError(t, err)
+	assert.Len(t, pics, 1)
+	assert.Equal(t, png, pics[0].File)
+	// Test register image with unsupported image type
+	_, err = f.RegisterImage(".txt", png)
+	assert.Equal(t, ErrImgExt, err)
+	// Test add picture with not exists image ID
+	for _, imageID := range []int{0, 100} {
+		_, err = f.AddRegisteredPicture("Sheet1", "A1", imageID, nil)
+		assert.Equal(t, newNoExistImageError(imageID), err)
+	}
+	// Test add picture on not exists worksheet
+	_, err = f.AddRegisteredPicture("SheetN", "A1", imageID, nil)
+	assert.EqualError(t, err, "sheet SheetN does not exist")
+	assert.NoError(t, f.Close())
+}
+
 func TestDeletePicture(t *testing.T) {
 	f, err := OpenFile(filepath.Join("test", "Book1.xlsx"))
 	assert.NoError(t, err)
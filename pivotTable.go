@@ -16,10 +16,12 @@ import (
 	"encoding/xml"
 	"fmt"
 	"io"
+	"math"
 	"path/filepath"
 	"reflect"
 	"strconv"
 	"strings"
+	"time"
 
 	"golang.org/x/text/cases"
 	"golang.org/x/text/language"
@@ -32,14 +34,35 @@ import (
 //	PivotStyleLight1 - PivotStyleLight28
 //	PivotStyleMedium1 - PivotStyleMedium28
 //	PivotStyleDark1 - PivotStyleDark28
+//
+// PivotTableRange: The range reference of the pivot table, or the top-left
+// cell reference such as "Sheet1!G2" to compute the range of the pivot table
+// automatically by the fields and the data.
+//
+// NewSheet: The name of a new worksheet which will be created for the pivot
+// table, the pivot table will be placed at the cell A3 of the worksheet, and
+// the PivotTableRange should be empty.
+//
+// DataSource: The cached dataset of the pivot table, the first row is the
+// field names and the other rows are the records. The dataset will be stored
+// in the pivot cache records directly and will not appear on any worksheet,
+// and the DataRange should be empty.
+//
+// CacheSourceType: The type of the pivot cache source when the DataSource is
+// specified, the optional values are "external" and "scenario", defaults to
+// "external".
 type PivotTableOptions struct {
 	pivotTableXML       string
 	pivotCacheXML       string
 	pivotSheetName      string
 	pivotDataRange      string
 	namedDataRange      bool
+	fieldGroups         map[string]*xlsxCacheField
 	DataRange           string
+	DataSource          [][]interface{}
+	CacheSourceType     string
 	PivotTableRange     string
+	NewSheet            string
 	Name                string
 	Rows                []PivotTableField
 	Columns             []PivotTableField
@@ -80,6 +103,10 @@ type PivotTableOptions struct {
 //
 // Name specifies the name of the data field. Maximum 255 characters
 // are allowed in data field name, excess characters will be truncated.
+//
+// GroupBy specifies the grouping settings of the row, column or filter field,
+// the date field could be grouped by days, months, quarters or years, and the
+// numeric field could be grouped into the ranges by the interval.
 type PivotTableField struct {
 	Compact         bool
 	Data            string
@@ -87,11 +114,66 @@ type PivotTableField struct {
 	Outline         bool
 	Subtotal        string
 	DefaultSubtotal bool
+	GroupBy         *PivotTableFieldGroup
+}
+
+// PivotTableFieldGroup directly maps the grouping settings of the pivot table
+// field. By specifies the grouping type, the possible values for this
+// attribute are:
+//
+//	Days
+//	Months
+//	Quarters
+//	Years
+//	Range
+//
+// The StartDate and EndDate specify the date range of the date grouping, and
+// the StartNum, EndNum and Interval specify the numeric ranges of the Range
+// grouping, the default interval is 1. The start and end values will be
+// computed by the source data automatically if they are not specified.
+type PivotTableFieldGroup struct {
+	By        string
+	StartDate time.Time
+	EndDate   time.Time
+	StartNum  float64
+	EndNum    float64
+	Interval  float64
 }
 
 // AddPivotTable provides the method to add pivot table by given pivot table
 // options. Note that the same fields can not in Columns, Rows and Filter
-// fields at the same time.
+// fields at the same time. The range of the pivot table will be computed by
+// the fields and the data automatically when the PivotTableRange is a single
+// cell reference or the NewSheet is specified, and the PivotTableRange of the
+// options will be set to the created range. For example, create a pivot table
+// on a new worksheet named 'Pivot':
+//
+//	opts := &excelize.PivotTableOptions{
+//	    DataRange: "Sheet1!A1:E31",
+//	    NewSheet:  "Pivot",
+//	    Rows:      []excelize.PivotTableField{{Data: "Month"}},
+//	    Data:      []excelize.PivotTableField{{Data: "Sales", Subtotal: "Sum"}},
+//	}
+//	if err := f.AddPivotTable(opts); err != nil {
+//	    fmt.Println(err)
+//	}
+//	fmt.Println(opts.PivotTableRange)
+//
+// The table name or the cached dataset could be used as the data source of
+// the pivot table. The cached dataset will be stored in the pivot cache
+// records only and will not appear on any worksheet. For example, create a
+// pivot table with the external cache source from a cached dataset:
+//
+//	err := f.AddPivotTable(&excelize.PivotTableOptions{
+//	    DataSource: [][]interface{}{
+//	        {"Region", "Sales"},
+//	        {"East", 100},
+//	        {"West", 200},
+//	    },
+//	    PivotTableRange: "Sheet1!A1",
+//	    Rows:            []excelize.PivotTableField{{Data: "Region"}},
+//	    Data:            []excelize.PivotTableField{{Data: "Sales"}},
+//	})
 //
 // For example, create a pivot table on the range reference Sheet1!G2:M34 with
 // the range reference Sheet1!A1:E31 as the data source, summarize by sum for
@@ -153,8 +235,8 @@ func (f *File) AddPivotTable(opts *PivotTableOptions) error {
 		return err
 	}
 
-	pivotTableID := f.countPivotTables() + 1
-	pivotCacheID := f.countPivotCache() + 1
+	pivotTableID := f.getUnusedPartID("xl/pivotTables/pivotTable")
+	pivotCacheID := f.getUnusedPartID("xl/pivotCache/pivotCacheDefinition")
 
 	sheetRelationshipsPivotTableXML := "../pivotTables/pivotTable" + strconv.Itoa(pivotTableID) + ".xml"
 	opts.pivotTableXML = strings.ReplaceAll(sheetRelationshipsPivotTableXML, "..", "xl")
@@ -178,7 +260,140 @@ func (f *File) AddPivotTable(opts *PivotTableOptions) error {
 	if err = f.addContentTypePart(pivotTableID, "pivotTable"); err != nil {
 		return err
 	}
-	return f.addContentTypePart(pivotCacheID, "pivotCache")
+	if err = f.addContentTypePart(pivotCacheID, "pivotCache"); err != nil || opts.DataSource == nil {
+		return err
+	}
+	return f.refreshPivotCache(opts, []PivotTableOptions{*opts})
+}
+
+// AddRecommendedPivotTable provides a function to create a recommended pivot
+// table and pivot chart on a new worksheet by given table name and new
+// worksheet name, and returns the options of the created pivot table. The
+// columns of the table will be inspected to detect the date, numeric and
+// categorical fields. The first categorical field will be the row field, or
+// the first date field grouped by years if there are no categorical fields.
+// The numeric fields will be summarized by sum, or the row field will be
+// summarized by count if there are no numeric fields. A clustered column
+// pivot chart of the pivot table will be placed at the right of the pivot
+// table. For example, summarize the table named 'Table1' on a new worksheet
+// named 'Summary':
+//
+//	opts, err := f.AddRecommendedPivotTable("Table1", "Summary")
+//	if err != nil {
+//	    fmt.Println(err)
+//	}
+//	fmt.Println(opts.PivotTableRange)
+func (f *File) AddRecommendedPivotTable(table, sheet string) (*PivotTableOptions, error) {
+	opts := &PivotTableOptions{
+		DataRange:      table,
+		NewSheet:       sheet,
+		Name:           "PivotTable" + strconv.Itoa(f.getUnusedPartID("xl/pivotTables/pivotTable")),
+		RowGrandTotals: true,
+		ColGrandTotals: true,
+		ShowDrill:      true,
+		ShowRowHeaders: true,
+		ShowColHeaders: true,
+		ShowLastColumn: true,
+	}
+	order, err := f.getTableFieldsOrder(opts)
+	if err != nil {
+		return nil, err
+	}
+	types, err := f.getPivotFieldsType(opts)
+	if err != nil {
+		return nil, err
+	}
+	var dateField string
+	for i, name := range order {
+		switch types[i] {
+		case CellTypeNumber:
+			opts.Data = append(opts.Data, PivotTableField{Data: name, Name: "Sum of " + name, Subtotal: "Sum"})
+		case CellTypeDate:
+			if dateField == "" {
+				dateField = name
+			}
+		case CellTypeInlineString:
+			if len(opts.Rows) == 0 {
+				opts.Rows = []PivotTableField{{Data: name}}
+			}
+		}
+	}
+	if len(opts.Rows) == 0 && dateField != "" {
+		opts.Rows = []PivotTableField{{Data: dateField, GroupBy: &PivotTableFieldGroup{By: "Years"}}}
+	}
+	if len(opts.Rows) == 0 {
+		return nil, newPivotTableDataRangeError(ErrParameterInvalid.Error())
+	}
+	if len(opts.Data) == 0 {
+		opts.Data = []PivotTableField{{Data: opts.Rows[0].Data, Name: "Count of " + opts.Rows[0].Data, Subtotal: "Count"}}
+	}
+	if err = f.AddPivotTable(opts); err != nil {
+		return opts, err
+	}
+	_, coordinates, _ := f.adjustRange(opts.PivotTableRange)
+	ref := escapeSheetName(sheet) + "!"
+	chart := &Chart{Type: Col, pivotSource: ref + opts.Name}
+	for i := range opts.Data {
+		col, _ := ColumnNumberToName(coordinates[0] + i + 1)
+		chart.Series = append(chart.Series, ChartSeries{
+			Name:       fmt.Sprintf("%s$%s$%d", ref, col, coordinates[1]),
+			Categories: fmt.Sprintf("%s$A$%d:$A$%d", ref, coordinates[1]+1, coordinates[3]-1),
+			Values:     fmt.Sprintf("%s$%s$%d:$%s$%d", ref, col, coordinates[1]+1, col, coordinates[3]-1),
+		})
+	}
+	cell, _ := CoordinatesToCellName(coordinates[2]+2, coordinates[1])
+	err = f.AddChart(sheet, cell, chart)
+	return opts, err
+}
+
+// getPivotFieldsType provides a function to detect the types of the fields
+// in the source data range of the pivot table by given pivot table options.
+// The type of a field will be CellTypeDate or CellTypeNumber if all non-empty
+// values of the field are dates or numbers, otherwise the field will be
+// treated as a categorical field with the CellTypeInlineString type, and the
+// type of the field without any values will be CellTypeUnset.
+func (f *File) getPivotFieldsType(opts *PivotTableOptions) ([]CellType, error) {
+	dataSheet, coordinates, err := f.getPivotDataRange(opts)
+	if err != nil {
+		return nil, err
+	}
+	types := make([]CellType, coordinates[2]-coordinates[0]+1)
+	rows, err := f.Rows(dataSheet)
+	if err != nil {
+		return types, err
+	}
+	for row := 1; row <= coordinates[3] && rows.Next(); row++ {
+		if row <= coordinates[1] {
+			continue
+		}
+		cells, err := rows.Cells()
+		if err != nil {
+			_ = rows.Close()
+			return types, err
+		}
+		for i := range types {
+			if coordinates[0]+i > len(cells) || cells[coordinates[0]+i-1].Value == nil {
+				continue
+			}
+			cellType := CellTypeInlineString
+			switch cells[coordinates[0]+i-1].Value.(type) {
+			case time.Time:
+				cellType = CellTypeDate
+			case float64:
+				cellType = CellTypeNumber
+			}
+			if types[i] == CellTypeUnset {
+				types[i] = cellType
+			}
+			if types[i] != cellType {
+				types[i] = CellTypeInlineString
+			}
+		}
+	}
+	if err = rows.Close(); err != nil {
+		return types, err
+	}
+	return types, rows.Error()
 }
 
 // parseFormatPivotTableSet provides a function to validate pivot table
@@ -187,6 +402,9 @@ func (f *File) parseFormatPivotTableSet(opts *PivotTableOptions) (*xlsxWorksheet
 	if opts == nil {
 		return nil, "", ErrParameterRequired
 	}
+	if err := f.setPivotTableAutoRange(opts); err != nil {
+		return nil, "", err
+	}
 	pivotTableSheetName, _, err := f.adjustRange(opts.PivotTableRange)
 	if err != nil {
 		return nil, "", newPivotTableRangeError(err.Error())
@@ -198,13 +416,15 @@ func (f *File) parseFormatPivotTableSet(opts *PivotTableOptions) (*xlsxWorksheet
 	if err = f.getPivotTableDataRange(opts); err != nil {
 		return nil, "", err
 	}
-	dataSheetName, _, err := f.adjustRange(opts.pivotDataRange)
+	dataSheetName, _, err := f.getPivotDataRange(opts)
 	if err != nil {
-		return nil, "", newPivotTableDataRangeError(err.Error())
+		return nil, "", err
 	}
-	dataSheet, err := f.workSheetReader(dataSheetName)
-	if err != nil {
-		return dataSheet, "", err
+	var dataSheet *xlsxWorksheet
+	if opts.DataSource == nil {
+		if dataSheet, err = f.workSheetReader(dataSheetName); err != nil {
+			return dataSheet, "", err
+		}
 	}
 	pivotTableSheetPath, ok := f.getSheetXMLPath(pivotTableSheetName)
 	if !ok {
@@ -213,6 +433,146 @@ func (f *File) parseFormatPivotTableSet(opts *PivotTableOptions) (*xlsxWorksheet
 	return dataSheet, pivotTableSheetPath, err
 }
 
+// setPivotTableAutoRange provides a function to compute the range of the pivot
+// table by the fields and the data when the pivot table range is a single cell
+// reference or the pivot table will be placed on a new worksheet, and create
+// the new worksheet.
+func (f *File) setPivotTableAutoRange(opts *PivotTableOptions) error {
+	sheet, cell := opts.NewSheet, "A3"
+	if opts.NewSheet != "" {
+		if opts.PivotTableRange != "" {
+			return newPivotTableRangeError(ErrParameterInvalid.Error())
+		}
+		if err := checkSheetName(sheet); err != nil {
+			return err
+		}
+		if f.getSheetID(sheet) != -1 {
+			return ErrExistsSheet
+		}
+	} else {
+		rng := strings.Split(opts.PivotTableRange, "!")
+		if len(rng) != 2 || strings.Contains(rng[1], ":") {
+			return nil
+		}
+		sheet, cell = rng[0], strings.ReplaceAll(rng[1], "$", "")
+	}
+	col, row, err := CellNameToCoordinates(cell)
+	if err != nil {
+		return newPivotTableRangeError(err.Error())
+	}
+	if len(opts.Name) > MaxFieldLength {
+		return ErrNameLength
+	}
+	opts.pivotSheetName = sheet
+	if err = f.getPivotTableDataRange(opts); err != nil {
+		return err
+	}
+	width, height, err := f.getPivotTableSize(opts)
+	if err != nil {
+		return err
+	}
+	if len(opts.Filter) > 0 {
+		row += len(opts.Filter) + 1
+	}
+	ref, err := f.coordinatesToRangeRef([]int{col, row, col + width - 1, row + height - 1})
+	if err != nil {
+		return newPivotTableRangeError(err.Error())
+	}
+	if opts.NewSheet != "" {
+		if _, err = f.NewSheet(sheet); err != nil {
+			return err
+		}
+	}
+	opts.PivotTableRange = sheet + "!" + ref
+	return err
+}
+
+// getPivotTableSize provides a function to estimate the number of columns and
+// rows of the pivot table in compact form by the fields and the distinct
+// items of the fields in the data range.
+func (f *File) getPivotTableSize(opts *PivotTableOptions) (int, int, error) {
+	dataSheet, coordinates, err := f.getPivotDataRange(opts)
+	if err != nil {
+		return 0, 0, err
+	}
+	rowFields, err := f.getPivotFieldsIndex(opts.Rows, opts)
+	if err != nil {
+		return 0, 0, err
+	}
+	colFields, _ := f.getPivotFieldsIndex(opts.Columns, opts)
+	dataFields, _ := f.getPivotFieldsIndex(opts.Data, opts)
+	var records [][]string
+	for row := coordinates[1] + 1; row <= coordinates[3]; row++ {
+		record := make([]string, coordinates[2]-coordinates[0]+1)
+		for _, idx := range append(append([]int{}, rowFields...), colFields...) {
+			if record[idx], _, err = f.getPivotDataCellValue(opts, dataSheet, coordinates[0]+idx, row, false); err != nil {
+				return 0, 0, err
+			}
+		}
+		records = append(records, record)
+	}
+	values := len(dataFields)
+	if values == 0 {
+		values = 1
+	}
+	width, height := values, 1
+	if len(rowFields) > 0 {
+		height = 0
+		for _, count := range countPivotItems(records, rowFields) {
+			height += count
+		}
+		if opts.ColGrandTotals {
+			height++
+		}
+	}
+	if len(rowFields) > 0 || len(colFields) > 0 {
+		width++
+	}
+	height++
+	if len(colFields) > 0 {
+		var subtotals []bool
+		order, _ := f.getTableFieldsOrder(opts)
+		for _, field := range opts.Columns {
+			if inStrSlice(order, field.Data, true) != -1 {
+				subtotals = append(subtotals, field.DefaultSubtotal)
+			}
+		}
+		counts, items := countPivotItems(records, colFields), 0
+		for level, count := range counts {
+			if level == len(counts)-1 || subtotals[level] {
+				items += count
+			}
+		}
+		if width += items*values - values; opts.RowGrandTotals {
+			width += values
+		}
+		if height += len(colFields); len(dataFields) > 1 {
+			height++
+		}
+	}
+	return width, height, err
+}
+
+// countPivotItems provides a function to count the distinct items of each
+// level of the pivot table fields, the items of each level is the distinct
+// combinations of the values of the field and its parent fields.
+func countPivotItems(records [][]string, fields []int) []int {
+	counts := make([]int, len(fields))
+	for level := range fields {
+		items := map[string]bool{}
+		for _, record := range records {
+			var key strings.Builder
+			for _, idx := range fields[:level+1] {
+				key.WriteString(record[idx])
+				key.WriteByte(0)
+			}
+			items[key.String()] = true
+		}
+		counts[level] = len(items)
+	}
+	return counts
+}
+
 // adjustRange adjust range, for example: adjust Sheet1!$E$31:$A$1 to Sheet1!$A$1:$E$31
 func (f *File) adjustRange(rangeStr string) (string, []int, error) {
 	if len(rangeStr) < 1 {
@@ -250,13 +610,12 @@ func (f *File) getTableFieldsOrder(opts *PivotTableOptions) ([]string, error) {
 	if err := f.getPivotTableDataRange(opts); err != nil {
 		return order, err
 	}
-	dataSheet, coordinates, err := f.adjustRange(opts.pivotDataRange)
+	dataSheet, coordinates, err := f.getPivotDataRange(opts)
 	if err != nil {
-		return order, newPivotTableDataRangeError(err.Error())
+		return order, err
 	}
 	for col := coordinates[0]; col <= coordinates[2]; col++ {
-		coordinate, _ := CoordinatesToCellName(col, coordinates[1])
-		name, err := f.GetCellValue(dataSheet, coordinate)
+		name, _, err := f.getPivotDataCellValue(opts, dataSheet, col, coordinates[1], false)
 		if err != nil {
 			return order, err
 		}
@@ -265,12 +624,67 @@ func (f *File) getTableFieldsOrder(opts *PivotTableOptions) ([]string, error) {
 	return order, nil
 }
 
+// getPivotDataRange provides a function to get the worksheet name and the
+// coordinates of the source data range of the pivot table. The coordinates
+// of the cached dataset are the column and row numbers in the dataset.
+func (f *File) getPivotDataRange(opts *PivotTableOptions) (string, []int, error) {
+	if opts.DataSource != nil {
+		if len(opts.DataSource) < 2 || len(opts.DataSource[0]) == 0 {
+			return "", []int{}, newPivotTableDataRangeError(ErrParameterInvalid.Error())
+		}
+		return "", []int{1, 1, len(opts.DataSource[0]), len(opts.DataSource)}, nil
+	}
+	dataSheet, coordinates, err := f.adjustRange(opts.pivotDataRange)
+	if err != nil {
+		return dataSheet, coordinates, newPivotTableDataRangeError(err.Error())
+	}
+	return dataSheet, coordinates, err
+}
+
+// getPivotDataCellValue provides a function to get the value and the type of
+// the cell in the source data of the pivot table by given pivot table
+// options, worksheet name, column and row number. The value of the cached
+// dataset will be converted to the raw cell value.
+func (f *File) getPivotDataCellValue(opts *PivotTableOptions, sheet string, col, row int, raw bool) (string, CellType, error) {
+	if opts.DataSource != nil {
+		if row > len(opts.DataSource) || col > len(opts.DataSource[row-1]) {
+			return "", CellTypeUnset, nil
+		}
+		switch v := opts.DataSource[row-1][col-1].(type) {
+		case nil:
+			return "", CellTypeUnset, nil
+		case string:
+			return v, CellTypeInlineString, nil
+		case []byte:
+			return string(v), CellTypeInlineString, nil
+		case bool:
+			return strings.ToUpper(strconv.FormatBool(v)), CellTypeBool, nil
+		case time.Time:
+			excelTime, err := timeToExcelTime(v, false)
+			return strconv.FormatFloat(excelTime, 'f', -1, 64), CellTypeNumber, err
+		default:
+			val := fmt.Sprint(v)
+			if _, err := strconv.ParseFloat(val, 64); err == nil {
+				return val, CellTypeNumber, nil
+			}
+			return val, CellTypeInlineString, nil
+		}
+	}
+	cell, _ := CoordinatesToCellName(col, row)
+	val, err := f.GetCellValue(sheet, cell, Options{RawCellValue: raw})
+	if err != nil {
+		return val, CellTypeUnset, err
+	}
+	cellType, err := f.GetCellType(sheet, cell)
+	return val, cellType, err
+}
+
 // addPivotCache provides a function to create a pivot cache by given properties.
 func (f *File) addPivotCache(opts *PivotTableOptions) error {
 	// validate data range
-	dataSheet, coordinates, err := f.adjustRange(opts.pivotDataRange)
+	dataSheet, coordinates, err := f.getPivotDataRange(opts)
 	if err != nil {
-		return newPivotTableDataRangeError(err.Error())
+		return err
 	}
 	// data range has been checked
 	order, _ := f.getTableFieldsOrder(opts)
@@ -294,11 +708,29 @@ func (f *File) addPivotCache(opts *PivotTableOptions) error {
 	if opts.namedDataRange {
 		pc.CacheSource.WorksheetSource = &xlsxWorksheetSource{Name: opts.DataRange}
 	}
-	for _, name := range order {
-		pc.CacheFields.CacheField = append(pc.CacheFields.CacheField, &xlsxCacheField{
+	if opts.DataSource != nil {
+		pc.SaveData, pc.RefreshOnLoad = true, false
+		pc.CacheSource = &xlsxCacheSource{Type: strings.ToLower(opts.CacheSourceType)}
+		if pc.CacheSource.Type == "" {
+			pc.CacheSource.Type = "external"
+		}
+	}
+	for i, name := range order {
+		cacheField := &xlsxCacheField{
 			Name:        name,
 			SharedItems: &xlsxSharedItems{ContainsBlank: true, M: []xlsxMissing{{}}},
-		})
+		}
+		if group := getPivotTableFieldGroup(name, opts); group != nil {
+			if cacheField, err = f.newPivotCacheFieldGroup(opts, dataSheet, coordinates, coordinates[0]+i, group); err != nil {
+				return err
+			}
+			cacheField.Name, cacheField.FieldGroup.Base = name, intPtr(i)
+			if opts.fieldGroups == nil {
+				opts.fieldGroups = map[string]*xlsxCacheField{}
+			}
+			opts.fieldGroups[name] = cacheField
+		}
+		pc.CacheFields.CacheField = append(pc.CacheFields.CacheField, cacheField)
 	}
 	pc.CacheFields.Count = len(pc.CacheFields.CacheField)
 	pivotCache, err := xml.Marshal(pc)
@@ -594,33 +1026,191 @@ func (f *File) addPivotFields(pt *xlsxPivotTableDefinition, opts *PivotTableOpti
 		}
 		pt.PivotFields.PivotField = append(pt.PivotFields.PivotField, &xlsxPivotField{})
 	}
+	for i, name := range order {
+		if cacheField, ok := opts.fieldGroups[name]; ok && pt.PivotFields.PivotField[i].Axis != "" {
+			fld := pt.PivotFields.PivotField[i]
+			fld.Items = getPivotGroupItems(cacheField.FieldGroup.GroupItems.Count, fld.DefaultSubtotal)
+		}
+	}
 	return err
 }
 
-// countPivotTables provides a function to get pivot table files count storage
-// in the folder xl/pivotTables.
-func (f *File) countPivotTables() int {
-	count := 0
-	f.Pkg.Range(func(k, v interface{}) bool {
-		if strings.Contains(k.(string), "xl/pivotTables/pivotTable") {
-			count++
+// getPivotTableFieldGroup provides a function to get the grouping settings of
+// the row, column or filter field by given field name and pivot table
+// options.
+func getPivotTableFieldGroup(name string, opts *PivotTableOptions) *PivotTableFieldGroup {
+	for _, fields := range [][]PivotTableField{opts.Rows, opts.Columns, opts.Filter} {
+		for _, field := range fields {
+			if field.Data == name && field.GroupBy != nil {
+				return field.GroupBy
+			}
 		}
-		return true
-	})
-	return count
+	}
+	return nil
 }
 
-// countPivotCache provides a function to get pivot table cache definition files
-// count storage in the folder xl/pivotCache.
-func (f *File) countPivotCache() int {
-	count := 0
-	f.Pkg.Range(func(k, v interface{}) bool {
-		if strings.Contains(k.(string), "xl/pivotCache/pivotCacheDefinition") {
-			count++
+// getPivotGroupItems provides a function to create the items of the pivot
+// table field by given number of the group items.
+func getPivotGroupItems(count int, defaultSubtotal *bool) *xlsxItems {
+	items := &xlsxItems{}
+	for x := 0; x < count; x++ {
+		idx := x
+		items.Item = append(items.Item, &xlsxItem{X: &idx})
+	}
+	if defaultSubtotal == nil || *defaultSubtotal {
+		items.Item = append(items.Item, &xlsxItem{T: "default"})
+	}
+	items.Count = len(items.Item)
+	return items
+}
+
+// newPivotCacheFieldGroup provides a function to create the pivot cache field
+// with the grouping settings by given pivot table options, worksheet name and
+// coordinates of the source data range, column number of the field and the
+// grouping settings.
+func (f *File) newPivotCacheFieldGroup(opts *PivotTableOptions, sheet string, coordinates []int, col int, group *PivotTableFieldGroup) (*xlsxCacheField, error) {
+	by := strings.ToLower(group.By)
+	if inStrSlice([]string{"days", "months", "quarters", "years", "range"}, by, true) == -1 {
+		return nil, ErrParameterInvalid
+	}
+	var values []float64
+	for row := coordinates[1] + 1; row <= coordinates[3]; row++ {
+		val, _, err := f.getPivotDataCellValue(opts, sheet, col, row, true)
+		if err != nil {
+			return nil, err
 		}
-		return true
-	})
-	return count
+		if num, err := strconv.ParseFloat(val, 64); err == nil {
+			values = append(values, num)
+		}
+	}
+	var minValue, maxValue float64
+	for i, num := range values {
+		if i == 0 || num < minValue {
+			minValue = num
+		}
+		if i == 0 || num > maxValue {
+			maxValue = num
+		}
+	}
+	if by == "range" {
+		return newPivotCacheFieldRangeGroup(group, values, minValue, maxValue)
+	}
+	wb, err := f.workbookReader()
+	if err != nil {
+		return nil, err
+	}
+	var date1904 bool
+	if wb != nil && wb.WorkbookPr != nil {
+		date1904 = wb.WorkbookPr.Date1904
+	}
+	startDate, endDate := group.StartDate, group.EndDate
+	if startDate.IsZero() {
+		startDate = timeFromExcelTime(minValue, date1904)
+	}
+	if endDate.IsZero() {
+		endDate = timeFromExcelTime(maxValue, date1904)
+	}
+	if endDate.Before(startDate) {
+		return nil, ErrParameterInvalid
+	}
+	layout := "2006-01-02T15:04:05"
+	items := []string{"<" + startDate.Format("1/2/2006")}
+	switch by {
+	case "days":
+		for day := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC); day.Year() == 2000; day = day.AddDate(0, 0, 1) {
+			items = append(items, day.Format("2-Jan"))
+		}
+	case "months":
+		for month := time.January; month <= time.December; month++ {
+			items = append(items, month.String()[:3])
+		}
+	case "quarters":
+		items = append(items, "Qtr1", "Qtr2", "Qtr3", "Qtr4")
+	case "years":
+		for year := startDate.Year(); year <= endDate.Year(); year++ {
+			items = append(items, strconv.Itoa(year))
+		}
+	}
+	items = append(items, ">"+endDate.Format("1/2/2006"))
+	cacheField := &xlsxCacheField{
+		SharedItems: &xlsxSharedItems{
+			ContainsSemiMixedTypes: boolPtr(false), ContainsNonDate: boolPtr(false),
+			ContainsDate: true, ContainsString: boolPtr(false),
+			MinDate: startDate.Format(layout), MaxDate: endDate.Format(layout),
+		},
+		FieldGroup: &xlsxFieldGroup{
+			RangePr: &xlsxRangePr{
+				AutoStart: boolPtr(group.StartDate.IsZero()), AutoEnd: boolPtr(group.EndDate.IsZero()),
+				GroupBy: by, StartDate: startDate.Format(layout), EndDate: endDate.Format(layout),
+			},
+			GroupItems: &xlsxGroupItems{Count: len(items)},
+		},
+	}
+	for _, item := range items {
+		cacheField.FieldGroup.GroupItems.S = append(cacheField.FieldGroup.GroupItems.S, xlsxString{V: item})
+	}
+	return cacheField, err
+}
+
+// newPivotCacheFieldRangeGroup provides a function to create the pivot cache
+// field with the numeric range grouping settings by given grouping settings,
+// the numeric values of the source data and the minimum and maximum values.
+func newPivotCacheFieldRangeGroup(group *PivotTableFieldGroup, values []float64, minValue, maxValue float64) (*xlsxCacheField, error) {
+	interval, auto := group.Interval, group.StartNum == 0 && group.EndNum == 0
+	if interval == 0 {
+		interval = 1
+	}
+	startNum, endNum := group.StartNum, group.EndNum
+	if auto {
+		startNum, endNum = minValue, maxValue
+	}
+	if interval < 0 || endNum < startNum {
+		return nil, ErrParameterInvalid
+	}
+	formatNum := func(num float64) string { return strconv.FormatFloat(num, 'f', -1, 64) }
+	isInteger := startNum == math.Trunc(startNum) && interval == math.Trunc(interval)
+	items := []string{"<" + formatNum(startNum)}
+	for lower := startNum; lower <= endNum; lower += interval {
+		upper := lower + interval
+		if isInteger {
+			upper--
+		}
+		items = append(items, formatNum(lower)+"-"+formatNum(upper))
+	}
+	items = append(items, ">"+formatNum(endNum))
+	cacheField := &xlsxCacheField{
+		SharedItems: &xlsxSharedItems{
+			ContainsSemiMixedTypes: boolPtr(false), ContainsString: boolPtr(false),
+			ContainsNumber: len(values) > 0, ContainsInteger: len(values) > 0,
+			MinValue: minValue, MaxValue: maxValue,
+		},
+		FieldGroup: &xlsxFieldGroup{
+			RangePr: &xlsxRangePr{
+				AutoStart: boolPtr(auto), AutoEnd: boolPtr(auto),
+				StartNum: float64Ptr(startNum), EndNum: float64Ptr(endNum), GroupInterval: interval,
+			},
+			GroupItems: &xlsxGroupItems{Count: len(items)},
+		},
+	}
+	for _, num := range values {
+		cacheField.SharedItems.ContainsInteger = cacheField.SharedItems.ContainsInteger && num == math.Trunc(num)
+	}
+	for _, item := range items {
+		cacheField.FieldGroup.GroupItems.S = append(cacheField.FieldGroup.GroupItems.S, xlsxString{V: item})
+	}
+	return cacheField, nil
+}
+
+// getUnusedPartID provides a function to get the minimum ID of the XML part
+// which doesn't exist in the package by given part name prefix, for example,
+// the ID 2 will be returned for the prefix xl/pivotTables/pivotTable if the
+// part xl/pivotTables/pivotTable1.xml already exists.
+func (f *File) getUnusedPartID(prefix string) int {
+	for ID := 1; ; ID++ {
+		if !f.hasPart(prefix + strconv.Itoa(ID) + ".xml") {
+			return ID
+		}
+	}
 }
 
 // getPivotFieldsIndex convert the column of the first row in the data region
@@ -714,7 +1304,21 @@ func (f *File) addWorkbookPivotCache(RID int) int {
 }
 
 // GetPivotTables returns all pivot table definitions in a worksheet by given
-// worksheet name.
+// worksheet name. The data range, the rows, columns, data and filter fields,
+// the subtotals and the style settings will be parsed from the pivot table
+// and pivot cache definition parts, and the field names will be read from the
+// pivot cache, so the returned options could be used to create the same
+// pivot table by the AddPivotTable function. For example, get the pivot
+// tables on Sheet1:
+//
+//	pivotTables, err := f.GetPivotTables("Sheet1")
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	for _, pivotTable := range pivotTables {
+//	    fmt.Println(pivotTable.Name, pivotTable.DataRange, pivotTable.PivotTableRange)
+//	}
 func (f *File) GetPivotTables(sheet string) ([]PivotTableOptions, error) {
 	var pivotTables []PivotTableOptions
 	name, ok := f.getSheetXMLPath(sheet)
@@ -731,7 +1335,7 @@ func (f *File) GetPivotTables(sheet string) ([]PivotTableOptions, error) {
 	}
 	for _, v := range sheetRels.Relationships {
 		if v.Type == SourceRelationshipPivotTable {
-			pivotTableXML := strings.ReplaceAll(v.Target, "..", "xl")
+			pivotTableXML := getPivotTablePartPath(v.Target)
 			pivotCacheRels := "xl/pivotTables/_rels/" + filepath.Base(v.Target) + ".rels"
 			pivotTable, err := f.getPivotTable(sheet, pivotTableXML, pivotCacheRels)
 			if err != nil {
@@ -746,6 +1350,12 @@ func (f *File) GetPivotTables(sheet string) ([]PivotTableOptions, error) {
 // getPivotTableDataRange checking given if data range is a cell reference or
 // named reference (defined name or table name), and set pivot table data range.
 func (f *File) getPivotTableDataRange(opts *PivotTableOptions) error {
+	if opts.DataSource != nil {
+		if opts.DataRange != "" || inStrSlice([]string{"", "external", "scenario"}, opts.CacheSourceType, false) == -1 {
+			return newPivotTableDataRangeError(ErrParameterInvalid.Error())
+		}
+		return nil
+	}
 	if opts.DataRange == "" {
 		return newPivotTableDataRangeError(ErrParameterRequired.Error())
 	}
@@ -790,7 +1400,7 @@ func (f *File) getPivotTable(sheet, pivotTableXML, pivotCacheRels string) (Pivot
 	var pivotCacheXML string
 	for _, v := range rels.Relationships {
 		if v.Type == SourceRelationshipPivotCache {
-			pivotCacheXML = strings.ReplaceAll(v.Target, "..", "xl")
+			pivotCacheXML = getPivotTablePartPath(v.Target)
 			break
 		}
 	}
@@ -806,13 +1416,24 @@ func (f *File) getPivotTable(sheet, pivotTableXML, pivotCacheRels string) (Pivot
 		pivotTableXML:   pivotTableXML,
 		pivotCacheXML:   pivotCacheXML,
 		pivotSheetName:  sheet,
-		DataRange:       fmt.Sprintf("%s!%s", sheet, pc.CacheSource.WorksheetSource.Ref),
 		PivotTableRange: fmt.Sprintf("%s!%s", sheet, pt.Location.Ref),
 		Name:            pt.Name,
 	}
-	if pc.CacheSource.WorksheetSource.Name != "" {
-		opts.DataRange = pc.CacheSource.WorksheetSource.Name
-		_ = f.getPivotTableDataRange(&opts)
+	if pc.CacheSource != nil && pc.CacheSource.WorksheetSource != nil {
+		dataSheet := sheet
+		if pc.CacheSource.WorksheetSource.Sheet != "" {
+			dataSheet = pc.CacheSource.WorksheetSource.Sheet
+		}
+		opts.DataRange = fmt.Sprintf("%s!%s", dataSheet, pc.CacheSource.WorksheetSource.Ref)
+		if pc.CacheSource.WorksheetSource.Name != "" {
+			opts.DataRange = pc.CacheSource.WorksheetSource.Name
+			_ = f.getPivotTableDataRange(&opts)
+		}
+	} else if pc.CacheSource != nil {
+		opts.CacheSourceType = pc.CacheSource.Type
+		if opts.DataSource, err = f.getPivotCacheDataSource(pc, pivotCacheXML); err != nil {
+			return opts, err
+		}
 	}
 	fields := []string{"RowGrandTotals", "ColGrandTotals", "ShowDrill", "UseAutoFormatting", "PageOverThenDown", "MergeItem", "CompactData", "ShowError"}
 	immutable, mutable := reflect.ValueOf(*pt), reflect.ValueOf(&opts).Elem()
@@ -830,14 +1451,156 @@ func (f *File) getPivotTable(sheet, pivotTableXML, pivotCacheRels string) (Pivot
 		opts.ShowLastColumn = si.ShowLastColumn
 		opts.PivotTableStyleName = si.Name
 	}
-	order, err := f.getTableFieldsOrder(&opts)
+	order, err := f.getPivotCacheFieldsOrder(pc, &opts)
 	if err != nil {
 		return opts, err
 	}
 	f.extractPivotTableFields(order, pt, &opts)
+	if pc.CacheFields != nil {
+		for _, fields := range [][]PivotTableField{opts.Rows, opts.Columns, opts.Filter} {
+			for i := range fields {
+				idx := inStrSlice(order, fields[i].Data, true)
+				if idx != -1 && idx < len(pc.CacheFields.CacheField) {
+					fields[i].GroupBy = extractPivotTableFieldGroup(pc.CacheFields.CacheField[idx].FieldGroup)
+				}
+			}
+		}
+	}
 	return opts, err
 }
 
+// getPivotCacheDataSource provides a function to get the cached dataset by
+// given pivot cache definition and the path of it, the first row of the
+// dataset is the names of the cache fields, and the other rows are the
+// values of the pivot cache records.
+func (f *File) getPivotCacheDataSource(pc *xlsxPivotCacheDefinition, pivotCacheXML string) ([][]interface{}, error) {
+	var (
+		fields      [][]interface{}
+		cacheFields decodePivotCacheFields
+	)
+	dataSource := [][]interface{}{{}}
+	if content, ok := f.Pkg.Load(pivotCacheXML); ok && content != nil {
+		if err := f.xmlNewDecoder(bytes.NewReader(namespaceStrictToTransitional(content.([]byte)))).
+			Decode(&cacheFields); err != nil && err != io.EOF {
+			return dataSource, err
+		}
+	}
+	if pc.CacheFields != nil {
+		for i, cacheField := range pc.CacheFields.CacheField {
+			var items []interface{}
+			if i < len(cacheFields.CacheField) {
+				for _, item := range cacheFields.CacheField[i].SharedItems.Items {
+					items = append(items, getPivotCacheSharedItemValue(item))
+				}
+			}
+			dataSource[0], fields = append(dataSource[0], cacheField.Name), append(fields, items)
+		}
+	}
+	rels, err := f.relsReader(getPartRelsPath(pivotCacheXML))
+	if err != nil || rels == nil {
+		return dataSource, err
+	}
+	for _, rel := range rels.Relationships {
+		if rel.ID != pc.RID || rel.Type != SourceRelationshipPivotCacheRecords {
+			continue
+		}
+		content, ok := f.Pkg.Load(getRelsTargetPath(pivotCacheXML, rel.Target))
+		if !ok {
+			break
+		}
+		var records xlsxPivotCacheRecords
+		if err = f.xmlNewDecoder(bytes.NewReader(namespaceStrictToTransitional(content.([]byte)))).
+			Decode(&records); err != nil && err != io.EOF {
+			return dataSource, err
+		}
+		for _, r := range records.R {
+			record := make([]interface{}, len(fields))
+			for i, x := range r.X {
+				if i < len(fields) && x.V < len(fields[i]) {
+					record[i] = fields[i][x.V]
+				}
+			}
+			dataSource = append(dataSource, record)
+		}
+	}
+	return dataSource, nil
+}
+
+// getPivotCacheSharedItemValue provides a function to get the value of the
+// shared item of the pivot cache field, the value of the missing item will be
+// nil.
+func getPivotCacheSharedItemValue(item xlsxSharedItem) interface{} {
+	switch item.XMLName.Local {
+	case "n":
+		num, _ := strconv.ParseFloat(item.V, 64)
+		return num
+	case "b":
+		return item.V == "1" || strings.EqualFold(item.V, "true")
+	case "d":
+		if t, err := time.Parse("2006-01-02T15:04:05", item.V); err == nil {
+			return t
+		}
+		return item.V
+	case "e", "s":
+		return item.V
+	}
+	return nil
+}
+
+// extractPivotTableFieldGroup provides a function to extract the grouping
+// settings of the pivot table field by given field group of the pivot cache
+// field.
+func extractPivotTableFieldGroup(fieldGroup *xlsxFieldGroup) *PivotTableFieldGroup {
+	if fieldGroup == nil || fieldGroup.RangePr == nil {
+		return nil
+	}
+	rangePr := fieldGroup.RangePr
+	group := &PivotTableFieldGroup{By: "Range", Interval: rangePr.GroupInterval}
+	if rangePr.GroupBy != "" && rangePr.GroupBy != "range" {
+		group.By = cases.Title(language.English).String(rangePr.GroupBy)
+	}
+	if rangePr.AutoStart != nil && !*rangePr.AutoStart {
+		if rangePr.StartNum != nil {
+			group.StartNum = *rangePr.StartNum
+		}
+		group.StartDate, _ = time.Parse("2006-01-02T15:04:05", rangePr.StartDate)
+	}
+	if rangePr.AutoEnd != nil && !*rangePr.AutoEnd {
+		if rangePr.EndNum != nil {
+			group.EndNum = *rangePr.EndNum
+		}
+		group.EndDate, _ = time.Parse("2006-01-02T15:04:05", rangePr.EndDate)
+	}
+	return group
+}
+
+// getPivotTablePartPath provides a function to get the path of the pivot
+// table or pivot cache part by given relationship target, which could be a
+// relative path or an absolute path in the package.
+func getPivotTablePartPath(target string) string {
+	if strings.HasPrefix(target, "/") {
+		return strings.TrimPrefix(target, "/")
+	}
+	return strings.ReplaceAll(target, "..", "xl")
+}
+
+// getPivotCacheFieldsOrder provides a function to get the names of the source
+// data fields by given pivot cache definition, the names will be read from
+// the header row of the data range if the pivot cache has no fields.
+func (f *File) getPivotCacheFieldsOrder(pc *xlsxPivotCacheDefinition, opts *PivotTableOptions) ([]string, error) {
+	if pc.CacheFields == nil || len(pc.CacheFields.CacheField) == 0 {
+		return f.getTableFieldsOrder(opts)
+	}
+	var order []string
+	if err := f.getPivotTableDataRange(opts); err != nil {
+		return order, err
+	}
+	for _, field := range pc.CacheFields.CacheField {
+		order = append(order, field.Name)
+	}
+	return order, nil
+}
+
 // pivotTableReader provides a function to get the pointer to the structure
 // after deserialization of xl/pivotTables/pivotTable%d.xml.
 func (f *File) pivotTableReader(path string) (*xlsxPivotTableDefinition, error) {
@@ -870,6 +1633,9 @@ func (f *File) pivotCacheReader(path string) (*xlsxPivotCacheDefinition, error)
 // settings by given pivot table fields.
 func (f *File) extractPivotTableFields(order []string, pt *xlsxPivotTableDefinition, opts *PivotTableOptions) {
 	for fieldIdx, field := range pt.PivotFields.PivotField {
+		if fieldIdx >= len(order) {
+			break
+		}
 		if field.Axis == "axisRow" {
 			opts.Rows = append(opts.Rows, extractPivotTableField(order[fieldIdx], field))
 		}
@@ -882,10 +1648,17 @@ func (f *File) extractPivotTableFields(order []string, pt *xlsxPivotTableDefinit
 	}
 	if pt.DataFields != nil {
 		for _, field := range pt.DataFields.DataField {
+			if field.Fld >= len(order) {
+				continue
+			}
+			subtotal := "Sum"
+			if field.Subtotal != "" {
+				subtotal = cases.Title(language.English).String(field.Subtotal)
+			}
 			opts.Data = append(opts.Data, PivotTableField{
 				Data:     order[field.Fld],
 				Name:     field.Name,
-				Subtotal: cases.Title(language.English).String(field.Subtotal),
+				Subtotal: subtotal,
 			})
 		}
 	}
@@ -941,8 +1714,8 @@ func (f *File) genPivotCacheDefinitionID() int {
 	return ID + 1
 }
 
-// deleteWorkbookPivotCache remove workbook pivot cache and pivot cache
-// relationships.
+// deleteWorkbookPivotCache remove workbook pivot cache, pivot cache
+// relationships, the pivot cache definition and records parts.
 func (f *File) deleteWorkbookPivotCache(opt PivotTableOptions) error {
 	rID, err := f.deleteWorkbookRels(SourceRelationshipPivotCache, strings.TrimPrefix(strings.TrimPrefix(opt.pivotCacheXML, "/"), "xl/"))
 	if err != nil {
@@ -962,29 +1735,116 @@ func (f *File) deleteWorkbookPivotCache(opt PivotTableOptions) error {
 			wb.PivotCaches = nil
 		}
 	}
-	return err
+	rels, err := f.relsReader(getPartRelsPath(opt.pivotCacheXML))
+	if err != nil {
+		return err
+	}
+	if rels != nil {
+		for _, rel := range rels.Relationships {
+			if rel.Type == SourceRelationshipPivotCacheRecords {
+				if err = f.deletePart(getRelsTargetPath(opt.pivotCacheXML, rel.Target)); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return f.deletePart(opt.pivotCacheXML)
 }
 
 // DeletePivotTable delete a pivot table by giving the worksheet name and pivot
-// table name. Note that this function does not clean cell values in the pivot
-// table range.
+// table name. The pivot table part, the relationships and content types of
+// it will be removed, and the pivot cache definition and records parts will
+// be removed too if the pivot cache isn't used by other pivot tables. Note
+// that this function does not clean cell values in the pivot table range.
 func (f *File) DeletePivotTable(sheet, name string) error {
+	rID, opt, err := f.getPivotTableByName(sheet, name)
+	if err != nil {
+		return err
+	}
+	return f.deletePivotTable(sheet, rID, opt)
+}
+
+// SetPivotTable provides a function to modify the pivot table by given
+// worksheet name, pivot table name and pivot table options. The existing
+// pivot table part, the pivot cache definition and records parts which only
+// used by the pivot table will be removed, and the pivot table will be
+// created by given options with the unused part names, so the pivot table could be regenerated repeatedly without
+// leaving the unused parts in the workbook. The name of the pivot table will
+// be kept if the name in the options is empty. Note that this function does
+// not clean cell values in the original pivot table range. For example,
+// change the data field of the pivot table named PivotTable1 on Sheet1:
+//
+//	pivotTables, err := f.GetPivotTables("Sheet1")
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	for _, pivotTable := range pivotTables {
+//	    if pivotTable.Name == "PivotTable1" {
+//	        pivotTable.Data = []excelize.PivotTableField{{Data: "Sales", Subtotal: "Average"}}
+//	        if err := f.SetPivotTable("Sheet1", "PivotTable1", &pivotTable); err != nil {
+//	            fmt.Println(err)
+//	        }
+//	    }
+//	}
+func (f *File) SetPivotTable(sheet, name string, opts *PivotTableOptions) error {
+	if opts == nil {
+		return ErrParameterRequired
+	}
+	rID, opt, err := f.getPivotTableByName(sheet, name)
+	if err != nil {
+		return err
+	}
+	if opts.Name == "" {
+		opts.Name = name
+	}
+	opts.pivotDataRange, opts.fieldGroups = "", nil
+	if _, _, err = f.parseFormatPivotTableSet(opts); err != nil {
+		return err
+	}
+	if err = f.deletePivotTable(sheet, rID, opt); err != nil {
+		return err
+	}
+	return f.AddPivotTable(opts)
+}
+
+// getPivotTableByName provides a function to get the relationship ID and the
+// options of the pivot table by given worksheet name and pivot table name.
+func (f *File) getPivotTableByName(sheet, name string) (string, PivotTableOptions, error) {
+	var opt PivotTableOptions
 	sheetXML, ok := f.getSheetXMLPath(sheet)
 	if !ok {
-		return ErrSheetNotExist{sheet}
+		return "", opt, ErrSheetNotExist{sheet}
 	}
-	rels := "xl/worksheets/_rels/" + strings.TrimPrefix(sheetXML, "xl/worksheets/") + ".rels"
-	sheetRels, err := f.relsReader(rels)
+	sheetRels, err := f.relsReader(getPartRelsPath(sheetXML))
 	if err != nil {
-		return err
+		return "", opt, err
 	}
 	if sheetRels == nil {
 		sheetRels = &xlsxRelationships{}
 	}
 	opts, err := f.GetPivotTables(sheet)
 	if err != nil {
-		return err
+		return "", opt, err
 	}
+	for _, v := range sheetRels.Relationships {
+		if v.Type != SourceRelationshipPivotTable {
+			continue
+		}
+		for _, opt = range opts {
+			if opt.Name == name && opt.pivotTableXML == getPivotTablePartPath(v.Target) {
+				return v.ID, opt, err
+			}
+		}
+	}
+	return "", PivotTableOptions{}, newNoExistTableError(name)
+}
+
+// deletePivotTable provides a function to delete the pivot table part and the
+// worksheet relationship of it by given worksheet name, relationship ID and
+// pivot table options, the pivot cache will be deleted if it isn't used by
+// other pivot tables.
+func (f *File) deletePivotTable(sheet, rID string, opt PivotTableOptions) error {
 	pivotTableCaches := map[string]int{}
 	for _, sheetName := range f.GetSheetList() {
 		sheetPivotTables, _ := f.GetPivotTables(sheetName)
@@ -992,19 +1852,280 @@ func (f *File) DeletePivotTable(sheet, name string) error {
 			pivotTableCaches[sheetPivotTable.pivotCacheXML]++
 		}
 	}
-	for _, v := range sheetRels.Relationships {
-		for _, opt := range opts {
-			if v.Type == SourceRelationshipPivotTable {
-				pivotTableXML := strings.ReplaceAll(v.Target, "..", "xl")
-				if opt.Name == name && opt.pivotTableXML == pivotTableXML {
-					if pivotTableCaches[opt.pivotCacheXML] == 1 {
-						err = f.deleteWorkbookPivotCache(opt)
-					}
-					f.deleteSheetRelationships(sheet, v.ID)
-					return err
+	if pivotTableCaches[opt.pivotCacheXML] == 1 {
+		if err := f.deleteWorkbookPivotCache(opt); err != nil {
+			return err
+		}
+	}
+	f.deleteSheetRelationships(sheet, rID)
+	return f.deletePart(opt.pivotTableXML)
+}
+
+// pivotCacheFieldItems directly maps the shared items of a pivot cache field
+// collected from the source data in order of first appearance, each value
+// will be nil for the blank item, or the type of float64, bool or string.
+type pivotCacheFieldItems struct {
+	values  []interface{}
+	numbers []float64
+	idx     map[interface{}]int
+}
+
+// RefreshPivotCache provides a function to refresh the pivot cache of the
+// pivot table by given pivot table name. This function re-reads the source
+// data range of the pivot table, rebuilds the cache fields with the shared
+// items and the pivot cache records, and updates the records count, so that
+// the pivot cache will be consistent with the source data which has been
+// changed after the pivot table was created. The items of the row, column
+// and filter fields of all pivot tables that share the same pivot cache will
+// be updated with the shared items, except the grouped fields which will keep
+// the group items. Note that this function doesn't update the cell values in
+// the pivot table range. For example, refresh the pivot
+// cache of the pivot table named PivotTable1:
+//
+//	err := f.RefreshPivotCache("PivotTable1")
+func (f *File) RefreshPivotCache(name string) error {
+	var opts *PivotTableOptions
+	var pivotTables []PivotTableOptions
+	for _, sheet := range f.GetSheetList() {
+		sheetPivotTables, err := f.GetPivotTables(sheet)
+		if err != nil {
+			return err
+		}
+		for i := range sheetPivotTables {
+			if opts == nil && sheetPivotTables[i].Name == name {
+				opts = &sheetPivotTables[i]
+			}
+		}
+		pivotTables = append(pivotTables, sheetPivotTables...)
+	}
+	if opts == nil {
+		return newNoExistTableError(name)
+	}
+	return f.refreshPivotCache(opts, pivotTables)
+}
+
+// refreshPivotCache provides a function to rebuild the pivot cache fields and
+// records of the pivot table by given pivot table options, and update the
+// items of the fields in the pivot tables which share the same pivot cache.
+func (f *File) refreshPivotCache(opts *PivotTableOptions, pivotTables []PivotTableOptions) error {
+	pc, err := f.pivotCacheReader(opts.pivotCacheXML)
+	if err != nil {
+		return err
+	}
+	order, err := f.getTableFieldsOrder(opts)
+	if err != nil {
+		return err
+	}
+	items, records, err := f.getPivotCacheRecords(opts, len(order))
+	if err != nil {
+		return err
+	}
+	pts := map[string]*xlsxPivotTableDefinition{}
+	for _, pivotTable := range pivotTables {
+		if pivotTable.pivotCacheXML != opts.pivotCacheXML {
+			continue
+		}
+		pt, err := f.pivotTableReader(pivotTable.pivotTableXML)
+		if err != nil {
+			return err
+		}
+		if pt.PivotFields != nil && len(pt.PivotFields.PivotField) != len(order) {
+			return newPivotTableDataRangeError(ErrParameterInvalid.Error())
+		}
+		pts[pivotTable.pivotTableXML] = pt
+	}
+	fieldGroups := map[int]*xlsxFieldGroup{}
+	if pc.CacheFields != nil {
+		for i, cacheField := range pc.CacheFields.CacheField {
+			if i < len(order) && cacheField.Name == order[i] && cacheField.FieldGroup != nil {
+				fieldGroups[i] = cacheField.FieldGroup
+			}
+		}
+	}
+	pc.CacheFields = &xlsxCacheFields{Count: len(order)}
+	for i, name := range order {
+		pc.CacheFields.CacheField = append(pc.CacheFields.CacheField, &xlsxCacheField{
+			Name: name, SharedItems: items[i].sharedItems(), FieldGroup: fieldGroups[i],
+		})
+	}
+	pc.SaveData, pc.RecordCount = true, len(records.R)
+	if pc.RID, err = f.setPivotCacheRecords(opts.pivotCacheXML, pc.RID, records); err != nil {
+		return err
+	}
+	pivotCache, err := xml.Marshal(pc)
+	if err != nil {
+		return err
+	}
+	f.saveFileList(opts.pivotCacheXML, pivotCache)
+	for pivotTableXML, pt := range pts {
+		if pt.PivotFields != nil {
+			for fieldIdx, field := range pt.PivotFields.PivotField {
+				if _, ok := fieldGroups[fieldIdx]; !ok && field.Axis != "" {
+					field.Items = items[fieldIdx].pivotItems(field.DefaultSubtotal)
+				}
+			}
+		}
+		pivotTable, err := xml.Marshal(pt)
+		if err != nil {
+			return err
+		}
+		f.saveFileList(pivotTableXML, pivotTable)
+	}
+	return err
+}
+
+// getPivotCacheRecords provides a function to read the source data of the
+// pivot table, and returns the shared items of each field and the pivot cache
+// records by given pivot table options and the number of fields.
+func (f *File) getPivotCacheRecords(opts *PivotTableOptions, fields int) ([]*pivotCacheFieldItems, *xlsxPivotCacheRecords, error) {
+	items := make([]*pivotCacheFieldItems, fields)
+	for i := range items {
+		items[i] = &pivotCacheFieldItems{idx: map[interface{}]int{}}
+	}
+	records := &xlsxPivotCacheRecords{}
+	dataSheet, coordinates, err := f.getPivotDataRange(opts)
+	if err != nil {
+		return items, records, err
+	}
+	var values [][]interface{}
+	for row := coordinates[1] + 1; row <= coordinates[3]; row++ {
+		record := make([]interface{}, fields)
+		for col := coordinates[0]; col <= coordinates[2]; col++ {
+			val, cellType, err := f.getPivotDataCellValue(opts, dataSheet, col, row, true)
+			if err != nil {
+				return items, records, err
+			}
+			record[col-coordinates[0]] = items[col-coordinates[0]].add(val, cellType)
+		}
+		values = append(values, record)
+	}
+	for _, record := range values {
+		r := &xlsxPivotCacheRecord{}
+		for i, value := range record {
+			r.X = append(r.X, &xlsxX{V: items[i].index(value)})
+		}
+		records.R = append(records.R, r)
+	}
+	records.Count = len(records.R)
+	return items, records, err
+}
+
+// add provides a function to add the cell value to the shared items of the
+// pivot cache field, and returns the value which stored in the field.
+func (items *pivotCacheFieldItems) add(val string, cellType CellType) interface{} {
+	var value interface{}
+	switch {
+	case val == "":
+	case cellType == CellTypeBool:
+		value = val == "1" || strings.EqualFold(val, "TRUE")
+	case cellType == CellTypeUnset || cellType == CellTypeNumber:
+		value = val
+		if num, err := strconv.ParseFloat(val, 64); err == nil {
+			value = num
+		}
+	default:
+		value = val
+	}
+	if _, ok := items.idx[value]; !ok {
+		items.idx[value] = len(items.values)
+		items.values = append(items.values, value)
+		if num, ok := value.(float64); ok {
+			items.numbers = append(items.numbers, num)
+		}
+	}
+	return value
+}
+
+// index provides a function to get the index of the shared item by given
+// value stored in the field. The shared items are in order of first
+// appearance in the source data.
+func (items *pivotCacheFieldItems) index(value interface{}) int {
+	return items.idx[value]
+}
+
+// sharedItems provides a function to create the shared items of the pivot
+// cache field.
+func (items *pivotCacheFieldItems) sharedItems() *xlsxSharedItems {
+	si := &xlsxSharedItems{Count: len(items.values), ContainsNumber: len(items.numbers) > 0}
+	var types int
+	for _, value := range items.values {
+		item := xlsxSharedItem{}
+		switch v := value.(type) {
+		case nil:
+			item.XMLName.Local, si.ContainsBlank = "m", true
+		case float64:
+			item.XMLName.Local, item.V = "n", strconv.FormatFloat(v, 'f', -1, 64)
+		case bool:
+			item.XMLName.Local, item.V = "b", "0"
+			if v {
+				item.V = "1"
+			}
+			types |= 1
+		case string:
+			item.XMLName.Local, item.V = "s", v
+			types |= 2
+		}
+		si.Items = append(si.Items, item)
+	}
+	if types&2 == 0 {
+		si.ContainsString = boolPtr(false)
+		if types == 0 {
+			si.ContainsSemiMixedTypes = boolPtr(false)
+		}
+	}
+	if si.ContainsNumber {
+		types |= 4
+		si.ContainsInteger, si.MinValue, si.MaxValue = true, items.numbers[0], items.numbers[0]
+	}
+	si.ContainsMixedTypes = types != 0 && types&(types-1) != 0
+	for _, num := range items.numbers {
+		si.ContainsInteger = si.ContainsInteger && num == math.Trunc(num)
+		si.MinValue, si.MaxValue = math.Min(si.MinValue, num), math.Max(si.MaxValue, num)
+	}
+	return si
+}
+
+// pivotItems provides a function to create the items of the pivot table field
+// by the shared items of the pivot cache field.
+func (items *pivotCacheFieldItems) pivotItems(defaultSubtotal *bool) *xlsxItems {
+	return getPivotGroupItems(len(items.values), defaultSubtotal)
+}
+
+// setPivotCacheRecords provides a function to save the pivot cache records
+// part by given pivot cache definition path, relationship ID of the records
+// part and the pivot cache records, and returns the relationship ID of the
+// records part.
+func (f *File) setPivotCacheRecords(pivotCacheXML, rID string, records *xlsxPivotCacheRecords) (string, error) {
+	dir, base := "", pivotCacheXML
+	if idx := strings.LastIndex(pivotCacheXML, "/"); idx != -1 {
+		dir, base = pivotCacheXML[:idx+1], pivotCacheXML[idx+1:]
+	}
+	relsPath, recordsXML := dir+"_rels/"+base+".rels", ""
+	rels, err := f.relsReader(relsPath)
+	if err != nil {
+		return rID, err
+	}
+	if rels != nil {
+		for _, rel := range rels.Relationships {
+			if rel.ID == rID && rel.Type == SourceRelationshipPivotCacheRecords {
+				recordsXML = dir + rel.Target
+				if strings.HasPrefix(rel.Target, "/") {
+					recordsXML = strings.TrimPrefix(rel.Target, "/")
 				}
 			}
 		}
 	}
-	return newNoExistTableError(name)
+	if recordsXML == "" {
+		target := strings.Replace(base, "pivotCacheDefinition", "pivotCacheRecords", 1)
+		if target == base {
+			target = "pivotCacheRecords" + base
+		}
+		rID, recordsXML = "rId"+strconv.Itoa(f.addRels(relsPath, SourceRelationshipPivotCacheRecords, target, "")), dir+target
+		if err = f.setContentTypes("/"+recordsXML, ContentTypeSpreadSheetMLPivotCacheRecords); err != nil {
+			return rID, err
+		}
+	}
+	output, err := xml.Marshal(records)
+	f.saveFileList(recordsXML, output)
+	return rID, err
 }
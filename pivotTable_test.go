@@ -1,11 +1,14 @@
 package excelize_ch
 
 import (
+	"encoding/xml"
 	"fmt"
 	"math/rand"
 	"path/filepath"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -338,6 +341,597 @@ func TestPivotTable(t *testing.T) {
 	assert.NoError(t, f.Close())
 }
 
+func TestAddPivotTableAutoRange(t *testing.T) {
+	f := NewFile()
+	assert.NoError(t, f.SetSheetRow("Sheet1", "A1", &[]string{"Month", "Year", "Type", "Sales", "Region"}))
+	for row, record := range [][]interface{}{
+		{"Jan", 2017, "Meat", 100, "East"},
+		{"Jan", 2018, "Dairy", 200, "West"},
+		{"Feb", 2017, "Meat", 300, "East"},
+		{"Mar", 2019, "Produce", 400, "North"},
+	} {
+		assert.NoError(t, f.SetSheetRow("Sheet1", fmt.Sprintf("A%d", row+2), &record))
+	}
+	for _, c := range []struct {
+		opts     PivotTableOptions
+		expected string
+	}{
+		{opts: PivotTableOptions{PivotTableRange: "Sheet1!G2", Data: []PivotTableField{{Data: "Sales"}}}, expected: "Sheet1!G2:G3"},
+		{opts: PivotTableOptions{PivotTableRange: "Sheet1!$G$2", Rows: []PivotTableField{{Data: "Month"}}, Data: []PivotTableField{{Data: "Sales"}}}, expected: "Sheet1!G2:H5"},
+		{opts: PivotTableOptions{PivotTableRange: "Sheet1!G2", Rows: []PivotTableField{{Data: "Month"}, {Data: "Year"}}, Data: []PivotTableField{{Data: "Sales"}}, ColGrandTotals: true}, expected: "Sheet1!G2:H10"},
+		{opts: PivotTableOptions{PivotTableRange: "Sheet1!G2", Rows: []PivotTableField{{Data: "Month"}}, Columns: []PivotTableField{{Data: "Type"}}, Data: []PivotTableField{{Data: "Sales"}}, RowGrandTotals: true}, expected: "Sheet1!G2:K6"},
+		{opts: PivotTableOptions{PivotTableRange: "Sheet1!G2", Rows: []PivotTableField{{Data: "Month"}}, Columns: []PivotTableField{{Data: "Year", DefaultSubtotal: true}, {Data: "Type"}}, Data: []PivotTableField{{Data: "Sales"}, {Data: "Year", Subtotal: "Count"}}}, expected: "Sheet1!G2:S8"},
+		{opts: PivotTableOptions{PivotTableRange: "Sheet1!G2", Rows: []PivotTableField{{Data: "Month"}}, Filter: []PivotTableField{{Data: "Region"}}, Data: []PivotTableField{{Data: "Sales"}}}, expected: "Sheet1!G4:H7"},
+		{opts: PivotTableOptions{NewSheet: "Pivot", Rows: []PivotTableField{{Data: "Month"}}, Data: []PivotTableField{{Data: "Sales"}}}, expected: "Pivot!A3:B6"},
+	} {
+		opts := c.opts
+		opts.DataRange = "Sheet1!A1:E5"
+		assert.NoError(t, f.AddPivotTable(&opts))
+		assert.Equal(t, c.expected, opts.PivotTableRange)
+	}
+	pivotTables, err := f.GetPivotTables("Pivot")
+	assert.NoError(t, err)
+	assert.Len(t, pivotTables, 1)
+	assert.Equal(t, "Pivot!A3:B6", pivotTables[0].PivotTableRange)
+	// Test add pivot table on the exists new worksheet
+	assert.Equal(t, ErrExistsSheet, f.AddPivotTable(&PivotTableOptions{DataRange: "Sheet1!A1:E5", NewSheet: "Pivot"}))
+	// Test add pivot table on new worksheet with pivot table range
+	assert.EqualError(t, f.AddPivotTable(&PivotTableOptions{DataRange: "Sheet1!A1:E5", NewSheet: "Sheet2", PivotTableRange: "Sheet2!A1"}), newPivotTableRangeError(ErrParameterInvalid.Error()).Error())
+	// Test add pivot table on new worksheet with invalid sheet name
+	assert.Equal(t, ErrSheetNameInvalid, f.AddPivotTable(&PivotTableOptions{DataRange: "Sheet1!A1:E5", NewSheet: "Sheet:1"}))
+	// Test add pivot table with invalid top-left cell reference
+	assert.EqualError(t, f.AddPivotTable(&PivotTableOptions{DataRange: "Sheet1!A1:E5", PivotTableRange: "Sheet1!A"}), newPivotTableRangeError(newCellNameToCoordinatesError("A", newInvalidCellNameError("A")).Error()).Error())
+	// Test add pivot table with the range exceeds the worksheet
+	assert.EqualError(t, f.AddPivotTable(&PivotTableOptions{DataRange: "Sheet1!A1:E5", PivotTableRange: "Sheet1!XFD1", Rows: []PivotTableField{{Data: "Month"}}}), newPivotTableRangeError(ErrColumnNumber.Error()).Error())
+	// Test add pivot table with invalid data range
+	assert.EqualError(t, f.AddPivotTable(&PivotTableOptions{DataRange: "Sheet1!A1", NewSheet: "Sheet2"}), newPivotTableDataRangeError(ErrParameterInvalid.Error()).Error())
+	assert.Equal(t, -1, f.getSheetID("Sheet2"))
+	assert.EqualError(t, f.AddPivotTable(&PivotTableOptions{NewSheet: "Sheet2"}), newPivotTableDataRangeError(ErrParameterRequired.Error()).Error())
+	// Test add pivot table with invalid name length
+	assert.Equal(t, ErrNameLength, f.AddPivotTable(&PivotTableOptions{DataRange: "Sheet1!A1:E5", NewSheet: "Sheet2", Name: strings.Repeat("c", MaxFieldLength+1)}))
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestAddPivotTableAutoRange.xlsx")))
+	// Test add pivot table with unsupported charset data worksheet
+	f.Sheet.Delete("xl/worksheets/sheet1.xml")
+	f.Pkg.Store("xl/worksheets/sheet1.xml", MacintoshCyrillicCharset)
+	f.checked = sync.Map{}
+	assert.EqualError(t, f.AddPivotTable(&PivotTableOptions{DataRange: "Sheet1!A1:E5", NewSheet: "Sheet2", Rows: []PivotTableField{{Data: "Month"}}}), "XML syntax error on line 1: invalid UTF-8")
+	assert.NoError(t, f.Close())
+}
+
+func TestGetPivotTables(t *testing.T) {
+	f := NewFile()
+	assert.NoError(t, f.SetSheetRow("Sheet1", "A1", &[]string{"Month", "Year", "Type", "Sales", "Region"}))
+	for row, record := range [][]interface{}{
+		{"Jan", 2017, "Meat", 100, "East"},
+		{"Feb", 2018, "Dairy", 200, "West"},
+	} {
+		assert.NoError(t, f.SetSheetRow("Sheet1", fmt.Sprintf("A%d", row+2), &record))
+	}
+	_, err := f.NewSheet("Report Sheet")
+	assert.NoError(t, err)
+	opts := &PivotTableOptions{
+		DataRange:       "Sheet1!A1:E3",
+		PivotTableRange: "Report Sheet!A3",
+		Rows:            []PivotTableField{{Data: "Month"}},
+		Columns:         []PivotTableField{{Data: "Type"}},
+		Data:            []PivotTableField{{Data: "Sales", Subtotal: "Max"}},
+		Filter:          []PivotTableField{{Data: "Region"}},
+	}
+	assert.NoError(t, f.AddPivotTable(opts))
+	// Test get pivot table with the data range on another worksheet, and the
+	// field names have been changed after creating the pivot cache
+	assert.NoError(t, f.SetCellValue("Sheet1", "A1", "Period"))
+	pivotTables, err := f.GetPivotTables("Report Sheet")
+	assert.NoError(t, err)
+	assert.Len(t, pivotTables, 1)
+	assert.Equal(t, "Sheet1!A1:E3", pivotTables[0].DataRange)
+	assert.Equal(t, opts.PivotTableRange, pivotTables[0].PivotTableRange)
+	assert.Equal(t, []PivotTableField{{Data: "Month"}}, pivotTables[0].Rows)
+	assert.Equal(t, []PivotTableField{{Data: "Type"}}, pivotTables[0].Columns)
+	assert.Equal(t, []PivotTableField{{Data: "Region"}}, pivotTables[0].Filter)
+	assert.Equal(t, []PivotTableField{{Data: "Sales", Subtotal: "Max"}}, pivotTables[0].Data)
+
+	// Test get pivot table with default subtotal and absolute relationship targets
+	pt, err := f.pivotTableReader(pivotTables[0].pivotTableXML)
+	assert.NoError(t, err)
+	pt.DataFields.DataField[0].Subtotal = ""
+	output, err := xml.Marshal(pt)
+	assert.NoError(t, err)
+	f.Pkg.Store(pivotTables[0].pivotTableXML, output)
+	rels, err := f.relsReader("xl/worksheets/_rels/sheet2.xml.rels")
+	assert.NoError(t, err)
+	rels.Relationships[0].Target = "/" + pivotTables[0].pivotTableXML
+	pivotTables, err = f.GetPivotTables("Report Sheet")
+	assert.NoError(t, err)
+	assert.Len(t, pivotTables, 1)
+	assert.Equal(t, []PivotTableField{{Data: "Sales", Subtotal: "Sum"}}, pivotTables[0].Data)
+	assert.NoError(t, f.Close())
+}
+
+func TestSetPivotTable(t *testing.T) {
+	f := NewFile()
+	assert.NoError(t, f.SetSheetRow("Sheet1", "A1", &[]string{"Month", "Year", "Type", "Sales"}))
+	for row, record := range [][]interface{}{
+		{"Jan", 2017, "Meat", 100},
+		{"Feb", 2018, "Dairy", 200},
+		{"Jan", 2018, "Meat", 300},
+	} {
+		assert.NoError(t, f.SetSheetRow("Sheet1", fmt.Sprintf("A%d", row+2), &record))
+	}
+	opts := &PivotTableOptions{
+		DataRange:       "Sheet1!A1:D4",
+		PivotTableRange: "Sheet1!G2",
+		Name:            "PivotTable1",
+		Rows:            []PivotTableField{{Data: "Month"}},
+		Data:            []PivotTableField{{Data: "Sales"}},
+	}
+	assert.NoError(t, f.AddPivotTable(opts))
+	assert.NoError(t, f.RefreshPivotCache("PivotTable1"))
+	for i := 0; i < 2; i++ {
+		assert.NoError(t, f.SetPivotTable("Sheet1", "PivotTable1", &PivotTableOptions{
+			DataRange:       "Sheet1!A1:D4",
+			PivotTableRange: "Sheet1!G2",
+			Rows:            []PivotTableField{{Data: "Year"}},
+			Data:            []PivotTableField{{Data: "Sales", Subtotal: "Average"}},
+		}))
+	}
+	pivotTables, err := f.GetPivotTables("Sheet1")
+	assert.NoError(t, err)
+	assert.Len(t, pivotTables, 1)
+	assert.Equal(t, "PivotTable1", pivotTables[0].Name)
+	assert.Equal(t, []PivotTableField{{Data: "Year"}}, pivotTables[0].Rows)
+	assert.Equal(t, "Average", pivotTables[0].Data[0].Subtotal)
+	assert.Equal(t, "xl/pivotTables/pivotTable1.xml", pivotTables[0].pivotTableXML)
+	assert.Equal(t, "xl/pivotCache/pivotCacheDefinition1.xml", pivotTables[0].pivotCacheXML)
+	// Test the unused pivot cache records part has been removed
+	_, ok := f.Pkg.Load("xl/pivotCache/pivotCacheRecords1.xml")
+	assert.False(t, ok)
+	wb, err := f.workbookReader()
+	assert.NoError(t, err)
+	assert.Len(t, wb.PivotCaches.PivotCache, 1)
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestSetPivotTable.xlsx")))
+
+	// Test set pivot table with nil options
+	assert.Equal(t, ErrParameterRequired, f.SetPivotTable("Sheet1", "PivotTable1", nil))
+	// Test set pivot table with not exists worksheet
+	assert.EqualError(t, f.SetPivotTable("SheetN", "PivotTable1", opts), "sheet SheetN does not exist")
+	// Test set pivot table with not exists pivot table name
+	assert.EqualError(t, f.SetPivotTable("Sheet1", "PivotTableN", opts), "table PivotTableN does not exist")
+	// Test set pivot table with invalid options, the original pivot table should be kept
+	assert.EqualError(t, f.SetPivotTable("Sheet1", "PivotTable1", &PivotTableOptions{
+		DataRange:       "Sheet1!A1:D4",
+		PivotTableRange: "SheetN!G2",
+		Rows:            []PivotTableField{{Data: "Year"}},
+	}), "sheet SheetN does not exist")
+	pivotTables, err = f.GetPivotTables("Sheet1")
+	assert.NoError(t, err)
+	assert.Len(t, pivotTables, 1)
+
+	// Test delete pivot table removes all the parts of it
+	assert.NoError(t, f.DeletePivotTable("Sheet1", "PivotTable1"))
+	f.Pkg.Range(func(k, v interface{}) bool {
+		assert.NotContains(t, k.(string), "pivot")
+		return true
+	})
+	content, err := f.contentTypesReader()
+	assert.NoError(t, err)
+	for _, override := range content.Overrides {
+		assert.NotContains(t, override.PartName, "pivot")
+	}
+	wb, err = f.workbookReader()
+	assert.NoError(t, err)
+	assert.Nil(t, wb.PivotCaches)
+	assert.NoError(t, f.Close())
+}
+
+func TestPivotTableDataSource(t *testing.T) {
+	f := NewFile()
+	dataSource := [][]interface{}{
+		{"Month", "Year", "Type", "Sales"},
+		{"Jan", 2017, "Meat", 100},
+		{"Feb", 2017, "Dairy", 200.5},
+		{"Jan", 2018, nil, 300},
+		{"Mar", 2018, true, time.Date(2018, 3, 1, 0, 0, 0, 0, time.UTC)},
+	}
+	opts := &PivotTableOptions{
+		DataSource:      dataSource,
+		PivotTableRange: "Sheet1!A1",
+		Name:            "PivotTable1",
+		Rows:            []PivotTableField{{Data: "Month"}},
+		Columns:         []PivotTableField{{Data: "Year"}},
+		Data:            []PivotTableField{{Data: "Sales"}},
+	}
+	assert.NoError(t, f.AddPivotTable(opts))
+	assert.Equal(t, "Sheet1!A1:C5", opts.PivotTableRange)
+	pc, err := f.pivotCacheReader("xl/pivotCache/pivotCacheDefinition1.xml")
+	assert.NoError(t, err)
+	assert.Equal(t, "external", pc.CacheSource.Type)
+	assert.Nil(t, pc.CacheSource.WorksheetSource)
+	assert.True(t, pc.SaveData)
+	assert.False(t, pc.RefreshOnLoad)
+	assert.Equal(t, 4, pc.RecordCount)
+	assert.Equal(t, []xlsxString{{V: "Jan"}, {V: "Feb"}, {V: "Mar"}}, pc.CacheFields.CacheField[0].SharedItems.S)
+	_, ok := f.Pkg.Load("xl/pivotCache/pivotCacheRecords1.xml")
+	assert.True(t, ok)
+	// Test the cached dataset doesn't appear on any worksheet
+	rows, err := f.GetRows("Sheet1")
+	assert.NoError(t, err)
+	assert.Empty(t, rows)
+	pivotTables, err := f.GetPivotTables("Sheet1")
+	assert.NoError(t, err)
+	assert.Len(t, pivotTables, 1)
+	assert.Empty(t, pivotTables[0].DataRange)
+	assert.Equal(t, "external", pivotTables[0].CacheSourceType)
+	assert.Equal(t, [][]interface{}{
+		{"Month", "Year", "Type", "Sales"},
+		{"Jan", 2017.0, "Meat", 100.0},
+		{"Feb", 2017.0, "Dairy", 200.5},
+		{"Jan", 2018.0, nil, 300.0},
+		{"Mar", 2018.0, true, 43160.0},
+	}, pivotTables[0].DataSource)
+	assert.Equal(t, opts.Rows, pivotTables[0].Rows)
+	assert.NoError(t, f.RefreshPivotCache("PivotTable1"))
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestPivotTableDataSource.xlsx")))
+
+	// Test add pivot table with the scenario cache source
+	assert.NoError(t, f.AddPivotTable(&PivotTableOptions{
+		DataSource:      dataSource,
+		CacheSourceType: "Scenario",
+		PivotTableRange: "Sheet1!G1:J10",
+		Rows:            []PivotTableField{{Data: "Type"}},
+		Data:            []PivotTableField{{Data: "Sales", Subtotal: "Count"}},
+	}))
+	pc, err = f.pivotCacheReader("xl/pivotCache/pivotCacheDefinition2.xml")
+	assert.NoError(t, err)
+	assert.Equal(t, "scenario", pc.CacheSource.Type)
+	// Test add pivot table with the cached dataset on a new worksheet
+	opts = &PivotTableOptions{
+		DataSource:     [][]interface{}{{"Region", "Sales"}, {"East", 1}, {"West", 2}, {"East", 3}},
+		NewSheet:       "Summary",
+		Rows:           []PivotTableField{{Data: "Region"}},
+		Data:           []PivotTableField{{Data: "Sales"}},
+		RowGrandTotals: true,
+		ColGrandTotals: true,
+	}
+	assert.NoError(t, f.AddPivotTable(opts))
+	assert.Equal(t, "Summary!A3:B6", opts.PivotTableRange)
+	assert.Equal(t, []string{"Sheet1", "Summary"}, f.GetSheetList())
+	pc, err = f.pivotCacheReader("xl/pivotCache/pivotCacheDefinition3.xml")
+	assert.NoError(t, err)
+	assert.Equal(t, 3, pc.RecordCount)
+	assert.Equal(t, []xlsxString{{V: "East"}, {V: "West"}}, pc.CacheFields.CacheField[0].SharedItems.S)
+	assert.Equal(t, []xlsxNumber{{V: 1}, {V: 2}, {V: 3}}, pc.CacheFields.CacheField[1].SharedItems.N)
+	// Test add pivot table with invalid cached dataset
+	for _, opts := range []*PivotTableOptions{
+		{DataSource: dataSource, DataRange: "Sheet1!A1:D5"},
+		{DataSource: dataSource, CacheSourceType: "worksheet"},
+		{DataSource: dataSource[:1]},
+		{DataSource: [][]interface{}{}},
+	} {
+		opts.PivotTableRange, opts.Rows = "Sheet1!G20:J30", []PivotTableField{{Data: "Month"}}
+		assert.EqualError(t, f.AddPivotTable(opts), newPivotTableDataRangeError(ErrParameterInvalid.Error()).Error())
+	}
+	// Test get the cached dataset with unsupported charset pivot cache records
+	f.Pkg.Store("xl/pivotCache/pivotCacheRecords1.xml", MacintoshCyrillicCharset)
+	_, err = f.GetPivotTables("Sheet1")
+	assert.EqualError(t, err, "XML syntax error on line 1: invalid UTF-8")
+	assert.NoError(t, f.Close())
+	// Test get the cached dataset with the pivot cache definition without records
+	dataSource, err = f.getPivotCacheDataSource(&xlsxPivotCacheDefinition{}, "xl/pivotCache/pivotCacheDefinition3.xml")
+	assert.NoError(t, err)
+	assert.Equal(t, [][]interface{}{{}}, dataSource)
+}
+
+func TestPivotTableDataSourceMixedTypes(t *testing.T) {
+	f := NewFile()
+	assert.NoError(t, f.AddPivotTable(&PivotTableOptions{
+		DataSource: [][]interface{}{
+			{"Item", "Sales"}, {"B", 1}, {2, 2}, {true, 3}, {nil, 4}, {"A", 5}, {2, 6}, {false, 7}, {"B", 8}, {true, 9},
+		},
+		PivotTableRange: "Sheet1!A1:C20",
+		Name:            "PivotTable1",
+		Rows:            []PivotTableField{{Data: "Item"}},
+		Data:            []PivotTableField{{Data: "Sales"}},
+	}))
+	content, ok := f.Pkg.Load("xl/pivotCache/pivotCacheDefinition1.xml")
+	assert.True(t, ok)
+	assert.Contains(t, string(content.([]byte)), `<sharedItems containsBlank="true" containsMixedTypes="true" containsNumber="true" containsInteger="true" minValue="2" maxValue="2" count="6"><s v="B"></s><n v="2"></n><b v="1"></b><m></m><s v="A"></s><b v="0"></b></sharedItems>`)
+	content, ok = f.Pkg.Load("xl/pivotCache/pivotCacheRecords1.xml")
+	assert.True(t, ok)
+	records := xlsxPivotCacheRecords{}
+	assert.NoError(t, xml.Unmarshal(content.([]byte), &records))
+	var items []int
+	for _, r := range records.R {
+		items = append(items, r.X[0].V)
+	}
+	assert.Equal(t, []int{0, 1, 2, 3, 4, 1, 5, 0, 2}, items)
+	pivotTables, err := f.GetPivotTables("Sheet1")
+	assert.NoError(t, err)
+	assert.Len(t, pivotTables, 1)
+	assert.Equal(t, [][]interface{}{
+		{"Item", "Sales"}, {"B", 1.0}, {2.0, 2.0}, {true, 3.0}, {nil, 4.0}, {"A", 5.0}, {2.0, 6.0}, {false, 7.0}, {"B", 8.0}, {true, 9.0},
+	}, pivotTables[0].DataSource)
+	assert.NoError(t, f.Close())
+}
+
+func TestAddRecommendedPivotTable(t *testing.T) {
+	f := NewFile()
+	assert.NoError(t, f.SetSheetRow("Sheet1", "A1", &[]string{"Region", "Date", "Sales", "Units", "Note"}))
+	for i, region := range []string{"East", "West", "East", "North"} {
+		cell, err := CoordinatesToCellName(1, i+2)
+		assert.NoError(t, err)
+		assert.NoError(t, f.SetSheetRow("Sheet1", cell, &[]interface{}{
+			region, time.Date(2022+i%2, 3, i+1, 0, 0, 0, 0, time.UTC), 100 * (i + 1), i + 1, i,
+		}))
+	}
+	assert.NoError(t, f.SetCellValue("Sheet1", "E3", "N/A"))
+	assert.NoError(t, f.AddTable("Sheet1", &Table{Range: "A1:E5", Name: "Table1"}))
+	opts, err := f.AddRecommendedPivotTable("Table1", "Summary")
+	assert.NoError(t, err)
+	assert.Equal(t, "Summary!A3:C7", opts.PivotTableRange)
+	assert.Equal(t, []PivotTableField{{Data: "Region"}}, opts.Rows)
+	assert.Equal(t, []PivotTableField{
+		{Data: "Sales", Name: "Sum of Sales", Subtotal: "Sum"},
+		{Data: "Units", Name: "Sum of Units", Subtotal: "Sum"},
+	}, opts.Data)
+	pivotTables, err := f.GetPivotTables("Summary")
+	assert.NoError(t, err)
+	assert.Len(t, pivotTables, 1)
+	assert.Equal(t, "PivotTable1", pivotTables[0].Name)
+	chart, ok := f.Pkg.Load("xl/charts/chart1.xml")
+	assert.True(t, ok)
+	assert.Contains(t, string(chart.([]byte)), "<pivotSource><name>Summary!PivotTable1</name><fmtId val=\"0\"></fmtId></pivotSource>")
+	assert.Contains(t, string(chart.([]byte)), "<f>Summary!$A$4:$A$6</f>")
+	assert.Contains(t, string(chart.([]byte)), "<f>Summary!$C$4:$C$6</f>")
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestAddRecommendedPivotTable.xlsx")))
+
+	// Test add recommended pivot table with the date field as the row field
+	assert.NoError(t, f.AddTable("Sheet1", &Table{Range: "G1:H5", Name: "Table2"}))
+	assert.NoError(t, f.SetSheetCol("Sheet1", "G1", &[]interface{}{"Date", time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)}))
+	assert.NoError(t, f.SetCellValue("Sheet1", "H1", "Items"))
+	opts, err = f.AddRecommendedPivotTable("Table2", "Summary 2")
+	assert.NoError(t, err)
+	assert.Equal(t, []PivotTableField{{Data: "Date", GroupBy: &PivotTableFieldGroup{By: "Years"}}}, opts.Rows)
+	assert.Equal(t, []PivotTableField{{Data: "Date", Name: "Count of Date", Subtotal: "Count"}}, opts.Data)
+	chart, ok = f.Pkg.Load("xl/charts/chart2.xml")
+	assert.True(t, ok)
+	assert.Contains(t, string(chart.([]byte)), "<name>&#39;Summary 2&#39;!PivotTable2</name>")
+	// Test add recommended pivot table without categorical and date fields
+	assert.NoError(t, f.AddTable("Sheet1", &Table{Range: "C1:D5", Name: "Table3"}))
+	_, err = f.AddRecommendedPivotTable("Table3", "Summary3")
+	assert.Equal(t, newPivotTableDataRangeError(ErrParameterInvalid.Error()), err)
+	// Test add recommended pivot table with not exist table
+	_, err = f.AddRecommendedPivotTable("Table4", "Summary3")
+	assert.Equal(t, newPivotTableDataRangeError(ErrParameterInvalid.Error()), err)
+	// Test add recommended pivot table with exist worksheet name
+	_, err = f.AddRecommendedPivotTable("Table1", "Sheet1")
+	assert.Error(t, err)
+	// Test add recommended pivot table with unsupported charset worksheet
+	f.Sheet.Delete("xl/worksheets/sheet1.xml")
+	f.Pkg.Store("xl/worksheets/sheet1.xml", MacintoshCyrillicCharset)
+	f.checked.Delete("xl/worksheets/sheet1.xml")
+	_, err = f.AddRecommendedPivotTable("Sheet1!A1:E5", "Summary3")
+	assert.EqualError(t, err, "XML syntax error on line 1: invalid UTF-8")
+	assert.NoError(t, f.Close())
+}
+
+func TestRefreshPivotCache(t *testing.T) {
+	f := NewFile()
+	assert.NoError(t, f.SetSheetRow("Sheet1", "A1", &[]string{"Month", "Year", "Type", "Sales"}))
+	for row, record := range [][]interface{}{
+		{"Jan", 2017, "Meat", 100},
+		{"Feb", 2018, "Dairy", 200.5},
+		{"Jan", 2018, "Meat", 300},
+	} {
+		assert.NoError(t, f.SetSheetRow("Sheet1", fmt.Sprintf("A%d", row+2), &record))
+	}
+	opts := &PivotTableOptions{
+		DataRange:       "Sheet1!A1:D5",
+		PivotTableRange: "Sheet1!G2",
+		Name:            "PivotTable1",
+		Rows:            []PivotTableField{{Data: "Month", DefaultSubtotal: true}, {Data: "Year"}},
+		Columns:         []PivotTableField{{Data: "Type", DefaultSubtotal: true}},
+		Data:            []PivotTableField{{Data: "Sales"}},
+	}
+	assert.NoError(t, f.AddPivotTable(opts))
+	assert.NoError(t, f.RefreshPivotCache("PivotTable1"))
+
+	pc, err := f.pivotCacheReader("xl/pivotCache/pivotCacheDefinition1.xml")
+	assert.NoError(t, err)
+	assert.True(t, pc.SaveData)
+	assert.Equal(t, 4, pc.RecordCount)
+	assert.Equal(t, "rId1", pc.RID)
+	assert.Equal(t, 4, pc.CacheFields.Count)
+	month := pc.CacheFields.CacheField[0].SharedItems
+	assert.True(t, month.ContainsBlank)
+	assert.Equal(t, 3, month.Count)
+	assert.Equal(t, []xlsxString{{V: "Jan"}, {V: "Feb"}}, month.S)
+	sales := pc.CacheFields.CacheField[3].SharedItems
+	assert.Equal(t, boolPtr(false), sales.ContainsString)
+	assert.True(t, sales.ContainsNumber)
+	assert.False(t, sales.ContainsInteger)
+	assert.Equal(t, 100.0, sales.MinValue)
+	assert.Equal(t, 300.0, sales.MaxValue)
+	assert.Equal(t, []xlsxNumber{{V: 100}, {V: 200.5}, {V: 300}}, sales.N)
+
+	content, ok := f.Pkg.Load("xl/pivotCache/pivotCacheRecords1.xml")
+	assert.True(t, ok)
+	records := xlsxPivotCacheRecords{}
+	assert.NoError(t, xml.Unmarshal(content.([]byte), &records))
+	assert.Equal(t, 4, records.Count)
+	assert.Equal(t, []*xlsxX{{V: 0}, {V: 1}, {V: 0}, {V: 2}}, records.R[2].X)
+	assert.Equal(t, []*xlsxX{{V: 2}, {V: 2}, {V: 2}, {V: 3}}, records.R[3].X)
+
+	pt, err := f.pivotTableReader("xl/pivotTables/pivotTable1.xml")
+	assert.NoError(t, err)
+	assert.Equal(t, 4, pt.PivotFields.PivotField[0].Items.Count)
+	assert.Equal(t, "default", pt.PivotFields.PivotField[0].Items.Item[3].T)
+	assert.Equal(t, 3, pt.PivotFields.PivotField[1].Items.Count)
+	assert.Nil(t, pt.PivotFields.PivotField[3].Items)
+
+	// Test refresh pivot cache with the modified source data
+	assert.NoError(t, f.SetSheetRow("Sheet1", "A5", &[]interface{}{"Mar", 2019, "Beverages", 400}))
+	assert.NoError(t, f.RefreshPivotCache("PivotTable1"))
+	pc, err = f.pivotCacheReader("xl/pivotCache/pivotCacheDefinition1.xml")
+	assert.NoError(t, err)
+	assert.Equal(t, "rId1", pc.RID)
+	assert.False(t, pc.CacheFields.CacheField[0].SharedItems.ContainsBlank)
+	assert.Equal(t, 3, pc.CacheFields.CacheField[0].SharedItems.Count)
+	assert.True(t, pc.CacheFields.CacheField[1].SharedItems.ContainsInteger)
+	assert.Equal(t, 2017.0, pc.CacheFields.CacheField[1].SharedItems.MinValue)
+	assert.Equal(t, 2019.0, pc.CacheFields.CacheField[1].SharedItems.MaxValue)
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestRefreshPivotCache.xlsx")))
+
+	// Test refresh pivot cache with not exists pivot table
+	assert.EqualError(t, f.RefreshPivotCache("PivotTable2"), newNoExistTableError("PivotTable2").Error())
+	// Test refresh pivot cache with the changed number of fields
+	assert.NoError(t, f.SetCellValue("Sheet1", "E1", "Region"))
+	pc.CacheSource.WorksheetSource.Ref = "A1:E5"
+	output, err := xml.Marshal(pc)
+	assert.NoError(t, err)
+	f.Pkg.Store("xl/pivotCache/pivotCacheDefinition1.xml", output)
+	assert.EqualError(t, f.RefreshPivotCache("PivotTable1"), newPivotTableDataRangeError(ErrParameterInvalid.Error()).Error())
+	// Test refresh pivot cache with unsupported charset pivot cache records relationships
+	f.Relationships.Delete("xl/pivotCache/_rels/pivotCacheDefinition1.xml.rels")
+	f.Pkg.Store("xl/pivotCache/_rels/pivotCacheDefinition1.xml.rels", MacintoshCyrillicCharset)
+	_, err = f.setPivotCacheRecords("xl/pivotCache/pivotCacheDefinition1.xml", "rId1", &xlsxPivotCacheRecords{})
+	assert.EqualError(t, err, "XML syntax error on line 1: invalid UTF-8")
+	// Test refresh pivot cache with unsupported charset pivot cache
+	f.Pkg.Store("xl/pivotCache/pivotCacheDefinition1.xml", MacintoshCyrillicCharset)
+	assert.EqualError(t, f.RefreshPivotCache("PivotTable1"), "XML syntax error on line 1: invalid UTF-8")
+	assert.NoError(t, f.Close())
+
+	// Test refresh pivot cache with unsupported charset worksheet
+	f = NewFile()
+	assert.NoError(t, f.SetSheetRow("Sheet1", "A1", &[]string{"Month", "Sales"}))
+	assert.NoError(t, f.SetSheetRow("Sheet1", "A2", &[]interface{}{"Jan", 100}))
+	assert.NoError(t, f.AddPivotTable(&PivotTableOptions{
+		DataRange:       "Sheet1!A1:B2",
+		PivotTableRange: "Sheet1!G2",
+		Name:            "PivotTable1",
+		Rows:            []PivotTableField{{Data: "Month"}},
+		Data:            []PivotTableField{{Data: "Sales"}},
+	}))
+	_, _, err = f.getPivotCacheRecords(&PivotTableOptions{pivotDataRange: "Sheet1!A1"}, 1)
+	assert.EqualError(t, err, newPivotTableDataRangeError(ErrParameterInvalid.Error()).Error())
+	f.Sheet.Delete("xl/worksheets/sheet1.xml")
+	f.Pkg.Store("xl/worksheets/sheet1.xml", MacintoshCyrillicCharset)
+	f.checked = sync.Map{}
+	_, _, err = f.getPivotCacheRecords(&PivotTableOptions{pivotDataRange: "Sheet1!A1:B2"}, 2)
+	assert.EqualError(t, err, "XML syntax error on line 1: invalid UTF-8")
+	assert.NoError(t, f.Close())
+}
+
+func TestPivotTableFieldGroup(t *testing.T) {
+	f := NewFile()
+	assert.NoError(t, f.SetSheetRow("Sheet1", "A1", &[]string{"Date", "Score", "Region", "Sales"}))
+	for row, record := range [][]interface{}{
+		{time.Date(2017, 1, 15, 0, 0, 0, 0, time.UTC), 12, "East", 100},
+		{time.Date(2018, 6, 1, 0, 0, 0, 0, time.UTC), 47, "West", 200},
+		{time.Date(2019, 12, 31, 0, 0, 0, 0, time.UTC), 35, "East", 300},
+	} {
+		assert.NoError(t, f.SetSheetRow("Sheet1", fmt.Sprintf("A%d", row+2), &record))
+	}
+	opts := &PivotTableOptions{
+		DataRange:       "Sheet1!A1:D4",
+		PivotTableRange: "Sheet1!G2:M20",
+		Name:            "PivotTable1",
+		Rows:            []PivotTableField{{Data: "Date", GroupBy: &PivotTableFieldGroup{By: "Months"}}},
+		Columns:         []PivotTableField{{Data: "Score", DefaultSubtotal: true, GroupBy: &PivotTableFieldGroup{By: "Range", StartNum: 10, EndNum: 49, Interval: 10}}},
+		Filter:          []PivotTableField{{Data: "Region"}},
+		Data:            []PivotTableField{{Data: "Sales"}},
+	}
+	assert.NoError(t, f.AddPivotTable(opts))
+	pc, err := f.pivotCacheReader("xl/pivotCache/pivotCacheDefinition1.xml")
+	assert.NoError(t, err)
+	date := pc.CacheFields.CacheField[0]
+	assert.Equal(t, intPtr(0), date.FieldGroup.Base)
+	assert.Equal(t, &xlsxRangePr{
+		AutoStart: boolPtr(true), AutoEnd: boolPtr(true), GroupBy: "months",
+		StartDate: "2017-01-15T00:00:00", EndDate: "2019-12-31T00:00:00",
+	}, date.FieldGroup.RangePr)
+	assert.Equal(t, 14, date.FieldGroup.GroupItems.Count)
+	assert.Equal(t, xlsxString{V: "<1/15/2017"}, date.FieldGroup.GroupItems.S[0])
+	assert.Equal(t, xlsxString{V: "Jan"}, date.FieldGroup.GroupItems.S[1])
+	assert.Equal(t, xlsxString{V: ">12/31/2019"}, date.FieldGroup.GroupItems.S[13])
+	assert.True(t, date.SharedItems.ContainsDate)
+	assert.Equal(t, "2017-01-15T00:00:00", date.SharedItems.MinDate)
+	score := pc.CacheFields.CacheField[1]
+	assert.Equal(t, intPtr(1), score.FieldGroup.Base)
+	assert.Equal(t, &xlsxRangePr{
+		AutoStart: boolPtr(false), AutoEnd: boolPtr(false),
+		StartNum: float64Ptr(10), EndNum: float64Ptr(49), GroupInterval: 10,
+	}, score.FieldGroup.RangePr)
+	assert.Equal(t, []xlsxString{{V: "<10"}, {V: "10-19"}, {V: "20-29"}, {V: "30-39"}, {V: "40-49"}, {V: ">49"}}, score.FieldGroup.GroupItems.S)
+	assert.True(t, score.SharedItems.ContainsInteger)
+	assert.Equal(t, 12.0, score.SharedItems.MinValue)
+	assert.Equal(t, 47.0, score.SharedItems.MaxValue)
+	assert.Nil(t, pc.CacheFields.CacheField[2].FieldGroup)
+
+	pt, err := f.pivotTableReader("xl/pivotTables/pivotTable1.xml")
+	assert.NoError(t, err)
+	assert.Equal(t, 14, pt.PivotFields.PivotField[0].Items.Count)
+	assert.Equal(t, 7, pt.PivotFields.PivotField[1].Items.Count)
+	assert.Equal(t, "default", pt.PivotFields.PivotField[1].Items.Item[6].T)
+
+	// Test get pivot table with the grouped fields
+	pivotTables, err := f.GetPivotTables("Sheet1")
+	assert.NoError(t, err)
+	assert.Len(t, pivotTables, 1)
+	assert.Equal(t, &PivotTableFieldGroup{By: "Months"}, pivotTables[0].Rows[0].GroupBy)
+	assert.Equal(t, opts.Columns[0].GroupBy, pivotTables[0].Columns[0].GroupBy)
+	assert.Nil(t, pivotTables[0].Filter[0].GroupBy)
+
+	// Test refresh pivot cache with the grouped fields
+	assert.NoError(t, f.RefreshPivotCache("PivotTable1"))
+	pc, err = f.pivotCacheReader("xl/pivotCache/pivotCacheDefinition1.xml")
+	assert.NoError(t, err)
+	assert.Equal(t, date.FieldGroup, pc.CacheFields.CacheField[0].FieldGroup)
+	pt, err = f.pivotTableReader("xl/pivotTables/pivotTable1.xml")
+	assert.NoError(t, err)
+	assert.Equal(t, 14, pt.PivotFields.PivotField[0].Items.Count)
+	assert.Equal(t, 3, pt.PivotFields.PivotField[2].Items.Count)
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestPivotTableFieldGroup.xlsx")))
+
+	// Test add pivot table with the other date and numeric grouping settings
+	for _, group := range []*PivotTableFieldGroup{
+		{By: "Days"}, {By: "Quarters"},
+		{By: "years", StartDate: time.Date(2016, 1, 1, 0, 0, 0, 0, time.UTC), EndDate: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)},
+	} {
+		cacheField, err := f.newPivotCacheFieldGroup(&PivotTableOptions{}, "Sheet1", []int{1, 1, 4, 4}, 1, group)
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]int{"days": 368, "quarters": 6, "years": 7}[cacheField.FieldGroup.RangePr.GroupBy], cacheField.FieldGroup.GroupItems.Count)
+	}
+	cacheField, err := f.newPivotCacheFieldGroup(&PivotTableOptions{}, "Sheet1", []int{1, 1, 4, 4}, 2, &PivotTableFieldGroup{By: "Range", Interval: 12.5})
+	assert.NoError(t, err)
+	assert.Equal(t, []xlsxString{{V: "<12"}, {V: "12-24.5"}, {V: "24.5-37"}, {V: "37-49.5"}, {V: ">47"}}, cacheField.FieldGroup.GroupItems.S)
+	// Test add pivot table with invalid grouping settings
+	for _, group := range []*PivotTableFieldGroup{
+		{By: "Weeks"}, {By: "Range", Interval: -1}, {By: "Range", StartNum: 10, EndNum: 1},
+		{By: "Months", StartDate: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC), EndDate: time.Date(2016, 1, 1, 0, 0, 0, 0, time.UTC)},
+	} {
+		_, err = f.newPivotCacheFieldGroup(&PivotTableOptions{}, "Sheet1", []int{1, 1, 4, 4}, 1, group)
+		assert.Equal(t, ErrParameterInvalid, err)
+	}
+	opts.PivotTableRange, opts.Rows[0].GroupBy.By = "Sheet1!O2:U20", "Weeks"
+	assert.Equal(t, ErrParameterInvalid, f.AddPivotTable(opts))
+	// Test add pivot table with unsupported charset workbook
+	f.WorkBook = nil
+	f.Pkg.Store(defaultXMLPathWorkbook, MacintoshCyrillicCharset)
+	_, err = f.newPivotCacheFieldGroup(&PivotTableOptions{}, "Sheet1", []int{1, 1, 4, 4}, 1, &PivotTableFieldGroup{By: "Months"})
+	assert.EqualError(t, err, "XML syntax error on line 1: invalid UTF-8")
+	assert.NoError(t, f.Close())
+
+	// Test add pivot table with unsupported charset worksheet
+	f = NewFile()
+	f.Sheet.Delete("xl/worksheets/sheet1.xml")
+	f.Pkg.Store("xl/worksheets/sheet1.xml", MacintoshCyrillicCharset)
+	f.checked = sync.Map{}
+	_, err = f.newPivotCacheFieldGroup(&PivotTableOptions{}, "Sheet1", []int{1, 1, 4, 4}, 1, &PivotTableFieldGroup{By: "Months"})
+	assert.EqualError(t, err, "XML syntax error on line 1: invalid UTF-8")
+	assert.NoError(t, f.Close())
+}
+
 func TestPivotTableDataRange(t *testing.T) {
 	f := NewFile()
 	// Create table in a worksheet
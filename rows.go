@@ -16,19 +16,37 @@ import (
 	"encoding/xml"
 	"io"
 	"math"
+	"math/rand"
 	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"runtime"
+	"sort"
 	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
 
 	"github.com/mohae/deepcopy"
 )
 
+// sheetDataExp is the regular expression for matching the start element of
+// the sheet data in the worksheet with the optional namespace prefix.
+var sheetDataExp = regexp.MustCompile(`<(\w+:)?sheetData>`)
+
 // GetRows return all the rows in a sheet by given worksheet name, returned as
 // a two-dimensional array, where the value of the cell is converted to the
 // string type. If the cell format can be applied to the value of the cell,
 // the applied value will be used, otherwise the original value will be used.
 // GetRows fetched the rows with value or formula cells, the continually blank
 // cells in the tail of each row will be skipped, so the length of each row
-// may be inconsistent.
+// may be inconsistent. Set the PadRows field of the options to true to pad
+// each row with empty strings to the width of the used range, for example,
+// when building the tables or exporting the rows as CSV:
+//
+//	rows, err := f.GetRows("Sheet1", excelize.Options{PadRows: true})
 //
 // For example, get and traverse the value of all cells by rows on a worksheet
 // named 'Sheet1':
@@ -49,6 +67,14 @@ func (f *File) GetRows(sheet string, opts ...Options) ([][]string, error) {
 	if err != nil {
 		return nil, err
 	}
+	return rows.getRows(opts...)
+}
+
+// getRows provides a function to read all the rows by given rows iterator,
+// the rows will be read from an in-memory sorted copy of the worksheet if the
+// worksheet has out of order rows and the strict sheet data mode is disabled.
+func (rows *Rows) getRows(opts ...Options) ([][]string, error) {
+	var err error
 	results, cur, max := make([][]string, 0, 64), 0, 0
 	for rows.Next() {
 		cur++
@@ -61,25 +87,426 @@ func (f *File) GetRows(sheet string, opts ...Options) ([][]string, error) {
 			max = cur
 		}
 	}
-	return results[:max], rows.Close()
+	if rows.repaired {
+		if rows, err = rows.repairedRows(); err != nil {
+			return nil, err
+		}
+		return rows.getRows(opts...)
+	}
+	if results = results[:max]; getOptions(opts...).PadRows {
+		padRows(results)
+	}
+	if err = rows.Error(); err != nil {
+		_ = rows.Close()
+		return results, err
+	}
+	return results, rows.Close()
+}
+
+// padRows provides a function to pad each row with empty strings to the
+// length of the longest row by given rows.
+func padRows(rows [][]string) {
+	var width int
+	for _, row := range rows {
+		if len(row) > width {
+			width = len(row)
+		}
+	}
+	for idx, row := range rows {
+		if len(row) < width {
+			rows[idx] = append(row, make([]string, width-len(row))...)
+		}
+	}
+}
+
+// SampleMethod is the type of the method for sampling the rows.
+type SampleMethod byte
+
+// This section defines the currently supported methods for sampling the rows.
+const (
+	SampleSystematic SampleMethod = iota
+	SampleReservoir
+)
+
+// SampleOptions directly maps the settings of sampling the rows.
+type SampleOptions struct {
+	Method     SampleMethod
+	Step       int
+	Size       int
+	Seed       int64
+	HeaderRows int
+}
+
+// RowsSample directly maps the sampled rows and the overall number of rows in
+// the worksheet.
+type RowsSample struct {
+	Rows       [][]string
+	RowNumbers []int
+	TotalRows  int
+}
+
+// GetRowsSample provides a function to get a sample of the rows in a sheet by
+// given worksheet name and sample options, the rows will be returned like
+// GetRows with the row numbers and the overall number of rows in the
+// worksheet. The worksheet will be read as a stream, and only the cells of the
+// sampled rows will be parsed, so that the huge worksheets could be profiled
+// without a full read. The overall number of rows is the row number of the
+// last row element in the worksheet, which includes the empty rows. The
+// sample options that can be set are:
+//
+// Method: Specifies the method for sampling the rows, the SampleSystematic
+// method returns every Nth row, and the SampleReservoir method returns a
+// uniform random sample of the rows in a given size. The default value is
+// SampleSystematic.
+//
+// Step: Specifies the interval of the rows for the SampleSystematic method,
+// the first row after the header rows and every Nth row after it will be
+// returned. The default value is 1.
+//
+// Size: Specifies the maximum number of the sampled rows excluding the header
+// rows. This property is required for the SampleReservoir method, and the
+// SampleSystematic method returns all rows in the step if it is 0.
+//
+// Seed: Specifies the seed of the random number generator for the
+// SampleReservoir method, the same seed always returns the same sample of the
+// same worksheet. The default value is 0.
+//
+// HeaderRows: Specifies the number of rows at the beginning of the worksheet
+// which always be returned and not be sampled. The default value is 0.
+//
+// For example, get a random sample of 1000 rows with a header row on a
+// worksheet named 'Sheet1':
+//
+//	sample, err := f.GetRowsSample("Sheet1", &excelize.SampleOptions{
+//	    Method:     excelize.SampleReservoir,
+//	    Size:       1000,
+//	    HeaderRows: 1,
+//	})
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	fmt.Println(sample.TotalRows)
+//	for idx, row := range sample.Rows {
+//	    fmt.Println(sample.RowNumbers[idx], row)
+//	}
+func (f *File) GetRowsSample(sheet string, sample *SampleOptions, opts ...Options) (*RowsSample, error) {
+	if sample == nil || sample.Step < 0 || sample.Size < 0 || sample.HeaderRows < 0 ||
+		(sample.Method == SampleReservoir && sample.Size == 0) || sample.Method > SampleReservoir {
+		return nil, ErrParameterInvalid
+	}
+	rows, err := f.Rows(sheet)
+	if err != nil {
+		return nil, err
+	}
+	return rows.getRowsSample(sample, opts...)
+}
+
+// getRowsSample provides a function to read the sample of rows by given rows
+// iterator and sampling options, the rows will be read from an in-memory
+// sorted copy of the worksheet if the worksheet has out of order rows and the
+// strict sheet data mode is disabled.
+func (rows *Rows) getRowsSample(sample *SampleOptions, opts ...Options) (*RowsSample, error) {
+	type sampledRow struct {
+		rowNum int
+		cells  []string
+	}
+	var (
+		result  []sampledRow
+		total   int
+		step    = sample.Step
+		rnd     = rand.New(rand.NewSource(sample.Seed))
+		sampled int
+		err     error
+	)
+	if step == 0 {
+		step = 1
+	}
+	for rows.Next() {
+		total = rows.seekRow
+		idx := -1
+		switch n := total - sample.HeaderRows; {
+		case n <= 0:
+			idx = len(result)
+		case sample.Method == SampleSystematic:
+			if (n-1)%step == 0 && (sample.Size == 0 || sampled < sample.Size) {
+				idx = len(result)
+				sampled++
+			}
+		case sampled < sample.Size:
+			idx = len(result)
+			sampled++
+		default:
+			if j := rnd.Intn(n); j < sample.Size {
+				idx = len(result) - sample.Size + j
+			}
+		}
+		if idx == -1 {
+			// Skip the cells of the row element which not be sampled
+			if rows.token != nil && rows.curRow == rows.seekRow {
+				if rows.err = rows.decoder.Skip(); rows.err != nil {
+					break
+				}
+				rows.token = nil
+			}
+			continue
+		}
+		row, err := rows.Columns(opts...)
+		if err != nil {
+			break
+		}
+		if idx == len(result) {
+			result = append(result, sampledRow{rowNum: total, cells: row})
+			continue
+		}
+		result[idx] = sampledRow{rowNum: total, cells: row}
+	}
+	if rows.repaired {
+		if rows, err = rows.repairedRows(); err != nil {
+			return nil, err
+		}
+		return rows.getRowsSample(sample, opts...)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].rowNum < result[j].rowNum })
+	rowsSample := &RowsSample{TotalRows: total}
+	for _, row := range result {
+		rowsSample.Rows = append(rowsSample.Rows, row.cells)
+		rowsSample.RowNumbers = append(rowsSample.RowNumbers, row.rowNum)
+	}
+	if getOptions(opts...).PadRows {
+		padRows(rowsSample.Rows)
+	}
+	if err = rows.Error(); err != nil {
+		_ = rows.Close()
+		return rowsSample, err
+	}
+	return rowsSample, rows.Close()
+}
+
+// GetRowsParallel provides a function to get all the rows in a sheet like
+// GetRows by given worksheet name and number of workers, which splits the
+// sheet data of the worksheet into the row ranges and parses them
+// concurrently, the rows will be merged in order. The number of CPUs will be
+// used if the workers is less than or equal to 0. This function returns the
+// same result as the GetRows, and it will fall back to the GetRows when the
+// worksheet has malformed rows, such as out of order rows or the row without
+// row number, or the worksheet is too small to be split. The namespace prefix
+// of the sheet data and row elements, such as "x:sheetData", is supported. For
+// example, get all rows on a worksheet named 'Sheet1' with 8 workers:
+//
+//	rows, err := f.GetRowsParallel("Sheet1", 8)
+func (f *File) GetRowsParallel(sheet string, workers int, opts ...Options) ([][]string, error) {
+	if err := checkSheetName(sheet); err != nil {
+		return nil, err
+	}
+	name, ok := f.getSheetXMLPath(sheet)
+	if !ok {
+		return nil, ErrSheetNotExist{sheet}
+	}
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	if worksheet, ok := f.Sheet.Load(name); ok && worksheet != nil {
+		ws := worksheet.(*xlsxWorksheet)
+		ws.mu.Lock()
+		output, _ := xml.Marshal(ws)
+		f.saveFileList(name, f.replaceNameSpaceBytes(name, output))
+		ws.mu.Unlock()
+	}
+	content, err := f.readSheetBytes(name)
+	if err != nil {
+		return nil, err
+	}
+	chunks := splitSheetDataRows(content, workers)
+	if len(chunks) < 2 {
+		return f.GetRows(sheet, opts...)
+	}
+	sst, err := f.prepareParallelReader()
+	if err != nil {
+		return f.GetRows(sheet, opts...)
+	}
+	var (
+		wg      sync.WaitGroup
+		raw     = getOptions(opts...).RawCellValue
+		rowNums = make([][]int, len(chunks))
+		results = make([][][]string, len(chunks))
+		valid   = make([]bool, len(chunks))
+	)
+	for i, chunk := range chunks {
+		wg.Add(1)
+		go func(i int, chunk []byte) {
+			defer wg.Done()
+			rows := &Rows{f: f, sheet: name, sheetName: sheet, sst: sst, decoder: f.xmlNewDecoder(bytes.NewReader(chunk))}
+			rowNums[i], results[i], valid[i] = rows.parseRowsChunk(raw)
+		}(i, chunk)
+	}
+	wg.Wait()
+	var prev int
+	for i := range chunks {
+		if !valid[i] {
+			return f.GetRows(sheet, opts...)
+		}
+		for _, rowNum := range rowNums[i] {
+			if rowNum <= prev {
+				return f.GetRows(sheet, opts...)
+			}
+			prev = rowNum
+		}
+	}
+	var rows [][]string
+	for i := range chunks {
+		for j, rowNum := range rowNums[i] {
+			if len(results[i][j]) == 0 {
+				continue
+			}
+			if len(rows) < rowNum {
+				rows = append(rows, make([][]string, rowNum-len(rows))...)
+			}
+			rows[rowNum-1] = results[i][j]
+		}
+	}
+	if rows == nil {
+		rows = [][]string{}
+	}
+	if getOptions(opts...).PadRows {
+		padRows(rows)
+	}
+	return rows, err
+}
+
+// readSheetBytes provides a function to read the worksheet XML content by
+// given worksheet XML path, the content in the system temporary directory
+// will not be cached in memory.
+func (f *File) readSheetBytes(name string) ([]byte, error) {
+	if content := f.readXML(name); len(content) > 0 {
+		return content, nil
+	}
+	file, err := f.readTemp(name)
+	if err != nil || file == nil {
+		return nil, err
+	}
+	defer file.Close()
+	return io.ReadAll(file)
+}
+
+// prepareParallelReader provides a function to load the shared string table,
+// the style sheet and the workbook before parsing the rows concurrently.
+func (f *File) prepareParallelReader() (*xlsxSST, error) {
+	sst, err := f.sharedStringsReader()
+	if err != nil {
+		return sst, err
+	}
+	if _, ok := f.tempFiles.Load(defaultXMLPathSharedStrings); ok && f.sharedStringTemp == nil {
+		_ = f.getFromStringItem(0)
+	}
+	if _, err = f.stylesReader(); err != nil {
+		return sst, err
+	}
+	_, err = f.workbookReader()
+	return sst, err
+}
+
+// splitSheetDataRows provides a function to split the row elements of the
+// sheet data in the worksheet XML content into the given number of chunks by
+// the row element boundary, the sheet data and row elements with the same
+// namespace prefix will be matched. It returns nil if the sheet data is not
+// found or empty.
+func splitSheetDataRows(content []byte, n int) [][]byte {
+	loc := sheetDataExp.FindSubmatchIndex(content)
+	if loc == nil {
+		return nil
+	}
+	var prefix string
+	if loc[2] != -1 {
+		prefix = string(content[loc[2]:loc[3]])
+	}
+	start, end := loc[1], bytes.LastIndex(content, []byte("</"+prefix+"sheetData>"))
+	if end == -1 || end < start {
+		return nil
+	}
+	var (
+		chunks   [][]byte
+		rowStart = []byte("<" + prefix + "row ")
+		size     = (end - start) / n
+	)
+	for pos := start; pos < end; {
+		next := end
+		if size > 0 && pos+size < end {
+			if idx := bytes.Index(content[pos+size:end], rowStart); idx != -1 {
+				next = pos + size + idx
+			}
+		}
+		chunks = append(chunks, content[pos:next])
+		pos = next
+	}
+	return chunks
+}
+
+// parseRowsChunk provides a function to parse the row elements in the chunk
+// of the sheet data, returns the row numbers and the cell values of rows, and
+// whether the rows in the chunk are well-formed.
+func (rows *Rows) parseRowsChunk(raw bool) ([]int, [][]string, bool) {
+	var (
+		rowNums     []int
+		results     [][]string
+		rowIterator rowXMLIterator
+		inRow       bool
+	)
+	for {
+		token, _ := rows.decoder.Token()
+		if token == nil {
+			return rowNums, results, !inRow
+		}
+		switch xmlElement := token.(type) {
+		case xml.StartElement:
+			rowIterator.inElement = xmlElement.Name.Local
+			if rowIterator.inElement == "row" {
+				rowNum, _ := attrValToInt("r", xmlElement.Attr)
+				if rowNum <= 0 || inRow {
+					return rowNums, results, false
+				}
+				rowIterator, inRow = rowXMLIterator{}, true
+				rowNums = append(rowNums, rowNum)
+				continue
+			}
+			if rows.rowXMLHandler(&rowIterator, &xmlElement, raw); rowIterator.err != nil {
+				return rowNums, results, false
+			}
+		case xml.EndElement:
+			if xmlElement.Name.Local == "row" {
+				results, inRow = append(results, rowIterator.cells), false
+			}
+		}
+	}
 }
 
 // Rows defines an iterator to a sheet.
 type Rows struct {
-	err                     error
-	curRow, seekRow         int
-	needClose, rawCellValue bool
-	sheet                   string
-	f                       *File
-	tempFile                *os.File
-	sst                     *xlsxSST
-	decoder                 *xml.Decoder
-	token                   xml.Token
-	curRowOpts, seekRowOpts RowOpts
+	err                                error
+	curRow, seekRow                    int
+	needClose, rawCellValue, unordered bool
+	repaired                           bool
+	sheet, sheetName                   string
+	f                                  *File
+	tempFile                           *os.File
+	sst                                *xlsxSST
+	decoder                            *xml.Decoder
+	token                              xml.Token
+	curRowOpts, seekRowOpts            RowOpts
+	date1904                           bool
+	dateStyles                         map[int]bool
+	sharedFormulas                     map[int]xlsxC
+	structHeader                       map[string]int
 }
 
 // Next will return true if it finds the next row element.
 func (rows *Rows) Next() bool {
+	if rows.unordered {
+		if rows.f.options.StrictSheetData {
+			return false
+		}
+		return rows.nextRepaired(rows.seekRow + 1)
+	}
 	rows.seekRow++
 	if rows.curRow >= rows.seekRow {
 		rows.curRowOpts = rows.seekRowOpts
@@ -93,10 +520,18 @@ func (rows *Rows) Next() bool {
 		switch xmlElement := token.(type) {
 		case xml.StartElement:
 			if xmlElement.Name.Local == "row" {
+				prevRow := rows.curRow
 				rows.curRow++
 				if rowNum, _ := attrValToInt("r", xmlElement.Attr); rowNum != 0 {
 					rows.curRow = rowNum
 				}
+				if rows.curRow <= prevRow {
+					if rows.f.options.StrictSheetData {
+						rows.err, rows.unordered = newUnorderedRowError(rows.curRow), true
+						return false
+					}
+					return rows.nextRepaired(rows.seekRow)
+				}
 				rows.token = token
 				rows.curRowOpts = extractRowOpts(xmlElement.Attr)
 				return true
@@ -109,6 +544,80 @@ func (rows *Rows) Next() bool {
 	}
 }
 
+// nextRepaired provides a function to switch the rows iterator to the
+// in-memory sorted copy of the worksheet when the out of order or duplicated
+// row was found, and move the iterator to the given row number. The rows
+// which have been returned before switching will not be returned again.
+func (rows *Rows) nextRepaired(n int) bool {
+	repaired, err := rows.repairedRows()
+	if err != nil {
+		rows.err = err
+		return false
+	}
+	repaired.repaired, repaired.structHeader = true, rows.structHeader
+	*rows = *repaired
+	if n > 1 {
+		if err = rows.SeekRow(n); err != nil {
+			return false
+		}
+	}
+	return rows.Next()
+}
+
+// SeekRow will fast-forward the iterator to the given row number without
+// parsing the cells of the intermediate rows, the following Next call will
+// move the iterator to the given row. This function could be used to resume
+// paging through the worksheet with huge amounts of rows. Seeking to the row
+// before the current row is not supported. For example, iterate the rows
+// start from the row 1000 on a worksheet named 'Sheet1':
+//
+//	rows, err := f.Rows("Sheet1")
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	if err = rows.SeekRow(1000); err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	for rows.Next() {
+//	    row, err := rows.Columns()
+//	    if err != nil {
+//	        fmt.Println(err)
+//	    }
+//	    fmt.Println(row)
+//	}
+//	if err = rows.Close(); err != nil {
+//	    fmt.Println(err)
+//	}
+func (rows *Rows) SeekRow(n int) error {
+	if n < 1 || n > TotalRows {
+		return newInvalidRowNumberError(n)
+	}
+	if n <= rows.seekRow {
+		return newSeekRowError(n)
+	}
+	for rows.seekRow < n-1 {
+		// Skip the empty rows before the decoded row element
+		if rows.curRow > rows.seekRow {
+			if rows.seekRow = rows.curRow - 1; rows.curRow >= n {
+				rows.seekRow = n - 1
+				break
+			}
+		}
+		if !rows.Next() {
+			break
+		}
+		if rows.token != nil && rows.curRow == rows.seekRow {
+			if rows.err = rows.decoder.Skip(); rows.err != nil {
+				return rows.err
+			}
+			rows.token = nil
+		}
+	}
+	return rows.err
+}
+
 // GetRowOpts will return the RowOpts of the current row.
 func (rows *Rows) GetRowOpts() RowOpts {
 	return rows.curRowOpts
@@ -132,14 +641,239 @@ func (rows *Rows) Close() error {
 // data as a stream, returns each cell in a row as is, and will not skip empty
 // rows in the tail of the worksheet.
 func (rows *Rows) Columns(opts ...Options) ([]string, error) {
+	var rowIterator rowXMLIterator
+	rows.rawCellValue = getOptions(opts...).RawCellValue
+	err := rows.parseRow(&rowIterator)
+	return rowIterator.cells, err
+}
+
+// RowCell directly maps the typed cell returned by the Rows.Cells function.
+// The Value is the value of the cell converted by the cell type, the RawValue
+// is the original value stored in the worksheet, and the SharedFormula will be
+// specified if the cell belongs to a shared formula group.
+type RowCell struct {
+	StyleID       int
+	Formula       string
+	SharedFormula *SharedFormula
+	Value         interface{}
+	RawValue      string
+	Type          CellType
+}
+
+// Cell returns the cell with the style, formula and value of the typed cell,
+// which can be used directly in StreamWriter.SetRow.
+func (c RowCell) Cell() Cell {
+	return Cell{StyleID: c.StyleID, Formula: c.Formula, SharedFormula: c.SharedFormula, Value: c.Value}
+}
+
+// Cells return the current row's cells with the type information. This
+// fetches the worksheet data as a stream like Columns, but returns each cell
+// with the style index, formula, the raw value without applying the number
+// format, and the value converted to the Go type by the cell type:
+//
+//	 Cell Type            | Value Type
+//	----------------------+------------
+//	 CellTypeBool         | bool
+//	 CellTypeDate         | time.Time
+//	 CellTypeNumber       | float64
+//	 CellTypeError        | string
+//	 CellTypeFormula      | string
+//	 CellTypeInlineString | string
+//	 CellTypeSharedString | string
+//	 CellTypeUnset        | nil
+//
+// The number cell with the date or time number format will be returned as
+// the CellTypeDate type. The SharedFormula of the cell will be specified if
+// it belongs to a shared formula group, the cells can be written by the
+// stream writer with the shared formula preserved by the Cell function of the
+// typed cell. For example, get the
+// typed cells on a worksheet named 'Sheet1':
+//
+//	rows, err := f.Rows("Sheet1")
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	for rows.Next() {
+//	    cells, err := rows.Cells()
+//	    if err != nil {
+//	        fmt.Println(err)
+//	    }
+//	    for _, cell := range cells {
+//	        fmt.Println(cell.Type, cell.Value, cell.StyleID, cell.Formula)
+//	    }
+//	}
+//	if err = rows.Close(); err != nil {
+//	    fmt.Println(err)
+//	}
+func (rows *Rows) Cells() ([]RowCell, error) {
+	rowIterator := rowXMLIterator{typed: true}
+	rows.rawCellValue = true
+	err := rows.parseRow(&rowIterator)
+	return rowIterator.typedCells, err
+}
+
+// Scan copies the cells of the current row into the struct pointed at by
+// dest. The cells are mapped to the exported fields of the struct by the
+// column header names, which could be specified by the "xlsx" struct tag,
+// the field name will be used if the tag is not specified, and the field
+// will be ignored if the tag is "-". When the Scan is called for the first
+// time, the current row will be treated as the header row if any cell of the
+// row matches the header name of the fields, and the next row will be
+// scanned. Otherwise, the fields will be mapped to the columns in order,
+// start with the column A. The cell values will be converted to the type of
+// the fields, including string, bool, numbers, time.Time and pointers to
+// them. The empty cells will set the fields to their zero value. For
+// example, scan the rows on a worksheet named 'Sheet1' with header row
+// "Name", "Age" and "Birthday":
+//
+//	type Person struct {
+//	    Name     string    `xlsx:"Name"`
+//	    Age      int       `xlsx:"Age"`
+//	    Birthday time.Time `xlsx:"Birthday"`
+//	}
+//	rows, err := f.Rows("Sheet1")
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	for rows.Next() {
+//	    var p Person
+//	    if err := rows.Scan(&p); err != nil {
+//	        fmt.Println(err)
+//	        break
+//	    }
+//	    fmt.Println(p.Name, p.Age, p.Birthday)
+//	}
+//	if err = rows.Close(); err != nil {
+//	    fmt.Println(err)
+//	}
+func (rows *Rows) Scan(dest interface{}) error {
+	v := reflect.ValueOf(dest)
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return ErrParameterInvalid
+	}
+	v = v.Elem()
+	fields := getStructFields(v.Type())
+	cells, err := rows.Cells()
+	if err != nil {
+		return err
+	}
+	if rows.structHeader == nil {
+		rows.structHeader = make(map[string]int)
+		for col, cell := range cells {
+			for _, field := range fields {
+				if name, ok := cell.Value.(string); ok && name == field.name {
+					rows.structHeader[field.name] = col
+				}
+			}
+		}
+		if len(rows.structHeader) > 0 {
+			if !rows.Next() {
+				return ErrScanNoDataRow
+			}
+			if cells, err = rows.Cells(); err != nil {
+				return err
+			}
+		} else {
+			for col, field := range fields {
+				rows.structHeader[field.name] = col
+			}
+		}
+	}
+	for _, field := range fields {
+		col, ok := rows.structHeader[field.name]
+		if !ok {
+			continue
+		}
+		fieldValue := v.Field(field.index)
+		if col >= len(cells) || (cells[col].RawValue == "" && cells[col].Formula == "") {
+			fieldValue.Set(reflect.Zero(fieldValue.Type()))
+			continue
+		}
+		if err = rows.scanCell(fieldValue, cells[col]); err != nil {
+			cell, _ := CoordinatesToCellName(col+1, rows.curRow)
+			return newScanFieldError(cells[col].RawValue, cell, field.name)
+		}
+	}
+	return err
+}
+
+// scanCell provides a function to convert the typed cell value to the type of
+// the given struct field.
+func (rows *Rows) scanCell(field reflect.Value, cell RowCell) error {
+	if field.Kind() == reflect.Ptr {
+		value := reflect.New(field.Type().Elem())
+		if err := rows.scanCell(value.Elem(), cell); err != nil {
+			return err
+		}
+		field.Set(value)
+		return nil
+	}
+	if field.Type() == reflect.TypeOf(time.Time{}) {
+		switch value := cell.Value.(type) {
+		case time.Time:
+			field.Set(reflect.ValueOf(value))
+		case float64:
+			field.Set(reflect.ValueOf(timeFromExcelTime(value, rows.date1904)))
+		default:
+			t, err := time.Parse(time.RFC3339, cell.RawValue)
+			if err != nil {
+				return err
+			}
+			field.Set(reflect.ValueOf(t))
+		}
+		return nil
+	}
+	switch field.Kind() {
+	case reflect.String:
+		c := xlsxC{S: cell.StyleID, V: cell.RawValue}
+		switch cell.Type {
+		case CellTypeBool:
+			c.T = "b"
+		case CellTypeNumber, CellTypeDate:
+		default:
+			field.SetString(cell.RawValue)
+			return nil
+		}
+		value, err := c.getValueFrom(rows.f, rows.sst, false)
+		field.SetString(value)
+		return err
+	case reflect.Bool:
+		value, err := strconv.ParseBool(cell.RawValue)
+		field.SetBool(value)
+		return err
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		value, err := strconv.ParseFloat(cell.RawValue, 64)
+		if err == nil && field.OverflowInt(int64(value)) {
+			err = ErrParameterInvalid
+		}
+		field.SetInt(int64(value))
+		return err
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		value, err := strconv.ParseFloat(cell.RawValue, 64)
+		if err == nil && (value < 0 || field.OverflowUint(uint64(value))) {
+			err = ErrParameterInvalid
+		}
+		field.SetUint(uint64(value))
+		return err
+	case reflect.Float32, reflect.Float64:
+		value, err := strconv.ParseFloat(cell.RawValue, 64)
+		field.SetFloat(value)
+		return err
+	}
+	return ErrParameterInvalid
+}
+
+// parseRow provides a function to parse the cells of the current row by
+// given row XML iterator.
+func (rows *Rows) parseRow(rowIterator *rowXMLIterator) error {
 	if rows.curRow > rows.seekRow {
-		return nil, nil
+		return nil
 	}
-	var rowIterator rowXMLIterator
 	var token xml.Token
-	rows.rawCellValue = getOptions(opts...).RawCellValue
 	if rows.sst, rowIterator.err = rows.f.sharedStringsReader(); rowIterator.err != nil {
-		return rowIterator.cells, rowIterator.err
+		return rowIterator.err
 	}
 	for {
 		if rows.token != nil {
@@ -151,31 +885,42 @@ func (rows *Rows) Columns(opts ...Options) ([]string, error) {
 		case xml.StartElement:
 			rowIterator.inElement = xmlElement.Name.Local
 			if rowIterator.inElement == "row" {
-				rowNum := 0
+				rowNum, prevRow := 0, rows.curRow
 				if rowNum, rowIterator.err = attrValToInt("r", xmlElement.Attr); rowNum != 0 {
 					rows.curRow = rowNum
 				} else if rows.token == nil {
 					rows.curRow++
 				}
+				if rows.token == nil && rowNum != 0 && rowNum <= prevRow {
+					// Stop at the end of the current row, the following Next
+					// call will report the out of order row, or continue
+					// from the sorted copy if not in strict mode
+					if rows.unordered = true; rows.f.options.StrictSheetData {
+						rows.err = newUnorderedRowError(rowNum)
+					}
+					return rowIterator.err
+				}
 				rows.token = token
 				rows.seekRowOpts = extractRowOpts(xmlElement.Attr)
 				if rows.curRow > rows.seekRow {
 					rows.token = nil
-					return rowIterator.cells, rowIterator.err
+					return rowIterator.err
 				}
 			}
-			if rows.rowXMLHandler(&rowIterator, &xmlElement, rows.rawCellValue); rowIterator.err != nil {
-				rows.token = nil
-				return rowIterator.cells, rowIterator.err
+			if rows.rowXMLHandler(rowIterator, &xmlElement, rows.rawCellValue); rowIterator.err != nil {
+				if rows.token = nil; rows.f.options.StrictSheetData {
+					rows.err = rowIterator.err
+				}
+				return rowIterator.err
 			}
 			rows.token = nil
 		case xml.EndElement:
 			if xmlElement.Name.Local == "sheetData" {
-				return rowIterator.cells, rowIterator.err
+				return rowIterator.err
 			}
 		}
 	}
-	return rowIterator.cells, rowIterator.err
+	return rowIterator.err
 }
 
 // extractRowOpts extract row element attributes.
@@ -190,6 +935,11 @@ func extractRowOpts(attrs []xml.Attr) RowOpts {
 	if height, err := attrValToFloat("ht", attrs); err == nil {
 		rowOpts.Height = height
 	}
+	for _, attr := range attrs {
+		if attr.Name.Local == "spans" {
+			rowOpts.Spans = attr.Value
+		}
+	}
 	return rowOpts
 }
 
@@ -207,24 +957,148 @@ type rowXMLIterator struct {
 	inElement        string
 	cellCol, cellRow int
 	cells            []string
+	typed            bool
+	typedCells       []RowCell
 }
 
-// rowXMLHandler parse the row XML element of the worksheet.
+// rowXMLHandler parse the row XML element of the worksheet. The value of the
+// duplicated cell will be overwritten by the latter non-empty one, and the
+// cells beyond the maximum column will be skipped if not in strict mode.
 func (rows *Rows) rowXMLHandler(rowIterator *rowXMLIterator, xmlElement *xml.StartElement, raw bool) {
 	if rowIterator.inElement == "c" {
+		prevCol, strict := rowIterator.cellCol, rows.f.options.StrictSheetData
 		rowIterator.cellCol++
 		colCell := xlsxC{}
 		_ = rows.decoder.DecodeElement(&colCell, xmlElement)
 		if colCell.R != "" {
 			if rowIterator.cellCol, _, rowIterator.err = CellNameToCoordinates(colCell.R); rowIterator.err != nil {
+				if rowIterator.err == ErrColumnNumber && !strict {
+					rowIterator.cellCol, rowIterator.err = prevCol, nil
+				}
 				return
 			}
+			if rowIterator.cellCol <= prevCol && strict {
+				rowIterator.err = newUnorderedCellError(colCell.R)
+				return
+			}
+		}
+		if rowIterator.cellCol > MaxColumns {
+			if rowIterator.cellCol = prevCol; strict {
+				rowIterator.err = ErrColumnNumber
+			}
+			return
+		}
+		if rowIterator.typed {
+			if cell := rows.getTypedCell(&colCell); cell.RawValue != "" || cell.Formula != "" {
+				if blank := rowIterator.cellCol - len(rowIterator.typedCells); blank > 0 {
+					rowIterator.typedCells = append(rowIterator.typedCells, make([]RowCell, blank)...)
+					if !raw {
+						rowIterator.cells = append(rowIterator.cells, make([]string, blank)...)
+					}
+				}
+				rowIterator.typedCells[rowIterator.cellCol-1] = cell
+				if !raw {
+					rowIterator.cells[rowIterator.cellCol-1], _ = colCell.getValueFrom(rows.f, rows.sst, false)
+				}
+			}
+		} else if val, _ := colCell.getValueFrom(rows.f, rows.sst, raw); val != "" || colCell.F != nil {
+			if rowIterator.cellCol <= len(rowIterator.cells) {
+				rowIterator.cells[rowIterator.cellCol-1] = val
+			} else {
+				blank := rowIterator.cellCol - len(rowIterator.cells)
+				rowIterator.cells = append(appendSpace(blank, rowIterator.cells), val)
+			}
+		}
+		if rowIterator.cellCol < prevCol {
+			rowIterator.cellCol = prevCol
+		}
+	}
+}
+
+// getTypedCell provides a function to get the cell with the type information
+// by given cell XML element.
+func (rows *Rows) getTypedCell(c *xlsxC) RowCell {
+	raw, _ := c.getValueFrom(rows.f, rows.sst, true)
+	cell := RowCell{StyleID: c.S, Value: raw, RawValue: raw, Type: cellTypes[c.T]}
+	if c.F != nil {
+		if cell.Formula = c.F.Content; c.F.T == STCellFormulaTypeShared && c.F.Si != nil {
+			cell.SharedFormula = &SharedFormula{Index: *c.F.Si, Ref: c.F.Ref}
+			if master, ok := rows.sharedFormulas[*c.F.Si]; ok && c.F.Ref == "" {
+				cell.Formula = master.getSharedFormula(c.R)
+			} else {
+				rows.sharedFormulas[*c.F.Si] = *c
+			}
 		}
-		blank := rowIterator.cellCol - len(rowIterator.cells)
-		if val, _ := colCell.getValueFrom(rows.f, rows.sst, raw); val != "" || colCell.F != nil {
-			rowIterator.cells = append(appendSpace(blank, rowIterator.cells), val)
+	}
+	switch c.T {
+	case "b":
+		cell.Value = raw == "1"
+	case "d":
+		if timestamp, err := time.Parse(time.RFC3339Nano, raw); err == nil {
+			cell.Value = timestamp
+		}
+	case "", "n":
+		if raw == "" {
+			cell.Type, cell.Value = CellTypeUnset, nil
+			break
+		}
+		number, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			break
+		}
+		cell.Type, cell.Value = CellTypeNumber, number
+		isDate, ok := rows.dateStyles[c.S]
+		if !ok {
+			isDate = rows.f.isDateTimeStyle(c.S)
+			rows.dateStyles[c.S] = isDate
+		}
+		if isDate {
+			cell.Type, cell.Value = CellTypeDate, timeFromExcelTime(number, rows.date1904)
+		}
+	}
+	return cell
+}
+
+// repairedRows provides a function to close the rows iterator and create a
+// new one from an in-memory copy of the worksheet, which out of order or
+// duplicated rows and cells have been sorted and merged. The workbook will
+// not be changed.
+func (rows *Rows) repairedRows() (*Rows, error) {
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	content := rows.f.readXML(rows.sheet)
+	if len(content) == 0 {
+		tempFile, err := rows.f.readTemp(rows.sheet)
+		if err != nil {
+			return nil, err
 		}
+		if tempFile != nil {
+			content, err = io.ReadAll(tempFile)
+			if _ = tempFile.Close(); err != nil {
+				return nil, err
+			}
+		}
+	}
+	ws := new(xlsxWorksheet)
+	if err := rows.f.xmlNewDecoder(bytes.NewReader(namespaceStrictToTransitional(content))).
+		Decode(ws); err != nil && err != io.EOF {
+		return nil, err
 	}
+	ws.checkSheet()
+	if _, err := ws.checkRow(); err != nil {
+		return nil, err
+	}
+	rows.f.logEvent(LogEvent{
+		Type: LogEventRepairPerformed, Part: rows.sheet,
+		Message: "read the rows from the in-memory sorted copy of the worksheet",
+	})
+	output, _ := xml.Marshal(ws)
+	return &Rows{
+		f: rows.f, sheet: rows.sheet, sheetName: rows.sheetName, rawCellValue: rows.rawCellValue,
+		date1904: rows.date1904, dateStyles: map[int]bool{}, sharedFormulas: map[int]xlsxC{},
+		decoder: rows.f.xmlNewDecoder(bytes.NewReader(output)),
+	}, nil
 }
 
 // Rows returns a rows iterator, used for streaming reading data for a
@@ -266,7 +1140,10 @@ func (f *File) Rows(sheet string) (*Rows, error) {
 		f.saveFileList(name, f.replaceNameSpaceBytes(name, output))
 	}
 	var err error
-	rows := Rows{f: f, sheet: name}
+	rows := Rows{f: f, sheet: name, sheetName: sheet, dateStyles: map[int]bool{}, sharedFormulas: map[int]xlsxC{}}
+	if wb, err := f.workbookReader(); err == nil && wb != nil && wb.WorkbookPr != nil {
+		rows.date1904 = wb.WorkbookPr.Date1904
+	}
 	rows.needClose, rows.decoder, rows.tempFile, err = f.xmlDecoder(name)
 	return &rows, err
 }
@@ -278,11 +1155,19 @@ func (f *File) getFromStringItem(index int) string {
 		if len(f.sharedStringItem) <= index {
 			return strconv.Itoa(index)
 		}
+		if f.sharedStringCache != nil {
+			if value, ok := f.sharedStringCache.get(index); ok {
+				return value
+			}
+		}
 		offsetRange := f.sharedStringItem[index]
 		buf := make([]byte, offsetRange[1]-offsetRange[0])
 		if _, err := f.sharedStringTemp.ReadAt(buf, int64(offsetRange[0])); err != nil {
 			return strconv.Itoa(index)
 		}
+		if f.sharedStringCache != nil {
+			f.sharedStringCache.put(index, string(buf))
+		}
 		return string(buf)
 	}
 	needClose, decoder, tempFile, err := f.xmlDecoder(defaultXMLPathSharedStrings)
@@ -294,6 +1179,7 @@ func (f *File) getFromStringItem(index int) string {
 	f.sharedStringItem = [][]uint{}
 	f.sharedStringTemp, _ = os.CreateTemp(os.TempDir(), "excelize-")
 	f.tempFiles.Store(defaultTempFileSST, f.sharedStringTemp.Name())
+	f.logEvent(LogEvent{Type: LogEventTempFileCreated, Part: defaultXMLPathSharedStrings, Path: f.sharedStringTemp.Name()})
 	var (
 		inElement string
 		i, offset uint
@@ -360,6 +1246,236 @@ func (f *File) SetRowHeight(sheet string, row int, height float64) error {
 	return nil
 }
 
+// AutoFitRows provides a function to adjust the height of rows to fit their
+// content by given worksheet name and the first and last Excel row number,
+// like double-clicking the row border in Office Excel. The height is measured
+// by the font size and wrap text setting of each cell, the column width, and
+// the default font metrics of the workbook. Merged cells will be ignored. For
+// example, fit the height of rows 1 to 10 on Sheet1:
+//
+//	err := f.AutoFitRows("Sheet1", 1, 10)
+func (f *File) AutoFitRows(sheet string, start, end int) error {
+	if end < start {
+		start, end = end, start
+	}
+	if start < 1 {
+		return newInvalidRowNumberError(start)
+	}
+	if end > TotalRows {
+		return ErrMaxRows
+	}
+	ws, err := f.workSheetReader(sheet)
+	if err != nil {
+		return err
+	}
+	s, err := f.stylesReader()
+	if err != nil {
+		return err
+	}
+	sst, err := f.sharedStringsReader()
+	if err != nil {
+		return err
+	}
+	defaultHeight := defaultRowHeight
+	if ws.SheetFormatPr != nil && ws.SheetFormatPr.DefaultRowHeight > 0 {
+		defaultHeight = ws.SheetFormatPr.DefaultRowHeight
+	}
+	mergeCells, err := ws.getMergeCellsCoordinates()
+	if err != nil {
+		return err
+	}
+	normalFontSize, maxDigitWidth := getFontSize(s, 0), f.getMaxDigitWidth()
+	for rowIdx := start - 1; rowIdx < end && rowIdx < len(ws.SheetData.Row); rowIdx++ {
+		height := defaultHeight
+		for i := range ws.SheetData.Row[rowIdx].C {
+			c := &ws.SheetData.Row[rowIdx].C[i]
+			col, row, err := CellNameToCoordinates(c.R)
+			if err != nil {
+				return err
+			}
+			if inMergeCells(mergeCells, col, row) {
+				continue
+			}
+			val, err := c.getValueFrom(f, sst, false)
+			if err != nil {
+				return err
+			}
+			if val == "" {
+				continue
+			}
+			var (
+				fontSize = normalFontSize
+				wrapText bool
+			)
+			if s.CellXfs != nil && c.S > 0 && c.S < len(s.CellXfs.Xf) {
+				xf := s.CellXfs.Xf[c.S]
+				if xf.FontID != nil {
+					fontSize = getFontSize(s, *xf.FontID)
+				}
+				wrapText = xf.Alignment != nil && xf.Alignment.WrapText
+			}
+			lines := 1
+			if wrapText {
+				lines = getWrappedTextLines(val, float64(f.getColWidth(sheet, col)), maxDigitWidth*fontSize/normalFontSize)
+			}
+			if ht := float64(lines) * defaultHeight * fontSize / normalFontSize; ht > height {
+				height = ht
+			}
+		}
+		// Row height in Office Excel is measured in steps of one pixel
+		height = math.Min(math.Ceil(height/0.75)*0.75, MaxRowHeight)
+		ws.SheetData.Row[rowIdx].Ht, ws.SheetData.Row[rowIdx].CustomHeight = nil, false
+		if height != defaultHeight {
+			ws.SheetData.Row[rowIdx].Ht = float64Ptr(height)
+		}
+	}
+	return err
+}
+
+// fitWrappedTextRowHeight provides a function to increase the height of the
+// row to fit the text of the cell with the wrap text style by given
+// worksheet, cell coordinates, text and style index. If the cell is merged,
+// the text will be measured by the width of the merged cell, and the height
+// of the last row of the merged cell will be increased when the total height
+// of the rows is not enough.
+func (f *File) fitWrappedTextRowHeight(ws *xlsxWorksheet, col, row int, text string, styleID int) error {
+	f.mu.Lock()
+	s, err := f.stylesReader()
+	f.mu.Unlock()
+	if err != nil || text == "" || s.CellXfs == nil || styleID <= 0 || styleID >= len(s.CellXfs.Xf) {
+		return err
+	}
+	xf := s.CellXfs.Xf[styleID]
+	if xf.Alignment == nil || !xf.Alignment.WrapText {
+		return err
+	}
+	mergeCells, err := ws.getMergeCellsCoordinates()
+	if err != nil {
+		return err
+	}
+	rect := []int{col, row, col, row}
+	for _, mergeCell := range mergeCells {
+		if cellInRange([]int{col, row}, mergeCell) {
+			rect = mergeCell
+			break
+		}
+	}
+	normalFontSize, maxDigitWidth := getFontSize(s, 0), f.getMaxDigitWidth()
+	fontSize := normalFontSize
+	if xf.FontID != nil {
+		fontSize = getFontSize(s, *xf.FontID)
+	}
+	var width int
+	for c := rect[0]; c <= rect[2]; c++ {
+		width += ws.getColWidth(c, maxDigitWidth)
+	}
+	defaultHeight := defaultRowHeight
+	if ws.SheetFormatPr != nil && ws.SheetFormatPr.DefaultRowHeight > 0 {
+		defaultHeight = ws.SheetFormatPr.DefaultRowHeight
+	}
+	lines := getWrappedTextLines(text, float64(width), maxDigitWidth*fontSize/normalFontSize)
+	height := float64(lines) * defaultHeight * fontSize / normalFontSize
+	ws.prepareSheetXML(0, rect[3])
+	for r := rect[1]; r <= rect[3]; r++ {
+		ht := defaultHeight
+		if ws.SheetData.Row[r-1].Ht != nil {
+			ht = *ws.SheetData.Row[r-1].Ht
+		}
+		if r < rect[3] {
+			height -= ht
+			continue
+		}
+		// Row height in Office Excel is measured in steps of one pixel
+		if height = math.Min(math.Ceil(height/0.75)*0.75, MaxRowHeight); height > ht {
+			ws.SheetData.Row[r-1].Ht = float64Ptr(height)
+			ws.SheetData.Row[r-1].CustomHeight = true
+		}
+	}
+	return err
+}
+
+// getMergeCellsCoordinates returns the sorted coordinates of all merged cells
+// in the worksheet.
+func (ws *xlsxWorksheet) getMergeCellsCoordinates() ([][]int, error) {
+	var rects [][]int
+	if ws.MergeCells == nil {
+		return rects, nil
+	}
+	for _, mergeCell := range ws.MergeCells.Cells {
+		rect, err := rangeRefToCoordinates(mergeCell.Ref)
+		if err != nil {
+			return rects, err
+		}
+		_ = sortCoordinates(rect)
+		rects = append(rects, rect)
+	}
+	return rects, nil
+}
+
+// inMergeCells returns whether the given cell coordinates is a part of any
+// merged cells.
+func inMergeCells(rects [][]int, col, row int) bool {
+	for _, rect := range rects {
+		if cellInRange([]int{col, row}, rect) {
+			return true
+		}
+	}
+	return false
+}
+
+// getFontSize returns the size of the font by given font ID in the style
+// sheet, the default font size will be returned if the font doesn't exist.
+func getFontSize(s *xlsxStyleSheet, fontID int) float64 {
+	if s.Fonts != nil && fontID >= 0 && fontID < len(s.Fonts.Font) {
+		if fnt := s.Fonts.Font[fontID]; fnt.Sz != nil && fnt.Sz.Val != nil && *fnt.Sz.Val > 0 {
+			return *fnt.Sz.Val
+		}
+	}
+	return defaultFontSize
+}
+
+// getWrappedTextLines returns the number of lines of the wrapped text by
+// given text, the width of the cell and the width of a character in pixels.
+// Text is wrapped on spaces where possible, and the East Asian wide
+// characters will be measured as double width.
+func getWrappedTextLines(text string, width, digitWidth float64) int {
+	var lines int
+	charWidth := func(r rune) float64 {
+		if unicode.In(r, unicode.Han, unicode.Hangul, unicode.Hiragana, unicode.Katakana) ||
+			(r >= 0xFF00 && r <= 0xFFEF) {
+			return 2 * digitWidth
+		}
+		return digitWidth
+	}
+	for _, paragraph := range strings.Split(text, "\n") {
+		var lineWidth float64
+		lines++
+		for i, word := range strings.Split(paragraph, " ") {
+			var wordWidth float64
+			for _, r := range word {
+				wordWidth += charWidth(r)
+			}
+			if i > 0 {
+				if lineWidth > 0 && lineWidth+charWidth(' ')+wordWidth > width {
+					lines++
+					lineWidth = 0
+				} else {
+					lineWidth += charWidth(' ')
+				}
+			}
+			for _, r := range word {
+				if w := charWidth(r); lineWidth > 0 && lineWidth+w > width {
+					lines++
+					lineWidth = w
+				} else {
+					lineWidth += w
+				}
+			}
+		}
+	}
+	return lines
+}
+
 // getRowHeight provides a function to get row height in pixels by given sheet
 // name and row number.
 func (f *File) getRowHeight(sheet string, row int) int {
@@ -416,11 +1532,12 @@ func (f *File) sharedStringsReader() (*xlsxSST, error) {
 	relPath := f.getWorkbookRelsPath()
 	if f.SharedStrings == nil {
 		var sharedStrings xlsxSST
-		ss := f.readXML(defaultXMLPathSharedStrings)
+		start, ss := time.Now(), f.readXML(defaultXMLPathSharedStrings)
 		if err = f.xmlNewDecoder(bytes.NewReader(namespaceStrictToTransitional(ss))).
 			Decode(&sharedStrings); err != nil && err != io.EOF {
 			return f.SharedStrings, err
 		}
+		f.logPartParsed(defaultXMLPathSharedStrings, len(ss), start)
 		if sharedStrings.Count == 0 {
 			sharedStrings.Count = len(sharedStrings.SI)
 		}
@@ -506,28 +1623,211 @@ func (f *File) SetRowOutlineLevel(sheet string, row int, level uint8) error {
 	if err != nil {
 		return err
 	}
-	ws.prepareSheetXML(0, row)
-	ws.SheetData.Row[row-1].OutlineLevel = level
-	return nil
+	ws.prepareSheetXML(0, row)
+	ws.SheetData.Row[row-1].OutlineLevel = level
+	ws.setOutlineLevelRow()
+	return nil
+}
+
+// GetRowOutlineLevel provides a function to get outline level number of a
+// single row by given worksheet name and Excel row number. For example, get
+// outline number of row 2 in Sheet1:
+//
+//	level, err := f.GetRowOutlineLevel("Sheet1", 2)
+func (f *File) GetRowOutlineLevel(sheet string, row int) (uint8, error) {
+	if row < 1 {
+		return 0, newInvalidRowNumberError(row)
+	}
+	ws, err := f.workSheetReader(sheet)
+	if err != nil {
+		return 0, err
+	}
+	if row > len(ws.SheetData.Row) {
+		return 0, nil
+	}
+	return ws.SheetData.Row[row-1].OutlineLevel, nil
+}
+
+// GroupRows provides a function to group the rows by given worksheet name, the
+// first and last Excel row number of the group, and whether to collapse the
+// group. The outline level of each row in the group will be increased by one,
+// so the grouped rows could be nested up to 7 levels. The summary row below
+// or above the group (depending on the summary rows setting of the worksheet)
+// will be marked as collapsed, and the rows in the group will be hidden if
+// the collapsed is true. For example, group rows 2 to 5 in Sheet1 and
+// collapse the group:
+//
+//	err := f.GroupRows("Sheet1", 2, 5, true)
+func (f *File) GroupRows(sheet string, start, end int, collapsed bool) error {
+	ws, err := f.prepareGroupRows(sheet, start, end)
+	if err != nil {
+		return err
+	}
+	summary, last := ws.getSummaryRow(start, end), end
+	if summary > last {
+		last = summary
+	}
+	ws.prepareSheetXML(0, last)
+	for row := start; row <= end; row++ {
+		if ws.SheetData.Row[row-1].OutlineLevel >= 7 {
+			return ErrOutlineLevel
+		}
+	}
+	for row := start; row <= end; row++ {
+		ws.SheetData.Row[row-1].OutlineLevel++
+		if collapsed {
+			ws.SheetData.Row[row-1].Hidden = true
+		}
+	}
+	if summary > 0 {
+		ws.SheetData.Row[summary-1].Collapsed = collapsed
+	}
+	ws.setOutlineLevelRow()
+	return err
+}
+
+// UngroupRows provides a function to ungroup the rows by given worksheet name,
+// the first and last Excel row number of the group. The outline level of each
+// grouped row in the range will be decreased by one, and the rows which are
+// no longer in any group will be shown. For example, ungroup rows 2 to 5 in
+// Sheet1:
+//
+//	err := f.UngroupRows("Sheet1", 2, 5)
+func (f *File) UngroupRows(sheet string, start, end int) error {
+	ws, err := f.prepareGroupRows(sheet, start, end)
+	if err != nil {
+		return err
+	}
+	for row := start; row <= end && row <= len(ws.SheetData.Row); row++ {
+		if r := &ws.SheetData.Row[row-1]; r.OutlineLevel > 0 {
+			if r.OutlineLevel--; r.OutlineLevel == 0 {
+				r.Hidden = false
+			}
+		}
+	}
+	if summary := ws.getSummaryRow(start, end); summary > 0 && summary <= len(ws.SheetData.Row) {
+		adjacent := end
+		if summary < start {
+			adjacent = start
+		}
+		if adjacent > len(ws.SheetData.Row) ||
+			ws.SheetData.Row[adjacent-1].OutlineLevel <= ws.SheetData.Row[summary-1].OutlineLevel {
+			ws.SheetData.Row[summary-1].Collapsed = false
+		}
+	}
+	ws.setOutlineLevelRow()
+	return err
+}
+
+// prepareGroupRows provides a function to check the range of the rows for
+// grouping or ungrouping, and returns the worksheet.
+func (f *File) prepareGroupRows(sheet string, start, end int) (*xlsxWorksheet, error) {
+	if start < 1 {
+		return nil, newInvalidRowNumberError(start)
+	}
+	if end < start {
+		return nil, ErrParameterInvalid
+	}
+	if end > TotalRows {
+		return nil, ErrMaxRows
+	}
+	return f.workSheetReader(sheet)
+}
+
+// getSummaryRow provides a function to get the Excel row number of the
+// summary row of the group by given first and last Excel row number of the
+// group, returns 0 if the summary row doesn't exist.
+func (ws *xlsxWorksheet) getSummaryRow(start, end int) int {
+	if ws.SheetPr != nil && ws.SheetPr.OutlinePr != nil &&
+		ws.SheetPr.OutlinePr.SummaryBelow != nil && !*ws.SheetPr.OutlinePr.SummaryBelow {
+		return start - 1
+	}
+	if end < TotalRows {
+		return end + 1
+	}
+	return 0
+}
+
+// setOutlineLevelRow provides a function to update the maximum outline level
+// of the rows in the sheet format properties of the worksheet.
+func (ws *xlsxWorksheet) setOutlineLevelRow() {
+	var level uint8
+	for _, row := range ws.SheetData.Row {
+		if row.OutlineLevel > level {
+			level = row.OutlineLevel
+		}
+	}
+	if ws.SheetFormatPr == nil {
+		if level == 0 {
+			return
+		}
+		ws.SheetFormatPr = &xlsxSheetFormatPr{DefaultRowHeight: defaultRowHeight}
+	}
+	ws.SheetFormatPr.OutlineLevelRow = level
 }
 
-// GetRowOutlineLevel provides a function to get outline level number of a
-// single row by given worksheet name and Excel row number. For example, get
-// outline number of row 2 in Sheet1:
+// CollapseOutlineLevel provides a function to collapse the row and column
+// groups by given worksheet name and outline level, just like click the
+// outline level button in Excel. The rows and columns with the outline level
+// greater than or equal to the given level will be hidden, the others in the
+// groups will be shown, and the summary rows and columns (depending on the
+// summary rows and columns settings of the worksheet) will be marked as
+// collapsed or expanded accordingly. The value of parameter 'level' is 0-7,
+// and 0 means expand all groups. For example, only show the rows and columns
+// with outline level 0 and 1 in Sheet1:
 //
-//	level, err := f.GetRowOutlineLevel("Sheet1", 2)
-func (f *File) GetRowOutlineLevel(sheet string, row int) (uint8, error) {
-	if row < 1 {
-		return 0, newInvalidRowNumberError(row)
+//	err := f.CollapseOutlineLevel("Sheet1", 2)
+//
+// The summary rows position and summary columns position could be set by the
+// OutlineSummaryBelow and OutlineSummaryRight field of the SetSheetProps
+// function.
+func (f *File) CollapseOutlineLevel(sheet string, level uint8) error {
+	if level > 7 {
+		return ErrOutlineLevel
 	}
 	ws, err := f.workSheetReader(sheet)
 	if err != nil {
-		return 0, err
+		return err
 	}
-	if row > len(ws.SheetData.Row) {
-		return 0, nil
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+	ws.collapseOutlineRows(level)
+	ws.collapseOutlineCols(level)
+	return err
+}
+
+// isOutlineCollapsed returns whether the group adjacent to the summary row or
+// column with the given outline level should be collapsed by given collapse
+// outline level.
+func isOutlineCollapsed(summaryLevel, level uint8) bool {
+	return level > 0 && summaryLevel+1 >= level
+}
+
+// collapseOutlineRows provides a function to set the hidden and collapsed
+// flags of the grouped rows by given collapse outline level.
+func (ws *xlsxWorksheet) collapseOutlineRows(level uint8) {
+	summaryBelow := ws.SheetPr == nil || ws.SheetPr.OutlinePr == nil ||
+		ws.SheetPr.OutlinePr.SummaryBelow == nil || *ws.SheetPr.OutlinePr.SummaryBelow
+	if rows := len(ws.SheetData.Row); summaryBelow && rows > 0 && rows < TotalRows &&
+		ws.SheetData.Row[rows-1].OutlineLevel > 0 && isOutlineCollapsed(0, level) {
+		// Create the summary row of the last group
+		ws.prepareSheetXML(0, rows+1)
+	}
+	for idx := range ws.SheetData.Row {
+		row := &ws.SheetData.Row[idx]
+		if row.OutlineLevel > 0 {
+			row.Hidden = level > 0 && row.OutlineLevel >= level
+		}
+		detail := idx + 1
+		if summaryBelow {
+			detail = idx - 1
+		}
+		row.Collapsed = false
+		if detail >= 0 && detail < len(ws.SheetData.Row) &&
+			ws.SheetData.Row[detail].OutlineLevel > row.OutlineLevel {
+			row.Collapsed = isOutlineCollapsed(row.OutlineLevel, level)
+		}
 	}
-	return ws.SheetData.Row[row-1].OutlineLevel, nil
 }
 
 // RemoveRow provides a function to remove single row by given worksheet name
@@ -540,27 +1840,214 @@ func (f *File) GetRowOutlineLevel(sheet string, row int) (uint8, error) {
 // worksheet, it will cause a file error when you open it. The excelize only
 // partially updates these references currently.
 func (f *File) RemoveRow(sheet string, row int) error {
+	return f.RemoveRows(sheet, row, 1)
+}
+
+// RemoveRows provides a function to remove the contiguous block of rows by
+// given worksheet name, the first Excel row number and the number of rows.
+// The references such as formulas, merged cells and defined names will be
+// adjusted in one pass, which is equivalent to but much faster than calling
+// the RemoveRow function repeatedly. For example, remove the rows 3 to 7 in
+// Sheet1:
+//
+//	err := f.RemoveRows("Sheet1", 3, 5)
+//
+// Use this method with caution, which will affect changes in references such
+// as formulas, charts, and so on. If there is any referenced value of the
+// worksheet, it will cause a file error when you open it. The excelize only
+// partially updates these references currently.
+func (f *File) RemoveRows(sheet string, row, n int) error {
+	defer f.dispatchWatchEvents()
 	if row < 1 {
 		return newInvalidRowNumberError(row)
 	}
-
+	if row > TotalRows || n > TotalRows {
+		return ErrMaxRows
+	}
+	if n < 1 {
+		return ErrParameterInvalid
+	}
 	ws, err := f.workSheetReader(sheet)
 	if err != nil {
 		return err
 	}
-	if row > len(ws.SheetData.Row) {
-		return f.adjustHelper(sheet, rows, row, -1)
-	}
 	keep := 0
 	for rowIdx := 0; rowIdx < len(ws.SheetData.Row); rowIdx++ {
 		v := &ws.SheetData.Row[rowIdx]
-		if v.R != row {
+		if v.R < row || v.R >= row+n {
 			ws.SheetData.Row[keep] = *v
 			keep++
 		}
 	}
 	ws.SheetData.Row = ws.SheetData.Row[:keep]
-	return f.adjustHelper(sheet, rows, row, -1)
+	return f.adjustHelper(sheet, rows, row, -n)
+}
+
+// MoveRows provides a function to move the contiguous block of rows by given
+// worksheet name, the first and last Excel row number of the block, and the
+// destination row number of the first row in the block after moving. The row
+// heights, styles, cell values, hyperlinks, comments and merged cells will be
+// moved with the rows, and the rows between the block and the destination
+// will be shifted to fill the vacated position. The references to the moved
+// rows in the formulas and defined names will follow the rows, just like cut
+// and insert the rows in Excel. For example, move the rows 2 to 4 to rows 8
+// to 10 in Sheet1, the original rows 5 to 10 will be shifted to rows 2 to 7:
+//
+//	err := f.MoveRows("Sheet1", 2, 4, 8)
+//
+// The merged cells can't be partially moved, and the data validations,
+// conditional formats, tables and drawing objects will not be moved. Use this
+// method with caution, which will affect changes in references such as
+// formulas, charts, and so on.
+func (f *File) MoveRows(sheet string, srcStart, srcEnd, dest int) error {
+	defer f.dispatchWatchEvents()
+	for _, row := range []int{srcStart, dest} {
+		if row < 1 {
+			return newInvalidRowNumberError(row)
+		}
+	}
+	if srcEnd < srcStart {
+		return ErrParameterInvalid
+	}
+	if srcEnd > TotalRows || dest+srcEnd-srcStart > TotalRows {
+		return ErrMaxRows
+	}
+	ws, err := f.workSheetReader(sheet)
+	if err != nil || dest == srcStart {
+		return err
+	}
+	n := srcEnd - srcStart + 1
+	moveRow := func(row int) int {
+		if row >= srcStart && row <= srcEnd {
+			return row + dest - srcStart
+		}
+		if dest > srcStart && row > srcEnd && row < dest+n {
+			return row - n
+		}
+		if dest < srcStart && row >= dest && row < srcStart {
+			return row + n
+		}
+		return row
+	}
+	if ws.MergeCells != nil {
+		for _, mergeCell := range ws.MergeCells.Cells {
+			coordinates, err := rangeRefToCoordinates(mergeCell.Ref)
+			if err != nil {
+				return err
+			}
+			_ = sortCoordinates(coordinates)
+			if moveRow(coordinates[3])-moveRow(coordinates[1]) != coordinates[3]-coordinates[1] {
+				return ErrMoveMergedCells
+			}
+		}
+	}
+	if err = f.rangeWorksheets(func(sheetN string, ws *xlsxWorksheet) error {
+		ws.mu.Lock()
+		defer ws.mu.Unlock()
+		ws.expandSharedFormulas(sheet, sheetN)
+		for r := range ws.SheetData.Row {
+			for c := range ws.SheetData.Row[r].C {
+				if formula := ws.SheetData.Row[r].C[c].F; formula != nil && formula.Content != "" {
+					formula.Content, _ = moveFormulaRows(sheet, sheetN, formula.Content, moveRow)
+					if formula.Ref != "" && sheet == sheetN {
+						formula.Ref, _ = moveFormulaRows(sheet, sheetN, formula.Ref, moveRow)
+					}
+				}
+			}
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+	ws.mu.Lock()
+	for r := range ws.SheetData.Row {
+		row := &ws.SheetData.Row[r]
+		row.R = moveRow(row.R)
+		for c := range row.C {
+			col, _, err := SplitCellName(row.C[c].R)
+			if err != nil {
+				ws.mu.Unlock()
+				return err
+			}
+			row.C[c].R, _ = JoinCellName(col, row.R)
+		}
+	}
+	sort.SliceStable(ws.SheetData.Row, func(i, j int) bool {
+		return ws.SheetData.Row[i].R < ws.SheetData.Row[j].R
+	})
+	if ws.MergeCells != nil {
+		for _, mergeCell := range ws.MergeCells.Cells {
+			mergeCell.Ref, _ = moveFormulaRows(sheet, sheet, mergeCell.Ref, moveRow)
+			mergeCell.rect = nil
+		}
+	}
+	if ws.Hyperlinks != nil {
+		for i := range ws.Hyperlinks.Hyperlink {
+			link := &ws.Hyperlinks.Hyperlink[i]
+			link.Ref, _ = moveFormulaRows(sheet, sheet, link.Ref, moveRow)
+			if link.Location != "" {
+				link.Location, _ = moveFormulaRows(sheet, "", link.Location, moveRow)
+			}
+		}
+	}
+	ws.mu.Unlock()
+	start, end := srcStart, srcEnd
+	if dest < start {
+		start = dest
+	}
+	if dest+n-1 > end {
+		end = dest + n - 1
+	}
+	// Mark the whole worksheet as changed for the partial recalculation
+	f.changedCells.Store(cellRef{Sheet: sheet}, struct{}{})
+	f.addWatchEvents(sheet, []int{MinColumns, start, MaxColumns, end})
+	return f.moveRowsRefs(sheet, moveRow)
+}
+
+// moveRowsRefs provides a function to update the references of the comments,
+// calculation chain and defined names on moving rows by given worksheet name
+// and the function which returns the new row number by the original row
+// number.
+func (f *File) moveRowsRefs(sheet string, fn func(row int) int) error {
+	sheetXMLPath, _ := f.getSheetXMLPath(sheet)
+	commentsXML := f.getSheetComments(filepath.Base(sheetXMLPath))
+	if !strings.HasPrefix(commentsXML, "/") {
+		commentsXML = "xl" + strings.TrimPrefix(commentsXML, "..")
+	}
+	commentsXML = strings.TrimPrefix(commentsXML, "/")
+	cmts, err := f.commentsReader(commentsXML)
+	if err != nil {
+		return err
+	}
+	if cmts != nil {
+		for i := range cmts.CommentList.Comment {
+			cmt := &cmts.CommentList.Comment[i]
+			cmt.Ref, _ = moveFormulaRows(sheet, sheet, cmt.Ref, fn)
+		}
+	}
+	if f.CalcChain != nil {
+		var prevSheetID int
+		sheetID := f.getSheetID(sheet)
+		for i, c := range f.CalcChain.C {
+			if c.I == 0 {
+				c.I = prevSheetID
+			}
+			if prevSheetID = c.I; c.I == sheetID {
+				f.CalcChain.C[i].R, _ = moveFormulaRows(sheet, sheet, c.R, fn)
+			}
+		}
+	}
+	wb, err := f.workbookReader()
+	if err != nil {
+		return err
+	}
+	if wb.DefinedNames != nil {
+		for i := range wb.DefinedNames.DefinedName {
+			definedName := &wb.DefinedNames.DefinedName[i]
+			definedName.Data, _ = moveFormulaRows(sheet, "", definedName.Data, fn)
+		}
+	}
+	return err
 }
 
 // InsertRows provides a function to insert new rows after the given Excel row
@@ -574,6 +2061,7 @@ func (f *File) RemoveRow(sheet string, row int) error {
 // worksheet, it will cause a file error when you open it. The excelize only
 // partially updates these references currently.
 func (f *File) InsertRows(sheet string, row, n int) error {
+	defer f.dispatchWatchEvents()
 	if row < 1 {
 		return newInvalidRowNumberError(row)
 	}
@@ -608,6 +2096,7 @@ func (f *File) DuplicateRow(sheet string, row int) error {
 // worksheet, it will cause a file error when you open it. The excelize only
 // partially updates these references currently.
 func (f *File) DuplicateRowTo(sheet string, row, row2 int) error {
+	defer f.dispatchWatchEvents()
 	if row < 1 {
 		return newInvalidRowNumberError(row)
 	}
@@ -718,64 +2207,78 @@ func (f *File) duplicateMergeCells(sheet string, ws *xlsxWorksheet, row, row2 in
 //	    <c r="G15" s="1" />
 //	</row>
 //
+// The out of order and duplicated cells will be sorted and merged, and the
+// cells beyond the maximum column will be dropped. This function returns true
+// if any out of order, duplicated or dropped cells have been repaired.
+//
 // Notice: this method could be very slow for large spreadsheets (more than
 // 3000 rows one sheet).
-func (ws *xlsxWorksheet) checkRow() error {
+func (ws *xlsxWorksheet) checkRow() (bool, error) {
+	var repaired bool
 	for rowIdx := range ws.SheetData.Row {
 		rowData := &ws.SheetData.Row[rowIdx]
-
-		colCount := len(rowData.C)
-		if colCount == 0 {
+		if len(rowData.C) == 0 {
 			continue
 		}
 		// check and fill the cell without r attribute in a row element
-		rCount := 0
-		for idx, cell := range rowData.C {
+		var rCount, lastCol int
+		ordered, cells := true, rowData.C[:0]
+		for _, cell := range rowData.C {
 			rCount++
 			if cell.R != "" {
-				lastR, _, err := CellNameToCoordinates(cell.R)
+				col, _, err := CellNameToCoordinates(cell.R)
+				if err == ErrColumnNumber {
+					rCount--
+					repaired = true
+					continue
+				}
 				if err != nil {
-					return err
+					return repaired, err
+				}
+				if col < rCount {
+					ordered = false
 				}
-				if lastR > rCount {
-					rCount = lastR
+				if col > rCount {
+					rCount = col
 				}
+			} else if cell.R, _ = CoordinatesToCellName(rCount, rowIdx+1); cell.R == "" {
+				rCount--
+				repaired = true
 				continue
 			}
-			rowData.C[idx].R, _ = CoordinatesToCellName(rCount, rowIdx+1)
+			if rCount > lastCol {
+				lastCol = rCount
+			}
+			cells = append(cells, cell)
 		}
-		lastCol, _, err := CellNameToCoordinates(rowData.C[colCount-1].R)
-		if err != nil {
-			return err
+		rowData.C = cells
+		if ordered && len(rowData.C) >= lastCol {
+			continue
 		}
-
-		if colCount < lastCol {
-			sourceList := rowData.C
-			targetList := make([]xlsxC, 0, lastCol)
-
-			rowData.C = ws.SheetData.Row[rowIdx].C[:0]
-
-			for colIdx := 0; colIdx < lastCol; colIdx++ {
-				cellName, err := CoordinatesToCellName(colIdx+1, rowIdx+1)
-				if err != nil {
-					return err
-				}
-				targetList = append(targetList, xlsxC{R: cellName})
+		repaired = repaired || !ordered
+		sourceList := rowData.C
+		targetList := make([]xlsxC, 0, lastCol)
+		for colIdx := 0; colIdx < lastCol; colIdx++ {
+			cellName, err := CoordinatesToCellName(colIdx+1, rowIdx+1)
+			if err != nil {
+				return repaired, err
 			}
-
-			rowData.C = targetList
-
-			for colIdx := range sourceList {
-				colData := &sourceList[colIdx]
-				colNum, _, err := CellNameToCoordinates(colData.R)
-				if err != nil {
-					return err
-				}
-				ws.SheetData.Row[rowIdx].C[colNum-1] = *colData
+			targetList = append(targetList, xlsxC{R: cellName})
+		}
+		for colIdx := range sourceList {
+			colData := &sourceList[colIdx]
+			colNum, _, err := CellNameToCoordinates(colData.R)
+			if err != nil {
+				return repaired, err
+			}
+			if targetList[colNum-1].hasValue() && !colData.hasValue() {
+				continue
 			}
+			targetList[colNum-1] = *colData
 		}
+		rowData.C = targetList
 	}
-	return nil
+	return repaired, nil
 }
 
 // hasAttr determine if row non-default attributes.
@@ -832,6 +2335,145 @@ func (f *File) SetRowStyle(sheet string, start, end, styleID int) error {
 	return nil
 }
 
+// GetRowStyle provides a function to get the style ID of a whole row by given
+// worksheet name and Excel row number. It returns 0 if the row doesn't have
+// a row-level style. For example, get the style of row 1 on Sheet1:
+//
+//	styleID, err := f.GetRowStyle("Sheet1", 1)
+func (f *File) GetRowStyle(sheet string, row int) (int, error) {
+	if row < 1 {
+		return 0, newInvalidRowNumberError(row)
+	}
+	if row > TotalRows {
+		return 0, ErrMaxRows
+	}
+	ws, err := f.workSheetReader(sheet)
+	if err != nil {
+		return 0, err
+	}
+	if row > len(ws.SheetData.Row) {
+		return 0, nil
+	}
+	return ws.SheetData.Row[row-1].S, nil
+}
+
+// SetRowStruct provides a function to write the exported fields of a struct
+// to a row by given worksheet name, starting cell reference and a struct,
+// pointer to struct or slice of structs. The field values will be written in
+// order of the fields. If a slice of structs was given, the column header
+// names of the fields will be written to the starting row, and each struct
+// in the slice will be written to the following rows. The header name could
+// be specified by the "xlsx" struct tag, the field name will be used if the
+// tag is not specified, and the field will be ignored if the tag is "-". The
+// number format of the field could be specified by the "numFmt" option of
+// the tag with the built-in number format ID. For example, write the header
+// row and 2 rows of data to the Sheet1 start with the cell A1:
+//
+//	type Person struct {
+//	    Name     string    `xlsx:"Name"`
+//	    Age      int       `xlsx:"Age"`
+//	    Birthday time.Time `xlsx:"Birthday,numFmt=14"`
+//	    Note     string    `xlsx:"-"`
+//	}
+//	err := f.SetRowStruct("Sheet1", "A1", []Person{
+//	    {Name: "Alice", Age: 30, Birthday: time.Date(1994, 5, 1, 0, 0, 0, 0, time.UTC)},
+//	    {Name: "Bob", Age: 25, Birthday: time.Date(1999, 9, 9, 0, 0, 0, 0, time.UTC)},
+//	})
+func (f *File) SetRowStruct(sheet, cell string, v interface{}) error {
+	col, row, err := CellNameToCoordinates(cell)
+	if err != nil {
+		return err
+	}
+	value := reflect.ValueOf(v)
+	for value.Kind() == reflect.Ptr && !value.IsNil() {
+		value = value.Elem()
+	}
+	if value.Kind() == reflect.Struct {
+		return f.setRowStruct(sheet, col, row, value)
+	}
+	if (value.Kind() != reflect.Slice && value.Kind() != reflect.Array) ||
+		value.Type().Elem().Kind() != reflect.Struct {
+		return ErrParameterInvalid
+	}
+	fields := getStructFields(value.Type().Elem())
+	header := make([]interface{}, len(fields))
+	for i, field := range fields {
+		header[i] = field.name
+	}
+	if err = f.SetSheetRow(sheet, cell, &header); err != nil {
+		return err
+	}
+	for i := 0; i < value.Len(); i++ {
+		if err = f.setRowStruct(sheet, col, row+i+1, value.Index(i)); err != nil {
+			return err
+		}
+	}
+	return err
+}
+
+// setRowStruct provides a function to write the exported fields of a struct
+// to a row by given worksheet name and starting coordinates.
+func (f *File) setRowStruct(sheet string, col, row int, value reflect.Value) error {
+	for i, field := range getStructFields(value.Type()) {
+		cell, err := CoordinatesToCellName(col+i, row)
+		if err != nil {
+			return err
+		}
+		var val interface{}
+		if fieldValue := value.Field(field.index); fieldValue.Kind() != reflect.Ptr || !fieldValue.IsNil() {
+			val = reflect.Indirect(fieldValue).Interface()
+		}
+		if err = f.SetCellValue(sheet, cell, val); err != nil {
+			return err
+		}
+		if field.numFmt != nil {
+			styleID, err := f.NewStyle(&Style{NumFmt: *field.numFmt})
+			if err != nil {
+				return err
+			}
+			if err = f.SetCellStyle(sheet, cell, cell, styleID); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// structField defined the exported field of a struct for reading and writing
+// rows by struct.
+type structField struct {
+	index  int
+	name   string
+	numFmt *int
+}
+
+// getStructFields provides a function to get the exported fields of a struct
+// type with the column header name and options in the "xlsx" struct tag.
+func getStructFields(t reflect.Type) []structField {
+	var fields []structField
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("xlsx")
+		if field.PkgPath != "" || tag == "-" {
+			continue
+		}
+		options := strings.Split(tag, ",")
+		f := structField{index: i, name: options[0]}
+		if f.name == "" {
+			f.name = field.Name
+		}
+		for _, option := range options[1:] {
+			if numFmt := strings.TrimPrefix(option, "numFmt="); numFmt != option {
+				if id, err := strconv.Atoi(numFmt); err == nil {
+					f.numFmt = &id
+				}
+			}
+		}
+		fields = append(fields, f)
+	}
+	return fields
+}
+
 // convertRowHeightToPixels provides a function to convert the height of a
 // cell from user's units to pixels. If the height hasn't been set by the user
 // we use the default value. If the row is hidden it has a value of zero.
@@ -841,3 +2483,199 @@ func convertRowHeightToPixels(height float64) float64 {
 	}
 	return math.Ceil(4.0 / 3.4 * height)
 }
+
+// CellRecord directly maps the flattened cell record returned by the cell
+// records iterator. The Value is the cell value with the number format
+// applied, the RawValue is the original value stored in the worksheet, and
+// the IsMergeAnchor specifies if the cell is the top-left cell of a merged
+// range.
+type CellRecord struct {
+	Sheet         string
+	Cell          string
+	Value         string
+	RawValue      string
+	Type          CellType
+	StyleID       int
+	Formula       string
+	IsFormula     bool
+	IsMergeAnchor bool
+}
+
+// CellRecords defined an iterator to the flattened cell records of the
+// worksheets.
+type CellRecords struct {
+	err          error
+	f            *File
+	sheets       []string
+	sheetIdx     int
+	rows         *Rows
+	mergeAnchors map[string]bool
+	records      []CellRecord
+	record       CellRecord
+}
+
+// CellRecords returns a cell records iterator, used for extracting the value,
+// type, style and formula of the cells of the worksheets in a single pass,
+// such as feeding the spreadsheet understanding pipelines. The worksheets will
+// be iterated in order by given worksheet names, or all worksheets in the
+// workbook if no worksheet name was specified. The empty cells will be
+// skipped. For example:
+//
+//	records, err := f.CellRecords("Sheet1", "Sheet2")
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	for records.Next() {
+//	    record := records.Record()
+//	    fmt.Println(record.Sheet, record.Cell, record.Value, record.Type,
+//	        record.StyleID, record.IsFormula, record.IsMergeAnchor)
+//	}
+//	if err = records.Error(); err != nil {
+//	    fmt.Println(err)
+//	}
+//	if err = records.Close(); err != nil {
+//	    fmt.Println(err)
+//	}
+func (f *File) CellRecords(sheets ...string) (*CellRecords, error) {
+	if len(sheets) == 0 {
+		sheets = f.GetSheetList()
+	}
+	for _, sheet := range sheets {
+		if err := checkSheetName(sheet); err != nil {
+			return nil, err
+		}
+		if _, ok := f.getSheetXMLPath(sheet); !ok {
+			return nil, ErrSheetNotExist{sheet}
+		}
+	}
+	return &CellRecords{f: f, sheets: sheets}, nil
+}
+
+// Next will return true if find the next cell record.
+func (cr *CellRecords) Next() bool {
+	for cr.err == nil {
+		if len(cr.records) > 0 {
+			cr.record, cr.records = cr.records[0], cr.records[1:]
+			return true
+		}
+		if cr.rows == nil {
+			if cr.sheetIdx >= len(cr.sheets) {
+				return false
+			}
+			if cr.err = cr.openSheet(cr.sheets[cr.sheetIdx]); cr.err != nil {
+				return false
+			}
+			cr.sheetIdx++
+		}
+		if !cr.rows.Next() {
+			if cr.err = cr.rows.Error(); cr.err == nil {
+				cr.err = cr.rows.Close()
+			}
+			cr.rows = nil
+			continue
+		}
+		cr.err = cr.readRow()
+	}
+	return false
+}
+
+// Record returns the current cell record.
+func (cr *CellRecords) Record() CellRecord {
+	return cr.record
+}
+
+// Error will return the error when the error occurs.
+func (cr *CellRecords) Error() error {
+	return cr.err
+}
+
+// Close closes the open worksheet XML file in the system temporary
+// directory.
+func (cr *CellRecords) Close() error {
+	if cr.rows != nil {
+		rows := cr.rows
+		cr.rows = nil
+		return rows.Close()
+	}
+	return nil
+}
+
+// openSheet provides a function to open the rows iterator and read the top-left
+// cells of the merged ranges by given worksheet name for the cell records
+// iterator.
+func (cr *CellRecords) openSheet(sheet string) error {
+	rows, err := cr.f.Rows(sheet)
+	if err != nil {
+		return err
+	}
+	cr.rows, cr.mergeAnchors = rows, map[string]bool{}
+	needClose, decoder, tempFile, err := cr.f.xmlDecoder(rows.sheet)
+	if needClose && tempFile != nil {
+		defer tempFile.Close()
+	}
+	if err != nil {
+		return err
+	}
+	for {
+		token, _ := decoder.Token()
+		if token == nil {
+			return err
+		}
+		xmlElement, ok := token.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		switch xmlElement.Name.Local {
+		case "sheetData":
+			if err = decoder.Skip(); err != nil {
+				return err
+			}
+		case "mergeCell":
+			var ref string
+			for _, attr := range xmlElement.Attr {
+				if attr.Name.Local == "ref" {
+					ref = attr.Value
+				}
+			}
+			coordinates, err := rangeRefToCoordinates(ref)
+			if err != nil {
+				return err
+			}
+			_ = sortCoordinates(coordinates)
+			cell, _ := CoordinatesToCellName(coordinates[0], coordinates[1])
+			cr.mergeAnchors[cell] = true
+		}
+	}
+}
+
+// readRow provides a function to read the cells of the current row to the cell
+// records.
+func (cr *CellRecords) readRow() error {
+	rowIterator := rowXMLIterator{typed: true}
+	cr.rows.rawCellValue = false
+	if err := cr.rows.parseRow(&rowIterator); err != nil {
+		return err
+	}
+	for i, cell := range rowIterator.typedCells {
+		if cell.RawValue == "" && cell.Formula == "" {
+			continue
+		}
+		ref, err := CoordinatesToCellName(i+1, cr.rows.seekRow)
+		if err != nil {
+			return err
+		}
+		cr.records = append(cr.records, CellRecord{
+			Sheet:         cr.rows.sheetName,
+			Cell:          ref,
+			Value:         rowIterator.cells[i],
+			RawValue:      cell.RawValue,
+			Type:          cell.Type,
+			StyleID:       cell.StyleID,
+			Formula:       cell.Formula,
+			IsFormula:     cell.Formula != "",
+			IsMergeAnchor: cr.mergeAnchors[ref],
+		})
+	}
+	return nil
+}
@@ -4,8 +4,13 @@ import (
 	"bytes"
 	"encoding/xml"
 	"fmt"
+	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -19,6 +24,201 @@ func TestGetRows(t *testing.T) {
 	f.Pkg.Store(defaultXMLPathSharedStrings, MacintoshCyrillicCharset)
 	_, err := f.GetRows("Sheet1")
 	assert.NoError(t, err)
+	// Test get rows with padding ragged rows
+	f = NewFile()
+	assert.NoError(t, f.SetCellValue("Sheet1", "A1", "A1"))
+	assert.NoError(t, f.SetCellValue("Sheet1", "C2", "C2"))
+	assert.NoError(t, f.SetCellValue("Sheet1", "B4", "B4"))
+	rows, err := f.GetRows("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{{"A1"}, {"", "", "C2"}, nil, {"", "B4"}}, rows)
+	rows, err = f.GetRows("Sheet1", Options{PadRows: true})
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{{"A1", "", ""}, {"", "", "C2"}, {"", "", ""}, {"", "B4", ""}}, rows)
+	assert.NoError(t, f.Close())
+}
+
+func TestGetRowsParallel(t *testing.T) {
+	f := NewFile()
+	styleID, err := f.NewStyle(&Style{NumFmt: 2})
+	assert.NoError(t, err)
+	for row := 1; row <= 500; row++ {
+		if row%7 == 0 {
+			continue
+		}
+		cell, err := CoordinatesToCellName(row%5+1, row)
+		assert.NoError(t, err)
+		assert.NoError(t, f.SetCellValue("Sheet1", cell, row))
+		assert.NoError(t, f.SetCellValue("Sheet1", "F"+strconv.Itoa(row), fmt.Sprintf("R%d", row)))
+		assert.NoError(t, f.SetCellStyle("Sheet1", cell, cell, styleID))
+	}
+	expected, err := f.GetRows("Sheet1")
+	assert.NoError(t, err)
+	for _, workers := range []int{0, 1, 4, 1000} {
+		rows, err := f.GetRowsParallel("Sheet1", workers)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, rows, workers)
+	}
+	for _, opts := range []Options{{RawCellValue: true}, {PadRows: true}} {
+		expected, err := f.GetRows("Sheet1", opts)
+		assert.NoError(t, err)
+		rows, err := f.GetRowsParallel("Sheet1", 4, opts)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, rows)
+	}
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestGetRowsParallel.xlsx")))
+	assert.NoError(t, f.Close())
+
+	// Test get rows in parallel from the worksheet in system temporary directory
+	f, err = OpenFile(filepath.Join("test", "TestGetRowsParallel.xlsx"), Options{UnzipXMLSizeLimit: 128})
+	assert.NoError(t, err)
+	rows, err := f.GetRowsParallel("Sheet1", 4)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, rows)
+	assert.NoError(t, f.Close())
+
+	// Test get rows in parallel with the malformed rows
+	for _, sheetData := range []string{
+		`<sheetData><row r="2"><c r="A2" t="str"><v>A2</v></c></row><row r="1"><c r="A1" t="str"><v>A1</v></c></row></sheetData>`,
+		`<sheetData><row><c t="str"><v>A1</v></c></row><row><c t="str"><v>A2</v></c></row></sheetData>`,
+		`<sheetData><row r="1"><c r="A1" t="str"><v>A1</v></c></row><row r="2"><c r="-" t="str"><v>A2</v></c></row></sheetData>`,
+	} {
+		f = NewFile()
+		f.Sheet.Delete("xl/worksheets/sheet1.xml")
+		f.Pkg.Store("xl/worksheets/sheet1.xml", []byte(fmt.Sprintf(`<worksheet xmlns="%s">%s</worksheet>`, NameSpaceSpreadSheet.Value, sheetData)))
+		f.checked = sync.Map{}
+		expected, err := f.GetRows("Sheet1")
+		assert.NoError(t, err)
+		f.Sheet.Delete("xl/worksheets/sheet1.xml")
+		f.Pkg.Store("xl/worksheets/sheet1.xml", []byte(fmt.Sprintf(`<worksheet xmlns="%s">%s</worksheet>`, NameSpaceSpreadSheet.Value, sheetData)))
+		f.checked = sync.Map{}
+		rows, err := f.GetRowsParallel("Sheet1", 2)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, rows)
+		assert.NoError(t, f.Close())
+	}
+	// Test get rows in parallel with the namespace prefixed sheet data
+	f = NewFile()
+	f.Sheet.Delete("xl/worksheets/sheet1.xml")
+	f.Pkg.Store("xl/worksheets/sheet1.xml", []byte(fmt.Sprintf(`<x:worksheet xmlns:x="%s"><x:sheetData><x:row r="1"><x:c r="A1" t="str"><x:v>A1</x:v></x:c></x:row><x:row r="3"><x:c r="B3" t="str"><x:v>B3</x:v></x:c></x:row></x:sheetData></x:worksheet>`, NameSpaceSpreadSheet.Value)))
+	f.checked = sync.Map{}
+	assert.Len(t, splitSheetDataRows(f.readXML("xl/worksheets/sheet1.xml"), 2), 2)
+	rows, err = f.GetRowsParallel("Sheet1", 2)
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{{"A1"}, nil, {"", "B3"}}, rows)
+	assert.NoError(t, f.Close())
+	// Test get rows in parallel with empty worksheet
+	f = NewFile()
+	rows, err = f.GetRowsParallel("Sheet1", 2)
+	assert.NoError(t, err)
+	assert.Empty(t, rows)
+	// Test get rows in parallel with not exist worksheet
+	_, err = f.GetRowsParallel("SheetN", 2)
+	assert.EqualError(t, err, "sheet SheetN does not exist")
+	// Test get rows in parallel with invalid sheet name
+	_, err = f.GetRowsParallel("Sheet:1", 2)
+	assert.Equal(t, ErrSheetNameInvalid, err)
+	// Test get rows in parallel with unsupported charset shared strings table
+	assert.NoError(t, f.SetCellValue("Sheet1", "A1", "A1"))
+	assert.NoError(t, f.SetCellValue("Sheet1", "A2", "A2"))
+	f.SharedStrings = nil
+	f.Pkg.Store(defaultXMLPathSharedStrings, MacintoshCyrillicCharset)
+	_, err = f.GetRowsParallel("Sheet1", 2)
+	assert.NoError(t, err)
+	assert.NoError(t, f.Close())
+}
+
+func TestGetRowsSample(t *testing.T) {
+	f := NewFile()
+	assert.NoError(t, f.SetSheetRow("Sheet1", "A1", &[]interface{}{"ID", "Name"}))
+	for row := 2; row <= 100; row++ {
+		if row%10 == 0 {
+			continue
+		}
+		assert.NoError(t, f.SetSheetRow("Sheet1", "A"+strconv.Itoa(row), &[]interface{}{row, fmt.Sprintf("R%d", row)}))
+	}
+	expected, err := f.GetRows("Sheet1")
+	assert.NoError(t, err)
+	// Test get every Nth row
+	sample, err := f.GetRowsSample("Sheet1", &SampleOptions{Step: 10, HeaderRows: 1})
+	assert.NoError(t, err)
+	assert.Equal(t, 99, sample.TotalRows)
+	assert.Equal(t, []int{1, 2, 12, 22, 32, 42, 52, 62, 72, 82, 92}, sample.RowNumbers)
+	for idx, rowNum := range sample.RowNumbers {
+		assert.Equal(t, expected[rowNum-1], sample.Rows[idx])
+	}
+	sample, err = f.GetRowsSample("Sheet1", &SampleOptions{Step: 9, Size: 3})
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1, 10, 19}, sample.RowNumbers)
+	assert.Empty(t, sample.Rows[1])
+	assert.Equal(t, 99, sample.TotalRows)
+	sample, err = f.GetRowsSample("Sheet1", &SampleOptions{}, Options{PadRows: true})
+	assert.NoError(t, err)
+	assert.Len(t, sample.Rows, 99)
+	assert.Equal(t, []string{"", ""}, sample.Rows[9])
+	// Test get random sample of rows
+	sample, err = f.GetRowsSample("Sheet1", &SampleOptions{Method: SampleReservoir, Size: 10, Seed: 1, HeaderRows: 1})
+	assert.NoError(t, err)
+	assert.Equal(t, 99, sample.TotalRows)
+	assert.Len(t, sample.Rows, 11)
+	assert.Equal(t, 1, sample.RowNumbers[0])
+	for idx, rowNum := range sample.RowNumbers {
+		if idx > 0 {
+			assert.Greater(t, rowNum, sample.RowNumbers[idx-1])
+		}
+		assert.Equal(t, expected[rowNum-1], sample.Rows[idx])
+	}
+	sample2, err := f.GetRowsSample("Sheet1", &SampleOptions{Method: SampleReservoir, Size: 10, Seed: 1, HeaderRows: 1})
+	assert.NoError(t, err)
+	assert.Equal(t, sample, sample2)
+	sample2, err = f.GetRowsSample("Sheet1", &SampleOptions{Method: SampleReservoir, Size: 10, Seed: 2, HeaderRows: 1})
+	assert.NoError(t, err)
+	assert.NotEqual(t, sample.RowNumbers, sample2.RowNumbers)
+	sample, err = f.GetRowsSample("Sheet1", &SampleOptions{Method: SampleReservoir, Size: 200})
+	assert.NoError(t, err)
+	assert.Len(t, sample.Rows, 99)
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestGetRowsSample.xlsx")))
+	assert.NoError(t, f.Close())
+
+	// Test get sample of rows from the worksheet in system temporary directory
+	f, err = OpenFile(filepath.Join("test", "TestGetRowsSample.xlsx"), Options{UnzipXMLSizeLimit: 128})
+	assert.NoError(t, err)
+	sample, err = f.GetRowsSample("Sheet1", &SampleOptions{Step: 10, HeaderRows: 1})
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1, 2, 12, 22, 32, 42, 52, 62, 72, 82, 92}, sample.RowNumbers)
+	assert.Equal(t, expected[91], sample.Rows[10])
+	assert.NoError(t, f.Close())
+
+	// Test get sample of rows with the repaired worksheet
+	f = NewFile()
+	f.Sheet.Delete("xl/worksheets/sheet1.xml")
+	f.checked.Delete("xl/worksheets/sheet1.xml")
+	f.Pkg.Store("xl/worksheets/sheet1.xml", []byte(fmt.Sprintf(`<worksheet xmlns="%s"><sheetData><row r="2"><c r="A2" t="str"><v>A2</v></c></row><row r="1"><c r="A1" t="str"><v>A1</v></c></row></sheetData></worksheet>`, NameSpaceSpreadSheet.Value)))
+	sample, err = f.GetRowsSample("Sheet1", &SampleOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, &RowsSample{Rows: [][]string{{"A1"}, {"A2"}}, RowNumbers: []int{1, 2}, TotalRows: 2}, sample)
+	assert.NoError(t, f.Close())
+	// Test get sample of rows with invalid sample options
+	f = NewFile()
+	for _, opts := range []*SampleOptions{
+		nil, {Step: -1}, {Size: -1}, {HeaderRows: -1}, {Method: SampleReservoir}, {Method: 2},
+	} {
+		_, err = f.GetRowsSample("Sheet1", opts)
+		assert.Equal(t, ErrParameterInvalid, err)
+	}
+	// Test get sample of rows with not exist worksheet
+	_, err = f.GetRowsSample("SheetN", &SampleOptions{})
+	assert.EqualError(t, err, "sheet SheetN does not exist")
+	// Test get sample of rows with invalid sheet name
+	_, err = f.GetRowsSample("Sheet:1", &SampleOptions{})
+	assert.Equal(t, ErrSheetNameInvalid, err)
+	// Test get sample of rows with unsupported charset shared strings table
+	assert.NoError(t, f.SetCellValue("Sheet1", "A1", "A1"))
+	f.SharedStrings = nil
+	f.Pkg.Store(defaultXMLPathSharedStrings, MacintoshCyrillicCharset)
+	_, err = f.GetRowsSample("Sheet1", &SampleOptions{})
+	assert.NoError(t, err)
+	assert.NoError(t, f.Close())
 }
 
 func TestRows(t *testing.T) {
@@ -114,9 +314,9 @@ func TestRowsIterator(t *testing.T) {
 
 func TestRowsGetRowOpts(t *testing.T) {
 	sheetName := "Sheet2"
-	expectedRowStyleID1 := RowOpts{Height: 17.0, Hidden: false, StyleID: 1}
-	expectedRowStyleID2 := RowOpts{Height: 17.0, Hidden: false, StyleID: 0}
-	expectedRowStyleID3 := RowOpts{Height: 17.0, Hidden: false, StyleID: 2}
+	expectedRowStyleID1 := RowOpts{Height: 17.0, Hidden: false, StyleID: 1, Spans: "1:4"}
+	expectedRowStyleID2 := RowOpts{Height: 17.0, Hidden: false, StyleID: 0, Spans: "1:4"}
+	expectedRowStyleID3 := RowOpts{Height: 17.0, Hidden: false, StyleID: 2, Spans: "1:4"}
 	f, err := OpenFile(filepath.Join("test", "Book1.xlsx"))
 	require.NoError(t, err)
 
@@ -211,7 +411,110 @@ func TestRowHeight(t *testing.T) {
 		t.FailNow()
 	}
 
-	assert.Equal(t, 0.0, convertColWidthToPixels(0))
+	assert.Equal(t, 0.0, convertColWidthToPixels(0, defaultMaxDigitWidth))
+}
+
+func TestAutoFitRows(t *testing.T) {
+	f := NewFile()
+	wrapStyle, err := f.NewStyle(&Style{Alignment: &Alignment{WrapText: true}})
+	assert.NoError(t, err)
+	fontStyle, err := f.NewStyle(&Style{Font: &Font{Size: 22}})
+	assert.NoError(t, err)
+	for cell, val := range map[string]string{
+		"A1": "hello world foo", "A2": "hello world foo", "A3": "hello",
+		"A4": "a\nb\nc", "A5": "hello world foo", "A6": "一二三四五六七八九十",
+	} {
+		assert.NoError(t, f.SetCellValue("Sheet1", cell, val))
+	}
+	for _, cell := range []string{"A1", "A4", "A5", "A6"} {
+		assert.NoError(t, f.SetCellStyle("Sheet1", cell, cell, wrapStyle))
+	}
+	assert.NoError(t, f.SetCellStyle("Sheet1", "A3", "A3", fontStyle))
+	assert.NoError(t, f.MergeCell("Sheet1", "A5", "B5"))
+	assert.NoError(t, f.SetRowHeight("Sheet1", 5, 50))
+	assert.NoError(t, f.AutoFitRows("Sheet1", 7, 1))
+	for row, expected := range map[int]float64{1: 30, 2: 15, 3: 30, 4: 45, 5: 15, 6: 45, 7: 15} {
+		height, err := f.GetRowHeight("Sheet1", row)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, height, row)
+	}
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestAutoFitRows.xlsx")))
+	// Test auto fit rows with invalid row number
+	assert.EqualError(t, f.AutoFitRows("Sheet1", 0, 1), newInvalidRowNumberError(0).Error())
+	assert.EqualError(t, f.AutoFitRows("Sheet1", 1, TotalRows+1), ErrMaxRows.Error())
+	// Test auto fit rows on not exists worksheet
+	assert.EqualError(t, f.AutoFitRows("SheetN", 1, 1), "sheet SheetN does not exist")
+	// Test auto fit rows with invalid cell reference
+	ws, err := f.workSheetReader("Sheet1")
+	assert.NoError(t, err)
+	ws.SheetData.Row[0].C[0].R = "A"
+	assert.Equal(t, newCellNameToCoordinatesError("A", newInvalidCellNameError("A")), f.AutoFitRows("Sheet1", 1, 1))
+	// Test auto fit rows with invalid merged cell reference
+	ws.MergeCells.Cells[0].Ref = "A"
+	assert.Equal(t, ErrParameterInvalid, f.AutoFitRows("Sheet1", 2, 2))
+	// Test auto fit rows with unsupported charset shared strings table
+	f.SharedStrings = nil
+	f.Pkg.Store(defaultXMLPathSharedStrings, MacintoshCyrillicCharset)
+	assert.EqualError(t, f.AutoFitRows("Sheet1", 1, 1), "XML syntax error on line 1: invalid UTF-8")
+	// Test auto fit rows with unsupported charset style sheet
+	f.Styles = nil
+	f.Pkg.Store(defaultXMLPathStyles, MacintoshCyrillicCharset)
+	assert.EqualError(t, f.AutoFitRows("Sheet1", 1, 1), "XML syntax error on line 1: invalid UTF-8")
+	// Test get wrapped text lines with a word longer than the cell width
+	assert.Equal(t, 3, getWrappedTextLines(strings.Repeat("a", 20), 64, defaultMaxDigitWidth))
+}
+
+func TestAutoFitRowHeight(t *testing.T) {
+	f := NewFile(Options{AutoFitRowHeight: true})
+	wrapStyle, err := f.NewStyle(&Style{Alignment: &Alignment{WrapText: true}})
+	assert.NoError(t, err)
+	fontStyle, err := f.NewStyle(&Style{Font: &Font{Size: 22}, Alignment: &Alignment{WrapText: true}})
+	assert.NoError(t, err)
+	assert.NoError(t, f.SetCellStyle("Sheet1", "A1", "D8", wrapStyle))
+	assert.NoError(t, f.SetCellStyle("Sheet1", "E1", "E1", fontStyle))
+	assert.NoError(t, f.MergeCell("Sheet1", "B3", "C3"))
+	assert.NoError(t, f.MergeCell("Sheet1", "D4", "D5"))
+	assert.NoError(t, f.SetRowHeight("Sheet1", 6, 50))
+	assert.NoError(t, f.SetCellValue("Sheet1", "A1", "hello world foo"))
+	assert.NoError(t, f.SetCellStr("Sheet1", "A2", "a\nb\nc"))
+	assert.NoError(t, f.SetCellValue("Sheet1", "C3", "hello world foo"))
+	assert.NoError(t, f.SetCellValue("Sheet1", "D4", "a\nb\nc\nd"))
+	assert.NoError(t, f.SetCellValue("Sheet1", "A6", "hello world foo"))
+	assert.NoError(t, f.SetCellValue("Sheet1", "F7", "hello world foo"))
+	assert.NoError(t, f.SetCellValue("Sheet1", "E8", "hello world foo"))
+	for row, expected := range map[int]float64{1: 30, 2: 45, 3: 15, 4: 15, 5: 45, 6: 50, 7: 15, 8: 15} {
+		height, err := f.GetRowHeight("Sheet1", row)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, height, row)
+	}
+	// Test auto fit row height with the font size of the cell
+	assert.NoError(t, f.SetCellValue("Sheet1", "E1", "hi yo"))
+	height, err := f.GetRowHeight("Sheet1", 1)
+	assert.NoError(t, err)
+	assert.Equal(t, 60.0, height)
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestAutoFitRowHeight.xlsx")))
+	// Test auto fit row height with invalid merged cell reference
+	ws, err := f.workSheetReader("Sheet1")
+	assert.NoError(t, err)
+	ws.MergeCells.Cells[0].Ref = "A"
+	assert.Equal(t, ErrParameterInvalid, f.fitWrappedTextRowHeight(ws, 1, 1, "hello", wrapStyle))
+	ws.MergeCells = nil
+	// Test auto fit row height with unsupported charset style sheet
+	f.Styles = nil
+	f.Pkg.Store(defaultXMLPathStyles, MacintoshCyrillicCharset)
+	assert.EqualError(t, f.SetCellValue("Sheet1", "A1", "hello"), "XML syntax error on line 1: invalid UTF-8")
+	assert.NoError(t, f.Close())
+
+	// Test set cell value without auto fit row height
+	f = NewFile()
+	wrapStyle, err = f.NewStyle(&Style{Alignment: &Alignment{WrapText: true}})
+	assert.NoError(t, err)
+	assert.NoError(t, f.SetCellStyle("Sheet1", "A1", "A1", wrapStyle))
+	assert.NoError(t, f.SetCellValue("Sheet1", "A1", "hello world foo"))
+	height, err = f.GetRowHeight("Sheet1", 1)
+	assert.NoError(t, err)
+	assert.Equal(t, defaultRowHeight, height)
+	assert.NoError(t, f.Close())
 }
 
 func TestColumns(t *testing.T) {
@@ -236,6 +539,7 @@ func TestColumns(t *testing.T) {
 	_, err = rows.Columns()
 	assert.NoError(t, err)
 
+	rows.curRow, rows.seekRow = 0, 0
 	rows.decoder = f.xmlNewDecoder(bytes.NewReader([]byte(`<worksheet><sheetData><row r="1"><c r="A" t="s"><v>1</v></c></row></sheetData></worksheet>`)))
 	assert.True(t, rows.Next())
 	_, err = rows.Columns()
@@ -296,6 +600,161 @@ func TestRowVisibility(t *testing.T) {
 	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestRowVisibility.xlsx")))
 }
 
+func TestGroupRows(t *testing.T) {
+	f := NewFile()
+	assert.NoError(t, f.GroupRows("Sheet1", 2, 6, false))
+	assert.NoError(t, f.GroupRows("Sheet1", 3, 4, true))
+	for row, expected := range map[int]uint8{1: 0, 2: 1, 3: 2, 4: 2, 5: 1, 6: 1, 7: 0} {
+		level, err := f.GetRowOutlineLevel("Sheet1", row)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, level, row)
+	}
+	for row, expected := range map[int]bool{2: true, 3: false, 4: false, 5: true} {
+		visible, err := f.GetRowVisible("Sheet1", row)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, visible, row)
+	}
+	ws, err := f.workSheetReader("Sheet1")
+	assert.NoError(t, err)
+	assert.True(t, ws.SheetData.Row[4].Collapsed)
+	assert.False(t, ws.SheetData.Row[6].Collapsed)
+	assert.Equal(t, uint8(2), ws.SheetFormatPr.OutlineLevelRow)
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestGroupRows.xlsx")))
+
+	// Test ungroup rows
+	ws, err = f.workSheetReader("Sheet1")
+	assert.NoError(t, err)
+	assert.NoError(t, f.UngroupRows("Sheet1", 3, 4))
+	assert.False(t, ws.SheetData.Row[4].Collapsed)
+	assert.Equal(t, uint8(1), ws.SheetFormatPr.OutlineLevelRow)
+	for row := 3; row <= 4; row++ {
+		visible, err := f.GetRowVisible("Sheet1", row)
+		assert.NoError(t, err)
+		assert.False(t, visible)
+	}
+	assert.NoError(t, f.UngroupRows("Sheet1", 1, 10))
+	assert.Equal(t, uint8(0), ws.SheetFormatPr.OutlineLevelRow)
+	for row := 1; row <= 7; row++ {
+		visible, err := f.GetRowVisible("Sheet1", row)
+		assert.NoError(t, err)
+		assert.True(t, visible)
+	}
+
+	// Test group rows with the summary rows above the detail
+	assert.NoError(t, f.SetSheetProps("Sheet1", &SheetPropsOptions{OutlineSummaryBelow: boolPtr(false)}))
+	assert.NoError(t, f.GroupRows("Sheet1", 2, 3, true))
+	assert.True(t, ws.SheetData.Row[0].Collapsed)
+	assert.NoError(t, f.UngroupRows("Sheet1", 2, 3))
+	assert.False(t, ws.SheetData.Row[0].Collapsed)
+	assert.NoError(t, f.GroupRows("Sheet1", 1, 2, true))
+	assert.NoError(t, f.UngroupRows("Sheet1", 1, 2))
+
+	// Test group rows without sheet format properties
+	f = NewFile()
+	ws, err = f.workSheetReader("Sheet1")
+	assert.NoError(t, err)
+	ws.SheetFormatPr = nil
+	assert.NoError(t, f.UngroupRows("Sheet1", 1, 2))
+	assert.Nil(t, ws.SheetFormatPr)
+	assert.NoError(t, f.GroupRows("Sheet1", TotalRows-1, TotalRows, true))
+	assert.Equal(t, uint8(1), ws.SheetFormatPr.OutlineLevelRow)
+	assert.NoError(t, f.UngroupRows("Sheet1", TotalRows-1, TotalRows))
+	// Test group rows exceeds the maximum outline level
+	for i := 0; i < 7; i++ {
+		assert.NoError(t, f.GroupRows("Sheet1", 1, 2, false))
+	}
+	assert.Equal(t, ErrOutlineLevel, f.GroupRows("Sheet1", 2, 3, false))
+	level, err := f.GetRowOutlineLevel("Sheet1", 3)
+	assert.NoError(t, err)
+	assert.Equal(t, uint8(0), level)
+	// Test group and ungroup rows with invalid parameters
+	for _, fn := range []func(sheet string, start, end int) error{
+		func(sheet string, start, end int) error { return f.GroupRows(sheet, start, end, false) },
+		f.UngroupRows,
+	} {
+		assert.Equal(t, newInvalidRowNumberError(0), fn("Sheet1", 0, 1))
+		assert.Equal(t, ErrParameterInvalid, fn("Sheet1", 2, 1))
+		assert.Equal(t, ErrMaxRows, fn("Sheet1", 1, TotalRows+1))
+		assert.EqualError(t, fn("SheetN", 1, 2), "sheet SheetN does not exist")
+	}
+	assert.NoError(t, f.Close())
+}
+
+func TestCollapseOutlineLevel(t *testing.T) {
+	f := NewFile()
+	assert.NoError(t, f.GroupRows("Sheet1", 2, 6, false))
+	assert.NoError(t, f.GroupRows("Sheet1", 3, 4, false))
+	for _, col := range []string{"B", "C", "D"} {
+		assert.NoError(t, f.SetColOutlineLevel("Sheet1", col, 1))
+	}
+	assert.NoError(t, f.SetColOutlineLevel("Sheet1", "C", 2))
+	ws, err := f.workSheetReader("Sheet1")
+	assert.NoError(t, err)
+	checkOutline := func(rows map[int]bool, cols map[string]bool, collapsedRows, collapsedCols []int) {
+		for row, expected := range rows {
+			visible, err := f.GetRowVisible("Sheet1", row)
+			assert.NoError(t, err)
+			assert.Equal(t, expected, visible, row)
+		}
+		for col, expected := range cols {
+			visible, err := f.GetColVisible("Sheet1", col)
+			assert.NoError(t, err)
+			assert.Equal(t, expected, visible, col)
+		}
+		var rowsCollapsed, colsCollapsed []int
+		for _, row := range ws.SheetData.Row {
+			if row.Collapsed {
+				rowsCollapsed = append(rowsCollapsed, row.R)
+			}
+		}
+		for _, col := range ws.Cols.Col {
+			if col.Collapsed {
+				colsCollapsed = append(colsCollapsed, col.Min)
+			}
+		}
+		assert.Equal(t, collapsedRows, rowsCollapsed)
+		assert.ElementsMatch(t, collapsedCols, colsCollapsed)
+	}
+	// Test collapse the groups with outline level 2
+	assert.NoError(t, f.CollapseOutlineLevel("Sheet1", 2))
+	checkOutline(map[int]bool{1: true, 2: true, 3: false, 4: false, 5: true, 6: true, 7: true},
+		map[string]bool{"A": true, "B": true, "C": false, "D": true, "E": true}, []int{5}, []int{4})
+	// Test collapse all groups
+	assert.NoError(t, f.CollapseOutlineLevel("Sheet1", 1))
+	checkOutline(map[int]bool{1: true, 2: false, 3: false, 4: false, 5: false, 6: false, 7: true},
+		map[string]bool{"A": true, "B": false, "C": false, "D": false, "E": true}, []int{5, 7}, []int{4, 5})
+	// Test expand all groups
+	assert.NoError(t, f.CollapseOutlineLevel("Sheet1", 0))
+	checkOutline(map[int]bool{1: true, 2: true, 3: true, 4: true, 5: true, 6: true, 7: true},
+		map[string]bool{"A": true, "B": true, "C": true, "D": true, "E": true}, nil, nil)
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestCollapseOutlineLevel.xlsx")))
+
+	// Test collapse the groups with the summary rows and columns before the detail
+	assert.NoError(t, f.SetSheetProps("Sheet1", &SheetPropsOptions{
+		OutlineSummaryBelow: boolPtr(false), OutlineSummaryRight: boolPtr(false),
+	}))
+	ws, err = f.workSheetReader("Sheet1")
+	assert.NoError(t, err)
+	assert.NoError(t, f.CollapseOutlineLevel("Sheet1", 1))
+	checkOutline(map[int]bool{1: true, 2: false, 6: false, 7: true},
+		map[string]bool{"A": true, "B": false, "D": false, "E": true}, []int{1, 2}, []int{1, 2})
+
+	// Test collapse the groups which summary row doesn't exist
+	f = NewFile()
+	assert.NoError(t, f.SetRowOutlineLevel("Sheet1", 2, 1))
+	assert.NoError(t, f.CollapseOutlineLevel("Sheet1", 1))
+	ws, err = f.workSheetReader("Sheet1")
+	assert.NoError(t, err)
+	assert.Len(t, ws.SheetData.Row, 3)
+	assert.True(t, ws.SheetData.Row[2].Collapsed)
+	assert.True(t, ws.SheetData.Row[1].Hidden)
+	// Test collapse outline level with invalid level
+	assert.Equal(t, ErrOutlineLevel, f.CollapseOutlineLevel("Sheet1", 8))
+	// Test collapse outline level on not exists worksheet
+	assert.EqualError(t, f.CollapseOutlineLevel("SheetN", 1), "sheet SheetN does not exist")
+	assert.NoError(t, f.Close())
+}
+
 func TestRemoveRow(t *testing.T) {
 	f := NewFile()
 	sheet1 := f.GetSheetName(0)
@@ -359,6 +818,160 @@ func TestRemoveRow(t *testing.T) {
 	assert.EqualError(t, f.RemoveRow("Sheet:1", 1), ErrSheetNameInvalid.Error())
 }
 
+func TestRemoveRows(t *testing.T) {
+	prepare := func() *File {
+		f := NewFile()
+		_, err := f.NewSheet("Sheet2")
+		assert.NoError(t, err)
+		assert.NoError(t, fillCells(f, "Sheet1", 5, 12))
+		for cell, formula := range map[string]string{
+			"F1": "SUM(A1:A12)", "F2": "A3+A4", "F6": "$A$1+A9", "F8": "SUM(A2:B4)",
+		} {
+			assert.NoError(t, f.SetCellFormula("Sheet1", cell, formula))
+		}
+		assert.NoError(t, f.SetCellFormula("Sheet2", "A1", "Sheet1!A3+Sheet1!A10"))
+		for _, rangeRef := range [][]string{{"A1", "B3"}, {"C2", "C4"}, {"D3", "D6"}, {"A7", "B8"}, {"C9", "D11"}} {
+			assert.NoError(t, f.MergeCell("Sheet1", rangeRef[0], rangeRef[1]))
+		}
+		for _, cell := range []string{"E2", "E3", "E9"} {
+			assert.NoError(t, f.SetCellHyperLink("Sheet1", cell, "Sheet2!A1", "Location"))
+		}
+		assert.NoError(t, f.AutoFilter("Sheet1", "G1:G12", nil))
+		assert.NoError(t, f.SetDefinedName(&DefinedName{Name: "Block", RefersTo: "Sheet1!$A$3:$B$10"}))
+		dv := NewDataValidation(true)
+		dv.Sqref = "H2:H10"
+		assert.NoError(t, dv.SetDropList([]string{"1", "2"}))
+		assert.NoError(t, f.AddDataValidation("Sheet1", dv))
+		format, err := f.NewConditionalStyle(&Style{Font: &Font{Color: "FF0000"}})
+		assert.NoError(t, err)
+		assert.NoError(t, f.SetConditionalFormat("Sheet1", "I3:I9", []ConditionalFormatOptions{
+			{Type: "cell", Criteria: ">", Format: format, Value: "6"},
+		}))
+		assert.NoError(t, f.AddPicture("Sheet1", "J9", filepath.Join("test", "images", "excel.png"), nil))
+		return f
+	}
+	f1, f2 := prepare(), prepare()
+	assert.NoError(t, f1.RemoveRows("Sheet1", 2, 3))
+	for i := 0; i < 3; i++ {
+		assert.NoError(t, f2.RemoveRow("Sheet1", 2))
+	}
+	for _, partName := range []string{
+		"xl/worksheets/sheet1.xml", "xl/worksheets/sheet2.xml", "xl/workbook.xml", "xl/drawings/drawing1.xml",
+	} {
+		content1, err := f1.GetPartContent(partName)
+		assert.NoError(t, err)
+		content2, err := f2.GetPartContent(partName)
+		assert.NoError(t, err)
+		assert.Equal(t, string(content2), string(content1), partName)
+	}
+	formula, err := f1.GetCellFormula("Sheet1", "F3")
+	assert.NoError(t, err)
+	assert.Equal(t, "$A$1+A6", formula)
+	rows, err := f1.GetRows("Sheet1")
+	assert.NoError(t, err)
+	assert.Len(t, rows, 9)
+	// Test remove rows with invalid parameters
+	assert.Equal(t, newInvalidRowNumberError(0), f1.RemoveRows("Sheet1", 0, 1))
+	assert.Equal(t, ErrMaxRows, f1.RemoveRows("Sheet1", TotalRows+1, 1))
+	assert.Equal(t, ErrMaxRows, f1.RemoveRows("Sheet1", 1, TotalRows+1))
+	assert.Equal(t, ErrParameterInvalid, f1.RemoveRows("Sheet1", 1, 0))
+	// Test remove rows on not exists worksheet
+	assert.EqualError(t, f1.RemoveRows("SheetN", 1, 1), "sheet SheetN does not exist")
+	assert.NoError(t, f1.Close())
+	assert.NoError(t, f2.Close())
+}
+
+func TestMoveRows(t *testing.T) {
+	f := NewFile()
+	_, err := f.NewSheet("Sheet2")
+	assert.NoError(t, err)
+	for row := 1; row <= 10; row++ {
+		assert.NoError(t, f.SetCellInt("Sheet1", fmt.Sprintf("A%d", row), row))
+	}
+	assert.NoError(t, f.SetRowHeight("Sheet1", 2, 30))
+	assert.NoError(t, f.SetCellFormula("Sheet1", "B1", "SUM(A2:A4)+A6"))
+	assert.NoError(t, f.SetCellFormula("Sheet2", "A1", "Sheet1!A3"))
+	formulaType, ref := STCellFormulaTypeShared, "F1:F10"
+	assert.NoError(t, f.SetCellFormula("Sheet1", "F1", "A1*2", FormulaOpts{Type: &formulaType, Ref: &ref}))
+	assert.NoError(t, f.MergeCell("Sheet1", "C2", "D3"))
+	assert.NoError(t, f.SetCellHyperLink("Sheet1", "E2", "https://github.com/xuri/excelize", "External"))
+	assert.NoError(t, f.AddComment("Sheet1", Comment{Cell: "A4", Author: "Excelize", Text: "comment"}))
+	assert.NoError(t, f.SetDefinedName(&DefinedName{Name: "Block", RefersTo: "Sheet1!$A$2:$A$4"}))
+
+	assert.NoError(t, f.MoveRows("Sheet1", 2, 4, 8))
+	for cell, expected := range map[string]string{
+		"A1": "1", "A2": "5", "A7": "10", "A8": "2", "A10": "4",
+	} {
+		val, err := f.GetCellValue("Sheet1", cell)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, val, cell)
+	}
+	height, err := f.GetRowHeight("Sheet1", 8)
+	assert.NoError(t, err)
+	assert.Equal(t, 30.0, height)
+	for _, expected := range [][]string{
+		{"Sheet1", "B1", "SUM(A8:A10)+A3"}, {"Sheet2", "A1", "Sheet1!A9"},
+		{"Sheet1", "F1", "A1*2"}, {"Sheet1", "F2", "A2*2"}, {"Sheet1", "F8", "A8*2"},
+	} {
+		formula, err := f.GetCellFormula(expected[0], expected[1])
+		assert.NoError(t, err)
+		assert.Equal(t, expected[2], formula)
+	}
+	result, err := f.CalcCellValue("Sheet1", "B1")
+	assert.NoError(t, err)
+	assert.Equal(t, "15", result)
+	mergeCells, err := f.GetMergeCells("Sheet1")
+	assert.NoError(t, err)
+	assert.Len(t, mergeCells, 1)
+	assert.Equal(t, "C8", mergeCells[0].GetStartAxis())
+	assert.Equal(t, "D9", mergeCells[0].GetEndAxis())
+	link, target, err := f.GetCellHyperLink("Sheet1", "E8")
+	assert.NoError(t, err)
+	assert.True(t, link)
+	assert.Equal(t, "https://github.com/xuri/excelize", target)
+	comments, err := f.GetComments("Sheet1")
+	assert.NoError(t, err)
+	assert.Len(t, comments, 1)
+	assert.Equal(t, "A10", comments[0].Cell)
+	assert.Equal(t, "Sheet1!$A$8:$A$10", f.GetDefinedName()[0].RefersTo)
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestMoveRows.xlsx")))
+
+	// Test move rows upward back to the original position
+	assert.NoError(t, f.MoveRows("Sheet1", 8, 10, 2))
+	cols, err := f.GetCols("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"1", "2", "3", "4", "5", "6", "7", "8", "9", "10"}, cols[0])
+	formula, err := f.GetCellFormula("Sheet1", "B1")
+	assert.NoError(t, err)
+	assert.Equal(t, "SUM(A2:A4)+A6", formula)
+	// Test move rows to the same position
+	assert.NoError(t, f.MoveRows("Sheet1", 2, 4, 2))
+	// Test move rows with cutting the part of the merged cells
+	assert.Equal(t, ErrMoveMergedCells, f.MoveRows("Sheet1", 3, 3, 1))
+	assert.Equal(t, ErrMoveMergedCells, f.MoveRows("Sheet1", 1, 1, 2))
+	// Test move rows with invalid parameters
+	assert.Equal(t, newInvalidRowNumberError(0), f.MoveRows("Sheet1", 0, 1, 2))
+	assert.Equal(t, newInvalidRowNumberError(0), f.MoveRows("Sheet1", 1, 1, 0))
+	assert.Equal(t, ErrParameterInvalid, f.MoveRows("Sheet1", 2, 1, 3))
+	assert.Equal(t, ErrMaxRows, f.MoveRows("Sheet1", 1, TotalRows+1, 2))
+	assert.Equal(t, ErrMaxRows, f.MoveRows("Sheet1", 1, 2, TotalRows))
+	// Test move rows on not exists worksheet
+	assert.EqualError(t, f.MoveRows("SheetN", 1, 1, 2), "sheet SheetN does not exist")
+	// Test move rows with invalid merged cell reference
+	ws, ok := f.Sheet.Load("xl/worksheets/sheet1.xml")
+	assert.True(t, ok)
+	ws.(*xlsxWorksheet).MergeCells = &xlsxMergeCells{Cells: []*xlsxMergeCell{{Ref: "A:A"}}}
+	assert.Equal(t, newCellNameToCoordinatesError("A", newInvalidCellNameError("A")), f.MoveRows("Sheet1", 1, 1, 2))
+	assert.NoError(t, f.Close())
+
+	// Test move rows with unsupported charset comments
+	f = NewFile()
+	assert.NoError(t, f.AddComment("Sheet1", Comment{Cell: "A1", Author: "Excelize", Text: "comment"}))
+	f.Comments["xl/comments1.xml"] = nil
+	f.Pkg.Store("xl/comments1.xml", MacintoshCyrillicCharset)
+	assert.EqualError(t, f.MoveRows("Sheet1", 1, 1, 2), "XML syntax error on line 1: invalid UTF-8")
+}
+
 func TestInsertRows(t *testing.T) {
 	f := NewFile()
 	sheet1 := f.GetSheetName(0)
@@ -995,6 +1608,29 @@ func TestSetRowStyle(t *testing.T) {
 	assert.EqualError(t, f.SetRowStyle("Sheet1", 1, 1, cellStyleID), "XML syntax error on line 1: invalid UTF-8")
 }
 
+func TestGetRowStyle(t *testing.T) {
+	f := NewFile()
+	styleID, err := f.NewStyle(&Style{Fill: Fill{Type: "pattern", Color: []string{"63BE7B"}, Pattern: 1}})
+	assert.NoError(t, err)
+	assert.NoError(t, f.SetRowStyle("Sheet1", 2, 3, styleID))
+	for row, expected := range map[int]int{1: 0, 2: styleID, 3: styleID, 4: 0, 100: 0} {
+		rowStyleID, err := f.GetRowStyle("Sheet1", row)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, rowStyleID, row)
+	}
+	// Test get row style with invalid row number
+	_, err = f.GetRowStyle("Sheet1", 0)
+	assert.EqualError(t, err, newInvalidRowNumberError(0).Error())
+	_, err = f.GetRowStyle("Sheet1", TotalRows+1)
+	assert.EqualError(t, err, ErrMaxRows.Error())
+	// Test get row style on not exists worksheet
+	_, err = f.GetRowStyle("SheetN", 1)
+	assert.EqualError(t, err, "sheet SheetN does not exist")
+	// Test get row style with invalid sheet name
+	_, err = f.GetRowStyle("Sheet:1", 1)
+	assert.EqualError(t, err, ErrSheetNameInvalid.Error())
+}
+
 func TestNumberFormats(t *testing.T) {
 	f, err := OpenFile(filepath.Join("test", "Book1.xlsx"))
 	if !assert.NoError(t, err) {
@@ -1125,3 +1761,458 @@ func trimSliceSpace(s []string) []string {
 	}
 	return s
 }
+
+func TestRowsMalformedSheetData(t *testing.T) {
+	sheetData := xml.Header + `<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>` +
+		`<row r="2"><c r="A2" t="inlineStr"><is><t>A2</t></is></c><c r="C2" t="inlineStr"><is><t>C2</t></is></c><c r="B2" t="inlineStr"><is><t>B2</t></is></c></row>` +
+		`<row><c t="inlineStr"><is><t>A3</t></is></c><c r="XFE3" t="inlineStr"><is><t>XFE3</t></is></c><c t="inlineStr"><is><t>B3</t></is></c></row>` +
+		`<row r="1"><c r="A1" t="inlineStr"><is><t>A1</t></is></c><c r="A1" t="inlineStr"><is><t>A1-dup</t></is></c></row>` +
+		`<row r="2"><c r="D2" t="inlineStr"><is><t>D2</t></is></c></row>` +
+		`</sheetData></worksheet>`
+	expected := [][]string{{"A1-dup"}, {"A2", "B2", "C2", "D2"}, {"A3", "B3"}}
+	// Test get rows with the repaired worksheet
+	f := NewFile()
+	f.Sheet.Delete("xl/worksheets/sheet1.xml")
+	f.checked.Delete("xl/worksheets/sheet1.xml")
+	f.Pkg.Store("xl/worksheets/sheet1.xml", []byte(sheetData))
+	rows, err := f.GetRows("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, expected, rows)
+	// Test get cell value on the repaired worksheet
+	f = NewFile()
+	f.Sheet.Delete("xl/worksheets/sheet1.xml")
+	f.checked.Delete("xl/worksheets/sheet1.xml")
+	f.Pkg.Store("xl/worksheets/sheet1.xml", []byte(sheetData))
+	for cell, value := range map[string]string{"A1": "A1-dup", "C2": "C2", "D2": "D2", "B3": "B3"} {
+		val, err := f.GetCellValue("Sheet1", cell)
+		assert.NoError(t, err)
+		assert.Equal(t, value, val)
+	}
+	// Test rows iterator continue from the sorted copy of the worksheet without
+	// changing the workbook
+	f = NewFile()
+	f.Pkg.Store("xl/worksheets/sheet1.xml", []byte(sheetData))
+	f.Sheet.Delete("xl/worksheets/sheet1.xml")
+	f.checked.Delete("xl/worksheets/sheet1.xml")
+	iter, err := f.Rows("Sheet1")
+	assert.NoError(t, err)
+	var results [][]string
+	for iter.Next() {
+		row, err := iter.Columns()
+		assert.NoError(t, err)
+		results = append(results, row)
+	}
+	assert.NoError(t, iter.Error())
+	assert.False(t, iter.Next())
+	assert.NoError(t, iter.Close())
+	assert.Equal(t, [][]string{nil, {"A2", "B2", "C2"}, {"A3", "B3"}}, results)
+	content, ok := f.Pkg.Load("xl/worksheets/sheet1.xml")
+	assert.True(t, ok)
+	assert.Equal(t, sheetData, string(content.([]byte)))
+	// Test get rows with the out of order row in the middle of the worksheet
+	f = NewFile()
+	unorderedData := xml.Header + `<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>` +
+		`<row r="1"><c r="A1" t="inlineStr"><is><t>A1</t></is></c></row>` +
+		`<row r="3"><c r="A3" t="inlineStr"><is><t>A3</t></is></c></row>` +
+		`<row r="2"><c r="A2" t="inlineStr"><is><t>A2</t></is></c></row>` +
+		`<row r="4"><c r="A4" t="inlineStr"><is><t>A4</t></is></c></row>` +
+		`<row r="5"><c r="A5" t="inlineStr"><is><t>A5</t></is></c></row>` +
+		`</sheetData></worksheet>`
+	f.Pkg.Store("xl/worksheets/sheet1.xml", []byte(unorderedData))
+	f.Sheet.Delete("xl/worksheets/sheet1.xml")
+	f.checked.Delete("xl/worksheets/sheet1.xml")
+	for _, skip := range []bool{false, true} {
+		// Test the out of order row was found on parsing the current row or
+		// seeking the next row
+		iter, err = f.Rows("Sheet1")
+		assert.NoError(t, err)
+		results = nil
+		for iter.Next() {
+			if skip && len(results) == 2 {
+				results = append(results, nil)
+				continue
+			}
+			row, err := iter.Columns()
+			assert.NoError(t, err)
+			results = append(results, row)
+		}
+		assert.NoError(t, iter.Error())
+		assert.NoError(t, iter.Close())
+		expected := [][]string{{"A1"}, nil, {"A3"}, {"A4"}, {"A5"}}
+		if skip {
+			expected[2] = nil
+		}
+		assert.Equal(t, expected, results)
+	}
+	// Test rows iterator stop at the out of order row in strict mode
+	f.options.StrictSheetData = true
+	iter, err = f.Rows("Sheet1")
+	assert.NoError(t, err)
+	assert.True(t, iter.Next())
+	assert.True(t, iter.Next())
+	assert.True(t, iter.Next())
+	_, err = iter.Columns()
+	assert.NoError(t, err)
+	assert.False(t, iter.Next())
+	assert.EqualError(t, iter.Error(), newUnorderedRowError(2).Error())
+	assert.NoError(t, iter.Close())
+	f.options.StrictSheetData = false
+	rows, err = f.GetRows("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{{"A1"}, {"A2"}, {"A3"}, {"A4"}, {"A5"}}, rows)
+	content, ok = f.Pkg.Load("xl/worksheets/sheet1.xml")
+	assert.True(t, ok)
+	assert.Equal(t, unorderedData, string(content.([]byte)))
+	_, ok = f.Sheet.Load("xl/worksheets/sheet1.xml")
+	assert.False(t, ok)
+	// Test get rows with the out of order row in the system temporary directory
+	f = NewFile()
+	f.Pkg.Delete("xl/worksheets/sheet1.xml")
+	f.Sheet.Delete("xl/worksheets/sheet1.xml")
+	f.checked.Delete("xl/worksheets/sheet1.xml")
+	tempFile, err := os.CreateTemp(os.TempDir(), "excelize-")
+	assert.NoError(t, err)
+	_, err = tempFile.WriteString(unorderedData)
+	assert.NoError(t, err)
+	assert.NoError(t, tempFile.Close())
+	f.tempFiles.Store("xl/worksheets/sheet1.xml", tempFile.Name())
+	rows, err = f.GetRows("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{{"A1"}, {"A2"}, {"A3"}, {"A4"}, {"A5"}}, rows)
+	_, ok = f.Pkg.Load("xl/worksheets/sheet1.xml")
+	assert.False(t, ok)
+	assert.NoError(t, f.Close())
+	// Test get rows in strict mode
+	f = NewFile(Options{StrictSheetData: true})
+	f.Pkg.Store("xl/worksheets/sheet1.xml", []byte(sheetData))
+	f.Sheet.Delete("xl/worksheets/sheet1.xml")
+	_, err = f.GetRows("Sheet1")
+	assert.EqualError(t, err, newUnorderedCellError("B2").Error())
+	f.Pkg.Store("xl/worksheets/sheet1.xml", []byte(xml.Header+`<worksheet><sheetData><row><c t="inlineStr"><is><t>A1</t></is></c><c r="XFE1" t="inlineStr"><is><t>XFE1</t></is></c></row></sheetData></worksheet>`))
+	_, err = f.GetRows("Sheet1")
+	assert.EqualError(t, err, ErrColumnNumber.Error())
+	f.Pkg.Store("xl/worksheets/sheet1.xml", []byte(xml.Header+`<worksheet><sheetData><row r="2"><c r="A2" t="inlineStr"><is><t>A2</t></is></c></row><row r="1"/></sheetData></worksheet>`))
+	_, err = f.GetRows("Sheet1")
+	assert.EqualError(t, err, newUnorderedRowError(1).Error())
+	iter, err = f.Rows("Sheet1")
+	assert.NoError(t, err)
+	assert.True(t, iter.Next())
+	assert.True(t, iter.Next())
+	assert.False(t, iter.Next())
+	assert.EqualError(t, iter.Error(), newUnorderedRowError(1).Error())
+}
+
+func TestRowsSeekRow(t *testing.T) {
+	f := NewFile()
+	for row := 1; row <= 10; row++ {
+		if row == 5 || row == 7 {
+			continue
+		}
+		cell, err := CoordinatesToCellName(1, row)
+		assert.NoError(t, err)
+		assert.NoError(t, f.SetSheetRow("Sheet1", cell, &[]interface{}{row, row * 2}))
+	}
+	assert.NoError(t, f.SetRowHeight("Sheet1", 8, 30))
+	expected, err := f.GetRows("Sheet1")
+	assert.NoError(t, err)
+	for n := 1; n <= 12; n++ {
+		rows, err := f.Rows("Sheet1")
+		assert.NoError(t, err)
+		assert.NoError(t, rows.SeekRow(n))
+		var results [][]string
+		for rows.Next() {
+			if rows.seekRow == 8 {
+				assert.Equal(t, 30.0, rows.GetRowOpts().Height)
+			}
+			columns, err := rows.Columns()
+			assert.NoError(t, err)
+			results = append(results, columns)
+		}
+		if n <= len(expected) {
+			assert.Equal(t, expected[n-1:], results, n)
+		} else {
+			assert.Empty(t, results, n)
+		}
+		assert.NoError(t, rows.Close())
+	}
+	// Test seek rows after iterating some rows
+	rows, err := f.Rows("Sheet1")
+	assert.NoError(t, err)
+	for i := 0; i < 2; i++ {
+		assert.True(t, rows.Next())
+	}
+	assert.NoError(t, rows.SeekRow(4))
+	assert.NoError(t, rows.SeekRow(6))
+	assert.True(t, rows.Next())
+	columns, err := rows.Columns()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"6", "12"}, columns)
+	// Test seek rows with invalid row number
+	assert.EqualError(t, rows.SeekRow(6), "cannot seek to row 6 before the current row")
+	assert.EqualError(t, rows.SeekRow(0), newInvalidRowNumberError(0).Error())
+	assert.EqualError(t, rows.SeekRow(TotalRows+1), newInvalidRowNumberError(TotalRows+1).Error())
+	assert.NoError(t, rows.Close())
+	// Test seek rows with invalid worksheet XML
+	f.Sheet.Delete("xl/worksheets/sheet1.xml")
+	f.Pkg.Store("xl/worksheets/sheet1.xml", []byte(`<worksheet><sheetData><row r="1"><c r="A1"></row></sheetData></worksheet>`))
+	f.checked.Delete("xl/worksheets/sheet1.xml")
+	rows, err = f.Rows("Sheet1")
+	assert.NoError(t, err)
+	assert.Error(t, rows.SeekRow(3))
+	assert.NoError(t, rows.Close())
+}
+
+func TestRowsCells(t *testing.T) {
+	f := NewFile()
+	dateStyle, err := f.NewStyle(&Style{NumFmt: 14})
+	assert.NoError(t, err)
+	numStyle, err := f.NewStyle(&Style{NumFmt: 2})
+	assert.NoError(t, err)
+	date := time.Date(2023, 5, 1, 0, 0, 0, 0, time.UTC)
+	assert.NoError(t, f.SetSheetRow("Sheet1", "A1", &[]interface{}{1.5, true, "text", date}))
+	assert.NoError(t, f.SetCellStyle("Sheet1", "A1", "A1", numStyle))
+	assert.NoError(t, f.SetCellStyle("Sheet1", "D1", "D1", dateStyle))
+	assert.NoError(t, f.SetCellFormula("Sheet1", "B2", "A1*2"))
+	assert.NoError(t, f.SetCellValue("Sheet1", "D2", "=1/0"))
+	f.Sheet.Range(func(_, ws interface{}) bool {
+		ws.(*xlsxWorksheet).SheetData.Row[1].C[3] = xlsxC{R: "D2", T: "e", V: "#DIV/0!", F: &xlsxF{Content: "1/0"}}
+		return true
+	})
+	ref, shared := "C3:C4", STCellFormulaTypeShared
+	assert.NoError(t, f.SetCellFormula("Sheet1", "C3", "A3+B3", FormulaOpts{Ref: &ref, Type: &shared}))
+	rows, err := f.Rows("Sheet1")
+	assert.NoError(t, err)
+	var results [][]RowCell
+	for rows.Next() {
+		cells, err := rows.Cells()
+		assert.NoError(t, err)
+		results = append(results, cells)
+	}
+	assert.NoError(t, rows.Close())
+	assert.Len(t, results, 4)
+	assert.Equal(t, []RowCell{
+		{StyleID: numStyle, Value: 1.5, RawValue: "1.5", Type: CellTypeNumber},
+		{Value: true, RawValue: "1", Type: CellTypeBool},
+		{Value: "text", RawValue: "text", Type: CellTypeSharedString},
+		{StyleID: dateStyle, Value: date, RawValue: "45047", Type: CellTypeDate},
+	}, results[0])
+	assert.Equal(t, []RowCell{
+		{}, {Formula: "A1*2", Value: "", Type: CellTypeFormula},
+		{}, {Formula: "1/0", Value: "#DIV/0!", RawValue: "#DIV/0!", Type: CellTypeError},
+	}, results[1])
+	assert.Equal(t, "A3+B3", results[2][2].Formula)
+	assert.Equal(t, &SharedFormula{Index: 0, Ref: "C3:C4"}, results[2][2].SharedFormula)
+	assert.Equal(t, "A4+B4", results[3][2].Formula)
+	assert.Equal(t, &SharedFormula{Index: 0}, results[3][2].SharedFormula)
+	// Test get typed cells with unsupported charset shared strings table
+	f.SharedStrings = nil
+	f.Pkg.Store(defaultXMLPathSharedStrings, MacintoshCyrillicCharset)
+	rows, err = f.Rows("Sheet1")
+	assert.NoError(t, err)
+	assert.True(t, rows.Next())
+	_, err = rows.Cells()
+	assert.EqualError(t, err, "XML syntax error on line 1: invalid UTF-8")
+	assert.NoError(t, rows.Close())
+	assert.NoError(t, f.Close())
+}
+
+func TestSetRowStruct(t *testing.T) {
+	type person struct {
+		Name     string    `xlsx:"Name"`
+		Age      *int      `xlsx:"Age"`
+		Birthday time.Time `xlsx:"Birthday,numFmt=14"`
+		Score    float64
+		Note     string `xlsx:"-"`
+		note     string
+	}
+	f := NewFile()
+	age, birthday := 30, time.Date(1994, 5, 1, 0, 0, 0, 0, time.UTC)
+	assert.NoError(t, f.SetRowStruct("Sheet1", "B2", []person{
+		{Name: "Alice", Age: &age, Birthday: birthday, Score: 98.5, Note: "note", note: "note"},
+		{Name: "Bob", Birthday: birthday},
+	}))
+	assert.NoError(t, f.SetRowStruct("Sheet1", "B5", &person{Name: "Carol", Age: &age}))
+	rows, err := f.GetRows("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{
+		nil,
+		{"", "Name", "Age", "Birthday", "Score"},
+		{"", "Alice", "30", "05-01-94", "98.5"},
+		{"", "Bob", "", "05-01-94", "0"},
+		{"", "Carol", "30", "0001-01-01T00:00:00Z", "0"},
+	}, rows)
+	// Test write struct with invalid parameters
+	assert.Equal(t, ErrParameterInvalid, f.SetRowStruct("Sheet1", "A1", nil))
+	assert.Equal(t, ErrParameterInvalid, f.SetRowStruct("Sheet1", "A1", []int{1}))
+	// Test write struct with invalid cell reference
+	assert.Equal(t, newCellNameToCoordinatesError("A", newInvalidCellNameError("A")), f.SetRowStruct("Sheet1", "A", person{}))
+	assert.Equal(t, ErrColumnNumber, f.SetRowStruct("Sheet1", "XFD1", person{}))
+	// Test write struct on not exists worksheet
+	assert.EqualError(t, f.SetRowStruct("SheetN", "A1", []person{}), "sheet SheetN does not exist")
+	assert.EqualError(t, f.SetRowStruct("SheetN", "A1", person{}), "sheet SheetN does not exist")
+	assert.NoError(t, f.Close())
+}
+
+func TestRowsScan(t *testing.T) {
+	type person struct {
+		Name     string     `xlsx:"Name"`
+		Age      uint8      `xlsx:"Age"`
+		Birthday *time.Time `xlsx:"Birthday"`
+		Score    float32    `xlsx:"Score"`
+		Member   bool       `xlsx:"Member"`
+		Level    int        `xlsx:"Level"`
+		Note     string     `xlsx:"-"`
+	}
+	f := NewFile()
+	birthday := time.Date(1994, 5, 1, 0, 0, 0, 0, time.UTC)
+	for cell, row := range map[string][]interface{}{
+		"B1": {"Level", "Name", "Age", "Birthday", "Score", "Member"},
+		"B2": {1, "Alice", 30, birthday, 98.5, true},
+		"B3": {nil, 100, nil, 35000, "9", "TRUE"},
+		"B4": {nil, true, nil, birthday.Format(time.RFC3339)},
+	} {
+		assert.NoError(t, f.SetSheetRow("Sheet1", cell, &row))
+	}
+	rows, err := f.Rows("Sheet1")
+	assert.NoError(t, err)
+	var results []person
+	for rows.Next() {
+		p := person{Name: "-", Note: "note"}
+		assert.NoError(t, rows.Scan(&p))
+		results = append(results, p)
+	}
+	assert.NoError(t, rows.Close())
+	date := time.Date(1995, 10, 28, 0, 0, 0, 0, time.UTC)
+	assert.Equal(t, []person{
+		{Name: "Alice", Age: 30, Birthday: &birthday, Score: 98.5, Member: true, Level: 1, Note: "note"},
+		{Name: "100", Birthday: &date, Score: 9, Member: true, Note: "note"},
+		{Name: "TRUE", Birthday: &birthday, Note: "note"},
+	}, results)
+	// Test scan rows without header row
+	type record struct {
+		ID    int
+		Value string
+	}
+	_, err = f.NewSheet("Sheet2")
+	assert.NoError(t, err)
+	assert.NoError(t, f.SetSheetRow("Sheet2", "A1", &[]interface{}{1, "a"}))
+	rows, err = f.Rows("Sheet2")
+	assert.NoError(t, err)
+	assert.True(t, rows.Next())
+	var r record
+	assert.NoError(t, rows.Scan(&r))
+	assert.Equal(t, record{ID: 1, Value: "a"}, r)
+	assert.NoError(t, rows.Close())
+	// Test scan rows with the header row only
+	assert.NoError(t, f.SetSheetRow("Sheet2", "A1", &[]interface{}{"ID", "Value"}))
+	rows, err = f.Rows("Sheet2")
+	assert.NoError(t, err)
+	assert.True(t, rows.Next())
+	assert.Equal(t, ErrScanNoDataRow, rows.Scan(&r))
+	assert.NoError(t, rows.Close())
+	// Test scan rows with invalid parameters
+	assert.Equal(t, ErrParameterInvalid, rows.Scan(nil))
+	assert.Equal(t, ErrParameterInvalid, rows.Scan(r))
+	// Test scan cell values which can't be converted to the type of fields
+	for _, value := range []interface{}{"a", -1, 256} {
+		assert.NoError(t, f.SetSheetRow("Sheet2", "A2", &[]interface{}{value, value}))
+		var v struct {
+			ID    uint8
+			Value string
+		}
+		rows, err = f.Rows("Sheet2")
+		assert.NoError(t, err)
+		assert.True(t, rows.Next())
+		assert.Equal(t, newScanFieldError(fmt.Sprint(value), "A2", "ID"), rows.Scan(&v))
+		assert.NoError(t, rows.Close())
+	}
+	for _, dest := range []interface{}{
+		&struct{ ID int8 }{}, &struct{ ID bool }{}, &struct{ ID float64 }{},
+		&struct{ ID time.Time }{}, &struct{ ID []int }{},
+	} {
+		assert.NoError(t, f.SetSheetRow("Sheet2", "A2", &[]interface{}{"a"}))
+		if _, ok := dest.(*struct{ ID int8 }); ok {
+			assert.NoError(t, f.SetSheetRow("Sheet2", "A2", &[]interface{}{128}))
+		}
+		rows, err = f.Rows("Sheet2")
+		assert.NoError(t, err)
+		assert.True(t, rows.Next())
+		assert.Error(t, rows.Scan(dest))
+		assert.NoError(t, rows.Close())
+	}
+	// Test scan rows with unsupported charset shared strings table
+	rows, err = f.Rows("Sheet2")
+	assert.NoError(t, err)
+	assert.True(t, rows.Next())
+	f.SharedStrings = nil
+	f.Pkg.Store(defaultXMLPathSharedStrings, MacintoshCyrillicCharset)
+	assert.EqualError(t, rows.Scan(&r), "XML syntax error on line 1: invalid UTF-8")
+	assert.NoError(t, rows.Close())
+	assert.NoError(t, f.Close())
+}
+
+func TestCellRecords(t *testing.T) {
+	f := NewFile()
+	_, err := f.NewSheet("Sheet2")
+	assert.NoError(t, err)
+	styleID, err := f.NewStyle(&Style{NumFmt: 2})
+	assert.NoError(t, err)
+	assert.NoError(t, f.SetCellValue("Sheet1", "A1", "Title"))
+	assert.NoError(t, f.MergeCell("Sheet1", "C1", "A2"))
+	assert.NoError(t, f.SetCellValue("Sheet1", "B3", 1.5))
+	assert.NoError(t, f.SetCellStyle("Sheet1", "B3", "B3", styleID))
+	assert.NoError(t, f.SetCellFormula("Sheet1", "C3", "B3*2"))
+	assert.NoError(t, f.SetCellValue("Sheet1", "D5", true))
+	assert.NoError(t, f.SetCellValue("Sheet2", "B2", "Data"))
+	records, err := f.CellRecords()
+	assert.NoError(t, err)
+	var result []CellRecord
+	for records.Next() {
+		result = append(result, records.Record())
+	}
+	assert.NoError(t, records.Error())
+	assert.NoError(t, records.Close())
+	assert.Equal(t, []CellRecord{
+		{Sheet: "Sheet1", Cell: "A1", Value: "Title", RawValue: "Title", Type: CellTypeSharedString, IsMergeAnchor: true},
+		{Sheet: "Sheet1", Cell: "B3", Value: "1.50", RawValue: "1.5", Type: CellTypeNumber, StyleID: styleID},
+		{Sheet: "Sheet1", Cell: "C3", Type: CellTypeFormula, Formula: "B3*2", IsFormula: true},
+		{Sheet: "Sheet1", Cell: "D5", Value: "TRUE", RawValue: "1", Type: CellTypeBool},
+		{Sheet: "Sheet2", Cell: "B2", Value: "Data", RawValue: "Data", Type: CellTypeSharedString},
+	}, result)
+
+	// Test get cell records by given worksheet name and close before the end
+	records, err = f.CellRecords("Sheet2")
+	assert.NoError(t, err)
+	assert.True(t, records.Next())
+	assert.Equal(t, "B2", records.Record().Cell)
+	assert.NoError(t, records.Close())
+	assert.NoError(t, records.Close())
+	// Test get cell records with not exist worksheet
+	_, err = f.CellRecords("SheetN")
+	assert.EqualError(t, err, "sheet SheetN does not exist")
+	// Test get cell records with invalid sheet name
+	_, err = f.CellRecords("Sheet:1")
+	assert.Equal(t, ErrSheetNameInvalid, err)
+	// Test get cell records with invalid merged range reference
+	ws, ok := f.Sheet.Load("xl/worksheets/sheet1.xml")
+	assert.True(t, ok)
+	ws.(*xlsxWorksheet).MergeCells = &xlsxMergeCells{Cells: []*xlsxMergeCell{{Ref: "A1"}}}
+	records, err = f.CellRecords("Sheet1")
+	assert.NoError(t, err)
+	assert.False(t, records.Next())
+	assert.Equal(t, ErrParameterInvalid, records.Error())
+	assert.NoError(t, records.Close())
+	assert.NoError(t, f.Close())
+
+	// Test get cell records with unsupported charset shared strings table
+	f = NewFile()
+	assert.NoError(t, f.SetCellValue("Sheet1", "A1", "Title"))
+	records, err = f.CellRecords("Sheet1")
+	assert.NoError(t, err)
+	f.SharedStrings = nil
+	f.Pkg.Store(defaultXMLPathSharedStrings, MacintoshCyrillicCharset)
+	assert.False(t, records.Next())
+	assert.EqualError(t, records.Error(), "XML syntax error on line 1: invalid UTF-8")
+	assert.NoError(t, records.Close())
+	assert.NoError(t, f.Close())
+}
@@ -12,6 +12,9 @@
 package excelize_ch
 
 import (
+	"encoding/xml"
+	"fmt"
+	"io"
 	"strconv"
 	"strings"
 )
@@ -46,9 +49,38 @@ func parseShapeOptions(opts *Shape) (*Shape, error) {
 	if opts.Line.Width == nil {
 		opts.Line.Width = float64Ptr(defaultShapeLineWidth)
 	}
+	if _, ok := shapeVerticalAnchors[opts.TextBox.Vertical]; !ok {
+		return nil, ErrParameterInvalid
+	}
+	if _, ok := shapeAutoFitTypes[opts.TextBox.AutoFit]; !ok {
+		return nil, ErrParameterInvalid
+	}
+	for _, p := range opts.TextBox.Paragraphs {
+		if _, ok := shapeHorizontalAlignments[p.Horizontal]; !ok {
+			return nil, ErrParameterInvalid
+		}
+		if _, _, err := parseShapeEquation(p.Equation); err != nil {
+			return nil, err
+		}
+	}
 	return opts, nil
 }
 
+// shapeHorizontalAlignments defined the paragraph alignment types of the text
+// box in the shape.
+var shapeHorizontalAlignments = map[string]string{
+	"": "", "left": "l", "center": "ctr", "right": "r", "justify": "just", "distributed": "dist",
+}
+
+// shapeVerticalAnchors defined the vertical anchoring types of the text box in
+// the shape.
+var shapeVerticalAnchors = map[string]string{
+	"": "t", "top": "t", "center": "ctr", "bottom": "b",
+}
+
+// shapeAutoFitTypes defined the autofit types of the text box in the shape.
+var shapeAutoFitTypes = map[string]bool{"": true, "none": true, "shrink": true, "resize": true}
+
 // AddShape provides the method to add shape in a sheet by given worksheet
 // name and shape format set (such as offset, scale, aspect ratio setting and
 // print settings). For example, add text box (rect shape) in Sheet1:
@@ -78,6 +110,67 @@ func parseShapeOptions(opts *Shape) (*Shape, error) {
 //	    },
 //	)
 //
+// Use the TextBox field to add the paragraphs with multiple rich text runs and
+// set the text box settings of the shape. The Horizontal field of the
+// paragraph specifies the alignment of the paragraph: left, center, right,
+// justify or distributed. The Vertical field specifies the vertical anchoring
+// of the text: top (default), center or bottom. The AutoFit field specifies
+// the autofit type of the text box: none, shrink (shrink text on overflow) or
+// resize (resize shape to fit text). The Margins field specifies the internal
+// margins of the text box in points. For example, add a callout with two
+// paragraphs, the text will be centered in the shape, and the shape will be
+// resized to fit the text:
+//
+//	err := f.AddShape("Sheet1",
+//	    &excelize.Shape{
+//	        Cell: "G6",
+//	        Type: "wedgeRectCallout",
+//	        TextBox: excelize.ShapeTextBox{
+//	            Paragraphs: []excelize.ShapeParagraph{
+//	                {
+//	                    Horizontal: "center",
+//	                    Runs: []excelize.RichTextRun{
+//	                        {Text: "Total: ", Font: &excelize.Font{Bold: true}},
+//	                        {Text: "1,024", Font: &excelize.Font{Color: "C00000"}},
+//	                    },
+//	                },
+//	                {Runs: []excelize.RichTextRun{{Text: "Updated daily"}}},
+//	            },
+//	            Vertical: "center",
+//	            AutoFit:  "resize",
+//	            WrapText: true,
+//	            Margins:  &excelize.ShapeMargins{Left: 4, Right: 4, Top: 2, Bottom: 2},
+//	        },
+//	    },
+//	)
+//
+// The Equation field of the paragraph specifies the mathematical equation in
+// Office Math Markup Language (OMML) with the oMath or oMathPara root element,
+// the "m" prefix is bound to the OMML namespace, and the equation will be placed
+// before the text runs of the paragraph. The shape with equations will be added
+// with the plain text of the equations as the fallback shape for the
+// applications which don't support equations. The symbols, such as Greek
+// letters and mathematical operators, could be inserted as Unicode characters
+// in the equations and text runs. For example, add a text box with the
+// equation E=mc²:
+//
+//	err := f.AddShape("Sheet1",
+//	    &excelize.Shape{
+//	        Cell: "G6",
+//	        Type: "rect",
+//	        TextBox: excelize.ShapeTextBox{
+//	            Paragraphs: []excelize.ShapeParagraph{
+//	                {
+//	                    Horizontal: "center",
+//	                    Equation: `<m:oMath><m:r><m:t>E=m</m:t></m:r><m:sSup>` +
+//	                        `<m:e><m:r><m:t>c</m:t></m:r></m:e>` +
+//	                        `<m:sup><m:r><m:t>2</m:t></m:r></m:sup></m:sSup></m:oMath>`,
+//	                },
+//	            },
+//	        },
+//	    },
+//	)
+//
 // The following shows the type of shape supported by excelize:
 //
 //	accentBorderCallout1 (Callout 1 with Border and Accent Shape)
@@ -289,14 +382,29 @@ func parseShapeOptions(opts *Shape) (*Shape, error) {
 //	wavyHeavy
 //	wavyDbl
 func (f *File) AddShape(sheet string, opts *Shape) error {
+	_, err := f.AddShapeObject(sheet, opts)
+	return err
+}
+
+// AddShapeObject provides the method to add shape in a sheet by given
+// worksheet name and shape format set as the AddShape function, and returns
+// the handle of the shape, which could be used with the DeleteDrawingObject,
+// MoveDrawingObject and UpdateDrawingObject functions. For example:
+//
+//	obj, err := f.AddShapeObject("Sheet1", &excelize.Shape{Cell: "G6", Type: "rect"})
+func (f *File) AddShapeObject(sheet string, opts *Shape) (DrawingObject, error) {
+	obj := DrawingObject{Sheet: sheet}
 	options, err := parseShapeOptions(opts)
 	if err != nil {
-		return err
+		return obj, err
 	}
 	// Read sheet data
 	ws, err := f.workSheetReader(sheet)
 	if err != nil {
-		return err
+		return obj, err
+	}
+	if err = f.checkObjectsProtection(ws); err != nil {
+		return obj, err
 	}
 	// Add first shape for given sheet, create xl/drawings/ and xl/drawings/_rels/ folder.
 	drawingID := f.countDrawings() + 1
@@ -316,10 +424,11 @@ func (f *File) AddShape(sheet string, opts *Shape) error {
 		f.addSheetDrawing(sheet, rID)
 		f.addSheetNameSpace(sheet, SourceRelationship)
 	}
-	if err = f.addDrawingShape(sheet, drawingXML, opts.Cell, options); err != nil {
-		return err
+	if obj.ID, err = f.addDrawingShape(sheet, drawingXML, opts.Cell, options); err != nil {
+		return obj, err
 	}
-	return f.addContentTypePart(drawingID, "drawings")
+	obj.Name = "Shape " + strconv.Itoa(obj.ID)
+	return obj, f.addContentTypePart(drawingID, "drawings")
 }
 
 // twoCellAnchorShape create a two cell anchor shape size placeholder for a
@@ -354,12 +463,12 @@ func (f *File) twoCellAnchorShape(sheet, drawingXML, cell string, width, height
 }
 
 // addDrawingShape provides a function to add preset geometry by given sheet,
-// drawingXML and format sets.
-func (f *File) addDrawingShape(sheet, drawingXML, cell string, opts *Shape) error {
+// drawingXML and format sets, and returns the ID of the shape.
+func (f *File) addDrawingShape(sheet, drawingXML, cell string, opts *Shape) (int, error) {
 	content, twoCellAnchor, cNvPrID, err := f.twoCellAnchorShape(
 		sheet, drawingXML, cell, opts.Width, opts.Height, opts.Format)
 	if err != nil {
-		return err
+		return cNvPrID, err
 	}
 	var solidColor string
 	if len(opts.Fill.Color) == 1 {
@@ -398,7 +507,7 @@ func (f *File) addDrawingShape(sheet, drawingXML, cell string, opts *Shape) erro
 				HorzOverflow: "clip",
 				Wrap:         "none",
 				RtlCol:       false,
-				Anchor:       "t",
+				Anchor:       shapeVerticalAnchors[opts.TextBox.Vertical],
 			},
 		},
 	}
@@ -407,11 +516,12 @@ func (f *File) addDrawingShape(sheet, drawingXML, cell string, opts *Shape) erro
 			W: f.ptToEMUs(*opts.Line.Width),
 		}
 	}
+	setShapeTextBodyPr(shape.TxBody.BodyPr, &opts.TextBox)
 	defaultFont, err := f.GetDefaultFont()
 	if err != nil {
-		return err
+		return cNvPrID, err
 	}
-	if len(opts.Paragraph) < 1 {
+	if len(opts.Paragraph) < 1 && len(opts.TextBox.Paragraphs) < 1 {
 		opts.Paragraph = []RichTextRun{
 			{
 				Font: &Font{
@@ -426,54 +536,172 @@ func (f *File) addDrawingShape(sheet, drawingXML, cell string, opts *Shape) erro
 			},
 		}
 	}
-	for _, p := range opts.Paragraph {
-		u := "none"
-		font := &Font{}
-		if p.Font != nil {
-			font = p.Font
-		}
-		if idx := inStrSlice(supportedDrawingUnderlineTypes, font.Underline, true); idx != -1 {
-			u = supportedDrawingUnderlineTypes[idx]
-		}
-		text := p.Text
-		if text == "" {
-			text = " "
+	paragraphs := make([]ShapeParagraph, 0, len(opts.Paragraph)+len(opts.TextBox.Paragraphs))
+	for _, run := range opts.Paragraph {
+		paragraphs = append(paragraphs, ShapeParagraph{Runs: []RichTextRun{run}})
+	}
+	var (
+		fallbackText []*aP
+		hasEquation  bool
+	)
+	for _, p := range append(paragraphs, opts.TextBox.Paragraphs...) {
+		paragraph, fallback := &aP{EndParaRPr: &aEndParaRPr{Lang: "en-US"}}, &aP{EndParaRPr: &aEndParaRPr{Lang: "en-US"}}
+		if p.Horizontal != "" {
+			paragraph.PPr = &aPPr{Algn: shapeHorizontalAlignments[p.Horizontal]}
+			fallback.PPr = &aPPr{Algn: shapeHorizontalAlignments[p.Horizontal]}
 		}
-		paragraph := &aP{
-			R: &aR{
-				RPr: aRPr{
-					I:       font.Italic,
-					B:       font.Bold,
-					Lang:    "en-US",
-					AltLang: "en-US",
-					U:       u,
-					Sz:      font.Size * 100,
-					Latin:   &xlsxCTTextFont{Typeface: font.Family},
-				},
-				T: text,
-			},
-			EndParaRPr: &aEndParaRPr{
-				Lang: "en-US",
-			},
+		if p.Equation != "" {
+			hasEquation = true
+			equation, text, _ := parseShapeEquation(p.Equation)
+			paragraph.Math = &aMath{XMLNSM: NameSpaceOfficeMath.Value, Content: equation}
+			fallback.R = append(fallback.R, newShapeTextRun(RichTextRun{Text: text, Font: &Font{Family: "Cambria Math"}}))
 		}
-		srgbClr := strings.ReplaceAll(strings.ToUpper(font.Color), "#", "")
-		if len(srgbClr) == 6 {
-			paragraph.R.RPr.SolidFill = &aSolidFill{
-				SrgbClr: &attrValString{
-					Val: stringPtr(srgbClr),
-				},
-			}
+		for _, run := range p.Runs {
+			paragraph.R = append(paragraph.R, newShapeTextRun(run))
+			fallback.R = append(fallback.R, newShapeTextRun(run))
 		}
 		shape.TxBody.P = append(shape.TxBody.P, paragraph)
+		fallbackText = append(fallbackText, fallback)
 	}
-	twoCellAnchor.Sp = &shape
 	twoCellAnchor.ClientData = &xdrClientData{
 		FLocksWithSheet:  *opts.Format.Locked,
 		FPrintsWithSheet: *opts.Format.PrintObject,
 	}
+	if !hasEquation {
+		twoCellAnchor.Sp = &shape
+		content.TwoCellAnchor = append(content.TwoCellAnchor, twoCellAnchor)
+		f.Drawings.Store(drawingXML, content)
+		return cNvPrID, err
+	}
+	// The equation requires the drawing 2010 extensions, add the shape with
+	// the plain text of the equation as the fallback
+	graphic, _ := xml.Marshal(shape)
+	shape.TxBody = &xdrTxBody{BodyPr: shape.TxBody.BodyPr, P: fallbackText}
+	fallbackShape, _ := xml.Marshal(shape)
+	choiceBytes, _ := xml.Marshal(xlsxChoice{
+		XMLNSA14: NameSpaceDrawingMLA14.Value,
+		Requires: NameSpaceDrawingMLA14.Name.Local,
+		Content:  string(graphic),
+	})
+	fallbackBytes, _ := xml.Marshal(xlsxFallback{Content: string(fallbackShape)})
+	twoCellAnchor.AlternateContent = append(twoCellAnchor.AlternateContent, &xlsxAlternateContent{
+		XMLNSMC: SourceRelationshipCompatibility.Value,
+		Content: string(choiceBytes) + string(fallbackBytes),
+	})
 	content.TwoCellAnchor = append(content.TwoCellAnchor, twoCellAnchor)
 	f.Drawings.Store(drawingXML, content)
-	return err
+	return cNvPrID, err
+}
+
+// parseShapeEquation provides a function to check the equation in Office Math
+// Markup Language (OMML), and returns the equation wrapped by the math
+// paragraph and the plain text of the equation.
+func parseShapeEquation(equation string) (string, string, error) {
+	if equation == "" {
+		return equation, "", nil
+	}
+	var (
+		text                 strings.Builder
+		depth, roots         int
+		inText, hasParagraph bool
+	)
+	d := xml.NewDecoder(strings.NewReader(fmt.Sprintf("<m:m xmlns:m=\"%s\">%s</m:m>", NameSpaceOfficeMath.Value, equation)))
+	for {
+		token, err := d.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return equation, "", ErrShapeEquation
+		}
+		switch element := token.(type) {
+		case xml.StartElement:
+			if depth++; depth == 2 {
+				if element.Name.Space != NameSpaceOfficeMath.Value ||
+					(element.Name.Local != "oMath" && element.Name.Local != "oMathPara") {
+					return equation, "", ErrShapeEquation
+				}
+				roots++
+				hasParagraph = element.Name.Local == "oMathPara"
+			}
+			inText = element.Name.Space == NameSpaceOfficeMath.Value && element.Name.Local == "t"
+		case xml.EndElement:
+			depth--
+			inText = false
+		case xml.CharData:
+			if inText {
+				text.Write(element)
+			}
+			if depth == 1 && strings.TrimSpace(string(element)) != "" {
+				return equation, "", ErrShapeEquation
+			}
+		}
+	}
+	if roots != 1 {
+		return equation, "", ErrShapeEquation
+	}
+	if !hasParagraph {
+		equation = "<m:oMathPara>" + equation + "</m:oMathPara>"
+	}
+	return equation, text.String(), nil
+}
+
+// setShapeTextBodyPr provides a function to set the autofit, text wrapping
+// and internal margins of the text body properties by given text box settings.
+func setShapeTextBodyPr(bodyPr *aBodyPr, textBox *ShapeTextBox) {
+	switch textBox.AutoFit {
+	case "none":
+		bodyPr.NoAutofit = stringPtr("")
+	case "shrink":
+		bodyPr.NormAutofit = stringPtr("")
+	case "resize":
+		bodyPr.SpAutoFit = stringPtr("")
+	}
+	if textBox.WrapText {
+		bodyPr.Wrap = "square"
+	}
+	if margins := textBox.Margins; margins != nil {
+		bodyPr.LIns, bodyPr.RIns = intPtr(int(margins.Left*12700)), intPtr(int(margins.Right*12700))
+		bodyPr.TIns, bodyPr.BIns = intPtr(int(margins.Top*12700)), intPtr(int(margins.Bottom*12700))
+	}
+}
+
+// newShapeTextRun provides a function to create the text run of the shape by
+// given rich text run settings.
+func newShapeTextRun(run RichTextRun) *aR {
+	u := "none"
+	font := &Font{}
+	if run.Font != nil {
+		font = run.Font
+	}
+	if idx := inStrSlice(supportedDrawingUnderlineTypes, font.Underline, true); idx != -1 {
+		u = supportedDrawingUnderlineTypes[idx]
+	}
+	text := run.Text
+	if text == "" {
+		text = " "
+	}
+	r := &aR{
+		RPr: aRPr{
+			I:       font.Italic,
+			B:       font.Bold,
+			Lang:    "en-US",
+			AltLang: "en-US",
+			U:       u,
+			Sz:      font.Size * 100,
+			Latin:   &xlsxCTTextFont{Typeface: font.Family},
+		},
+		T: text,
+	}
+	srgbClr := strings.ReplaceAll(strings.ToUpper(font.Color), "#", "")
+	if len(srgbClr) == 6 {
+		r.RPr.SolidFill = &aSolidFill{
+			SrgbClr: &attrValString{
+				Val: stringPtr(srgbClr),
+			},
+		}
+	}
+	return r
 }
 
 // setShapeRef provides a function to set color with hex model by given actual
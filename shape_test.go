@@ -1,6 +1,7 @@
 package excelize_ch
 
 import (
+	"fmt"
 	"path/filepath"
 	"testing"
 
@@ -98,11 +99,136 @@ func TestAddShape(t *testing.T) {
 	assert.EqualError(t, f.AddShape("Sheet1", &Shape{Cell: "B30", Type: "rect", Paragraph: []RichTextRun{{Text: "Rectangle"}, {}}}), "XML syntax error on line 1: invalid UTF-8")
 }
 
+func TestAddShapeTextBox(t *testing.T) {
+	f := NewFile()
+	for i, autoFit := range []string{"", "none", "shrink", "resize"} {
+		err := f.AddShape("Sheet1", &Shape{
+			Cell: fmt.Sprintf("A%d", i*10+1),
+			Type: "wedgeRectCallout",
+			Paragraph: []RichTextRun{
+				{Text: "Title", Font: &Font{Bold: true}},
+			},
+			TextBox: ShapeTextBox{
+				Paragraphs: []ShapeParagraph{
+					{
+						Horizontal: "center",
+						Runs: []RichTextRun{
+							{Text: "Total: ", Font: &Font{Bold: true}},
+							{Text: "1,024", Font: &Font{Color: "C00000"}},
+						},
+					},
+					{},
+					{Horizontal: "right", Runs: []RichTextRun{{Text: "Updated daily"}}},
+				},
+				Vertical: "center",
+				AutoFit:  autoFit,
+				WrapText: true,
+				Margins:  &ShapeMargins{Left: 4, Right: 4, Top: 2, Bottom: 2},
+			},
+		})
+		assert.NoError(t, err)
+	}
+	drawing, ok := f.Drawings.Load("xl/drawings/drawing1.xml")
+	assert.True(t, ok)
+	anchors := drawing.(*xlsxWsDr).TwoCellAnchor
+	assert.Len(t, anchors, 4)
+	txBody := anchors[0].Sp.TxBody
+	assert.Equal(t, "ctr", txBody.BodyPr.Anchor)
+	assert.Equal(t, "square", txBody.BodyPr.Wrap)
+	assert.Equal(t, 50800, *txBody.BodyPr.LIns)
+	assert.Equal(t, 25400, *txBody.BodyPr.BIns)
+	assert.Nil(t, txBody.BodyPr.NoAutofit)
+	assert.Len(t, txBody.P, 4)
+	assert.Nil(t, txBody.P[0].PPr)
+	assert.Equal(t, "ctr", txBody.P[1].PPr.Algn)
+	assert.Len(t, txBody.P[1].R, 2)
+	assert.Equal(t, "1,024", txBody.P[1].R[1].T)
+	assert.Len(t, txBody.P[2].R, 0)
+	assert.Equal(t, "r", txBody.P[3].PPr.Algn)
+	assert.NotNil(t, anchors[1].Sp.TxBody.BodyPr.NoAutofit)
+	assert.NotNil(t, anchors[2].Sp.TxBody.BodyPr.NormAutofit)
+	assert.NotNil(t, anchors[3].Sp.TxBody.BodyPr.SpAutoFit)
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestAddShapeTextBox.xlsx")))
+	// Test add shape with invalid text box options
+	for _, textBox := range []ShapeTextBox{
+		{Vertical: "middle"},
+		{AutoFit: "grow"},
+		{Paragraphs: []ShapeParagraph{{Horizontal: "top"}}},
+	} {
+		err := f.AddShape("Sheet1", &Shape{Cell: "H1", Type: "rect", TextBox: textBox})
+		assert.Equal(t, ErrParameterInvalid, err)
+	}
+}
+
+func TestAddShapeEquation(t *testing.T) {
+	f := NewFile()
+	obj, err := f.AddShapeObject("Sheet1", &Shape{
+		Cell: "B2",
+		Type: "rect",
+		TextBox: ShapeTextBox{
+			Paragraphs: []ShapeParagraph{
+				{Runs: []RichTextRun{{Text: "Kinetic energy:"}}},
+				{
+					Horizontal: "center",
+					Equation:   `<m:oMath><m:r><m:t>E=</m:t></m:r><m:f><m:num><m:r><m:t>1</m:t></m:r></m:num><m:den><m:r><m:t>2</m:t></m:r></m:den></m:f><m:r><m:t>m</m:t></m:r><m:sSup><m:e><m:r><m:t>v</m:t></m:r></m:e><m:sup><m:r><m:t>2</m:t></m:r></m:sup></m:sSup></m:oMath>`,
+				},
+				{Equation: `<m:oMathPara><m:oMath><m:r><m:t>α+β≥γ</m:t></m:r></m:oMath></m:oMathPara>`},
+			},
+		},
+	})
+	assert.NoError(t, err)
+	drawing, ok := f.Drawings.Load("xl/drawings/drawing1.xml")
+	assert.True(t, ok)
+	anchor := drawing.(*xlsxWsDr).TwoCellAnchor[0]
+	assert.Nil(t, anchor.Sp)
+	assert.Len(t, anchor.AlternateContent, 1)
+	content := anchor.AlternateContent[0].Content
+	assert.Contains(t, content, `<mc:Choice xmlns:a14="http://schemas.microsoft.com/office/drawing/2010/main" Requires="a14">`)
+	assert.Contains(t, content, `<a14:m xmlns:m="http://schemas.openxmlformats.org/officeDocument/2006/math"><m:oMathPara><m:oMath><m:r><m:t>E=</m:t></m:r>`)
+	assert.Contains(t, content, `<a14:m xmlns:m="http://schemas.openxmlformats.org/officeDocument/2006/math"><m:oMathPara><m:oMath><m:r><m:t>α+β≥γ</m:t></m:r></m:oMath></m:oMathPara></a14:m>`)
+	assert.Contains(t, content, `<a:t>E=12mv2</a:t>`)
+	assert.Contains(t, content, `<a:t>α+β≥γ</a:t>`)
+	// Test get the ID and name of the shape with equation
+	id, name := f.getCellAnchorCNvPr(anchor)
+	assert.Equal(t, obj.ID, id)
+	assert.Equal(t, obj.Name, name)
+	assert.NoError(t, f.MoveDrawingObject(obj, "D4"))
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestAddShapeEquation.xlsx")))
+	assert.NoError(t, f.Close())
+	// Test preserve the equation of the shape after round trip
+	f, err = OpenFile(filepath.Join("test", "TestAddShapeEquation.xlsx"))
+	assert.NoError(t, err)
+	assert.NoError(t, f.AddShape("Sheet1", &Shape{Cell: "H2", Type: "rect", Paragraph: []RichTextRun{{Text: "Note"}}}))
+	drawing, ok = f.Drawings.Load("xl/drawings/drawing1.xml")
+	assert.True(t, ok)
+	anchor = drawing.(*xlsxWsDr).TwoCellAnchor[0]
+	id, _ = f.getCellAnchorCNvPr(anchor)
+	assert.Equal(t, obj.ID, id)
+	assert.Contains(t, anchor.GraphicFrame, `<m:t>α+β≥γ</m:t>`)
+	assert.NoError(t, f.DeleteDrawingObject(obj))
+	assert.Len(t, drawing.(*xlsxWsDr).TwoCellAnchor, 1)
+	assert.NoError(t, f.Close())
+	// Test add shape with invalid equations
+	for _, equation := range []string{
+		"<m:oMath>",
+		"<m:r><m:t>x</m:t></m:r>",
+		"<m:oMath/><m:oMath/>",
+		"x<m:oMath/>",
+		"<oMath/>",
+	} {
+		err = f.AddShape("Sheet1", &Shape{
+			Cell: "A1", Type: "rect",
+			TextBox: ShapeTextBox{Paragraphs: []ShapeParagraph{{Equation: equation}}},
+		})
+		assert.Equal(t, ErrShapeEquation, err, equation)
+	}
+}
+
 func TestAddDrawingShape(t *testing.T) {
 	f := NewFile()
 	path := "xl/drawings/drawing1.xml"
 	f.Pkg.Store(path, MacintoshCyrillicCharset)
-	assert.EqualError(t, f.addDrawingShape("sheet1", path, "A1",
+	_, err := f.addDrawingShape("sheet1", path, "A1",
 		&Shape{
 			Width:  defaultShapeSize,
 			Height: defaultShapeSize,
@@ -111,5 +237,6 @@ func TestAddDrawingShape(t *testing.T) {
 				Locked:      boolPtr(false),
 			},
 		},
-	), "XML syntax error on line 1: invalid UTF-8")
+	)
+	assert.EqualError(t, err, "XML syntax error on line 1: invalid UTF-8")
 }
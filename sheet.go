@@ -45,6 +45,7 @@ func (f *File) NewSheet(sheet string) (int, error) {
 		return index, err
 	}
 	_ = f.DeleteSheet(sheet)
+	f.resetFormulaIndex()
 	f.SheetCount++
 	wb, _ := f.workbookReader()
 	sheetID := 0
@@ -136,45 +137,51 @@ func (f *File) mergeExpandedCols(ws *xlsxWorksheet) {
 // workSheetWriter provides a function to save xl/worksheets/sheet%d.xml after
 // serialize structure.
 func (f *File) workSheetWriter() {
-	var (
-		arr     []byte
-		buffer  = bytes.NewBuffer(arr)
-		encoder = xml.NewEncoder(buffer)
-	)
 	f.Sheet.Range(func(p, ws interface{}) bool {
 		if ws != nil {
 			sheet := ws.(*xlsxWorksheet)
-			if sheet.MergeCells != nil && len(sheet.MergeCells.Cells) > 0 {
-				_ = f.mergeOverlapCells(sheet)
-			}
-			if sheet.Cols != nil && len(sheet.Cols.Col) > 0 {
-				f.mergeExpandedCols(sheet)
-			}
-			sheet.SheetData.Row = trimRow(&sheet.SheetData)
-			if sheet.SheetPr != nil || sheet.Drawing != nil || sheet.Hyperlinks != nil || sheet.Picture != nil || sheet.TableParts != nil {
-				f.addNameSpaces(p.(string), SourceRelationship)
-			}
-			if sheet.DecodeAlternateContent != nil {
-				sheet.AlternateContent = &xlsxAlternateContent{
-					Content: sheet.DecodeAlternateContent.Content,
-					XMLNSMC: SourceRelationshipCompatibility.Value,
-				}
-			}
-			sheet.DecodeAlternateContent = nil
-			// reusing buffer
-			_ = encoder.Encode(sheet)
-			f.saveFileList(p.(string), replaceRelationshipsBytes(f.replaceNameSpaceBytes(p.(string), buffer.Bytes())))
+			sheet.mu.Lock()
+			defer sheet.mu.Unlock()
+			f.saveFileList(p.(string), f.marshalWorksheet(p.(string), sheet))
 			_, ok := f.checked.Load(p.(string))
 			if ok {
 				f.Sheet.Delete(p.(string))
 				f.checked.Store(p.(string), false)
 			}
-			buffer.Reset()
 		}
 		return true
 	})
 }
 
+// marshalWorksheet provides a function to serialize the worksheet by given
+// worksheet XML path and the worksheet structure.
+func (f *File) marshalWorksheet(path string, sheet *xlsxWorksheet) []byte {
+	var buffer bytes.Buffer
+	if sheet.MergeCells != nil && len(sheet.MergeCells.Cells) > 0 {
+		_ = f.mergeOverlapCells(sheet)
+	}
+	if sheet.Cols != nil && len(sheet.Cols.Col) > 0 {
+		f.mergeExpandedCols(sheet)
+	}
+	sheet.SheetData.Row = trimRow(&sheet.SheetData)
+	if sheet.SheetPr != nil || sheet.Drawing != nil || sheet.Hyperlinks != nil || sheet.Picture != nil || sheet.TableParts != nil {
+		f.addNameSpaces(path, SourceRelationship)
+	}
+	if sheet.DecodeAlternateContent != nil {
+		sheet.AlternateContent = &xlsxAlternateContent{
+			Content: sheet.DecodeAlternateContent.Content,
+			XMLNSMC: SourceRelationshipCompatibility.Value,
+		}
+	}
+	sheet.DecodeAlternateContent = nil
+	_ = xml.NewEncoder(&buffer).Encode(sheet)
+	output, nameSpaces := replaceRevisionBytes(buffer.Bytes())
+	for _, ns := range nameSpaces {
+		f.addNameSpaces(path, ns)
+	}
+	return replaceRelationshipsBytes(f.replaceNameSpaceBytes(path, output))
+}
+
 // trimRow provides a function to trim empty rows.
 func trimRow(sheetData *xlsxSheetData) []xlsxRow {
 	var (
@@ -184,7 +191,9 @@ func trimRow(sheetData *xlsxSheetData) []xlsxRow {
 
 	for k := range sheetData.Row {
 		row = sheetData.Row[k]
-		if row = trimCell(row); len(row.C) != 0 || row.hasAttr() {
+		row = trimCell(row)
+		setRowSpans(&row)
+		if len(row.C) != 0 || row.hasAttr() {
 			sheetData.Row[i] = row
 		}
 		i++
@@ -213,6 +222,28 @@ func trimCell(row xlsxRow) xlsxRow {
 	return row
 }
 
+// setRowSpans provides a function to set the spans attribute of the row by the
+// first and last column number of the cells in the row, the spans attribute
+// will be removed if there are no cells in the row.
+func setRowSpans(row *xlsxRow) {
+	row.Spans = ""
+	if len(row.C) == 0 {
+		return
+	}
+	first, _, err := CellNameToCoordinates(row.C[0].R)
+	if err != nil {
+		return
+	}
+	last, _, err := CellNameToCoordinates(row.C[len(row.C)-1].R)
+	if err != nil {
+		return
+	}
+	if first > last {
+		first, last = last, first
+	}
+	row.Spans = strconv.Itoa(first) + ":" + strconv.Itoa(last)
+}
+
 // setContentTypes provides a function to read and update property of contents
 // type of the spreadsheet.
 func (f *File) setContentTypes(partName, contentType string) error {
@@ -366,6 +397,7 @@ func (f *File) SetSheetName(source, target string) error {
 	if strings.EqualFold(target, source) {
 		return err
 	}
+	f.resetFormulaIndex()
 	wb, _ := f.workbookReader()
 	for k, v := range wb.Sheets.Sheet {
 		if v.Name == source {
@@ -486,6 +518,26 @@ func (f *File) getSheetMap() (map[string]string, error) {
 	return maps, nil
 }
 
+// rangeWorksheets provides a function to call the given function for each
+// worksheet in the workbook sequentially, the chart sheets, dialog sheets and
+// macro sheets will be skipped. If the given function returns an error, the
+// iteration will be stopped.
+func (f *File) rangeWorksheets(fn func(sheet string, ws *xlsxWorksheet) error) error {
+	for _, sheet := range f.GetSheetList() {
+		ws, err := f.workSheetReader(sheet)
+		if err != nil {
+			if err.Error() == newNotWorksheetError(sheet).Error() {
+				continue
+			}
+			return err
+		}
+		if err = fn(sheet, ws); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // getSheetXMLPath provides a function to get XML file path by given sheet
 // name.
 func (f *File) getSheetXMLPath(sheet string) (string, bool) {
@@ -502,6 +554,102 @@ func (f *File) getSheetXMLPath(sheet string) (string, bool) {
 	return name, ok
 }
 
+// GetMacroSheets provides a function to get the Excel 4.0 (XLM) macro sheets
+// and the non-empty cells of them in the workbook, includes the visible state
+// of each macro sheet. The macro sheets are usually hidden and run the macro
+// formulas automatically, this function could be used to detect the XLM based
+// malicious content. For example, list the formulas of the macro sheets:
+//
+//	sheets, err := f.GetMacroSheets()
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	for _, sheet := range sheets {
+//	    for _, cell := range sheet.Cells {
+//	        fmt.Println(sheet.Name, cell.Cell, cell.Formula, cell.Value)
+//	    }
+//	}
+func (f *File) GetMacroSheets() ([]MacroSheet, error) {
+	var sheets []MacroSheet
+	wb, err := f.workbookReader()
+	if err != nil {
+		return sheets, err
+	}
+	rels, err := f.relsReader(f.getWorkbookRelsPath())
+	if err != nil || rels == nil {
+		return sheets, err
+	}
+	for _, sheet := range wb.Sheets.Sheet {
+		for _, rel := range rels.Relationships {
+			if rel.ID != sheet.ID || (rel.Type != SourceRelationshipMacrosheet && rel.Type != SourceRelationshipIntlMacrosheet) {
+				continue
+			}
+			macroSheet := MacroSheet{
+				Name:          sheet.Name,
+				Path:          f.getWorksheetPath(rel.Target),
+				Visible:       sheet.State == "" || sheet.State == "visible",
+				VeryHidden:    sheet.State == "veryHidden",
+				International: rel.Type == SourceRelationshipIntlMacrosheet,
+			}
+			if macroSheet.Cells, err = f.getMacroSheetCells(macroSheet.Path); err != nil {
+				return sheets, err
+			}
+			sheets = append(sheets, macroSheet)
+		}
+	}
+	return sheets, err
+}
+
+// getMacroSheetCells provides a function to get the non-empty cells of the
+// Excel 4.0 (XLM) macro sheet by given part name.
+func (f *File) getMacroSheetCells(partName string) ([]MacroSheetCell, error) {
+	var (
+		cells      []MacroSheetCell
+		macroSheet xlsxMacrosheet
+	)
+	if err := f.xmlNewDecoder(bytes.NewReader(namespaceStrictToTransitional(f.readXML(partName)))).
+		Decode(&macroSheet); err != nil && err != io.EOF {
+		return cells, err
+	}
+	sst, err := f.sharedStringsReader()
+	if err != nil {
+		return cells, err
+	}
+	var rowNum int
+	for _, row := range macroSheet.SheetData.Row {
+		if rowNum++; row.R > 0 {
+			rowNum = row.R
+		}
+		var colNum int
+		for _, c := range row.C {
+			colNum++
+			if c.R != "" {
+				if col, _, err := CellNameToCoordinates(c.R); err == nil {
+					colNum = col
+				}
+			}
+			cell, err := CoordinatesToCellName(colNum, rowNum)
+			if err != nil {
+				return cells, err
+			}
+			val, err := c.getValueFrom(f, sst, true)
+			if err != nil {
+				return cells, err
+			}
+			var formula string
+			if c.F != nil {
+				formula = c.F.Content
+			}
+			if formula == "" && val == "" {
+				continue
+			}
+			cells = append(cells, MacroSheetCell{Cell: cell, Formula: formula, Value: val})
+		}
+	}
+	return cells, err
+}
+
 // SetSheetBackground provides a function to set background picture by given
 // worksheet name and file path. Supported image types: BMP, EMF, EMZ, GIF,
 // JPEG, JPG, PNG, SVG, TIF, TIFF, WMF, and WMZ.
@@ -555,7 +703,7 @@ func (f *File) DeleteSheet(sheet string) error {
 	if idx, _ := f.GetSheetIndex(sheet); f.SheetCount == 1 || idx == -1 {
 		return nil
 	}
-
+	f.resetFormulaIndex()
 	wb, _ := f.workbookReader()
 	wbRels, _ := f.relsReader(f.getWorkbookRelsPath())
 	activeSheetName := f.GetSheetName(f.GetActiveSheetIndex())
@@ -701,6 +849,7 @@ func (f *File) copySheet(from, to int) error {
 	if err != nil {
 		return err
 	}
+	f.resetFormulaIndex()
 	worksheet := deepcopy.Copy(sheet).(*xlsxWorksheet)
 	toSheetID := strconv.Itoa(f.getSheetID(f.GetSheetName(to)))
 	sheetXMLPath := "xl/worksheets/sheet" + toSheetID + ".xml"
@@ -1099,6 +1248,131 @@ func (f *File) searchSheet(name, value string, regSearch bool) (result []string,
 	return
 }
 
+// SearchKind is the type of the kinds of text searched in the worksheet.
+type SearchKind byte
+
+// This section defines the kinds of text searched in the worksheet.
+const (
+	SearchKindValue SearchKind = iota
+	SearchKindFormula
+	SearchKindComment
+	SearchKindHyperlink
+)
+
+// SearchSheetWithOptions provides a function to search the worksheet by given
+// worksheet name, text and search options, and returns the structured search
+// results including the cell reference, the kind of the text and the matched
+// text. Besides the cell values, the formula text, comments and hyperlink
+// display strings could also be searched by the options. The text should be
+// equal to the searched text if the RegExp of the options is false, the
+// matching could be case-insensitive by the CaseInsensitive of the options.
+// The search results are sorted by the cell values and formulas in the order
+// of the cells, then the hyperlinks and comments. For example, search the
+// cells, formulas, comments and hyperlinks which contain "total" in any case
+// on Sheet1:
+//
+//	hits, err := f.SearchSheetWithOptions("Sheet1", "total", &excelize.SearchOptions{
+//	    RegExp:          true,
+//	    CaseInsensitive: true,
+//	    Formulas:        true,
+//	    Comments:        true,
+//	    Hyperlinks:      true,
+//	})
+func (f *File) SearchSheetWithOptions(sheet, value string, opts *SearchOptions) ([]SearchHit, error) {
+	var hits []SearchHit
+	if opts == nil {
+		opts = &SearchOptions{}
+	}
+	match, err := newSearchMatcher(value, opts)
+	if err != nil {
+		return hits, err
+	}
+	f.mu.Lock()
+	ws, err := f.workSheetReader(sheet)
+	f.mu.Unlock()
+	if err != nil {
+		return hits, err
+	}
+	sst, err := f.sharedStringsReader()
+	if err != nil {
+		return hits, err
+	}
+	appendHit := func(cell string, kind SearchKind, text string) {
+		if matched, ok := match(text); ok {
+			hits = append(hits, SearchHit{Cell: cell, Kind: kind, MatchedText: matched})
+		}
+	}
+	ws.mu.Lock()
+	for _, row := range ws.SheetData.Row {
+		for _, c := range row.C {
+			val, _ := c.getValueFrom(f, sst, false)
+			appendHit(c.R, SearchKindValue, val)
+			if !opts.Formulas || c.F == nil {
+				continue
+			}
+			formula := c.F.Content
+			if c.F.T == STCellFormulaTypeShared && c.F.Si != nil {
+				formula = getSharedFormula(ws, *c.F.Si, c.R)
+			}
+			appendHit(c.R, SearchKindFormula, formula)
+		}
+	}
+	if opts.Hyperlinks && ws.Hyperlinks != nil {
+		for _, link := range ws.Hyperlinks.Hyperlink {
+			appendHit(strings.Split(link.Ref, ":")[0], SearchKindHyperlink, link.Display)
+		}
+	}
+	ws.mu.Unlock()
+	if !opts.Comments {
+		return hits, err
+	}
+	comments, err := f.GetComments(sheet)
+	if err != nil {
+		return hits, err
+	}
+	for _, comment := range comments {
+		text := comment.Text
+		for _, run := range comment.Paragraph {
+			text += run.Text
+		}
+		appendHit(comment.Cell, SearchKindComment, text)
+	}
+	return hits, err
+}
+
+// newSearchMatcher provides a function to create the function to match the
+// text by given searched text and search options, the function returns the
+// matched part of the text and whether the text was matched. The empty text
+// will not be matched.
+func newSearchMatcher(value string, opts *SearchOptions) (func(text string) (string, bool), error) {
+	if !opts.RegExp {
+		return func(text string) (string, bool) {
+			if text == "" {
+				return "", false
+			}
+			return text, text == value || (opts.CaseInsensitive && strings.EqualFold(text, value))
+		}, nil
+	}
+	expr := value
+	if opts.CaseInsensitive {
+		expr = "(?i)" + expr
+	}
+	regex, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, err
+	}
+	return func(text string) (string, bool) {
+		if text == "" {
+			return "", false
+		}
+		loc := regex.FindStringIndex(text)
+		if loc == nil {
+			return "", false
+		}
+		return text[loc[0]:loc[1]], true
+	}, nil
+}
+
 // attrValToInt provides a function to convert the local names to an integer
 // by given XML attributes and specified names.
 func attrValToInt(name string, attrs []xml.Attr) (val int, err error) {
@@ -1289,12 +1563,211 @@ func (f *File) SetHeaderFooter(sheet string, opts *HeaderFooterOptions) error {
 	return err
 }
 
+// HeaderFooterToken defined the type of the code in the header or footer.
+type HeaderFooterToken int
+
+// Header and footer tokens.
+const (
+	_ HeaderFooterToken = iota
+	HeaderFooterTokenPageNumber
+	HeaderFooterTokenTotalPages
+	HeaderFooterTokenDate
+	HeaderFooterTokenTime
+	HeaderFooterTokenFileName
+	HeaderFooterTokenFilePath
+	HeaderFooterTokenSheetName
+)
+
+// headerFooterTokenMap defined supported header and footer tokens.
+var headerFooterTokenMap = map[HeaderFooterToken]string{
+	HeaderFooterTokenPageNumber: "&P",
+	HeaderFooterTokenTotalPages: "&N",
+	HeaderFooterTokenDate:       "&D",
+	HeaderFooterTokenTime:       "&T",
+	HeaderFooterTokenFileName:   "&F",
+	HeaderFooterTokenFilePath:   "&Z",
+	HeaderFooterTokenSheetName:  "&A",
+}
+
+// headerFooterFont defined the font formatting state of the section in the
+// header or footer, which used to generate the toggle codes.
+type headerFooterFont struct {
+	bold, italic, strike bool
+	underline, vertAlign string
+}
+
+// NewHeaderFooter provides a function to generate the header or footer with
+// the formatting codes by given left, center and right sections, the text in
+// the runs will be escaped, and the font formatting and tokens will be
+// converted to the codes. The generated header or footer could be used in
+// the fields of the SetHeaderFooter function. It returns an error if the
+// header or footer exceeds 255 characters, or the font formatting or token is
+// invalid. The font formatting of a run will be kept in the following runs of
+// the section if they don't specify the font. A space will be inserted
+// between the font size and the following text starting with a digit. For
+// example, set the footer to show the page number and total pages like "Page
+// 1 of 3" in the center section, and the current date in the right section
+// with bold Arial font in 9 points:
+//
+//	footer, err := excelize.NewHeaderFooter(&excelize.HeaderFooterSections{
+//	    Center: []excelize.HeaderFooterRun{
+//	        {Text: "Page ", Token: excelize.HeaderFooterTokenPageNumber},
+//	        {Text: " of ", Token: excelize.HeaderFooterTokenTotalPages},
+//	    },
+//	    Right: []excelize.HeaderFooterRun{
+//	        {
+//	            Font:  &excelize.Font{Family: "Arial", Bold: true, Size: 9},
+//	            Token: excelize.HeaderFooterTokenDate,
+//	        },
+//	    },
+//	})
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	err = f.SetHeaderFooter("Sheet1", &excelize.HeaderFooterOptions{OddFooter: footer})
+//
+// The generated footer is: &CPage &P of &N&R&9&"Arial,Bold"&D
+//
+// Header and footer tokens:
+//
+//	 Token                       | Code | Description
+//	-----------------------------+------+-------------------------------
+//	 HeaderFooterTokenPageNumber | &P   | Current page number
+//	 HeaderFooterTokenTotalPages | &N   | Total number of pages
+//	 HeaderFooterTokenDate       | &D   | Current date
+//	 HeaderFooterTokenTime       | &T   | Current time
+//	 HeaderFooterTokenFileName   | &F   | Current workbook's file name
+//	 HeaderFooterTokenFilePath   | &Z   | Current workbook's file path
+//	 HeaderFooterTokenSheetName  | &A   | Current worksheet's tab name
+func NewHeaderFooter(sections *HeaderFooterSections) (string, error) {
+	if sections == nil {
+		return "", ErrParameterRequired
+	}
+	var sb strings.Builder
+	for i, runs := range [][]HeaderFooterRun{sections.Left, sections.Center, sections.Right} {
+		if len(runs) == 0 {
+			continue
+		}
+		section, err := newHeaderFooterSection(runs)
+		if err != nil {
+			return "", err
+		}
+		sb.WriteString([]string{"&L", "&C", "&R"}[i] + section)
+	}
+	if len(utf16.Encode([]rune(sb.String()))) > MaxFieldLength {
+		return "", ErrHeaderFooterLength
+	}
+	return sb.String(), nil
+}
+
+// newHeaderFooterSection provides a function to generate the section of the
+// header or footer by given runs.
+func newHeaderFooterSection(runs []HeaderFooterRun) (string, error) {
+	var (
+		sb    strings.Builder
+		state headerFooterFont
+	)
+	for _, run := range runs {
+		var sizeLast bool
+		if run.Font != nil {
+			codes, err := state.codes(run.Font)
+			if err != nil {
+				return "", err
+			}
+			// The font size code is the first code
+			sizeLast = len(codes) == 1 && run.Font.Size != 0
+			sb.WriteString(strings.Join(codes, ""))
+		}
+		text := strings.ReplaceAll(run.Text, "&", "&&")
+		if sizeLast && text != "" && text[0] >= '0' && text[0] <= '9' {
+			text = " " + text
+		}
+		sb.WriteString(text)
+		if run.Token != 0 {
+			code, ok := headerFooterTokenMap[run.Token]
+			if !ok {
+				return "", ErrParameterInvalid
+			}
+			sb.WriteString(code)
+		}
+	}
+	return sb.String(), nil
+}
+
+// codes provides a function to get the formatting codes of the header or
+// footer by given font, the font style codes will be generated by comparing
+// with the current font formatting state.
+func (state *headerFooterFont) codes(font *Font) ([]string, error) {
+	var codes []string
+	if font.Size != 0 {
+		if font.Size < MinFontSize || font.Size > MaxFontSize {
+			return codes, ErrFontSize
+		}
+		codes = append(codes, "&"+strconv.FormatFloat(font.Size, 'f', -1, 64))
+	}
+	if font.Family != "" || font.Bold != state.bold || font.Italic != state.italic {
+		family := font.Family
+		if family == "" {
+			family = "-"
+		}
+		if len(family) > MaxFontFamilyLength {
+			return codes, ErrFontLength
+		}
+		if strings.ContainsAny(family, "\",") {
+			return codes, ErrParameterInvalid
+		}
+		style := map[bool]map[bool]string{false: {false: "Regular", true: "Italic"}, true: {false: "Bold", true: "Bold Italic"}}[font.Bold][font.Italic]
+		codes = append(codes, fmt.Sprintf("&\"%s,%s\"", family, style))
+		state.bold, state.italic = font.Bold, font.Italic
+	}
+	toggles := map[string]string{"single": "&U", "double": "&E", "superscript": "&X", "subscript": "&Y"}
+	toggle := func(current *string, value string) error {
+		if value == "baseline" {
+			value = ""
+		}
+		if _, ok := toggles[value]; !ok && value != "" {
+			return ErrParameterInvalid
+		}
+		if value != *current {
+			for _, code := range []string{toggles[*current], toggles[value]} {
+				if code != "" {
+					codes = append(codes, code)
+				}
+			}
+			*current = value
+		}
+		return nil
+	}
+	if err := toggle(&state.underline, font.Underline); err != nil {
+		return codes, err
+	}
+	if err := toggle(&state.vertAlign, font.VertAlign); err != nil {
+		return codes, err
+	}
+	if font.Strike != state.strike {
+		codes = append(codes, "&S")
+		state.strike = font.Strike
+	}
+	if font.Color != "" {
+		color := strings.TrimPrefix(font.Color, "#")
+		if _, err := strconv.ParseUint(color, 16, 32); err != nil || len(color) != 6 {
+			return codes, ErrParameterInvalid
+		}
+		codes = append(codes, "&K"+strings.ToUpper(color))
+	}
+	return codes, nil
+}
+
 // ProtectSheet provides a function to prevent other users from accidentally or
 // deliberately changing, moving, or deleting data in a worksheet. The
 // optional field AlgorithmName specified hash algorithm, support XOR, MD4,
 // MD5, SHA-1, SHA2-56, SHA-384, and SHA-512 currently, if no hash algorithm
-// specified, will be using the XOR algorithm as default. For example, protect
-// Sheet1 with protection settings:
+// specified, will be using the XOR algorithm as default. The charts, pictures,
+// shapes, comments and form controls can't be added, moved or deleted on the
+// protected worksheet unless the EditObjects is true, or the ForceEditObjects
+// of the workbook options is true. For example, protect Sheet1 with
+// protection settings:
 //
 //	err := f.ProtectSheet("Sheet1", &excelize.SheetProtectionOptions{
 //	    AlgorithmName:       "SHA-512",
@@ -1378,6 +1851,16 @@ func (f *File) UnprotectSheet(sheet string, password ...string) error {
 	return err
 }
 
+// checkObjectsProtection provides a function to check if the objects of the
+// worksheet could be added, moved or deleted by the sheet protection settings.
+func (f *File) checkObjectsProtection(ws *xlsxWorksheet) error {
+	if ws.SheetProtection == nil || !ws.SheetProtection.Sheet ||
+		!ws.SheetProtection.Objects || f.options.ForceEditObjects {
+		return nil
+	}
+	return ErrProtectedObjects
+}
+
 // checkSheetName check whether there are illegal characters in the sheet name.
 // 1. Confirm that the sheet name is not empty
 // 2. Make sure to enter a name with no more than 31 characters
@@ -1631,6 +2114,7 @@ func (f *File) SetDefinedName(definedName *DefinedName) error {
 	if err != nil {
 		return err
 	}
+	f.resetFormulaIndex()
 	d := xlsxDefinedName{
 		Name:    definedName.Name,
 		Comment: definedName.Comment,
@@ -1685,6 +2169,7 @@ func (f *File) DeleteDefinedName(definedName *DefinedName) error {
 			}
 			if scope == deleteScope && dn.Name == definedName.Name {
 				wb.DefinedNames.DefinedName = append(wb.DefinedNames.DefinedName[:idx], wb.DefinedNames.DefinedName[idx+1:]...)
+				f.resetFormulaIndex()
 				return err
 			}
 		}
@@ -1714,6 +2199,130 @@ func (f *File) GetDefinedName() []DefinedName {
 	return definedNames
 }
 
+// ExportDefinedNames provides a function to export all defined names of the
+// workbook and worksheets with the formulas they refer to and the scope, the
+// scope of the defined names in the workbook level is "Workbook". The
+// exported defined names could be imported to another workbook by the
+// ImportDefinedNames function.
+func (f *File) ExportDefinedNames() ([]DefinedName, error) {
+	var definedNames []DefinedName
+	wb, err := f.workbookReader()
+	if err != nil {
+		return definedNames, err
+	}
+	if wb.DefinedNames != nil {
+		for _, dn := range wb.DefinedNames.DefinedName {
+			definedName := DefinedName{
+				Name:     dn.Name,
+				Comment:  dn.Comment,
+				RefersTo: dn.Data,
+				Scope:    "Workbook",
+			}
+			if dn.LocalSheetID != nil && *dn.LocalSheetID >= 0 {
+				definedName.Scope = f.GetSheetName(*dn.LocalSheetID)
+			}
+			definedNames = append(definedNames, definedName)
+		}
+	}
+	return definedNames, err
+}
+
+// ImportDefinedNames provides a function to import the defined names, which
+// are usually exported from another workbook by the ExportDefinedNames
+// function. The worksheet names in the formulas and the scope of the defined
+// names will be rewritten to the worksheets of the workbook by the SheetMap
+// of the options, the worksheet names not in the SheetMap will be matched to
+// the worksheets of the workbook with the same name case-insensitively, and
+// the references to the worksheets which not exist in the workbook will be
+// replaced with the #REF! error. If a defined name is scoped to a worksheet
+// which not exists, or a defined name with the same name and scope already
+// exists and the Overwrite of the options is false, none of the defined names
+// will be imported. For example, synchronize the defined names of the
+// template workbook to the workbook, and the worksheet "Data" of the template
+// is named "Sheet1" in the workbook:
+//
+//	definedNames, err := template.ExportDefinedNames()
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	err = f.ImportDefinedNames(definedNames, &excelize.ImportDefinedNamesOptions{
+//	    SheetMap:  map[string]string{"Data": "Sheet1"},
+//	    Overwrite: true,
+//	})
+func (f *File) ImportDefinedNames(definedNames []DefinedName, opts *ImportDefinedNamesOptions) error {
+	if opts == nil {
+		opts = &ImportDefinedNamesOptions{}
+	}
+	wb, err := f.workbookReader()
+	if err != nil {
+		return err
+	}
+	sheets := f.GetSheetList()
+	targets, renames := map[string]string{}, map[string]string{}
+	for _, sheet := range sheets {
+		targets[strings.ToLower(sheet)] = sheet
+	}
+	for src, dst := range opts.SheetMap {
+		name, ok := targets[strings.ToLower(dst)]
+		if !ok {
+			return ErrSheetNotExist{dst}
+		}
+		renames[strings.ToLower(src)] = name
+	}
+	resolve := func(sheet string) (string, bool) {
+		if name, ok := renames[strings.ToLower(sheet)]; ok {
+			return name, true
+		}
+		if name, ok := targets[strings.ToLower(sheet)]; ok {
+			return name, true
+		}
+		return sheet, false
+	}
+	var items []xlsxDefinedName
+	if wb.DefinedNames != nil {
+		items = append(items, wb.DefinedNames.DefinedName...)
+	}
+	for _, definedName := range definedNames {
+		if definedName.Name == "" || definedName.RefersTo == "" {
+			return ErrParameterInvalid
+		}
+		if err := checkDefinedName(definedName.Name); err != nil && inStrSlice(builtInDefinedNames[:2], definedName.Name, false) == -1 {
+			return err
+		}
+		d := xlsxDefinedName{Name: definedName.Name, Comment: definedName.Comment}
+		d.Data, _ = adjustFormulaSheetRefs(definedName.RefersTo, resolve)
+		if definedName.Scope != "" && definedName.Scope != "Workbook" {
+			scope, ok := resolve(definedName.Scope)
+			if !ok {
+				return ErrSheetNotExist{definedName.Scope}
+			}
+			d.LocalSheetID = intPtr(inStrSlice(sheets, scope, true))
+		}
+		idx := -1
+		for i, dn := range items {
+			if strings.EqualFold(dn.Name, d.Name) &&
+				((dn.LocalSheetID == nil && d.LocalSheetID == nil) ||
+					(dn.LocalSheetID != nil && d.LocalSheetID != nil && *dn.LocalSheetID == *d.LocalSheetID)) {
+				idx = i
+				break
+			}
+		}
+		if idx == -1 {
+			items = append(items, d)
+			continue
+		}
+		if !opts.Overwrite {
+			return ErrDefinedNameDuplicate
+		}
+		items[idx].Data, items[idx].Comment = d.Data, d.Comment
+	}
+	if len(items) > 0 {
+		wb.DefinedNames = &xlsxDefinedNames{DefinedName: items}
+	}
+	return err
+}
+
 // GroupSheets provides a function to group worksheets by given worksheets
 // name. Group worksheets must contain an active worksheet.
 func (f *File) GroupSheets(sheets []string) error {
@@ -1987,7 +2596,10 @@ func (f *File) SetSheetDimension(sheet string, rangeRef string) error {
 	return err
 }
 
-// GetSheetDimension provides the method to get the used range of the worksheet.
+// GetSheetDimension provides the method to get the used range of the
+// worksheet declared in the worksheet dimension element, which may be
+// missing or inaccurate in the workbook created by other applications, use
+// the GetSheetUsedRange function to compute the actual used range.
 func (f *File) GetSheetDimension(sheet string) (string, error) {
 	var ref string
 	ws, err := f.workSheetReader(sheet)
@@ -1999,3 +2611,121 @@ func (f *File) GetSheetDimension(sheet string) (string, error) {
 	}
 	return ref, err
 }
+
+// GetSheetUsedRange provides the method to compute the actual used range of
+// the worksheet by a given worksheet name. The cells with value, formula or
+// style will be considered as used cells. The worksheet will be scanned by
+// the streaming XML decoder without loading the whole worksheet if it has not
+// been read before, so it could be used to pre-allocate the buffers and
+// verify the declared dimension for the large worksheet. An empty string will
+// be returned if there are no used cells in the worksheet. For example, get
+// the used range of the worksheet named Sheet1:
+//
+//	ref, err := f.GetSheetUsedRange("Sheet1")
+func (f *File) GetSheetUsedRange(sheet string) (string, error) {
+	if err := checkSheetName(sheet); err != nil {
+		return "", err
+	}
+	name, ok := f.getSheetXMLPath(sheet)
+	if !ok {
+		return "", ErrSheetNotExist{sheet}
+	}
+	var coordinates []int
+	update := func(c *xlsxC, col, row int) {
+		if !c.hasValue() && c.IS == nil {
+			return
+		}
+		if coordinates == nil {
+			coordinates = []int{col, row, col, row}
+			return
+		}
+		if col < coordinates[0] {
+			coordinates[0] = col
+		}
+		if row < coordinates[1] {
+			coordinates[1] = row
+		}
+		if col > coordinates[2] {
+			coordinates[2] = col
+		}
+		if row > coordinates[3] {
+			coordinates[3] = row
+		}
+	}
+	if worksheet, ok := f.Sheet.Load(name); ok && worksheet != nil {
+		ws := worksheet.(*xlsxWorksheet)
+		ws.mu.Lock()
+		for rowIdx, r := range ws.SheetData.Row {
+			for colIdx := range r.C {
+				col, row := colIdx+1, rowIdx+1
+				if r.R != 0 {
+					row = r.R
+				}
+				if r.C[colIdx].R != "" {
+					var err error
+					if col, row, err = CellNameToCoordinates(r.C[colIdx].R); err != nil {
+						ws.mu.Unlock()
+						return "", err
+					}
+				}
+				update(&r.C[colIdx], col, row)
+			}
+		}
+		ws.mu.Unlock()
+		return f.getUsedRangeRef(coordinates)
+	}
+	needClose, decoder, tempFile, err := f.xmlDecoder(name)
+	if needClose && tempFile != nil {
+		defer tempFile.Close()
+	}
+	if err != nil {
+		return "", err
+	}
+	var col, row int
+	for {
+		token, _ := decoder.Token()
+		if token == nil {
+			break
+		}
+		if xmlElement, ok := token.(xml.EndElement); ok && xmlElement.Name.Local == "sheetData" {
+			break
+		}
+		xmlElement, ok := token.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		switch xmlElement.Name.Local {
+		case "row":
+			row, col = row+1, 0
+			if rowNum, _ := attrValToInt("r", xmlElement.Attr); rowNum != 0 {
+				row = rowNum
+			}
+		case "c":
+			col++
+			var c xlsxC
+			if err = decoder.DecodeElement(&c, &xmlElement); err != nil {
+				return "", err
+			}
+			if c.R != "" {
+				if col, _, err = CellNameToCoordinates(c.R); err != nil {
+					return "", err
+				}
+			}
+			update(&c, col, row)
+		}
+	}
+	return f.getUsedRangeRef(coordinates)
+}
+
+// getUsedRangeRef provides a function to convert the coordinates of the used
+// range to the range reference, returns the single cell reference if the
+// used range contains only one cell.
+func (f *File) getUsedRangeRef(coordinates []int) (string, error) {
+	if coordinates == nil {
+		return "", nil
+	}
+	if coordinates[0] == coordinates[2] && coordinates[1] == coordinates[3] {
+		return CoordinatesToCellName(coordinates[0], coordinates[1])
+	}
+	return f.coordinatesToRangeRef(coordinates)
+}
@@ -196,6 +196,67 @@ func TestSearchSheet(t *testing.T) {
 	assert.EqualError(t, err, "XML syntax error on line 1: invalid UTF-8")
 }
 
+func TestSearchSheetWithOptions(t *testing.T) {
+	f := NewFile()
+	assert.NoError(t, f.SetCellValue("Sheet1", "A1", "Total"))
+	assert.NoError(t, f.SetCellValue("Sheet1", "A2", 100))
+	assert.NoError(t, f.SetCellFormula("Sheet1", "B1", "SUM(A2:A3)"))
+	assert.NoError(t, f.SetCellValue("Sheet1", "C1", "subtotal"))
+	formulaType, ref := STCellFormulaTypeShared, "D1:D3"
+	assert.NoError(t, f.SetCellFormula("Sheet1", "D1", "total(A1)", FormulaOpts{Type: &formulaType, Ref: &ref}))
+	display := "TOTAL report"
+	assert.NoError(t, f.SetCellHyperLink("Sheet1", "E1", "https://github.com/xuri/excelize", "External", HyperlinkOpts{Display: &display}))
+	assert.NoError(t, f.AddComment("Sheet1", Comment{
+		Cell: "F1", Author: "Excelize",
+		Paragraph: []RichTextRun{{Text: "Check the "}, {Text: "totals"}},
+	}))
+
+	hits, err := f.SearchSheetWithOptions("Sheet1", "Total", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []SearchHit{{Cell: "A1", Kind: SearchKindValue, MatchedText: "Total"}}, hits)
+
+	hits, err = f.SearchSheetWithOptions("Sheet1", "total", &SearchOptions{
+		RegExp: true, CaseInsensitive: true, Formulas: true, Comments: true, Hyperlinks: true,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []SearchHit{
+		{Cell: "A1", Kind: SearchKindValue, MatchedText: "Total"},
+		{Cell: "C1", Kind: SearchKindValue, MatchedText: "total"},
+		{Cell: "D1", Kind: SearchKindFormula, MatchedText: "total"},
+		{Cell: "D2", Kind: SearchKindFormula, MatchedText: "total"},
+		{Cell: "D3", Kind: SearchKindFormula, MatchedText: "total"},
+		{Cell: "E1", Kind: SearchKindHyperlink, MatchedText: "TOTAL"},
+		{Cell: "F1", Kind: SearchKindComment, MatchedText: "total"},
+	}, hits)
+
+	hits, err = f.SearchSheetWithOptions("Sheet1", "sum\\((A\\d+):", &SearchOptions{
+		RegExp: true, CaseInsensitive: true, Formulas: true,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []SearchHit{{Cell: "B1", Kind: SearchKindFormula, MatchedText: "SUM(A2:"}}, hits)
+
+	hits, err = f.SearchSheetWithOptions("Sheet1", "total(a2)", &SearchOptions{CaseInsensitive: true, Formulas: true})
+	assert.NoError(t, err)
+	assert.Equal(t, []SearchHit{{Cell: "D2", Kind: SearchKindFormula, MatchedText: "total(A2)"}}, hits)
+
+	// Test search sheet with invalid regular expression
+	_, err = f.SearchSheetWithOptions("Sheet1", "[", &SearchOptions{RegExp: true})
+	assert.EqualError(t, err, "error parsing regexp: missing closing ]: `[`")
+	// Test search in a not exists worksheet
+	_, err = f.SearchSheetWithOptions("SheetN", "Total", nil)
+	assert.EqualError(t, err, "sheet SheetN does not exist")
+	// Test search sheet with unsupported charset comments
+	f.Comments["xl/comments1.xml"] = nil
+	f.Pkg.Store("xl/comments1.xml", MacintoshCyrillicCharset)
+	_, err = f.SearchSheetWithOptions("Sheet1", "Total", &SearchOptions{Comments: true})
+	assert.EqualError(t, err, "XML syntax error on line 1: invalid UTF-8")
+	// Test search sheet with unsupported charset shared strings table
+	f.SharedStrings = nil
+	f.Pkg.Store(defaultXMLPathSharedStrings, MacintoshCyrillicCharset)
+	_, err = f.SearchSheetWithOptions("Sheet1", "Total", nil)
+	assert.EqualError(t, err, "XML syntax error on line 1: invalid UTF-8")
+}
+
 func TestSetPageLayout(t *testing.T) {
 	f := NewFile()
 	assert.NoError(t, f.SetPageLayout("Sheet1", nil))
@@ -264,6 +325,63 @@ func TestSetHeaderFooter(t *testing.T) {
 	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestSetHeaderFooter.xlsx")))
 }
 
+func TestNewHeaderFooter(t *testing.T) {
+	footer, err := NewHeaderFooter(&HeaderFooterSections{
+		Center: []HeaderFooterRun{
+			{Text: "Page ", Token: HeaderFooterTokenPageNumber},
+			{Text: " of ", Token: HeaderFooterTokenTotalPages},
+		},
+		Right: []HeaderFooterRun{
+			{Font: &Font{Family: "Arial", Bold: true, Size: 9}, Token: HeaderFooterTokenDate},
+		},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, `&CPage &P of &N&R&9&"Arial,Bold"&D`, footer)
+	f := NewFile()
+	assert.NoError(t, f.SetHeaderFooter("Sheet1", &HeaderFooterOptions{OddFooter: footer}))
+
+	header, err := NewHeaderFooter(&HeaderFooterSections{
+		Left: []HeaderFooterRun{
+			{Text: "R&D ", Token: HeaderFooterTokenSheetName},
+			{Font: &Font{Size: 12}, Text: "2024"},
+		},
+		Center: []HeaderFooterRun{
+			{Font: &Font{Italic: true, Underline: "double", Color: "#ff0000"}, Text: "A"},
+			{Font: &Font{Underline: "single", Strike: true, VertAlign: "superscript"}, Text: "B"},
+			{Text: "C"},
+			{Font: &Font{VertAlign: "baseline"}, Token: HeaderFooterTokenFileName},
+		},
+		Right: []HeaderFooterRun{
+			{Token: HeaderFooterTokenTime},
+			{Token: HeaderFooterTokenFilePath},
+		},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, `&LR&&D &A&12 2024&C&"-,Italic"&E&KFF0000A&"-,Regular"&E&U&X&SBC&U&X&S&F&R&T&Z`, header)
+	assert.NoError(t, f.SetHeaderFooter("Sheet1", &HeaderFooterOptions{OddHeader: header}))
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestNewHeaderFooter.xlsx")))
+
+	// Test generate header or footer with invalid parameters
+	for _, c := range []struct {
+		runs []HeaderFooterRun
+		err  error
+	}{
+		{runs: []HeaderFooterRun{{Text: strings.Repeat("&", 128)}}, err: ErrHeaderFooterLength},
+		{runs: []HeaderFooterRun{{Token: HeaderFooterToken(100)}}, err: ErrParameterInvalid},
+		{runs: []HeaderFooterRun{{Font: &Font{Size: MaxFontSize + 1}}}, err: ErrFontSize},
+		{runs: []HeaderFooterRun{{Font: &Font{Family: strings.Repeat("a", MaxFontFamilyLength+1)}}}, err: ErrFontLength},
+		{runs: []HeaderFooterRun{{Font: &Font{Family: `A"B`}}}, err: ErrParameterInvalid},
+		{runs: []HeaderFooterRun{{Font: &Font{Underline: "none"}}}, err: ErrParameterInvalid},
+		{runs: []HeaderFooterRun{{Font: &Font{VertAlign: "top"}}}, err: ErrParameterInvalid},
+		{runs: []HeaderFooterRun{{Font: &Font{Color: "red"}}}, err: ErrParameterInvalid},
+	} {
+		_, err = NewHeaderFooter(&HeaderFooterSections{Right: c.runs})
+		assert.Equal(t, c.err, err)
+	}
+	_, err = NewHeaderFooter(nil)
+	assert.Equal(t, ErrParameterRequired, err)
+}
+
 func TestDefinedName(t *testing.T) {
 	f := NewFile()
 	assert.NoError(t, f.SetDefinedName(&DefinedName{
@@ -323,6 +441,77 @@ func TestDefinedName(t *testing.T) {
 		"XML syntax error on line 1: invalid UTF-8")
 }
 
+func TestExportAndImportDefinedNames(t *testing.T) {
+	src := NewFile()
+	for _, sheet := range []string{"Data", "My Sheet"} {
+		_, err := src.NewSheet(sheet)
+		assert.NoError(t, err)
+	}
+	for _, dn := range []DefinedName{
+		{Name: "Amount", RefersTo: "Data!$A$2:$D$5", Comment: "comment"},
+		{Name: "Rate", RefersTo: "'My Sheet'!$B$1*Sheet1!$A$1", Scope: "Data"},
+		{Name: "Total", RefersTo: "SUM(Data!$A:$A)+Deleted!$A$1"},
+		{Name: builtInDefinedNames[0], RefersTo: "Data!$A$1:$Z$100", Scope: "Data"},
+	} {
+		dn := dn
+		if dn.Name == "Total" {
+			_, err := src.NewSheet("Deleted")
+			assert.NoError(t, err)
+		}
+		assert.NoError(t, src.SetDefinedName(&dn))
+	}
+	definedNames, err := src.ExportDefinedNames()
+	assert.NoError(t, err)
+	assert.Equal(t, src.GetDefinedName(), definedNames)
+	assert.Equal(t, "Data", definedNames[1].Scope)
+	assert.Equal(t, "Workbook", definedNames[2].Scope)
+
+	f := NewFile()
+	_, err = f.NewSheet("MY SHEET")
+	assert.NoError(t, err)
+	assert.NoError(t, f.SetDefinedName(&DefinedName{Name: "amount", RefersTo: "Sheet1!$A$1"}))
+	// Test import defined names with duplicate defined name
+	assert.Equal(t, ErrDefinedNameDuplicate, f.ImportDefinedNames(definedNames, &ImportDefinedNamesOptions{
+		SheetMap: map[string]string{"data": "Sheet1"},
+	}))
+	assert.Len(t, f.GetDefinedName(), 1)
+	// Test import defined names with the scope worksheet not exists
+	assert.Equal(t, ErrSheetNotExist{"Data"}, f.ImportDefinedNames(definedNames, &ImportDefinedNamesOptions{Overwrite: true}))
+	// Test import defined names with the mapped worksheet not exists
+	assert.Equal(t, ErrSheetNotExist{"SheetN"}, f.ImportDefinedNames(definedNames, &ImportDefinedNamesOptions{
+		SheetMap: map[string]string{"Data": "SheetN"},
+	}))
+	assert.Len(t, f.GetDefinedName(), 1)
+	assert.NoError(t, f.ImportDefinedNames(definedNames, &ImportDefinedNamesOptions{
+		SheetMap:  map[string]string{"data": "Sheet1"},
+		Overwrite: true,
+	}))
+	assert.Equal(t, []DefinedName{
+		{Name: "amount", RefersTo: "Sheet1!$A$2:$D$5", Comment: "comment", Scope: "Workbook"},
+		{Name: "Rate", RefersTo: "'MY SHEET'!$B$1*Sheet1!$A$1", Scope: "Sheet1"},
+		{Name: "Total", RefersTo: "SUM(Sheet1!$A:$A)+#REF!", Scope: "Workbook"},
+		{Name: builtInDefinedNames[0], RefersTo: "Sheet1!$A$1:$Z$100", Scope: "Sheet1"},
+	}, f.GetDefinedName())
+
+	// Test import defined names without options
+	f = NewFile()
+	assert.NoError(t, f.ImportDefinedNames([]DefinedName{{Name: "Amount", RefersTo: "Sheet1!$A$1", Scope: "sheet1"}}, nil))
+	assert.Equal(t, []DefinedName{{Name: "Amount", RefersTo: "Sheet1!$A$1", Scope: "Sheet1"}}, f.GetDefinedName())
+	assert.NoError(t, f.ImportDefinedNames(nil, nil))
+	// Test import defined names with invalid defined names
+	assert.Equal(t, ErrParameterInvalid, f.ImportDefinedNames([]DefinedName{{Name: "Amount"}}, nil))
+	assert.Equal(t, ErrParameterInvalid, f.ImportDefinedNames([]DefinedName{{RefersTo: "Sheet1!$A$1"}}, nil))
+	assert.Equal(t, newInvalidNameError("1Amount"), f.ImportDefinedNames([]DefinedName{{Name: "1Amount", RefersTo: "Sheet1!$A$1"}}, nil))
+	// Test export and import defined names with unsupported charset workbook
+	f.WorkBook = nil
+	f.Pkg.Store(defaultXMLPathWorkbook, MacintoshCyrillicCharset)
+	_, err = f.ExportDefinedNames()
+	assert.EqualError(t, err, "XML syntax error on line 1: invalid UTF-8")
+	f.WorkBook = nil
+	f.Pkg.Store(defaultXMLPathWorkbook, MacintoshCyrillicCharset)
+	assert.EqualError(t, f.ImportDefinedNames(definedNames, nil), "XML syntax error on line 1: invalid UTF-8")
+}
+
 func TestGroupSheets(t *testing.T) {
 	f := NewFile()
 	sheets := []string{"Sheet2", "Sheet3"}
@@ -461,7 +650,7 @@ func TestWorksheetWriter(t *testing.T) {
 	f := NewFile()
 	// Test set cell value with alternate content
 	f.Sheet.Delete("xl/worksheets/sheet1.xml")
-	worksheet := xml.Header + `<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheetData><row r="1"><c r="A1"><v>%d</v></c></row></sheetData><mc:AlternateContent xmlns:mc="http://schemas.openxmlformats.org/markup-compatibility/2006"><mc:Choice xmlns:a14="http://schemas.microsoft.com/office/drawing/2010/main" Requires="a14"><xdr:twoCellAnchor editAs="oneCell"></xdr:twoCellAnchor></mc:Choice><mc:Fallback/></mc:AlternateContent></worksheet>`
+	worksheet := xml.Header + `<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheetData><row r="1" spans="1:1"><c r="A1"><v>%d</v></c></row></sheetData><mc:AlternateContent xmlns:mc="http://schemas.openxmlformats.org/markup-compatibility/2006"><mc:Choice xmlns:a14="http://schemas.microsoft.com/office/drawing/2010/main" Requires="a14"><xdr:twoCellAnchor editAs="oneCell"></xdr:twoCellAnchor></mc:Choice><mc:Fallback/></mc:AlternateContent></worksheet>`
 	f.Pkg.Store("xl/worksheets/sheet1.xml", []byte(fmt.Sprintf(worksheet, 1)))
 	f.checked = sync.Map{}
 	assert.NoError(t, f.SetCellValue("Sheet1", "A1", 2))
@@ -471,6 +660,30 @@ func TestWorksheetWriter(t *testing.T) {
 	assert.Equal(t, fmt.Sprintf(worksheet, 2), string(value.([]byte)))
 }
 
+func TestSetRowSpans(t *testing.T) {
+	f := NewFile()
+	assert.NoError(t, f.SetSheetRow("Sheet1", "B1", &[]interface{}{1, 2, 3}))
+	assert.NoError(t, f.SetCellValue("Sheet1", "A2", 1))
+	assert.NoError(t, f.SetRowHeight("Sheet1", 3, 20))
+	// Test the spans attribute keeps correct after edit the cells
+	assert.NoError(t, f.RemoveCol("Sheet1", "A"))
+	f.workSheetWriter()
+	value, ok := f.Pkg.Load("xl/worksheets/sheet1.xml")
+	assert.True(t, ok)
+	assert.Contains(t, string(value.([]byte)), `<row r="1" spans="1:3">`)
+	assert.Contains(t, string(value.([]byte)), `<row r="2"></row><row r="3" ht="20" customHeight="true"></row>`)
+	// Test set the spans attribute with invalid cell reference
+	row := xlsxRow{Spans: "1:1", C: []xlsxC{{R: "A"}}}
+	setRowSpans(&row)
+	assert.Empty(t, row.Spans)
+	row = xlsxRow{C: []xlsxC{{R: "A1"}, {R: "B"}}}
+	setRowSpans(&row)
+	assert.Empty(t, row.Spans)
+	row = xlsxRow{C: []xlsxC{{R: "C1"}, {R: "A1"}}}
+	setRowSpans(&row)
+	assert.Equal(t, "1:3", row.Spans)
+}
+
 func TestGetWorkbookPath(t *testing.T) {
 	f := NewFile()
 	f.Pkg.Delete("_rels/.rels")
@@ -727,3 +940,110 @@ func TestSheetDimension(t *testing.T) {
 	assert.Empty(t, dimension)
 	assert.EqualError(t, err, "sheet SheetN does not exist")
 }
+
+func TestGetSheetUsedRange(t *testing.T) {
+	f := NewFile()
+	ref, err := f.GetSheetUsedRange("Sheet1")
+	assert.NoError(t, err)
+	assert.Empty(t, ref)
+	assert.NoError(t, f.SetCellValue("Sheet1", "C3", 1))
+	ref, err = f.GetSheetUsedRange("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, "C3", ref)
+	style, err := f.NewStyle(&Style{Fill: Fill{Type: "pattern", Color: []string{"FFFF00"}, Pattern: 1}})
+	assert.NoError(t, err)
+	assert.NoError(t, f.SetCellStyle("Sheet1", "B5", "B5", style))
+	assert.NoError(t, f.SetCellFormula("Sheet1", "E2", "C3*2"))
+	ref, err = f.GetSheetUsedRange("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, "B2:E5", ref)
+	// Test get the used range of the worksheet by the streaming XML decoder
+	assert.NoError(t, f.SetSheetDimension("Sheet1", "A1"))
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestGetSheetUsedRange.xlsx")))
+	assert.NoError(t, f.Close())
+	f, err = OpenFile(filepath.Join("test", "TestGetSheetUsedRange.xlsx"))
+	assert.NoError(t, err)
+	ref, err = f.GetSheetUsedRange("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, "B2:E5", ref)
+	dimension, err := f.GetSheetDimension("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, "A1", dimension)
+	assert.NoError(t, f.Close())
+	// Test get the used range with invalid sheet name
+	_, err = f.GetSheetUsedRange("Sheet:1")
+	assert.EqualError(t, err, ErrSheetNameInvalid.Error())
+	// Test get the used range on not exists worksheet
+	_, err = f.GetSheetUsedRange("SheetN")
+	assert.EqualError(t, err, "sheet SheetN does not exist")
+	// Test get the used range with invalid cell reference
+	f = NewFile()
+	f.Sheet.Delete("xl/worksheets/sheet1.xml")
+	f.Pkg.Store("xl/worksheets/sheet1.xml", []byte(fmt.Sprintf(`<worksheet xmlns="%s"><sheetData><row><c><v>1</v></c><c r="A"><v>1</v></c></row></sheetData></worksheet>`, NameSpaceSpreadSheet.Value)))
+	_, err = f.GetSheetUsedRange("Sheet1")
+	assert.Equal(t, newCellNameToCoordinatesError("A", newInvalidCellNameError("A")), err)
+	f.Pkg.Store("xl/worksheets/sheet1.xml", []byte(fmt.Sprintf(`<worksheet xmlns="%s"><sheetData><row><c><v>1</v></c><c r="B2"><v>1</v><v></c></row></sheetData></worksheet>`, NameSpaceSpreadSheet.Value)))
+	_, err = f.GetSheetUsedRange("Sheet1")
+	assert.Error(t, err)
+	f.Pkg.Store("xl/worksheets/sheet1.xml", []byte(fmt.Sprintf(`<worksheet xmlns="%s"><sheetData><row><c s="1"/></row><row r="3"><c r="C3"><v>1</v></c><c><f>A1</f></c></row></sheetData></worksheet>`, NameSpaceSpreadSheet.Value)))
+	ref, err = f.GetSheetUsedRange("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, "A1:D3", ref)
+	ws, err := f.workSheetReader("Sheet1")
+	assert.NoError(t, err)
+	ws.SheetData.Row[0].C[0].R = "A"
+	_, err = f.GetSheetUsedRange("Sheet1")
+	assert.Equal(t, newCellNameToCoordinatesError("A", newInvalidCellNameError("A")), err)
+}
+
+func TestGetMacroSheets(t *testing.T) {
+	f := NewFile()
+	sheets, err := f.GetMacroSheets()
+	assert.NoError(t, err)
+	assert.Empty(t, sheets)
+	wb, err := f.workbookReader()
+	assert.NoError(t, err)
+	for i, relType := range []string{SourceRelationshipMacrosheet, SourceRelationshipIntlMacrosheet} {
+		name := "macrosheets/sheet" + strconv.Itoa(i+1) + ".xml"
+		rID := f.addRels(f.getWorkbookRelsPath(), relType, name, "")
+		wb.Sheets.Sheet = append(wb.Sheets.Sheet, xlsxSheet{Name: "Macro" + strconv.Itoa(i+1), SheetID: i + 2, ID: "rId" + strconv.Itoa(rID), State: []string{"hidden", ""}[i]})
+	}
+	f.Pkg.Store("xl/macrosheets/sheet1.xml", []byte(`<xm:macrosheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:xm="http://schemas.microsoft.com/office/excel/2006/main"><sheetData><row r="2"><c r="A2"><f>EXEC("calc.exe")</f></c><c><f>HALT()</f><v>TRUE</v></c></row><row><c r="B3"/><c t="inlineStr"><is><t>text</t></is></c></row></sheetData></xm:macrosheet>`))
+	sheets, err = f.GetMacroSheets()
+	assert.NoError(t, err)
+	assert.Equal(t, []MacroSheet{
+		{
+			Name: "Macro1", Path: "xl/macrosheets/sheet1.xml",
+			Cells: []MacroSheetCell{
+				{Cell: "A2", Formula: `EXEC("calc.exe")`},
+				{Cell: "B2", Formula: "HALT()", Value: "TRUE"},
+				{Cell: "C3", Value: "text"},
+			},
+		},
+		{Name: "Macro2", Path: "xl/macrosheets/sheet2.xml", Visible: true, International: true},
+	}, sheets)
+	// Test get macro sheets with exceeds maximum row number
+	f.Pkg.Store("xl/macrosheets/sheet1.xml", []byte(`<xm:macrosheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:xm="http://schemas.microsoft.com/office/excel/2006/main"><sheetData><row r="1048577"><c><v>1</v></c></row></sheetData></xm:macrosheet>`))
+	_, err = f.GetMacroSheets()
+	assert.Equal(t, ErrMaxRows, err)
+	// Test get macro sheets with unsupported charset macro sheet
+	f.Pkg.Store("xl/macrosheets/sheet1.xml", MacintoshCyrillicCharset)
+	_, err = f.GetMacroSheets()
+	assert.EqualError(t, err, "XML syntax error on line 1: invalid UTF-8")
+	// Test get macro sheets with unsupported charset shared strings table
+	f.Pkg.Store("xl/macrosheets/sheet1.xml", []byte(`<macrosheet/>`))
+	f.SharedStrings = nil
+	f.Pkg.Store(defaultXMLPathSharedStrings, MacintoshCyrillicCharset)
+	_, err = f.GetMacroSheets()
+	assert.EqualError(t, err, "XML syntax error on line 1: invalid UTF-8")
+	// Test get macro sheets with unsupported charset workbook relationships
+	f.Relationships.Delete(defaultXMLPathWorkbookRels)
+	f.Pkg.Store(defaultXMLPathWorkbookRels, MacintoshCyrillicCharset)
+	_, err = f.GetMacroSheets()
+	assert.EqualError(t, err, "XML syntax error on line 1: invalid UTF-8")
+	// Test get macro sheets with unsupported charset workbook
+	f.WorkBook = nil
+	f.Pkg.Store(defaultXMLPathWorkbook, MacintoshCyrillicCharset)
+	_, err = f.GetMacroSheets()
+	assert.EqualError(t, err, "XML syntax error on line 1: invalid UTF-8")
+}
@@ -11,7 +11,13 @@
 
 package excelize_ch
 
-import "reflect"
+import (
+	"encoding/xml"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+)
 
 // SetPageMargins provides a function to set worksheet page margins.
 func (f *File) SetPageMargins(sheet string, opts *PageLayoutMarginsOptions) error {
@@ -155,7 +161,17 @@ func (ws *xlsxWorksheet) setSheetProps(opts *SheetPropsOptions) {
 	}
 }
 
-// SetSheetProps provides a function to set worksheet properties.
+// SetSheetProps provides a function to set worksheet properties. The custom
+// height will be enabled when the default row height was specified without
+// the custom height setting, so that the spreadsheet application will use the
+// given default row height instead of calculating it by the default font. For
+// example, set the default column width and row height of Sheet1:
+//
+//	width, height := 12.0, 18.0
+//	err := f.SetSheetProps("Sheet1", &excelize.SheetPropsOptions{
+//	    DefaultColWidth:  &width,
+//	    DefaultRowHeight: &height,
+//	})
 func (f *File) SetSheetProps(sheet string, opts *SheetPropsOptions) error {
 	ws, err := f.workSheetReader(sheet)
 	if err != nil {
@@ -164,10 +180,19 @@ func (f *File) SetSheetProps(sheet string, opts *SheetPropsOptions) error {
 	if opts == nil {
 		return err
 	}
+	if opts.DefaultColWidth != nil && (*opts.DefaultColWidth < 0 || *opts.DefaultColWidth > MaxColumnWidth) {
+		return ErrColumnWidth
+	}
+	if opts.DefaultRowHeight != nil && (*opts.DefaultRowHeight < 0 || *opts.DefaultRowHeight > MaxRowHeight) {
+		return ErrMaxRowHeight
+	}
 	ws.setSheetProps(opts)
 	if ws.SheetFormatPr == nil {
 		ws.SheetFormatPr = &xlsxSheetFormatPr{DefaultRowHeight: defaultRowHeight}
 	}
+	if opts.DefaultRowHeight != nil && opts.CustomHeight == nil {
+		ws.SheetFormatPr.CustomHeight = true
+	}
 	s := reflect.ValueOf(opts).Elem()
 	for i := 11; i < 18; i++ {
 		if !s.Field(i).IsNil() {
@@ -226,3 +251,158 @@ func (f *File) GetSheetProps(sheet string) (SheetPropsOptions, error) {
 	}
 	return opts, err
 }
+
+// SetSheetCustomProps provides a function to set the custom properties of the
+// worksheet by given worksheet name and the names and values of the
+// properties. The custom properties will be stored in the extension list of
+// the worksheet, and the other extensions in the list will be kept
+// untouched. The existing properties which are not given will be kept, and
+// the property will be deleted if the value is empty. For example, set the
+// custom properties of the worksheet named Sheet1:
+//
+//	err := f.SetSheetCustomProps("Sheet1", map[string]string{
+//	    "Owner":  "Finance",
+//	    "Source": "Sales Cube",
+//	})
+func (f *File) SetSheetCustomProps(sheet string, props map[string]string) error {
+	ws, err := f.workSheetReader(sheet)
+	if err != nil {
+		return err
+	}
+	ws.ExtLst, err = setCustomPropsExtLst(ws.ExtLst, props)
+	return err
+}
+
+// GetSheetCustomProps provides a function to get the names and values of the
+// custom properties stored in the extension list of the worksheet by given
+// worksheet name.
+func (f *File) GetSheetCustomProps(sheet string) (map[string]string, error) {
+	ws, err := f.workSheetReader(sheet)
+	if err != nil {
+		return map[string]string{}, err
+	}
+	return getCustomPropsExtLst(ws.ExtLst)
+}
+
+// rawExt directly maps the URI and the raw XML of the extension in the
+// extension list.
+type rawExt struct {
+	uri, content string
+}
+
+// splitExtLst provides a function to split the extension list into the raw
+// XML of each extension, so that the unknown extensions could be kept
+// untouched on writing the extension list.
+func splitExtLst(extLst *xlsxExtLst) ([]rawExt, error) {
+	var exts []rawExt
+	if extLst == nil {
+		return exts, nil
+	}
+	decoder := xml.NewDecoder(strings.NewReader(extLst.Ext))
+	for {
+		offset := decoder.InputOffset()
+		token, err := decoder.Token()
+		if err == io.EOF {
+			return exts, nil
+		}
+		if err != nil {
+			return exts, err
+		}
+		if se, ok := token.(xml.StartElement); ok {
+			var ext rawExt
+			for _, attr := range se.Attr {
+				if attr.Name.Local == "uri" {
+					ext.uri = attr.Value
+				}
+			}
+			if err = decoder.Skip(); err != nil {
+				return exts, err
+			}
+			ext.content = extLst.Ext[offset:decoder.InputOffset()]
+			exts = append(exts, ext)
+		}
+	}
+}
+
+// getCustomPropsExtLst provides a function to get the names and values of the
+// custom properties by given extension list.
+func getCustomPropsExtLst(extLst *xlsxExtLst) (map[string]string, error) {
+	props := map[string]string{}
+	exts, err := splitExtLst(extLst)
+	if err != nil {
+		return props, err
+	}
+	for _, ext := range exts {
+		if ext.uri != ExtURICustomProps {
+			continue
+		}
+		var decodeProps decodeCustomProps
+		if err = xml.Unmarshal([]byte(ext.content), &decodeProps); err != nil {
+			return props, err
+		}
+		for _, prop := range decodeProps.CustomProps {
+			props[prop.Name] = prop.Value
+		}
+	}
+	return props, err
+}
+
+// setCustomPropsExtLst provides a function to set the custom properties in
+// the extension list by given extension list and the names and values of the
+// properties, and returns the new extension list.
+func setCustomPropsExtLst(extLst *xlsxExtLst, props map[string]string) (*xlsxExtLst, error) {
+	exts, err := splitExtLst(extLst)
+	if err != nil {
+		return extLst, err
+	}
+	idx, customProps := -1, xlsxCustomProps{XMLNSXCP: NameSpaceCustomProps.Value}
+	for i, ext := range exts {
+		if ext.uri == ExtURICustomProps {
+			var decodeProps decodeCustomProps
+			if err = xml.Unmarshal([]byte(ext.content), &decodeProps); err != nil {
+				return extLst, err
+			}
+			idx, customProps.CustomProps = i, decodeProps.CustomProps
+		}
+	}
+	names := make([]string, 0, len(props))
+	for name := range props {
+		if name == "" {
+			return extLst, ErrParameterInvalid
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		i := 0
+		for ; i < len(customProps.CustomProps) && customProps.CustomProps[i].Name != name; i++ {
+		}
+		if i == len(customProps.CustomProps) {
+			if props[name] != "" {
+				customProps.CustomProps = append(customProps.CustomProps, xlsxCustomProp{Name: name, Value: props[name]})
+			}
+			continue
+		}
+		if customProps.CustomProps[i].Value = props[name]; props[name] == "" {
+			customProps.CustomProps = append(customProps.CustomProps[:i], customProps.CustomProps[i+1:]...)
+		}
+	}
+	var ext rawExt
+	if len(customProps.CustomProps) > 0 {
+		content, _ := xml.Marshal(customProps)
+		ext = rawExt{uri: ExtURICustomProps, content: "<ext uri=\"" + ExtURICustomProps + "\">" + string(content) + "</ext>"}
+	}
+	if idx == -1 {
+		exts = append(exts, ext)
+	} else {
+		exts[idx] = ext
+	}
+	var buf strings.Builder
+	for _, ext := range exts {
+		buf.WriteString(ext.content)
+	}
+	if buf.Len() == 0 {
+		return nil, err
+	}
+	return &xlsxExtLst{Ext: buf.String()}, err
+}
@@ -1,6 +1,8 @@
 package excelize_ch
 
 import (
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -84,6 +86,16 @@ func TestSetSheetProps(t *testing.T) {
 	assert.NoError(t, f.SetSheetProps("Sheet1", &SheetPropsOptions{TabColorTheme: intPtr(1)}))
 	ws.(*xlsxWorksheet).SheetPr = nil
 	assert.NoError(t, f.SetSheetProps("Sheet1", &SheetPropsOptions{TabColorTint: float64Ptr(1)}))
+	// Test set default row height without custom height
+	ws.(*xlsxWorksheet).SheetFormatPr = nil
+	assert.NoError(t, f.SetSheetProps("Sheet1", &SheetPropsOptions{DefaultRowHeight: float64Ptr(20)}))
+	opts, err = f.GetSheetProps("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, 20.0, *opts.DefaultRowHeight)
+	assert.True(t, *opts.CustomHeight)
+	// Test set worksheet properties with invalid default column width and row height
+	assert.Equal(t, ErrColumnWidth, f.SetSheetProps("Sheet1", &SheetPropsOptions{DefaultColWidth: float64Ptr(MaxColumnWidth + 1)}))
+	assert.Equal(t, ErrMaxRowHeight, f.SetSheetProps("Sheet1", &SheetPropsOptions{DefaultRowHeight: float64Ptr(MaxRowHeight + 1)}))
 
 	// Test set worksheet properties on not exists worksheet
 	assert.EqualError(t, f.SetSheetProps("SheetN", nil), "sheet SheetN does not exist")
@@ -100,3 +112,60 @@ func TestGetSheetProps(t *testing.T) {
 	_, err = f.GetSheetProps("Sheet:1")
 	assert.Equal(t, ErrSheetNameInvalid, err)
 }
+
+func TestSheetCustomProps(t *testing.T) {
+	f := NewFile()
+	props, err := f.GetSheetCustomProps("Sheet1")
+	assert.NoError(t, err)
+	assert.Empty(t, props)
+	// Test set custom properties with the unknown extension preserved
+	ws, ok := f.Sheet.Load("xl/worksheets/sheet1.xml")
+	assert.True(t, ok)
+	unknownExt := `<ext xmlns:x="urn:unknown" uri="{00000000-0000-0000-0000-000000000000}"><x:data a="1"/></ext>`
+	ws.(*xlsxWorksheet).ExtLst = &xlsxExtLst{Ext: unknownExt}
+	assert.NoError(t, f.SetSheetCustomProps("Sheet1", map[string]string{"Owner": "Finance", "Source": "Cube"}))
+	assert.True(t, strings.HasPrefix(ws.(*xlsxWorksheet).ExtLst.Ext, unknownExt))
+	props, err = f.GetSheetCustomProps("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"Owner": "Finance", "Source": "Cube"}, props)
+	// Test update and delete custom properties
+	assert.NoError(t, f.SetSheetCustomProps("Sheet1", map[string]string{"Owner": "Sales", "Source": ""}))
+	props, err = f.GetSheetCustomProps("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"Owner": "Sales"}, props)
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestSheetCustomProps.xlsx")))
+	assert.NoError(t, f.Close())
+
+	f, err = OpenFile(filepath.Join("test", "TestSheetCustomProps.xlsx"))
+	assert.NoError(t, err)
+	props, err = f.GetSheetCustomProps("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"Owner": "Sales"}, props)
+	// Test delete all custom properties keeps the unknown extension
+	assert.NoError(t, f.SetSheetCustomProps("Sheet1", map[string]string{"Owner": ""}))
+	ws, ok = f.Sheet.Load("xl/worksheets/sheet1.xml")
+	assert.True(t, ok)
+	assert.NotContains(t, ws.(*xlsxWorksheet).ExtLst.Ext, ExtURICustomProps)
+	assert.Contains(t, ws.(*xlsxWorksheet).ExtLst.Ext, "{00000000-0000-0000-0000-000000000000}")
+	// Test delete all custom properties without other extensions
+	ws.(*xlsxWorksheet).ExtLst = nil
+	assert.NoError(t, f.SetSheetCustomProps("Sheet1", map[string]string{"Owner": "Sales"}))
+	assert.NoError(t, f.SetSheetCustomProps("Sheet1", map[string]string{"Owner": ""}))
+	assert.Nil(t, ws.(*xlsxWorksheet).ExtLst)
+	// Test set custom properties with invalid property name
+	assert.Equal(t, ErrParameterInvalid, f.SetSheetCustomProps("Sheet1", map[string]string{"": "value"}))
+	// Test set and get custom properties with invalid extension list
+	ws.(*xlsxWorksheet).ExtLst = &xlsxExtLst{Ext: "<ext"}
+	assert.Error(t, f.SetSheetCustomProps("Sheet1", map[string]string{"Owner": "Sales"}))
+	_, err = f.GetSheetCustomProps("Sheet1")
+	assert.Error(t, err)
+	ws.(*xlsxWorksheet).ExtLst = &xlsxExtLst{Ext: `<ext uri="` + ExtURICustomProps + `"><customProps><customProp name="a" value="1"></customProps></ext>`}
+	assert.Error(t, f.SetSheetCustomProps("Sheet1", map[string]string{"Owner": "Sales"}))
+	_, err = f.GetSheetCustomProps("Sheet1")
+	assert.Error(t, err)
+	// Test set and get custom properties on not exists worksheet
+	assert.EqualError(t, f.SetSheetCustomProps("SheetN", nil), "sheet SheetN does not exist")
+	_, err = f.GetSheetCustomProps("SheetN")
+	assert.EqualError(t, err, "sheet SheetN does not exist")
+	assert.NoError(t, f.Close())
+}
@@ -11,6 +11,8 @@
 
 package excelize_ch
 
+import "math"
+
 // getSheetView returns the SheetView object
 func (f *File) getSheetView(sheet string, viewIndex int) (*xlsxSheetView, error) {
 	ws, err := f.workSheetReader(sheet)
@@ -48,12 +50,18 @@ func (view *xlsxSheetView) setSheetView(opts *ViewOptions) {
 	if opts.ShowGridLines != nil {
 		view.ShowGridLines = opts.ShowGridLines
 	}
+	if opts.ShowOutlineSymbols != nil {
+		view.ShowOutlineSymbols = opts.ShowOutlineSymbols
+	}
 	if opts.ShowRowColHeaders != nil {
 		view.ShowRowColHeaders = opts.ShowRowColHeaders
 	}
 	if opts.ShowRuler != nil {
 		view.ShowRuler = opts.ShowRuler
 	}
+	if opts.ShowWhiteSpace != nil {
+		view.ShowWhiteSpace = opts.ShowWhiteSpace
+	}
 	if opts.ShowZeros != nil {
 		view.ShowZeros = opts.ShowZeros
 	}
@@ -61,9 +69,7 @@ func (view *xlsxSheetView) setSheetView(opts *ViewOptions) {
 		view.TopLeftCell = *opts.TopLeftCell
 	}
 	if opts.View != nil {
-		if inStrSlice([]string{"normal", "pageLayout", "pageBreakPreview"}, *opts.View, true) != -1 {
-			view.View = *opts.View
-		}
+		view.View = *opts.View
 	}
 	if opts.ZoomScale != nil && *opts.ZoomScale >= 10 && *opts.ZoomScale <= 400 {
 		view.ZoomScale = *opts.ZoomScale
@@ -71,7 +77,17 @@ func (view *xlsxSheetView) setSheetView(opts *ViewOptions) {
 }
 
 // SetSheetView sets sheet view options. The viewIndex may be negative and if
-// so is counted backward (-1 is the last view).
+// so is counted backward (-1 is the last view). For example, hide the grid
+// lines, row and column headers, and zero values of the first view in Sheet1,
+// and display it in page layout view:
+//
+//	show, view := false, "pageLayout"
+//	err := f.SetSheetView("Sheet1", 0, &excelize.ViewOptions{
+//	    ShowGridLines:     &show,
+//	    ShowRowColHeaders: &show,
+//	    ShowZeros:         &show,
+//	    View:              &view,
+//	})
 func (f *File) SetSheetView(sheet string, viewIndex int, opts *ViewOptions) error {
 	view, err := f.getSheetView(sheet, viewIndex)
 	if err != nil {
@@ -80,6 +96,9 @@ func (f *File) SetSheetView(sheet string, viewIndex int, opts *ViewOptions) erro
 	if opts == nil {
 		return err
 	}
+	if opts.View != nil && inStrSlice([]string{"normal", "pageLayout", "pageBreakPreview"}, *opts.View, true) == -1 {
+		return ErrSheetViewType
+	}
 	view.setSheetView(opts)
 	return nil
 }
@@ -88,14 +107,16 @@ func (f *File) SetSheetView(sheet string, viewIndex int, opts *ViewOptions) erro
 // negative and if so is counted backward (-1 is the last view).
 func (f *File) GetSheetView(sheet string, viewIndex int) (ViewOptions, error) {
 	opts := ViewOptions{
-		DefaultGridColor:  boolPtr(true),
-		ShowFormulas:      boolPtr(true),
-		ShowGridLines:     boolPtr(true),
-		ShowRowColHeaders: boolPtr(true),
-		ShowRuler:         boolPtr(true),
-		ShowZeros:         boolPtr(true),
-		View:              stringPtr("normal"),
-		ZoomScale:         float64Ptr(100),
+		DefaultGridColor:   boolPtr(true),
+		ShowFormulas:       boolPtr(true),
+		ShowGridLines:      boolPtr(true),
+		ShowOutlineSymbols: boolPtr(true),
+		ShowRowColHeaders:  boolPtr(true),
+		ShowRuler:          boolPtr(true),
+		ShowWhiteSpace:     boolPtr(true),
+		ShowZeros:          boolPtr(true),
+		View:               stringPtr("normal"),
+		ZoomScale:          float64Ptr(100),
 	}
 	view, err := f.getSheetView(sheet, viewIndex)
 	if err != nil {
@@ -109,12 +130,18 @@ func (f *File) GetSheetView(sheet string, viewIndex int) (ViewOptions, error) {
 	if view.ShowGridLines != nil {
 		opts.ShowGridLines = view.ShowGridLines
 	}
+	if view.ShowOutlineSymbols != nil {
+		opts.ShowOutlineSymbols = view.ShowOutlineSymbols
+	}
 	if view.ShowRowColHeaders != nil {
 		opts.ShowRowColHeaders = view.ShowRowColHeaders
 	}
 	if view.ShowRuler != nil {
 		opts.ShowRuler = view.ShowRuler
 	}
+	if view.ShowWhiteSpace != nil {
+		opts.ShowWhiteSpace = view.ShowWhiteSpace
+	}
 	if view.ShowZeros != nil {
 		opts.ShowZeros = view.ShowZeros
 	}
@@ -127,3 +154,58 @@ func (f *File) GetSheetView(sheet string, viewIndex int) (ViewOptions, error) {
 	}
 	return opts, err
 }
+
+// SetSheetViewZoomToRange provides a function to zoom the sheet view to fit
+// the given range in the workbook window, like the "Zoom to Selection" in
+// Office Excel. The zoom scale will be calculated by the size of the window
+// of the first workbook view and the width of columns and height of rows in
+// the range, and restricted to values ranging from 10 to 400. The view will
+// scroll to the range and select it. The viewIndex may be negative and if so
+// is counted backward (-1 is the last view). For example, zoom the first
+// view of Sheet1 to fit the range A1:H20:
+//
+//	err := f.SetSheetViewZoomToRange("Sheet1", 0, "A1:H20")
+func (f *File) SetSheetViewZoomToRange(sheet string, viewIndex int, rangeRef string) error {
+	coordinates, err := rangeRefToCoordinates(rangeRef)
+	if err != nil {
+		return err
+	}
+	_ = sortCoordinates(coordinates)
+	view, err := f.getSheetView(sheet, viewIndex)
+	if err != nil {
+		return err
+	}
+	wb, err := f.workbookReader()
+	if err != nil {
+		return err
+	}
+	// The size of the window measured in twips, 15 twips per pixel
+	windowWidth, windowHeight := 14805, 8010
+	if wb.BookViews != nil && len(wb.BookViews.WorkBookView) > 0 {
+		if wbView := wb.BookViews.WorkBookView[0]; wbView.WindowWidth > 0 && wbView.WindowHeight > 0 {
+			windowWidth, windowHeight = wbView.WindowWidth, wbView.WindowHeight
+		}
+	}
+	var width, height int
+	for col := coordinates[0]; col <= coordinates[2]; col++ {
+		width += f.getColWidth(sheet, col)
+	}
+	for row := coordinates[1]; row <= coordinates[3]; row++ {
+		height += f.getRowHeight(sheet, row)
+	}
+	zoomScale := 400.0
+	if width > 0 {
+		zoomScale = math.Min(zoomScale, float64(windowWidth)/15/float64(width)*100)
+	}
+	if height > 0 {
+		zoomScale = math.Min(zoomScale, float64(windowHeight)/15/float64(height)*100)
+	}
+	view.ZoomScale = math.Max(math.Floor(zoomScale), 10)
+	topLeftCell, _ := CoordinatesToCellName(coordinates[0], coordinates[1])
+	ref, _ := f.coordinatesToRangeRef(coordinates)
+	view.TopLeftCell = topLeftCell
+	if view.Pane == nil {
+		view.Selection = []*xlsxSelection{{ActiveCell: topLeftCell, SQRef: ref}}
+	}
+	return err
+}
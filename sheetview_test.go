@@ -13,21 +13,35 @@ func TestSetView(t *testing.T) {
 	assert.True(t, ok)
 	ws.(*xlsxWorksheet).SheetViews = nil
 	expected := ViewOptions{
-		DefaultGridColor:  boolPtr(false),
-		RightToLeft:       boolPtr(false),
-		ShowFormulas:      boolPtr(false),
-		ShowGridLines:     boolPtr(false),
-		ShowRowColHeaders: boolPtr(false),
-		ShowRuler:         boolPtr(false),
-		ShowZeros:         boolPtr(false),
-		TopLeftCell:       stringPtr("A1"),
-		View:              stringPtr("normal"),
-		ZoomScale:         float64Ptr(120),
+		DefaultGridColor:   boolPtr(false),
+		RightToLeft:        boolPtr(false),
+		ShowFormulas:       boolPtr(false),
+		ShowGridLines:      boolPtr(false),
+		ShowOutlineSymbols: boolPtr(false),
+		ShowRowColHeaders:  boolPtr(false),
+		ShowRuler:          boolPtr(false),
+		ShowWhiteSpace:     boolPtr(false),
+		ShowZeros:          boolPtr(false),
+		TopLeftCell:        stringPtr("A1"),
+		View:               stringPtr("normal"),
+		ZoomScale:          float64Ptr(120),
 	}
 	assert.NoError(t, f.SetSheetView("Sheet1", 0, &expected))
 	opts, err := f.GetSheetView("Sheet1", 0)
 	assert.NoError(t, err)
 	assert.Equal(t, expected, opts)
+	// Test read back sheet view options from the saved workbook
+	assert.NoError(t, f.SetSheetView("Sheet1", 0, &ViewOptions{View: stringPtr("pageBreakPreview")}))
+	expected.View = stringPtr("pageBreakPreview")
+	buf, err := f.WriteToBuffer()
+	assert.NoError(t, err)
+	f, err = OpenReader(buf)
+	assert.NoError(t, err)
+	opts, err = f.GetSheetView("Sheet1", 0)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, opts)
+	// Test set sheet view options with invalid view type
+	assert.Equal(t, ErrSheetViewType, f.SetSheetView("Sheet1", 0, &ViewOptions{View: stringPtr("unknown")}))
 	// Test set sheet view options with invalid view index
 	assert.EqualError(t, f.SetSheetView("Sheet1", 1, nil), "view index 1 out of range")
 	assert.EqualError(t, f.SetSheetView("Sheet1", -2, nil), "view index -2 out of range")
@@ -48,3 +62,41 @@ func TestGetView(t *testing.T) {
 	_, err = f.GetSheetView("SheetN", 0)
 	assert.EqualError(t, err, "sheet SheetN does not exist")
 }
+
+func TestSetSheetViewZoomToRange(t *testing.T) {
+	f := NewFile()
+	assert.NoError(t, f.SetSheetViewZoomToRange("Sheet1", 0, "H20:A1"))
+	opts, err := f.GetSheetView("Sheet1", 0)
+	assert.NoError(t, err)
+	// The window is 987 x 534 pixels, and the range is 512 x 360 pixels
+	assert.Equal(t, 148.0, *opts.ZoomScale)
+	assert.Equal(t, "A1", *opts.TopLeftCell)
+	ws, err := f.workSheetReader("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, []*xlsxSelection{{ActiveCell: "A1", SQRef: "A1:H20"}}, ws.SheetViews.SheetView[0].Selection)
+	// Test zoom to the range which is larger than the window
+	assert.NoError(t, f.SetColWidth("Sheet1", "A", "A", 255))
+	assert.NoError(t, f.SetSheetViewZoomToRange("Sheet1", 0, "A1:B2"))
+	opts, err = f.GetSheetView("Sheet1", 0)
+	assert.NoError(t, err)
+	assert.Equal(t, 53.0, *opts.ZoomScale)
+	// Test zoom to a single cell by the window size of the workbook view
+	wb, err := f.workbookReader()
+	assert.NoError(t, err)
+	wb.BookViews.WorkBookView[0].WindowWidth, wb.BookViews.WorkBookView[0].WindowHeight = 30000, 15000
+	assert.NoError(t, f.SetSheetViewZoomToRange("Sheet1", 0, "C3:C3"))
+	opts, err = f.GetSheetView("Sheet1", 0)
+	assert.NoError(t, err)
+	assert.Equal(t, 400.0, *opts.ZoomScale)
+	assert.Equal(t, "C3", *opts.TopLeftCell)
+	// Test zoom to range with invalid range reference
+	assert.Equal(t, ErrParameterInvalid, f.SetSheetViewZoomToRange("Sheet1", 0, "A"))
+	// Test zoom to range with invalid view index
+	assert.EqualError(t, f.SetSheetViewZoomToRange("Sheet1", 1, "A1:B2"), "view index 1 out of range")
+	// Test zoom to range on not exists worksheet
+	assert.EqualError(t, f.SetSheetViewZoomToRange("SheetN", 0, "A1:B2"), "sheet SheetN does not exist")
+	// Test zoom to range with unsupported charset workbook
+	f.WorkBook = nil
+	f.Pkg.Store(defaultXMLPathWorkbook, MacintoshCyrillicCharset)
+	assert.EqualError(t, f.SetSheetViewZoomToRange("Sheet1", 0, "A1:B2"), "XML syntax error on line 1: invalid UTF-8")
+}
@@ -181,7 +181,7 @@ func (f *File) getSlicerSource(opts *SlicerOptions) (*Table, *PivotTableOptions,
 		pivotTable  *PivotTableOptions
 		colIdx      int
 		err         error
-		dataRange   string
+		dataSource  = &PivotTableOptions{}
 		tables      []Table
 		pivotTables []PivotTableOptions
 	)
@@ -191,7 +191,7 @@ func (f *File) getSlicerSource(opts *SlicerOptions) (*Table, *PivotTableOptions,
 	for _, tbl := range tables {
 		if tbl.Name == opts.TableName {
 			table = &tbl
-			dataRange = fmt.Sprintf("%s!%s", opts.TableSheet, tbl.Range)
+			dataSource.DataRange = fmt.Sprintf("%s!%s", opts.TableSheet, tbl.Range)
 			break
 		}
 	}
@@ -202,7 +202,8 @@ func (f *File) getSlicerSource(opts *SlicerOptions) (*Table, *PivotTableOptions,
 		for _, tbl := range pivotTables {
 			if tbl.Name == opts.TableName {
 				pivotTable = &tbl
-				dataRange = tbl.DataRange
+				dataSource.DataRange, dataSource.DataSource = tbl.DataRange, tbl.DataSource
+				dataSource.CacheSourceType = tbl.CacheSourceType
 				break
 			}
 		}
@@ -210,7 +211,7 @@ func (f *File) getSlicerSource(opts *SlicerOptions) (*Table, *PivotTableOptions,
 			return table, pivotTable, colIdx, newNoExistTableError(opts.TableName)
 		}
 	}
-	order, _ := f.getTableFieldsOrder(&PivotTableOptions{DataRange: dataRange})
+	order, _ := f.getTableFieldsOrder(dataSource)
 	if colIdx = inStrSlice(order, opts.Name, true); colIdx == -1 {
 		return table, pivotTable, colIdx, newInvalidSlicerNameError(opts.Name)
 	}
@@ -349,8 +350,9 @@ func (f *File) genSlicerName(name string) string {
 	return slicerName
 }
 
-// genSlicerNames generates a unique slicer cache name by giving the slicer name.
-func (f *File) genSlicerCacheName(name string) string {
+// genSlicerCacheName generates a unique slicer or timeline cache name by giving
+// the prefix of the cache name and the slicer or timeline name.
+func (f *File) genSlicerCacheName(prefix, name string) string {
 	var (
 		cnt             int
 		definedNames    []string
@@ -372,7 +374,7 @@ func (f *File) genSlicerCacheName(name string) string {
 		}
 		slicerCacheName += "_"
 	}
-	slicerCacheName = fmt.Sprintf("Slicer_%s", slicerCacheName)
+	slicerCacheName = prefix + slicerCacheName
 	for {
 		tmp := slicerCacheName
 		if cnt > 0 {
@@ -427,7 +429,7 @@ func (f *File) setSlicerCache(sheet string, colIdx int, opts *SlicerOptions, tab
 	if ok {
 		return slicerCacheName, nil
 	}
-	slicerCacheName = f.genSlicerCacheName(opts.Name)
+	slicerCacheName = f.genSlicerCacheName("Slicer_", opts.Name)
 	return slicerCacheName, f.addSlicerCache(slicerCacheName, colIdx, opts, table, pivotTable)
 }
 
@@ -571,8 +573,8 @@ func (f *File) addPivotCacheSlicer(opts *PivotTableOptions) (int, error) {
 	return pivotCacheID, err
 }
 
-// addDrawingSlicer adds a slicer shape and fallback shape by giving the
-// worksheet name, slicer name, and slicer options.
+// addDrawingSlicer adds a slicer or timeline shape and fallback shape by giving
+// the worksheet name, slicer name, namespace, and slicer options.
 func (f *File) addDrawingSlicer(sheet, slicerName string, ns xml.Attr, opts *SlicerOptions) error {
 	drawingID := f.countDrawings() + 1
 	drawingXML := "xl/drawings/drawing" + strconv.Itoa(drawingID) + ".xml"
@@ -600,6 +602,17 @@ func (f *File) addDrawingSlicer(sheet, slicerName string, ns xml.Attr, opts *Sli
 			},
 		},
 	}
+	fallbackText := []*aP{
+		{R: []*aR{{T: "This shape represents a table slicer. Table slicers are not supported in this version of Excel."}}},
+		{R: []*aR{{T: "If the shape was modified in an earlier version of Excel, or if the workbook was saved in Excel 2007 or earlier, the slicer can't be used."}}},
+	}
+	if ns.Value == NameSpaceDrawingMLTimeslicer.Value { // timeline
+		graphicFrame.Graphic.GraphicData = &xlsxGraphicData{
+			URI:  NameSpaceDrawingMLTimeslicer.Value,
+			Tsle: &xlsxTsle{XMLNS: NameSpaceDrawingMLTimeslicer.Value, Name: slicerName},
+		}
+		fallbackText = []*aP{{R: []*aR{{T: "Timeline: Works in Excel 2013 or higher. Do not move or resize."}}}}
+	}
 	graphic, _ := xml.Marshal(graphicFrame)
 	sp := xdrSp{
 		Macro: opts.Macro,
@@ -621,10 +634,7 @@ func (f *File) addDrawingSlicer(sheet, slicerName string, ns xml.Attr, opts *Sli
 		},
 		TxBody: &xdrTxBody{
 			BodyPr: &aBodyPr{VertOverflow: "clip", HorzOverflow: "clip"},
-			P: []*aP{
-				{R: &aR{T: "This shape represents a table slicer. Table slicers are not supported in this version of Excel."}},
-				{R: &aR{T: "If the shape was modified in an earlier version of Excel, or if the workbook was saved in Excel 2007 or earlier, the slicer can't be used."}},
-			},
+			P:      fallbackText,
 		},
 	}
 	shape, _ := xml.Marshal(sp)
@@ -639,6 +649,9 @@ func (f *File) addDrawingSlicer(sheet, slicerName string, ns xml.Attr, opts *Sli
 	if ns.Value == NameSpaceDrawingMLSlicerX15.Value { // table slicer
 		choice.XMLNSSle15 = ns.Value
 	}
+	if ns.Value == NameSpaceDrawingMLTimeslicer.Value { // timeline
+		choice.XMLNSTsle = ns.Value
+	}
 	fallback := xlsxFallback{Content: string(shape)}
 	choiceBytes, _ := xml.Marshal(choice)
 	shapeBytes, _ := xml.Marshal(fallback)
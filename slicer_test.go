@@ -116,6 +116,25 @@ func TestAddSlicer(t *testing.T) {
 		Caption:    "Region",
 		ItemDesc:   true,
 	}))
+	// Test add a pivot table slicer for the pivot table with cached dataset
+	_, err = f.NewSheet("Sheet3")
+	assert.NoError(t, err)
+	assert.NoError(t, f.AddPivotTable(&PivotTableOptions{
+		DataSource: [][]interface{}{
+			{"Region", "Sales"}, {"East", 100}, {"West", 200},
+		},
+		PivotTableRange: "Sheet3!A1:B4",
+		Name:            "PivotTable3",
+		Rows:            []PivotTableField{{Data: "Region"}},
+		Data:            []PivotTableField{{Data: "Sales"}},
+	}))
+	assert.NoError(t, f.AddSlicer("Sheet3", &SlicerOptions{
+		Name:       "Region",
+		Cell:       "D1",
+		TableSheet: "Sheet3",
+		TableName:  "PivotTable3",
+		Caption:    "Region",
+	}))
 	// Test add a table slicer with empty slicer options
 	assert.Equal(t, ErrParameterRequired, f.AddSlicer("Sheet1", nil))
 	// Test add a table slicer with invalid slicer options
@@ -370,7 +389,7 @@ func TestAddWorkbookSlicerCache(t *testing.T) {
 func TestGenSlicerCacheName(t *testing.T) {
 	f := NewFile()
 	assert.NoError(t, f.SetDefinedName(&DefinedName{Name: "Slicer_Column_1", RefersTo: formulaErrorNA}))
-	assert.Equal(t, "Slicer_Column_11", f.genSlicerCacheName("Column 1"))
+	assert.Equal(t, "Slicer_Column_11", f.genSlicerCacheName("Slicer_", "Column 1"))
 	assert.NoError(t, f.Close())
 }
 
@@ -374,21 +374,31 @@ func (f *File) addSparklineGroupByStyle(ID int) *xlsxX14SparklineGroup {
 //
 // The following shows the formatting options of sparkline supported by excelize:
 //
-//	 Parameter   | Description
-//	-------------+--------------------------------------------
-//	 Location    | Required, must have the same number with 'Range' parameter
-//	 Range       | Required, must have the same number with 'Location' parameter
-//	 Type        | Enumeration value: line, column, win_loss
-//	 Style       | Value range: 0 - 35
-//	 Hight       | Toggle sparkline high points
-//	 Low         | Toggle sparkline low points
-//	 First       | Toggle sparkline first points
-//	 Last        | Toggle sparkline last points
-//	 Negative    | Toggle sparkline negative points
-//	 Markers     | Toggle sparkline markers
-//	 Axis        | Used to specify if show horizontal axis
-//	 Reverse     | Used to specify if enable plot data right-to-left
-//	 SeriesColor | An RGB Color is specified as RRGGBB
+//	 Parameter     | Description
+//	---------------+--------------------------------------------
+//	 Location      | Required, must have the same number with 'Range' parameter
+//	 Range         | Required, must have the same number with 'Location' parameter
+//	 Type          | Enumeration value: line, column, win_loss
+//	 Style         | Value range: 0 - 35
+//	 Hight         | Toggle sparkline high points
+//	 Low           | Toggle sparkline low points
+//	 First         | Toggle sparkline first points
+//	 Last          | Toggle sparkline last points
+//	 Negative      | Toggle sparkline negative points
+//	 Markers       | Toggle sparkline markers
+//	 Axis          | Used to specify if show horizontal axis
+//	 Reverse       | Used to specify if enable plot data right-to-left
+//	 DateAxis      | Used to specify if use the date axis type
+//	 Hidden        | Used to specify if show data in hidden rows and columns
+//	 Weight        | The line weight of the line sparklines in points
+//	 EmptyCells    | Enumeration value: gap, zero, span, default is gap
+//	 SeriesColor   | An RGB Color is specified as RRGGBB
+//	 NegativeColor | An RGB Color of the negative points specified as RRGGBB
+//	 MarkersColor  | An RGB Color of the markers specified as RRGGBB
+//	 FirstColor    | An RGB Color of the first point specified as RRGGBB
+//	 LastColor     | An RGB Color of the last point specified as RRGGBB
+//	 HightColor    | An RGB Color of the high point specified as RRGGBB
+//	 LowColor      | An RGB Color of the low point specified as RRGGBB
 func (f *File) AddSparkline(sheet string, opts *SparklineOptions) error {
 	var (
 		err                 error
@@ -426,9 +436,26 @@ func (f *File) AddSparkline(sheet string, opts *SparklineOptions) error {
 	group.Negative = opts.Negative
 	group.DisplayXAxis = opts.Axis
 	group.Markers = opts.Markers
-	if opts.SeriesColor != "" {
-		group.ColorSeries = &xlsxColor{
-			RGB: getPaletteColor(opts.SeriesColor),
+	group.DateAxis = opts.DateAxis
+	group.DisplayHidden = opts.Hidden
+	group.LineWeight = opts.Weight
+	if opts.EmptyCells != "" {
+		group.DisplayEmptyCellsAs = opts.EmptyCells
+	}
+	for _, color := range []struct {
+		value string
+		color **xlsxColor
+	}{
+		{opts.SeriesColor, &group.ColorSeries},
+		{opts.NegativeColor, &group.ColorNegative},
+		{opts.MarkersColor, &group.ColorMarkers},
+		{opts.FirstColor, &group.ColorFirst},
+		{opts.LastColor, &group.ColorLast},
+		{opts.HightColor, &group.ColorHigh},
+		{opts.LowColor, &group.ColorLow},
+	} {
+		if color.value != "" {
+			*color.color = &xlsxColor{RGB: getPaletteColor(color.value)}
 		}
 	}
 	if opts.Reverse {
@@ -535,3 +562,222 @@ func (f *File) appendSparkline(ws *xlsxWorksheet, group *xlsxX14SparklineGroup,
 	ws.ExtLst = &xlsxExtLst{Ext: strings.TrimSuffix(strings.TrimPrefix(string(extLstBytes), "<extLst>"), "</extLst>")}
 	return err
 }
+
+// GetSparklines provides a function to get the sparkline groups of the
+// worksheet by given worksheet name. Each returned options represents a
+// sparkline group, and the Location and Range fields contains the cells and
+// the data ranges of the sparklines in the group. The Style field will be
+// detected by the point colors of the group, and the colors which not
+// specified by the style will be returned as RGB colors. For example, get the
+// sparklines in the worksheet named 'Sheet1':
+//
+//	sparklines, err := f.GetSparklines("Sheet1")
+func (f *File) GetSparklines(sheet string) ([]SparklineOptions, error) {
+	var sparklines []SparklineOptions
+	groups, err := f.getSparklineGroups(sheet)
+	if err != nil {
+		return sparklines, err
+	}
+	for _, group := range groups {
+		opts := SparklineOptions{
+			Type:       group.Type,
+			Weight:     group.LineWeight,
+			DateAxis:   group.DateAxis,
+			Markers:    group.Markers,
+			High:       group.High,
+			Low:        group.Low,
+			First:      group.First,
+			Last:       group.Last,
+			Negative:   group.Negative,
+			Axis:       group.DisplayXAxis,
+			Hidden:     group.DisplayHidden,
+			Reverse:    group.RightToLeft,
+			EmptyCells: group.DisplayEmptyCellsAs,
+		}
+		if opts.Type == "" {
+			opts.Type = "line"
+		}
+		if opts.Type == "stacked" {
+			opts.Type = "win_loss"
+		}
+		if group.MaxAxisType == "custom" {
+			opts.CustMax = group.ManualMax
+		}
+		if group.MinAxisType == "custom" {
+			opts.CustMin = group.ManualMin
+		}
+		f.extractSparklineColors(group, &opts)
+		for _, sparkline := range group.Sparklines.Sparkline {
+			opts.Location = append(opts.Location, sparkline.Sqref)
+			opts.Range = append(opts.Range, sparkline.F)
+		}
+		sparklines = append(sparklines, opts)
+	}
+	return sparklines, err
+}
+
+// extractSparklineColors provides a function to detect the style ID of the
+// sparkline group by the point colors, and extract the colors which not
+// specified by the style.
+func (f *File) extractSparklineColors(group *decodeX14SparklineGroup, opts *SparklineOptions) {
+	style := -1
+	for ID := 0; ID <= 35; ID++ {
+		preset := f.addSparklineGroupByStyle(ID)
+		if sparklineColorEqual(preset.ColorNegative, group.ColorNegative) &&
+			sparklineColorEqual(preset.ColorMarkers, group.ColorMarkers) &&
+			sparklineColorEqual(preset.ColorFirst, group.ColorFirst) &&
+			sparklineColorEqual(preset.ColorLast, group.ColorLast) &&
+			sparklineColorEqual(preset.ColorHigh, group.ColorHigh) &&
+			sparklineColorEqual(preset.ColorLow, group.ColorLow) {
+			style = ID
+			break
+		}
+	}
+	getRGB := func(color *xlsxColor) string {
+		if color == nil || color.RGB == "" {
+			return ""
+		}
+		return strings.TrimPrefix(color.RGB, "FF")
+	}
+	if style != -1 {
+		opts.Style = style
+		if !sparklineColorEqual(f.addSparklineGroupByStyle(style).ColorSeries, group.ColorSeries) {
+			opts.SeriesColor = getRGB(group.ColorSeries)
+		}
+		return
+	}
+	opts.SeriesColor = getRGB(group.ColorSeries)
+	opts.NegativeColor = getRGB(group.ColorNegative)
+	opts.MarkersColor = getRGB(group.ColorMarkers)
+	opts.FirstColor = getRGB(group.ColorFirst)
+	opts.LastColor = getRGB(group.ColorLast)
+	opts.HightColor = getRGB(group.ColorHigh)
+	opts.LowColor = getRGB(group.ColorLow)
+}
+
+// sparklineColorEqual provides a function to check if the given two colors
+// of the sparkline group are the same.
+func sparklineColorEqual(a, b *xlsxColor) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	if (a.Theme == nil) != (b.Theme == nil) || (a.Theme != nil && *a.Theme != *b.Theme) {
+		return false
+	}
+	return a.Auto == b.Auto && strings.EqualFold(a.RGB, b.RGB) &&
+		a.Indexed == b.Indexed && a.Tint == b.Tint
+}
+
+// DeleteSparkline provides a function to delete the sparkline by given
+// worksheet name and the cell reference of the sparkline location. The
+// sparkline group will be removed if all sparklines in the group has been
+// deleted. For example, delete the sparkline at cell A2 in the worksheet
+// named 'Sheet1':
+//
+//	err := f.DeleteSparkline("Sheet1", "A2")
+func (f *File) DeleteSparkline(sheet, sqref string) error {
+	ws, err := f.workSheetReader(sheet)
+	if err != nil {
+		return err
+	}
+	if ws.ExtLst == nil {
+		return err
+	}
+	decodeExtLst := new(decodeExtLst)
+	if err = f.xmlNewDecoder(strings.NewReader("<extLst>" + ws.ExtLst.Ext + "</extLst>")).
+		Decode(decodeExtLst); err != nil && err != io.EOF {
+		return err
+	}
+	for i := 0; i < len(decodeExtLst.Ext); i++ {
+		if decodeExtLst.Ext[i].URI != ExtURISparklineGroups {
+			continue
+		}
+		decodeSparklineGroups := new(decodeX14SparklineGroups)
+		if err = f.xmlNewDecoder(strings.NewReader(decodeExtLst.Ext[i].Content)).
+			Decode(decodeSparklineGroups); err != nil && err != io.EOF {
+			return err
+		}
+		groups := &xlsxX14SparklineGroups{XMLNSXM: NameSpaceSpreadSheetExcel2006Main.Value}
+		for _, group := range decodeSparklineGroups.SparklineGroups {
+			var sparklines []*xlsxX14Sparkline
+			for _, sparkline := range group.Sparklines.Sparkline {
+				if !strings.EqualFold(sparkline.Sqref, sqref) {
+					sparklines = append(sparklines, &xlsxX14Sparkline{F: sparkline.F, Sqref: sparkline.Sqref})
+				}
+			}
+			if len(sparklines) == 0 {
+				continue
+			}
+			g := &xlsxX14SparklineGroup{
+				ManualMax:           group.ManualMax,
+				ManualMin:           group.ManualMin,
+				LineWeight:          group.LineWeight,
+				Type:                group.Type,
+				DateAxis:            group.DateAxis,
+				DisplayEmptyCellsAs: group.DisplayEmptyCellsAs,
+				Markers:             group.Markers,
+				High:                group.High,
+				Low:                 group.Low,
+				First:               group.First,
+				Last:                group.Last,
+				Negative:            group.Negative,
+				DisplayXAxis:        group.DisplayXAxis,
+				DisplayHidden:       group.DisplayHidden,
+				MinAxisType:         group.MinAxisType,
+				MaxAxisType:         group.MaxAxisType,
+				RightToLeft:         group.RightToLeft,
+				ColorSeries:         group.ColorSeries,
+				ColorNegative:       group.ColorNegative,
+				ColorAxis:           group.ColorAxis,
+				ColorMarkers:        group.ColorMarkers,
+				ColorFirst:          group.ColorFirst,
+				ColorLast:           group.ColorLast,
+				ColorHigh:           group.ColorHigh,
+				ColorLow:            group.ColorLow,
+			}
+			g.Sparklines.Sparkline = sparklines
+			groups.SparklineGroups = append(groups.SparklineGroups, g)
+		}
+		if len(groups.SparklineGroups) == 0 {
+			decodeExtLst.Ext = append(decodeExtLst.Ext[:i], decodeExtLst.Ext[i+1:]...)
+			i--
+			continue
+		}
+		sparklineGroupsBytes, _ := xml.Marshal(groups)
+		decodeExtLst.Ext[i].Content = string(sparklineGroupsBytes)
+	}
+	if len(decodeExtLst.Ext) == 0 {
+		ws.ExtLst = nil
+		return nil
+	}
+	extLstBytes, err := xml.Marshal(decodeExtLst)
+	ws.ExtLst = &xlsxExtLst{Ext: strings.TrimSuffix(strings.TrimPrefix(string(extLstBytes), "<extLst>"), "</extLst>")}
+	return err
+}
+
+// getSparklineGroups provides a function to get the sparkline groups in the
+// worksheet extension list by given worksheet name.
+func (f *File) getSparklineGroups(sheet string) ([]*decodeX14SparklineGroup, error) {
+	var groups []*decodeX14SparklineGroup
+	ws, err := f.workSheetReader(sheet)
+	if err != nil || ws.ExtLst == nil {
+		return groups, err
+	}
+	decodeExtLst := new(decodeExtLst)
+	if err = f.xmlNewDecoder(strings.NewReader("<extLst>" + ws.ExtLst.Ext + "</extLst>")).
+		Decode(decodeExtLst); err != nil && err != io.EOF {
+		return groups, err
+	}
+	for _, ext := range decodeExtLst.Ext {
+		if ext.URI != ExtURISparklineGroups {
+			continue
+		}
+		decodeSparklineGroups := new(decodeX14SparklineGroups)
+		if err = f.xmlNewDecoder(strings.NewReader(ext.Content)).
+			Decode(decodeSparklineGroups); err != nil && err != io.EOF {
+			return groups, err
+		}
+		groups = append(groups, decodeSparklineGroups.SparklineGroups...)
+	}
+	return groups, nil
+}
@@ -290,6 +290,156 @@ func TestAppendSparkline(t *testing.T) {
 	assert.EqualError(t, f.appendSparkline(ws, &xlsxX14SparklineGroup{}, &xlsxX14SparklineGroups{}), "XML syntax error on line 1: invalid UTF-8")
 }
 
+func TestGetSparklines(t *testing.T) {
+	f, err := prepareSparklineDataset()
+	assert.NoError(t, err)
+	sparklines, err := f.GetSparklines("Sheet1")
+	assert.NoError(t, err)
+	assert.Empty(t, sparklines)
+	assert.NoError(t, f.AddSparkline("Sheet1", &SparklineOptions{
+		Location: []string{"A1", "A2"},
+		Range:    []string{"Sheet2!A1:E1", "Sheet2!A2:E2"},
+		Type:     "win_loss",
+		Style:    18,
+		Markers:  true,
+		Axis:     true,
+		Reverse:  true,
+	}))
+	assert.NoError(t, f.AddSparkline("Sheet1", &SparklineOptions{
+		Location:      []string{"A3"},
+		Range:         []string{"Sheet2!A3:E3"},
+		Style:         5,
+		Weight:        1.5,
+		DateAxis:      true,
+		Hidden:        true,
+		EmptyCells:    "zero",
+		SeriesColor:   "FF0000",
+		NegativeColor: "00FF00",
+		MarkersColor:  "0000FF",
+		FirstColor:    "FFFF00",
+		LastColor:     "00FFFF",
+		HightColor:    "FF00FF",
+		LowColor:      "C0C0C0",
+	}))
+	// Test get sparklines and save the workbook
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestGetSparklines.xlsx")))
+	sparklines, err = f.GetSparklines("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, []SparklineOptions{
+		{
+			Location:   []string{"A1", "A2"},
+			Range:      []string{"Sheet2!A1:E1", "Sheet2!A2:E2"},
+			Type:       "win_loss",
+			Style:      18,
+			Markers:    true,
+			Axis:       true,
+			Reverse:    true,
+			EmptyCells: "gap",
+		},
+		{
+			Location:      []string{"A3"},
+			Range:         []string{"Sheet2!A3:E3"},
+			Type:          "line",
+			Weight:        1.5,
+			DateAxis:      true,
+			Hidden:        true,
+			EmptyCells:    "zero",
+			SeriesColor:   "FF0000",
+			NegativeColor: "00FF00",
+			MarkersColor:  "0000FF",
+			FirstColor:    "FFFF00",
+			LastColor:     "00FFFF",
+			HightColor:    "FF00FF",
+			LowColor:      "C0C0C0",
+		},
+	}, sparklines)
+	// Test get sparklines with custom series color of the style
+	assert.NoError(t, f.AddSparkline("Sheet1", &SparklineOptions{
+		Location:    []string{"A4"},
+		Range:       []string{"Sheet2!A1:E1"},
+		Style:       3,
+		SeriesColor: "#123456",
+	}))
+	sparklines, err = f.GetSparklines("Sheet1")
+	assert.NoError(t, err)
+	assert.Len(t, sparklines, 3)
+	assert.Equal(t, 3, sparklines[2].Style)
+	assert.Equal(t, "123456", sparklines[2].SeriesColor)
+	// Test get sparklines with not exist worksheet
+	_, err = f.GetSparklines("SheetN")
+	assert.EqualError(t, err, "sheet SheetN does not exist")
+	// Test get sparklines with unsupported charset extension list
+	ws, ok := f.Sheet.Load("xl/worksheets/sheet1.xml")
+	assert.True(t, ok)
+	ws.(*xlsxWorksheet).ExtLst = &xlsxExtLst{Ext: string(MacintoshCyrillicCharset)}
+	_, err = f.GetSparklines("Sheet1")
+	assert.EqualError(t, err, "XML syntax error on line 1: invalid UTF-8")
+	// Test get sparklines with unsupported charset sparkline groups
+	ws.(*xlsxWorksheet).ExtLst = &xlsxExtLst{Ext: fmt.Sprintf(`<ext uri="%s">%s</ext>`, ExtURISparklineGroups, MacintoshCyrillicCharset)}
+	_, err = f.GetSparklines("Sheet1")
+	assert.EqualError(t, err, "XML syntax error on line 1: invalid UTF-8")
+	assert.NoError(t, f.Close())
+}
+
+func TestDeleteSparkline(t *testing.T) {
+	f, err := prepareSparklineDataset()
+	assert.NoError(t, err)
+	// Test delete sparkline on the worksheet without sparklines
+	assert.NoError(t, f.DeleteSparkline("Sheet1", "A1"))
+	assert.NoError(t, f.AddSparkline("Sheet1", &SparklineOptions{
+		Location: []string{"A1", "A2"},
+		Range:    []string{"Sheet2!A1:E1", "Sheet2!A2:E2"},
+		Markers:  true,
+	}))
+	assert.NoError(t, f.AddSparkline("Sheet1", &SparklineOptions{
+		Location: []string{"A3"},
+		Range:    []string{"Sheet2!A3:E3"},
+		Type:     "column",
+	}))
+	assert.NoError(t, f.DeleteSparkline("Sheet1", "a1"))
+	sparklines, err := f.GetSparklines("Sheet1")
+	assert.NoError(t, err)
+	assert.Len(t, sparklines, 2)
+	assert.Equal(t, []string{"A2"}, sparklines[0].Location)
+	assert.True(t, sparklines[0].Markers)
+	// Test delete the last sparkline in the group
+	assert.NoError(t, f.DeleteSparkline("Sheet1", "A3"))
+	sparklines, err = f.GetSparklines("Sheet1")
+	assert.NoError(t, err)
+	assert.Len(t, sparklines, 1)
+	assert.NoError(t, f.AddDataValidation("Sheet1", &DataValidation{
+		Sqref: "B1", Type: "list", Formula1: "Sheet2!$A$1:$A$3", ShowDropDown: true,
+	}))
+	assert.NoError(t, f.DeleteSparkline("Sheet1", "A2"))
+	sparklines, err = f.GetSparklines("Sheet1")
+	assert.NoError(t, err)
+	assert.Empty(t, sparklines)
+	ws, ok := f.Sheet.Load("xl/worksheets/sheet1.xml")
+	assert.True(t, ok)
+	assert.NotContains(t, ws.(*xlsxWorksheet).ExtLst.Ext, ExtURISparklineGroups)
+	assert.Contains(t, ws.(*xlsxWorksheet).ExtLst.Ext, ExtURIDataValidations)
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestDeleteSparkline.xlsx")))
+	// Test delete all sparklines in the worksheet
+	f = NewFile()
+	assert.NoError(t, f.AddSparkline("Sheet1", &SparklineOptions{
+		Location: []string{"A1"},
+		Range:    []string{"Sheet1!B1:E1"},
+	}))
+	assert.NoError(t, f.DeleteSparkline("Sheet1", "A1"))
+	ws, ok = f.Sheet.Load("xl/worksheets/sheet1.xml")
+	assert.True(t, ok)
+	assert.Nil(t, ws.(*xlsxWorksheet).ExtLst)
+	// Test delete sparkline with not exist worksheet
+	assert.EqualError(t, f.DeleteSparkline("SheetN", "A1"), "sheet SheetN does not exist")
+	// Test delete sparkline with unsupported charset extension list
+	ws.(*xlsxWorksheet).ExtLst = &xlsxExtLst{Ext: string(MacintoshCyrillicCharset)}
+	assert.EqualError(t, f.DeleteSparkline("Sheet1", "A1"), "XML syntax error on line 1: invalid UTF-8")
+	// Test delete sparkline with unsupported charset sparkline groups
+	ws.(*xlsxWorksheet).ExtLst = &xlsxExtLst{Ext: fmt.Sprintf(`<ext uri="%s">%s</ext>`, ExtURISparklineGroups, MacintoshCyrillicCharset)}
+	assert.EqualError(t, f.DeleteSparkline("Sheet1", "A1"), "XML syntax error on line 1: invalid UTF-8")
+	assert.NoError(t, f.Close())
+}
+
 func prepareSparklineDataset() (*File, error) {
 	f := NewFile()
 	sheet2 := [][]int{
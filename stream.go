@@ -16,6 +16,8 @@ import (
 	"encoding/xml"
 	"fmt"
 	"io"
+	"math"
+	"math/big"
 	"os"
 	"reflect"
 	"strconv"
@@ -36,6 +38,13 @@ type StreamWriter struct {
 	mergeCellsCount int
 	mergeCells      strings.Builder
 	tableParts      string
+	appending       bool
+	rowStyles       []streamRowStyle
+}
+
+// streamRowStyle directly maps the rows style range of the stream writer.
+type streamRowStyle struct {
+	start, end, styleID int
 }
 
 // NewStreamWriter returns stream writer struct by given worksheet name used for
@@ -142,6 +151,93 @@ func (f *File) NewStreamWriter(sheet string) (*StreamWriter, error) {
 	return sw, err
 }
 
+// AppendStreamWriter returns stream writer struct by given worksheet name used
+// for continuing writing rows after the last row of an existing worksheet in
+// stream mode. The existing rows, column widths, merged cells and table parts
+// of the worksheet will be preserved, and the row number of the newly written
+// rows must be greater than the last existing row. Note that after writing
+// data with the stream writer for the worksheet, you must call the 'Flush'
+// method to end the streaming writing process. For example, append 10 rows
+// after the existing data in the worksheet named Sheet1:
+//
+//	f, err := excelize.OpenFile("Book1.xlsx")
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	defer func() {
+//	    if err := f.Close(); err != nil {
+//	        fmt.Println(err)
+//	    }
+//	}()
+//	sw, err := f.AppendStreamWriter("Sheet1")
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	for i := 0; i < 10; i++ {
+//	    cell, err := excelize.CoordinatesToCellName(1, sw.LastRow()+1)
+//	    if err != nil {
+//	        fmt.Println(err)
+//	        return
+//	    }
+//	    if err := sw.SetRow(cell, []interface{}{i}); err != nil {
+//	        fmt.Println(err)
+//	        return
+//	    }
+//	}
+//	if err := sw.Flush(); err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	if err := f.Save(); err != nil {
+//	    fmt.Println(err)
+//	}
+func (f *File) AppendStreamWriter(sheet string) (*StreamWriter, error) {
+	sw, err := f.NewStreamWriter(sheet)
+	if err != nil {
+		return nil, err
+	}
+	sw.appending = true
+	ws := sw.worksheet
+	if rows := ws.SheetData.Row; len(rows) > 0 {
+		sw.rows = rows[len(rows)-1].R
+	}
+	if ws.MergeCells != nil {
+		for _, mergeCell := range ws.MergeCells.Cells {
+			if mergeCell == nil {
+				continue
+			}
+			sw.mergeCellsCount++
+			_, _ = sw.mergeCells.WriteString(`<mergeCell ref="`)
+			_, _ = sw.mergeCells.WriteString(mergeCell.Ref)
+			_, _ = sw.mergeCells.WriteString(`"/>`)
+		}
+	}
+	if ws.TableParts != nil && len(ws.TableParts.TableParts) > 0 {
+		tableParts := strings.Builder{}
+		_, _ = tableParts.WriteString(`<tableParts count="`)
+		_, _ = tableParts.WriteString(strconv.Itoa(len(ws.TableParts.TableParts)))
+		_, _ = tableParts.WriteString(`">`)
+		for _, tablePart := range ws.TableParts.TableParts {
+			_, _ = tableParts.WriteString(`<tablePart r:id="`)
+			_, _ = tableParts.WriteString(tablePart.RID)
+			_, _ = tableParts.WriteString(`"></tablePart>`)
+		}
+		_, _ = tableParts.WriteString(`</tableParts>`)
+		sw.tableParts = tableParts.String()
+	}
+	return sw, err
+}
+
+// LastRow returns the number of the last row which has been written in the
+// worksheet by the stream writer, including the existing rows of the
+// worksheet when the stream writer was created by the AppendStreamWriter
+// function.
+func (sw *StreamWriter) LastRow() int {
+	return sw.rows
+}
+
 // AddTable creates an Excel table for the StreamWriter using the given
 // cell range and format set. For example, create a table of A1:D5:
 //
@@ -315,20 +411,34 @@ func getRowElement(token xml.Token, hRow int) (startElement xml.StartElement, ok
 }
 
 // Cell can be used directly in StreamWriter.SetRow to specify a style and
-// a value.
+// a value. The SharedFormula field specifies the shared formula group which
+// the cell belongs to, the Formula of the cell will be ignored by the stream
+// writer unless it's the master cell of the group.
 type Cell struct {
-	StyleID int
-	Formula string
-	Value   interface{}
+	StyleID       int
+	Formula       string
+	SharedFormula *SharedFormula
+	Value         interface{}
+}
+
+// SharedFormula directly maps the shared formula group of a cell. The Index
+// is the group index of the shared formula in the worksheet, and the Ref is
+// the range reference of the cells which the shared formula applies to, it's
+// only specified in the master cell of the group.
+type SharedFormula struct {
+	Index int
+	Ref   string
 }
 
 // RowOpts define the options for the set row, it can be used directly in
-// StreamWriter.SetRow to specify the style and properties of the row.
+// StreamWriter.SetRow to specify the style and properties of the row. The
+// Spans is an optimization hint of the cells range in the row, such as "1:5".
 type RowOpts struct {
 	Height       float64
 	Hidden       bool
 	StyleID      int
 	OutlineLevel int
+	Spans        string
 }
 
 // marshalAttrs prepare attributes of the row.
@@ -366,6 +476,11 @@ func (r *RowOpts) marshalAttrs() (strings.Builder, error) {
 	if r.Hidden {
 		attrs.WriteString(` hidden="1"`)
 	}
+	if r.Spans != "" {
+		attrs.WriteString(` spans="`)
+		attrs.WriteString(r.Spans)
+		attrs.WriteString(`"`)
+	}
 	return attrs, err
 }
 
@@ -393,9 +508,16 @@ func (sw *StreamWriter) SetRow(cell string, values []interface{}, opts ...RowOpt
 	if row <= sw.rows {
 		return newStreamSetRowError(row)
 	}
-	sw.rows = row
 	sw.writeSheetData()
+	sw.writeStyledRows(sw.rows, row)
+	sw.rows = row
 	options := parseRowOpts(opts...)
+	if options.StyleID == 0 {
+		options.StyleID = sw.getRowStyle(row)
+	}
+	if options.Spans == "" {
+		options.Spans = getStreamRowSpans(col, values)
+	}
 	attrs, err := options.marshalAttrs()
 	if err != nil {
 		return err
@@ -417,11 +539,11 @@ func (sw *StreamWriter) SetRow(cell string, values []interface{}, opts ...RowOpt
 		if v, ok := val.(Cell); ok {
 			c.S = v.StyleID
 			val = v.Value
-			setCellFormula(&c, v.Formula)
+			setCellFormula(&c, v.Formula, v.SharedFormula)
 		} else if v, ok := val.(*Cell); ok && v != nil {
 			c.S = v.StyleID
 			val = v.Value
-			setCellFormula(&c, v.Formula)
+			setCellFormula(&c, v.Formula, v.SharedFormula)
 		}
 		if err = sw.setCellValFunc(&c, val); err != nil {
 			_, _ = sw.rawData.WriteString(`</row>`)
@@ -433,6 +555,26 @@ func (sw *StreamWriter) SetRow(cell string, values []interface{}, opts ...RowOpt
 	return sw.rawData.Sync()
 }
 
+// getStreamRowSpans provides a function to get the spans attribute of the row
+// by given starting column number and values of the row, returns an empty
+// string if there are no values in the row.
+func getStreamRowSpans(col int, values []interface{}) string {
+	first, last := -1, -1
+	for i, val := range values {
+		if val == nil || col+i > MaxColumns {
+			continue
+		}
+		if first == -1 {
+			first = col + i
+		}
+		last = col + i
+	}
+	if first == -1 {
+		return ""
+	}
+	return strconv.Itoa(first) + ":" + strconv.Itoa(last)
+}
+
 // SetColWidth provides a function to set the width of a single column or
 // multiple columns for the StreamWriter. Note that you must call
 // the 'SetColWidth' function before the 'SetRow' function. For example set
@@ -452,6 +594,23 @@ func (sw *StreamWriter) SetColWidth(min, max int, width float64) error {
 	if min > max {
 		min, max = max, min
 	}
+	if sw.appending {
+		col := xlsxCol{Min: min, Max: max, Width: float64Ptr(width), CustomWidth: true}
+		if sw.worksheet.Cols == nil {
+			sw.worksheet.Cols = &xlsxCols{Col: []xlsxCol{col}}
+			return nil
+		}
+		sw.worksheet.Cols.Col = flatCols(col, sw.worksheet.Cols.Col, func(fc, c xlsxCol) xlsxCol {
+			fc.BestFit = c.BestFit
+			fc.Collapsed = c.Collapsed
+			fc.Hidden = c.Hidden
+			fc.OutlineLevel = c.OutlineLevel
+			fc.Phonetic = c.Phonetic
+			fc.Style = c.Style
+			return fc
+		})
+		return nil
+	}
 
 	sw.cols.WriteString(`<col min="`)
 	sw.cols.WriteString(strconv.Itoa(min))
@@ -463,6 +622,172 @@ func (sw *StreamWriter) SetColWidth(min, max int, width float64) error {
 	return nil
 }
 
+// SetRowStyleRange provides a function to set the style of rows by given
+// start and end row number (inclusive) and style ID for the StreamWriter. The
+// style will be applied to the rows and the cells without style in the range
+// when they are written by the 'SetRow' function, and the rows in the range
+// which are not written will be created with the style during the 'Flush'
+// function. Note that you must call the 'SetRowStyleRange' function before the
+// rows in the range were written, and the style ID specified in the RowOpts of
+// the 'SetRow' function takes precedence. For example, set banded rows style
+// for the rows from 2 to 1001:
+//
+//	styleID, err := f.NewStyle(&excelize.Style{
+//	    Fill: excelize.Fill{Type: "pattern", Color: []string{"DFEBF6"}, Pattern: 1},
+//	})
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	for row := 2; row <= 1001; row += 2 {
+//	    if err := sw.SetRowStyleRange(row, row, styleID); err != nil {
+//	        fmt.Println(err)
+//	        return
+//	    }
+//	}
+func (sw *StreamWriter) SetRowStyleRange(start, end, styleID int) error {
+	if end < start {
+		start, end = end, start
+	}
+	if start < 1 {
+		return newInvalidRowNumberError(start)
+	}
+	if end > TotalRows {
+		return ErrMaxRows
+	}
+	if start <= sw.rows {
+		return newStreamSetRowError(start)
+	}
+	s, err := sw.file.stylesReader()
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if styleID < 0 || s.CellXfs == nil || len(s.CellXfs.Xf) <= styleID {
+		return newInvalidStyleID(styleID)
+	}
+	sw.rowStyles = append(sw.rowStyles, streamRowStyle{start: start, end: end, styleID: styleID})
+	return err
+}
+
+// getRowStyle provides a function to get the style ID of the row by given row
+// number from the rows style ranges of the stream writer, the last specified
+// range takes precedence.
+func (sw *StreamWriter) getRowStyle(row int) int {
+	for i := len(sw.rowStyles) - 1; i >= 0; i-- {
+		if rowStyle := sw.rowStyles[i]; rowStyle.start <= row && row <= rowStyle.end {
+			return rowStyle.styleID
+		}
+	}
+	return 0
+}
+
+// writeStyledRows provides a function to write the empty rows with style
+// between the given row numbers (exclusive) for the rows style ranges of the
+// stream writer.
+func (sw *StreamWriter) writeStyledRows(from, to int) {
+	var last int
+	for _, rowStyle := range sw.rowStyles {
+		if rowStyle.end > last {
+			last = rowStyle.end
+		}
+	}
+	if to > last+1 {
+		to = last + 1
+	}
+	for row := from + 1; row < to; row++ {
+		if styleID := sw.getRowStyle(row); styleID > 0 {
+			_, _ = sw.rawData.WriteString(`<row r="`)
+			_, _ = sw.rawData.WriteString(strconv.Itoa(row))
+			_, _ = sw.rawData.WriteString(`" s="`)
+			_, _ = sw.rawData.WriteString(strconv.Itoa(styleID))
+			_, _ = sw.rawData.WriteString(`" customFormat="1"></row>`)
+		}
+	}
+}
+
+// AddDataValidation provides a function to add data validation rule for the
+// StreamWriter, the rule will be written after the sheet data when calling
+// the 'Flush' function, so it doesn't require reloading the worksheet into
+// memory. Note that you must call the 'AddDataValidation' function before the
+// 'Flush' function. For example, set data validation on Sheet1!A1:B2000:
+//
+//	dv := excelize.NewDataValidation(true)
+//	dv.Sqref = "A1:B2000"
+//	if err := dv.SetRange(10, 20, excelize.DataValidationTypeWhole,
+//	    excelize.DataValidationOperatorBetween); err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	err := sw.AddDataValidation(dv)
+func (sw *StreamWriter) AddDataValidation(dv *DataValidation) error {
+	if dv == nil {
+		return ErrParameterRequired
+	}
+	return sw.file.addDataValidation(sw.worksheet, sw.Sheet, dv)
+}
+
+// AddRegisteredPicture provides a function to add picture by given cell
+// reference, ID of the image registered by the RegisterImage function and
+// picture format set for the StreamWriter, the drawing relationship will be
+// written after the sheet data when calling the 'Flush' function, and all
+// pictures of the same image share the single media part. Note that you must
+// call the 'AddRegisteredPicture' function before the 'Flush' function, and
+// the custom row heights set by the StreamWriter will not be taken into
+// account when positioning the pictures. For example, add the same product
+// photo to each row of the cells B2:B10001 on Sheet1:
+//
+//	imageID, err := f.RegisterImage(".png", file)
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	for row := 2; row <= 10001; row++ {
+//	    cell := fmt.Sprintf("A%d", row)
+//	    if err := sw.SetRow(cell, []interface{}{fmt.Sprintf("SKU-%d", row)}); err != nil {
+//	        fmt.Println(err)
+//	        return
+//	    }
+//	    if _, err := sw.AddRegisteredPicture(fmt.Sprintf("B%d", row), imageID,
+//	        &excelize.GraphicOptions{AutoFit: true}); err != nil {
+//	        fmt.Println(err)
+//	        return
+//	    }
+//	}
+func (sw *StreamWriter) AddRegisteredPicture(cell string, imageID int, opts *GraphicOptions) (DrawingObject, error) {
+	return sw.file.AddRegisteredPicture(sw.Sheet, cell, imageID, opts)
+}
+
+// SetConditionalFormat provides a function to create conditional formatting
+// rule for cell value for the StreamWriter, the rule will be written after the
+// sheet data when calling the 'Flush' function. Note that you must call the
+// 'SetConditionalFormat' function before the 'Flush' function. Read the
+// 'SetConditionalFormat' of the File for more details about the conditional
+// format options. For example, highlight the cells greater than 6 in the range
+// A1:A10000:
+//
+//	format, err := f.NewConditionalStyle(
+//	    &excelize.Style{
+//	        Font: &excelize.Font{Color: "9A0511"},
+//	        Fill: excelize.Fill{
+//	            Type: "pattern", Color: []string{"FEC7CE"}, Pattern: 1,
+//	        },
+//	    },
+//	)
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	err = sw.SetConditionalFormat("A1:A10000",
+//	    []excelize.ConditionalFormatOptions{
+//	        {Type: "cell", Criteria: ">", Format: format, Value: "6"},
+//	    },
+//	)
+func (sw *StreamWriter) SetConditionalFormat(rangeRef string, opts []ConditionalFormatOptions) error {
+	return sw.file.setConditionalFormat(sw.worksheet, sw.Sheet, rangeRef, opts)
+}
+
 // InsertPageBreak creates a page break to determine where the printed page ends
 // and where begins the next one by a given cell reference, the content before
 // the page break will be printed on one page and after the page break on
@@ -499,7 +824,14 @@ func (sw *StreamWriter) MergeCell(hCell, vCell string) error {
 }
 
 // setCellFormula provides a function to set formula of a cell.
-func setCellFormula(c *xlsxC, formula string) {
+func setCellFormula(c *xlsxC, formula string, shared *SharedFormula) {
+	if shared != nil {
+		c.T, c.F = "str", &xlsxF{T: STCellFormulaTypeShared, Ref: shared.Ref, Si: intPtr(shared.Index)}
+		if shared.Ref != "" {
+			c.F.Content = formula
+		}
+		return
+	}
 	if formula != "" {
 		c.T, c.F = "str", &xlsxF{Content: formula}
 	}
@@ -522,6 +854,26 @@ func (sw *StreamWriter) setCellTime(c *xlsxC, val time.Time) error {
 	return nil
 }
 
+// setCellFloat provides a function to set number of a cell with a float
+// value, the NaN or infinity value will be written by the NonFinitePolicy of
+// the workbook options.
+func (sw *StreamWriter) setCellFloat(c *xlsxC, val float64, bitSize int) error {
+	if !math.IsNaN(val) && !math.IsInf(val, 0) {
+		c.T, c.V = setCellFloat(val, -1, bitSize)
+		return nil
+	}
+	t, v, isText, err := sw.file.formatNonFiniteFloat(val)
+	if err != nil {
+		return err
+	}
+	if isText {
+		c.setCellValue(v)
+		return err
+	}
+	c.T, c.V = t, v
+	return err
+}
+
 // setCellValFunc provides a function to set value of a cell.
 func (sw *StreamWriter) setCellValFunc(c *xlsxC, val interface{}) error {
 	var err error
@@ -529,13 +881,13 @@ func (sw *StreamWriter) setCellValFunc(c *xlsxC, val interface{}) error {
 	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
 		err = setCellIntFunc(c, val)
 	case float32:
-		c.T, c.V = setCellFloat(float64(val), -1, 32)
+		err = sw.setCellFloat(c, float64(val), 32)
 	case float64:
-		c.T, c.V = setCellFloat(val, -1, 64)
+		err = sw.setCellFloat(c, val, 64)
 	case string:
-		c.setCellValue(val)
+		c.setCellValue(sw.file.escapeFormula(val))
 	case []byte:
-		c.setCellValue(string(val))
+		c.setCellValue(sw.file.escapeFormula(string(val)))
 	case time.Duration:
 		c.T, c.V = setCellDuration(val)
 	case time.Time:
@@ -544,11 +896,21 @@ func (sw *StreamWriter) setCellValFunc(c *xlsxC, val interface{}) error {
 		c.T, c.V = setCellBool(val)
 	case nil:
 		return err
+	case *big.Int, *big.Rat, *big.Float, Decimal:
+		var (
+			value  string
+			isText bool
+		)
+		if value, isText, err = sw.file.formatBigNumber(val); isText {
+			c.setCellValue(value)
+			break
+		}
+		c.setCellDefault(value)
 	case []RichTextRun:
 		c.T, c.IS = "inlineStr", &xlsxSI{}
 		c.IS.R, err = setRichText(val)
 	default:
-		c.setCellValue(fmt.Sprint(val))
+		c.setCellValue(sw.file.escapeFormula(fmt.Sprint(val)))
 	}
 	return err
 }
@@ -606,7 +968,23 @@ func writeCell(buf *bufferedWriter, c xlsxC) {
 	}
 	_, _ = buf.WriteString(`>`)
 	if c.F != nil {
-		_, _ = buf.WriteString(`<f>`)
+		_, _ = buf.WriteString(`<f`)
+		if c.F.T != "" {
+			_, _ = buf.WriteString(` t="`)
+			_, _ = buf.WriteString(c.F.T)
+			_, _ = buf.WriteString(`"`)
+		}
+		if c.F.Ref != "" {
+			_, _ = buf.WriteString(` ref="`)
+			_, _ = buf.WriteString(c.F.Ref)
+			_, _ = buf.WriteString(`"`)
+		}
+		if c.F.Si != nil {
+			_, _ = buf.WriteString(` si="`)
+			_, _ = buf.WriteString(strconv.Itoa(*c.F.Si))
+			_, _ = buf.WriteString(`"`)
+		}
+		_, _ = buf.WriteString(`>`)
 		_ = xml.EscapeText(buf, []byte(c.F.Content))
 		_, _ = buf.WriteString(`</f>`)
 	}
@@ -644,19 +1022,43 @@ func writeCell(buf *bufferedWriter, c xlsxC) {
 func (sw *StreamWriter) writeSheetData() {
 	if !sw.sheetWritten {
 		bulkAppendFields(&sw.rawData, sw.worksheet, 4, 5)
+		if sw.appending {
+			sw.writeExistingCols()
+		}
 		if sw.cols.Len() > 0 {
 			_, _ = sw.rawData.WriteString("<cols>")
 			_, _ = sw.rawData.WriteString(sw.cols.String())
 			_, _ = sw.rawData.WriteString("</cols>")
 		}
 		_, _ = sw.rawData.WriteString(`<sheetData>`)
+		if sw.appending {
+			enc := xml.NewEncoder(&sw.rawData)
+			for _, row := range sw.worksheet.SheetData.Row {
+				_ = enc.EncodeElement(row, xml.StartElement{Name: xml.Name{Local: "row"}})
+			}
+			_ = enc.Flush()
+		}
 		sw.sheetWritten = true
 	}
 }
 
+// writeExistingCols provides a function to write the columns definition of
+// the existing worksheet for the stream writer in appending mode.
+func (sw *StreamWriter) writeExistingCols() {
+	if sw.worksheet.Cols == nil {
+		return
+	}
+	enc := xml.NewEncoder(&sw.cols)
+	for _, col := range sw.worksheet.Cols.Col {
+		_ = enc.EncodeElement(col, xml.StartElement{Name: xml.Name{Local: "col"}})
+	}
+	_ = enc.Flush()
+}
+
 // Flush ending the streaming writing process.
 func (sw *StreamWriter) Flush() error {
 	sw.writeSheetData()
+	sw.writeStyledRows(sw.rows, TotalRows+1)
 	_, _ = sw.rawData.WriteString(`</sheetData>`)
 	bulkAppendFields(&sw.rawData, sw.worksheet, 8, 15)
 	mergeCells := strings.Builder{}
@@ -668,15 +1070,16 @@ func (sw *StreamWriter) Flush() error {
 		_, _ = mergeCells.WriteString(`</mergeCells>`)
 	}
 	_, _ = sw.rawData.WriteString(mergeCells.String())
-	bulkAppendFields(&sw.rawData, sw.worksheet, 17, 38)
+	bulkAppendFields(&sw.rawData, sw.worksheet, 17, 39)
 	_, _ = sw.rawData.WriteString(sw.tableParts)
-	bulkAppendFields(&sw.rawData, sw.worksheet, 40, 40)
+	bulkAppendFields(&sw.rawData, sw.worksheet, 41, 41)
 	_, _ = sw.rawData.WriteString(`</worksheet>`)
 	if err := sw.rawData.Flush(); err != nil {
 		return err
 	}
 
 	sheetPath := sw.file.sheetMap[sw.Sheet]
+	sw.file.changedCells.Store(cellRef{Sheet: sw.Sheet}, struct{}{})
 	sw.file.Sheet.Delete(sheetPath)
 	sw.file.checked.Delete(sheetPath)
 	sw.file.Pkg.Delete(sheetPath)
@@ -691,6 +1094,10 @@ func bulkAppendFields(w io.Writer, ws *xlsxWorksheet, from, to int) {
 	enc := xml.NewEncoder(w)
 	for i := 0; i < s.NumField(); i++ {
 		if from <= i && i <= to {
+			if name := strings.Split(s.Type().Field(i).Tag.Get("xml"), ",")[0]; name != "" {
+				_ = enc.EncodeElement(s.Field(i).Interface(), xml.StartElement{Name: xml.Name{Local: name}})
+				continue
+			}
 			_ = enc.Encode(s.Field(i).Interface())
 		}
 	}
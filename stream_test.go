@@ -3,6 +3,8 @@ package excelize_ch
 import (
 	"encoding/xml"
 	"fmt"
+	"math"
+	"math/big"
 	"math/rand"
 	"os"
 	"path/filepath"
@@ -258,6 +260,95 @@ func TestStreamInsertPageBreak(t *testing.T) {
 	assert.NoError(t, file.SaveAs(filepath.Join("test", "TestStreamInsertPageBreak.xlsx")))
 }
 
+func TestStreamAddRegisteredPicture(t *testing.T) {
+	file := NewFile()
+	streamWriter, err := file.NewStreamWriter("Sheet1")
+	assert.NoError(t, err)
+	img, err := os.ReadFile(filepath.Join("test", "images", "excel.png"))
+	assert.NoError(t, err)
+	imageID, err := file.RegisterImage(".png", img)
+	assert.NoError(t, err)
+	for rowID := 1; rowID <= 50; rowID++ {
+		cell, err := CoordinatesToCellName(1, rowID)
+		assert.NoError(t, err)
+		assert.NoError(t, streamWriter.SetRow(cell, []interface{}{fmt.Sprintf("Product %d", rowID)}))
+		cell, err = CoordinatesToCellName(2, rowID)
+		assert.NoError(t, err)
+		_, err = streamWriter.AddRegisteredPicture(cell, imageID, &GraphicOptions{ScaleX: 0.1, ScaleY: 0.1})
+		assert.NoError(t, err)
+	}
+	// Test add picture with not exists image ID
+	_, err = streamWriter.AddRegisteredPicture("C1", imageID+1, nil)
+	assert.Equal(t, newNoExistImageError(imageID+1), err)
+	assert.NoError(t, streamWriter.Flush())
+	assert.NoError(t, file.SaveAs(filepath.Join("test", "TestStreamAddRegisteredPicture.xlsx")))
+	assert.NoError(t, file.Close())
+
+	file, err = OpenFile(filepath.Join("test", "TestStreamAddRegisteredPicture.xlsx"))
+	assert.NoError(t, err)
+	cells, err := file.GetPictureCells("Sheet1")
+	assert.NoError(t, err)
+	assert.Len(t, cells, 50)
+	pics, err := file.GetPictures("Sheet1", "B50")
+	assert.NoError(t, err)
+	assert.Len(t, pics, 1)
+	assert.Equal(t, img, pics[0].File)
+	var media int
+	file.Pkg.Range(func(k, v interface{}) bool {
+		if strings.HasPrefix(k.(string), "xl/media/") {
+			media++
+		}
+		return true
+	})
+	assert.Equal(t, 1, media)
+	val, err := file.GetCellValue("Sheet1", "A50")
+	assert.NoError(t, err)
+	assert.Equal(t, "Product 50", val)
+	assert.NoError(t, file.Close())
+}
+
+func TestStreamDataValidationAndConditionalFormat(t *testing.T) {
+	f := NewFile()
+	sw, err := f.NewStreamWriter("Sheet1")
+	assert.NoError(t, err)
+	dv := NewDataValidation(true)
+	dv.Sqref = "A1:A10"
+	assert.NoError(t, dv.SetRange(10, 20, DataValidationTypeWhole, DataValidationOperatorBetween))
+	assert.NoError(t, sw.AddDataValidation(dv))
+	assert.Equal(t, ErrParameterRequired, sw.AddDataValidation(nil))
+	format, err := f.NewConditionalStyle(&Style{Font: &Font{Color: "9A0511"}})
+	assert.NoError(t, err)
+	assert.NoError(t, sw.SetConditionalFormat("B10:B1", []ConditionalFormatOptions{
+		{Type: "cell", Criteria: ">", Format: format, Value: "6"},
+	}))
+	assert.NoError(t, sw.SetConditionalFormat("C1:C10", []ConditionalFormatOptions{
+		{Type: "data_bar", Criteria: "=", MinType: "min", MaxType: "max", BarBorderColor: "#0000FF", BarColor: "#638EC6", BarSolid: true},
+	}))
+	// Test set conditional format with invalid options
+	assert.Equal(t, ErrParameterInvalid, sw.SetConditionalFormat("D1:D10", []ConditionalFormatOptions{{Type: "unknown"}}))
+	assert.EqualError(t, sw.SetConditionalFormat("D:D10", []ConditionalFormatOptions{{Type: "cell"}}), newCellNameToCoordinatesError("D", newInvalidCellNameError("D")).Error())
+	for row := 1; row <= 10; row++ {
+		assert.NoError(t, sw.SetRow(fmt.Sprintf("A%d", row), []interface{}{row, row, row}))
+	}
+	assert.NoError(t, sw.Flush())
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestStreamDataValidationAndConditionalFormat.xlsx")))
+	assert.NoError(t, f.Close())
+
+	f, err = OpenFile(filepath.Join("test", "TestStreamDataValidationAndConditionalFormat.xlsx"))
+	assert.NoError(t, err)
+	dvs, err := f.GetDataValidations("Sheet1")
+	assert.NoError(t, err)
+	assert.Len(t, dvs, 1)
+	assert.Equal(t, "A1:A10", dvs[0].Sqref)
+	formats, err := f.GetConditionalFormats("Sheet1")
+	assert.NoError(t, err)
+	assert.Len(t, formats, 2)
+	assert.Equal(t, "cell", formats["B1:B10"][0].Type)
+	assert.Equal(t, "data_bar", formats["C1:C10"][0].Type)
+	assert.True(t, formats["C1:C10"][0].BarSolid)
+	assert.NoError(t, f.Close())
+}
+
 func TestNewStreamWriter(t *testing.T) {
 	// Test error exceptions
 	file := NewFile()
@@ -273,6 +364,67 @@ func TestNewStreamWriter(t *testing.T) {
 	assert.Equal(t, ErrSheetNameInvalid, err)
 }
 
+func TestAppendStreamWriter(t *testing.T) {
+	f := NewFile()
+	assert.NoError(t, f.SetSheetRow("Sheet1", "A1", &[]interface{}{"Name", "Score"}))
+	assert.NoError(t, f.SetSheetRow("Sheet1", "A2", &[]interface{}{"Alice", 90}))
+	assert.NoError(t, f.SetColWidth("Sheet1", "A", "B", 20))
+	assert.NoError(t, f.MergeCell("Sheet1", "C1", "D2"))
+	assert.NoError(t, f.AddTable("Sheet1", &Table{Range: "A1:B2"}))
+	sw, err := f.AppendStreamWriter("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, sw.LastRow())
+	// Test append rows which overlap the existing rows
+	assert.Equal(t, newStreamSetRowError(2), sw.SetRow("A2", []interface{}{"Bob"}))
+	// Test set column width which overlaps the existing column widths
+	assert.NoError(t, sw.SetColWidth(2, 3, 30))
+	assert.NoError(t, sw.SetRow("A3", []interface{}{"Bob", 85}))
+	assert.NoError(t, sw.MergeCell("A4", "B4"))
+	assert.NoError(t, sw.SetRow("A4", []interface{}{"Total"}))
+	assert.Equal(t, 4, sw.LastRow())
+	assert.NoError(t, sw.Flush())
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestAppendStreamWriter.xlsx")))
+	assert.NoError(t, f.Close())
+
+	f, err = OpenFile(filepath.Join("test", "TestAppendStreamWriter.xlsx"))
+	assert.NoError(t, err)
+	rows, err := f.GetRows("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{{"Name", "Score"}, {"Alice", "90"}, {"Bob", "85"}, {"Total"}}, rows)
+	for col, expected := range map[string]float64{"A": 20, "B": 30, "C": 30, "D": defaultColWidth} {
+		width, err := f.GetColWidth("Sheet1", col)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, width)
+	}
+	mergeCells, err := f.GetMergeCells("Sheet1")
+	assert.NoError(t, err)
+	assert.Len(t, mergeCells, 2)
+	assert.Equal(t, "C1", mergeCells[0].GetStartAxis())
+	assert.Equal(t, "D2", mergeCells[0].GetEndAxis())
+	assert.Equal(t, "A4", mergeCells[1].GetStartAxis())
+	assert.Equal(t, "B4", mergeCells[1].GetEndAxis())
+	tables, err := f.GetTables("Sheet1")
+	assert.NoError(t, err)
+	assert.Len(t, tables, 1)
+	assert.Equal(t, "A1:B2", tables[0].Range)
+	assert.NoError(t, f.Close())
+
+	// Test append stream writer on an empty worksheet
+	f = NewFile()
+	sw, err = f.AppendStreamWriter("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, 0, sw.LastRow())
+	assert.NoError(t, sw.SetRow("A1", []interface{}{1}))
+	assert.NoError(t, sw.Flush())
+	// Test append stream writer on not exists worksheet
+	_, err = f.AppendStreamWriter("SheetN")
+	assert.EqualError(t, err, "sheet SheetN does not exist")
+	// Test append stream writer with invalid sheet name
+	_, err = f.AppendStreamWriter("Sheet:1")
+	assert.Equal(t, ErrSheetNameInvalid, err)
+	assert.NoError(t, f.Close())
+}
+
 func TestStreamMarshalAttrs(t *testing.T) {
 	var r *RowOpts
 	attrs, err := r.marshalAttrs()
@@ -312,6 +464,41 @@ func TestStreamSetRowNilValues(t *testing.T) {
 	assert.NotEqual(t, ws.SheetData.Row[0].C[0].XMLName.Local, "c")
 }
 
+func TestStreamSetRowSpans(t *testing.T) {
+	file := NewFile()
+	defer func() {
+		assert.NoError(t, file.Close())
+	}()
+	streamWriter, err := file.NewStreamWriter("Sheet1")
+	assert.NoError(t, err)
+	assert.NoError(t, streamWriter.SetRow("B1", []interface{}{nil, "foo", nil, "bar", nil}))
+	assert.NoError(t, streamWriter.SetRow("A2", []interface{}{"foo"}, RowOpts{Spans: "1:5"}))
+	assert.NoError(t, streamWriter.SetRow("A3", []interface{}{nil}))
+	assert.NoError(t, streamWriter.Flush())
+	rows, err := file.Rows("Sheet1")
+	assert.NoError(t, err)
+	for _, expected := range []string{"3:5", "1:5", ""} {
+		assert.True(t, rows.Next())
+		assert.Equal(t, expected, rows.GetRowOpts().Spans)
+	}
+	assert.NoError(t, rows.Close())
+	assert.Equal(t, "", getStreamRowSpans(MaxColumns, []interface{}{nil, "foo"}))
+}
+
+func TestStreamSetRowEscapeFormula(t *testing.T) {
+	file := NewFile(Options{EscapeFormula: true})
+	defer func() {
+		assert.NoError(t, file.Close())
+	}()
+	streamWriter, err := file.NewStreamWriter("Sheet1")
+	assert.NoError(t, err)
+	assert.NoError(t, streamWriter.SetRow("A1", []interface{}{"=1+1", []byte("@A1"), Cell{Value: "text"}}))
+	assert.NoError(t, streamWriter.Flush())
+	rows, err := file.GetRows("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{{"'=1+1", "'@A1", "text"}}, rows)
+}
+
 func TestStreamSetRowWithStyle(t *testing.T) {
 	file := NewFile()
 	defer func() {
@@ -370,9 +557,103 @@ func TestStreamSetCellValFunc(t *testing.T) {
 		true,
 		nil,
 		complex64(5 + 10i),
+		big.NewInt(128),
 	} {
 		assert.NoError(t, sw.setCellValFunc(c, val))
 	}
+	// Test set big number cell value with infinity value
+	assert.Equal(t, ErrParameterInvalid, sw.setCellValFunc(c, new(big.Float).SetInf(true)))
+}
+
+func TestStreamSetRowBigNumber(t *testing.T) {
+	f := NewFile(Options{BigNumberAsText: true})
+	sw, err := f.NewStreamWriter("Sheet1")
+	assert.NoError(t, err)
+	bigInt, _ := new(big.Int).SetString("12345678901234567890", 10)
+	assert.NoError(t, sw.SetRow("A1", []interface{}{bigInt, big.NewRat(1, 4)}))
+	assert.NoError(t, sw.Flush())
+	for cell, expected := range map[string]string{"A1": "12345678901234567890", "B1": "0.25"} {
+		val, err := f.GetCellValue("Sheet1", cell)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, val, cell)
+	}
+	cellType, err := f.GetCellType("Sheet1", "A1")
+	assert.NoError(t, err)
+	assert.Equal(t, CellTypeInlineString, cellType)
+	assert.NoError(t, f.Close())
+}
+
+func TestStreamSetRowNonFinite(t *testing.T) {
+	f := NewFile()
+	sw, err := f.NewStreamWriter("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, ErrNonFiniteNumber, sw.SetRow("A1", []interface{}{math.NaN()}))
+	assert.NoError(t, f.Close())
+
+	for policy, expected := range map[NonFinitePolicy][]string{
+		NonFinitePolicyBlank:    {"1", "", ""},
+		NonFinitePolicyNumError: {"1", "#NUM!", "#NUM!"},
+		NonFinitePolicyString:   {"1", "NaN", "-Inf"},
+	} {
+		f := NewFile(Options{NonFinitePolicy: policy})
+		sw, err := f.NewStreamWriter("Sheet1")
+		assert.NoError(t, err)
+		assert.NoError(t, sw.SetRow("A1", []interface{}{1.0, math.NaN(), float32(math.Inf(-1))}))
+		assert.NoError(t, sw.Flush())
+		for i, cell := range []string{"A1", "B1", "C1"} {
+			val, err := f.GetCellValue("Sheet1", cell)
+			assert.NoError(t, err)
+			assert.Equal(t, expected[i], val, cell)
+		}
+		assert.NoError(t, f.Close())
+	}
+}
+
+func TestStreamSetRowStyleRange(t *testing.T) {
+	f := NewFile()
+	styleID1, err := f.NewStyle(&Style{Fill: Fill{Type: "pattern", Color: []string{"DFEBF6"}, Pattern: 1}})
+	assert.NoError(t, err)
+	styleID2, err := f.NewStyle(&Style{Font: &Font{Bold: true}})
+	assert.NoError(t, err)
+	sw, err := f.NewStreamWriter("Sheet1")
+	assert.NoError(t, err)
+	assert.NoError(t, sw.SetRowStyleRange(5, 2, styleID1))
+	assert.NoError(t, sw.SetRowStyleRange(4, 4, styleID2))
+	assert.NoError(t, sw.SetRowStyleRange(7, 7, styleID2))
+	assert.NoError(t, sw.SetRow("A1", []interface{}{"Header"}))
+	assert.NoError(t, sw.SetRow("A3", []interface{}{1, Cell{StyleID: styleID2, Value: 2}}))
+	assert.NoError(t, sw.SetRow("A5", []interface{}{3}, RowOpts{StyleID: styleID2, OutlineLevel: 1}))
+	// Test set rows style range on the written rows
+	assert.EqualError(t, sw.SetRowStyleRange(5, 6, styleID1), "row 5 has already been written")
+	// Test set rows style range with invalid row number
+	assert.EqualError(t, sw.SetRowStyleRange(0, 6, styleID1), newInvalidRowNumberError(0).Error())
+	assert.Equal(t, ErrMaxRows, sw.SetRowStyleRange(6, TotalRows+1, styleID1))
+	// Test set rows style range with invalid style ID
+	assert.EqualError(t, sw.SetRowStyleRange(6, 6, -1), newInvalidStyleID(-1).Error())
+	assert.NoError(t, sw.Flush())
+	for row, expected := range map[int]int{1: 0, 2: styleID1, 3: styleID1, 4: styleID2, 5: styleID2, 6: 0, 7: styleID2, 8: 0} {
+		styleID, err := f.GetRowStyle("Sheet1", row)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, styleID, row)
+	}
+	for cell, expected := range map[string]int{"A1": 0, "A3": styleID1, "B3": styleID2, "A5": styleID2} {
+		styleID, err := f.GetCellStyle("Sheet1", cell)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, styleID, cell)
+	}
+	level, err := f.GetRowOutlineLevel("Sheet1", 5)
+	assert.NoError(t, err)
+	assert.Equal(t, uint8(1), level)
+	assert.NoError(t, f.Close())
+
+	// Test set rows style range with unsupported charset style sheet
+	f = NewFile()
+	sw, err = f.NewStreamWriter("Sheet1")
+	assert.NoError(t, err)
+	f.Styles = nil
+	f.Pkg.Store(defaultXMLPathStyles, MacintoshCyrillicCharset)
+	assert.EqualError(t, sw.SetRowStyleRange(1, 1, 0), "XML syntax error on line 1: invalid UTF-8")
+	assert.NoError(t, f.Close())
 }
 
 func TestStreamWriterOutlineLevel(t *testing.T) {
@@ -398,3 +679,57 @@ func TestStreamWriterOutlineLevel(t *testing.T) {
 	}
 	assert.NoError(t, file.Close())
 }
+
+func TestStreamSetRowSharedFormula(t *testing.T) {
+	file := NewFile()
+	streamWriter, err := file.NewStreamWriter("Sheet1")
+	assert.NoError(t, err)
+	for row, cells := range [][]interface{}{
+		{1, 2, Cell{Formula: "A1+B1", SharedFormula: &SharedFormula{Index: 0, Ref: "C1:C3"}}},
+		{3, 4, Cell{Formula: "A2+B2", SharedFormula: &SharedFormula{Index: 0}}},
+		{5, 6, &Cell{SharedFormula: &SharedFormula{Index: 0}}},
+	} {
+		assert.NoError(t, streamWriter.SetRow(fmt.Sprintf("A%d", row+1), cells, RowOpts{Spans: "1:3"}))
+	}
+	assert.NoError(t, streamWriter.Flush())
+	rows, err := file.Rows("Sheet1")
+	assert.NoError(t, err)
+	var results [][]RowCell
+	for rows.Next() {
+		cells, err := rows.Cells()
+		assert.NoError(t, err)
+		assert.Equal(t, RowOpts{Spans: "1:3"}, rows.GetRowOpts())
+		results = append(results, cells)
+	}
+	assert.NoError(t, rows.Close())
+	assert.Len(t, results, 3)
+	for row, expected := range []Cell{
+		{Formula: "A1+B1", SharedFormula: &SharedFormula{Index: 0, Ref: "C1:C3"}},
+		{Formula: "A2+B2", SharedFormula: &SharedFormula{Index: 0}},
+		{Formula: "A3+B3", SharedFormula: &SharedFormula{Index: 0}},
+	} {
+		assert.Equal(t, expected.Formula, results[row][2].Formula)
+		assert.Equal(t, expected.SharedFormula, results[row][2].SharedFormula)
+	}
+	formula, err := file.GetCellFormula("Sheet1", "C3")
+	assert.NoError(t, err)
+	assert.Equal(t, "A3+B3", formula)
+	// Test write the typed cells by the stream writer
+	_, err = file.NewSheet("Sheet2")
+	assert.NoError(t, err)
+	streamWriter, err = file.NewStreamWriter("Sheet2")
+	assert.NoError(t, err)
+	for row, cells := range results {
+		values := make([]interface{}, len(cells))
+		for col, cell := range cells {
+			values[col] = cell.Cell()
+		}
+		assert.NoError(t, streamWriter.SetRow(fmt.Sprintf("A%d", row+1), values))
+	}
+	assert.NoError(t, streamWriter.Flush())
+	assert.Equal(t, Cell{Formula: "A1+B1", SharedFormula: &SharedFormula{Index: 0, Ref: "C1:C3"}, Value: ""}, results[0][2].Cell())
+	formula, err = file.GetCellFormula("Sheet2", "C3")
+	assert.NoError(t, err)
+	assert.Equal(t, "A3+B3", formula)
+	assert.NoError(t, file.Close())
+}
@@ -21,6 +21,11 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"time"
+	"unicode"
+
+	"github.com/mohae/deepcopy"
+	"github.com/xuri/efp"
 )
 
 // validType defined the list of valid validation types.
@@ -109,14 +114,112 @@ var operatorType = map[string]string{
 func (f *File) stylesReader() (*xlsxStyleSheet, error) {
 	if f.Styles == nil {
 		f.Styles = new(xlsxStyleSheet)
-		if err := f.xmlNewDecoder(bytes.NewReader(namespaceStrictToTransitional(f.readXML(defaultXMLPathStyles)))).
+		start, content := time.Now(), f.readXML(defaultXMLPathStyles)
+		if err := f.xmlNewDecoder(bytes.NewReader(namespaceStrictToTransitional(content))).
 			Decode(f.Styles); err != nil && err != io.EOF {
 			return f.Styles, err
 		}
+		f.logPartParsed(defaultXMLPathStyles, len(content), start)
 	}
 	return f.Styles, nil
 }
 
+// pruneStyles provides a function to remove the cell formats which not
+// referenced by the cells, rows and columns of worksheets, and remove the
+// fonts, fills and borders which not referenced by the cell formats and cell
+// style formats, then update the style index of the cells, rows and columns.
+func (f *File) pruneStyles() error {
+	s, err := f.stylesReader()
+	if err != nil || s.CellXfs == nil || len(s.CellXfs.Xf) == 0 {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	xfs, indexes := []xlsxXf{s.CellXfs.Xf[0]}, map[int]int{0: 0}
+	getIndex := func(idx int) int {
+		if idx <= 0 || idx >= len(s.CellXfs.Xf) {
+			return 0
+		}
+		newIdx, ok := indexes[idx]
+		if !ok {
+			newIdx, indexes[idx] = len(xfs), len(xfs)
+			xfs = append(xfs, s.CellXfs.Xf[idx])
+		}
+		return newIdx
+	}
+	if err = f.rangeWorksheets(func(sheet string, ws *xlsxWorksheet) error {
+		for rowIdx, row := range ws.SheetData.Row {
+			ws.SheetData.Row[rowIdx].S = getIndex(row.S)
+			for colIdx, c := range row.C {
+				ws.SheetData.Row[rowIdx].C[colIdx].S = getIndex(c.S)
+			}
+		}
+		if ws.Cols != nil {
+			for idx, col := range ws.Cols.Col {
+				ws.Cols.Col[idx].Style = getIndex(col.Style)
+			}
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+	s.CellXfs.Xf, s.CellXfs.Count = xfs, len(xfs)
+	groups := [][]xlsxXf{s.CellXfs.Xf}
+	if s.CellStyleXfs != nil {
+		groups = append(groups, s.CellStyleXfs.Xf)
+	}
+	// pruneItems returns the indexes of the items to be kept, and the reserved
+	// items at the beginning will always be kept
+	pruneItems := func(count, reserved int, getID func(xf *xlsxXf) **int) []int {
+		var kept []int
+		newIndexes := map[int]int{}
+		for idx := 0; idx < reserved && idx < count; idx++ {
+			newIndexes[idx] = len(kept)
+			kept = append(kept, idx)
+		}
+		for _, xfs := range groups {
+			for idx := range xfs {
+				if id := *getID(&xfs[idx]); id != nil && *id >= 0 && *id < count {
+					if _, ok := newIndexes[*id]; !ok {
+						newIndexes[*id] = len(kept)
+						kept = append(kept, *id)
+					}
+				}
+			}
+		}
+		for _, xfs := range groups {
+			for idx := range xfs {
+				if id := getID(&xfs[idx]); *id != nil && **id >= 0 && **id < count {
+					*id = intPtr(newIndexes[**id])
+				}
+			}
+		}
+		return kept
+	}
+	if s.Fonts != nil {
+		var fonts []*xlsxFont
+		for _, idx := range pruneItems(len(s.Fonts.Font), 1, func(xf *xlsxXf) **int { return &xf.FontID }) {
+			fonts = append(fonts, s.Fonts.Font[idx])
+		}
+		s.Fonts.Font, s.Fonts.Count = fonts, len(fonts)
+	}
+	if s.Fills != nil {
+		var fills []*xlsxFill
+		for _, idx := range pruneItems(len(s.Fills.Fill), 2, func(xf *xlsxXf) **int { return &xf.FillID }) {
+			fills = append(fills, s.Fills.Fill[idx])
+		}
+		s.Fills.Fill, s.Fills.Count = fills, len(fills)
+	}
+	if s.Borders != nil {
+		var borders []*xlsxBorder
+		for _, idx := range pruneItems(len(s.Borders.Border), 1, func(xf *xlsxXf) **int { return &xf.BorderID }) {
+			borders = append(borders, s.Borders.Border[idx])
+		}
+		s.Borders.Border, s.Borders.Count = borders, len(borders)
+	}
+	return err
+}
+
 // styleSheetWriter provides a function to save xl/styles.xml after serialize
 // structure.
 func (f *File) styleSheetWriter() {
@@ -1022,6 +1125,21 @@ func parseFormatStyleSet(style *Style) (*Style, error) {
 //	err = f.SetCellStyle("Sheet1", "A6", "A6", style)
 //
 // Cell Sheet1!A6 in the Excel Application: martes, 04 de Julio de 2017
+//
+// The Checkbox specifies the cell checkbox control, which renders the boolean
+// cell value as a checkbox in the newer Excel application. For example, add a
+// checked checkbox in Sheet1!A7:
+//
+//	style, err := f.NewStyle(&excelize.Style{Checkbox: true})
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	if err = f.SetCellStyle("Sheet1", "A7", "A7", style); err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	err = f.SetCellBool("Sheet1", "A7", true)
 func (f *File) NewStyle(style *Style) (int, error) {
 	var (
 		fs                                  *Style
@@ -1088,7 +1206,20 @@ func (f *File) NewStyle(style *Style) (int, error) {
 
 	applyAlignment, alignment := fs.Alignment != nil, newAlignment(fs)
 	applyProtection, protection := fs.Protection != nil, newProtection(fs)
-	return setCellXfs(s, fontID, numFmtID, fillID, borderID, applyAlignment, applyProtection, alignment, protection)
+	var checkboxID int
+	if fs.Checkbox {
+		if checkboxID, err = f.getCheckboxComplementID(true); err != nil {
+			return cellXfsID, err
+		}
+	}
+	if cellXfsID, err = setCellXfs(s, fontID, numFmtID, fillID, borderID, applyAlignment, applyProtection, alignment, protection); err != nil || !fs.Checkbox {
+		return cellXfsID, err
+	}
+	s.CellXfs.Xf[cellXfsID].ExtLst = &xlsxExtLst{Ext: fmt.Sprintf(
+		`<ext uri="%s" xmlns:xfpb="%s"><xfpb:xfComplement i="%d"/></ext>`,
+		ExtURIFeaturePropertyBag, NameSpaceFeaturePropertyBag, checkboxID,
+	)}
+	return cellXfsID, err
 }
 
 var (
@@ -1196,6 +1327,12 @@ var (
 			}
 			return reflect.DeepEqual(xf.Protection, newProtection(style)) && xf.ApplyProtection != nil && *xf.ApplyProtection
 		},
+		"checkbox": func(checkboxID int, xf xlsxXf, style *Style) bool {
+			if !style.Checkbox {
+				return xf.getXfComplement() == -1
+			}
+			return checkboxID != -1 && xf.getXfComplement() == checkboxID
+		},
 	}
 	// extractStyleCondFuncs provides a function set to returns if shoudle be
 	// extract style definition by given style.
@@ -1415,7 +1552,11 @@ func (f *File) extractNumFmt(n *int, s *xlsxStyleSheet, style *Style) {
 		}
 		if s.NumFmts != nil {
 			for _, numFmt := range s.NumFmts.NumFmt {
-				style.CustomNumFmt = &numFmt.FormatCode
+				if numFmt.NumFmtID != numFmtID {
+					continue
+				}
+				code := numFmt.FormatCode
+				style.CustomNumFmt = &code
 				if strings.Contains(numFmt.FormatCode, ";[Red]") {
 					style.NegRed = true
 				}
@@ -1494,6 +1635,13 @@ func (f *File) GetStyle(idx int) (*Style, error) {
 		f.extractProtection(xf.Protection, s, style)
 	}
 	f.extractNumFmt(xf.NumFmtID, s, style)
+	if complementID := xf.getXfComplement(); complementID != -1 {
+		checkboxID, err := f.getCheckboxComplementID(false)
+		if err != nil {
+			return style, err
+		}
+		style.Checkbox = complementID == checkboxID
+	}
 	return style, nil
 }
 
@@ -1501,9 +1649,9 @@ func (f *File) GetStyle(idx int) (*Style, error) {
 // style does not exist, will return -1.
 func (f *File) getStyleID(ss *xlsxStyleSheet, style *Style) (int, error) {
 	var (
-		err     error
-		fontID  int
-		styleID = -1
+		err                error
+		fontID, checkboxID int
+		styleID            = -1
 	)
 	if ss.CellXfs == nil {
 		return styleID, err
@@ -1512,6 +1660,11 @@ func (f *File) getStyleID(ss *xlsxStyleSheet, style *Style) (int, error) {
 	if fontID, err = f.getFontID(ss, style); err != nil {
 		return styleID, err
 	}
+	if style.Checkbox {
+		if checkboxID, err = f.getCheckboxComplementID(false); err != nil {
+			return styleID, err
+		}
+	}
 	if style.CustomNumFmt != nil {
 		numFmtID = getCustomNumFmtID(ss, style)
 	}
@@ -1521,7 +1674,8 @@ func (f *File) getStyleID(ss *xlsxStyleSheet, style *Style) (int, error) {
 			getXfIDFuncs["fill"](fillID, xf, style) &&
 			getXfIDFuncs["border"](borderID, xf, style) &&
 			getXfIDFuncs["alignment"](0, xf, style) &&
-			getXfIDFuncs["protection"](0, xf, style) {
+			getXfIDFuncs["protection"](0, xf, style) &&
+			getXfIDFuncs["checkbox"](checkboxID, xf, style) {
 			styleID = xfID
 			return styleID, err
 		}
@@ -1529,6 +1683,239 @@ func (f *File) getStyleID(ss *xlsxStyleSheet, style *Style) (int, error) {
 	return styleID, err
 }
 
+// getXfComplement provides a function to get the index of the feature
+// property bag mapped by the cell format, returns -1 if the cell format
+// doesn't map any feature property bag.
+func (xf *xlsxXf) getXfComplement() int {
+	if xf.ExtLst == nil {
+		return -1
+	}
+	var extLst decodeExtLst
+	if err := xml.Unmarshal([]byte("<extLst>"+xf.ExtLst.Ext+"</extLst>"), &extLst); err != nil {
+		return -1
+	}
+	for _, ext := range extLst.Ext {
+		if ext.URI != ExtURIFeaturePropertyBag {
+			continue
+		}
+		var complement xlsxXfComplement
+		if err := xml.Unmarshal([]byte(ext.Content), &complement); err == nil {
+			return complement.I
+		}
+	}
+	return -1
+}
+
+// getFeaturePropertyBagPath provides a function to get the path of the
+// feature property bag part in the package, and returns the default path and
+// false if the workbook doesn't have a feature property bag part.
+func (f *File) getFeaturePropertyBagPath() (string, bool) {
+	rels, _ := f.relsReader(f.getWorkbookRelsPath())
+	if rels != nil {
+		rels.mu.Lock()
+		defer rels.mu.Unlock()
+		for _, rel := range rels.Relationships {
+			if rel.Type == SourceRelationshipFeaturePropertyBag {
+				return getRelsTargetPath(f.getWorkbookPath(), rel.Target), true
+			}
+		}
+	}
+	return defaultXMLPathFeaturePropertyBag, false
+}
+
+// featurePropertyBagReader provides a function to get the pointer to the
+// structure after deserialization of the feature property bag part by given
+// part path.
+func (f *File) featurePropertyBagReader(path string) (*xlsxFeaturePropertyBags, error) {
+	bags := new(xlsxFeaturePropertyBags)
+	if err := f.xmlNewDecoder(bytes.NewReader(namespaceStrictToTransitional(f.readXML(path)))).
+		Decode(bags); err != nil && err != io.EOF {
+		return bags, err
+	}
+	return bags, nil
+}
+
+// getBag provides a function to get the feature property bag by given index
+// and bag type, returns nil if the bag doesn't exist.
+func (bags *xlsxFeaturePropertyBags) getBag(idx int, bagType string) *xlsxFeaturePropertyBag {
+	if idx < 0 || idx >= len(bags.Bag) || bags.Bag[idx].Type != bagType {
+		return nil
+	}
+	return &bags.Bag[idx]
+}
+
+// getBagID provides a function to get the index of the referenced feature
+// property bag by given key, returns -1 if the key doesn't exist.
+func (bag *xlsxFeaturePropertyBag) getBagID(key string) int {
+	for _, bagID := range bag.BagID {
+		if bagID.K == key {
+			return bagID.Val
+		}
+	}
+	return -1
+}
+
+// getCheckboxComplementID provides a function to get the index of the cell
+// format complement which specifies the cell checkbox control, returns -1 if
+// the complement doesn't exist.
+func (bags *xlsxFeaturePropertyBags) getCheckboxComplementID() int {
+	for _, bag := range bags.Bag {
+		if bag.Type != "XFComplements" {
+			continue
+		}
+		for _, a := range bag.A {
+			if a.K != "MappedFeaturePropertyBags" {
+				continue
+			}
+			for idx, bagID := range a.BagID {
+				complement := bags.getBag(bagID.Val, "XFComplement")
+				if complement == nil {
+					continue
+				}
+				controls := bags.getBag(complement.getBagID("XFControls"), "XFControls")
+				if controls != nil && bags.getBag(controls.getBagID("CellControl"), "Checkbox") != nil {
+					return idx
+				}
+			}
+		}
+	}
+	return -1
+}
+
+// addCheckboxComplement provides a function to add the feature property bags
+// for the cell checkbox control, and returns the index of the cell format
+// complement.
+func (bags *xlsxFeaturePropertyBags) addCheckboxComplement() int {
+	n := len(bags.Bag)
+	bags.Bag = append(bags.Bag,
+		xlsxFeaturePropertyBag{Type: "Checkbox"},
+		xlsxFeaturePropertyBag{Type: "XFControls", BagID: []xlsxFeaturePropertyBagID{{K: "CellControl", Val: n}}},
+		xlsxFeaturePropertyBag{Type: "XFComplement", BagID: []xlsxFeaturePropertyBagID{{K: "XFControls", Val: n + 1}}},
+	)
+	bags.Count = len(bags.Bag)
+	for i := range bags.Bag {
+		if bags.Bag[i].Type != "XFComplements" {
+			continue
+		}
+		for j := range bags.Bag[i].A {
+			if a := &bags.Bag[i].A[j]; a.K == "MappedFeaturePropertyBags" {
+				a.BagID = append(a.BagID, xlsxFeaturePropertyBagID{Val: n + 2})
+				return len(a.BagID) - 1
+			}
+		}
+	}
+	bags.Bag = append(bags.Bag, xlsxFeaturePropertyBag{
+		Type: "XFComplements", ExtRef: "XFComplementsMapperExtRef",
+		A: []xlsxFeaturePropertyBagArray{{K: "MappedFeaturePropertyBags", BagID: []xlsxFeaturePropertyBagID{{Val: n + 2}}}},
+	})
+	bags.Count = len(bags.Bag)
+	return 0
+}
+
+// getCheckboxComplementID provides a function to get the index of the cell
+// format complement which specifies the cell checkbox control in the
+// workbook, the feature property bags will be created if not exists and
+// create is true, otherwise returns -1 if the complement doesn't exist.
+func (f *File) getCheckboxComplementID(create bool) (int, error) {
+	path, ok := f.getFeaturePropertyBagPath()
+	bags, err := f.featurePropertyBagReader(path)
+	if err != nil {
+		return -1, err
+	}
+	if checkboxID := bags.getCheckboxComplementID(); checkboxID != -1 || !create {
+		return checkboxID, err
+	}
+	checkboxID := bags.addCheckboxComplement()
+	output, _ := xml.Marshal(bags)
+	f.saveFileList(path, output)
+	if !ok {
+		f.addRels(f.getWorkbookRelsPath(), SourceRelationshipFeaturePropertyBag, "/"+defaultXMLPathFeaturePropertyBag, "")
+		err = f.addContentTypePart(0, "featurePropertyBag")
+	}
+	return checkboxID, err
+}
+
+// getBuiltInCellStyleXfID provides a function to get the index of the master
+// formatting record of the built-in cell style by given style sheet, cell
+// style name, built-in cell style ID and the theme color index of the font,
+// the cell style which uses the underline font with the theme color will be
+// created if not exists.
+func (f *File) getBuiltInCellStyleXfID(s *xlsxStyleSheet, name string, builtInID, theme int) int {
+	if s.CellStyles == nil {
+		s.CellStyles = &xlsxCellStyles{}
+	}
+	for _, cellStyle := range s.CellStyles.CellStyle {
+		if cellStyle.BuiltInID != nil && *cellStyle.BuiltInID == builtInID {
+			return cellStyle.XfID
+		}
+	}
+	font := deepcopy.Copy(s.Fonts.Font[0]).(*xlsxFont)
+	font.U, font.Color = &attrValString{Val: stringPtr("single")}, &xlsxColor{Theme: intPtr(theme)}
+	fontID := -1
+	for idx, fnt := range s.Fonts.Font {
+		if reflect.DeepEqual(fnt, font) {
+			fontID = idx
+			break
+		}
+	}
+	if fontID == -1 {
+		s.Fonts.Font = append(s.Fonts.Font, font)
+		s.Fonts.Count, fontID = len(s.Fonts.Font), len(s.Fonts.Font)-1
+	}
+	if s.CellStyleXfs == nil {
+		s.CellStyleXfs = &xlsxCellStyleXfs{}
+	}
+	s.CellStyleXfs.Xf = append(s.CellStyleXfs.Xf, xlsxXf{
+		NumFmtID: intPtr(0), FontID: intPtr(fontID), FillID: intPtr(0), BorderID: intPtr(0),
+		ApplyNumberFormat: boolPtr(false), ApplyFill: boolPtr(false), ApplyBorder: boolPtr(false),
+		ApplyAlignment: boolPtr(false), ApplyProtection: boolPtr(false),
+	})
+	s.CellStyleXfs.Count = len(s.CellStyleXfs.Xf)
+	xfID := s.CellStyleXfs.Count - 1
+	s.CellStyles.CellStyle = append(s.CellStyles.CellStyle, &xlsxCellStyle{Name: name, XfID: xfID, BuiltInID: intPtr(builtInID)})
+	s.CellStyles.Count = len(s.CellStyles.CellStyle)
+	return xfID
+}
+
+// setHyperlinkCellStyle provides a function to apply the built-in Hyperlink
+// cell style to the cell by given worksheet name and cell reference, and
+// register the built-in Followed Hyperlink cell style in the workbook. The
+// other formatting of the cell will be kept.
+func (f *File) setHyperlinkCellStyle(sheet, cell string) error {
+	styleID, err := f.GetCellStyle(sheet, cell)
+	if err != nil {
+		return err
+	}
+	f.mu.Lock()
+	s, err := f.stylesReader()
+	f.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	xfID := f.getBuiltInCellStyleXfID(s, "Hyperlink", 8, 10)
+	_ = f.getBuiltInCellStyleXfID(s, "Followed Hyperlink", 9, 11)
+	xf := s.CellXfs.Xf[styleID]
+	xf.FontID, xf.ApplyFont, xf.XfID = intPtr(*s.CellStyleXfs.Xf[xfID].FontID), boolPtr(true), intPtr(xfID)
+	styleID = -1
+	for idx, cellXf := range s.CellXfs.Xf {
+		if reflect.DeepEqual(cellXf, xf) {
+			styleID = idx
+			break
+		}
+	}
+	if styleID == -1 {
+		if len(s.CellXfs.Xf) == MaxCellStyles {
+			s.mu.Unlock()
+			return ErrCellStyles
+		}
+		s.CellXfs.Xf = append(s.CellXfs.Xf, xf)
+		s.CellXfs.Count, styleID = len(s.CellXfs.Xf), len(s.CellXfs.Xf)-1
+	}
+	s.mu.Unlock()
+	return f.SetCellStyle(sheet, cell, cell, styleID)
+}
+
 // NewConditionalStyle provides a function to create style for conditional
 // format by given style format. The parameters are the same with the NewStyle
 // function.
@@ -1648,7 +2035,9 @@ func (f *File) GetDefaultFont() (string, error) {
 	return *font.Name.Val, err
 }
 
-// SetDefaultFont changes the default font in the workbook.
+// SetDefaultFont changes the default font in the workbook. The minor font of
+// the theme will be changed too, so that the cells and the fonts which refer
+// to the minor font of the theme will be displayed in the given font.
 func (f *File) SetDefaultFont(fontName string) error {
 	font, err := f.readDefaultFont()
 	if err != nil {
@@ -1661,9 +2050,65 @@ func (f *File) SetDefaultFont(fontName string) error {
 	s.Fonts.Font[0] = font
 	custom := true
 	s.CellStyles.CellStyle[0].CustomBuiltIn = &custom
+	if f.Theme != nil {
+		minorFont := &f.Theme.ThemeElements.FontScheme.MinorFont
+		if minorFont.Latin == nil {
+			minorFont.Latin = &xlsxCTTextFont{}
+		}
+		minorFont.Latin.Typeface, minorFont.Latin.Panose = fontName, ""
+	}
 	return err
 }
 
+// GetDefaultFontSize provides the size of the default font currently set in
+// the workbook. The spreadsheet generated by excelize default font size is 11
+// points.
+func (f *File) GetDefaultFontSize() (float64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	s, err := f.stylesReader()
+	if err != nil {
+		return 0, err
+	}
+	return getFontSize(s, 0), err
+}
+
+// SetDefaultFontSize changes the size of the default font in the workbook.
+// The width of columns in pixels used for positioning the drawing objects and
+// fitting the row height will be calculated by the new default font size.
+// For example, set the default font size to 12 points:
+//
+//	err := f.SetDefaultFontSize(12)
+func (f *File) SetDefaultFontSize(size float64) error {
+	if size < MinFontSize || size > MaxFontSize {
+		return ErrFontSize
+	}
+	font, err := f.readDefaultFont()
+	if err != nil {
+		return err
+	}
+	font.Sz = &attrValFloat{Val: float64Ptr(size)}
+	f.mu.Lock()
+	s, _ := f.stylesReader()
+	f.mu.Unlock()
+	custom := true
+	s.CellStyles.CellStyle[0].CustomBuiltIn = &custom
+	return err
+}
+
+// getMaxDigitWidth provides a function to get the maximum digit width of the
+// default font in pixels, which is used for converting the column width to
+// pixels.
+func (f *File) getMaxDigitWidth() float64 {
+	s, err := f.stylesReader()
+	if err != nil {
+		// Keep the error for the following style sheet readers
+		f.Styles = nil
+		return defaultMaxDigitWidth
+	}
+	return math.Max(math.Round(defaultMaxDigitWidth*getFontSize(s, 0)/defaultFontSize), 1)
+}
+
 // readDefaultFont provides an un-marshalled font value.
 func (f *File) readDefaultFont() (*xlsxFont, error) {
 	f.mu.Lock()
@@ -2075,6 +2520,8 @@ func (f *File) GetCellStyle(sheet, cell string) (int, error) {
 // safe. Note that diagonalDown and diagonalUp type border should be use same
 // color in the same range. SetCellStyle will overwrite the existing
 // styles for the cell, it won't append or merge style with existing styles.
+// Use the SetSqrefStyle function to set the style of multiple discontiguous
+// cell ranges, whole columns or whole rows at once.
 //
 // For example create a borders of cell H9 on Sheet1:
 //
@@ -2229,6 +2676,122 @@ func (f *File) SetCellStyle(sheet, hCell, vCell string, styleID int) error {
 	return err
 }
 
+// SetSqrefStyle provides a function to set the style of the cells by given
+// worksheet name, reference sequence (sqref) and style ID. The reference
+// sequence could contain multiple discontiguous cell ranges separated by
+// spaces or commas, whole columns such as "C:C" or "C:E", and whole rows such
+// as "5:7". The styles of the cell ranges will be set in one pass, and the
+// whole columns and rows will be set by the SetColStyle and SetRowStyle
+// functions, so that the style will be also applied to the new cells of the
+// columns and rows. For example, set the style of the cell range A1:B2, the
+// cell D4, the column F and the rows 10 to 12 on Sheet1:
+//
+//	err := f.SetSqrefStyle("Sheet1", "A1:B2 D4 F:F 10:12", styleID)
+func (f *File) SetSqrefStyle(sheet, sqref string, styleID int) error {
+	var (
+		cells, rows [][]int
+		cols        []string
+	)
+	refs := strings.FieldsFunc(sqref, func(r rune) bool { return r == ' ' || r == ',' })
+	if len(refs) == 0 {
+		return ErrParameterInvalid
+	}
+	for _, ref := range refs {
+		ref = strings.ReplaceAll(ref, "$", "")
+		parts := strings.Split(ref, ":")
+		if len(parts) > 2 {
+			return ErrParameterInvalid
+		}
+		if strings.IndexFunc(ref, unicode.IsDigit) == -1 {
+			if _, _, err := f.parseColRange(ref); err != nil {
+				return err
+			}
+			cols = append(cols, ref)
+			continue
+		}
+		if strings.IndexFunc(ref, unicode.IsLetter) == -1 {
+			var rng []int
+			for _, part := range []string{parts[0], parts[len(parts)-1]} {
+				row, err := strconv.Atoi(part)
+				if err != nil || row < 1 || row > TotalRows {
+					return newInvalidRowNumberError(row)
+				}
+				rng = append(rng, row)
+			}
+			rows = append(rows, rng)
+			continue
+		}
+		coordinates, err := getDropListMappingRange(ref)
+		if err != nil {
+			return err
+		}
+		cells = append(cells, coordinates)
+	}
+	if err := f.setCellRangesStyle(sheet, cells, styleID); err != nil {
+		return err
+	}
+	for _, col := range cols {
+		if err := f.SetColStyle(sheet, col, styleID); err != nil {
+			return err
+		}
+	}
+	for _, rng := range rows {
+		if err := f.SetRowStyle(sheet, rng[0], rng[1], styleID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// setCellRangesStyle provides a function to set the style of the cell ranges
+// by given worksheet name, coordinates of the cell ranges and style ID, the
+// worksheet will be grown to cover all the cell ranges at once.
+func (f *File) setCellRangesStyle(sheet string, cells [][]int, styleID int) error {
+	f.mu.Lock()
+	ws, err := f.workSheetReader(sheet)
+	if err != nil {
+		f.mu.Unlock()
+		return err
+	}
+	s, err := f.stylesReader()
+	if err != nil {
+		f.mu.Unlock()
+		return err
+	}
+	f.mu.Unlock()
+	if styleID < 0 || s.CellXfs == nil || len(s.CellXfs.Xf) <= styleID {
+		return newInvalidStyleID(styleID)
+	}
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+	var (
+		maxRow int
+		cols   = map[int]int{}
+	)
+	for _, coordinates := range cells {
+		for row := coordinates[1]; row <= coordinates[3]; row++ {
+			if cols[row] < coordinates[2] {
+				cols[row] = coordinates[2]
+			}
+		}
+		if coordinates[3] > maxRow {
+			maxRow = coordinates[3]
+		}
+	}
+	ws.prepareSheetXML(0, maxRow)
+	for row, col := range cols {
+		fillColumns(&ws.SheetData.Row[row-1], col, row)
+	}
+	for _, coordinates := range cells {
+		for row := coordinates[1]; row <= coordinates[3]; row++ {
+			for col := coordinates[0]; col <= coordinates[2]; col++ {
+				ws.SheetData.Row[row-1].C[col-1].S = styleID
+			}
+		}
+	}
+	return err
+}
+
 // SetConditionalFormat provides a function to create conditional formatting
 // rule for cell value. Conditional formatting is a feature of Excel which
 // allows you to apply a format to a cell or a range of cells based on certain
@@ -2646,6 +3209,13 @@ func (f *File) SetConditionalFormat(sheet, rangeRef string, opts []ConditionalFo
 	if err != nil {
 		return err
 	}
+	return f.setConditionalFormat(ws, sheet, rangeRef, opts)
+}
+
+// setConditionalFormat provides a function to create conditional formatting
+// rules for the given worksheet.
+func (f *File) setConditionalFormat(ws *xlsxWorksheet, sheet, rangeRef string, opts []ConditionalFormatOptions) error {
+	var err error
 	if strings.Contains(rangeRef, ":") {
 		rect, err := rangeRefToCoordinates(rangeRef)
 		if err != nil {
@@ -2660,7 +3230,7 @@ func (f *File) SetConditionalFormat(sheet, rangeRef string, opts []ConditionalFo
 		rules += len(cf.CfRule)
 	}
 	var (
-		GUID            = fmt.Sprintf("{00000000-0000-0000-%04X-%012X}", f.getSheetID(sheet), rules)
+		sheetID         = f.getSheetID(sheet)
 		cfRule          []*xlsxCfRule
 		noCriteriaTypes = []string{
 			"containsBlanks",
@@ -2682,6 +3252,7 @@ func (f *File) SetConditionalFormat(sheet, rangeRef string, opts []ConditionalFo
 			if ok || inStrSlice(noCriteriaTypes, vt, true) != -1 {
 				drawFunc, ok := drawContFmtFunc[vt]
 				if ok {
+					GUID := fmt.Sprintf("{00000000-0000-0000-%04X-%012X}", sheetID, rules+p)
 					rule, x14rule := drawFunc(p, ct, strings.Split(rangeRef, ":")[0], GUID, &v)
 					if rule == nil {
 						return ErrParameterInvalid
@@ -2804,13 +3375,19 @@ func extractCondFmtTop10(c *xlsxCfRule, extLst *xlsxExtLst) ConditionalFormatOpt
 // settings for above average and below average by given conditional formatting
 // rule.
 func extractCondFmtAboveAverage(c *xlsxCfRule, extLst *xlsxExtLst) ConditionalFormatOptions {
-	return ConditionalFormatOptions{
+	format := ConditionalFormatOptions{
 		StopIfTrue:   c.StopIfTrue,
 		Type:         "average",
 		Criteria:     "=",
 		Format:       *c.DxfID,
-		AboveAverage: *c.AboveAverage,
+		AboveAverage: true,
+		EqualAverage: c.EqualAverage,
+		StdDev:       c.StdDev,
+	}
+	if c.AboveAverage != nil {
+		format.AboveAverage = *c.AboveAverage
 	}
+	return format
 }
 
 // extractCondFmtDuplicateUniqueValues provides a function to extract
@@ -2881,80 +3458,120 @@ func extractCondFmtColorScale(c *xlsxCfRule, extLst *xlsxExtLst) ConditionalForm
 		if c.ColorScale.Cfvo[0].Val != "0" {
 			format.MinValue = c.ColorScale.Cfvo[0].Val
 		}
-		format.MinColor = "#" + strings.TrimPrefix(strings.ToUpper(c.ColorScale.Color[0].RGB), "FF")
+		format.MinColor = getCondFmtColor(c.ColorScale.Color[0])
 		format.MaxType = c.ColorScale.Cfvo[1].Type
 		if c.ColorScale.Cfvo[1].Val != "0" {
 			format.MaxValue = c.ColorScale.Cfvo[1].Val
 		}
-		format.MaxColor = "#" + strings.TrimPrefix(strings.ToUpper(c.ColorScale.Color[1].RGB), "FF")
+		format.MaxColor = getCondFmtColor(c.ColorScale.Color[1])
 	}
-	if colors == 3 {
+	if colors == 3 && values == 3 {
 		format.Type = "3_color_scale"
 		format.MidType = c.ColorScale.Cfvo[1].Type
 		if c.ColorScale.Cfvo[1].Val != "0" {
 			format.MidValue = c.ColorScale.Cfvo[1].Val
 		}
-		format.MidColor = "#" + strings.TrimPrefix(strings.ToUpper(c.ColorScale.Color[1].RGB), "FF")
+		format.MidColor = getCondFmtColor(c.ColorScale.Color[1])
 		format.MaxType = c.ColorScale.Cfvo[2].Type
+		format.MaxValue = ""
 		if c.ColorScale.Cfvo[2].Val != "0" {
 			format.MaxValue = c.ColorScale.Cfvo[2].Val
 		}
-		format.MaxColor = "#" + strings.TrimPrefix(strings.ToUpper(c.ColorScale.Color[2].RGB), "FF")
+		format.MaxColor = getCondFmtColor(c.ColorScale.Color[2])
 	}
 	return format
 }
 
 // extractCondFmtDataBar provides a function to extract conditional format
-// settings for data bar by given conditional formatting rule.
+// settings for data bar by given conditional formatting rule. The settings
+// of the data bar in the worksheet extension list, such as the solid fill,
+// the direction, the border, negative value and axis settings will be
+// extracted by the ID of the rule.
 func extractCondFmtDataBar(c *xlsxCfRule, extLst *xlsxExtLst) ConditionalFormatOptions {
 	format := ConditionalFormatOptions{Type: "data_bar", Criteria: "="}
 	if c.DataBar != nil {
 		format.StopIfTrue = c.StopIfTrue
-		format.MinType = c.DataBar.Cfvo[0].Type
-		format.MinValue = c.DataBar.Cfvo[0].Val
-		format.MaxType = c.DataBar.Cfvo[1].Type
-		format.MaxValue = c.DataBar.Cfvo[1].Val
-		format.BarColor = "#" + strings.TrimPrefix(strings.ToUpper(c.DataBar.Color[0].RGB), "FF")
+		if len(c.DataBar.Cfvo) > 1 {
+			format.MinType = c.DataBar.Cfvo[0].Type
+			format.MinValue = c.DataBar.Cfvo[0].Val
+			format.MaxType = c.DataBar.Cfvo[1].Type
+			format.MaxValue = c.DataBar.Cfvo[1].Val
+		}
+		if len(c.DataBar.Color) > 0 {
+			format.BarColor = getCondFmtColor(c.DataBar.Color[0])
+		}
 		if c.DataBar.ShowValue != nil {
 			format.BarOnly = !*c.DataBar.ShowValue
 		}
 	}
-	extractDataBarRule := func(condFmts []decodeX14ConditionalFormatting) {
-		for _, condFmt := range condFmts {
+	if c.ExtLst == nil || extLst == nil {
+		return format
+	}
+	ruleExt := decodeX14ConditionalFormattingExt{}
+	if err := xml.Unmarshal([]byte(c.ExtLst.Ext), &ruleExt); err != nil {
+		return format
+	}
+	decodeExtLst := new(decodeExtLst)
+	if err := xml.Unmarshal([]byte("<extLst>"+extLst.Ext+"</extLst>"), decodeExtLst); err != nil {
+		return format
+	}
+	for _, ext := range decodeExtLst.Ext {
+		if ext.URI != ExtURIConditionalFormattings {
+			continue
+		}
+		decodeCondFmts := new(decodeX14ConditionalFormattings)
+		if err := xml.Unmarshal([]byte(ext.Content), &decodeCondFmts); err != nil {
+			continue
+		}
+		decoder := xml.NewDecoder(strings.NewReader(decodeCondFmts.Content))
+		for {
+			var condFmt decodeX14ConditionalFormatting
+			if err := decoder.Decode(&condFmt); err != nil {
+				break
+			}
 			for _, rule := range condFmt.CfRule {
-				if rule.DataBar != nil {
-					format.BarSolid = !rule.DataBar.Gradient
-					format.BarDirection = rule.DataBar.Direction
-					if rule.DataBar.BorderColor != nil {
-						format.BarBorderColor = "#" + strings.TrimPrefix(strings.ToUpper(rule.DataBar.BorderColor.RGB), "FF")
-					}
+				if rule.DataBar != nil && rule.ID == ruleExt.ID {
+					extractCondFmtX14DataBar(rule.DataBar, &format)
 				}
 			}
 		}
 	}
-	extractExtLst := func(extLst *decodeExtLst) {
-		for _, ext := range extLst.Ext {
-			if ext.URI == ExtURIConditionalFormattings {
-				decodeCondFmts := new(decodeX14ConditionalFormattings)
-				if err := xml.Unmarshal([]byte(ext.Content), &decodeCondFmts); err == nil {
-					var condFmts []decodeX14ConditionalFormatting
-					if err = xml.Unmarshal([]byte(decodeCondFmts.Content), &condFmts); err == nil {
-						extractDataBarRule(condFmts)
-					}
-				}
-			}
-		}
+	return format
+}
+
+// extractCondFmtX14DataBar provides a function to extract the data bar
+// settings in the worksheet extension list by given data bar.
+func extractCondFmtX14DataBar(dataBar *decodeX14DataBar, format *ConditionalFormatOptions) {
+	format.BarSolid = !dataBar.Gradient
+	format.BarDirection = dataBar.Direction
+	format.BarAxisPosition = dataBar.AxisPosition
+	if dataBar.BorderColor != nil {
+		format.BarBorderColor = getCondFmtColor(dataBar.BorderColor)
 	}
-	if c.ExtLst != nil {
-		ext := decodeX14ConditionalFormattingExt{}
-		if err := xml.Unmarshal([]byte(c.ExtLst.Ext), &ext); err == nil && extLst != nil {
-			decodeExtLst := new(decodeExtLst)
-			if err = xml.Unmarshal([]byte("<extLst>"+extLst.Ext+"</extLst>"), decodeExtLst); err == nil {
-				extractExtLst(decodeExtLst)
-			}
-		}
+	if dataBar.NegativeBorderColor != nil {
+		format.BarNegativeBorderColor = getCondFmtColor(dataBar.NegativeBorderColor)
 	}
-	return format
+	// The negative fill and axis colors are red by default
+	if color := getCondFmtColor(dataBar.NegativeFillColor); color != "#FF0000" {
+		format.BarNegativeColor = color
+	}
+	if color := getCondFmtColor(dataBar.AxisColor); color != "#FF0000" {
+		format.BarAxisColor = color
+	}
+}
+
+// getCondFmtColor provides a function to get the RGB color in the format of
+// "#RRGGBB" by given conditional format color, it returns an empty string if
+// the color is not specified by the RGB value.
+func getCondFmtColor(clr *xlsxColor) string {
+	if clr == nil || clr.RGB == "" {
+		return ""
+	}
+	RGB := strings.ToUpper(clr.RGB)
+	if len(RGB) == 8 {
+		RGB = RGB[2:]
+	}
+	return "#" + RGB
 }
 
 // extractCondFmtExp provides a function to extract conditional format settings
@@ -2970,13 +3587,30 @@ func extractCondFmtExp(c *xlsxCfRule, extLst *xlsxExtLst) ConditionalFormatOptio
 // extractCondFmtIconSet provides a function to extract conditional format
 // settings for icon sets by given conditional formatting rule.
 func extractCondFmtIconSet(c *xlsxCfRule, extLst *xlsxExtLst) ConditionalFormatOptions {
-	format := ConditionalFormatOptions{Type: "icon_set"}
+	format := ConditionalFormatOptions{Type: "icon_set", StopIfTrue: c.StopIfTrue}
 	if c.IconSet != nil {
 		if c.IconSet.ShowValue != nil {
 			format.IconsOnly = !*c.IconSet.ShowValue
 		}
 		format.IconStyle = c.IconSet.IconSet
+		if format.IconStyle == "" {
+			format.IconStyle = "3TrafficLights1"
+		}
 		format.ReverseIcons = c.IconSet.Reverse
+		// The threshold values will be returned if not the same as preset
+		preset, _ := drawCondFmtIconSet(0, "", "", "", &ConditionalFormatOptions{Type: "icon_set", IconStyle: format.IconStyle})
+		var values []ConditionalFormatValue
+		custom := preset == nil || len(preset.IconSet.Cfvo) != len(c.IconSet.Cfvo)
+		for idx, cfvo := range c.IconSet.Cfvo {
+			value := ConditionalFormatValue{Type: cfvo.Type, Value: cfvo.Val, GreaterThan: cfvo.Gte != nil && !*cfvo.Gte}
+			if !custom && (preset.IconSet.Cfvo[idx].Type != value.Type || preset.IconSet.Cfvo[idx].Val != value.Value || value.GreaterThan) {
+				custom = true
+			}
+			values = append(values, value)
+		}
+		if custom {
+			format.IconValues = values
+		}
 	}
 	return format
 }
@@ -3017,6 +3651,774 @@ func (f *File) UnsetConditionalFormat(sheet, rangeRef string) error {
 	return nil
 }
 
+// condFmtRule defines the conditional formatting rule with the range
+// reference which the rule applies to.
+type condFmtRule struct {
+	rangeRef string
+	rule     *xlsxCfRule
+}
+
+// EvaluateConditionalFormat provides a function to evaluate the conditional
+// formatting rules applied to the cell by given worksheet name and cell
+// reference, the formulas in the rules and the cells will be calculated by
+// the calculation engine. It returns the matched rules in the order of
+// priority, and the rules with lower priority won't be evaluated after a
+// matched rule with stop if true. The data bar, color scale and icon set
+// rules match the cells with numeric value, and the rendering values such as
+// the data bar length, color scale color and icon index will be returned in
+// the result, which is useful for mirroring the visual output of Excel. For
+// example, evaluate the conditional formatting rules for cell A1 in Sheet1:
+//
+//	results, err := f.EvaluateConditionalFormat("Sheet1", "A1")
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	for _, result := range results {
+//	    fmt.Println(result.Options.Type, result.BarPercent, result.Color, result.IconIndex)
+//	}
+func (f *File) EvaluateConditionalFormat(sheet, cell string) ([]ConditionalFormatResult, error) {
+	var results []ConditionalFormatResult
+	col, row, err := CellNameToCoordinates(cell)
+	if err != nil {
+		return results, err
+	}
+	ws, err := f.workSheetReader(sheet)
+	if err != nil {
+		return results, err
+	}
+	if _, err = f.stylesReader(); err != nil {
+		return results, err
+	}
+	var rules []condFmtRule
+	for _, cf := range ws.ConditionalFormatting {
+		if !inCondFmtRange(cf.SQRef, col, row) {
+			continue
+		}
+		for _, cr := range cf.CfRule {
+			if _, ok := extractContFmtFunc[cr.Type]; ok {
+				rules = append(rules, condFmtRule{rangeRef: cf.SQRef, rule: cr})
+			}
+		}
+	}
+	sort.SliceStable(rules, func(i, j int) bool {
+		return rules[i].rule.Priority < rules[j].rule.Priority
+	})
+	ctx := &calcContext{
+		maxCalcIterations: f.options.MaxCalcIterations,
+		iterations:        make(map[string]uint),
+		iterationsCache:   make(map[string]formulaArg),
+	}
+	value, err := f.cellResolver(ctx, sheet, cell)
+	if err != nil {
+		return results, err
+	}
+	for _, r := range rules {
+		result := ConditionalFormatResult{
+			RangeRef: r.rangeRef,
+			Priority: r.rule.Priority,
+			Options:  extractContFmtFunc[r.rule.Type](r.rule, ws.ExtLst),
+		}
+		matched, err := f.evalCondFmtRule(ctx, ws, sheet, cell, value, r, &result)
+		if err != nil {
+			return results, err
+		}
+		if !matched {
+			continue
+		}
+		results = append(results, result)
+		if r.rule.StopIfTrue {
+			break
+		}
+	}
+	return results, err
+}
+
+// inCondFmtRange returns whether the cell is in the range reference (sqref)
+// of the conditional formatting by given cell coordinates.
+func inCondFmtRange(rangeRef string, col, row int) bool {
+	for _, ref := range strings.Fields(rangeRef) {
+		coordinates, err := getDropListMappingRange(ref)
+		if err != nil {
+			return false
+		}
+		if coordinates[0] <= col && col <= coordinates[2] && coordinates[1] <= row && row <= coordinates[3] {
+			return true
+		}
+	}
+	return false
+}
+
+// evalCondFmtRule provides a function to evaluate the conditional formatting
+// rule for the cell by given cell value, and set the rendering values of the
+// data bar, color scale and icon set rules in the result.
+func (f *File) evalCondFmtRule(ctx *calcContext, ws *xlsxWorksheet, sheet, cell string, value formulaArg, r condFmtRule, result *ConditionalFormatResult) (bool, error) {
+	col, row, err := getCondFmtAnchor(r.rangeRef)
+	if err != nil {
+		return false, err
+	}
+	anchor, _ := CoordinatesToCellName(col, row)
+	rule := r.rule
+	switch rule.Type {
+	case "cellIs":
+		return f.evalCondFmtCellIs(sheet, cell, anchor, value, rule)
+	case "top10", "aboveAverage", "duplicateValues", "uniqueValues",
+		"colorScale", "dataBar", "iconSet":
+		values, err := f.getCondFmtRangeValues(ctx, ws, sheet, r.rangeRef)
+		if err != nil {
+			return false, err
+		}
+		if rule.Type == "duplicateValues" || rule.Type == "uniqueValues" {
+			return evalCondFmtDuplicateUniqueValues(rule, value, values), err
+		}
+		number, ok := condFmtNumber(value)
+		numbers := condFmtNumbers(values)
+		if !ok || len(numbers) == 0 {
+			return false, err
+		}
+		switch rule.Type {
+		case "top10":
+			return evalCondFmtTop10(rule, number, numbers), err
+		case "aboveAverage":
+			return evalCondFmtAboveAverage(rule, number, numbers), err
+		}
+		return f.evalCondFmtScale(sheet, anchor, rule, number, numbers, result)
+	}
+	if len(rule.Formula) == 0 {
+		return false, err
+	}
+	arg, err := f.evalRelativeFormula(sheet, cell, anchor, rule.Formula[0], nil)
+	return condFmtTrue(arg), err
+}
+
+// evalCondFmtCellIs provides a function to evaluate the cell value type
+// conditional formatting rule by given cell value, the formulas of the rule
+// are written for the anchor cell.
+func (f *File) evalCondFmtCellIs(sheet, cell, anchor string, value formulaArg, rule *xlsxCfRule) (bool, error) {
+	var operator DataValidationOperator
+	for op, name := range dataValidationOperatorMap {
+		if name == rule.Operator {
+			operator = op
+		}
+	}
+	var operands []formulaArg
+	for _, formula := range rule.Formula {
+		arg, err := f.evalRelativeFormula(sheet, cell, anchor, formula, nil)
+		if err != nil {
+			return false, err
+		}
+		if list := arg.ToList(); len(list) > 0 {
+			arg = list[0]
+		}
+		operands = append(operands, arg)
+	}
+	number, ok := condFmtNumber(value)
+	var numbers []float64
+	for _, operand := range operands {
+		if n, isNum := condFmtNumber(operand); isNum {
+			numbers = append(numbers, n)
+		}
+	}
+	if ok && len(numbers) == len(operands) {
+		return compareDataValidationOperands(operator, number, numbers), nil
+	}
+	if len(operands) == 0 || value.Value() == "" {
+		return false, nil
+	}
+	switch operator {
+	case DataValidationOperatorEqual:
+		return strings.EqualFold(value.Value(), operands[0].Value()), nil
+	case DataValidationOperatorNotEqual:
+		return !strings.EqualFold(value.Value(), operands[0].Value()), nil
+	}
+	return false, nil
+}
+
+// getCondFmtRangeValues provides a function to get the values of the cells
+// in the range reference (sqref) of the conditional formatting.
+func (f *File) getCondFmtRangeValues(ctx *calcContext, ws *xlsxWorksheet, sheet, rangeRef string) ([]formulaArg, error) {
+	var values []formulaArg
+	for _, row := range ws.SheetData.Row {
+		for _, c := range row.C {
+			col, r, err := CellNameToCoordinates(c.R)
+			if err != nil || !inCondFmtRange(rangeRef, col, r) {
+				continue
+			}
+			arg, err := f.cellResolver(ctx, sheet, c.R)
+			if err != nil {
+				return values, err
+			}
+			values = append(values, arg)
+		}
+	}
+	return values, nil
+}
+
+// evalCondFmtDuplicateUniqueValues provides a function to evaluate the
+// duplicate and unique values conditional formatting rule by given cell value
+// and the values in the range, the values are compared case-insensitively.
+func evalCondFmtDuplicateUniqueValues(rule *xlsxCfRule, value formulaArg, values []formulaArg) bool {
+	if value.Value() == "" {
+		return false
+	}
+	var count int
+	for _, v := range values {
+		if strings.EqualFold(v.Value(), value.Value()) {
+			count++
+		}
+	}
+	return (count > 1) == (rule.Type == "duplicateValues")
+}
+
+// evalCondFmtTop10 provides a function to evaluate the top and bottom
+// conditional formatting rule by given number and the numbers in the range.
+func evalCondFmtTop10(rule *xlsxCfRule, number float64, numbers []float64) bool {
+	rank := rule.Rank
+	if rule.Percent {
+		if rank = len(numbers) * rank / 100; rank < 1 {
+			rank = 1
+		}
+	}
+	var count int
+	for _, n := range numbers {
+		if (rule.Bottom && n < number) || (!rule.Bottom && n > number) {
+			count++
+		}
+	}
+	return count < rank
+}
+
+// evalCondFmtAboveAverage provides a function to evaluate the above and below
+// average conditional formatting rule by given number and the numbers in the
+// range, the standard deviation is calculated for the entire population.
+func evalCondFmtAboveAverage(rule *xlsxCfRule, number float64, numbers []float64) bool {
+	var sum, variance float64
+	for _, n := range numbers {
+		sum += n
+	}
+	mean := sum / float64(len(numbers))
+	for _, n := range numbers {
+		variance += (n - mean) * (n - mean)
+	}
+	threshold := mean
+	above := rule.AboveAverage == nil || *rule.AboveAverage
+	if rule.StdDev > 0 {
+		stdDev := float64(rule.StdDev) * math.Sqrt(variance/float64(len(numbers)))
+		if above {
+			threshold += stdDev
+		} else {
+			threshold -= stdDev
+		}
+	}
+	if rule.EqualAverage && number == threshold {
+		return true
+	}
+	if above {
+		return number > threshold
+	}
+	return number < threshold
+}
+
+// evalCondFmtScale provides a function to evaluate the color scale, data bar
+// and icon set conditional formatting rule by given number and the sorted
+// numbers in the range, and set the rendering values in the result.
+func (f *File) evalCondFmtScale(sheet, anchor string, rule *xlsxCfRule, number float64, numbers []float64, result *ConditionalFormatResult) (bool, error) {
+	var cfvos []*xlsxCfvo
+	switch {
+	case rule.ColorScale != nil:
+		cfvos = rule.ColorScale.Cfvo
+	case rule.DataBar != nil:
+		cfvos = rule.DataBar.Cfvo
+	case rule.IconSet != nil:
+		cfvos = rule.IconSet.Cfvo
+	}
+	if len(cfvos) < 2 {
+		return false, nil
+	}
+	thresholds := make([]float64, len(cfvos))
+	for i, cfvo := range cfvos {
+		val, err := f.getCondFmtCfvoValue(sheet, anchor, cfvo, numbers)
+		if err != nil {
+			return false, err
+		}
+		thresholds[i] = val
+	}
+	switch {
+	case rule.ColorScale != nil:
+		if len(rule.ColorScale.Color) < len(thresholds) {
+			return false, nil
+		}
+		result.Color = f.getCondFmtScaleColor(rule.ColorScale.Color, thresholds, number)
+	case rule.DataBar != nil:
+		low, high := thresholds[0], thresholds[1]
+		result.BarPercent = 100
+		if high > low {
+			result.BarPercent = math.Min(math.Max((number-low)/(high-low)*100, 0), 100)
+		} else if number < high {
+			result.BarPercent = 0
+		}
+	default:
+		for i := 1; i < len(cfvos); i++ {
+			if number > thresholds[i] || (number == thresholds[i] && (cfvos[i].Gte == nil || *cfvos[i].Gte)) {
+				result.IconIndex = i
+			}
+		}
+		if rule.IconSet.Reverse {
+			result.IconIndex = len(cfvos) - 1 - result.IconIndex
+		}
+	}
+	return true, nil
+}
+
+// getCondFmtCfvoValue provides a function to get the value of the
+// conditional format value object by given sorted numbers in the range.
+func (f *File) getCondFmtCfvoValue(sheet, anchor string, cfvo *xlsxCfvo, numbers []float64) (float64, error) {
+	low, high := numbers[0], numbers[len(numbers)-1]
+	switch cfvo.Type {
+	case "min":
+		return low, nil
+	case "max":
+		return high, nil
+	}
+	val, err := strconv.ParseFloat(cfvo.Val, 64)
+	if err != nil && cfvo.Val != "" {
+		arg, err := f.evalRelativeFormula(sheet, anchor, anchor, cfvo.Val, nil)
+		if err != nil {
+			return val, err
+		}
+		val, _ = condFmtNumber(arg)
+	}
+	switch cfvo.Type {
+	case "percent":
+		return low + (high-low)*val/100, nil
+	case "percentile":
+		pos := math.Min(math.Max(val/100, 0), 1) * float64(len(numbers)-1)
+		idx := int(pos)
+		if idx+1 >= len(numbers) {
+			return high, nil
+		}
+		return numbers[idx] + (pos-float64(idx))*(numbers[idx+1]-numbers[idx]), nil
+	}
+	return val, nil
+}
+
+// getCondFmtScaleColor provides a function to get the interpolated color of
+// the color scale by given colors, thresholds and the number.
+func (f *File) getCondFmtScaleColor(colors []*xlsxColor, thresholds []float64, number float64) string {
+	last := len(thresholds) - 1
+	if number <= thresholds[0] {
+		return f.getCondFmtRGB(colors[0])
+	}
+	if number >= thresholds[last] {
+		return f.getCondFmtRGB(colors[last])
+	}
+	for i := 0; i < last; i++ {
+		if number > thresholds[i+1] {
+			continue
+		}
+		from, to := f.getCondFmtRGB(colors[i]), f.getCondFmtRGB(colors[i+1])
+		if len(from) != 7 || len(to) != 7 || thresholds[i+1] == thresholds[i] {
+			return to
+		}
+		ratio, color := (number-thresholds[i])/(thresholds[i+1]-thresholds[i]), "#"
+		for c := 1; c < 7; c += 2 {
+			a, _ := strconv.ParseUint(from[c:c+2], 16, 8)
+			b, _ := strconv.ParseUint(to[c:c+2], 16, 8)
+			color += fmt.Sprintf("%02X", int(math.Round(float64(a)+(float64(b)-float64(a))*ratio)))
+		}
+		return color
+	}
+	return f.getCondFmtRGB(colors[last])
+}
+
+// getCondFmtRGB provides a function to get the RGB color in the format of
+// "#RRGGBB" by given conditional format color, the theme and indexed color
+// will be converted to the RGB color.
+func (f *File) getCondFmtRGB(clr *xlsxColor) string {
+	if color := getCondFmtColor(clr); color != "" || clr == nil {
+		return color
+	}
+	if RGB := f.getThemeColor(clr); RGB != "" {
+		return "#" + strings.ToUpper(RGB)
+	}
+	return ""
+}
+
+// condFmtNumber provides a function to convert the formula argument to the
+// number for the conditional formatting, returns false if the argument is
+// not a number.
+func condFmtNumber(arg formulaArg) (float64, bool) {
+	if list := arg.ToList(); len(list) > 0 {
+		arg = list[0]
+	}
+	if arg.Type != ArgNumber || arg.Boolean {
+		return 0, false
+	}
+	return arg.Number, true
+}
+
+// condFmtNumbers provides a function to get the sorted numbers in the given
+// formula arguments.
+func condFmtNumbers(values []formulaArg) []float64 {
+	var numbers []float64
+	for _, value := range values {
+		if n, ok := condFmtNumber(value); ok {
+			numbers = append(numbers, n)
+		}
+	}
+	sort.Float64s(numbers)
+	return numbers
+}
+
+// condFmtTrue returns whether the formula argument evaluated by the formula
+// of the conditional formatting rule is true.
+func condFmtTrue(arg formulaArg) bool {
+	if list := arg.ToList(); len(list) > 0 {
+		arg = list[0]
+	}
+	switch arg.Type {
+	case ArgNumber:
+		return arg.Number != 0
+	case ArgString:
+		return strings.EqualFold(arg.String, "TRUE")
+	}
+	return false
+}
+
+// getCondFmtAnchor provides a function to get the top-left cell coordinates
+// of the first range in the given range reference (sqref), the relative
+// references in the formula of the expression type conditional formatting
+// rule are evaluated from this cell.
+func getCondFmtAnchor(rangeRef string) (int, int, error) {
+	refs := strings.Fields(rangeRef)
+	if len(refs) == 0 {
+		return 0, 0, ErrParameterInvalid
+	}
+	coordinates, err := getDropListMappingRange(refs[0])
+	if err != nil {
+		return 0, 0, err
+	}
+	return coordinates[0], coordinates[1], err
+}
+
+// ConditionalFormatColumnFormula provides a function to generate the formula
+// of the expression type conditional formatting rule by given range
+// reference, column name and criteria, which checks the cell in the given
+// column of each row in the range. The column of the reference will be
+// absolute and the row will be relative to the first row of the range, so
+// that the whole row in the range will be formatted when the cell in the
+// given column meets the criteria. For example, highlight the rows in the
+// range A2:H100 on Sheet1 which the value of the column D is "FAIL":
+//
+//	formula, err := excelize.ConditionalFormatColumnFormula("A2:H100", "D", `="FAIL"`)
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	// The formula is: $D2="FAIL"
+//	err = f.SetConditionalFormat("Sheet1", "A2:H100",
+//	    []excelize.ConditionalFormatOptions{
+//	        {Type: "formula", Criteria: formula, Format: format},
+//	    },
+//	)
+func ConditionalFormatColumnFormula(rangeRef, column, criteria string) (string, error) {
+	_, row, err := getCondFmtAnchor(rangeRef)
+	if err != nil {
+		return "", err
+	}
+	if _, err = ColumnNameToNumber(column); err != nil {
+		return "", err
+	}
+	if criteria == "" {
+		return "", ErrParameterInvalid
+	}
+	return "$" + strings.ToUpper(column) + strconv.Itoa(row) + criteria, err
+}
+
+// ConditionalFormatRowFormula provides a function to generate the formula of
+// the expression type conditional formatting rule by given range reference,
+// row number and criteria, which checks the cell in the given row of each
+// column in the range. The row of the reference will be absolute and the
+// column will be relative to the first column of the range, so that the
+// whole column in the range will be formatted when the cell in the given row
+// meets the criteria. For example, highlight the columns in the range
+// B2:M20 which the value of the row 1 is greater than 100:
+//
+//	formula, err := excelize.ConditionalFormatRowFormula("B2:M20", 1, ">100")
+//
+// The formula is: B$1>100
+func ConditionalFormatRowFormula(rangeRef string, row int, criteria string) (string, error) {
+	col, _, err := getCondFmtAnchor(rangeRef)
+	if err != nil {
+		return "", err
+	}
+	if row < 1 || row > TotalRows {
+		return "", newInvalidRowNumberError(row)
+	}
+	if criteria == "" {
+		return "", ErrParameterInvalid
+	}
+	colName, _ := ColumnNumberToName(col)
+	return colName + "$" + strconv.Itoa(row) + criteria, err
+}
+
+// ConditionalFormatRowBandingFormula provides a function to generate the
+// formula of the expression type conditional formatting rule by given range
+// reference and band size, which formats the alternate bands of rows in the
+// range, the first band of the range will be formatted. For example, format
+// every other row in the range A2:H100:
+//
+//	formula, err := excelize.ConditionalFormatRowBandingFormula("A2:H100", 1)
+//
+// The formula is: MOD(ROW()-ROW($A$2),2)=0, and the formula for the bands of
+// 3 rows is: MOD(INT((ROW()-ROW($A$2))/3),2)=0
+func ConditionalFormatRowBandingFormula(rangeRef string, size int) (string, error) {
+	return getCondFmtBandingFormula(rangeRef, "ROW", size)
+}
+
+// ConditionalFormatColumnBandingFormula provides a function to generate the
+// formula of the expression type conditional formatting rule by given range
+// reference and band size, which formats the alternate bands of columns in
+// the range, the first band of the range will be formatted. For example,
+// format every other column in the range B2:M20:
+//
+//	formula, err := excelize.ConditionalFormatColumnBandingFormula("B2:M20", 1)
+//
+// The formula is: MOD(COLUMN()-COLUMN($B$2),2)=0
+func ConditionalFormatColumnBandingFormula(rangeRef string, size int) (string, error) {
+	return getCondFmtBandingFormula(rangeRef, "COLUMN", size)
+}
+
+// getCondFmtBandingFormula provides a function to generate the formula of the
+// expression type conditional formatting rule for the alternate bands of
+// rows or columns by given range reference, function name and band size.
+func getCondFmtBandingFormula(rangeRef, fn string, size int) (string, error) {
+	col, row, err := getCondFmtAnchor(rangeRef)
+	if err != nil {
+		return "", err
+	}
+	if size < 1 {
+		return "", ErrParameterInvalid
+	}
+	anchor, _ := CoordinatesToCellName(col, row, true)
+	offset := fmt.Sprintf("%s()-%s(%s)", fn, fn, anchor)
+	if size > 1 {
+		offset = fmt.Sprintf("INT((%s)/%d)", offset, size)
+	}
+	return fmt.Sprintf("MOD(%s,2)=0", offset), err
+}
+
+// ConditionalFormatRelativeFormula provides a function to generate the
+// formula of the expression type conditional formatting rule by given range
+// reference, anchor cell reference and formula written for the anchor cell.
+// The relative references in the formula will be shifted by the offset
+// between the anchor cell and the top-left cell of the first range in the
+// range reference, the absolute references, references in other workbooks
+// and defined names will not be changed. For example, write the formula for
+// the cell D5 and apply the rule to the range A2:H100:
+//
+//	formula, err := excelize.ConditionalFormatRelativeFormula("A2:H100", "D5", `AND($D5="FAIL",E5>E$1)`)
+//
+// The formula is: AND($D2="FAIL",B2>B$1)
+func ConditionalFormatRelativeFormula(rangeRef, cell, formula string) (string, error) {
+	col, row, err := getCondFmtAnchor(rangeRef)
+	if err != nil {
+		return "", err
+	}
+	anchorCol, anchorRow, err := CellNameToCoordinates(cell)
+	if err != nil {
+		return "", err
+	}
+	var (
+		val string
+		ps  = efp.ExcelParser()
+	)
+	for _, token := range ps.Parse(formula) {
+		if token.TType == efp.TokenTypeOperand && token.TSubType == efp.TokenSubTypeRange &&
+			!strings.ContainsAny(token.TValue, "[]") {
+			operand, err := shiftCondFmtOperand(token.TValue, col-anchorCol, row-anchorRow)
+			if err != nil {
+				return "", err
+			}
+			val += operand
+			continue
+		}
+		if isFunctionStart(token) {
+			val += token.TValue + string(efp.ParenOpen)
+			continue
+		}
+		if isFunctionStop(token) {
+			val += token.TValue + string(efp.ParenClose)
+			continue
+		}
+		if token.TType == efp.TokenTypeOperand && token.TSubType == efp.TokenSubTypeText {
+			val += string(efp.QuoteDouble) + strings.ReplaceAll(token.TValue, "\"", "\"\"") + string(efp.QuoteDouble)
+			continue
+		}
+		val += token.TValue
+	}
+	return val, err
+}
+
+// shiftCondFmtOperand provides a function to shift the relative references of
+// the range operand by given column and row offset. The operands which are
+// not cell references, whole column or whole row references, such as defined
+// names, will be returned as-is.
+func shiftCondFmtOperand(operand string, dCol, dRow int) (string, error) {
+	var prefix string
+	if i := strings.LastIndex(operand, "!"); i != -1 {
+		prefix, operand = escapeSheetName(operand[:i])+"!", operand[i+1:]
+	}
+	refs := strings.Split(operand, ":")
+	if len(refs) > 2 {
+		return prefix + operand, nil
+	}
+	for i, ref := range refs {
+		col, row, isCol, isRow := parseCondFmtRef(ref)
+		if !isCol && !isRow {
+			return prefix + operand, nil
+		}
+		if len(refs) == 1 && (!isCol || !isRow) {
+			return prefix + operand, nil
+		}
+		var val string
+		if isCol {
+			abs := strings.HasPrefix(col, "$")
+			num, _ := ColumnNameToNumber(strings.TrimPrefix(col, "$"))
+			if !abs {
+				if num += dCol; num < 1 || num > MaxColumns {
+					return "", ErrColumnNumber
+				}
+			}
+			if val, _ = ColumnNumberToName(num); abs {
+				val = "$" + val
+			}
+		}
+		if isRow {
+			abs := strings.HasPrefix(row, "$")
+			num, _ := strconv.Atoi(strings.TrimPrefix(row, "$"))
+			if !abs {
+				if num += dRow; num < 1 || num > TotalRows {
+					return "", newInvalidRowNumberError(num)
+				}
+			}
+			if abs {
+				val += "$"
+			}
+			val += strconv.Itoa(num)
+		}
+		refs[i] = val
+	}
+	return prefix + strings.Join(refs, ":"), nil
+}
+
+// parseCondFmtRef provides a function to split the cell reference, whole
+// column or whole row reference into the column name and row number with
+// the absolute reference mark, and returns whether the column and row exist.
+func parseCondFmtRef(ref string) (string, string, bool, bool) {
+	var i int
+	if strings.HasPrefix(ref, "$") {
+		i++
+	}
+	for i < len(ref) && (('A' <= ref[i] && ref[i] <= 'Z') || ('a' <= ref[i] && ref[i] <= 'z')) {
+		i++
+	}
+	col, row := ref[:i], ref[i:]
+	if col == "$" {
+		col, row = "", ref
+	}
+	if col != "" {
+		if _, err := ColumnNameToNumber(strings.TrimPrefix(col, "$")); err != nil {
+			return col, row, false, false
+		}
+	}
+	if row == "" {
+		return col, row, col != "", false
+	}
+	num, err := strconv.Atoi(strings.TrimPrefix(row, "$"))
+	if err != nil || num < 1 || num > TotalRows {
+		return col, row, false, false
+	}
+	return col, row, col != "", true
+}
+
+// SetConditionalCurrencyFormat provides a function to set the number formats
+// which switching the currency symbol based on the value of another cell or
+// defined name for the cells by given worksheet name, range reference and
+// currency format settings. An expression type conditional formatting rule
+// with the multi-section number format of positive, negative and zero values
+// will be created for each currency, and the cells keep their own number
+// format if the code doesn't match any currency. The DecimalPlaces specifies
+// the decimal places of the number formats, the default value is 2, and the
+// negative numbers will be shown in red if the NegRed is true. For example,
+// set the currency formats of the range B2:D20 on Sheet1 by the currency code
+// in the cell Sheet1!$B$1:
+//
+//	err := f.SetConditionalCurrencyFormat("Sheet1", "B2:D20",
+//	    &excelize.ConditionalCurrencyFormatOptions{
+//	        Ref: "$B$1",
+//	        Currencies: []excelize.CurrencyFormat{
+//	            {Code: "USD", Symbol: "$", Locale: "409"},
+//	            {Code: "EUR", Symbol: "€", Locale: "407"},
+//	            {Code: "JPY", Symbol: "¥", Locale: "411"},
+//	        },
+//	        NegRed: true,
+//	    },
+//	)
+//
+// The number format of the EUR currency in the example is:
+//
+//	[$€-407]#,##0.00;[Red]-[$€-407]#,##0.00;[$€-407]0.00
+func (f *File) SetConditionalCurrencyFormat(sheet, rangeRef string, opts *ConditionalCurrencyFormatOptions) error {
+	if opts == nil {
+		return ErrParameterRequired
+	}
+	decimalPlaces := 2
+	if opts.DecimalPlaces != nil {
+		decimalPlaces = *opts.DecimalPlaces
+	}
+	if opts.Ref == "" || len(opts.Currencies) == 0 || decimalPlaces < 0 || decimalPlaces > 30 {
+		return ErrParameterInvalid
+	}
+	var formats []ConditionalFormatOptions
+	for _, currency := range opts.Currencies {
+		if currency.Code == "" || currency.Symbol == "" {
+			return ErrParameterInvalid
+		}
+		numFmt := getCurrencyNumFmt(currency, decimalPlaces, opts.NegRed)
+		format, err := f.NewConditionalStyle(&Style{CustomNumFmt: &numFmt})
+		if err != nil {
+			return err
+		}
+		formats = append(formats, ConditionalFormatOptions{
+			Type:       "formula",
+			Criteria:   opts.Ref + "=\"" + strings.ReplaceAll(currency.Code, "\"", "\"\"") + "\"",
+			Format:     format,
+			StopIfTrue: true,
+		})
+	}
+	return f.SetConditionalFormat(sheet, rangeRef, formats)
+}
+
+// getCurrencyNumFmt provides a function to generate the multi-section number
+// format of positive, negative and zero values by given currency settings,
+// decimal places and if the negative numbers will be shown in red.
+func getCurrencyNumFmt(currency CurrencyFormat, decimalPlaces int, negRed bool) string {
+	symbol := "[$" + currency.Symbol + "]"
+	if currency.Locale != "" {
+		symbol = "[$" + currency.Symbol + "-" + currency.Locale + "]"
+	}
+	var decimal string
+	if decimalPlaces > 0 {
+		decimal = "." + strings.Repeat("0", decimalPlaces)
+	}
+	negative := "-" + symbol + "#,##0" + decimal
+	if negRed {
+		negative = "[Red]" + negative
+	}
+	return symbol + "#,##0" + decimal + ";" + negative + ";" + symbol + "0" + decimal
+}
+
 // drawCondFmtCellIs provides a function to create conditional formatting rule
 // for cell value (include between, not between, equal, not equal, greater
 // than and less than) by given priority, criteria type and format settings.
@@ -3122,6 +4524,8 @@ func drawCondFmtAboveAverage(p int, ct, ref, GUID string, format *ConditionalFor
 		StopIfTrue:   format.StopIfTrue,
 		Type:         validType[format.Type],
 		AboveAverage: boolPtr(format.AboveAverage),
+		EqualAverage: format.EqualAverage,
+		StdDev:       format.StdDev,
 		DxfID:        intPtr(format.Format),
 	}, nil
 }
@@ -3182,7 +4586,8 @@ func drawCondFmtColorScale(p int, ct, ref, GUID string, format *ConditionalForma
 func drawCondFmtDataBar(p int, ct, ref, GUID string, format *ConditionalFormatOptions) (*xlsxCfRule, *xlsxX14CfRule) {
 	var x14CfRule *xlsxX14CfRule
 	var extLst *xlsxExtLst
-	if format.BarSolid || format.BarDirection == "leftToRight" || format.BarDirection == "rightToLeft" || format.BarBorderColor != "" {
+	if format.BarSolid || format.BarDirection == "leftToRight" || format.BarDirection == "rightToLeft" || format.BarBorderColor != "" ||
+		format.BarNegativeColor != "" || format.BarNegativeBorderColor != "" || format.BarAxisColor != "" || format.BarAxisPosition != "" {
 		extLst = &xlsxExtLst{Ext: fmt.Sprintf(`<ext uri="%s" xmlns:x14="%s"><x14:id>%s</x14:id></ext>`, ExtURIConditionalFormattingRuleID, NameSpaceSpreadSheetX14.Value, GUID)}
 		x14CfRule = &xlsxX14CfRule{
 			Type: validType[format.Type],
@@ -3200,6 +4605,17 @@ func drawCondFmtDataBar(p int, ct, ref, GUID string, format *ConditionalFormatOp
 		if x14CfRule.DataBar.Border {
 			x14CfRule.DataBar.BorderColor = &xlsxColor{RGB: getPaletteColor(format.BarBorderColor)}
 		}
+		if format.BarNegativeColor != "" {
+			x14CfRule.DataBar.NegativeFillColor = &xlsxColor{RGB: getPaletteColor(format.BarNegativeColor)}
+		}
+		if format.BarNegativeBorderColor != "" {
+			x14CfRule.DataBar.NegativeBarBorderColorSameAsPositive = boolPtr(false)
+			x14CfRule.DataBar.NegativeBorderColor = &xlsxColor{RGB: getPaletteColor(format.BarNegativeBorderColor)}
+		}
+		if format.BarAxisColor != "" {
+			x14CfRule.DataBar.AxisColor = &xlsxColor{RGB: getPaletteColor(format.BarAxisColor)}
+		}
+		x14CfRule.DataBar.AxisPosition = format.BarAxisPosition
 	}
 	return &xlsxCfRule{
 		Priority:   p + 1,
@@ -3318,7 +4734,16 @@ func drawCondFmtIconSet(p int, ct, ref, GUID string, format *ConditionalFormatOp
 	if !ok {
 		return nil, nil
 	}
+	if len(format.IconValues) == len(cfRule.IconSet.Cfvo) {
+		for idx, value := range format.IconValues {
+			cfRule.IconSet.Cfvo[idx] = &xlsxCfvo{Type: value.Type, Val: value.Value}
+			if value.GreaterThan {
+				cfRule.IconSet.Cfvo[idx].Gte = boolPtr(false)
+			}
+		}
+	}
 	cfRule.Priority = p + 1
+	cfRule.StopIfTrue = format.StopIfTrue
 	cfRule.IconSet.IconSet = format.IconStyle
 	cfRule.IconSet.Reverse = format.ReverseIcons
 	cfRule.IconSet.ShowValue = boolPtr(!format.IconsOnly)
@@ -228,6 +228,15 @@ func TestGetConditionalFormats(t *testing.T) {
 		{{Type: "errors", Format: 1}},
 		{{Type: "no_errors", Format: 1}},
 		{{Type: "icon_set", IconStyle: "3Arrows", ReverseIcons: true, IconsOnly: true}},
+		{{Type: "average", EqualAverage: true, StdDev: 2, Format: 1, Criteria: "="}},
+		{{Type: "data_bar", Criteria: "=", MinType: "min", MaxType: "max", BarColor: "#638EC6", BarNegativeColor: "#00FF00", BarNegativeBorderColor: "#0000FF", BarAxisColor: "#000000", BarAxisPosition: "middle"}},
+		{
+			{Type: "data_bar", Criteria: "=", MinType: "min", MaxType: "max", BarColor: "#638EC6", BarDirection: "leftToRight"},
+			{Type: "data_bar", Criteria: "=", MinType: "min", MaxType: "max", BarColor: "#63BE7B", BarDirection: "rightToLeft", BarSolid: true},
+		},
+		{{Type: "icon_set", IconStyle: "3Arrows", StopIfTrue: true, IconValues: []ConditionalFormatValue{
+			{Type: "percent", Value: "0"}, {Type: "num", Value: "10", GreaterThan: true}, {Type: "formula", Value: "$A$1"},
+		}}},
 	} {
 		f := NewFile()
 		err := f.SetConditionalFormat("Sheet1", "A2:A1", format)
@@ -236,9 +245,29 @@ func TestGetConditionalFormats(t *testing.T) {
 		assert.NoError(t, err)
 		assert.Equal(t, format, opts["A1:A2"])
 	}
-	// Test get conditional formats on no exists worksheet
+	// Test get conditional formats with the default attributes
 	f := NewFile()
-	_, err := f.GetConditionalFormats("SheetN")
+	ws, ok := f.Sheet.Load("xl/worksheets/sheet1.xml")
+	assert.True(t, ok)
+	ws.(*xlsxWorksheet).ConditionalFormatting = []*xlsxConditionalFormatting{{SQRef: "A1:A2", CfRule: []*xlsxCfRule{
+		{Type: "aboveAverage", DxfID: intPtr(0)},
+		{Type: "iconSet", IconSet: &xlsxIconSet{Cfvo: []*xlsxCfvo{{Type: "percent", Val: "0"}, {Type: "percent", Val: "33"}, {Type: "percent", Val: "67"}}}},
+		{Type: "colorScale", ColorScale: &xlsxColorScale{
+			Cfvo:  []*xlsxCfvo{{Type: "min"}, {Type: "max"}},
+			Color: []*xlsxColor{{Theme: intPtr(4)}, {RGB: "FF63BE7B"}},
+		}},
+		{Type: "dataBar", DataBar: &xlsxDataBar{}},
+	}}}
+	opts, err := f.GetConditionalFormats("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, []ConditionalFormatOptions{
+		{Type: "average", Criteria: "=", AboveAverage: true},
+		{Type: "icon_set", IconStyle: "3TrafficLights1"},
+		{Type: "2_color_scale", Criteria: "=", MinType: "min", MaxType: "max", MaxColor: "#63BE7B"},
+		{Type: "data_bar", Criteria: "="},
+	}, opts["A1:A2"])
+	// Test get conditional formats on no exists worksheet
+	_, err = f.GetConditionalFormats("SheetN")
 	assert.EqualError(t, err, "sheet SheetN does not exist")
 	// Test get conditional formats with invalid sheet name
 	_, err = f.GetConditionalFormats("Sheet:1")
@@ -261,6 +290,234 @@ func TestUnsetConditionalFormat(t *testing.T) {
 	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestUnsetConditionalFormat.xlsx")))
 }
 
+func TestEvaluateConditionalFormat(t *testing.T) {
+	f := NewFile()
+	for row := 1; row <= 10; row++ {
+		for _, col := range []string{"A", "B", "C", "D", "E"} {
+			assert.NoError(t, f.SetCellValue("Sheet1", fmt.Sprintf("%s%d", col, row), row))
+		}
+		assert.NoError(t, f.SetCellFormula("Sheet1", fmt.Sprintf("F%d", row), fmt.Sprintf("A%d*2", row)))
+	}
+	for cell, value := range map[string]string{"G1": "a", "G2": "A", "G3": "b", "H1": "apple pie", "H2": "apple"} {
+		assert.NoError(t, f.SetCellValue("Sheet1", cell, value))
+	}
+	format, err := f.NewConditionalStyle(&Style{Font: &Font{Color: "9A0511"}})
+	assert.NoError(t, err)
+	for rangeRef, opts := range map[string][]ConditionalFormatOptions{
+		"A1:A10": {{Type: "data_bar", Criteria: "=", MinType: "min", MaxType: "max", BarColor: "#638EC6"}},
+		"B1:B10": {{Type: "2_color_scale", Criteria: "=", MinType: "min", MaxType: "max", MinColor: "#000000", MaxColor: "#FFFFFF"}},
+		"C1:C10": {{Type: "icon_set", IconStyle: "3Arrows"}},
+		"D1:D10": {
+			{Type: "cell", Criteria: ">", Format: format, Value: "$A$8", StopIfTrue: true},
+			{Type: "top", Criteria: "=", Format: format, Value: "5"},
+			{Type: "average", Criteria: "=", Format: format, AboveAverage: false},
+		},
+		"E1:E10": {{Type: "cell", Criteria: "between", Format: format, MinValue: "3", MaxValue: "$F$2"}},
+		"F1:F10": {{Type: "formula", Criteria: "$F1>10", Format: format}},
+		"G1:G3":  {{Type: "duplicate", Criteria: "=", Format: format}},
+		"H1:H2":  {{Type: "text", Criteria: "containing", Format: format, Value: "pie"}},
+	} {
+		assert.NoError(t, f.SetConditionalFormat("Sheet1", rangeRef, opts))
+	}
+	for _, c := range []struct {
+		cell       string
+		types      []string
+		barPercent float64
+		color      string
+		iconIndex  int
+	}{
+		{cell: "A1", types: []string{"data_bar"}},
+		{cell: "A4", types: []string{"data_bar"}, barPercent: 100.0 / 3},
+		{cell: "A10", types: []string{"data_bar"}, barPercent: 100},
+		{cell: "B1", types: []string{"2_color_scale"}, color: "#000000"},
+		{cell: "B4", types: []string{"2_color_scale"}, color: "#555555"},
+		{cell: "B10", types: []string{"2_color_scale"}, color: "#FFFFFF"},
+		{cell: "C1", types: []string{"icon_set"}},
+		{cell: "C5", types: []string{"icon_set"}, iconIndex: 1},
+		{cell: "C10", types: []string{"icon_set"}, iconIndex: 2},
+		{cell: "D2", types: []string{"average"}},
+		{cell: "D7", types: []string{"top"}},
+		{cell: "D9", types: []string{"cell"}},
+		{cell: "E2", types: nil},
+		{cell: "E4", types: []string{"cell"}},
+		{cell: "E5", types: nil},
+		{cell: "F5", types: nil},
+		{cell: "F6", types: []string{"formula"}},
+		{cell: "G2", types: []string{"duplicate"}},
+		{cell: "G3", types: nil},
+		{cell: "H1", types: []string{"text"}},
+		{cell: "H2", types: nil},
+		{cell: "J1", types: nil},
+	} {
+		results, err := f.EvaluateConditionalFormat("Sheet1", c.cell)
+		assert.NoError(t, err, c.cell)
+		var types []string
+		for _, result := range results {
+			types = append(types, result.Options.Type)
+		}
+		assert.Equal(t, c.types, types, c.cell)
+		if len(results) == 1 {
+			assert.InDelta(t, c.barPercent, results[0].BarPercent, 1e-9, c.cell)
+			assert.Equal(t, c.color, results[0].Color, c.cell)
+			assert.Equal(t, c.iconIndex, results[0].IconIndex, c.cell)
+		}
+	}
+	// Test evaluate conditional format with the theme color of color scale
+	ws, ok := f.Sheet.Load("xl/worksheets/sheet1.xml")
+	assert.True(t, ok)
+	for _, cf := range ws.(*xlsxWorksheet).ConditionalFormatting {
+		if cf.SQRef == "B1:B10" {
+			cf.CfRule[0].ColorScale.Color[1] = &xlsxColor{Theme: intPtr(1)}
+		}
+	}
+	results, err := f.EvaluateConditionalFormat("Sheet1", "B10")
+	assert.NoError(t, err)
+	assert.Len(t, results, 1)
+	assert.Equal(t, "#000000", results[0].Color)
+	// Test evaluate conditional format with invalid cell reference
+	_, err = f.EvaluateConditionalFormat("Sheet1", "A")
+	assert.Equal(t, newCellNameToCoordinatesError("A", newInvalidCellNameError("A")), err)
+	// Test evaluate conditional format on not exists worksheet
+	_, err = f.EvaluateConditionalFormat("SheetN", "A1")
+	assert.EqualError(t, err, "sheet SheetN does not exist")
+	// Test evaluate conditional format with unsupported charset style sheet
+	f.Styles = nil
+	f.Pkg.Store(defaultXMLPathStyles, MacintoshCyrillicCharset)
+	_, err = f.EvaluateConditionalFormat("Sheet1", "A1")
+	assert.EqualError(t, err, "XML syntax error on line 1: invalid UTF-8")
+}
+
+func TestConditionalFormatFormulas(t *testing.T) {
+	formula, err := ConditionalFormatColumnFormula("A2:H100", "d", `="FAIL"`)
+	assert.NoError(t, err)
+	assert.Equal(t, `$D2="FAIL"`, formula)
+	formula, err = ConditionalFormatColumnFormula("H100:A2 J2:J100", "D", `="FAIL"`)
+	assert.NoError(t, err)
+	assert.Equal(t, `$D2="FAIL"`, formula)
+	formula, err = ConditionalFormatRowFormula("B2:M20", 1, ">100")
+	assert.NoError(t, err)
+	assert.Equal(t, "B$1>100", formula)
+	formula, err = ConditionalFormatRowBandingFormula("A2:H100", 1)
+	assert.NoError(t, err)
+	assert.Equal(t, "MOD(ROW()-ROW($A$2),2)=0", formula)
+	formula, err = ConditionalFormatRowBandingFormula("A2:H100", 3)
+	assert.NoError(t, err)
+	assert.Equal(t, "MOD(INT((ROW()-ROW($A$2))/3),2)=0", formula)
+	formula, err = ConditionalFormatColumnBandingFormula("B2:M20", 1)
+	assert.NoError(t, err)
+	assert.Equal(t, "MOD(COLUMN()-COLUMN($B$2),2)=0", formula)
+	for anchor, expected := range map[string]string{
+		`AND($D5="FAIL",E5>E$1)`:           `AND($D2="FAIL",B2>B$1)`,
+		`SUM(E5:E$6)>Sheet2!$A$1+Total`:    `SUM(B2:B$6)>Sheet2!$A$1+Total`,
+		`COUNTIF(D:D,D5)>1`:                `COUNTIF(A:A,A2)>1`,
+		`COUNTIF($D:$D,D5)+SUM(5:$6)>1`:    `COUNTIF($D:$D,A2)+SUM(2:$6)>1`,
+		`COUNTIF('Sheet 2'!D5:E6,"x")>1`:   `COUNTIF('Sheet 2'!A2:B3,"x")>1`,
+		`[1]Sheet1!D5+Table1[[#This Row]]`: `[1]Sheet1!D5+Table1[[#This Row]]`,
+	} {
+		formula, err = ConditionalFormatRelativeFormula("A2:H100", "D5", anchor)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, formula)
+	}
+	f := NewFile()
+	format, err := f.NewConditionalStyle(&Style{Font: &Font{Color: "9A0511"}})
+	assert.NoError(t, err)
+	formula, err = ConditionalFormatColumnFormula("A2:H100", "D", `="FAIL"`)
+	assert.NoError(t, err)
+	assert.NoError(t, f.SetConditionalFormat("Sheet1", "A2:H100", []ConditionalFormatOptions{
+		{Type: "formula", Criteria: formula, Format: format},
+	}))
+	opts, err := f.GetConditionalFormats("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, `$D2="FAIL"`, opts["A2:H100"][0].Criteria)
+	assert.NoError(t, f.Close())
+
+	// Test generate formulas with invalid range reference
+	for _, rangeRef := range []string{"", "A", "A1:B"} {
+		_, err = ConditionalFormatColumnFormula(rangeRef, "D", "=1")
+		assert.Error(t, err)
+		_, err = ConditionalFormatRowFormula(rangeRef, 1, "=1")
+		assert.Error(t, err)
+		_, err = ConditionalFormatRowBandingFormula(rangeRef, 1)
+		assert.Error(t, err)
+		_, err = ConditionalFormatRelativeFormula(rangeRef, "A1", "A1=1")
+		assert.Error(t, err)
+	}
+	// Test generate formulas with invalid parameters
+	_, err = ConditionalFormatColumnFormula("A2:H100", "-", "=1")
+	assert.EqualError(t, err, newInvalidColumnNameError("-").Error())
+	_, err = ConditionalFormatColumnFormula("A2:H100", "D", "")
+	assert.Equal(t, ErrParameterInvalid, err)
+	_, err = ConditionalFormatRowFormula("A2:H100", 0, "=1")
+	assert.EqualError(t, err, newInvalidRowNumberError(0).Error())
+	_, err = ConditionalFormatRowFormula("A2:H100", 1, "")
+	assert.Equal(t, ErrParameterInvalid, err)
+	_, err = ConditionalFormatColumnBandingFormula("A2:H100", 0)
+	assert.Equal(t, ErrParameterInvalid, err)
+	_, err = ConditionalFormatRelativeFormula("A2:H100", "A", "A1=1")
+	assert.EqualError(t, err, newCellNameToCoordinatesError("A", newInvalidCellNameError("A")).Error())
+	// Test generate formula with the references out of range after shifted
+	_, err = ConditionalFormatRelativeFormula("A1:H100", "D5", "C5=1")
+	assert.Equal(t, ErrColumnNumber, err)
+	_, err = ConditionalFormatRelativeFormula("A1:H100", "D5", "D4=1")
+	assert.EqualError(t, err, newInvalidRowNumberError(0).Error())
+}
+
+func TestSetConditionalCurrencyFormat(t *testing.T) {
+	f := NewFile()
+	assert.NoError(t, f.SetCellValue("Sheet1", "B1", "EUR"))
+	assert.NoError(t, f.SetSheetRow("Sheet1", "B2", &[]float64{1234.5, -20, 0}))
+	assert.NoError(t, f.SetConditionalCurrencyFormat("Sheet1", "B2:D20", &ConditionalCurrencyFormatOptions{
+		Ref: "$B$1",
+		Currencies: []CurrencyFormat{
+			{Code: "USD", Symbol: "$", Locale: "409"},
+			{Code: "EUR", Symbol: "€", Locale: "407"},
+			{Code: `"JPY"`, Symbol: "¥"},
+		},
+		NegRed: true,
+	}))
+	formats, err := f.GetConditionalFormats("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string][]ConditionalFormatOptions{"B2:D20": {
+		{Type: "formula", Criteria: `$B$1="USD"`, Format: 0, StopIfTrue: true},
+		{Type: "formula", Criteria: `$B$1="EUR"`, Format: 1, StopIfTrue: true},
+		{Type: "formula", Criteria: `$B$1="""JPY"""`, Format: 2, StopIfTrue: true},
+	}}, formats)
+	for idx, numFmt := range []string{
+		"[$$-409]#,##0.00;[Red]-[$$-409]#,##0.00;[$$-409]0.00",
+		"[$€-407]#,##0.00;[Red]-[$€-407]#,##0.00;[$€-407]0.00",
+		"[$¥]#,##0.00;[Red]-[$¥]#,##0.00;[$¥]0.00",
+	} {
+		assert.Equal(t, numFmt, f.Styles.Dxfs.Dxfs[idx].NumFmt.FormatCode)
+	}
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestSetConditionalCurrencyFormat.xlsx")))
+	// Test set conditional currency format without decimal places
+	assert.NoError(t, f.SetConditionalCurrencyFormat("Sheet1", "F2:F20", &ConditionalCurrencyFormatOptions{
+		Ref: "Currency", Currencies: []CurrencyFormat{{Code: "GBP", Symbol: "£"}}, DecimalPlaces: intPtr(0),
+	}))
+	assert.Equal(t, "[$£]#,##0;-[$£]#,##0;[$£]0", f.Styles.Dxfs.Dxfs[3].NumFmt.FormatCode)
+	// Test set conditional currency format with invalid options
+	assert.Equal(t, ErrParameterRequired, f.SetConditionalCurrencyFormat("Sheet1", "B2:D20", nil))
+	for _, opts := range []*ConditionalCurrencyFormatOptions{
+		{Currencies: []CurrencyFormat{{Code: "USD", Symbol: "$"}}},
+		{Ref: "$B$1"},
+		{Ref: "$B$1", Currencies: []CurrencyFormat{{Code: "USD", Symbol: "$"}}, DecimalPlaces: intPtr(31)},
+		{Ref: "$B$1", Currencies: []CurrencyFormat{{Symbol: "$"}}},
+		{Ref: "$B$1", Currencies: []CurrencyFormat{{Code: "USD"}}},
+	} {
+		assert.Equal(t, ErrParameterInvalid, f.SetConditionalCurrencyFormat("Sheet1", "B2:D20", opts))
+	}
+	// Test set conditional currency format on not exists worksheet
+	assert.EqualError(t, f.SetConditionalCurrencyFormat("SheetN", "B2:D20", &ConditionalCurrencyFormatOptions{
+		Ref: "$B$1", Currencies: []CurrencyFormat{{Code: "USD", Symbol: "$"}},
+	}), "sheet SheetN does not exist")
+	// Test set conditional currency format with unsupported charset style sheet
+	f.Styles = nil
+	f.Pkg.Store(defaultXMLPathStyles, MacintoshCyrillicCharset)
+	assert.EqualError(t, f.SetConditionalCurrencyFormat("Sheet1", "B2:D20", &ConditionalCurrencyFormatOptions{
+		Ref: "$B$1", Currencies: []CurrencyFormat{{Code: "USD", Symbol: "$"}},
+	}), "XML syntax error on line 1: invalid UTF-8")
+}
+
 func TestNewStyle(t *testing.T) {
 	f := NewFile()
 	for i := 0; i < 18; i++ {
@@ -446,12 +703,49 @@ func TestSetDefaultFont(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, s, "Arial", "Default font should change to Arial")
 	assert.Equal(t, *styles.CellStyles.CellStyle[0].CustomBuiltIn, true)
+	assert.Equal(t, "Arial", f.Theme.ThemeElements.FontScheme.MinorFont.Latin.Typeface)
+	// Test set default font without minor font in the theme
+	f.Theme.ThemeElements.FontScheme.MinorFont.Latin = nil
+	assert.NoError(t, f.SetDefaultFont("Times New Roman"))
+	assert.Equal(t, "Times New Roman", f.Theme.ThemeElements.FontScheme.MinorFont.Latin.Typeface)
 	// Test set default font with unsupported charset style sheet
 	f.Styles = nil
 	f.Pkg.Store(defaultXMLPathStyles, MacintoshCyrillicCharset)
 	assert.EqualError(t, f.SetDefaultFont("Arial"), "XML syntax error on line 1: invalid UTF-8")
 }
 
+func TestDefaultFontSize(t *testing.T) {
+	f := NewFile()
+	size, err := f.GetDefaultFontSize()
+	assert.NoError(t, err)
+	assert.Equal(t, 11.0, size)
+	assert.Equal(t, 64, f.getColWidth("Sheet1", 1))
+	assert.NoError(t, f.SetDefaultFontSize(14))
+	size, err = f.GetDefaultFontSize()
+	assert.NoError(t, err)
+	assert.Equal(t, 14.0, size)
+	// Test get column width in pixels by the maximum digit width of the
+	// default font
+	assert.Equal(t, 80, f.getColWidth("Sheet1", 1))
+	assert.NoError(t, f.SetColWidth("Sheet1", "B", "B", 10))
+	assert.Equal(t, 96, f.getColWidth("Sheet1", 2))
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestDefaultFontSize.xlsx")))
+	// Test set default font size with invalid size
+	assert.Equal(t, ErrFontSize, f.SetDefaultFontSize(0))
+	assert.Equal(t, ErrFontSize, f.SetDefaultFontSize(MaxFontSize+1))
+	// Test get and set default font size with unsupported charset style sheet
+	f.Styles = nil
+	f.Pkg.Store(defaultXMLPathStyles, MacintoshCyrillicCharset)
+	_, err = f.GetDefaultFontSize()
+	assert.EqualError(t, err, "XML syntax error on line 1: invalid UTF-8")
+	f.Styles = nil
+	assert.EqualError(t, f.SetDefaultFontSize(12), "XML syntax error on line 1: invalid UTF-8")
+	// Test get column width in pixels with unsupported charset style sheet
+	f.Styles = nil
+	assert.Equal(t, 64, f.getColWidth("Sheet1", 3))
+	assert.Nil(t, f.Styles)
+}
+
 func TestStylesReader(t *testing.T) {
 	f := NewFile()
 	// Test read styles with unsupported charset
@@ -485,6 +779,118 @@ func TestSetCellStyle(t *testing.T) {
 	assert.EqualError(t, f.SetCellStyle("Sheet1", "A1", "A2", 1), "XML syntax error on line 1: invalid UTF-8")
 }
 
+func TestSetSqrefStyle(t *testing.T) {
+	f := NewFile()
+	styleID, err := f.NewStyle(&Style{Font: &Font{Bold: true}})
+	assert.NoError(t, err)
+	assert.NoError(t, f.SetCellValue("Sheet1", "H20", "value"))
+	assert.NoError(t, f.SetSqrefStyle("Sheet1", "B2:A1 D4,$F:$F 12:10 H:I", styleID))
+	for cell, expected := range map[string]int{
+		"A1": styleID, "B2": styleID, "C3": 0, "D4": styleID, "E4": 0,
+		"F1": styleID, "F100": styleID, "H20": styleID, "A11": styleID, "Z12": styleID, "A13": 0,
+	} {
+		style, err := f.GetCellStyle("Sheet1", cell)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, style, cell)
+	}
+	style, err := f.GetColStyle("Sheet1", "I")
+	assert.NoError(t, err)
+	assert.Equal(t, styleID, style)
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestSetSqrefStyle.xlsx")))
+
+	// Test set style with invalid reference sequence
+	for _, sqref := range []string{"", " , ", "A1:B2:C3", "A1:B", "A:XFE", "0:1", "1:1048577", ":1"} {
+		assert.Error(t, f.SetSqrefStyle("Sheet1", sqref, styleID), sqref)
+	}
+	// Test set style on not exists worksheet
+	assert.EqualError(t, f.SetSqrefStyle("SheetN", "A1", styleID), "sheet SheetN does not exist")
+	assert.EqualError(t, f.SetSqrefStyle("SheetN", "A:A", styleID), "sheet SheetN does not exist")
+	// Test set style with invalid style ID
+	assert.Equal(t, newInvalidStyleID(10), f.SetSqrefStyle("Sheet1", "A1", 10))
+	assert.Equal(t, newInvalidStyleID(10), f.SetSqrefStyle("Sheet1", "1:2", 10))
+	// Test set style with unsupported charset style sheet
+	f.Styles = nil
+	f.Pkg.Store(defaultXMLPathStyles, MacintoshCyrillicCharset)
+	assert.EqualError(t, f.SetSqrefStyle("Sheet1", "A1", styleID), "XML syntax error on line 1: invalid UTF-8")
+	assert.NoError(t, f.Close())
+}
+
+func TestNewStyleCheckbox(t *testing.T) {
+	f := NewFile()
+	checkbox, err := f.NewStyle(&Style{Checkbox: true})
+	assert.NoError(t, err)
+	styleID, err := f.NewStyle(&Style{Checkbox: true})
+	assert.NoError(t, err)
+	assert.Equal(t, checkbox, styleID)
+	styleID, err = f.NewStyle(&Style{Alignment: &Alignment{Horizontal: "center"}})
+	assert.NoError(t, err)
+	assert.NotEqual(t, checkbox, styleID)
+	style, err := f.GetStyle(checkbox)
+	assert.NoError(t, err)
+	assert.True(t, style.Checkbox)
+	style, err = f.GetStyle(styleID)
+	assert.NoError(t, err)
+	assert.False(t, style.Checkbox)
+	assert.NoError(t, f.SetCellStyle("Sheet1", "A1", "A2", checkbox))
+	assert.NoError(t, f.SetCellBool("Sheet1", "A1", true))
+	assert.NoError(t, f.SetCellBool("Sheet1", "A2", false))
+	bags, err := f.featurePropertyBagReader(defaultXMLPathFeaturePropertyBag)
+	assert.NoError(t, err)
+	assert.Len(t, bags.Bag, 4)
+	rels, err := f.relsReader(defaultXMLPathWorkbookRels)
+	assert.NoError(t, err)
+	assert.Equal(t, SourceRelationshipFeaturePropertyBag, rels.Relationships[len(rels.Relationships)-1].Type)
+	contentTypes, err := f.contentTypesReader()
+	assert.NoError(t, err)
+	assert.Equal(t, ContentTypeFeaturePropertyBag, contentTypes.Overrides[len(contentTypes.Overrides)-1].ContentType)
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestNewStyleCheckbox.xlsx")))
+	assert.NoError(t, f.Close())
+
+	// Test read the cell checkbox control after round trip
+	f, err = OpenFile(filepath.Join("test", "TestNewStyleCheckbox.xlsx"))
+	assert.NoError(t, err)
+	styleID, err = f.GetCellStyle("Sheet1", "A1")
+	assert.NoError(t, err)
+	assert.Equal(t, checkbox, styleID)
+	style, err = f.GetStyle(styleID)
+	assert.NoError(t, err)
+	assert.True(t, style.Checkbox)
+	val, err := f.GetCellValue("Sheet1", "A1")
+	assert.NoError(t, err)
+	assert.Equal(t, "TRUE", val)
+	styleID, err = f.NewStyle(&Style{Checkbox: true})
+	assert.NoError(t, err)
+	assert.Equal(t, checkbox, styleID)
+	assert.NoError(t, f.Close())
+
+	// Test add cell checkbox control with existing feature property bags
+	f = NewFile()
+	f.addRels(f.getWorkbookRelsPath(), SourceRelationshipFeaturePropertyBag, "featurePropertyBag/featurePropertyBag.xml", "")
+	f.Pkg.Store(defaultXMLPathFeaturePropertyBag, []byte(`<FeaturePropertyBags count="3" xmlns="`+NameSpaceFeaturePropertyBag+`"><bag type="XFControls"/><bag type="XFComplement"><bagId k="XFControls">0</bagId></bag><bag type="XFComplements" extRef="XFComplementsMapperExtRef"><a k="MappedFeaturePropertyBags"><bagId>1</bagId></a></bag></FeaturePropertyBags>`))
+	checkbox, err = f.NewStyle(&Style{Checkbox: true})
+	assert.NoError(t, err)
+	bags, err = f.featurePropertyBagReader(defaultXMLPathFeaturePropertyBag)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, bags.getCheckboxComplementID())
+	assert.Equal(t, []xlsxFeaturePropertyBagID{{Val: 1}, {Val: 5}}, bags.Bag[2].A[0].BagID)
+	assert.Equal(t, 1, f.Styles.CellXfs.Xf[checkbox].getXfComplement())
+	// Test get style with the cell format which maps the other feature
+	f.Styles.CellXfs.Xf[checkbox].ExtLst.Ext = strings.ReplaceAll(f.Styles.CellXfs.Xf[checkbox].ExtLst.Ext, `i="1"`, `i="0"`)
+	style, err = f.GetStyle(checkbox)
+	assert.NoError(t, err)
+	assert.False(t, style.Checkbox)
+	// Test get cell format complement with invalid extension list
+	assert.Equal(t, -1, (&xlsxXf{ExtLst: &xlsxExtLst{Ext: "<ext"}}).getXfComplement())
+	assert.Equal(t, -1, (&xlsxXf{ExtLst: &xlsxExtLst{Ext: `<ext uri="` + ExtURIFeaturePropertyBag + `"><xfpb:xfComplement i="a"/></ext>`}}).getXfComplement())
+	// Test add cell checkbox control with unsupported charset feature property bags
+	f.Pkg.Store(defaultXMLPathFeaturePropertyBag, MacintoshCyrillicCharset)
+	_, err = f.NewStyle(&Style{Checkbox: true})
+	assert.EqualError(t, err, "XML syntax error on line 1: invalid UTF-8")
+	_, err = f.GetStyle(checkbox)
+	assert.EqualError(t, err, "XML syntax error on line 1: invalid UTF-8")
+	assert.NoError(t, f.Close())
+}
+
 func TestGetStyleID(t *testing.T) {
 	f := NewFile()
 	styleID, err := f.getStyleID(&xlsxStyleSheet{}, nil)
@@ -618,6 +1024,20 @@ func TestGetStyle(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, expected.NumFmt, style.NumFmt)
 
+	// Test get styles with multiple custom number formats
+	var styleIDs []int
+	for _, numFmt := range []string{"0.000%", "#,##0.0000"} {
+		numFmt := numFmt
+		styleID, err = f.NewStyle(&Style{CustomNumFmt: &numFmt})
+		assert.NoError(t, err)
+		styleIDs = append(styleIDs, styleID)
+	}
+	for i, numFmt := range []string{"0.000%", "#,##0.0000"} {
+		style, err = f.GetStyle(styleIDs[i])
+		assert.NoError(t, err)
+		assert.Equal(t, numFmt, *style.CustomNumFmt)
+	}
+
 	// Test get style with custom color index
 	f.Styles.Colors = &xlsxStyleColors{
 		IndexedColors: &xlsxIndexedColors{
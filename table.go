@@ -254,6 +254,25 @@ func (f *File) addSheetTable(sheet string, rID int) error {
 	return err
 }
 
+// marshalTable provides a function to serialize the table, and keep the
+// revision identifiers of the table, auto filter and table columns.
+func marshalTable(tbl *xlsxTable) ([]byte, error) {
+	table, err := xml.Marshal(tbl)
+	if err != nil {
+		return table, err
+	}
+	table, nameSpaces := replaceRevisionBytes(table)
+	if len(nameSpaces) == 0 {
+		return table, err
+	}
+	var xmlns string
+	for _, ns := range nameSpaces {
+		xmlns += fmt.Sprintf(" xmlns:%s=\"%s\"", ns.Name.Local, ns.Value)
+	}
+	return bytesReplace(table, []byte(fmt.Sprintf("<table xmlns=\"%s\"", tbl.XMLNS)),
+		[]byte(fmt.Sprintf("<table xmlns=\"%s\"%s", tbl.XMLNS, xmlns)), 1), err
+}
+
 // setTableColumns provides a function to set cells value in header row for the
 // table.
 func (f *File) setTableColumns(sheet string, showHeaderRow bool, x1, y1, x2 int, tbl *xlsxTable) error {
@@ -19,19 +19,30 @@ import "encoding/xml"
 // Source relationship and namespace list, associated prefixes and schema in which it was
 // introduced.
 var (
+	NameSpaceCustomProps                    = xml.Attr{Name: xml.Name{Local: "xcp", Space: "xmlns"}, Value: "http://schemas.excelize.org/spreadsheetml/2023/customProps"}
 	NameSpaceDocumentPropertiesVariantTypes = xml.Attr{Name: xml.Name{Local: "vt", Space: "xmlns"}, Value: "http://schemas.openxmlformats.org/officeDocument/2006/docPropsVTypes"}
 	NameSpaceDrawing2016SVG                 = xml.Attr{Name: xml.Name{Local: "asvg", Space: "xmlns"}, Value: "http://schemas.microsoft.com/office/drawing/2016/SVG/main"}
 	NameSpaceDrawingML                      = xml.Attr{Name: xml.Name{Local: "a", Space: "xmlns"}, Value: "http://schemas.openxmlformats.org/drawingml/2006/main"}
 	NameSpaceDrawingMLA14                   = xml.Attr{Name: xml.Name{Local: "a14", Space: "xmlns"}, Value: "http://schemas.microsoft.com/office/drawing/2010/main"}
 	NameSpaceDrawingMLChart                 = xml.Attr{Name: xml.Name{Local: "c", Space: "xmlns"}, Value: "http://schemas.openxmlformats.org/drawingml/2006/chart"}
+	NameSpaceDrawingMLChartEx               = xml.Attr{Name: xml.Name{Local: "cx", Space: "xmlns"}, Value: "http://schemas.microsoft.com/office/drawing/2014/chartex"}
+	NameSpaceDrawingMLChartEx1              = xml.Attr{Name: xml.Name{Local: "cx1", Space: "xmlns"}, Value: "http://schemas.microsoft.com/office/drawing/2015/9/8/chartex"}
+	NameSpaceDrawingMLChartEx2              = xml.Attr{Name: xml.Name{Local: "cx2", Space: "xmlns"}, Value: "http://schemas.microsoft.com/office/drawing/2015/10/21/chartex"}
+	NameSpaceDrawingMLChartX15              = xml.Attr{Name: xml.Name{Local: "c15", Space: "xmlns"}, Value: "http://schemas.microsoft.com/office/drawing/2012/chart"}
 	NameSpaceDrawingMLSlicer                = xml.Attr{Name: xml.Name{Local: "sle", Space: "xmlns"}, Value: "http://schemas.microsoft.com/office/drawing/2010/slicer"}
 	NameSpaceDrawingMLSlicerX15             = xml.Attr{Name: xml.Name{Local: "sle15", Space: "xmlns"}, Value: "http://schemas.microsoft.com/office/drawing/2012/slicer"}
+	NameSpaceDrawingMLTimeslicer            = xml.Attr{Name: xml.Name{Local: "tsle", Space: "xmlns"}, Value: "http://schemas.microsoft.com/office/drawing/2012/timeslicer"}
 	NameSpaceDrawingMLSpreadSheet           = xml.Attr{Name: xml.Name{Local: "xdr", Space: "xmlns"}, Value: "http://schemas.openxmlformats.org/drawingml/2006/spreadsheetDrawing"}
 	NameSpaceMacExcel2008Main               = xml.Attr{Name: xml.Name{Local: "mx", Space: "xmlns"}, Value: "http://schemas.microsoft.com/office/mac/excel/2008/main"}
+	NameSpaceOfficeMath                     = xml.Attr{Name: xml.Name{Local: "m", Space: "xmlns"}, Value: "http://schemas.openxmlformats.org/officeDocument/2006/math"}
 	NameSpaceSpreadSheet                    = xml.Attr{Name: xml.Name{Local: "xmlns"}, Value: "http://schemas.openxmlformats.org/spreadsheetml/2006/main"}
 	NameSpaceSpreadSheetExcel2006Main       = xml.Attr{Name: xml.Name{Local: "xne", Space: "xmlns"}, Value: "http://schemas.microsoft.com/office/excel/2006/main"}
 	NameSpaceSpreadSheetX14                 = xml.Attr{Name: xml.Name{Local: "x14", Space: "xmlns"}, Value: "http://schemas.microsoft.com/office/spreadsheetml/2009/9/main"}
 	NameSpaceSpreadSheetX15                 = xml.Attr{Name: xml.Name{Local: "x15", Space: "xmlns"}, Value: "http://schemas.microsoft.com/office/spreadsheetml/2010/11/main"}
+	NameSpaceSpreadSheetXR                  = xml.Attr{Name: xml.Name{Local: "xr", Space: "xmlns"}, Value: "http://schemas.microsoft.com/office/spreadsheetml/2014/revision"}
+	NameSpaceSpreadSheetXR2                 = xml.Attr{Name: xml.Name{Local: "xr2", Space: "xmlns"}, Value: "http://schemas.microsoft.com/office/spreadsheetml/2015/revision2"}
+	NameSpaceSpreadSheetXR3                 = xml.Attr{Name: xml.Name{Local: "xr3", Space: "xmlns"}, Value: "http://schemas.microsoft.com/office/spreadsheetml/2016/revision3"}
+	NameSpaceSpreadSheetXR6                 = xml.Attr{Name: xml.Name{Local: "xr6", Space: "xmlns"}, Value: "http://schemas.microsoft.com/office/spreadsheetml/2016/revision6"}
 	NameSpaceSpreadSheetXR10                = xml.Attr{Name: xml.Name{Local: "xr10", Space: "xmlns"}, Value: "http://schemas.microsoft.com/office/spreadsheetml/2016/revision10"}
 	SourceRelationship                      = xml.Attr{Name: xml.Name{Local: "r", Space: "xmlns"}, Value: "http://schemas.openxmlformats.org/officeDocument/2006/relationships"}
 	SourceRelationshipChart20070802         = xml.Attr{Name: xml.Name{Local: "c14", Space: "xmlns"}, Value: "http://schemas.microsoft.com/office/drawing/2007/8/2/chart"}
@@ -43,46 +54,73 @@ var (
 // Source relationship and namespace.
 const (
 	ContentTypeAddinMacro                         = "application/vnd.ms-excel.addin.macroEnabled.main+xml"
+	ContentTypeChartEx                            = "application/vnd.ms-office.chartex+xml"
 	ContentTypeDrawing                            = "application/vnd.openxmlformats-officedocument.drawing+xml"
 	ContentTypeDrawingML                          = "application/vnd.openxmlformats-officedocument.drawingml.chart+xml"
+	ContentTypeFeaturePropertyBag                 = "application/vnd.ms-excel.featurepropertybag+xml"
+	ContentTypeIntlMacrosheet                     = "application/vnd.ms-excel.intlmacrosheet+xml"
 	ContentTypeMacro                              = "application/vnd.ms-excel.sheet.macroEnabled.main+xml"
+	ContentTypeMacrosheet                         = "application/vnd.ms-excel.macrosheet+xml"
+	ContentTypeOLEObject                          = "application/vnd.openxmlformats-officedocument.oleObject"
 	ContentTypeRelationships                      = "application/vnd.openxmlformats-package.relationships+xml"
 	ContentTypeSheetML                            = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"
 	ContentTypeSlicer                             = "application/vnd.ms-excel.slicer+xml"
 	ContentTypeSlicerCache                        = "application/vnd.ms-excel.slicerCache+xml"
 	ContentTypeSpreadSheetMLChartsheet            = "application/vnd.openxmlformats-officedocument.spreadsheetml.chartsheet+xml"
 	ContentTypeSpreadSheetMLComments              = "application/vnd.openxmlformats-officedocument.spreadsheetml.comments+xml"
+	ContentTypeSpreadSheetMLExternalLink          = "application/vnd.openxmlformats-officedocument.spreadsheetml.externalLink+xml"
 	ContentTypeSpreadSheetMLPivotCacheDefinition  = "application/vnd.openxmlformats-officedocument.spreadsheetml.pivotCacheDefinition+xml"
+	ContentTypeSpreadSheetMLPivotCacheRecords     = "application/vnd.openxmlformats-officedocument.spreadsheetml.pivotCacheRecords+xml"
 	ContentTypeSpreadSheetMLPivotTable            = "application/vnd.openxmlformats-officedocument.spreadsheetml.pivotTable+xml"
 	ContentTypeSpreadSheetMLSharedStrings         = "application/vnd.openxmlformats-officedocument.spreadsheetml.sharedStrings+xml"
 	ContentTypeSpreadSheetMLTable                 = "application/vnd.openxmlformats-officedocument.spreadsheetml.table+xml"
 	ContentTypeSpreadSheetMLWorksheet             = "application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"
 	ContentTypeTemplate                           = "application/vnd.openxmlformats-officedocument.spreadsheetml.template.main+xml"
 	ContentTypeTemplateMacro                      = "application/vnd.ms-excel.template.macroEnabled.main+xml"
+	ContentTypeThreadedComments                   = "application/vnd.ms-excel.threadedcomments+xml"
+	ContentTypeTimeline                           = "application/vnd.ms-excel.timeline+xml"
+	ContentTypeTimelineCache                      = "application/vnd.ms-excel.timelineCache+xml"
 	ContentTypeVBA                                = "application/vnd.ms-office.vbaProject"
 	ContentTypeVML                                = "application/vnd.openxmlformats-officedocument.vmlDrawing"
+	NameSpaceCustomUI                             = "http://schemas.microsoft.com/office/2006/01/customui"
+	NameSpaceCustomUI14                           = "http://schemas.microsoft.com/office/2009/07/customui"
 	NameSpaceDrawingMLMain                        = "http://schemas.openxmlformats.org/drawingml/2006/main"
 	NameSpaceDublinCore                           = "http://purl.org/dc/elements/1.1/"
 	NameSpaceDublinCoreMetadataInitiative         = "http://purl.org/dc/dcmitype/"
 	NameSpaceDublinCoreTerms                      = "http://purl.org/dc/terms/"
 	NameSpaceExtendedProperties                   = "http://schemas.openxmlformats.org/officeDocument/2006/extended-properties"
+	NameSpaceFeaturePropertyBag                   = "http://schemas.microsoft.com/office/spreadsheetml/2022/featurepropertybag"
 	NameSpaceXML                                  = "http://www.w3.org/XML/1998/namespace"
 	NameSpaceXMLSchemaInstance                    = "http://www.w3.org/2001/XMLSchema-instance"
 	SourceRelationshipChart                       = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/chart"
+	SourceRelationshipChartEx                     = "http://schemas.microsoft.com/office/2014/relationships/chartEx"
 	SourceRelationshipChartsheet                  = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/chartsheet"
 	SourceRelationshipComments                    = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/comments"
+	SourceRelationshipControl                     = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/control"
+	SourceRelationshipCustomUI                    = "http://schemas.microsoft.com/office/2006/relationships/ui/extensibility"
+	SourceRelationshipCustomUI14                  = "http://schemas.microsoft.com/office/2007/relationships/ui/extensibility"
 	SourceRelationshipDialogsheet                 = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/dialogsheet"
 	SourceRelationshipDrawingML                   = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/drawing"
 	SourceRelationshipDrawingVML                  = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/vmlDrawing"
 	SourceRelationshipExtendProperties            = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/extended-properties"
+	SourceRelationshipExternalLink                = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/externalLink"
+	SourceRelationshipExternalLinkPath            = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/externalLinkPath"
+	SourceRelationshipFeaturePropertyBag          = "http://schemas.microsoft.com/office/2022/11/relationships/FeaturePropertyBag"
 	SourceRelationshipHyperLink                   = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/hyperlink"
 	SourceRelationshipImage                       = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/image"
+	SourceRelationshipIntlMacrosheet              = "http://schemas.microsoft.com/office/2006/relationships/xlIntlMacrosheet"
+	SourceRelationshipMacrosheet                  = "http://schemas.microsoft.com/office/2006/relationships/xlMacrosheet"
+	SourceRelationshipOLEObject                   = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/oleObject"
 	SourceRelationshipOfficeDocument              = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument"
+	SourceRelationshipPackage                     = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/package"
 	SourceRelationshipPivotCache                  = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/pivotCacheDefinition"
+	SourceRelationshipPivotCacheRecords           = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/pivotCacheRecords"
 	SourceRelationshipPivotTable                  = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/pivotTable"
 	SourceRelationshipSharedStrings               = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/sharedStrings"
 	SourceRelationshipSlicer                      = "http://schemas.microsoft.com/office/2007/relationships/slicer"
 	SourceRelationshipSlicerCache                 = "http://schemas.microsoft.com/office/2007/relationships/slicerCache"
+	SourceRelationshipTimeline                    = "http://schemas.microsoft.com/office/2011/relationships/timeline"
+	SourceRelationshipTimelineCache               = "http://schemas.microsoft.com/office/2011/relationships/timelineCache"
 	SourceRelationshipTable                       = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/table"
 	SourceRelationshipVBAProject                  = "http://schemas.microsoft.com/office/2006/relationships/vbaProject"
 	SourceRelationshipWorkSheet                   = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet"
@@ -100,12 +138,16 @@ const (
 	// ([ISO/IEC29500-1:2016] section 18.2.10) of the workbook and worksheet
 	// elements extended by the addition of new child ext elements.
 	ExtURICalcFeatures                   = "{B58B0392-4F1F-4190-BB64-5DF3571DCE5F}"
+	ExtURIChartDataLabels                = "{CE6537A1-D6FC-4f65-9D91-7224C49458BB}"
+	ExtURIChartSeriesDataLabelsRange     = "{02D57815-91ED-43cb-92C2-25804820EDAC}"
 	ExtURIConditionalFormattingRuleID    = "{B025F937-C7B1-47D3-B67F-A62EFF666E3E}"
 	ExtURIConditionalFormattings         = "{78C0D931-6437-407d-A8EE-F0AAD7539E65}"
+	ExtURICustomProps                    = "{3E6B0D58-9C1F-4A7B-8E2D-5F4C7A1B9D60}"
 	ExtURIDataModel                      = "{FCE2AD5D-F65C-4FA6-A056-5C36A1767C68}"
 	ExtURIDataValidations                = "{CCE6A557-97BC-4B89-ADB6-D9C93CAAB3DF}"
 	ExtURIDrawingBlip                    = "{28A0092B-C50C-407E-A947-70E740481C1C}"
 	ExtURIExternalLinkPr                 = "{FCE6A71B-6B00-49CD-AB44-F6B1AE7CDE65}"
+	ExtURIFeaturePropertyBag             = "{C7286773-470A-42A8-94C5-96B5CB345126}"
 	ExtURIIgnoredErrors                  = "{01252117-D84E-4E92-8308-4BE1C098FCBB}"
 	ExtURIMacExcelMX                     = "{64002731-A6B0-56B0-2670-7721B7C09600}"
 	ExtURIModelTimeGroupings             = "{9835A34E-60A6-4A7C-AAB8-D5F71C897F49}"
@@ -191,6 +233,8 @@ const (
 	defaultChartDimensionHeight = 260
 	defaultSlicerWidth          = 200
 	defaultSlicerHeight         = 200
+	defaultTimelineWidth        = 320
+	defaultTimelineHeight       = 140
 	defaultChartLegendPosition  = "bottom"
 	defaultChartShowBlanksAs    = "gap"
 	defaultShapeSize            = 160
@@ -218,17 +262,18 @@ const (
 )
 
 const (
-	defaultTempFileSST          = "sharedStrings"
-	defaultXMLPathCalcChain     = "xl/calcChain.xml"
-	defaultXMLPathContentTypes  = "[Content_Types].xml"
-	defaultXMLPathDocPropsApp   = "docProps/app.xml"
-	defaultXMLPathDocPropsCore  = "docProps/core.xml"
-	defaultXMLPathSharedStrings = "xl/sharedStrings.xml"
-	defaultXMLPathStyles        = "xl/styles.xml"
-	defaultXMLPathTheme         = "xl/theme/theme1.xml"
-	defaultXMLPathVolatileDeps  = "xl/volatileDependencies.xml"
-	defaultXMLPathWorkbook      = "xl/workbook.xml"
-	defaultXMLPathWorkbookRels  = "xl/_rels/workbook.xml.rels"
+	defaultTempFileSST               = "sharedStrings"
+	defaultXMLPathCalcChain          = "xl/calcChain.xml"
+	defaultXMLPathContentTypes       = "[Content_Types].xml"
+	defaultXMLPathDocPropsApp        = "docProps/app.xml"
+	defaultXMLPathDocPropsCore       = "docProps/core.xml"
+	defaultXMLPathFeaturePropertyBag = "xl/featurePropertyBag/featurePropertyBag.xml"
+	defaultXMLPathSharedStrings      = "xl/sharedStrings.xml"
+	defaultXMLPathStyles             = "xl/styles.xml"
+	defaultXMLPathTheme              = "xl/theme/theme1.xml"
+	defaultXMLPathVolatileDeps       = "xl/volatileDependencies.xml"
+	defaultXMLPathWorkbook           = "xl/workbook.xml"
+	defaultXMLPathWorkbookRels       = "xl/_rels/workbook.xml.rels"
 )
 
 // IndexedColorMapping is the table of default mappings from indexed color value
@@ -0,0 +1,420 @@
+// Copyright 2016 - 2023 The excelize Authors. All rights reserved. Use of
+// this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+//
+// package excelize_ch providing a set of functions that allow you to write to and
+// read from XLAM / XLSM / XLSX / XLTM / XLTX files. Supports reading and
+// writing spreadsheet documents generated by Microsoft Excel™ 2007 and later.
+// Supports complex components by high compatibility, and provided streaming
+// API for generating or reading data from a worksheet with huge amounts of
+// data. This library needs Go version 1.16 or later.
+
+package excelize_ch
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TimelineOptions represents the settings of the timeline.
+//
+// Name specifies the timeline name, should be an existing date field name of
+// the given pivot table, this setting is required.
+//
+// Cell specifies the left top cell coordinates the position for inserting the
+// timeline, this setting is required.
+//
+// TableSheet specifies the worksheet name of the pivot table, this setting is
+// required.
+//
+// TableName specifies the name of the pivot table, this setting is required.
+// The pivot table should be created with the data range of the worksheet.
+//
+// Caption specifies the caption of the timeline, this setting is optional,
+// and the default caption is the timeline name.
+//
+// Macro used for set macro for the timeline, the workbook extension should be
+// XLSM or XLTM.
+//
+// Width specifies the width of the timeline, this setting is optional.
+//
+// Height specifies the height of the timeline, this setting is optional.
+//
+// DisplayHeader specifies if display header of the timeline, this setting is
+// optional, the default setting is display.
+//
+// Level specifies the time level of the timeline, this setting is optional,
+// the possible values are "years", "quarters", "months" and "days", and the
+// default setting is "months".
+//
+// Format specifies the format of the timeline, this setting is optional.
+type TimelineOptions struct {
+	Name          string
+	Cell          string
+	TableSheet    string
+	TableName     string
+	Caption       string
+	Macro         string
+	Width         uint
+	Height        uint
+	DisplayHeader *bool
+	Level         string
+	Format        GraphicOptions
+}
+
+// timelineLevels defined the time levels of the timeline.
+var timelineLevels = map[string]int{"years": 0, "quarters": 1, "months": 2, "days": 3}
+
+// AddTimeline function inserts a timeline by giving the worksheet name and
+// timeline settings. The timeline provides the date-scrubbing control for
+// filtering the pivot table by the date field.
+//
+// For example, insert a timeline on the Sheet1!H1 with the date field Date
+// for the pivot table named PivotTable1:
+//
+//	err := f.AddTimeline("Sheet1", &excelize.TimelineOptions{
+//	    Name:       "Date",
+//	    Cell:       "H1",
+//	    TableSheet: "Sheet1",
+//	    TableName:  "PivotTable1",
+//	    Caption:    "Date",
+//	    Level:      "quarters",
+//	})
+func (f *File) AddTimeline(sheet string, opts *TimelineOptions) error {
+	opts, err := parseTimelineOptions(opts)
+	if err != nil {
+		return err
+	}
+	pivotTable, bounds, err := f.getTimelineSource(opts)
+	if err != nil {
+		return err
+	}
+	timelineID, err := f.addSheetTimeline(sheet)
+	if err != nil {
+		return err
+	}
+	timelineCacheName, err := f.setTimelineCache(opts, pivotTable, bounds)
+	if err != nil {
+		return err
+	}
+	timelineName := f.genSlicerName(opts.Name)
+	if err := f.addDrawingSlicer(sheet, timelineName, NameSpaceDrawingMLTimeslicer, &SlicerOptions{
+		Cell: opts.Cell, Macro: opts.Macro, Width: opts.Width, Height: opts.Height, Format: opts.Format,
+	}); err != nil {
+		return err
+	}
+	level := timelineLevels[opts.Level]
+	return f.addTimeline(timelineID, xlsxTimeline{
+		Name:           timelineName,
+		Cache:          timelineCacheName,
+		Caption:        opts.Caption,
+		ShowHeader:     opts.DisplayHeader,
+		Level:          level,
+		SelectionLevel: level,
+		ScrollPosition: bounds[0].Format("2006-01-02T15:04:05"),
+	})
+}
+
+// parseTimelineOptions provides a function to parse the format settings of
+// the timeline with default value.
+func parseTimelineOptions(opts *TimelineOptions) (*TimelineOptions, error) {
+	if opts == nil {
+		return nil, ErrParameterRequired
+	}
+	if opts.Name == "" || opts.Cell == "" || opts.TableSheet == "" || opts.TableName == "" {
+		return nil, ErrParameterInvalid
+	}
+	if opts.Level == "" {
+		opts.Level = "months"
+	}
+	if _, ok := timelineLevels[opts.Level]; !ok {
+		return nil, ErrParameterInvalid
+	}
+	if opts.Caption == "" {
+		opts.Caption = opts.Name
+	}
+	if opts.Width == 0 {
+		opts.Width = defaultTimelineWidth
+	}
+	if opts.Height == 0 {
+		opts.Height = defaultTimelineHeight
+	}
+	if opts.Format.PrintObject == nil {
+		opts.Format.PrintObject = boolPtr(true)
+	}
+	if opts.Format.Locked == nil {
+		opts.Format.Locked = boolPtr(false)
+	}
+	if opts.Format.ScaleX == 0 {
+		opts.Format.ScaleX = defaultDrawingScale
+	}
+	if opts.Format.ScaleY == 0 {
+		opts.Format.ScaleY = defaultDrawingScale
+	}
+	return opts, nil
+}
+
+// getTimelineSource returns the pivot table settings of the timeline and the
+// bounds of the dates in the given date field of the pivot table source data,
+// the bounds starts from the beginning of the year of the earliest date and
+// ends at the beginning of the next year of the latest date.
+func (f *File) getTimelineSource(opts *TimelineOptions) (*PivotTableOptions, []time.Time, error) {
+	var (
+		pivotTable *PivotTableOptions
+		bounds     []time.Time
+	)
+	pivotTables, err := f.GetPivotTables(opts.TableSheet)
+	if err != nil {
+		return pivotTable, bounds, err
+	}
+	for _, tbl := range pivotTables {
+		if tbl.Name == opts.TableName {
+			pivotTable = &tbl
+			break
+		}
+	}
+	if pivotTable == nil {
+		return pivotTable, bounds, newNoExistTableError(opts.TableName)
+	}
+	if pivotTable.DataSource != nil {
+		return pivotTable, bounds, ErrParameterInvalid
+	}
+	dataSource := &PivotTableOptions{DataRange: pivotTable.DataRange}
+	order, err := f.getTableFieldsOrder(dataSource)
+	if err != nil {
+		return pivotTable, bounds, err
+	}
+	colIdx := inStrSlice(order, opts.Name, true)
+	if colIdx == -1 {
+		return pivotTable, bounds, newInvalidTimelineNameError(opts.Name)
+	}
+	dataSheet, coordinates, err := f.getPivotDataRange(dataSource)
+	if err != nil {
+		return pivotTable, bounds, err
+	}
+	var minValue, maxValue float64
+	found := false
+	for row := coordinates[1] + 1; row <= coordinates[3]; row++ {
+		val, _, err := f.getPivotDataCellValue(dataSource, dataSheet, coordinates[0]+colIdx, row, true)
+		if err != nil {
+			return pivotTable, bounds, err
+		}
+		if val == "" {
+			continue
+		}
+		num, err := strconv.ParseFloat(val, 64)
+		if err != nil || num < 0 {
+			return pivotTable, bounds, newInvalidTimelineNameError(opts.Name)
+		}
+		if !found || num < minValue {
+			minValue = num
+		}
+		if !found || num > maxValue {
+			maxValue = num
+		}
+		found = true
+	}
+	if !found {
+		return pivotTable, bounds, newInvalidTimelineNameError(opts.Name)
+	}
+	wb, err := f.workbookReader()
+	if err != nil {
+		return pivotTable, bounds, err
+	}
+	var date1904 bool
+	if wb.WorkbookPr != nil {
+		date1904 = wb.WorkbookPr.Date1904
+	}
+	startDate, endDate := timeFromExcelTime(minValue, date1904), timeFromExcelTime(maxValue, date1904)
+	bounds = []time.Time{
+		time.Date(startDate.Year(), time.January, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(endDate.Year()+1, time.January, 1, 0, 0, 0, 0, time.UTC),
+	}
+	return pivotTable, bounds, err
+}
+
+// addSheetTimeline adds a new timeline and updates the relationships and
+// extension list of the worksheet by giving the worksheet name, and returns
+// the timeline part ID.
+func (f *File) addSheetTimeline(sheet string) (int, error) {
+	var (
+		timelineID   = f.getUnusedPartID("xl/timelines/timeline")
+		ws, err      = f.workSheetReader(sheet)
+		decodeExtLst = new(decodeExtLst)
+		timelineRefs = new(decodeTimelineRefs)
+	)
+	if err != nil {
+		return timelineID, err
+	}
+	if ws.ExtLst != nil {
+		if err = f.xmlNewDecoder(strings.NewReader("<extLst>" + ws.ExtLst.Ext + "</extLst>")).
+			Decode(decodeExtLst); err != nil && err != io.EOF {
+			return timelineID, err
+		}
+		for _, ext := range decodeExtLst.Ext {
+			if ext.URI == ExtURITimelineRefs {
+				_ = f.xmlNewDecoder(strings.NewReader(ext.Content)).Decode(timelineRefs)
+				for _, timelineRef := range timelineRefs.TimelineRef {
+					if timelineRef.RID != "" {
+						target := f.getSheetRelationshipsTargetByID(sheet, timelineRef.RID)
+						timelineID, _ = strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(target, "../timelines/timeline"), ".xml"))
+						return timelineID, err
+					}
+				}
+			}
+		}
+	}
+	sheetXMLPath, _ := f.getSheetXMLPath(sheet)
+	rID := f.addRels(getPartRelsPath(sheetXMLPath), SourceRelationshipTimeline, "../timelines/timeline"+strconv.Itoa(timelineID)+".xml", "")
+	timelineRefsBytes, _ := xml.Marshal(&xlsxX15TimelineRefs{
+		TimelineRef: []*xlsxX15TimelineRef{{RID: "rId" + strconv.Itoa(rID)}},
+	})
+	decodeExtLst.Ext = append(decodeExtLst.Ext, &xlsxExt{
+		xmlns: []xml.Attr{{Name: xml.Name{Local: "xmlns:" + NameSpaceSpreadSheetX15.Name.Local}, Value: NameSpaceSpreadSheetX15.Value}},
+		URI:   ExtURITimelineRefs, Content: string(timelineRefsBytes),
+	})
+	sort.Slice(decodeExtLst.Ext, func(i, j int) bool {
+		return inStrSlice(worksheetExtURIPriority, decodeExtLst.Ext[i].URI, false) <
+			inStrSlice(worksheetExtURIPriority, decodeExtLst.Ext[j].URI, false)
+	})
+	extLstBytes, err := xml.Marshal(decodeExtLst)
+	ws.ExtLst = &xlsxExtLst{Ext: strings.TrimSuffix(strings.TrimPrefix(string(extLstBytes), "<extLst>"), "</extLst>")}
+	return timelineID, err
+}
+
+// addTimeline adds a new timeline to the workbook by giving the timeline ID
+// and settings.
+func (f *File) addTimeline(timelineID int, timeline xlsxTimeline) error {
+	timelineXML := "xl/timelines/timeline" + strconv.Itoa(timelineID) + ".xml"
+	timelines, err := f.timelineReader(timelineXML)
+	if err != nil {
+		return err
+	}
+	if err := f.addContentTypePart(timelineID, "timeline"); err != nil {
+		return err
+	}
+	timelines.Timeline = append(timelines.Timeline, timeline)
+	output, err := xml.Marshal(timelines)
+	f.saveFileList(timelineXML, output)
+	return err
+}
+
+// setTimelineCache check if a timeline cache already exists or add a new
+// timeline cache by giving the timeline and pivot table options, and the
+// bounds of the dates, returns the timeline cache name.
+func (f *File) setTimelineCache(opts *TimelineOptions, pivotTable *PivotTableOptions, bounds []time.Time) (string, error) {
+	var timelineCacheName string
+	f.Pkg.Range(func(k, v interface{}) bool {
+		if strings.Contains(k.(string), "xl/timelineCaches/timelineCache") {
+			timelineCache := &xlsxTimelineCacheDefinition{}
+			if err := f.xmlNewDecoder(bytes.NewReader(namespaceStrictToTransitional(v.([]byte)))).
+				Decode(timelineCache); err != nil && err != io.EOF {
+				return true
+			}
+			if timelineCache.SourceName != opts.Name || timelineCache.PivotTables == nil {
+				return true
+			}
+			for _, tbl := range timelineCache.PivotTables.PivotTable {
+				if tbl.Name == pivotTable.Name {
+					timelineCacheName = timelineCache.Name
+					return false
+				}
+			}
+		}
+		return true
+	})
+	if timelineCacheName != "" {
+		return timelineCacheName, nil
+	}
+	timelineCacheName = f.genSlicerCacheName("NativeTimeline_", opts.Name)
+	return timelineCacheName, f.addTimelineCache(timelineCacheName, opts, pivotTable, bounds)
+}
+
+// addTimelineCache adds a new timeline cache by giving the timeline cache
+// name, timeline and pivot table options, and the bounds of the dates.
+func (f *File) addTimelineCache(timelineCacheName string, opts *TimelineOptions, pivotTable *PivotTableOptions, bounds []time.Time) error {
+	pivotCacheID, err := f.addPivotCacheSlicer(pivotTable)
+	if err != nil {
+		return err
+	}
+	layout := "2006-01-02T15:04:05"
+	timelineCacheID := f.getUnusedPartID("xl/timelineCaches/timelineCache")
+	timelineCacheXML := "xl/timelineCaches/timelineCache" + strconv.Itoa(timelineCacheID) + ".xml"
+	timelineCacheBytes, _ := xml.Marshal(xlsxTimelineCacheDefinition{
+		XMLNSXMC:   SourceRelationshipCompatibility.Value,
+		XMLNSX:     NameSpaceSpreadSheet.Value,
+		XMLNSXR10:  NameSpaceSpreadSheetXR10.Value,
+		Name:       timelineCacheName,
+		SourceName: opts.Name,
+		PivotTables: &xlsxSlicerCachePivotTables{
+			PivotTable: []xlsxSlicerCachePivotTable{
+				{TabID: f.getSheetID(opts.TableSheet), Name: pivotTable.Name},
+			},
+		},
+		State: &xlsxTimelineState{
+			MinimalRefreshVersion: 6,
+			LastRefreshVersion:    6,
+			PivotCacheID:          pivotCacheID,
+			FilterType:            "unknown",
+			Bounds:                &xlsxTimelineRange{StartDate: bounds[0].Format(layout), EndDate: bounds[1].Format(layout)},
+		},
+	})
+	f.saveFileList(timelineCacheXML, timelineCacheBytes)
+	if err := f.addContentTypePart(timelineCacheID, "timelineCache"); err != nil {
+		return err
+	}
+	if err := f.addWorkbookTimelineCache(timelineCacheID); err != nil {
+		return err
+	}
+	return f.SetDefinedName(&DefinedName{Name: timelineCacheName, RefersTo: formulaErrorNA})
+}
+
+// addWorkbookTimelineCache add the association ID of the timeline cache in
+// workbook.xml.
+func (f *File) addWorkbookTimelineCache(timelineCacheID int) error {
+	var (
+		appendMode   bool
+		decodeExtLst = new(decodeExtLst)
+	)
+	wb, err := f.workbookReader()
+	if err != nil {
+		return err
+	}
+	rID := f.addRels(f.getWorkbookRelsPath(), SourceRelationshipTimelineCache, fmt.Sprintf("/xl/timelineCaches/timelineCache%d.xml", timelineCacheID), "")
+	timelineCacheBytes, _ := xml.Marshal(xlsxX15TimelineCacheRef{RID: "rId" + strconv.Itoa(rID)})
+	if wb.ExtLst != nil {
+		if err = f.xmlNewDecoder(strings.NewReader("<extLst>" + wb.ExtLst.Ext + "</extLst>")).
+			Decode(decodeExtLst); err != nil && err != io.EOF {
+			return err
+		}
+		for idx, ext := range decodeExtLst.Ext {
+			if ext.URI == ExtURITimelineCacheRefs {
+				timelineCacheRefs := new(decodeTimelineCacheRefs)
+				_ = f.xmlNewDecoder(strings.NewReader(ext.Content)).Decode(timelineCacheRefs)
+				timelineCacheRefsBytes, _ := xml.Marshal(xlsxX15TimelineCacheRefs{Content: timelineCacheRefs.Content + string(timelineCacheBytes)})
+				decodeExtLst.Ext[idx].Content = string(timelineCacheRefsBytes)
+				appendMode = true
+			}
+		}
+	}
+	if !appendMode {
+		timelineCacheRefsBytes, _ := xml.Marshal(xlsxX15TimelineCacheRefs{Content: string(timelineCacheBytes)})
+		decodeExtLst.Ext = append(decodeExtLst.Ext, &xlsxExt{
+			xmlns: []xml.Attr{{Name: xml.Name{Local: "xmlns:" + NameSpaceSpreadSheetX15.Name.Local}, Value: NameSpaceSpreadSheetX15.Value}},
+			URI:   ExtURITimelineCacheRefs, Content: string(timelineCacheRefsBytes),
+		})
+	}
+	sort.Slice(decodeExtLst.Ext, func(i, j int) bool {
+		return inStrSlice(workbookExtURIPriority, decodeExtLst.Ext[i].URI, false) <
+			inStrSlice(workbookExtURIPriority, decodeExtLst.Ext[j].URI, false)
+	})
+	extLstBytes, err := xml.Marshal(decodeExtLst)
+	wb.ExtLst = &xlsxExtLst{Ext: strings.TrimSuffix(strings.TrimPrefix(string(extLstBytes), "<extLst>"), "</extLst>")}
+	return err
+}
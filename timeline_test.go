@@ -0,0 +1,220 @@
+package excelize_ch
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddTimeline(t *testing.T) {
+	f := NewFile()
+	assert.NoError(t, f.SetSheetRow("Sheet1", "A1", &[]string{"Date", "Region", "Sales"}))
+	for row := 2; row < 14; row++ {
+		assert.NoError(t, f.SetSheetRow("Sheet1", fmt.Sprintf("A%d", row), &[]interface{}{
+			time.Date(2022+row%2, time.Month(row-1), 15, 0, 0, 0, 0, time.UTC), []string{"East", "West"}[row%2], row * 100,
+		}))
+	}
+	assert.NoError(t, f.AddPivotTable(&PivotTableOptions{
+		DataRange:       "Sheet1!A1:C13",
+		PivotTableRange: "Sheet1!E1:G20",
+		Rows:            []PivotTableField{{Data: "Region"}},
+		Data:            []PivotTableField{{Data: "Sales"}},
+	}))
+	assert.NoError(t, f.AddTimeline("Sheet1", &TimelineOptions{
+		Name:       "Date",
+		Cell:       "I1",
+		TableSheet: "Sheet1",
+		TableName:  "PivotTable1",
+	}))
+	// Test add a timeline with the same date field and custom settings
+	disable := false
+	assert.NoError(t, f.AddTimeline("Sheet1", &TimelineOptions{
+		Name:          "Date",
+		Cell:          "I10",
+		TableSheet:    "Sheet1",
+		TableName:     "PivotTable1",
+		Caption:       "Order Date",
+		Macro:         "Button1_Click",
+		Width:         400,
+		Height:        160,
+		DisplayHeader: &disable,
+		Level:         "quarters",
+	}))
+	timelines, err := f.timelineReader("xl/timelines/timeline1.xml")
+	assert.NoError(t, err)
+	assert.Len(t, timelines.Timeline, 2)
+	assert.Equal(t, xlsxTimeline{
+		Name: "Date", Cache: "NativeTimeline_Date", Caption: "Date",
+		Level: 2, SelectionLevel: 2, ScrollPosition: "2022-01-01T00:00:00",
+	}, timelines.Timeline[0])
+	assert.Equal(t, "Date 1", timelines.Timeline[1].Name)
+	assert.Equal(t, 1, timelines.Timeline[1].Level)
+	assert.Equal(t, "NativeTimeline_Date", timelines.Timeline[1].Cache)
+	timelineCache, ok := f.Pkg.Load("xl/timelineCaches/timelineCache1.xml")
+	assert.True(t, ok)
+	assert.Contains(t, string(timelineCache.([]byte)), `<bounds startDate="2022-01-01T00:00:00" endDate="2024-01-01T00:00:00"></bounds>`)
+	_, ok = f.Pkg.Load("xl/timelineCaches/timelineCache2.xml")
+	assert.False(t, ok)
+	definedNames := f.GetDefinedName()
+	assert.Len(t, definedNames, 1)
+	assert.Equal(t, "NativeTimeline_Date", definedNames[0].Name)
+	// Test add a timeline in another worksheet
+	_, err = f.NewSheet("Sheet2")
+	assert.NoError(t, err)
+	assert.NoError(t, f.AddTimeline("Sheet2", &TimelineOptions{
+		Name:       "Date",
+		Cell:       "A1",
+		TableSheet: "Sheet1",
+		TableName:  "PivotTable1",
+		Level:      "days",
+	}))
+	_, ok = f.Pkg.Load("xl/timelines/timeline2.xml")
+	assert.True(t, ok)
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestAddTimeline.xlsx")))
+	assert.NoError(t, f.Close())
+
+	f, err = OpenFile(filepath.Join("test", "TestAddTimeline.xlsx"))
+	assert.NoError(t, err)
+	// Test add a timeline for the pivot table of the opened workbook
+	assert.NoError(t, f.AddTimeline("Sheet2", &TimelineOptions{
+		Name:       "Date",
+		Cell:       "A10",
+		TableSheet: "Sheet1",
+		TableName:  "PivotTable1",
+	}))
+	timelines, err = f.timelineReader("xl/timelines/timeline2.xml")
+	assert.NoError(t, err)
+	assert.Len(t, timelines.Timeline, 2)
+	assert.Equal(t, "NativeTimeline_Date", timelines.Timeline[1].Cache)
+	// Test add a timeline with empty timeline options
+	assert.Equal(t, ErrParameterRequired, f.AddTimeline("Sheet1", nil))
+	// Test add a timeline with invalid timeline options
+	for _, opts := range []*TimelineOptions{
+		{Cell: "Q1", TableSheet: "Sheet1", TableName: "PivotTable1"},
+		{Name: "Date", Cell: "Q1", TableSheet: "Sheet1"},
+		{Name: "Date", TableSheet: "Sheet1", TableName: "PivotTable1"},
+		{Name: "Date", Cell: "Q1", TableSheet: "Sheet1", TableName: "PivotTable1", Level: "weeks"},
+	} {
+		assert.Equal(t, ErrParameterInvalid, f.AddTimeline("Sheet1", opts))
+	}
+	// Test add a timeline with not exist pivot table
+	assert.EqualError(t, f.AddTimeline("Sheet1", &TimelineOptions{
+		Name: "Date", Cell: "Q1", TableSheet: "Sheet1", TableName: "PivotTableN",
+	}), "table PivotTableN does not exist")
+	// Test add a timeline with not exist field and non-date field
+	for _, name := range []string{"Month", "Region"} {
+		assert.Equal(t, newInvalidTimelineNameError(name), f.AddTimeline("Sheet1", &TimelineOptions{
+			Name: name, Cell: "Q1", TableSheet: "Sheet1", TableName: "PivotTable1",
+		}))
+	}
+	// Test add a timeline with not exist worksheet
+	assert.EqualError(t, f.AddTimeline("SheetN", &TimelineOptions{
+		Name: "Date", Cell: "Q1", TableSheet: "Sheet1", TableName: "PivotTable1",
+	}), "sheet SheetN does not exist")
+	assert.EqualError(t, f.AddTimeline("Sheet1", &TimelineOptions{
+		Name: "Date", Cell: "Q1", TableSheet: "SheetN", TableName: "PivotTable1",
+	}), "sheet SheetN does not exist")
+	// Test add a timeline with invalid cell reference
+	assert.EqualError(t, f.AddTimeline("Sheet1", &TimelineOptions{
+		Name: "Date", Cell: "A", TableSheet: "Sheet1", TableName: "PivotTable1",
+	}), newCellNameToCoordinatesError("A", newInvalidCellNameError("A")).Error())
+	assert.NoError(t, f.Close())
+}
+
+func TestGetTimelineSource(t *testing.T) {
+	f := NewFile()
+	assert.NoError(t, f.SetSheetRow("Sheet1", "A1", &[]interface{}{"Date", "Sales"}))
+	assert.NoError(t, f.SetSheetRow("Sheet1", "A2", &[]interface{}{nil, 100}))
+	assert.NoError(t, f.AddPivotTable(&PivotTableOptions{
+		DataRange:       "Sheet1!A1:B2",
+		PivotTableRange: "Sheet1!E1:G20",
+		Rows:            []PivotTableField{{Data: "Date"}},
+		Data:            []PivotTableField{{Data: "Sales"}},
+	}))
+	opts := &TimelineOptions{Name: "Date", Cell: "I1", TableSheet: "Sheet1", TableName: "PivotTable1"}
+	// Test get the timeline source with blank date field
+	_, _, err := f.getTimelineSource(opts)
+	assert.Equal(t, newInvalidTimelineNameError("Date"), err)
+	// Test get the timeline source with unsupported charset workbook
+	assert.NoError(t, f.SetCellValue("Sheet1", "A2", 45000))
+	f.WorkBook = nil
+	f.Pkg.Store(defaultXMLPathWorkbook, MacintoshCyrillicCharset)
+	_, _, err = f.getTimelineSource(opts)
+	assert.EqualError(t, err, "XML syntax error on line 1: invalid UTF-8")
+	assert.NoError(t, f.Close())
+	// Test get the timeline source for the pivot table with cached dataset
+	f = NewFile()
+	assert.NoError(t, f.AddPivotTable(&PivotTableOptions{
+		DataSource:      [][]interface{}{{"Date", "Sales"}, {45000, 100}},
+		PivotTableRange: "Sheet1!E1:G20",
+		Rows:            []PivotTableField{{Data: "Date"}},
+		Data:            []PivotTableField{{Data: "Sales"}},
+	}))
+	_, _, err = f.getTimelineSource(opts)
+	assert.Equal(t, ErrParameterInvalid, err)
+	assert.NoError(t, f.Close())
+}
+
+func TestAddSheetTimeline(t *testing.T) {
+	f := NewFile()
+	// Test add sheet timeline with invalid worksheet extension
+	ws, ok := f.Sheet.Load("xl/worksheets/sheet1.xml")
+	assert.True(t, ok)
+	ws.(*xlsxWorksheet).ExtLst = &xlsxExtLst{Ext: "<>"}
+	_, err := f.addSheetTimeline("Sheet1")
+	assert.Error(t, err)
+	// Test add sheet timeline with existing worksheet extension
+	ws.(*xlsxWorksheet).ExtLst = &xlsxExtLst{Ext: fmt.Sprintf("<ext uri=\"%s\"></ext>", ExtURISlicerListX15)}
+	timelineID, err := f.addSheetTimeline("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, timelineID)
+	assert.Contains(t, ws.(*xlsxWorksheet).ExtLst.Ext, ExtURISlicerListX15)
+	assert.NoError(t, f.Close())
+}
+
+func TestAddTimelineCache(t *testing.T) {
+	f := NewFile()
+	// Test set timeline cache with unsupported charset timeline cache
+	f.Pkg.Store("xl/timelineCaches/timelineCache1.xml", MacintoshCyrillicCharset)
+	f.ContentTypes = nil
+	f.Pkg.Store(defaultXMLPathContentTypes, MacintoshCyrillicCharset)
+	pivotCacheXML := "xl/pivotCache/pivotCacheDefinition1.xml"
+	f.Pkg.Store(pivotCacheXML, []byte(`<pivotCacheDefinition xmlns="`+NameSpaceSpreadSheet.Value+`"/>`))
+	_, err := f.setTimelineCache(&TimelineOptions{Name: "Date"}, &PivotTableOptions{pivotCacheXML: pivotCacheXML},
+		[]time.Time{{}, {}})
+	assert.EqualError(t, err, "XML syntax error on line 1: invalid UTF-8")
+	// Test add timeline cache with unsupported charset pivot cache
+	f.Pkg.Store(pivotCacheXML, MacintoshCyrillicCharset)
+	assert.EqualError(t, f.addTimelineCache("NativeTimeline_Date", &TimelineOptions{Name: "Date"},
+		&PivotTableOptions{pivotCacheXML: pivotCacheXML}, []time.Time{{}, {}}), "XML syntax error on line 1: invalid UTF-8")
+	assert.NoError(t, f.Close())
+}
+
+func TestAddWorkbookTimelineCache(t *testing.T) {
+	f := NewFile()
+	// Test add workbook timeline cache with invalid workbook extension
+	wb, err := f.workbookReader()
+	assert.NoError(t, err)
+	wb.ExtLst = &xlsxExtLst{Ext: "<>"}
+	assert.Error(t, f.addWorkbookTimelineCache(1))
+	// Test add workbook timeline cache with unsupported charset workbook
+	f.WorkBook = nil
+	f.Pkg.Store(defaultXMLPathWorkbook, MacintoshCyrillicCharset)
+	assert.EqualError(t, f.addWorkbookTimelineCache(1), "XML syntax error on line 1: invalid UTF-8")
+	assert.NoError(t, f.Close())
+}
+
+func TestAddTimelinePart(t *testing.T) {
+	f := NewFile()
+	// Test add timeline with unsupported charset timeline
+	f.Pkg.Store("xl/timelines/timeline1.xml", MacintoshCyrillicCharset)
+	assert.EqualError(t, f.addTimeline(1, xlsxTimeline{}), "XML syntax error on line 1: invalid UTF-8")
+	// Test add timeline with unsupported charset content types
+	f.ContentTypes = nil
+	f.Pkg.Store(defaultXMLPathContentTypes, MacintoshCyrillicCharset)
+	assert.EqualError(t, f.addTimeline(2, xlsxTimeline{}), "XML syntax error on line 1: invalid UTF-8")
+	assert.NoError(t, f.Close())
+}
@@ -130,13 +130,14 @@ func (f *File) AddComment(sheet string, opts Comment) error {
 //
 //	err := f.DeleteComment("Sheet1", "A30")
 func (f *File) DeleteComment(sheet, cell string) error {
-	if err := checkSheetName(sheet); err != nil {
+	ws, err := f.workSheetReader(sheet)
+	if err != nil {
 		return err
 	}
-	sheetXMLPath, ok := f.getSheetXMLPath(sheet)
-	if !ok {
-		return ErrSheetNotExist{sheet}
+	if err = f.checkObjectsProtection(ws); err != nil {
+		return err
 	}
+	sheetXMLPath, _ := f.getSheetXMLPath(sheet)
 	commentsXML := f.getSheetComments(filepath.Base(sheetXMLPath))
 	if !strings.HasPrefix(commentsXML, "/") {
 		commentsXML = "xl" + strings.TrimPrefix(commentsXML, "..")
@@ -375,6 +376,9 @@ func (f *File) DeleteFormControl(sheet, cell string) error {
 	if err != nil {
 		return err
 	}
+	if err = f.checkObjectsProtection(ws); err != nil {
+		return err
+	}
 	col, row, err := CellNameToCoordinates(cell)
 	if err != nil {
 		return err
@@ -498,6 +502,9 @@ func (f *File) addVMLObject(opts vmlOptions) error {
 	if err != nil {
 		return err
 	}
+	if err = f.checkObjectsProtection(ws); err != nil {
+		return err
+	}
 	vmlID := f.countComments() + 1
 	if opts.formCtrl {
 		if opts.Type > FormControlScrollBar {
@@ -0,0 +1,192 @@
+// Copyright 2016 - 2023 The excelize Authors. All rights reserved. Use of
+// this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+//
+// package excelize_ch providing a set of functions that allow you to write to and
+// read from XLAM / XLSM / XLSX / XLTM / XLTX files. Supports reading and
+// writing spreadsheet documents generated by Microsoft Excel™ 2007 and later.
+// Supports complex components by high compatibility, and provided streaming
+// API for generating or reading data from a worksheet with huge amounts of
+// data. This library needs Go version 1.16 or later.
+
+package excelize_ch
+
+import (
+	"strings"
+	"sync"
+)
+
+// cellWatchers directly maps the cell watch list of the workbook and the
+// pending change events of the watched cells.
+type cellWatchers struct {
+	mu       sync.Mutex
+	watchers []*cellWatcher
+	events   []watchEvent
+	pending  map[watchEvent]struct{}
+}
+
+// cellWatcher directly maps the watched cell range and the callback function.
+type cellWatcher struct {
+	sheet       string
+	coordinates []int
+	fn          func(sheet, cell string)
+}
+
+// watchEvent directly maps the pending change event of the watcher and the
+// coordinates of the changed area in the watched cell range.
+type watchEvent struct {
+	watcher *cellWatcher
+	area    [4]int
+}
+
+// Watch provides a function to subscribe to the value changes of the cells by
+// given worksheet name, cell reference or range reference and callback
+// function. The callback function will be invoked with the worksheet name and
+// cell reference of each changed cell, after the API call which changes the
+// watched cell directly. If the watched cells have been shifted by inserting,
+// removing, duplicating or moving rows and columns, the callback function
+// will be invoked only once with the range reference of the shifted cells in
+// the watched range, such as "A5:B10", or the cell reference if only one cell
+// has been shifted. For example, print the changed cells in the range A1:B10
+// on Sheet1:
+//
+//	err := f.Watch("Sheet1", "A1:B10", func(sheet, cell string) {
+//	    fmt.Println(sheet, cell, "changed")
+//	})
+//
+// Note that the callback function will be invoked synchronously, changing
+// the watched cell in the callback function will invoke the callback function
+// again.
+func (f *File) Watch(sheet, ref string, fn func(sheet, cell string)) error {
+	coordinates, err := f.prepareWatchRef(sheet, ref)
+	if err != nil {
+		return err
+	}
+	if fn == nil {
+		return ErrParameterInvalid
+	}
+	f.watch.mu.Lock()
+	defer f.watch.mu.Unlock()
+	f.watch.watchers = append(f.watch.watchers, &cellWatcher{sheet: sheet, coordinates: coordinates, fn: fn})
+	return err
+}
+
+// Unwatch provides a function to cancel all subscriptions of the value changes
+// by given worksheet name, cell reference or range reference.
+func (f *File) Unwatch(sheet, ref string) error {
+	coordinates, err := f.prepareWatchRef(sheet, ref)
+	if err != nil {
+		return err
+	}
+	f.watch.mu.Lock()
+	defer f.watch.mu.Unlock()
+	watchers := f.watch.watchers[:0]
+	for _, w := range f.watch.watchers {
+		if strings.EqualFold(w.sheet, sheet) && coordinatesContains(w.coordinates, coordinates) &&
+			coordinatesContains(coordinates, w.coordinates) {
+			continue
+		}
+		watchers = append(watchers, w)
+	}
+	f.watch.watchers = watchers
+	return err
+}
+
+// prepareWatchRef provides a function to check the worksheet name and returns
+// the coordinates of the watched cell or range reference.
+func (f *File) prepareWatchRef(sheet, ref string) ([]int, error) {
+	if err := checkSheetName(sheet); err != nil {
+		return nil, err
+	}
+	if _, ok := f.getSheetXMLPath(sheet); !ok {
+		return nil, ErrSheetNotExist{sheet}
+	}
+	if !strings.Contains(ref, ":") {
+		ref += ":" + ref
+	}
+	coordinates, err := rangeRefToCoordinates(ref)
+	if err != nil {
+		return nil, err
+	}
+	_ = sortCoordinates(coordinates)
+	return coordinates, err
+}
+
+// coordinatesContains returns if the area of the coordinates b is inside of
+// the area of the coordinates a.
+func coordinatesContains(a, b []int) bool {
+	return b[0] >= a[0] && b[1] >= a[1] && b[2] <= a[2] && b[3] <= a[3]
+}
+
+// addWatchEvents provides a function to add the change events of the watched
+// cells in the given area by worksheet name and the coordinates of the area.
+// Only one event with the intersection of the given area and the watched
+// range will be added for each watcher, and the event will be skipped if the
+// area has been covered by the pending events of the watcher.
+func (f *File) addWatchEvents(sheet string, coordinates []int) {
+	f.watch.mu.Lock()
+	defer f.watch.mu.Unlock()
+	for _, w := range f.watch.watchers {
+		if !strings.EqualFold(w.sheet, sheet) {
+			continue
+		}
+		event := watchEvent{watcher: w, area: [4]int{coordinates[0], coordinates[1], coordinates[2], coordinates[3]}}
+		for i := range event.area {
+			if (i < 2 && w.coordinates[i] > event.area[i]) || (i >= 2 && w.coordinates[i] < event.area[i]) {
+				event.area[i] = w.coordinates[i]
+			}
+		}
+		if event.area[0] > event.area[2] || event.area[1] > event.area[3] {
+			continue
+		}
+		if _, ok := f.watch.pending[event]; ok {
+			continue
+		}
+		if event.area[0] != event.area[2] || event.area[1] != event.area[3] {
+			if f.watch.covered(event) {
+				continue
+			}
+		}
+		if f.watch.pending == nil {
+			f.watch.pending = make(map[watchEvent]struct{})
+		}
+		f.watch.pending[event] = struct{}{}
+		f.watch.events = append(f.watch.events, event)
+	}
+}
+
+// covered returns if the changed area of the given event is inside of the
+// changed area of the pending events of the same watcher.
+func (cw *cellWatchers) covered(event watchEvent) bool {
+	for _, e := range cw.events {
+		if e.watcher == event.watcher && coordinatesContains(e.area[:], event.area[:]) {
+			return true
+		}
+	}
+	return false
+}
+
+// dispatchWatchEvents provides a function to invoke the callback functions of
+// the watched cells for the pending change events.
+func (f *File) dispatchWatchEvents() {
+	f.watch.mu.Lock()
+	events, watchers := f.watch.events, make(map[*cellWatcher]struct{}, len(f.watch.watchers))
+	for _, w := range f.watch.watchers {
+		watchers[w] = struct{}{}
+	}
+	f.watch.events, f.watch.pending = nil, nil
+	f.watch.mu.Unlock()
+	for _, event := range events {
+		if _, ok := watchers[event.watcher]; !ok {
+			continue
+		}
+		ref, err := CoordinatesToCellName(event.area[0], event.area[1])
+		if event.area[0] != event.area[2] || event.area[1] != event.area[3] {
+			ref, err = f.coordinatesToRangeRef(event.area[:])
+		}
+		if err != nil {
+			continue
+		}
+		event.watcher.fn(event.watcher.sheet, ref)
+	}
+}
@@ -0,0 +1,102 @@
+package excelize_ch
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWatch(t *testing.T) {
+	f := NewFile()
+	var changed []string
+	assert.NoError(t, f.Watch("Sheet1", "B2:C3", func(sheet, cell string) {
+		if strings.Contains(cell, ":") {
+			changed = append(changed, sheet+"!"+cell)
+			return
+		}
+		value, err := f.GetCellValue(sheet, cell)
+		assert.NoError(t, err)
+		changed = append(changed, sheet+"!"+cell+"="+value)
+	}))
+	// Test change the watched cells directly
+	assert.NoError(t, f.SetCellValue("Sheet1", "A1", 1))
+	assert.Empty(t, changed)
+	assert.NoError(t, f.SetCellValue("Sheet1", "B2", 2))
+	assert.NoError(t, f.SetCellFormula("Sheet1", "C3", "=B2*2"))
+	assert.NoError(t, f.SetSheetRow("Sheet1", "A3", &[]interface{}{"a", "b"}))
+	assert.Equal(t, []string{"Sheet1!B2=2", "Sheet1!C3=", "Sheet1!B3=b"}, changed)
+	// Test change the watched cells by the batch
+	changed = nil
+	assert.NoError(t, f.BatchUpdate(func(b *Batch) error {
+		return b.SetCellValue("Sheet1", "C2", true)
+	}))
+	assert.Equal(t, []string{"Sheet1!C2=TRUE"}, changed)
+	// Test shift the watched cells by the row and column operations
+	changed = nil
+	assert.NoError(t, f.InsertRows("Sheet1", 3, 1))
+	assert.Equal(t, []string{"Sheet1!B3:C3"}, changed)
+	changed = nil
+	assert.NoError(t, f.RemoveRow("Sheet1", 4))
+	assert.NoError(t, f.InsertCols("Sheet1", "D", 1))
+	assert.Empty(t, changed)
+	assert.NoError(t, f.RemoveCol("Sheet1", "A"))
+	assert.Equal(t, []string{"Sheet1!B2:C3"}, changed)
+	changed = nil
+	assert.NoError(t, f.MoveRows("Sheet1", 1, 1, 2))
+	assert.Equal(t, []string{"Sheet1!B2:C2"}, changed)
+	// Test unwatch the cells
+	changed = nil
+	assert.NoError(t, f.Unwatch("Sheet1", "B2:C3"))
+	assert.NoError(t, f.SetCellValue("Sheet1", "B2", 3))
+	assert.Empty(t, changed)
+	// Test watch a single cell
+	assert.NoError(t, f.Watch("Sheet1", "D4", func(sheet, cell string) {
+		changed = append(changed, sheet+"!"+cell)
+	}))
+	assert.NoError(t, f.SetCellStr("Sheet1", "D4", "d"))
+	assert.NoError(t, f.DuplicateRow("Sheet1", 3))
+	assert.Equal(t, []string{"Sheet1!D4", "Sheet1!D4"}, changed)
+	// Test watch the whole worksheet and shift the cells with the range level
+	// change event
+	changed = nil
+	assert.NoError(t, f.Unwatch("Sheet1", "D4"))
+	assert.NoError(t, f.Watch("Sheet1", "A1:XFD1048576", func(sheet, cell string) {
+		changed = append(changed, sheet+"!"+cell)
+	}))
+	assert.NoError(t, f.InsertRows("Sheet1", 1, 1))
+	assert.NoError(t, f.InsertRows("Sheet1", 2, 1))
+	assert.NoError(t, f.SetCellValue("Sheet1", "A1", 1))
+	assert.NoError(t, f.SetCellValue("Sheet1", "A1", 2))
+	assert.NoError(t, f.InsertCols("Sheet1", "B", 1))
+	assert.Equal(t, []string{"Sheet1!A1:XFD1048576", "Sheet1!A2:XFD1048576", "Sheet1!A1", "Sheet1!A1", "Sheet1!B1:XFD1048576"}, changed)
+	// Test coalesce the change events covered by the pending events
+	changed = nil
+	f.addWatchEvents("Sheet1", []int{1, 5, MaxColumns, TotalRows})
+	f.addWatchEvents("Sheet1", []int{1, 6, MaxColumns, TotalRows})
+	f.addWatchEvents("Sheet1", []int{2, 2, 2, 2})
+	f.addWatchEvents("Sheet1", []int{2, 2, 2, 2})
+	f.dispatchWatchEvents()
+	assert.Equal(t, []string{"Sheet1!A5:XFD1048576", "Sheet1!B2"}, changed)
+	// Test watch the formula cells on recalculation
+	g := NewFile()
+	changed = nil
+	assert.NoError(t, g.SetCellFormula("Sheet1", "B1", "A1*2"))
+	assert.NoError(t, g.Watch("Sheet1", "B1", func(sheet, cell string) {
+		changed = append(changed, sheet+"!"+cell)
+	}))
+	assert.NoError(t, g.SetCellValue("Sheet1", "A1", 1))
+	assert.Empty(t, changed)
+	assert.NoError(t, g.Recalculate(true))
+	assert.Equal(t, []string{"Sheet1!B1"}, changed)
+	assert.NoError(t, g.Recalculate(false))
+	assert.Equal(t, []string{"Sheet1!B1"}, changed)
+	assert.NoError(t, g.Close())
+	// Test watch and unwatch with invalid parameters
+	assert.Equal(t, ErrParameterInvalid, f.Watch("Sheet1", "A1", nil))
+	assert.Equal(t, ErrSheetNotExist{"SheetN"}, f.Watch("SheetN", "A1", func(sheet, cell string) {}))
+	assert.Equal(t, ErrSheetNameInvalid, f.Watch("Sheet:1", "A1", func(sheet, cell string) {}))
+	assert.Equal(t, newCellNameToCoordinatesError("A", newInvalidCellNameError("A")), f.Watch("Sheet1", "A", func(sheet, cell string) {}))
+	assert.Equal(t, ErrSheetNotExist{"SheetN"}, f.Unwatch("SheetN", "A1"))
+	assert.NoError(t, f.Close())
+}
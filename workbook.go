@@ -14,12 +14,24 @@ package excelize_ch
 import (
 	"bytes"
 	"encoding/xml"
+	"html"
 	"io"
+	"path"
 	"path/filepath"
+	"regexp"
+	"sort"
 	"strconv"
 	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/mohae/deepcopy"
 )
 
+// chartFormulaExp is the regular expression for matching the formula elements
+// in the chart part.
+var chartFormulaExp = regexp.MustCompile(`(<(?:c:)?f>)([^<]*)(</(?:c:)?f>)`)
+
 // SetWorkbookProps provides a function to sets workbook properties.
 func (f *File) SetWorkbookProps(opts *WorkbookPropsOptions) error {
 	wb, err := f.workbookReader()
@@ -59,6 +71,110 @@ func (f *File) GetWorkbookProps() (WorkbookPropsOptions, error) {
 	return opts, err
 }
 
+// SetCalcProps provides a function to set the calculation properties of the
+// workbook. The iterative calculation settings are used to calculate the
+// formulas with circular references by the Calculate and Recalculate
+// functions. The optional value of the CalcMode field is "manual", "auto" or
+// "autoNoTable". For example, enable the iterative calculation with maximum
+// 100 iterations and the maximum change 0.001:
+//
+//	iterate, count, delta := true, uint(100), 0.001
+//	err := f.SetCalcProps(&excelize.CalcPropsOptions{
+//	    Iterate:      &iterate,
+//	    IterateCount: &count,
+//	    IterateDelta: &delta,
+//	})
+func (f *File) SetCalcProps(opts *CalcPropsOptions) error {
+	wb, err := f.workbookReader()
+	if err != nil {
+		return err
+	}
+	if opts == nil {
+		return nil
+	}
+	if opts.CalcMode != nil && inStrSlice([]string{"manual", "auto", "autoNoTable"}, *opts.CalcMode, true) == -1 {
+		return ErrParameterInvalid
+	}
+	if opts.IterateDelta != nil && *opts.IterateDelta < 0 {
+		return ErrParameterInvalid
+	}
+	if wb.CalcPr == nil {
+		wb.CalcPr = new(xlsxCalcPr)
+	}
+	if opts.CalcMode != nil {
+		wb.CalcPr.CalcMode = *opts.CalcMode
+	}
+	if opts.FullCalcOnLoad != nil {
+		wb.CalcPr.FullCalcOnLoad = *opts.FullCalcOnLoad
+	}
+	if opts.Iterate != nil {
+		wb.CalcPr.Iterate = *opts.Iterate
+	}
+	if opts.IterateCount != nil {
+		wb.CalcPr.IterateCount = int(*opts.IterateCount)
+	}
+	if opts.IterateDelta != nil {
+		wb.CalcPr.IterateDelta = *opts.IterateDelta
+	}
+	return nil
+}
+
+// GetCalcProps provides a function to get the calculation properties of the
+// workbook, the default values will be returned if the properties are not
+// specified.
+func (f *File) GetCalcProps() (CalcPropsOptions, error) {
+	opts := CalcPropsOptions{
+		CalcMode:       stringPtr("auto"),
+		FullCalcOnLoad: boolPtr(false),
+		Iterate:        boolPtr(false),
+		IterateCount:   uintPtr(100),
+		IterateDelta:   float64Ptr(0.001),
+	}
+	wb, err := f.workbookReader()
+	if err != nil || wb.CalcPr == nil {
+		return opts, err
+	}
+	if wb.CalcPr.CalcMode != "" {
+		opts.CalcMode = stringPtr(wb.CalcPr.CalcMode)
+	}
+	opts.FullCalcOnLoad = boolPtr(wb.CalcPr.FullCalcOnLoad)
+	opts.Iterate = boolPtr(wb.CalcPr.Iterate)
+	if wb.CalcPr.IterateCount > 0 {
+		opts.IterateCount = uintPtr(uint(wb.CalcPr.IterateCount))
+	}
+	if wb.CalcPr.IterateDelta > 0 {
+		opts.IterateDelta = float64Ptr(wb.CalcPr.IterateDelta)
+	}
+	return opts, err
+}
+
+// SetWorkbookCustomProps provides a function to set the custom properties of
+// the workbook by given names and values of the properties. The custom
+// properties will be stored in the extension list of the workbook, and the
+// other extensions in the list will be kept untouched. The existing
+// properties which are not given will be kept, and the property will be
+// deleted if the value is empty. For example:
+//
+//	err := f.SetWorkbookCustomProps(map[string]string{"Model": "Budget 2024"})
+func (f *File) SetWorkbookCustomProps(props map[string]string) error {
+	wb, err := f.workbookReader()
+	if err != nil {
+		return err
+	}
+	wb.ExtLst, err = setCustomPropsExtLst(wb.ExtLst, props)
+	return err
+}
+
+// GetWorkbookCustomProps provides a function to get the names and values of
+// the custom properties stored in the extension list of the workbook.
+func (f *File) GetWorkbookCustomProps() (map[string]string, error) {
+	wb, err := f.workbookReader()
+	if err != nil {
+		return map[string]string{}, err
+	}
+	return getCustomPropsExtLst(wb.ExtLst)
+}
+
 // ProtectWorkbook provides a function to prevent other users from viewing
 // hidden worksheets, adding, moving, deleting, or hiding worksheets, and
 // renaming worksheets in a workbook. The optional field AlgorithmName
@@ -130,6 +246,855 @@ func (f *File) UnprotectWorkbook(password ...string) error {
 	return err
 }
 
+// SetCustomUI provides a function to attach the custom UI (ribbon) definition
+// with image resources to the workbook, usually used in the macro-enabled
+// workbook or template. The version of the custom UI part will be detected by
+// the namespace of the root element, the existing custom UI definition of
+// the same version will be replaced. The definition will be validated before
+// attaching, which root element must be customUI, the control identifiers
+// must be unique, and the image attributes must reference the given images.
+// For example, add a custom tab with a button in the ribbon:
+//
+//	file, err := os.ReadFile("icon.png")
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	err = f.SetCustomUI(&excelize.CustomUIOptions{
+//	    Content: `<customUI xmlns="http://schemas.microsoft.com/office/2009/07/customui">
+//	    <ribbon><tabs><tab id="customTab" label="Custom">
+//	        <group id="customGroup" label="Group">
+//	            <button id="customButton" label="Run" image="icon" size="large" onAction="Run"/>
+//	        </group>
+//	    </tab></tabs></ribbon>
+//	</customUI>`,
+//	    Images: []excelize.CustomUIImage{
+//	        {ID: "icon", Extension: ".png", File: file},
+//	    },
+//	})
+func (f *File) SetCustomUI(opts *CustomUIOptions) error {
+	if opts == nil {
+		return ErrParameterInvalid
+	}
+	relType, partName, err := validateCustomUI(opts)
+	if err != nil {
+		return err
+	}
+	if err = f.deleteCustomUI(relType); err != nil {
+		return err
+	}
+	f.Pkg.Store(partName, []byte(opts.Content))
+	if len(opts.Images) > 0 {
+		rels := &xlsxRelationships{}
+		for _, image := range opts.Images {
+			target := "images/" + image.ID + supportedImageTypes[strings.ToLower(image.Extension)]
+			f.Pkg.Store(path.Join(path.Dir(partName), target), image.File)
+			rels.Relationships = append(rels.Relationships, xlsxRelationship{
+				ID: image.ID, Type: SourceRelationshipImage, Target: target,
+			})
+		}
+		f.Relationships.Store(getPartRelsPath(partName), rels)
+		if err = f.setContentTypePartImageExtensions(); err != nil {
+			return err
+		}
+	}
+	f.addRels("_rels/.rels", relType, partName, "")
+	return err
+}
+
+// GetCustomUI provides a function to get the custom UI (ribbon) definitions
+// with image resources of the workbook.
+func (f *File) GetCustomUI() ([]CustomUIOptions, error) {
+	var customUI []CustomUIOptions
+	rels, err := f.relsReader("_rels/.rels")
+	if err != nil || rels == nil {
+		return customUI, err
+	}
+	for _, rel := range rels.Relationships {
+		if rel.Type != SourceRelationshipCustomUI && rel.Type != SourceRelationshipCustomUI14 {
+			continue
+		}
+		partName := getRelsTargetPath("", rel.Target)
+		opts := CustomUIOptions{Content: string(f.readBytes(partName))}
+		imageRels, err := f.relsReader(getPartRelsPath(partName))
+		if err != nil {
+			return customUI, err
+		}
+		if imageRels != nil {
+			for _, imageRel := range imageRels.Relationships {
+				if imageRel.Type != SourceRelationshipImage {
+					continue
+				}
+				target := getRelsTargetPath(partName, imageRel.Target)
+				opts.Images = append(opts.Images, CustomUIImage{
+					ID: imageRel.ID, Extension: path.Ext(target), File: f.readBytes(target),
+				})
+			}
+		}
+		customUI = append(customUI, opts)
+	}
+	return customUI, err
+}
+
+// validateCustomUI provides a function to validate the custom UI definition,
+// and returns the relationship type and part name of the custom UI part by
+// given custom UI settings.
+func validateCustomUI(opts *CustomUIOptions) (string, string, error) {
+	var relType, partName string
+	images := map[string]struct{}{}
+	for _, image := range opts.Images {
+		if _, ok := supportedImageTypes[strings.ToLower(image.Extension)]; !ok {
+			return relType, partName, ErrImgExt
+		}
+		if _, ok := images[image.ID]; ok || image.ID == "" || strings.ContainsAny(image.ID, "/\\") {
+			return relType, partName, ErrParameterInvalid
+		}
+		images[image.ID] = struct{}{}
+	}
+	ids := map[string]struct{}{}
+	decoder := xml.NewDecoder(strings.NewReader(opts.Content))
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return relType, partName, err
+		}
+		element, ok := token.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		if relType == "" {
+			switch {
+			case element.Name.Local == "customUI" && element.Name.Space == NameSpaceCustomUI:
+				relType, partName = SourceRelationshipCustomUI, "customUI/customUI.xml"
+			case element.Name.Local == "customUI" && element.Name.Space == NameSpaceCustomUI14:
+				relType, partName = SourceRelationshipCustomUI14, "customUI/customUI14.xml"
+			default:
+				return relType, partName, ErrCustomUI
+			}
+		}
+		for _, attr := range element.Attr {
+			if attr.Name.Space != "" {
+				continue
+			}
+			switch attr.Name.Local {
+			case "id":
+				if _, ok := ids[attr.Value]; ok {
+					return relType, partName, newDuplicateCustomUIIDError(attr.Value)
+				}
+				ids[attr.Value] = struct{}{}
+			case "image":
+				if _, ok := images[attr.Value]; !ok {
+					return relType, partName, newNoExistCustomUIImageError(attr.Value)
+				}
+			}
+		}
+	}
+	if relType == "" {
+		return relType, partName, ErrCustomUI
+	}
+	return relType, partName, nil
+}
+
+// deleteCustomUI provides a function to delete the custom UI part and the
+// image resources of it by given relationship type.
+func (f *File) deleteCustomUI(relType string) error {
+	rels, err := f.relsReader("_rels/.rels")
+	if err != nil || rels == nil {
+		return err
+	}
+	var parts []string
+	rels.mu.Lock()
+	for i := 0; i < len(rels.Relationships); i++ {
+		if rel := rels.Relationships[i]; rel.Type == relType {
+			parts = append(parts, getRelsTargetPath("", rel.Target))
+			rels.Relationships = append(rels.Relationships[:i], rels.Relationships[i+1:]...)
+			i--
+		}
+	}
+	rels.mu.Unlock()
+	for _, partName := range parts {
+		imageRels, err := f.relsReader(getPartRelsPath(partName))
+		if err != nil {
+			return err
+		}
+		if imageRels != nil {
+			for _, rel := range imageRels.Relationships {
+				if err = f.deletePart(getRelsTargetPath(partName, rel.Target)); err != nil {
+					return err
+				}
+			}
+		}
+		if err = f.deletePart(partName); err != nil {
+			return err
+		}
+	}
+	return err
+}
+
+// ExtractSheets provides a function to extract the given sheets into a new
+// workbook, with all their dependencies, such as styles, shared strings,
+// images, charts, tables and so on. The other sheets and the parts only used
+// by them will be removed from the new workbook, and the unused styles and
+// shared strings will be removed too. The formulas in the extracted
+// worksheets referencing the removed sheets will be replaced with the cached
+// values, and the defined names referencing the removed sheets will be
+// removed. For example, extract the sheets named Sheet1 and Sheet3 into a new
+// workbook:
+//
+//	extract, err := f.ExtractSheets("Sheet1", "Sheet3")
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	if err := extract.SaveAs("Book2.xlsx"); err != nil {
+//	    fmt.Println(err)
+//	}
+func (f *File) ExtractSheets(names ...string) (*File, error) {
+	if len(names) == 0 {
+		return nil, ErrParameterRequired
+	}
+	kept := map[string]struct{}{}
+	for _, name := range names {
+		idx, err := f.GetSheetIndex(name)
+		if err != nil {
+			return nil, err
+		}
+		if idx == -1 {
+			return nil, ErrSheetNotExist{name}
+		}
+		kept[strings.ToLower(name)] = struct{}{}
+	}
+	buf := new(bytes.Buffer)
+	if err := f.writeDirectToWriter(buf); err != nil {
+		return nil, err
+	}
+	var opts Options
+	if f.options != nil {
+		opts = *f.options
+		opts.Password = ""
+	}
+	extract, err := OpenReader(buf, opts)
+	if err != nil {
+		return nil, err
+	}
+	removed := map[string]struct{}{}
+	for _, sheet := range extract.GetSheetList() {
+		if _, ok := kept[strings.ToLower(sheet)]; !ok {
+			removed[strings.ToLower(sheet)] = struct{}{}
+		}
+	}
+	if err = extract.removeSheetRefs(removed); err != nil {
+		return extract, err
+	}
+	for _, sheet := range extract.GetSheetList() {
+		if _, ok := removed[strings.ToLower(sheet)]; ok {
+			if err = extract.DeleteSheet(sheet); err != nil {
+				return extract, err
+			}
+		}
+	}
+	if err = extract.removeUnreachableParts(); err != nil {
+		return extract, err
+	}
+	if err = extract.pruneSharedStrings(); err != nil {
+		return extract, err
+	}
+	return extract, extract.pruneStyles()
+}
+
+// removeSheetRefs provides a function to replace the formulas referencing the
+// given sheets with the cached values, and remove the defined names
+// referencing the given sheets. The sheet names should be in lower case.
+func (f *File) removeSheetRefs(sheets map[string]struct{}) error {
+	isKept := func(sheet string) (string, bool) {
+		_, ok := sheets[strings.ToLower(sheet)]
+		return sheet, !ok
+	}
+	wb, err := f.workbookReader()
+	if err != nil {
+		return err
+	}
+	if wb.DefinedNames != nil {
+		for i := 0; i < len(wb.DefinedNames.DefinedName); i++ {
+			if _, removed := adjustFormulaSheetRefs(wb.DefinedNames.DefinedName[i].Data, isKept); removed {
+				wb.DefinedNames.DefinedName = append(wb.DefinedNames.DefinedName[:i], wb.DefinedNames.DefinedName[i+1:]...)
+				i--
+			}
+		}
+	}
+	return f.rangeWorksheets(func(sheet string, ws *xlsxWorksheet) error {
+		if _, ok := sheets[strings.ToLower(sheet)]; ok {
+			return nil
+		}
+		for rowIdx := range ws.SheetData.Row {
+			for colIdx := range ws.SheetData.Row[rowIdx].C {
+				c := &ws.SheetData.Row[rowIdx].C[colIdx]
+				if c.F == nil {
+					continue
+				}
+				if _, removed := adjustFormulaSheetRefs(c.F.Content, isKept); removed {
+					if err := f.removeFormula(c, ws, sheet); err != nil {
+						return err
+					}
+				}
+			}
+		}
+		return nil
+	})
+}
+
+// CollisionPolicy defines the policy to resolve the sheet name collision
+// when appending the sheets from another workbook.
+type CollisionPolicy byte
+
+// This section defines the currently supported sheet name collision policies.
+const (
+	CollisionPolicyRename CollisionPolicy = iota
+	CollisionPolicyReplace
+	CollisionPolicySkip
+)
+
+// appendContext defines the context used to append the sheets from the source
+// workbook, which includes the mapping of the parts, sheet names, shared
+// strings and styles between the source and the target workbook.
+type appendContext struct {
+	src                   *File
+	sst                   *xlsxSST
+	parts, sheets         map[string]string
+	skipped               map[string]struct{}
+	strings, styles, dxfs map[int]int
+}
+
+// appendSheet defines the sheet to be appended from the source workbook.
+type appendSheet struct {
+	name, tempName, partName, relType, state string
+}
+
+// AppendWorkbook provides a function to import all worksheets and chart
+// sheets from the source workbook as new sheets, with their styles, shared
+// strings, images, charts, tables, comments and so on. The sheet names will
+// be prefixed by the given prefix, and the collision with the existing sheet
+// name will be resolved by the given policy:
+//
+//	CollisionPolicyRename  | Append the sheet with the number suffix, such as "Sheet1 (2)"
+//	CollisionPolicyReplace | Replace the existing sheet with the appended sheet
+//	CollisionPolicySkip    | Skip the sheet and keep the existing sheet
+//
+// The sheet references in the formulas, data validations, conditional
+// formats, hyperlinks, chart series and defined names of the appended sheets
+// will be updated to the new sheet names, and the defined names of the source
+// workbook will be appended if there is no defined name with the same name
+// and scope. The parts of the source workbook will be read without writing
+// them into the package or unloading them from memory, so the source workbook
+// will not be changed. Note that the pivot tables and slicers will not be
+// appended. For example, append all sheets of Book2.xlsx with the prefix
+// "Book2 ":
+//
+//	src, err := excelize.OpenFile("Book2.xlsx")
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	err = f.AppendWorkbook(src, "Book2 ", excelize.CollisionPolicyRename)
+func (f *File) AppendWorkbook(src *File, prefix string, policy CollisionPolicy) error {
+	if src == nil || src == f || policy > CollisionPolicySkip {
+		return ErrParameterInvalid
+	}
+	f.writeParts()
+	f.resetFormulaIndex()
+	srcWb, err := src.workbookReader()
+	if err != nil {
+		return err
+	}
+	ctx := &appendContext{
+		src: src, parts: map[string]string{}, sheets: map[string]string{}, skipped: map[string]struct{}{},
+		strings: map[int]int{}, styles: map[int]int{0: 0}, dxfs: map[int]int{},
+	}
+	if ctx.sst, err = src.sharedStringsReader(); err != nil {
+		return err
+	}
+	sheets, err := f.getAppendSheets(ctx, srcWb, prefix, policy)
+	if err != nil {
+		return err
+	}
+	for _, sheet := range sheets {
+		name := sheet.name
+		if sheet.tempName != "" {
+			name = sheet.tempName
+		}
+		partName, err := f.appendPart(ctx, sheet.partName)
+		if err != nil {
+			return err
+		}
+		f.SheetCount++
+		wb, err := f.workbookReader()
+		if err != nil {
+			return err
+		}
+		sheetID := 0
+		for _, v := range wb.Sheets.Sheet {
+			if v.SheetID > sheetID {
+				sheetID = v.SheetID
+			}
+		}
+		rID := f.addRels(f.getWorkbookRelsPath(), sheet.relType, "/"+partName, "")
+		f.setWorkbook(name, sheetID+1, rID)
+		wb.Sheets.Sheet[len(wb.Sheets.Sheet)-1].State = sheet.state
+		f.sheetMap[name] = partName
+		if sheet.relType == SourceRelationshipWorkSheet {
+			if err = f.appendWorksheet(ctx, name); err != nil {
+				return err
+			}
+		}
+		if sheet.tempName != "" {
+			if err = f.DeleteSheet(sheet.name); err != nil {
+				return err
+			}
+			if err = f.SetSheetName(sheet.tempName, sheet.name); err != nil {
+				return err
+			}
+		}
+	}
+	return f.appendDefinedNames(ctx, srcWb)
+}
+
+// getAppendSheets provides a function to get the sheets to be appended from
+// the source workbook, and resolve the new sheet names by given prefix and
+// collision policy.
+func (f *File) getAppendSheets(ctx *appendContext, srcWb *xlsxWorkbook, prefix string, policy CollisionPolicy) ([]appendSheet, error) {
+	var sheets []appendSheet
+	srcRels, err := ctx.src.relsReader(ctx.src.getWorkbookRelsPath())
+	if err != nil || srcRels == nil {
+		return sheets, err
+	}
+	existing, taken := map[string]string{}, map[string]struct{}{}
+	for _, name := range f.GetSheetList() {
+		existing[strings.ToLower(name)] = name
+		taken[strings.ToLower(name)] = struct{}{}
+	}
+	for _, v := range srcWb.Sheets.Sheet {
+		sheet := appendSheet{state: v.State}
+		for _, rel := range srcRels.Relationships {
+			if rel.ID == v.ID && (rel.Type == SourceRelationshipWorkSheet || rel.Type == SourceRelationshipChartsheet) {
+				sheet.partName, sheet.relType = getRelsTargetPath(ctx.src.getWorkbookPath(), rel.Target), rel.Type
+			}
+		}
+		if sheet.partName == "" {
+			continue
+		}
+		if sheet.name = prefix + v.Name; utf8.RuneCountInString(sheet.name) > MaxSheetNameLength {
+			sheet.name = string([]rune(sheet.name)[:MaxSheetNameLength])
+		}
+		if err = checkSheetName(sheet.name); err != nil {
+			return sheets, err
+		}
+		if _, ok := taken[strings.ToLower(sheet.name)]; ok {
+			existingName, isExisting := existing[strings.ToLower(sheet.name)]
+			switch {
+			case isExisting && policy == CollisionPolicySkip:
+				ctx.sheets[strings.ToLower(v.Name)] = existingName
+				ctx.skipped[strings.ToLower(v.Name)] = struct{}{}
+				continue
+			case isExisting && policy == CollisionPolicyReplace:
+				sheet.tempName = getUniqueSheetName(sheet.name, taken)
+				taken[strings.ToLower(sheet.tempName)] = struct{}{}
+			default:
+				sheet.name = getUniqueSheetName(sheet.name, taken)
+			}
+		}
+		taken[strings.ToLower(sheet.name)] = struct{}{}
+		ctx.sheets[strings.ToLower(v.Name)] = sheet.name
+		sheets = append(sheets, sheet)
+	}
+	return sheets, err
+}
+
+// getUniqueSheetName provides a function to get the sheet name with the
+// number suffix which doesn't exist in the given lower case sheet names.
+func getUniqueSheetName(name string, taken map[string]struct{}) string {
+	for num := 2; ; num++ {
+		suffix := " (" + strconv.Itoa(num) + ")"
+		base := []rune(name)
+		if len(base)+len(suffix) > MaxSheetNameLength {
+			base = base[:MaxSheetNameLength-len(suffix)]
+		}
+		candidate := string(base) + suffix
+		if _, ok := taken[strings.ToLower(candidate)]; !ok {
+			return candidate
+		}
+	}
+}
+
+// adjustSheetRef provides a function to get the new sheet name of the sheet
+// in the source workbook, the sheet name will be returned as-is if the sheet
+// was not appended.
+func (ctx *appendContext) adjustSheetRef(sheet string) (string, bool) {
+	if name, ok := ctx.sheets[strings.ToLower(sheet)]; ok {
+		return name, true
+	}
+	return sheet, true
+}
+
+// appendPart provides a function to copy the part and the parts referenced by
+// it from the source workbook recursively, and returns the new part name.
+// The pivot table and slicer relationships will be skipped.
+func (f *File) appendPart(ctx *appendContext, srcPart string) (string, error) {
+	if partName, ok := ctx.parts[srcPart]; ok {
+		return partName, nil
+	}
+	content, err := ctx.readSourcePart(srcPart)
+	if err != nil {
+		return "", err
+	}
+	contentType, err := ctx.src.getPartContentType(srcPart)
+	if err != nil {
+		return "", err
+	}
+	partName := f.getUniquePartName(srcPart)
+	ctx.parts[srcPart] = partName
+	switch contentType {
+	case ContentTypeSpreadSheetMLTable:
+		if content, err = f.appendTable(content); err != nil {
+			return partName, err
+		}
+	case ContentTypeDrawingML:
+		content = ctx.adjustChartSheetRefs(content)
+	}
+	f.Pkg.Store(partName, content)
+	if ct, err := f.getPartContentType(partName); err != nil || ct != contentType {
+		if err != nil {
+			return partName, err
+		}
+		if err = f.setPartContentType(partName, contentType); err != nil {
+			return partName, err
+		}
+	}
+	srcRels, err := ctx.src.relsReader(getPartRelsPath(srcPart))
+	if err != nil || srcRels == nil {
+		return partName, err
+	}
+	rels := &xlsxRelationships{}
+	for _, rel := range srcRels.Relationships {
+		if rel.Type == SourceRelationshipPivotTable || rel.Type == SourceRelationshipSlicer {
+			continue
+		}
+		if target := getRelsTargetPath(srcPart, rel.Target); rel.TargetMode != "External" && ctx.hasSourcePart(target) {
+			if target, err = f.appendPart(ctx, target); err != nil {
+				return partName, err
+			}
+			rel.Target = getRelsTarget(partName, target)
+		}
+		rels.Relationships = append(rels.Relationships, rel)
+	}
+	f.Relationships.Store(getPartRelsPath(partName), rels)
+	return partName, err
+}
+
+// readSourcePart provides a function to read the content of the part from
+// the source workbook by given part name. The worksheet, drawing, comments
+// and VML drawing parts which have been parsed in memory will be serialized
+// from a copy of them, without writing into the package of the source
+// workbook.
+func (ctx *appendContext) readSourcePart(partName string) ([]byte, error) {
+	if ws, ok := ctx.src.Sheet.Load(partName); ok && ws != nil {
+		sheet := ws.(*xlsxWorksheet)
+		sheet.mu.Lock()
+		worksheet := deepcopy.Copy(sheet).(*xlsxWorksheet)
+		sheet.mu.Unlock()
+		return ctx.src.marshalWorksheet(partName, worksheet), nil
+	}
+	if d, ok := ctx.src.Drawings.Load(partName); ok && d != nil {
+		return xml.Marshal(d.(*xlsxWsDr))
+	}
+	ctx.src.mu.Lock()
+	defer ctx.src.mu.Unlock()
+	if c := ctx.src.Comments[partName]; c != nil {
+		return xml.Marshal(c)
+	}
+	if vml := ctx.src.VMLDrawing[partName]; vml != nil {
+		return xml.Marshal(vml)
+	}
+	return ctx.src.readPart(partName)
+}
+
+// hasSourcePart provides a function to check if the part exists in the
+// package or memory of the source workbook by given part name.
+func (ctx *appendContext) hasSourcePart(partName string) bool {
+	if ws, ok := ctx.src.Sheet.Load(partName); ok && ws != nil {
+		return true
+	}
+	if d, ok := ctx.src.Drawings.Load(partName); ok && d != nil {
+		return true
+	}
+	ctx.src.mu.Lock()
+	defer ctx.src.mu.Unlock()
+	return ctx.src.Comments[partName] != nil || ctx.src.VMLDrawing[partName] != nil || ctx.src.hasPart(partName)
+}
+
+// appendTable provides a function to update the ID and name of the table
+// appended from the source workbook to avoid conflicts with the existing
+// tables.
+func (f *File) appendTable(content []byte) ([]byte, error) {
+	var t xlsxTable
+	if err := f.xmlNewDecoder(bytes.NewReader(namespaceStrictToTransitional(content))).
+		Decode(&t); err != nil && err != io.EOF {
+		return content, err
+	}
+	names := map[string]struct{}{}
+	f.Pkg.Range(func(k, v interface{}) bool {
+		if strings.Contains(k.(string), "xl/tables/table") {
+			var table xlsxTable
+			if err := f.xmlNewDecoder(bytes.NewReader(namespaceStrictToTransitional(v.([]byte)))).
+				Decode(&table); err == nil {
+				names[strings.ToLower(table.Name)] = struct{}{}
+			}
+		}
+		return true
+	})
+	name := t.Name
+	for num := 2; ; num++ {
+		if _, ok := names[strings.ToLower(name)]; !ok {
+			break
+		}
+		name = t.Name + "_" + strconv.Itoa(num)
+	}
+	t.ID, t.Name, t.DisplayName = f.countTables()+1, name, name
+	table, err := marshalTable(&t)
+	return append([]byte(xml.Header), table...), err
+}
+
+// adjustChartSheetRefs provides a function to update the sheet names in the
+// series formulas of the chart appended from the source workbook.
+func (ctx *appendContext) adjustChartSheetRefs(content []byte) []byte {
+	return chartFormulaExp.ReplaceAllFunc(content, func(match []byte) []byte {
+		parts := chartFormulaExp.FindSubmatch(match)
+		formula, _ := adjustFormulaSheetRefs(html.UnescapeString(string(parts[2])), ctx.adjustSheetRef)
+		var buf bytes.Buffer
+		_ = xml.EscapeText(&buf, []byte(formula))
+		return append(append(append([]byte{}, parts[1]...), buf.Bytes()...), parts[3]...)
+	})
+}
+
+// appendWorksheet provides a function to update the style indexes, shared
+// string indexes and sheet references of the worksheet appended from the
+// source workbook.
+func (f *File) appendWorksheet(ctx *appendContext, sheet string) error {
+	ws, err := f.workSheetReader(sheet)
+	if err != nil {
+		return err
+	}
+	for rowIdx := range ws.SheetData.Row {
+		row := &ws.SheetData.Row[rowIdx]
+		if row.S, err = f.getAppendStyleID(ctx, row.S); err != nil {
+			return err
+		}
+		for colIdx := range row.C {
+			c := &row.C[colIdx]
+			if c.S, err = f.getAppendStyleID(ctx, c.S); err != nil {
+				return err
+			}
+			if c.T == "s" {
+				if c.V, err = f.getAppendSharedString(ctx, c.V); err != nil {
+					return err
+				}
+			}
+			if c.F != nil {
+				c.F.Content, _ = adjustFormulaSheetRefs(c.F.Content, ctx.adjustSheetRef)
+			}
+		}
+	}
+	if ws.Cols != nil {
+		for idx := range ws.Cols.Col {
+			if ws.Cols.Col[idx].Style, err = f.getAppendStyleID(ctx, ws.Cols.Col[idx].Style); err != nil {
+				return err
+			}
+		}
+	}
+	for _, cf := range ws.ConditionalFormatting {
+		for _, rule := range cf.CfRule {
+			if rule.DxfID != nil {
+				dxfID, err := f.getAppendDxfID(ctx, *rule.DxfID)
+				if err != nil {
+					return err
+				}
+				rule.DxfID = intPtr(dxfID)
+			}
+			for idx := range rule.Formula {
+				rule.Formula[idx], _ = adjustFormulaSheetRefs(rule.Formula[idx], ctx.adjustSheetRef)
+			}
+		}
+	}
+	if ws.DataValidations != nil {
+		for _, dv := range ws.DataValidations.DataValidation {
+			for _, formula := range []*xlsxInnerXML{dv.Formula1, dv.Formula2} {
+				if formula != nil {
+					formula.Content, _ = adjustFormulaSheetRefs(formula.Content, ctx.adjustSheetRef)
+				}
+			}
+		}
+	}
+	if ws.Hyperlinks != nil {
+		for idx := range ws.Hyperlinks.Hyperlink {
+			if location := ws.Hyperlinks.Hyperlink[idx].Location; location != "" {
+				ws.Hyperlinks.Hyperlink[idx].Location, _ = adjustFormulaSheetRefs(location, ctx.adjustSheetRef)
+			}
+		}
+	}
+	dvs, err := f.getX14DataValidations(ws)
+	if err != nil {
+		return err
+	}
+	if dvs != nil {
+		for _, dv := range dvs {
+			dv.Formula1, _ = adjustFormulaSheetRefs(dv.Formula1, ctx.adjustSheetRef)
+			dv.Formula2, _ = adjustFormulaSheetRefs(dv.Formula2, ctx.adjustSheetRef)
+		}
+		if err = f.setX14DataValidations(ws, dvs); err != nil {
+			return err
+		}
+	}
+	return f.deleteSlicerListExt(ws)
+}
+
+// deleteSlicerListExt provides a function to remove the slicer list extensions
+// of the worksheet.
+func (f *File) deleteSlicerListExt(ws *xlsxWorksheet) error {
+	if ws.ExtLst == nil {
+		return nil
+	}
+	decodeExtLst := new(decodeExtLst)
+	if err := f.xmlNewDecoder(strings.NewReader("<extLst>" + ws.ExtLst.Ext + "</extLst>")).
+		Decode(decodeExtLst); err != nil && err != io.EOF {
+		return err
+	}
+	var exts []*xlsxExt
+	for _, ext := range decodeExtLst.Ext {
+		if ext.URI != ExtURISlicerListX14 && ext.URI != ExtURISlicerListX15 {
+			exts = append(exts, ext)
+		}
+	}
+	if len(exts) == len(decodeExtLst.Ext) {
+		return nil
+	}
+	if decodeExtLst.Ext = exts; len(exts) == 0 {
+		ws.ExtLst = nil
+		return nil
+	}
+	extLstBytes, err := xml.Marshal(decodeExtLst)
+	ws.ExtLst = &xlsxExtLst{Ext: strings.TrimSuffix(strings.TrimPrefix(string(extLstBytes), "<extLst>"), "</extLst>")}
+	return err
+}
+
+// getAppendStyleID provides a function to get the cell style index in the
+// workbook by given cell style index of the source workbook.
+func (f *File) getAppendStyleID(ctx *appendContext, idx int) (int, error) {
+	if styleID, ok := ctx.styles[idx]; ok {
+		return styleID, nil
+	}
+	style, err := ctx.src.GetStyle(idx)
+	if err != nil {
+		return 0, err
+	}
+	styleID, err := f.NewStyle(style)
+	ctx.styles[idx] = styleID
+	return styleID, err
+}
+
+// getAppendDxfID provides a function to get the conditional format style
+// index in the workbook by given conditional format style index of the
+// source workbook.
+func (f *File) getAppendDxfID(ctx *appendContext, idx int) (int, error) {
+	if dxfID, ok := ctx.dxfs[idx]; ok {
+		return dxfID, nil
+	}
+	style, err := ctx.src.GetConditionalStyle(idx)
+	if err != nil {
+		return 0, err
+	}
+	dxfID, err := f.NewConditionalStyle(style)
+	ctx.dxfs[idx] = dxfID
+	return dxfID, err
+}
+
+// getAppendSharedString provides a function to get the shared string index in
+// the workbook by given shared string index of the source workbook.
+func (f *File) getAppendSharedString(ctx *appendContext, val string) (string, error) {
+	idx, err := strconv.Atoi(val)
+	if err != nil || ctx.sst == nil || idx < 0 || idx >= len(ctx.sst.SI) {
+		return val, nil
+	}
+	if sstIdx, ok := ctx.strings[idx]; ok {
+		return strconv.Itoa(sstIdx), err
+	}
+	si := ctx.sst.SI[idx]
+	if si.T != nil && len(si.R) == 0 {
+		sstIdx, err := f.setSharedString(si.T.Val)
+		ctx.strings[idx] = sstIdx
+		return strconv.Itoa(sstIdx), err
+	}
+	if err = f.sharedStringsLoader(); err != nil {
+		return val, err
+	}
+	sst, err := f.sharedStringsReader()
+	if err != nil {
+		return val, err
+	}
+	sst.mu.Lock()
+	defer sst.mu.Unlock()
+	sst.SI = append(sst.SI, si)
+	sst.Count++
+	sst.UniqueCount++
+	ctx.strings[idx] = len(sst.SI) - 1
+	return strconv.Itoa(len(sst.SI) - 1), err
+}
+
+// appendDefinedNames provides a function to append the defined names of the
+// source workbook, the defined names with the same name and scope of the
+// existing defined names and the defined names scoped to the skipped sheets
+// will be ignored.
+func (f *File) appendDefinedNames(ctx *appendContext, srcWb *xlsxWorkbook) error {
+	if srcWb.DefinedNames == nil {
+		return nil
+	}
+	wb, err := f.workbookReader()
+	if err != nil {
+		return err
+	}
+	srcSheets := ctx.src.GetSheetList()
+	for _, dn := range srcWb.DefinedNames.DefinedName {
+		if dn.LocalSheetID != nil {
+			if *dn.LocalSheetID < 0 || *dn.LocalSheetID >= len(srcSheets) {
+				continue
+			}
+			name, ok := ctx.sheets[strings.ToLower(srcSheets[*dn.LocalSheetID])]
+			if _, skipped := ctx.skipped[strings.ToLower(srcSheets[*dn.LocalSheetID])]; !ok || skipped {
+				continue
+			}
+			idx, _ := f.GetSheetIndex(name)
+			dn.LocalSheetID = intPtr(idx)
+		}
+		if wb.DefinedNames == nil {
+			wb.DefinedNames = &xlsxDefinedNames{}
+		}
+		var exist bool
+		for _, v := range wb.DefinedNames.DefinedName {
+			if exist = strings.EqualFold(v.Name, dn.Name) &&
+				((v.LocalSheetID == nil && dn.LocalSheetID == nil) ||
+					(v.LocalSheetID != nil && dn.LocalSheetID != nil && *v.LocalSheetID == *dn.LocalSheetID)); exist {
+				break
+			}
+		}
+		if !exist {
+			dn.Data, _ = adjustFormulaSheetRefs(dn.Data, ctx.adjustSheetRef)
+			wb.DefinedNames.DefinedName = append(wb.DefinedNames.DefinedName, dn)
+		}
+	}
+	return err
+}
+
 // setWorkbook update workbook property of the spreadsheet. Maximum 31
 // characters are allowed in sheet title.
 func (f *File) setWorkbook(name string, sheetID, rid int) {
@@ -206,10 +1171,12 @@ func (f *File) workbookReader() (*xlsxWorkbook, error) {
 			f.xmlAttr.Store(wbPath, attrs)
 			f.addNameSpaces(wbPath, SourceRelationship)
 		}
-		if err = f.xmlNewDecoder(bytes.NewReader(namespaceStrictToTransitional(f.readXML(wbPath)))).
+		start, content := time.Now(), f.readXML(wbPath)
+		if err = f.xmlNewDecoder(bytes.NewReader(namespaceStrictToTransitional(content))).
 			Decode(f.WorkBook); err != nil && err != io.EOF {
 			return f.WorkBook, err
 		}
+		f.logPartParsed(wbPath, len(content), start)
 	}
 	return f.WorkBook, err
 }
@@ -225,7 +1192,26 @@ func (f *File) workBookWriter() {
 			}
 		}
 		f.WorkBook.DecodeAlternateContent = nil
+		if f.WorkBook.DecodeRevisionPtr != nil {
+			f.WorkBook.RevisionPtr = &xlsxRevisionPtr{
+				RevIDLastSave:     f.WorkBook.DecodeRevisionPtr.RevIDLastSave,
+				DocumentID:        f.WorkBook.DecodeRevisionPtr.DocumentID,
+				CoauthVersionLast: f.WorkBook.DecodeRevisionPtr.CoauthVersionLast,
+				CoauthVersionMax:  f.WorkBook.DecodeRevisionPtr.CoauthVersionMax,
+				UIDLastSave:       f.WorkBook.DecodeRevisionPtr.UIDLastSave,
+			}
+		}
+		f.WorkBook.DecodeRevisionPtr = nil
+		if f.WorkBook.RevisionPtr != nil {
+			for _, ns := range []xml.Attr{NameSpaceSpreadSheetXR, NameSpaceSpreadSheetXR6, NameSpaceSpreadSheetXR10} {
+				f.addNameSpaces(f.getWorkbookPath(), ns)
+			}
+		}
 		output, _ := xml.Marshal(f.WorkBook)
+		output, nameSpaces := replaceRevisionBytes(output)
+		for _, ns := range nameSpaces {
+			f.addNameSpaces(f.getWorkbookPath(), ns)
+		}
 		f.saveFileList(f.getWorkbookPath(), replaceRelationshipsBytes(f.replaceNameSpaceBytes(f.getWorkbookPath(), output)))
 	}
 }
@@ -269,10 +1255,15 @@ func (f *File) setContentTypePartImageExtensions() error {
 	for _, file := range content.Defaults {
 		delete(imageTypes, file.Extension)
 	}
-	for extension, prefix := range imageTypes {
+	extensions := make([]string, 0, len(imageTypes))
+	for extension := range imageTypes {
+		extensions = append(extensions, extension)
+	}
+	sort.Strings(extensions)
+	for _, extension := range extensions {
 		content.Defaults = append(content.Defaults, xlsxDefault{
 			Extension:   extension,
-			ContentType: prefix + extension,
+			ContentType: imageTypes[extension] + extension,
 		})
 	}
 	return err
@@ -310,28 +1301,36 @@ func (f *File) addContentTypePart(index int, contentType string) error {
 		"drawings": f.setContentTypePartImageExtensions,
 	}
 	partNames := map[string]string{
-		"chart":         "/xl/charts/chart" + strconv.Itoa(index) + ".xml",
-		"chartsheet":    "/xl/chartsheets/sheet" + strconv.Itoa(index) + ".xml",
-		"comments":      "/xl/comments" + strconv.Itoa(index) + ".xml",
-		"drawings":      "/xl/drawings/drawing" + strconv.Itoa(index) + ".xml",
-		"table":         "/xl/tables/table" + strconv.Itoa(index) + ".xml",
-		"pivotTable":    "/xl/pivotTables/pivotTable" + strconv.Itoa(index) + ".xml",
-		"pivotCache":    "/xl/pivotCache/pivotCacheDefinition" + strconv.Itoa(index) + ".xml",
-		"sharedStrings": "/xl/sharedStrings.xml",
-		"slicer":        "/xl/slicers/slicer" + strconv.Itoa(index) + ".xml",
-		"slicerCache":   "/xl/slicerCaches/slicerCache" + strconv.Itoa(index) + ".xml",
+		"chart":              "/xl/charts/chart" + strconv.Itoa(index) + ".xml",
+		"chartEx":            "/xl/charts/chartEx" + strconv.Itoa(index) + ".xml",
+		"chartsheet":         "/xl/chartsheets/sheet" + strconv.Itoa(index) + ".xml",
+		"comments":           "/xl/comments" + strconv.Itoa(index) + ".xml",
+		"drawings":           "/xl/drawings/drawing" + strconv.Itoa(index) + ".xml",
+		"featurePropertyBag": "/" + defaultXMLPathFeaturePropertyBag,
+		"table":              "/xl/tables/table" + strconv.Itoa(index) + ".xml",
+		"pivotTable":         "/xl/pivotTables/pivotTable" + strconv.Itoa(index) + ".xml",
+		"pivotCache":         "/xl/pivotCache/pivotCacheDefinition" + strconv.Itoa(index) + ".xml",
+		"sharedStrings":      "/xl/sharedStrings.xml",
+		"slicer":             "/xl/slicers/slicer" + strconv.Itoa(index) + ".xml",
+		"slicerCache":        "/xl/slicerCaches/slicerCache" + strconv.Itoa(index) + ".xml",
+		"timeline":           "/xl/timelines/timeline" + strconv.Itoa(index) + ".xml",
+		"timelineCache":      "/xl/timelineCaches/timelineCache" + strconv.Itoa(index) + ".xml",
 	}
 	contentTypes := map[string]string{
-		"chart":         ContentTypeDrawingML,
-		"chartsheet":    ContentTypeSpreadSheetMLChartsheet,
-		"comments":      ContentTypeSpreadSheetMLComments,
-		"drawings":      ContentTypeDrawing,
-		"table":         ContentTypeSpreadSheetMLTable,
-		"pivotTable":    ContentTypeSpreadSheetMLPivotTable,
-		"pivotCache":    ContentTypeSpreadSheetMLPivotCacheDefinition,
-		"sharedStrings": ContentTypeSpreadSheetMLSharedStrings,
-		"slicer":        ContentTypeSlicer,
-		"slicerCache":   ContentTypeSlicerCache,
+		"chart":              ContentTypeDrawingML,
+		"chartEx":            ContentTypeChartEx,
+		"chartsheet":         ContentTypeSpreadSheetMLChartsheet,
+		"comments":           ContentTypeSpreadSheetMLComments,
+		"drawings":           ContentTypeDrawing,
+		"featurePropertyBag": ContentTypeFeaturePropertyBag,
+		"table":              ContentTypeSpreadSheetMLTable,
+		"pivotTable":         ContentTypeSpreadSheetMLPivotTable,
+		"pivotCache":         ContentTypeSpreadSheetMLPivotCacheDefinition,
+		"sharedStrings":      ContentTypeSpreadSheetMLSharedStrings,
+		"slicer":             ContentTypeSlicer,
+		"slicerCache":        ContentTypeSlicerCache,
+		"timeline":           ContentTypeTimeline,
+		"timelineCache":      ContentTypeTimelineCache,
 	}
 	s, ok := setContentType[contentType]
 	if ok {
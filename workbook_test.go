@@ -1,6 +1,10 @@
 package excelize_ch
 
 import (
+	"encoding/xml"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -32,6 +36,63 @@ func TestWorkbookProps(t *testing.T) {
 	assert.EqualError(t, err, "XML syntax error on line 1: invalid UTF-8")
 }
 
+func TestCalcProps(t *testing.T) {
+	f := NewFile()
+	opts, err := f.GetCalcProps()
+	assert.NoError(t, err)
+	assert.Equal(t, CalcPropsOptions{
+		CalcMode: stringPtr("auto"), FullCalcOnLoad: boolPtr(false), Iterate: boolPtr(false),
+		IterateCount: uintPtr(100), IterateDelta: float64Ptr(0.001),
+	}, opts)
+	assert.NoError(t, f.SetCalcProps(nil))
+	expected := CalcPropsOptions{
+		CalcMode: stringPtr("manual"), FullCalcOnLoad: boolPtr(true), Iterate: boolPtr(true),
+		IterateCount: uintPtr(10), IterateDelta: float64Ptr(0.01),
+	}
+	assert.NoError(t, f.SetCalcProps(&expected))
+	opts, err = f.GetCalcProps()
+	assert.NoError(t, err)
+	assert.Equal(t, expected, opts)
+	// Test set calculation properties with invalid options
+	assert.Equal(t, ErrParameterInvalid, f.SetCalcProps(&CalcPropsOptions{CalcMode: stringPtr("none")}))
+	assert.Equal(t, ErrParameterInvalid, f.SetCalcProps(&CalcPropsOptions{IterateDelta: float64Ptr(-1)}))
+	// Test set calculation properties with unsupported charset workbook
+	f.WorkBook = nil
+	f.Pkg.Store(defaultXMLPathWorkbook, MacintoshCyrillicCharset)
+	assert.EqualError(t, f.SetCalcProps(&expected), "XML syntax error on line 1: invalid UTF-8")
+	// Test get calculation properties with unsupported charset workbook
+	f.WorkBook = nil
+	f.Pkg.Store(defaultXMLPathWorkbook, MacintoshCyrillicCharset)
+	_, err = f.GetCalcProps()
+	assert.EqualError(t, err, "XML syntax error on line 1: invalid UTF-8")
+}
+
+func TestWorkbookCustomProps(t *testing.T) {
+	f := NewFile()
+	assert.NoError(t, f.SetWorkbookCustomProps(map[string]string{"Model": "Budget"}))
+	props, err := f.GetWorkbookCustomProps()
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"Model": "Budget"}, props)
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestWorkbookCustomProps.xlsx")))
+	assert.NoError(t, f.Close())
+
+	f, err = OpenFile(filepath.Join("test", "TestWorkbookCustomProps.xlsx"))
+	assert.NoError(t, err)
+	props, err = f.GetWorkbookCustomProps()
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"Model": "Budget"}, props)
+	// Test set workbook custom properties with unsupported charset workbook
+	f.WorkBook = nil
+	f.Pkg.Store(defaultXMLPathWorkbook, MacintoshCyrillicCharset)
+	assert.EqualError(t, f.SetWorkbookCustomProps(nil), "XML syntax error on line 1: invalid UTF-8")
+	// Test get workbook custom properties with unsupported charset workbook
+	f.WorkBook = nil
+	f.Pkg.Store(defaultXMLPathWorkbook, MacintoshCyrillicCharset)
+	_, err = f.GetWorkbookCustomProps()
+	assert.EqualError(t, err, "XML syntax error on line 1: invalid UTF-8")
+	assert.NoError(t, f.Close())
+}
+
 func TestDeleteWorkbookRels(t *testing.T) {
 	f := NewFile()
 	// Test delete pivot table without worksheet relationships
@@ -41,3 +102,363 @@ func TestDeleteWorkbookRels(t *testing.T) {
 	assert.Empty(t, rID)
 	assert.NoError(t, err)
 }
+
+func TestSetCustomUI(t *testing.T) {
+	f := NewFile()
+	file, err := os.ReadFile(filepath.Join("test", "images", "excel.png"))
+	assert.NoError(t, err)
+	content := `<customUI xmlns="http://schemas.microsoft.com/office/2009/07/customui"><ribbon><tabs><tab id="customTab" label="Custom"><group id="customGroup" label="Group"><button id="customButton" label="Run" image="icon" size="large" onAction="Run"/></group></tab></tabs></ribbon></customUI>`
+	assert.NoError(t, f.SetCustomUI(&CustomUIOptions{
+		Content: content,
+		Images:  []CustomUIImage{{ID: "icon", Extension: ".PNG", File: file}, {ID: "unused", Extension: ".jpg", File: file}},
+	}))
+	customUI, err := f.GetCustomUI()
+	assert.NoError(t, err)
+	assert.Equal(t, []CustomUIOptions{{
+		Content: content,
+		Images:  []CustomUIImage{{ID: "icon", Extension: ".png", File: file}, {ID: "unused", Extension: ".jpeg", File: file}},
+	}}, customUI)
+	// Test replace the custom UI definition and add the Office 2007 version
+	assert.NoError(t, f.SetCustomUI(&CustomUIOptions{Content: `<customUI xmlns="http://schemas.microsoft.com/office/2009/07/customui"/>`}))
+	assert.NoError(t, f.SetCustomUI(&CustomUIOptions{Content: `<customUI xmlns="http://schemas.microsoft.com/office/2006/01/customui"/>`}))
+	_, ok := f.Pkg.Load("customUI/images/icon.png")
+	assert.False(t, ok)
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestSetCustomUI.xlsm")))
+	assert.NoError(t, f.Close())
+	f, err = OpenFile(filepath.Join("test", "TestSetCustomUI.xlsm"))
+	assert.NoError(t, err)
+	customUI, err = f.GetCustomUI()
+	assert.NoError(t, err)
+	assert.Equal(t, []CustomUIOptions{
+		{Content: `<customUI xmlns="http://schemas.microsoft.com/office/2009/07/customui"/>`},
+		{Content: `<customUI xmlns="http://schemas.microsoft.com/office/2006/01/customui"/>`},
+	}, customUI)
+	assert.NoError(t, f.Close())
+	// Test set custom UI with invalid definitions
+	f = NewFile()
+	assert.Equal(t, ErrParameterInvalid, f.SetCustomUI(nil))
+	for _, opts := range []struct {
+		options *CustomUIOptions
+		err     string
+	}{
+		{options: &CustomUIOptions{}, err: ErrCustomUI.Error()},
+		{options: &CustomUIOptions{Content: `<ribbon/>`}, err: ErrCustomUI.Error()},
+		{options: &CustomUIOptions{Content: `<customUI xmlns="http://schemas.microsoft.com/office/2009/07/customui">`}, err: "XML syntax error on line 1: unexpected EOF"},
+		{options: &CustomUIOptions{Content: `<customUI xmlns="http://schemas.microsoft.com/office/2009/07/customui"><ribbon><tabs><tab id="tab"/><tab id="tab"/></tabs></ribbon></customUI>`}, err: "duplicate custom UI control id tab"},
+		{options: &CustomUIOptions{Content: `<customUI xmlns="http://schemas.microsoft.com/office/2009/07/customui"><ribbon><tabs><tab id="tab"><group id="group"><button id="button" image="icon"/></group></tab></tabs></ribbon></customUI>`}, err: "custom UI image icon does not exist"},
+		{options: &CustomUIOptions{Content: content, Images: []CustomUIImage{{ID: "icon", Extension: ".txt"}}}, err: ErrImgExt.Error()},
+		{options: &CustomUIOptions{Content: content, Images: []CustomUIImage{{ID: "", Extension: ".png"}}}, err: ErrParameterInvalid.Error()},
+		{options: &CustomUIOptions{Content: content, Images: []CustomUIImage{{ID: "icon", Extension: ".png"}, {ID: "icon", Extension: ".png"}}}, err: ErrParameterInvalid.Error()},
+	} {
+		assert.EqualError(t, f.SetCustomUI(opts.options), opts.err)
+	}
+	// Test set custom UI with unsupported charset package relationships
+	f.Relationships.Delete("_rels/.rels")
+	f.Pkg.Store("_rels/.rels", MacintoshCyrillicCharset)
+	assert.EqualError(t, f.SetCustomUI(&CustomUIOptions{Content: content, Images: []CustomUIImage{{ID: "icon", Extension: ".png"}}}), "XML syntax error on line 1: invalid UTF-8")
+	_, err = f.GetCustomUI()
+	assert.EqualError(t, err, "XML syntax error on line 1: invalid UTF-8")
+	// Test set custom UI with unsupported charset custom UI relationships
+	f = NewFile()
+	assert.NoError(t, f.SetCustomUI(&CustomUIOptions{Content: content, Images: []CustomUIImage{{ID: "icon", Extension: ".png"}}}))
+	f.Relationships.Delete("customUI/_rels/customUI14.xml.rels")
+	f.Pkg.Store("customUI/_rels/customUI14.xml.rels", MacintoshCyrillicCharset)
+	_, err = f.GetCustomUI()
+	assert.EqualError(t, err, "XML syntax error on line 1: invalid UTF-8")
+	assert.EqualError(t, f.SetCustomUI(&CustomUIOptions{Content: content, Images: []CustomUIImage{{ID: "icon", Extension: ".png"}}}), "XML syntax error on line 1: invalid UTF-8")
+	// Test set custom UI with unsupported charset content types
+	f = NewFile()
+	f.ContentTypes = nil
+	f.Pkg.Store(defaultXMLPathContentTypes, MacintoshCyrillicCharset)
+	assert.EqualError(t, f.SetCustomUI(&CustomUIOptions{Content: content, Images: []CustomUIImage{{ID: "icon", Extension: ".png"}}}), "XML syntax error on line 1: invalid UTF-8")
+}
+
+func TestExtractSheets(t *testing.T) {
+	f := NewFile()
+	for _, sheet := range []string{"Sheet2", "Sheet 3"} {
+		_, err := f.NewSheet(sheet)
+		assert.NoError(t, err)
+	}
+	boldStyle, err := f.NewStyle(&Style{Font: &Font{Bold: true}})
+	assert.NoError(t, err)
+	italicStyle, err := f.NewStyle(&Style{Font: &Font{Italic: true}, Fill: Fill{Type: "pattern", Color: []string{"E0EBF5"}, Pattern: 1}})
+	assert.NoError(t, err)
+	fillStyle, err := f.NewStyle(&Style{Fill: Fill{Type: "pattern", Color: []string{"FF0000"}, Pattern: 1}})
+	assert.NoError(t, err)
+	assert.NoError(t, f.SetCellValue("Sheet1", "A1", "Sheet1 text"))
+	assert.NoError(t, f.SetCellStyle("Sheet1", "A1", "A1", boldStyle))
+	assert.NoError(t, f.SetCellFormula("Sheet1", "B1", "Sheet2!A1&\"!\""))
+	assert.NoError(t, f.SetCellFormula("Sheet1", "C1", "'Sheet 3'!A1"))
+	ws, err := f.workSheetReader("Sheet1")
+	assert.NoError(t, err)
+	ws.SheetData.Row[0].C[1].V, ws.SheetData.Row[0].C[1].T = "Sheet2 text!", "str"
+	assert.NoError(t, f.SetCellValue("Sheet2", "A1", "Sheet2 text"))
+	assert.NoError(t, f.SetCellStyle("Sheet2", "A1", "A1", italicStyle))
+	assert.NoError(t, f.AddPicture("Sheet2", "B2", filepath.Join("test", "images", "excel.png"), nil))
+	assert.NoError(t, f.SetCellValue("Sheet 3", "A1", "Sheet3 text"))
+	assert.NoError(t, f.SetColStyle("Sheet 3", "B", fillStyle))
+	assert.NoError(t, f.AddPicture("Sheet 3", "B2", filepath.Join("test", "images", "excel.jpg"), nil))
+	for _, dn := range []*DefinedName{
+		{Name: "Name1", RefersTo: "Sheet1!$A$1"},
+		{Name: "Name2", RefersTo: "Sheet2!$A$1"},
+		{Name: "Name3", RefersTo: "SUM(Sheet1:Sheet2!$A$1)"},
+	} {
+		assert.NoError(t, f.SetDefinedName(dn))
+	}
+
+	extract, err := f.ExtractSheets("Sheet1", "sheet 3")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Sheet1", "Sheet 3"}, extract.GetSheetList())
+	assert.Equal(t, []DefinedName{{Name: "Name1", RefersTo: "Sheet1!$A$1", Scope: "Workbook"}}, extract.GetDefinedName())
+	for cell, expected := range map[string][]string{"A1": {"Sheet1 text", ""}, "B1": {"Sheet2 text!", ""}, "C1": {"", "'Sheet 3'!A1"}} {
+		value, err := extract.GetCellValue("Sheet1", cell)
+		assert.NoError(t, err)
+		assert.Equal(t, expected[0], value)
+		formula, err := extract.GetCellFormula("Sheet1", cell)
+		assert.NoError(t, err)
+		assert.Equal(t, expected[1], formula)
+	}
+	value, err := extract.GetCellValue("Sheet 3", "A1")
+	assert.NoError(t, err)
+	assert.Equal(t, "Sheet3 text", value)
+	sst, err := extract.sharedStringsReader()
+	assert.NoError(t, err)
+	assert.Len(t, sst.SI, 2)
+	// Test the unused styles, fonts and fills are removed
+	styles, err := extract.stylesReader()
+	assert.NoError(t, err)
+	assert.Len(t, styles.CellXfs.Xf, 3)
+	assert.Len(t, styles.Fonts.Font, 2)
+	assert.Len(t, styles.Fills.Fill, 3)
+	style, err := extract.GetCellStyle("Sheet1", "A1")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, style)
+	s, err := extract.GetStyle(style)
+	assert.NoError(t, err)
+	assert.True(t, s.Font.Bold)
+	style, err = extract.GetColStyle("Sheet 3", "B")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, style)
+	s, err = extract.GetStyle(style)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"FF0000"}, s.Fill.Color)
+	// Test the parts only used by the removed sheet are removed
+	for partName, expected := range map[string]bool{
+		"xl/worksheets/sheet2.xml": false, "xl/drawings/drawing1.xml": false, "xl/media/image1.png": false,
+		"xl/worksheets/sheet3.xml": true, "xl/drawings/drawing2.xml": true, "xl/media/image2.jpeg": true,
+	} {
+		_, ok := extract.Pkg.Load(partName)
+		assert.Equal(t, expected, ok, partName)
+	}
+	contentType, err := extract.GetPartContentType("xl/worksheets/sheet3.xml")
+	assert.NoError(t, err)
+	assert.Equal(t, ContentTypeSpreadSheetMLWorksheet, contentType)
+	assert.NoError(t, extract.SaveAs(filepath.Join("test", "TestExtractSheets.xlsx")))
+	assert.NoError(t, extract.Close())
+	// Test the source workbook is not modified
+	assert.Equal(t, []string{"Sheet1", "Sheet2", "Sheet 3"}, f.GetSheetList())
+	formula, err := f.GetCellFormula("Sheet1", "B1")
+	assert.NoError(t, err)
+	assert.Equal(t, "Sheet2!A1&\"!\"", formula)
+
+	// Test extract sheets with invalid sheet names
+	_, err = f.ExtractSheets()
+	assert.Equal(t, ErrParameterRequired, err)
+	_, err = f.ExtractSheets("SheetN")
+	assert.EqualError(t, err, "sheet SheetN does not exist")
+	_, err = f.ExtractSheets("Sheet:1")
+	assert.Equal(t, ErrSheetNameInvalid, err)
+	// Test extract sheets with unsupported charset shared strings table
+	f.SharedStrings = nil
+	f.Pkg.Store(defaultXMLPathSharedStrings, MacintoshCyrillicCharset)
+	_, err = f.ExtractSheets("Sheet1")
+	assert.EqualError(t, err, "XML syntax error on line 1: invalid UTF-8")
+}
+
+func TestAppendWorkbook(t *testing.T) {
+	src := NewFile()
+	_, err := src.NewSheet("Data")
+	assert.NoError(t, err)
+	boldStyle, err := src.NewStyle(&Style{Font: &Font{Bold: true}})
+	assert.NoError(t, err)
+	dxfStyle, err := src.NewConditionalStyle(&Style{Font: &Font{Color: "9A0511"}})
+	assert.NoError(t, err)
+	assert.NoError(t, src.SetCellValue("Sheet1", "A1", "Source"))
+	assert.NoError(t, src.SetCellStyle("Sheet1", "A1", "A1", boldStyle))
+	assert.NoError(t, src.SetSheetRow("Sheet1", "B1", &[]interface{}{1, 2}))
+	assert.NoError(t, src.SetCellRichText("Sheet1", "C1", []RichTextRun{{Text: "Rich", Font: &Font{Bold: true}}}))
+	assert.NoError(t, src.SetCellFormula("Data", "A1", "SUM(Sheet1!B1:C1)"))
+	assert.NoError(t, src.SetCellHyperLink("Data", "A2", "Sheet1!A1", "Location"))
+	dv := NewDataValidation(true)
+	dv.Sqref = "A3"
+	dv.SetSqrefDropList("Sheet1!$A$1:$C$1")
+	assert.NoError(t, src.AddDataValidation("Data", dv))
+	assert.NoError(t, src.SetConditionalFormat("Data", "A1", []ConditionalFormatOptions{
+		{Type: "formula", Criteria: "Sheet1!$B$1>0", Format: dxfStyle},
+	}))
+	assert.NoError(t, src.AddTable("Sheet1", &Table{Range: "D1:E3", Name: "Table1"}))
+	assert.NoError(t, src.AddChart("Data", "C1", &Chart{
+		Type:   Col,
+		Series: []ChartSeries{{Name: "Sheet1!$A$1", Values: "Sheet1!$B$1:$C$1"}},
+	}))
+	assert.NoError(t, src.AddPicture("Sheet1", "F1", filepath.Join("test", "images", "excel.png"), nil))
+	for _, dn := range []*DefinedName{
+		{Name: "Total", RefersTo: "Sheet1!$B$1"},
+		{Name: "Local", RefersTo: "Data!$A$1", Scope: "Data"},
+	} {
+		assert.NoError(t, src.SetDefinedName(dn))
+	}
+
+	f := NewFile()
+	assert.NoError(t, f.SetCellValue("Sheet1", "A1", "Target"))
+	assert.NoError(t, f.AddTable("Sheet1", &Table{Range: "B1:C3", Name: "Table1"}))
+	assert.NoError(t, f.AddPicture("Sheet1", "D1", filepath.Join("test", "images", "excel.jpg"), nil))
+	assert.NoError(t, f.SetDefinedName(&DefinedName{Name: "Total", RefersTo: "Sheet1!$A$1"}))
+	assert.NoError(t, f.AppendWorkbook(src, "", CollisionPolicyRename))
+	assert.Equal(t, []string{"Sheet1", "Sheet1 (2)", "Data"}, f.GetSheetList())
+	// Test the parts of the source workbook are not written into the package
+	_, ok := src.Sheet.Load("xl/worksheets/sheet2.xml")
+	assert.True(t, ok)
+	_, ok = src.Pkg.Load("xl/worksheets/sheet2.xml")
+	assert.False(t, ok)
+	for cell, expected := range map[string]string{"A1": "Source", "B1": "1", "C1": "Rich"} {
+		value, err := f.GetCellValue("Sheet1 (2)", cell)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, value)
+	}
+	runs, err := f.GetCellRichText("Sheet1 (2)", "C1")
+	assert.NoError(t, err)
+	assert.True(t, runs[0].Font.Bold)
+	styleID, err := f.GetCellStyle("Sheet1 (2)", "A1")
+	assert.NoError(t, err)
+	style, err := f.GetStyle(styleID)
+	assert.NoError(t, err)
+	assert.True(t, style.Font.Bold)
+	// Test the sheet references are updated
+	formula, err := f.GetCellFormula("Data", "A1")
+	assert.NoError(t, err)
+	assert.Equal(t, "SUM('Sheet1 (2)'!B1:C1)", formula)
+	_, link, err := f.GetCellHyperLink("Data", "A2")
+	assert.NoError(t, err)
+	assert.Equal(t, "'Sheet1 (2)'!A1", link)
+	dvs, err := f.GetDataValidations("Data")
+	assert.NoError(t, err)
+	assert.Equal(t, "'Sheet1 (2)'!$A$1:$C$1", dvs[0].Formula1)
+	cfs, err := f.GetConditionalFormats("Data")
+	assert.NoError(t, err)
+	assert.Equal(t, "'Sheet1 (2)'!$B$1>0", cfs["A1"][0].Criteria)
+	style, err = f.GetConditionalStyle(cfs["A1"][0].Format)
+	assert.NoError(t, err)
+	assert.Equal(t, "9A0511", style.Font.Color)
+	content, err := f.GetPartContent("xl/charts/chart1.xml")
+	assert.NoError(t, err)
+	assert.Contains(t, string(content), "<f>&#39;Sheet1 (2)&#39;!$B$1:$C$1</f>")
+	assert.Equal(t, []DefinedName{
+		{Name: "Total", RefersTo: "Sheet1!$A$1", Scope: "Workbook"},
+		{Name: "Local", RefersTo: "Data!$A$1", Scope: "Data"},
+	}, f.GetDefinedName())
+	// Test the tables and images are appended without conflicts
+	tables, err := f.GetTables("Sheet1 (2)")
+	assert.NoError(t, err)
+	assert.Equal(t, "Table1_2", tables[0].Name)
+	for _, partName := range []string{"xl/tables/table2.xml", "xl/drawings/drawing3.xml", "xl/media/image1.png"} {
+		_, ok := f.Pkg.Load(partName)
+		assert.True(t, ok, partName)
+	}
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestAppendWorkbook.xlsx")))
+	assert.NoError(t, f.Close())
+
+	// Test append workbook with prefix and replace policy
+	f = NewFile()
+	_, err = f.NewSheet("Book2 Data")
+	assert.NoError(t, err)
+	assert.NoError(t, f.SetCellValue("Book2 Data", "A1", "Replaced"))
+	assert.NoError(t, f.AppendWorkbook(src, "Book2 ", CollisionPolicyReplace))
+	assert.Equal(t, []string{"Sheet1", "Book2 Sheet1", "Book2 Data"}, f.GetSheetList())
+	formula, err = f.GetCellFormula("Book2 Data", "A1")
+	assert.NoError(t, err)
+	assert.Equal(t, "SUM('Book2 Sheet1'!B1:C1)", formula)
+	assert.Equal(t, []DefinedName{
+		{Name: "Total", RefersTo: "'Book2 Sheet1'!$B$1", Scope: "Workbook"},
+		{Name: "Local", RefersTo: "'Book2 Data'!$A$1", Scope: "Book2 Data"},
+	}, f.GetDefinedName())
+	assert.NoError(t, f.Close())
+
+	// Test append workbook with skip policy
+	f = NewFile()
+	assert.NoError(t, f.SetCellValue("Sheet1", "B1", 10))
+	assert.NoError(t, f.AppendWorkbook(src, "", CollisionPolicySkip))
+	assert.Equal(t, []string{"Sheet1", "Data"}, f.GetSheetList())
+	value, err := f.GetCellValue("Sheet1", "B1")
+	assert.NoError(t, err)
+	assert.Equal(t, "10", value)
+	formula, err = f.GetCellFormula("Data", "A1")
+	assert.NoError(t, err)
+	assert.Equal(t, "SUM(Sheet1!B1:C1)", formula)
+
+	// Test append workbook with invalid parameters
+	assert.Equal(t, ErrParameterInvalid, f.AppendWorkbook(nil, "", CollisionPolicyRename))
+	assert.Equal(t, ErrParameterInvalid, f.AppendWorkbook(f, "", CollisionPolicyRename))
+	assert.Equal(t, ErrParameterInvalid, f.AppendWorkbook(src, "", CollisionPolicy(3)))
+	assert.Equal(t, ErrSheetNameInvalid, f.AppendWorkbook(src, "Book:", CollisionPolicyRename))
+	// Test append workbook with unsupported charset shared strings table
+	src.SharedStrings = nil
+	src.Pkg.Store(defaultXMLPathSharedStrings, MacintoshCyrillicCharset)
+	assert.EqualError(t, f.AppendWorkbook(src, "", CollisionPolicyRename), "XML syntax error on line 1: invalid UTF-8")
+	assert.NoError(t, src.Close())
+	assert.NoError(t, f.Close())
+}
+
+func TestPreserveRevisionIdentifiers(t *testing.T) {
+	f := NewFile()
+	assert.NoError(t, f.SetSheetRow("Sheet1", "A1", &[]string{"A", "B"}))
+	assert.NoError(t, f.AddTable("Sheet1", &Table{Range: "A1:B3"}))
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestPreserveRevisionIdentifiers.xlsx")))
+	assert.NoError(t, f.Close())
+
+	f, err := OpenFile(filepath.Join("test", "TestPreserveRevisionIdentifiers.xlsx"))
+	assert.NoError(t, err)
+	workbook := strings.NewReplacer(
+		`<bookViews><workbookView`, `<xr:revisionPtr revIDLastSave="3" documentId="8_{B1D5B6C6}" xr6:coauthVersionLast="47" xr6:coauthVersionMax="47" xr10:uidLastSave="{00000000-0000-0000-0000-000000000000}"/><bookViews><workbookView xr2:uid="{A9A0D8E4}"`,
+		`<workbook `, `<workbook xmlns:xr="http://schemas.microsoft.com/office/spreadsheetml/2014/revision" xmlns:xr2="http://schemas.microsoft.com/office/spreadsheetml/2015/revision2" xmlns:xr6="http://schemas.microsoft.com/office/spreadsheetml/2016/revision6" xmlns:xr10="http://schemas.microsoft.com/office/spreadsheetml/2016/revision10" `,
+	).Replace(string(f.readXML(defaultXMLPathWorkbook)))
+	f.Pkg.Store(defaultXMLPathWorkbook, []byte(workbook))
+	f.WorkBook = nil
+	f.xmlAttr.Delete(defaultXMLPathWorkbook)
+	f.Pkg.Store("xl/tables/table1.xml", []byte(strings.NewReplacer(
+		`<table `, `<table xmlns:xr="http://schemas.microsoft.com/office/spreadsheetml/2014/revision" xmlns:xr3="http://schemas.microsoft.com/office/spreadsheetml/2016/revision3" xr:uid="{9C5C5F23}" `,
+		`<autoFilter ref="A1:B3"`, `<autoFilter ref="A1:B3" xr:uid="{F4D3C281}"`,
+		`<tableColumn id="1"`, `<tableColumn id="1" xr3:uid="{1A2B3C4D}"`,
+	).Replace(string(f.readXML("xl/tables/table1.xml")))))
+	assert.NoError(t, f.AddDataValidation("Sheet1", &DataValidation{Sqref: "D1", Type: "whole"}))
+	ws, ok := f.Sheet.Load("xl/worksheets/sheet1.xml")
+	assert.True(t, ok)
+	ws.(*xlsxWorksheet).DataValidations.DataValidation[0].UID = "{5E6F7A8B}"
+	assert.NoError(t, f.InsertRows("Sheet1", 3, 1))
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestPreserveRevisionIdentifiers.xlsx")))
+	assert.NoError(t, f.Close())
+
+	f, err = OpenFile(filepath.Join("test", "TestPreserveRevisionIdentifiers.xlsx"))
+	assert.NoError(t, err)
+	wb, err := f.workbookReader()
+	assert.NoError(t, err)
+	assert.Equal(t, &decodeRevisionPtr{
+		RevIDLastSave: "3", DocumentID: "8_{B1D5B6C6}", CoauthVersionLast: "47",
+		CoauthVersionMax: "47", UIDLastSave: "{00000000-0000-0000-0000-000000000000}",
+	}, wb.DecodeRevisionPtr)
+	assert.Equal(t, "{A9A0D8E4}", wb.BookViews.WorkBookView[0].UID)
+	content := string(f.readXML(defaultXMLPathWorkbook))
+	assert.Contains(t, content, `<xr:revisionPtr revIDLastSave="3"`)
+	assert.Contains(t, content, ` xr2:uid="{A9A0D8E4}"`)
+	var tbl xlsxTable
+	assert.NoError(t, xml.Unmarshal(f.readXML("xl/tables/table1.xml"), &tbl))
+	assert.Equal(t, "A1:B4", tbl.Ref)
+	assert.Equal(t, "{9C5C5F23}", tbl.UID)
+	assert.Equal(t, "{F4D3C281}", tbl.AutoFilter.UID)
+	assert.Equal(t, "{1A2B3C4D}", tbl.TableColumns.TableColumn[0].UID)
+	ws2, err := f.workSheetReader("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, "{5E6F7A8B}", ws2.DataValidations.DataValidation[0].UID)
+	assert.Contains(t, string(f.readXML("xl/worksheets/sheet1.xml")), `xmlns:xr="http://schemas.microsoft.com/office/spreadsheetml/2014/revision"`)
+	assert.NoError(t, f.Close())
+}
@@ -15,6 +15,7 @@ import "encoding/xml"
 
 // AppProperties directly maps the document application properties.
 type AppProperties struct {
+	Template          string
 	Application       string
 	ScaleCrop         bool
 	DocSecurity       int
@@ -69,6 +70,22 @@ type xlsxVectorLpstr struct {
 	Content string `xml:",innerxml"`
 }
 
+// xlsxVector specifies the vector of the variant types, which used to
+// generate the heading pairs and the titles of parts of the document.
+type xlsxVector struct {
+	XMLName  xml.Name      `xml:"vt:vector"`
+	Size     int           `xml:"size,attr"`
+	BaseType string        `xml:"baseType,attr"`
+	Variant  []xlsxVariant `xml:"vt:variant"`
+	Lpstr    []string      `xml:"vt:lpstr"`
+}
+
+// xlsxVariant specifies the variant type value in the vector.
+type xlsxVariant struct {
+	Lpstr string `xml:"vt:lpstr,omitempty"`
+	I4    int    `xml:"vt:i4,omitempty"`
+}
+
 // xlsxDigSig contains the signature of a digitally signed document.
 type xlsxDigSig struct {
 	Content string `xml:",innerxml"`
@@ -22,12 +22,20 @@ type xlsxChartSpace struct {
 	Date1904       *attrValBool    `xml:"date1904"`
 	Lang           *attrValString  `xml:"lang"`
 	RoundedCorners *attrValBool    `xml:"roundedCorners"`
+	PivotSource    *cPivotSource   `xml:"pivotSource"`
 	Chart          cChart          `xml:"chart"`
 	SpPr           *cSpPr          `xml:"spPr"`
 	TxPr           *cTxPr          `xml:"txPr"`
 	PrintSettings  *cPrintSettings `xml:"printSettings"`
 }
 
+// cPivotSource (Pivot Source) directly maps the pivotSource element. This
+// element specifies the source pivot table of a pivot chart.
+type cPivotSource struct {
+	Name  string      `xml:"name"`
+	FmtID *attrValInt `xml:"fmtId"`
+}
+
 // cThicknessSpPr directly maps the element that specifies the thickness of
 // the walls or floor as a percentage of the largest dimension of the plot
 // volume and SpPr element.
@@ -83,32 +91,43 @@ type aBodyPr struct {
 	Anchor           string  `xml:"anchor,attr,omitempty"`
 	AnchorCtr        bool    `xml:"anchorCtr,attr"`
 	Rot              int     `xml:"rot,attr"`
-	BIns             float64 `xml:"bIns,attr,omitempty"`
+	BIns             *int    `xml:"bIns,attr"`
 	CompatLnSpc      bool    `xml:"compatLnSpc,attr,omitempty"`
 	ForceAA          bool    `xml:"forceAA,attr,omitempty"`
 	FromWordArt      bool    `xml:"fromWordArt,attr,omitempty"`
 	HorzOverflow     string  `xml:"horzOverflow,attr,omitempty"`
-	LIns             float64 `xml:"lIns,attr,omitempty"`
+	LIns             *int    `xml:"lIns,attr"`
 	NumCol           int     `xml:"numCol,attr,omitempty"`
-	RIns             float64 `xml:"rIns,attr,omitempty"`
+	RIns             *int    `xml:"rIns,attr"`
 	RtlCol           bool    `xml:"rtlCol,attr,omitempty"`
 	SpcCol           int     `xml:"spcCol,attr,omitempty"`
 	SpcFirstLastPara bool    `xml:"spcFirstLastPara,attr"`
-	TIns             float64 `xml:"tIns,attr,omitempty"`
+	TIns             *int    `xml:"tIns,attr"`
 	Upright          bool    `xml:"upright,attr,omitempty"`
 	Vert             string  `xml:"vert,attr,omitempty"`
 	VertOverflow     string  `xml:"vertOverflow,attr,omitempty"`
 	Wrap             string  `xml:"wrap,attr,omitempty"`
+	NoAutofit        *string `xml:"a:noAutofit"`
+	NormAutofit      *string `xml:"a:normAutofit"`
+	SpAutoFit        *string `xml:"a:spAutoFit"`
 }
 
 // aP (Paragraph) directly maps the a:p element. This element specifies a
 // paragraph of content in the document.
 type aP struct {
 	PPr        *aPPr        `xml:"a:pPr"`
-	R          *aR          `xml:"a:r"`
+	Math       *aMath       `xml:"a14:m"`
+	R          []*aR        `xml:"a:r"`
 	EndParaRPr *aEndParaRPr `xml:"a:endParaRPr"`
 }
 
+// aMath directly maps the a14:m element. This element specifies the equation
+// in Office Math Markup Language (OMML) of the paragraph.
+type aMath struct {
+	XMLNSM  string `xml:"xmlns:m,attr,omitempty"`
+	Content string `xml:",innerxml"`
+}
+
 // aPPr (Paragraph Properties) directly maps the a:pPr element. This element
 // specifies a set of paragraph properties which shall be applied to the
 // contents of the parent paragraph after all style/numbering/table properties
@@ -116,7 +135,8 @@ type aP struct {
 // formatting, since they are directly applied to the paragraph and supersede
 // any formatting from styles.
 type aPPr struct {
-	DefRPr aRPr `xml:"a:defRPr"`
+	Algn   string `xml:"algn,attr,omitempty"`
+	DefRPr aRPr   `xml:"a:defRPr"`
 }
 
 // aSolidFill (Solid Fill) directly maps the solidFill element. This element
@@ -316,10 +336,19 @@ type cPlotArea struct {
 	ScatterChart   *cCharts `xml:"scatterChart"`
 	Surface3DChart *cCharts `xml:"surface3DChart"`
 	SurfaceChart   *cCharts `xml:"surfaceChart"`
-	CatAx          []*cAxs  `xml:"catAx"`
-	ValAx          []*cAxs  `xml:"valAx"`
-	SerAx          []*cAxs  `xml:"serAx"`
-	SpPr           *cSpPr   `xml:"spPr"`
+	ComboCharts    []*cComboChart
+	CatAx          []*cAxs `xml:"catAx"`
+	ValAx          []*cAxs `xml:"valAx"`
+	SerAx          []*cAxs `xml:"serAx"`
+	SpPr           *cSpPr  `xml:"spPr"`
+}
+
+// cComboChart specifies the additional chart element in the plot area of the
+// combo chart, which has the same type as another chart element in the plot
+// area, the element name is given by the XMLName field.
+type cComboChart struct {
+	XMLName xml.Name
+	cCharts
 }
 
 // cCharts specifies the common element of the chart.
@@ -410,6 +439,7 @@ type cSer struct {
 	Smooth           *attrValBool `xml:"smooth"`
 	BubbleSize       *cVal        `xml:"bubbleSize"`
 	Bubble3D         *attrValBool `xml:"bubble3D"`
+	ExtLst           *xlsxExtLst  `xml:"extLst"`
 }
 
 // cMarker (Marker) directly maps the marker element. This element specifies a
@@ -432,6 +462,7 @@ type cDPt struct {
 // specifies the data used for the category axis.
 type cCat struct {
 	StrRef *cStrRef `xml:"strRef"`
+	StrLit *cStrLit `xml:"strLit"`
 }
 
 // cStrRef (String Reference) directly maps the strRef element. This element
@@ -445,8 +476,8 @@ type cStrRef struct {
 // cStrCache (String Cache) directly maps the strCache element. This element
 // specifies the last string data used for a chart.
 type cStrCache struct {
-	Pt      []*cPt      `xml:"pt"`
 	PtCount *attrValInt `xml:"ptCount"`
+	Pt      []*cPt      `xml:"pt"`
 }
 
 // cPt directly maps the pt element. This element specifies data for a
@@ -460,6 +491,7 @@ type cPt struct {
 // which shall be used to define the location of data markers on a chart.
 type cVal struct {
 	NumRef *cNumRef `xml:"numRef"`
+	NumLit *cNumLit `xml:"numLit"`
 }
 
 // cNumRef directly maps the numRef element. This element specifies a
@@ -477,19 +509,72 @@ type cNumCache struct {
 	PtCount    *attrValInt `xml:"ptCount"`
 }
 
+// cStrLit (String Literal) directly maps the strLit element. This element
+// specifies a set of string values used for a chart without the reference to
+// the worksheet data.
+type cStrLit struct {
+	PtCount *attrValInt `xml:"ptCount"`
+	Pt      []*cPt      `xml:"pt"`
+}
+
+// cNumLit (Numeric Literal) directly maps the numLit element. This element
+// specifies a set of numbers used for a chart without the reference to the
+// worksheet data.
+type cNumLit struct {
+	FormatCode string      `xml:"formatCode"`
+	PtCount    *attrValInt `xml:"ptCount"`
+	Pt         []*cPt      `xml:"pt"`
+}
+
 // cDLbls (Data Labels) directly maps the dLbls element. This element serves
 // as a root element that specifies the settings for the data labels for an
 // entire series or the entire chart. It contains child elements that specify
 // the specific formatting and positioning settings.
 type cDLbls struct {
-	NumFmt          *cNumFmt     `xml:"numFmt"`
-	ShowLegendKey   *attrValBool `xml:"showLegendKey"`
-	ShowVal         *attrValBool `xml:"showVal"`
-	ShowCatName     *attrValBool `xml:"showCatName"`
-	ShowSerName     *attrValBool `xml:"showSerName"`
-	ShowPercent     *attrValBool `xml:"showPercent"`
-	ShowBubbleSize  *attrValBool `xml:"showBubbleSize"`
-	ShowLeaderLines *attrValBool `xml:"showLeaderLines"`
+	NumFmt          *cNumFmt       `xml:"numFmt"`
+	DLblPos         *attrValString `xml:"dLblPos"`
+	ShowLegendKey   *attrValBool   `xml:"showLegendKey"`
+	ShowVal         *attrValBool   `xml:"showVal"`
+	ShowCatName     *attrValBool   `xml:"showCatName"`
+	ShowSerName     *attrValBool   `xml:"showSerName"`
+	ShowPercent     *attrValBool   `xml:"showPercent"`
+	ShowBubbleSize  *attrValBool   `xml:"showBubbleSize"`
+	ShowLeaderLines *attrValBool   `xml:"showLeaderLines"`
+	ExtLst          *xlsxExtLst    `xml:"extLst"`
+}
+
+// cDLblsExt directly maps the ext element of the data labels, which specifies
+// the shape, the worksheet range text and the leader lines of the data labels
+// introduced in Office 2013.
+type cDLblsExt struct {
+	XMLName             xml.Name     `xml:"ext"`
+	URI                 string       `xml:"uri,attr"`
+	XMLNSC15            string       `xml:"xmlns:c15,attr"`
+	SpPr                *cDLblsSpPr  `xml:"c15:spPr"`
+	ShowDataLabelsRange *attrValBool `xml:"c15:showDataLabelsRange"`
+	ShowLeaderLines     *attrValBool `xml:"c15:showLeaderLines"`
+}
+
+// cDLblsSpPr directly maps the c15:spPr element. This element specifies the
+// shape of the data labels.
+type cDLblsSpPr struct {
+	PrstGeom xlsxPrstGeom `xml:"a:prstGeom"`
+}
+
+// cSerExt directly maps the ext element of the series, which specifies the
+// worksheet range of the data labels text introduced in Office 2013.
+type cSerExt struct {
+	XMLName         xml.Name          `xml:"ext"`
+	URI             string            `xml:"uri,attr"`
+	XMLNSC15        string            `xml:"xmlns:c15,attr"`
+	DataLabelsRange *cDataLabelsRange `xml:"c15:datalabelsRange"`
+}
+
+// cDataLabelsRange directly maps the c15:datalabelsRange element. This element
+// specifies the worksheet range and the cached text of the data labels.
+type cDataLabelsRange struct {
+	F              string   `xml:"c15:f"`
+	DlblRangeCache *cStrLit `xml:"c15:dlblRangeCache"`
 }
 
 // cLegend (Legend) directly maps the legend element. This element specifies
@@ -542,6 +627,7 @@ type ChartAxis struct {
 	LogBase        float64
 	NumFmt         ChartNumFmt
 	Title          []RichTextRun
+	TitleRef       string
 	axID           int
 }
 
@@ -571,6 +657,7 @@ type Chart struct {
 	Dimension    ChartDimension
 	Legend       ChartLegend
 	Title        []RichTextRun
+	TitleRef     string
 	VaryColors   *bool
 	XAxis        ChartAxis
 	YAxis        ChartAxis
@@ -579,6 +666,7 @@ type Chart struct {
 	ShowBlanksAs string
 	HoleSize     int
 	order        int
+	pivotSource  string
 }
 
 // ChartLegend directly maps the format settings of the chart legend.
@@ -602,11 +690,31 @@ type ChartLine struct {
 
 // ChartSeries directly maps the format settings of the chart series.
 type ChartSeries struct {
-	Name       string
-	Categories string
-	Sizes      string
-	Values     string
-	Fill       Fill
-	Line       ChartLine
-	Marker     ChartMarker
+	Name           string
+	Categories     string
+	CategoriesData []string
+	Sizes          string
+	SizesData      []float64
+	Values         string
+	ValuesData     []float64
+	Fill           Fill
+	Line           ChartLine
+	Marker         ChartMarker
+	DataLabel      ChartDataLabel
+	SecondaryAxis  bool
+}
+
+// ChartDataLabel directly maps the format settings of the chart series data
+// labels.
+type ChartDataLabel struct {
+	Range           string
+	NumFmt          ChartNumFmt
+	Position        ChartDataLabelPositionType
+	ShowVal         bool
+	ShowCatName     bool
+	ShowSerName     bool
+	ShowPercent     bool
+	ShowBubbleSize  bool
+	ShowLeaderLines bool
+	CalloutShape    string
 }
@@ -0,0 +1,256 @@
+// Copyright 2016 - 2023 The excelize Authors. All rights reserved. Use of
+// this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+//
+// package excelize_ch providing a set of functions that allow you to write to and
+// read from XLAM / XLSM / XLSX / XLTM / XLTX files. Supports reading and
+// writing spreadsheet documents generated by Microsoft Excel™ 2007 and later.
+// Supports complex components by high compatibility, and provided streaming
+// API for generating or reading data from a worksheet with huge amounts of
+// data. This library needs Go version 1.16 or later.
+
+package excelize_ch
+
+import "encoding/xml"
+
+// xlsxChartExSpace directly maps the cx:chartSpace element. The chartEx part
+// specifies the Excel 2016 chart types, such as waterfall, funnel, histogram,
+// box and whisker, treemap and sunburst charts, which can't be expressed in
+// the chartSpace of the DrawingML chart.
+type xlsxChartExSpace struct {
+	XMLName   xml.Name    `xml:"cx:chartSpace"`
+	XMLNSA    string      `xml:"xmlns:a,attr"`
+	XMLNSR    string      `xml:"xmlns:r,attr"`
+	XMLNSCx   string      `xml:"xmlns:cx,attr"`
+	ChartData cxChartData `xml:"cx:chartData"`
+	Chart     cxChart     `xml:"cx:chart"`
+}
+
+// cxChartData directly maps the cx:chartData element. This element specifies
+// the data sets of the chartEx chart.
+type cxChartData struct {
+	Data []*cxData `xml:"cx:data"`
+}
+
+// cxData directly maps the cx:data element. This element specifies a data
+// set which referenced by the series with the ID.
+type cxData struct {
+	ID     int      `xml:"id,attr"`
+	StrDim []*cxDim `xml:"cx:strDim"`
+	NumDim []*cxDim `xml:"cx:numDim"`
+}
+
+// cxDim directly maps the cx:strDim and cx:numDim element. This element
+// specifies the formula and the cached levels of a text or numeric dimension
+// of the data set.
+type cxDim struct {
+	Type string   `xml:"type,attr"`
+	F    string   `xml:"cx:f"`
+	Lvl  []*cxLvl `xml:"cx:lvl"`
+}
+
+// cxLvl directly maps the cx:lvl element. This element specifies a level of
+// the cached values of the dimension.
+type cxLvl struct {
+	PtCount    int     `xml:"ptCount,attr"`
+	FormatCode string  `xml:"formatCode,attr,omitempty"`
+	Pt         []*cxPt `xml:"cx:pt"`
+}
+
+// cxPt directly maps the cx:pt element. This element specifies a cached value
+// of the dimension level.
+type cxPt struct {
+	Idx int    `xml:"idx,attr"`
+	V   string `xml:",chardata"`
+}
+
+// cxChart directly maps the cx:chart element. This element specifies the
+// title, plot area and legend of the chartEx chart.
+type cxChart struct {
+	Title    *cxTitle   `xml:"cx:title"`
+	PlotArea cxPlotArea `xml:"cx:plotArea"`
+	Legend   *cxLegend  `xml:"cx:legend"`
+}
+
+// cxTitle directly maps the cx:title element. This element specifies the
+// title of the chartEx chart.
+type cxTitle struct {
+	Pos     string `xml:"pos,attr"`
+	Align   string `xml:"align,attr"`
+	Overlay bool   `xml:"overlay,attr"`
+	Tx      *cxTx  `xml:"cx:tx"`
+}
+
+// cxTx directly maps the cx:tx element. This element specifies the text of
+// the title or series.
+type cxTx struct {
+	TxData *cxTxData `xml:"cx:txData"`
+}
+
+// cxTxData directly maps the cx:txData element. This element specifies the
+// formula and the cached text.
+type cxTxData struct {
+	F string `xml:"cx:f,omitempty"`
+	V string `xml:"cx:v"`
+}
+
+// cxPlotArea directly maps the cx:plotArea element. This element specifies
+// the plot area region and the axes of the chartEx chart.
+type cxPlotArea struct {
+	PlotAreaRegion cxPlotAreaRegion `xml:"cx:plotAreaRegion"`
+	Axis           []*cxAxis        `xml:"cx:axis"`
+}
+
+// cxPlotAreaRegion directly maps the cx:plotAreaRegion element. This element
+// specifies the series of the chartEx chart.
+type cxPlotAreaRegion struct {
+	Series []*cxSeries `xml:"cx:series"`
+}
+
+// cxSeries directly maps the cx:series element. This element specifies a
+// series of the chartEx chart, the layout ID specifies the chart type of the
+// series.
+type cxSeries struct {
+	LayoutID   string        `xml:"layoutId,attr"`
+	Tx         *cxTx         `xml:"cx:tx"`
+	DataLabels *cxDataLabels `xml:"cx:dataLabels"`
+	DataID     attrValInt    `xml:"cx:dataId"`
+	LayoutPr   *cxLayoutPr   `xml:"cx:layoutPr"`
+}
+
+// cxDataLabels directly maps the cx:dataLabels element. This element
+// specifies the position and visibility of the data labels of the series.
+type cxDataLabels struct {
+	Pos        string        `xml:"pos,attr,omitempty"`
+	Visibility *cxVisibility `xml:"cx:visibility"`
+}
+
+// cxVisibility directly maps the cx:visibility element of the data labels.
+type cxVisibility struct {
+	SeriesName   bool `xml:"seriesName,attr"`
+	CategoryName bool `xml:"categoryName,attr"`
+	Value        bool `xml:"value,attr"`
+}
+
+// cxLayoutPr directly maps the cx:layoutPr element. This element specifies
+// the layout properties of the series, such as the label layout of the
+// treemap chart, the visibilities of the box and whisker chart elements, the
+// binning of the histogram chart, the statistics of the box and whisker chart
+// and the subtotals of the waterfall chart.
+type cxLayoutPr struct {
+	ParentLabelLayout *attrValString               `xml:"cx:parentLabelLayout"`
+	Visibility        *cxSeriesElementVisibilities `xml:"cx:visibility"`
+	Binning           *cxBinning                   `xml:"cx:binning"`
+	Statistics        *cxStatistics                `xml:"cx:statistics"`
+	Subtotals         *cxSubtotals                 `xml:"cx:subtotals"`
+}
+
+// cxSeriesElementVisibilities directly maps the cx:visibility element of the
+// series layout properties.
+type cxSeriesElementVisibilities struct {
+	MeanLine    bool `xml:"meanLine,attr"`
+	MeanMarker  bool `xml:"meanMarker,attr"`
+	Nonoutliers bool `xml:"nonoutliers,attr"`
+	Outliers    bool `xml:"outliers,attr"`
+}
+
+// cxBinning directly maps the cx:binning element. This element specifies the
+// binning of the histogram chart by the bin size or the number of bins, the
+// bins will be computed automatically if both are not specified.
+type cxBinning struct {
+	IntervalClosed string        `xml:"intervalClosed,attr"`
+	BinSize        *attrValFloat `xml:"cx:binSize"`
+	BinCount       *attrValInt   `xml:"cx:binCount"`
+}
+
+// cxStatistics directly maps the cx:statistics element. This element
+// specifies the quartile calculation method of the box and whisker chart.
+type cxStatistics struct {
+	QuartileMethod string `xml:"quartileMethod,attr"`
+}
+
+// cxSubtotals directly maps the cx:subtotals element. This element specifies
+// the indexes of the data points which will be displayed as the totals in
+// the waterfall chart.
+type cxSubtotals struct {
+	Idx []*attrValInt `xml:"cx:idx"`
+}
+
+// cxAxis directly maps the cx:axis element. This element specifies the
+// category or value axis of the chartEx chart.
+type cxAxis struct {
+	ID             int           `xml:"id,attr"`
+	CatScaling     *cxCatScaling `xml:"cx:catScaling"`
+	ValScaling     *cxValScaling `xml:"cx:valScaling"`
+	MajorGridlines *cxGridlines  `xml:"cx:majorGridlines"`
+	TickLabels     *cxTickLabels `xml:"cx:tickLabels"`
+}
+
+// cxCatScaling directly maps the cx:catScaling element. This element
+// specifies the gap width of the category axis.
+type cxCatScaling struct {
+	GapWidth string `xml:"gapWidth,attr,omitempty"`
+}
+
+// cxValScaling directly maps the cx:valScaling element. This element
+// specifies the scaling of the value axis.
+type cxValScaling struct{}
+
+// cxGridlines directly maps the cx:majorGridlines element. This element
+// specifies the major gridlines of the axis.
+type cxGridlines struct{}
+
+// cxTickLabels directly maps the cx:tickLabels element. This element
+// specifies the tick labels of the axis.
+type cxTickLabels struct{}
+
+// cxLegend directly maps the cx:legend element. This element specifies the
+// legend of the chartEx chart.
+type cxLegend struct {
+	Pos     string `xml:"pos,attr"`
+	Align   string `xml:"align,attr"`
+	Overlay bool   `xml:"overlay,attr"`
+}
+
+// xlsxChartEx directly maps the cx:chart element of the graphic data in the
+// drawing part, which references the chartEx part by the relationship ID.
+type xlsxChartEx struct {
+	XMLNSCx string `xml:"xmlns:cx,attr"`
+	XMLNSR  string `xml:"xmlns:r,attr"`
+	RID     string `xml:"r:id,attr"`
+}
+
+// ChartExType is the type of supported chartEx chart types.
+type ChartExType byte
+
+// This section defines the currently supported chartEx chart types
+// enumeration.
+const (
+	Waterfall ChartExType = iota
+	Funnel
+	Histogram
+	BoxWhisker
+	Treemap
+	Sunburst
+)
+
+// ChartEx directly maps the format settings of the chartEx chart.
+type ChartEx struct {
+	Type      ChartExType
+	Series    []ChartExSeries
+	Format    GraphicOptions
+	Dimension ChartDimension
+	Legend    ChartLegend
+	Title     []RichTextRun
+	Subtotals []int
+	BinCount  int
+	BinSize   float64
+}
+
+// ChartExSeries directly maps the format settings of the chartEx chart
+// series.
+type ChartExSeries struct {
+	Name       string
+	Categories string
+	Values     string
+}
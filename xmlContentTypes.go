@@ -39,3 +39,36 @@ type xlsxDefault struct {
 	Extension   string `xml:",attr"`
 	ContentType string `xml:",attr"`
 }
+
+// PackagePart directly maps the part of the workbook package, the name of the
+// part is the path in the package without leading slash.
+type PackagePart struct {
+	Name        string
+	ContentType string
+	Size        int64
+}
+
+// PackageRelationship directly maps the relationship of the part in the
+// workbook package. The Source is the name of the part which the relationship
+// belongs to, and is empty for the package level relationships. The
+// TargetPart is the name of the resolved target part for the internal
+// relationship, and is empty for the external relationship.
+type PackageRelationship struct {
+	Source     string
+	ID         string
+	Type       string
+	Target     string
+	TargetMode string
+	TargetPart string
+}
+
+// RawPartOptions directly maps the settings of the raw part. The ContentType
+// specifies the override content type of the part. The RelationshipSource
+// specifies the name of the part which the relationship to the raw part
+// belongs to, use empty string for the package level relationship. The
+// relationship will be created only if the RelationshipType is specified.
+type RawPartOptions struct {
+	ContentType        string
+	RelationshipSource string
+	RelationshipType   string
+}
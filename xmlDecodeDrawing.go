@@ -23,6 +23,7 @@ type decodeCellAnchor struct {
 	To               *decodeTo               `xml:"to"`
 	Sp               *decodeSp               `xml:"sp"`
 	Pic              *decodePic              `xml:"pic"`
+	GraphicFrame     *decodeGraphicFrame     `xml:"graphicFrame"`
 	ClientData       *decodeClientData       `xml:"clientData"`
 	AlternateContent []*xlsxAlternateContent `xml:"mc:AlternateContent"`
 	Content          string                  `xml:",innerxml"`
@@ -92,6 +93,26 @@ type decodeWsDr struct {
 	TwoCellAnchor    []*decodeCellAnchor `xml:"twoCellAnchor,omitempty"`
 }
 
+// decodeDrawingObject defines the structure used to deserialize the non-visual
+// drawing properties of the shape, picture, group shape or graphic frame in
+// the cell anchor.
+type decodeDrawingObject struct {
+	Elements []decodeDrawingObjectElement `xml:",any"`
+}
+
+// decodeDrawingObjectElement defines the structure used to deserialize the
+// non-visual properties of the element in the cell anchor.
+type decodeDrawingObjectElement struct {
+	Choice *decodeDrawingObject      `xml:"Choice"`
+	NvPr   []decodeDrawingObjectNvPr `xml:",any"`
+}
+
+// decodeDrawingObjectNvPr defines the structure used to deserialize the
+// non-visual drawing properties in the non-visual properties element.
+type decodeDrawingObjectNvPr struct {
+	CNvPr *decodeCNvPr `xml:"cNvPr"`
+}
+
 // decodeCNvPr directly maps the cNvPr (Non-Visual Drawing Properties). This
 // element specifies non-visual canvas properties. This allows for additional
 // information that does not affect the appearance of the picture to be
@@ -242,3 +263,252 @@ type decodeClientData struct {
 	FLocksWithSheet  bool `xml:"fLocksWithSheet,attr"`
 	FPrintsWithSheet bool `xml:"fPrintsWithSheet,attr"`
 }
+
+// decodeGraphicFrame defines the structure used to deserialize the graphic
+// frame in the cell anchor, which used to reference the chart part.
+type decodeGraphicFrame struct {
+	NvGraphicFramePr decodeNvGraphicFramePr `xml:"nvGraphicFramePr"`
+	Graphic          *decodeGraphic         `xml:"graphic"`
+}
+
+// decodeNvGraphicFramePr defines the structure used to deserialize the
+// non-visual properties of the graphic frame.
+type decodeNvGraphicFramePr struct {
+	CNvPr *decodeCNvPr `xml:"cNvPr"`
+}
+
+// decodeGraphic defines the structure used to deserialize the graphic object
+// of the graphic frame.
+type decodeGraphic struct {
+	GraphicData *decodeGraphicData `xml:"graphicData"`
+}
+
+// decodeGraphicData defines the structure used to deserialize the graphic
+// object data of the graphic frame.
+type decodeGraphicData struct {
+	URI   string              `xml:"uri,attr"`
+	Chart *decodeGraphicChart `xml:"chart"`
+}
+
+// decodeGraphicChart defines the structure used to deserialize the chart
+// reference in the graphic object data.
+type decodeGraphicChart struct {
+	RID string `xml:"id,attr"`
+}
+
+// decodeChartSpace defines the structure used to deserialize the chartSpace
+// element. The DrawingML elements in the xlsxChartSpace were defined with
+// prefixed element names for serialization, so decodeChartSpace just for
+// deserialization.
+type decodeChartSpace struct {
+	XMLName xml.Name         `xml:"http://schemas.openxmlformats.org/drawingml/2006/chart chartSpace"`
+	Chart   decodeChartChart `xml:"chart"`
+	SpPr    *decodeChartSpPr `xml:"spPr"`
+}
+
+// decodeChartChart defines the structure used to deserialize the chart
+// element of the chart space.
+type decodeChartChart struct {
+	Title        *decodeChartTitle    `xml:"title"`
+	PlotArea     *decodeChartPlotArea `xml:"plotArea"`
+	Legend       *decodeChartLegend   `xml:"legend"`
+	DispBlanksAs *attrValString       `xml:"dispBlanksAs"`
+}
+
+// decodeChartTitle defines the structure used to deserialize the title
+// element of the chart or axis.
+type decodeChartTitle struct {
+	Tx decodeChartTx `xml:"tx"`
+}
+
+// decodeChartTx defines the structure used to deserialize the text of the
+// chart title.
+type decodeChartTx struct {
+	StrRef *cStrRef         `xml:"strRef"`
+	Rich   *decodeChartRich `xml:"rich"`
+}
+
+// decodeChartRich defines the structure used to deserialize the rich text of
+// the chart title.
+type decodeChartRich struct {
+	P []decodeChartParagraph `xml:"p"`
+}
+
+// decodeChartParagraph defines the structure used to deserialize the
+// paragraph of the rich text.
+type decodeChartParagraph struct {
+	R []decodeChartRun `xml:"r"`
+}
+
+// decodeChartRun defines the structure used to deserialize the rich text run
+// in the title of the chart or axis.
+type decodeChartRun struct {
+	RPr *decodeChartRunPr `xml:"rPr"`
+	T   string            `xml:"t"`
+}
+
+// decodeChartRunPr defines the structure used to deserialize the run
+// properties of the rich text run.
+type decodeChartRunPr struct {
+	B         bool                  `xml:"b,attr"`
+	I         bool                  `xml:"i,attr"`
+	Sz        float64               `xml:"sz,attr"`
+	SolidFill *decodeChartSolidFill `xml:"solidFill"`
+}
+
+// decodeChartSolidFill defines the structure used to deserialize the solid
+// fill of the chart elements.
+type decodeChartSolidFill struct {
+	SrgbClr *attrValString `xml:"srgbClr"`
+}
+
+// decodeChartSpPr defines the structure used to deserialize the shape
+// properties of the chart elements.
+type decodeChartSpPr struct {
+	SolidFill *decodeChartSolidFill `xml:"solidFill"`
+	Ln        *decodeChartLn        `xml:"ln"`
+}
+
+// decodeChartLn defines the structure used to deserialize the outline of the
+// chart elements.
+type decodeChartLn struct {
+	W         int                   `xml:"w,attr"`
+	NoFill    *attrValString        `xml:"noFill"`
+	SolidFill *decodeChartSolidFill `xml:"solidFill"`
+}
+
+// decodeChartLegend defines the structure used to deserialize the legend
+// element of the chart.
+type decodeChartLegend struct {
+	LegendPos *attrValString `xml:"legendPos"`
+}
+
+// decodeChartPlotArea defines the structure used to deserialize the plot area
+// element of the chart.
+type decodeChartPlotArea struct {
+	AreaChart      []*decodeCharts   `xml:"areaChart"`
+	Area3DChart    []*decodeCharts   `xml:"area3DChart"`
+	BarChart       []*decodeCharts   `xml:"barChart"`
+	Bar3DChart     []*decodeCharts   `xml:"bar3DChart"`
+	BubbleChart    []*decodeCharts   `xml:"bubbleChart"`
+	DoughnutChart  []*decodeCharts   `xml:"doughnutChart"`
+	LineChart      []*decodeCharts   `xml:"lineChart"`
+	Line3DChart    []*decodeCharts   `xml:"line3DChart"`
+	PieChart       []*decodeCharts   `xml:"pieChart"`
+	Pie3DChart     []*decodeCharts   `xml:"pie3DChart"`
+	OfPieChart     []*decodeCharts   `xml:"ofPieChart"`
+	RadarChart     []*decodeCharts   `xml:"radarChart"`
+	ScatterChart   []*decodeCharts   `xml:"scatterChart"`
+	Surface3DChart []*decodeCharts   `xml:"surface3DChart"`
+	SurfaceChart   []*decodeCharts   `xml:"surfaceChart"`
+	CatAx          []*decodeChartAxs `xml:"catAx"`
+	DateAx         []*decodeChartAxs `xml:"dateAx"`
+	ValAx          []*decodeChartAxs `xml:"valAx"`
+	SerAx          []*decodeChartAxs `xml:"serAx"`
+}
+
+// decodeCharts defines the structure used to deserialize the common element
+// of the chart types in the plot area.
+type decodeCharts struct {
+	BarDir     *attrValString    `xml:"barDir"`
+	Grouping   *attrValString    `xml:"grouping"`
+	OfPieType  *attrValString    `xml:"ofPieType"`
+	VaryColors *attrValBool      `xml:"varyColors"`
+	Wireframe  *attrValBool      `xml:"wireframe"`
+	Ser        []*decodeChartSer `xml:"ser"`
+	SplitPos   *attrValInt       `xml:"splitPos"`
+	DLbls      *decodeChartDLbls `xml:"dLbls"`
+	Shape      *attrValString    `xml:"shape"`
+	HoleSize   *attrValInt       `xml:"holeSize"`
+	AxID       []*attrValInt     `xml:"axId"`
+}
+
+// decodeChartSer defines the structure used to deserialize the series
+// element of the chart.
+type decodeChartSer struct {
+	Tx         *cTx               `xml:"tx"`
+	SpPr       *decodeChartSpPr   `xml:"spPr"`
+	Marker     *decodeChartMarker `xml:"marker"`
+	Cat        *decodeChartData   `xml:"cat"`
+	Val        *decodeChartData   `xml:"val"`
+	XVal       *decodeChartData   `xml:"xVal"`
+	YVal       *decodeChartData   `xml:"yVal"`
+	Smooth     *attrValBool       `xml:"smooth"`
+	BubbleSize *decodeChartData   `xml:"bubbleSize"`
+	Bubble3D   *attrValBool       `xml:"bubble3D"`
+	DLbls      *decodeChartDLbls  `xml:"dLbls"`
+	ExtLst     *decodeChartExtLst `xml:"extLst"`
+}
+
+// decodeChartDLbls defines the structure used to deserialize the dLbls
+// element of the chart type element and series.
+type decodeChartDLbls struct {
+	NumFmt          *cNumFmt           `xml:"numFmt"`
+	DLblPos         *attrValString     `xml:"dLblPos"`
+	ShowLegendKey   *attrValBool       `xml:"showLegendKey"`
+	ShowVal         *attrValBool       `xml:"showVal"`
+	ShowCatName     *attrValBool       `xml:"showCatName"`
+	ShowSerName     *attrValBool       `xml:"showSerName"`
+	ShowPercent     *attrValBool       `xml:"showPercent"`
+	ShowBubbleSize  *attrValBool       `xml:"showBubbleSize"`
+	ShowLeaderLines *attrValBool       `xml:"showLeaderLines"`
+	ExtLst          *decodeChartExtLst `xml:"extLst"`
+}
+
+// decodeChartExtLst defines the structure used to deserialize the extLst
+// element of the data labels and series.
+type decodeChartExtLst struct {
+	Ext []*decodeChartExt `xml:"ext"`
+}
+
+// decodeChartExt defines the structure used to deserialize the ext element of
+// the data labels and series.
+type decodeChartExt struct {
+	URI                 string                 `xml:"uri,attr"`
+	SpPr                *decodeChartExtSpPr    `xml:"spPr"`
+	ShowDataLabelsRange *attrValBool           `xml:"showDataLabelsRange"`
+	ShowLeaderLines     *attrValBool           `xml:"showLeaderLines"`
+	DataLabelsRange     *decodeDataLabelsRange `xml:"datalabelsRange"`
+}
+
+// decodeChartExtSpPr defines the structure used to deserialize the c15:spPr
+// element of the data labels.
+type decodeChartExtSpPr struct {
+	PrstGeom *decodePrstGeom `xml:"prstGeom"`
+}
+
+// decodeDataLabelsRange defines the structure used to deserialize the
+// c15:datalabelsRange element of the series.
+type decodeDataLabelsRange struct {
+	F string `xml:"f"`
+}
+
+// decodeChartMarker defines the structure used to deserialize the marker
+// element of the series.
+type decodeChartMarker struct {
+	Symbol *attrValString `xml:"symbol"`
+	Size   *attrValInt    `xml:"size"`
+}
+
+// decodeChartData defines the structure used to deserialize the data source
+// of the series, such as cat, val, xVal, yVal and bubbleSize element.
+type decodeChartData struct {
+	StrRef *cStrRef `xml:"strRef"`
+	NumRef *cNumRef `xml:"numRef"`
+	StrLit *cStrLit `xml:"strLit"`
+	NumLit *cNumLit `xml:"numLit"`
+}
+
+// decodeChartAxs defines the structure used to deserialize the catAx, dateAx,
+// valAx and serAx element.
+type decodeChartAxs struct {
+	AxID           *attrValInt       `xml:"axId"`
+	Scaling        *cScaling         `xml:"scaling"`
+	Delete         *attrValBool      `xml:"delete"`
+	MajorGridlines *cChartLines      `xml:"majorGridlines"`
+	MinorGridlines *cChartLines      `xml:"minorGridlines"`
+	Title          *decodeChartTitle `xml:"title"`
+	NumFmt         *cNumFmt          `xml:"numFmt"`
+	MajorUnit      *attrValFloat     `xml:"majorUnit"`
+	TickLblSkip    *attrValInt       `xml:"tickLblSkip"`
+}
@@ -264,6 +264,7 @@ type xlsxWsDr struct {
 	A                string                  `xml:"xmlns:a,attr,omitempty"`
 	Xdr              string                  `xml:"xmlns:xdr,attr,omitempty"`
 	R                string                  `xml:"xmlns:r,attr,omitempty"`
+	Attrs            []xml.Attr              `xml:",any,attr"`
 	AlternateContent []*xlsxAlternateContent `xml:"mc:AlternateContent"`
 	AbsoluteAnchor   []*xdrCellAnchor        `xml:"xdr:absoluteAnchor"`
 	OneCellAnchor    []*xdrCellAnchor        `xml:"xdr:oneCellAnchor"`
@@ -308,9 +309,11 @@ type xlsxGraphic struct {
 // document. This graphic object is provided entirely by the document authors
 // who choose to persist this data within the document.
 type xlsxGraphicData struct {
-	URI   string     `xml:"uri,attr"`
-	Chart *xlsxChart `xml:"c:chart,omitempty"`
-	Sle   *xlsxSle   `xml:"sle:slicer"`
+	URI     string       `xml:"uri,attr"`
+	Chart   *xlsxChart   `xml:"c:chart,omitempty"`
+	ChartEx *xlsxChartEx `xml:"cx:chart"`
+	Sle     *xlsxSle     `xml:"sle:slicer"`
+	Tsle    *xlsxTsle    `xml:"tsle:timeslicer"`
 }
 
 type xlsxSle struct {
@@ -318,6 +321,13 @@ type xlsxSle struct {
 	Name  string `xml:"name,attr"`
 }
 
+// xlsxTsle directly maps the tsle:timeslicer element, which specifies the
+// timeline view in the drawing.
+type xlsxTsle struct {
+	XMLNS string `xml:"xmlns:tsle,attr"`
+	Name  string `xml:"name,attr"`
+}
+
 // xlsxChart (Chart) directly maps the c:chart element.
 type xlsxChart struct {
 	C   string `xml:"xmlns:c,attr"`
@@ -407,6 +417,17 @@ type xdrTxBody struct {
 	P      []*aP    `xml:"a:p"`
 }
 
+// DrawingObject directly maps the handle of the chart, picture or shape in the
+// worksheet returned by the AddChartObject, AddPictureObject,
+// AddPictureFromBytesObject and AddShapeObject functions. The ID is the unique identifier of the object in the
+// drawing of the worksheet, and the Name is the object name displayed in the
+// selection pane of the spreadsheet application.
+type DrawingObject struct {
+	Sheet string
+	ID    int
+	Name  string
+}
+
 // Picture maps the format settings of the picture.
 type Picture struct {
 	Extension string
@@ -441,6 +462,36 @@ type Shape struct {
 	Fill      Fill
 	Line      ShapeLine
 	Paragraph []RichTextRun
+	TextBox   ShapeTextBox
+}
+
+// ShapeTextBox directly maps the text box settings of the shape, including
+// the paragraphs, vertical anchoring, autofit, text wrapping and internal
+// margins of the text body.
+type ShapeTextBox struct {
+	Paragraphs []ShapeParagraph
+	Vertical   string
+	AutoFit    string
+	WrapText   bool
+	Margins    *ShapeMargins
+}
+
+// ShapeParagraph directly maps the paragraph in the text box of the shape,
+// which contains multiple rich text runs and the equation in Office Math
+// Markup Language (OMML) with the horizontal alignment.
+type ShapeParagraph struct {
+	Horizontal string
+	Equation   string
+	Runs       []RichTextRun
+}
+
+// ShapeMargins directly maps the internal margins of the text box of the
+// shape in points.
+type ShapeMargins struct {
+	Left   float64
+	Right  float64
+	Top    float64
+	Bottom float64
 }
 
 // ShapeColor directly maps the color settings of the shape.
@@ -120,26 +120,36 @@ type xlsxCacheField struct {
 // those values that are referenced in multiple places across all the
 // PivotTable parts.
 type xlsxSharedItems struct {
-	ContainsSemiMixedTypes bool           `xml:"containsSemiMixedTypes,attr,omitempty"`
-	ContainsNonDate        bool           `xml:"containsNonDate,attr,omitempty"`
-	ContainsDate           bool           `xml:"containsDate,attr,omitempty"`
-	ContainsString         bool           `xml:"containsString,attr,omitempty"`
-	ContainsBlank          bool           `xml:"containsBlank,attr,omitempty"`
-	ContainsMixedTypes     bool           `xml:"containsMixedTypes,attr,omitempty"`
-	ContainsNumber         bool           `xml:"containsNumber,attr,omitempty"`
-	ContainsInteger        bool           `xml:"containsInteger,attr,omitempty"`
-	MinValue               float64        `xml:"minValue,attr,omitempty"`
-	MaxValue               float64        `xml:"maxValue,attr,omitempty"`
-	MinDate                string         `xml:"minDate,attr,omitempty"`
-	MaxDate                string         `xml:"maxDate,attr,omitempty"`
-	Count                  int            `xml:"count,attr"`
-	LongText               bool           `xml:"longText,attr,omitempty"`
-	M                      []xlsxMissing  `xml:"m"`
-	N                      []xlsxNumber   `xml:"n"`
-	B                      []xlsxBoolean  `xml:"b"`
-	E                      []xlsxError    `xml:"e"`
-	S                      []xlsxString   `xml:"s"`
-	D                      []xlsxDateTime `xml:"d"`
+	ContainsSemiMixedTypes *bool            `xml:"containsSemiMixedTypes,attr"`
+	ContainsNonDate        *bool            `xml:"containsNonDate,attr"`
+	ContainsDate           bool             `xml:"containsDate,attr,omitempty"`
+	ContainsString         *bool            `xml:"containsString,attr"`
+	ContainsBlank          bool             `xml:"containsBlank,attr,omitempty"`
+	ContainsMixedTypes     bool             `xml:"containsMixedTypes,attr,omitempty"`
+	ContainsNumber         bool             `xml:"containsNumber,attr,omitempty"`
+	ContainsInteger        bool             `xml:"containsInteger,attr,omitempty"`
+	MinValue               float64          `xml:"minValue,attr,omitempty"`
+	MaxValue               float64          `xml:"maxValue,attr,omitempty"`
+	MinDate                string           `xml:"minDate,attr,omitempty"`
+	MaxDate                string           `xml:"maxDate,attr,omitempty"`
+	Count                  int              `xml:"count,attr"`
+	LongText               bool             `xml:"longText,attr,omitempty"`
+	M                      []xlsxMissing    `xml:"m"`
+	N                      []xlsxNumber     `xml:"n"`
+	B                      []xlsxBoolean    `xml:"b"`
+	E                      []xlsxError      `xml:"e"`
+	S                      []xlsxString     `xml:"s"`
+	D                      []xlsxDateTime   `xml:"d"`
+	Items                  []xlsxSharedItem `xml:",any"`
+}
+
+// xlsxSharedItem represents a shared item of the pivot cache field, the name
+// of the element specifies the type of the item, such as m, n, b, e, s and d.
+// This is used to keep the shared items in order of first appearance in the
+// source data.
+type xlsxSharedItem struct {
+	XMLName xml.Name
+	V       string `xml:"v,attr,omitempty"`
 }
 
 // xlsxMissing represents a value that was not specified.
@@ -197,7 +207,33 @@ type xlsxString struct {
 type xlsxDateTime struct{}
 
 // xlsxFieldGroup represents the collection of properties for a field group.
-type xlsxFieldGroup struct{}
+type xlsxFieldGroup struct {
+	Par        int             `xml:"par,attr,omitempty"`
+	Base       *int            `xml:"base,attr"`
+	RangePr    *xlsxRangePr    `xml:"rangePr"`
+	DiscretePr *xlsxInnerXML   `xml:"discretePr"`
+	GroupItems *xlsxGroupItems `xml:"groupItems"`
+}
+
+// xlsxRangePr represents the properties of a range grouping, such as the
+// start and end values and the interval of the numeric range, or the start
+// and end dates and the date part of the date range.
+type xlsxRangePr struct {
+	AutoStart     *bool    `xml:"autoStart,attr"`
+	AutoEnd       *bool    `xml:"autoEnd,attr"`
+	GroupBy       string   `xml:"groupBy,attr,omitempty"`
+	StartNum      *float64 `xml:"startNum,attr"`
+	EndNum        *float64 `xml:"endNum,attr"`
+	StartDate     string   `xml:"startDate,attr,omitempty"`
+	EndDate       string   `xml:"endDate,attr,omitempty"`
+	GroupInterval float64  `xml:"groupInterval,attr,omitempty"`
+}
+
+// xlsxGroupItems represents the collection of items in a grouped field.
+type xlsxGroupItems struct {
+	Count int          `xml:"count,attr"`
+	S     []xlsxString `xml:"s"`
+}
 
 // xlsxCacheHierarchies represents the collection of OLAP hierarchies in the
 // PivotCache.
@@ -240,3 +276,27 @@ type decodeX14PivotCacheDefinition struct {
 	XMLName      xml.Name `xml:"pivotCacheDefinition"`
 	PivotCacheID int      `xml:"pivotCacheId,attr"`
 }
+
+// decodePivotCacheFields defines the structure used to parse the shared items
+// of the cache fields in a pivot cache definition in order of appearance.
+type decodePivotCacheFields struct {
+	CacheField []struct {
+		SharedItems struct {
+			Items []xlsxSharedItem `xml:",any"`
+		} `xml:"sharedItems"`
+	} `xml:"cacheFields>cacheField"`
+}
+
+// xlsxPivotCacheRecords represents the pivotCacheRecords part. This part
+// contains the underlying source data of the pivot cache, each record is
+// stored as the indexes to the shared items of the cache fields.
+type xlsxPivotCacheRecords struct {
+	XMLName xml.Name                `xml:"http://schemas.openxmlformats.org/spreadsheetml/2006/main pivotCacheRecords"`
+	Count   int                     `xml:"count,attr"`
+	R       []*xlsxPivotCacheRecord `xml:"r"`
+}
+
+// xlsxPivotCacheRecord represents a single record of data in the pivot cache.
+type xlsxPivotCacheRecord struct {
+	X []*xlsxX `xml:"x"`
+}
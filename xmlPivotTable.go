@@ -224,7 +224,9 @@ type xlsxI struct {
 }
 
 // xlsxX represents an array of indexes to cached shared item values.
-type xlsxX struct{}
+type xlsxX struct {
+	V int `xml:"v,attr,omitempty"`
+}
 
 // xlsxColFields represents the collection of fields that are on the column
 // axis of the PivotTable.
@@ -203,3 +203,84 @@ type xlsxTimeline struct {
 	ScrollPosition          string `xml:"scrollPosition,attr,omitempty"`
 	Style                   string `xml:"style,attr,omitempty"`
 }
+
+// xlsxTimelineCacheDefinition directly maps the timelineCacheDefinition
+// element that specifies a timeline cache.
+type xlsxTimelineCacheDefinition struct {
+	XMLName     xml.Name                    `xml:"http://schemas.microsoft.com/office/spreadsheetml/2010/11/main timelineCacheDefinition"`
+	XMLNSXMC    string                      `xml:"xmlns:mc,attr"`
+	XMLNSX      string                      `xml:"xmlns:x,attr"`
+	XMLNSXR10   string                      `xml:"xmlns:xr10,attr"`
+	Name        string                      `xml:"name,attr"`
+	XR10UID     string                      `xml:"xr10:uid,attr,omitempty"`
+	SourceName  string                      `xml:"sourceName,attr"`
+	PivotTables *xlsxSlicerCachePivotTables `xml:"pivotTables"`
+	State       *xlsxTimelineState          `xml:"state"`
+	ExtLst      *xlsxExtLst                 `xml:"extLst"`
+}
+
+// xlsxTimelineState is a complex type that specifies the filter state, the
+// selected range and the bounds of the timeline cache.
+type xlsxTimelineState struct {
+	SingleRangeFilterState *bool              `xml:"singleRangeFilterState,attr"`
+	MinimalRefreshVersion  int                `xml:"minimalRefreshVersion,attr"`
+	LastRefreshVersion     int                `xml:"lastRefreshVersion,attr"`
+	PivotCacheID           int                `xml:"pivotCacheId,attr"`
+	FilterType             string             `xml:"filterType,attr"`
+	FilterID               *int               `xml:"filterId,attr"`
+	FilterTabID            *int               `xml:"filterTabId,attr"`
+	FilterPivotName        string             `xml:"filterPivotName,attr,omitempty"`
+	Selection              *xlsxTimelineRange `xml:"selection"`
+	Bounds                 *xlsxTimelineRange `xml:"bounds"`
+	ExtLst                 *xlsxExtLst        `xml:"extLst"`
+}
+
+// xlsxTimelineRange is a complex type that specifies the date range of the
+// timeline cache.
+type xlsxTimelineRange struct {
+	StartDate string `xml:"startDate,attr"`
+	EndDate   string `xml:"endDate,attr"`
+}
+
+// xlsxX15TimelineRefs directly maps the x15:timelineRefs element.
+type xlsxX15TimelineRefs struct {
+	XMLName     xml.Name              `xml:"x15:timelineRefs"`
+	TimelineRef []*xlsxX15TimelineRef `xml:"x15:timelineRef"`
+}
+
+// xlsxX15TimelineRef directly maps the x15:timelineRef element.
+type xlsxX15TimelineRef struct {
+	RID string `xml:"r:id,attr"`
+}
+
+// xlsxX15TimelineCacheRefs directly maps the x15:timelineCacheRefs element.
+type xlsxX15TimelineCacheRefs struct {
+	XMLName xml.Name `xml:"x15:timelineCacheRefs"`
+	Content string   `xml:",innerxml"`
+}
+
+// xlsxX15TimelineCacheRef directly maps the x15:timelineCacheRef element.
+type xlsxX15TimelineCacheRef struct {
+	XMLName xml.Name `xml:"x15:timelineCacheRef"`
+	RID     string   `xml:"r:id,attr"`
+}
+
+// decodeTimelineRefs defines the structure used to parse the
+// x15:timelineRefs element of the worksheet.
+type decodeTimelineRefs struct {
+	XMLName     xml.Name             `xml:"timelineRefs"`
+	TimelineRef []*decodeTimelineRef `xml:"timelineRef"`
+}
+
+// decodeTimelineRef defines the structure used to parse the x15:timelineRef
+// element of the worksheet.
+type decodeTimelineRef struct {
+	RID string `xml:"id,attr"`
+}
+
+// decodeTimelineCacheRefs defines the structure used to parse the
+// x15:timelineCacheRefs element of the workbook.
+type decodeTimelineCacheRefs struct {
+	XMLName xml.Name `xml:"timelineCacheRefs"`
+	Content string   `xml:",innerxml"`
+}
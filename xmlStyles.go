@@ -223,6 +223,7 @@ type xlsxXf struct {
 	ApplyProtection   *bool           `xml:"applyProtection,attr"`
 	Alignment         *xlsxAlignment  `xml:"alignment"`
 	Protection        *xlsxProtection `xml:"protection"`
+	ExtLst            *xlsxExtLst     `xml:"extLst"`
 }
 
 // xlsxCellXfs directly maps the cellXfs element. This element contains the
@@ -375,4 +376,44 @@ type Style struct {
 	DecimalPlaces *int
 	CustomNumFmt  *string
 	NegRed        bool
+	Checkbox      bool
+}
+
+// xlsxXfComplement directly maps the xfComplement element in the extension
+// of the cell format, which specifies the index of the feature property bag
+// mapped by the cell format.
+type xlsxXfComplement struct {
+	I int `xml:"i,attr"`
+}
+
+// xlsxFeaturePropertyBags directly maps the FeaturePropertyBags element, the
+// root element of the feature property bag part, which specifies the
+// properties of the features such as the cell checkbox control.
+type xlsxFeaturePropertyBags struct {
+	XMLName xml.Name                 `xml:"http://schemas.microsoft.com/office/spreadsheetml/2022/featurepropertybag FeaturePropertyBags"`
+	Count   int                      `xml:"count,attr,omitempty"`
+	Bag     []xlsxFeaturePropertyBag `xml:"bag"`
+}
+
+// xlsxFeaturePropertyBag directly maps the bag element, which specifies a
+// feature property bag.
+type xlsxFeaturePropertyBag struct {
+	Type   string                        `xml:"type,attr"`
+	ExtRef string                        `xml:"extRef,attr,omitempty"`
+	BagID  []xlsxFeaturePropertyBagID    `xml:"bagId"`
+	A      []xlsxFeaturePropertyBagArray `xml:"a"`
+}
+
+// xlsxFeaturePropertyBagID directly maps the bagId element, which specifies
+// the index of the referenced feature property bag.
+type xlsxFeaturePropertyBagID struct {
+	K   string `xml:"k,attr,omitempty"`
+	Val int    `xml:",chardata"`
+}
+
+// xlsxFeaturePropertyBagArray directly maps the a element, which specifies
+// an array of the referenced feature property bags.
+type xlsxFeaturePropertyBagArray struct {
+	K     string                     `xml:"k,attr,omitempty"`
+	BagID []xlsxFeaturePropertyBagID `xml:"bagId"`
 }
@@ -43,6 +43,7 @@ type xlsxTable struct {
 	DataCellStyle        string              `xml:"dataCellStyle,attr,omitempty"`
 	TotalsRowCellStyle   string              `xml:"totalsRowCellStyle,attr,omitempty"`
 	ConnectionID         int                 `xml:"connectionId,attr,omitempty"`
+	UID                  string              `xml:"http://schemas.microsoft.com/office/spreadsheetml/2014/revision uid,attr,omitempty"`
 	AutoFilter           *xlsxAutoFilter     `xml:"autoFilter"`
 	TableColumns         *xlsxTableColumns   `xml:"tableColumns"`
 	TableStyleInfo       *xlsxTableStyleInfo `xml:"tableStyleInfo"`
@@ -54,6 +55,7 @@ type xlsxTable struct {
 type xlsxAutoFilter struct {
 	XMLName      xml.Name            `xml:"autoFilter"`
 	Ref          string              `xml:"ref,attr"`
+	UID          string              `xml:"http://schemas.microsoft.com/office/spreadsheetml/2014/revision uid,attr,omitempty"`
 	FilterColumn []*xlsxFilterColumn `xml:"filterColumn"`
 }
 
@@ -178,6 +180,7 @@ type xlsxTableColumns struct {
 // this table.
 type xlsxTableColumn struct {
 	ID                 int    `xml:"id,attr"`
+	UID                string `xml:"http://schemas.microsoft.com/office/spreadsheetml/2016/revision3 uid,attr,omitempty"`
 	UniqueName         string `xml:"uniqueName,attr,omitempty"`
 	Name               string `xml:"name,attr"`
 	TotalsRowFunction  string `xml:"totalsRowFunction,attr,omitempty"`
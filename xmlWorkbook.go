@@ -43,6 +43,8 @@ type xlsxWorkbook struct {
 	WorkbookPr             *xlsxWorkbookPr          `xml:"workbookPr"`
 	AlternateContent       *xlsxAlternateContent    `xml:"mc:AlternateContent"`
 	DecodeAlternateContent *xlsxInnerXML            `xml:"http://schemas.openxmlformats.org/markup-compatibility/2006 AlternateContent"`
+	RevisionPtr            *xlsxRevisionPtr         `xml:"xr:revisionPtr"`
+	DecodeRevisionPtr      *decodeRevisionPtr       `xml:"http://schemas.microsoft.com/office/spreadsheetml/2014/revision revisionPtr"`
 	WorkbookProtection     *xlsxWorkbookProtection  `xml:"workbookProtection"`
 	BookViews              *xlsxBookViews           `xml:"bookViews"`
 	Sheets                 xlsxSheets               `xml:"sheets"`
@@ -61,6 +63,27 @@ type xlsxWorkbook struct {
 	ExtLst                 *xlsxExtLst              `xml:"extLst"`
 }
 
+// xlsxRevisionPtr directly maps the revisionPtr element. This element
+// specifies the revision identifiers of the workbook which are used for
+// co-authoring the document in SharePoint and OneDrive.
+type xlsxRevisionPtr struct {
+	RevIDLastSave     string `xml:"revIDLastSave,attr,omitempty"`
+	DocumentID        string `xml:"documentId,attr,omitempty"`
+	CoauthVersionLast string `xml:"xr6:coauthVersionLast,attr,omitempty"`
+	CoauthVersionMax  string `xml:"xr6:coauthVersionMax,attr,omitempty"`
+	UIDLastSave       string `xml:"xr10:uidLastSave,attr,omitempty"`
+}
+
+// decodeRevisionPtr defines the structure used to parse the revisionPtr
+// element of the workbook.
+type decodeRevisionPtr struct {
+	RevIDLastSave     string `xml:"revIDLastSave,attr,omitempty"`
+	DocumentID        string `xml:"documentId,attr,omitempty"`
+	CoauthVersionLast string `xml:"http://schemas.microsoft.com/office/spreadsheetml/2016/revision6 coauthVersionLast,attr,omitempty"`
+	CoauthVersionMax  string `xml:"http://schemas.microsoft.com/office/spreadsheetml/2016/revision6 coauthVersionMax,attr,omitempty"`
+	UIDLastSave       string `xml:"http://schemas.microsoft.com/office/spreadsheetml/2016/revision10 uidLastSave,attr,omitempty"`
+}
+
 // xlsxFileRecoveryPr maps sheet recovery information. This element defines
 // properties that track the state of the workbook file, such as whether the
 // file was saved during a crash, or whether it should be opened in auto-recover
@@ -155,6 +178,7 @@ type xlsxWorkBookView struct {
 	FirstSheet             int    `xml:"firstSheet,attr,omitempty"`
 	ActiveTab              int    `xml:"activeTab,attr,omitempty"`
 	AutoFilterDateGrouping *bool  `xml:"autoFilterDateGrouping,attr"`
+	UID                    string `xml:"http://schemas.microsoft.com/office/spreadsheetml/2015/revision2 uid,attr,omitempty"`
 }
 
 // xlsxSheets directly maps the sheets element from the namespace
@@ -195,6 +219,76 @@ type xlsxExternalReference struct {
 	RID string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr,omitempty"`
 }
 
+// xlsxExternalLink directly maps the externalLink element. This part specifies
+// the data of the external workbook, DDE or OLE link referenced by the
+// workbook.
+type xlsxExternalLink struct {
+	XMLName      xml.Name          `xml:"http://schemas.openxmlformats.org/spreadsheetml/2006/main externalLink"`
+	ExternalBook *xlsxExternalBook `xml:"externalBook"`
+	DDELink      *xlsxInnerXML     `xml:"ddeLink"`
+	OLELink      *xlsxInnerXML     `xml:"oleLink"`
+	ExtLst       *xlsxExtLst       `xml:"extLst"`
+}
+
+// xlsxExternalBook directly maps the externalBook element. This element
+// specifies the relationship of the external workbook, the worksheet names,
+// defined names and the cached cell values of the external workbook.
+type xlsxExternalBook struct {
+	RID          string                    `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr,omitempty"`
+	SheetNames   *xlsxExternalSheetNames   `xml:"sheetNames"`
+	DefinedNames *xlsxInnerXML             `xml:"definedNames"`
+	SheetDataSet *xlsxExternalSheetDataSet `xml:"sheetDataSet"`
+}
+
+// xlsxExternalSheetNames directly maps the sheetNames element. This element
+// specifies the worksheet names of the external workbook.
+type xlsxExternalSheetNames struct {
+	SheetName []xlsxExternalSheetName `xml:"sheetName"`
+}
+
+// xlsxExternalSheetName directly maps the sheetName element.
+type xlsxExternalSheetName struct {
+	Val string `xml:"val,attr"`
+}
+
+// xlsxExternalSheetDataSet directly maps the sheetDataSet element. This
+// element specifies the cached cell values of the worksheets in the external
+// workbook.
+type xlsxExternalSheetDataSet struct {
+	SheetData []xlsxExternalSheetData `xml:"sheetData"`
+}
+
+// xlsxExternalSheetData directly maps the sheetData element of the external
+// workbook. The sheetId attribute is the zero-based index of the worksheet
+// name in the sheetNames element.
+type xlsxExternalSheetData struct {
+	SheetID      int               `xml:"sheetId,attr"`
+	RefreshError bool              `xml:"refreshError,attr,omitempty"`
+	Row          []xlsxExternalRow `xml:"row"`
+}
+
+// xlsxExternalRow directly maps the row element of the external workbook.
+type xlsxExternalRow struct {
+	R    int                `xml:"r,attr"`
+	Cell []xlsxExternalCell `xml:"cell"`
+}
+
+// xlsxExternalCell directly maps the cell element of the external workbook.
+type xlsxExternalCell struct {
+	R  string `xml:"r,attr,omitempty"`
+	T  string `xml:"t,attr,omitempty"`
+	VM *int   `xml:"vm,attr"`
+	V  string `xml:"v,omitempty"`
+}
+
+// xlsxMacrosheet directly maps the macrosheet element in the namespace
+// http://schemas.microsoft.com/office/excel/2006/main. This element represents
+// the Excel 4.0 (XLM) macro sheet, in which the cells contains the macro
+// formulas and the values.
+type xlsxMacrosheet struct {
+	SheetData xlsxSheetData `xml:"sheetData"`
+}
+
 // xlsxPivotCaches element enumerates pivot cache definition parts used by pivot
 // tables and formulas in this workbook.
 type xlsxPivotCaches struct {
@@ -246,7 +340,10 @@ type xlsxAlternateContent struct {
 type xlsxChoice struct {
 	XMLName    xml.Name `xml:"mc:Choice"`
 	XMLNSA14   string   `xml:"xmlns:a14,attr,omitempty"`
+	XMLNSCx1   string   `xml:"xmlns:cx1,attr,omitempty"`
+	XMLNSCx2   string   `xml:"xmlns:cx2,attr,omitempty"`
 	XMLNSSle15 string   `xml:"xmlns:sle15,attr,omitempty"`
+	XMLNSTsle  string   `xml:"xmlns:tsle,attr,omitempty"`
 	Requires   string   `xml:"Requires,attr,omitempty"`
 	Content    string   `xml:",innerxml"`
 }
@@ -277,6 +374,27 @@ type decodeExt struct {
 	Content string `xml:",innerxml"`
 }
 
+// xlsxCustomProps directly maps the custom properties of the workbook or
+// worksheet, which stored in the extension list.
+type xlsxCustomProps struct {
+	XMLName     xml.Name         `xml:"xcp:customProps"`
+	XMLNSXCP    string           `xml:"xmlns:xcp,attr"`
+	CustomProps []xlsxCustomProp `xml:"xcp:customProp"`
+}
+
+// xlsxCustomProp directly maps the name and value of the custom property.
+type xlsxCustomProp struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value,attr"`
+}
+
+// decodeCustomProps defines the structure used to parse the custom
+// properties in the extension list.
+type decodeCustomProps struct {
+	XMLName     xml.Name         `xml:"ext"`
+	CustomProps []xlsxCustomProp `xml:"customProps>customProp"`
+}
+
 // xlsxDefinedNames directly maps the definedNames element. This element defines
 // the collection of defined names for this workbook. Defined names are
 // descriptive names to represent cells, ranges of cells, formulas, or constant
@@ -375,6 +493,24 @@ type xlsxCustomWorkbookView struct {
 	YWindow              *int    `xml:"yWindow,attr"`
 }
 
+// ExternalLink directly maps the settings of the external workbook link. The
+// Index is the index of the external workbook in the formulas, such as 1 in
+// the formula [1]Sheet1!A1.
+type ExternalLink struct {
+	Index      int
+	Target     string
+	SheetNames []string
+	Values     []ExternalLinkValue
+}
+
+// ExternalLinkValue directly maps the cached cell value of the external
+// workbook link.
+type ExternalLinkValue struct {
+	Sheet string
+	Cell  string
+	Value string
+}
+
 // DefinedName directly maps the name for a cell or cell range on a
 // worksheet.
 type DefinedName struct {
@@ -384,6 +520,15 @@ type DefinedName struct {
 	Scope    string
 }
 
+// ImportDefinedNamesOptions directly maps the settings of importing the
+// defined names. The SheetMap specifies the target worksheet names by the
+// worksheet names of the source workbook, and the Overwrite specifies if
+// replace the existing defined names with the same name and scope.
+type ImportDefinedNamesOptions struct {
+	SheetMap  map[string]string
+	Overwrite bool
+}
+
 // WorkbookPropsOptions directly maps the settings of workbook proprieties.
 type WorkbookPropsOptions struct {
 	Date1904      *bool
@@ -391,6 +536,16 @@ type WorkbookPropsOptions struct {
 	CodeName      *string
 }
 
+// CalcPropsOptions defines the collection of properties the application uses
+// to record calculation status and details.
+type CalcPropsOptions struct {
+	CalcMode       *string
+	FullCalcOnLoad *bool
+	Iterate        *bool
+	IterateCount   *uint
+	IterateDelta   *float64
+}
+
 // WorkbookProtectionOptions directly maps the settings of workbook protection.
 type WorkbookProtectionOptions struct {
 	AlgorithmName string
@@ -398,3 +553,53 @@ type WorkbookProtectionOptions struct {
 	LockStructure bool
 	LockWindows   bool
 }
+
+// MacroSheet directly maps the Excel 4.0 (XLM) macro sheet of the workbook.
+type MacroSheet struct {
+	Name          string
+	Path          string
+	Visible       bool
+	VeryHidden    bool
+	International bool
+	Cells         []MacroSheetCell
+}
+
+// MacroSheetCell directly maps the non-empty cell of the Excel 4.0 (XLM)
+// macro sheet.
+type MacroSheetCell struct {
+	Cell    string
+	Formula string
+	Value   string
+}
+
+// CustomUIOptions directly maps the settings of the custom UI (ribbon)
+// definition. The Content is the XML of the custom UI definition, which root
+// element is customUI in the namespace of Office 2007 or Office 2010 and later.
+// The Images specifies the image resources referenced by the image attribute
+// of the controls.
+type CustomUIOptions struct {
+	Content string
+	Images  []CustomUIImage
+}
+
+// CustomUIImage directly maps the image resource of the custom UI definition.
+// The ID is the identifier referenced by the image attribute of the controls.
+type CustomUIImage struct {
+	ID        string
+	Extension string
+	File      []byte
+}
+
+// ReferenceIssue directly maps the broken, circular or external reference
+// found in the workbook. The Cell is empty for the issue of the defined name
+// or external link, and the Sheet is the scope of the defined name, which is
+// empty for the global defined name.
+type ReferenceIssue struct {
+	Type        ReferenceIssueType
+	Sheet       string
+	Cell        string
+	DefinedName string
+	Formula     string
+	Reference   string
+	Suggestion  string
+}
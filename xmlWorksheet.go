@@ -62,6 +62,7 @@ type xlsxWorksheet struct {
 	TableParts             *xlsxTableParts              `xml:"tableParts"`
 	ExtLst                 *xlsxExtLst                  `xml:"extLst"`
 	DecodeAlternateContent *xlsxInnerXML                `xml:"http://schemas.openxmlformats.org/markup-compatibility/2006 AlternateContent"`
+	dvOrder                []bool
 }
 
 // xlsxDrawing change r:id to rid in the namespace.
@@ -194,7 +195,7 @@ type xlsxSheetView struct {
 	TabSelected              bool             `xml:"tabSelected,attr,omitempty"`
 	ShowRuler                *bool            `xml:"showRuler,attr,omitempty"`
 	ShowWhiteSpace           *bool            `xml:"showWhiteSpace,attr"`
-	ShowOutlineSymbols       bool             `xml:"showOutlineSymbols,attr,omitempty"`
+	ShowOutlineSymbols       *bool            `xml:"showOutlineSymbols,attr"`
 	DefaultGridColor         *bool            `xml:"defaultGridColor,attr"`
 	View                     string           `xml:"view,attr,omitempty"`
 	TopLeftCell              string           `xml:"topLeftCell,attr,omitempty"`
@@ -434,6 +435,7 @@ type xlsxDataValidation struct {
 	Error            *string       `xml:"error,attr"`
 	ErrorStyle       *string       `xml:"errorStyle,attr"`
 	ErrorTitle       *string       `xml:"errorTitle,attr"`
+	ImeMode          string        `xml:"imeMode,attr,omitempty"`
 	Operator         string        `xml:"operator,attr,omitempty"`
 	Prompt           *string       `xml:"prompt,attr"`
 	PromptTitle      *string       `xml:"promptTitle,attr"`
@@ -442,6 +444,7 @@ type xlsxDataValidation struct {
 	ShowInputMessage bool          `xml:"showInputMessage,attr,omitempty"`
 	Sqref            string        `xml:"sqref,attr"`
 	Type             string        `xml:"type,attr,omitempty"`
+	UID              string        `xml:"http://schemas.microsoft.com/office/spreadsheetml/2014/revision uid,attr,omitempty"`
 	Formula1         *xlsxInnerXML `xml:"formula1"`
 	Formula2         *xlsxInnerXML `xml:"formula2"`
 }
@@ -600,7 +603,7 @@ type xlsxIconSet struct {
 // cfvo (Conditional Format Value Object) describes the values of the
 // interpolation points in a gradient scale.
 type xlsxCfvo struct {
-	Gte    bool        `xml:"gte,attr,omitempty"`
+	Gte    *bool       `xml:"gte,attr"`
 	Type   string      `xml:"type,attr,omitempty"`
 	Val    string      `xml:"val,attr,omitempty"`
 	ExtLst *xlsxExtLst `xml:"extLst"`
@@ -701,9 +704,51 @@ type xlsxLegacyDrawingHF struct {
 
 // decodeX14SparklineGroups directly maps the sparklineGroups element.
 type decodeX14SparklineGroups struct {
-	XMLName xml.Name `xml:"sparklineGroups"`
-	XMLNSXM string   `xml:"xmlns:xm,attr"`
-	Content string   `xml:",innerxml"`
+	XMLName         xml.Name                   `xml:"sparklineGroups"`
+	XMLNSXM         string                     `xml:"xmlns:xm,attr"`
+	SparklineGroups []*decodeX14SparklineGroup `xml:"sparklineGroup"`
+	Content         string                     `xml:",innerxml"`
+}
+
+// decodeX14SparklineGroup directly maps the sparklineGroup element.
+type decodeX14SparklineGroup struct {
+	ManualMax           int                 `xml:"manualMax,attr"`
+	ManualMin           int                 `xml:"manualMin,attr"`
+	LineWeight          float64             `xml:"lineWeight,attr"`
+	Type                string              `xml:"type,attr"`
+	DateAxis            bool                `xml:"dateAxis,attr"`
+	DisplayEmptyCellsAs string              `xml:"displayEmptyCellsAs,attr"`
+	Markers             bool                `xml:"markers,attr"`
+	High                bool                `xml:"high,attr"`
+	Low                 bool                `xml:"low,attr"`
+	First               bool                `xml:"first,attr"`
+	Last                bool                `xml:"last,attr"`
+	Negative            bool                `xml:"negative,attr"`
+	DisplayXAxis        bool                `xml:"displayXAxis,attr"`
+	DisplayHidden       bool                `xml:"displayHidden,attr"`
+	MinAxisType         string              `xml:"minAxisType,attr"`
+	MaxAxisType         string              `xml:"maxAxisType,attr"`
+	RightToLeft         bool                `xml:"rightToLeft,attr"`
+	ColorSeries         *xlsxColor          `xml:"colorSeries"`
+	ColorNegative       *xlsxColor          `xml:"colorNegative"`
+	ColorAxis           *xlsxColor          `xml:"colorAxis"`
+	ColorMarkers        *xlsxColor          `xml:"colorMarkers"`
+	ColorFirst          *xlsxColor          `xml:"colorFirst"`
+	ColorLast           *xlsxColor          `xml:"colorLast"`
+	ColorHigh           *xlsxColor          `xml:"colorHigh"`
+	ColorLow            *xlsxColor          `xml:"colorLow"`
+	Sparklines          decodeX14Sparklines `xml:"sparklines"`
+}
+
+// decodeX14Sparklines directly maps the sparklines element.
+type decodeX14Sparklines struct {
+	Sparkline []*decodeX14Sparkline `xml:"sparkline"`
+}
+
+// decodeX14Sparkline directly maps the sparkline element.
+type decodeX14Sparkline struct {
+	F     string `xml:"f"`
+	Sqref string `xml:"sqref"`
 }
 
 // decodeX14ConditionalFormattingExt directly maps the ext element.
@@ -737,17 +782,19 @@ type decodeX14CfRule struct {
 
 // decodeX14DataBar directly maps the dataBar element.
 type decodeX14DataBar struct {
-	XMLName           xml.Name    `xml:"dataBar"`
-	MaxLength         int         `xml:"maxLength,attr"`
-	MinLength         int         `xml:"minLength,attr"`
-	Border            bool        `xml:"border,attr,omitempty"`
-	Gradient          bool        `xml:"gradient,attr"`
-	ShowValue         bool        `xml:"showValue,attr,omitempty"`
-	Direction         string      `xml:"direction,attr,omitempty"`
-	Cfvo              []*xlsxCfvo `xml:"cfvo"`
-	BorderColor       *xlsxColor  `xml:"borderColor"`
-	NegativeFillColor *xlsxColor  `xml:"negativeFillColor"`
-	AxisColor         *xlsxColor  `xml:"axisColor"`
+	XMLName             xml.Name    `xml:"dataBar"`
+	MaxLength           int         `xml:"maxLength,attr"`
+	MinLength           int         `xml:"minLength,attr"`
+	Border              bool        `xml:"border,attr,omitempty"`
+	Gradient            bool        `xml:"gradient,attr"`
+	ShowValue           bool        `xml:"showValue,attr,omitempty"`
+	Direction           string      `xml:"direction,attr,omitempty"`
+	AxisPosition        string      `xml:"axisPosition,attr"`
+	Cfvo                []*xlsxCfvo `xml:"cfvo"`
+	BorderColor         *xlsxColor  `xml:"borderColor"`
+	NegativeFillColor   *xlsxColor  `xml:"negativeFillColor"`
+	NegativeBorderColor *xlsxColor  `xml:"negativeBorderColor"`
+	AxisColor           *xlsxColor  `xml:"axisColor"`
 }
 
 // xlsxX14ConditionalFormattings directly maps the conditionalFormattings
@@ -773,16 +820,19 @@ type xlsxX14CfRule struct {
 
 // xlsx14DataBar directly maps the dataBar element.
 type xlsx14DataBar struct {
-	MaxLength         int         `xml:"maxLength,attr"`
-	MinLength         int         `xml:"minLength,attr"`
-	Border            bool        `xml:"border,attr"`
-	Gradient          bool        `xml:"gradient,attr"`
-	ShowValue         bool        `xml:"showValue,attr,omitempty"`
-	Direction         string      `xml:"direction,attr,omitempty"`
-	Cfvo              []*xlsxCfvo `xml:"x14:cfvo"`
-	BorderColor       *xlsxColor  `xml:"x14:borderColor"`
-	NegativeFillColor *xlsxColor  `xml:"x14:negativeFillColor"`
-	AxisColor         *xlsxColor  `xml:"x14:axisColor"`
+	MaxLength                            int         `xml:"maxLength,attr"`
+	MinLength                            int         `xml:"minLength,attr"`
+	Border                               bool        `xml:"border,attr"`
+	Gradient                             bool        `xml:"gradient,attr"`
+	ShowValue                            bool        `xml:"showValue,attr,omitempty"`
+	Direction                            string      `xml:"direction,attr,omitempty"`
+	NegativeBarBorderColorSameAsPositive *bool       `xml:"negativeBarBorderColorSameAsPositive,attr"`
+	AxisPosition                         string      `xml:"axisPosition,attr,omitempty"`
+	Cfvo                                 []*xlsxCfvo `xml:"x14:cfvo"`
+	BorderColor                          *xlsxColor  `xml:"x14:borderColor"`
+	NegativeFillColor                    *xlsxColor  `xml:"x14:negativeFillColor"`
+	NegativeBorderColor                  *xlsxColor  `xml:"x14:negativeBorderColor"`
+	AxisColor                            *xlsxColor  `xml:"x14:axisColor"`
 }
 
 // xlsxX14SparklineGroups directly maps the sparklineGroups element.
@@ -835,12 +885,82 @@ type xlsxX14Sparkline struct {
 	Sqref string `xml:"xm:sqref"`
 }
 
+// xlsxX14DataValidations directly maps the dataValidations element in the
+// worksheet extension list, which holding the data validations referencing
+// the cells of other worksheets.
+type xlsxX14DataValidations struct {
+	XMLName        xml.Name                 `xml:"x14:dataValidations"`
+	XMLNSXM        string                   `xml:"xmlns:xm,attr"`
+	Count          int                      `xml:"count,attr"`
+	DataValidation []*xlsxX14DataValidation `xml:"x14:dataValidation"`
+}
+
+// xlsxX14DataValidation directly maps the dataValidation element in the
+// worksheet extension list.
+type xlsxX14DataValidation struct {
+	AllowBlank       bool                `xml:"allowBlank,attr"`
+	Error            *string             `xml:"error,attr"`
+	ErrorStyle       *string             `xml:"errorStyle,attr"`
+	ErrorTitle       *string             `xml:"errorTitle,attr"`
+	ImeMode          string              `xml:"imeMode,attr,omitempty"`
+	Operator         string              `xml:"operator,attr,omitempty"`
+	Prompt           *string             `xml:"prompt,attr"`
+	PromptTitle      *string             `xml:"promptTitle,attr"`
+	ShowDropDown     bool                `xml:"showDropDown,attr,omitempty"`
+	ShowErrorMessage bool                `xml:"showErrorMessage,attr,omitempty"`
+	ShowInputMessage bool                `xml:"showInputMessage,attr,omitempty"`
+	Type             string              `xml:"type,attr,omitempty"`
+	Formula1         *xlsxX14DataFormula `xml:"x14:formula1"`
+	Formula2         *xlsxX14DataFormula `xml:"x14:formula2"`
+	Sqref            string              `xml:"xm:sqref"`
+}
+
+// xlsxX14DataFormula directly maps the formula1 and formula2 element of the
+// data validation in the worksheet extension list.
+type xlsxX14DataFormula struct {
+	F string `xml:"xm:f"`
+}
+
+// decodeX14DataValidations directly maps the dataValidations element in the
+// worksheet extension list.
+type decodeX14DataValidations struct {
+	XMLName        xml.Name                   `xml:"dataValidations"`
+	DataValidation []*decodeX14DataValidation `xml:"dataValidation"`
+}
+
+// decodeX14DataValidation directly maps the dataValidation element in the
+// worksheet extension list.
+type decodeX14DataValidation struct {
+	AllowBlank       bool                  `xml:"allowBlank,attr"`
+	Error            *string               `xml:"error,attr"`
+	ErrorStyle       *string               `xml:"errorStyle,attr"`
+	ErrorTitle       *string               `xml:"errorTitle,attr"`
+	ImeMode          string                `xml:"imeMode,attr,omitempty"`
+	Operator         string                `xml:"operator,attr,omitempty"`
+	Prompt           *string               `xml:"prompt,attr"`
+	PromptTitle      *string               `xml:"promptTitle,attr"`
+	ShowDropDown     bool                  `xml:"showDropDown,attr,omitempty"`
+	ShowErrorMessage bool                  `xml:"showErrorMessage,attr,omitempty"`
+	ShowInputMessage bool                  `xml:"showInputMessage,attr,omitempty"`
+	Type             string                `xml:"type,attr,omitempty"`
+	Formula1         *decodeX14DataFormula `xml:"formula1"`
+	Formula2         *decodeX14DataFormula `xml:"formula2"`
+	Sqref            string                `xml:"sqref"`
+}
+
+// decodeX14DataFormula directly maps the formula1 and formula2 element of the
+// data validation in the worksheet extension list.
+type decodeX14DataFormula struct {
+	F string `xml:"f"`
+}
+
 // DataValidation directly maps the settings of the data validation rule.
 type DataValidation struct {
 	AllowBlank       bool
 	Error            *string
 	ErrorStyle       *string
 	ErrorTitle       *string
+	ImeMode          string
 	Operator         string
 	Prompt           *string
 	PromptTitle      *string
@@ -853,6 +973,41 @@ type DataValidation struct {
 	Formula2         string
 }
 
+// DropListMappingOptions directly maps the settings of the drop list with the
+// key/value display mapping. The Codes and Labels specify the codes and the
+// display labels of the drop list items in the same order. The ListSheet
+// specifies the hidden worksheet for storing the lookup table, the default
+// value is "Lists". The Sqref specifies the cell range of the drop list, and
+// the optional CodeRange with the same size of the Sqref specifies the cells
+// for the formulas resolving the code of the selected label.
+type DropListMappingOptions struct {
+	Name      string
+	ListSheet string
+	Codes     []string
+	Labels    []string
+	Sqref     string
+	CodeRange string
+}
+
+// CascadingDropListItem directly maps the value and the dependent items of
+// the cascading drop list item.
+type CascadingDropListItem struct {
+	Value    string
+	Children []CascadingDropListItem
+}
+
+// CascadingDropListOptions directly maps the settings of the cascading drop
+// lists. The Name specifies the prefix of the defined names for the lists.
+// The ListSheet specifies the hidden worksheet for storing the items, the
+// default value is "Lists". The Sqref specifies the cell range of each level
+// of the drop lists.
+type CascadingDropListOptions struct {
+	Name      string
+	ListSheet string
+	Items     []CascadingDropListItem
+	Sqref     []string
+}
+
 // SparklineOptions directly maps the settings of the sparkline.
 type SparklineOptions struct {
 	Location      []string
@@ -902,32 +1057,85 @@ type Panes struct {
 	Selection   []Selection
 }
 
+// CurrencyFormat directly maps the settings of a currency in the conditional
+// currency number formats. The Code specifies the value of the switching cell
+// or defined name for the currency, the Symbol specifies the currency symbol
+// and the optional Locale specifies the hexadecimal locale ID of the currency
+// symbol, such as "409" for English (United States).
+type CurrencyFormat struct {
+	Code   string
+	Symbol string
+	Locale string
+}
+
+// ConditionalCurrencyFormatOptions directly maps the settings of the
+// conditional currency number formats. The Ref specifies the cell reference
+// or defined name holding the currency code.
+type ConditionalCurrencyFormatOptions struct {
+	Ref           string
+	Currencies    []CurrencyFormat
+	DecimalPlaces *int
+	NegRed        bool
+}
+
+// ConditionalFormatValue directly maps the threshold value of the icon in the
+// icon set conditional format. The Type specifies the value type, the
+// optional value is: num, percent, percentile and formula. The threshold is
+// compared by "greater than or equal to" by default, and will be compared by
+// "greater than" if the GreaterThan is true.
+type ConditionalFormatValue struct {
+	Type        string
+	Value       string
+	GreaterThan bool
+}
+
 // ConditionalFormatOptions directly maps the conditional format settings of the cells.
 type ConditionalFormatOptions struct {
-	Type           string
-	AboveAverage   bool
-	Percent        bool
-	Format         int
-	Criteria       string
-	Value          string
-	MinType        string
-	MidType        string
-	MaxType        string
-	MinValue       string
-	MidValue       string
-	MaxValue       string
-	MinColor       string
-	MidColor       string
-	MaxColor       string
-	BarColor       string
-	BarBorderColor string
-	BarDirection   string
-	BarOnly        bool
-	BarSolid       bool
-	IconStyle      string
-	ReverseIcons   bool
-	IconsOnly      bool
-	StopIfTrue     bool
+	Type                   string
+	AboveAverage           bool
+	EqualAverage           bool
+	StdDev                 int
+	Percent                bool
+	Format                 int
+	Criteria               string
+	Value                  string
+	MinType                string
+	MidType                string
+	MaxType                string
+	MinValue               string
+	MidValue               string
+	MaxValue               string
+	MinColor               string
+	MidColor               string
+	MaxColor               string
+	BarColor               string
+	BarBorderColor         string
+	BarDirection           string
+	BarOnly                bool
+	BarSolid               bool
+	BarNegativeColor       string
+	BarNegativeBorderColor string
+	BarAxisColor           string
+	BarAxisPosition        string
+	IconStyle              string
+	IconValues             []ConditionalFormatValue
+	ReverseIcons           bool
+	IconsOnly              bool
+	StopIfTrue             bool
+}
+
+// ConditionalFormatResult directly maps the evaluated result of the
+// conditional formatting rule for a cell. BarPercent is the percentage of the
+// data bar length, Color is the interpolated color of the color scale in the
+// format of "#RRGGBB", and IconIndex is the zero-based index of the icon in
+// the icon set from the lowest values.
+type ConditionalFormatResult struct {
+	RangeRef   string
+	Priority   int
+	Options    ConditionalFormatOptions
+	BarPercent float64
+	Color      string
+	IconIndex  int
 }
 
 // SheetProtectionOptions directly maps the settings of worksheet protection.
@@ -965,6 +1173,23 @@ type HeaderFooterOptions struct {
 	FirstFooter      string
 }
 
+// HeaderFooterRun directly maps the settings of a run of text or code in the
+// section of the header or footer. The token will be placed after the text,
+// and the font formatting will be applied from the run.
+type HeaderFooterRun struct {
+	Font  *Font
+	Text  string
+	Token HeaderFooterToken
+}
+
+// HeaderFooterSections directly maps the left, center and right sections of
+// the header or footer.
+type HeaderFooterSections struct {
+	Left   []HeaderFooterRun
+	Center []HeaderFooterRun
+	Right  []HeaderFooterRun
+}
+
 // PageLayoutMarginsOptions directly maps the settings of page layout margins.
 type PageLayoutMarginsOptions struct {
 	Bottom       *float64
@@ -998,6 +1223,26 @@ type PageLayoutOptions struct {
 	BlackAndWhite *bool
 }
 
+// SearchOptions directly maps the settings of searching the worksheet. The
+// Formulas, Comments and Hyperlinks specifies if search the formula text,
+// comments and hyperlink display strings besides the cell values.
+type SearchOptions struct {
+	RegExp          bool
+	CaseInsensitive bool
+	Formulas        bool
+	Comments        bool
+	Hyperlinks      bool
+}
+
+// SearchHit directly maps the search result of the worksheet, the Kind
+// specifies where the matched text was found, and the MatchedText is the
+// matched part of the text.
+type SearchHit struct {
+	Cell        string
+	Kind        SearchKind
+	MatchedText string
+}
+
 // ViewOptions directly maps the settings of sheet view.
 type ViewOptions struct {
 	// DefaultGridColor indicating that the consuming application should use
@@ -1013,11 +1258,17 @@ type ViewOptions struct {
 	ShowFormulas *bool
 	// ShowGridLines indicating whether this sheet should display grid lines.
 	ShowGridLines *bool
+	// ShowOutlineSymbols indicating whether the sheet should display outline
+	// symbols of the grouped rows and columns.
+	ShowOutlineSymbols *bool
 	// ShowRowColHeaders indicating whether the sheet should display row and
 	// column headings.
 	ShowRowColHeaders *bool
 	// ShowRuler indicating this sheet should display ruler.
 	ShowRuler *bool
+	// ShowWhiteSpace indicating whether the page layout view shall display
+	// margins between pages.
+	ShowWhiteSpace *bool
 	// ShowZeros indicating whether to "show a zero in cells that have zero
 	// value". When using a formula to reference another cell which is empty,
 	// the referenced value becomes 0 when the flag is true. (Default setting
@@ -1028,7 +1279,8 @@ type ViewOptions struct {
 	// Left-to-Right mode).
 	TopLeftCell *string
 	// View indicating how sheet is displayed, by default it uses empty string
-	// available options: normal, pageLayout, pageBreakPreview
+	// available options: normal, pageLayout, pageBreakPreview. Setting any
+	// other value will return an error.
 	View *string
 	// ZoomScale specifies a window zoom magnification for current view
 	// representing percent values. This attribute is restricted to values